@@ -3,7 +3,9 @@ module todo
 go 1.23.0
 
 require (
+	github.com/evanphx/json-patch/v5 v5.9.11
 	github.com/golang-jwt/jwt v3.2.2+incompatible
+	github.com/graph-gophers/graphql-go v1.5.0
 	github.com/jmoiron/sqlx v1.4.0
 	github.com/joho/godotenv v1.5.1
 	github.com/labstack/echo-contrib v0.17.2
@@ -15,6 +17,9 @@ require (
 	github.com/swaggo/swag v1.16.4
 	golang.org/x/crypto v0.35.0
 	golang.org/x/net v0.35.0
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20241015192408-796eee8c2d53
+	google.golang.org/grpc v1.69.2
+	google.golang.org/protobuf v1.36.5
 )
 
 require (
@@ -44,6 +49,5 @@ require (
 	golang.org/x/text v0.22.0 // indirect
 	golang.org/x/time v0.10.0 // indirect
 	golang.org/x/tools v0.30.0 // indirect
-	google.golang.org/protobuf v1.36.5 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -19,6 +19,7 @@ import (
 // @in 							header
 // @name 						Authorization
 // @bearerFormat 				JWT
+// @securityDefinitions.basic 	basicAuth
 func main() {
 	config.NewServer()
 }
@@ -10,8 +10,11 @@ CREATE TABLE user_account
 
 CREATE TABLE collection
 (
-    id   SERIAL      PRIMARY KEY,
-    name VARCHAR(50) NOT NULL,
+    id          SERIAL      PRIMARY KEY,
+    name        VARCHAR(50) NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    version     INTEGER     NOT NULL DEFAULT 1,
+    archived_at TIMESTAMPTZ,
 
     user_id INT NOT NULL,
 
@@ -20,9 +23,13 @@ CREATE TABLE collection
 
 CREATE TABLE task
 (
-    id          SERIAL      PRIMARY KEY,
-    description VARCHAR(50) NOT NULL,
-    finished    BOOLEAN     NOT NULL,
+    id          SERIAL        PRIMARY KEY,
+    description VARCHAR(50)   NOT NULL,
+    finished    BOOLEAN       NOT NULL,
+    created_at  TIMESTAMPTZ   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    version     INTEGER       NOT NULL DEFAULT 1,
+    due_date    TIMESTAMPTZ,
+    tags        VARCHAR(30)[] NOT NULL DEFAULT '{}',
 
     user_id       INT NOT NULL,
     collection_id INT,
@@ -30,3 +37,202 @@ CREATE TABLE task
     CONSTRAINT task_user_fk       FOREIGN KEY (user_id)       REFERENCES user_account (id),
     CONSTRAINT task_collection_fk FOREIGN KEY (collection_id) REFERENCES collection   (id)
 );
+
+CREATE INDEX task_user_id_idx         ON task (user_id, id);
+CREATE INDEX task_user_created_at_idx ON task (user_id, created_at, id);
+CREATE INDEX task_user_due_date_idx   ON task (user_id, due_date);
+CREATE INDEX task_tags_idx            ON task USING GIN (tags);
+
+CREATE TABLE idempotency_key
+(
+    user_id         INT          NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL,
+    fingerprint     CHAR(64)     NOT NULL,
+    status          INT,
+    content_type    VARCHAR(100),
+    headers         JSONB,
+    body            BYTEA,
+    completed       BOOLEAN      NOT NULL DEFAULT FALSE,
+    created_at      TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT idempotency_key_pk      PRIMARY KEY (user_id, idempotency_key),
+    CONSTRAINT idempotency_key_user_fk FOREIGN KEY (user_id) REFERENCES user_account (id)
+);
+
+CREATE TABLE undo_operation
+(
+    user_id    INT         NOT NULL,
+    token      CHAR(32)    NOT NULL,
+    kind       VARCHAR(30) NOT NULL,
+    changes    JSONB       NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+
+    CONSTRAINT undo_operation_pk      PRIMARY KEY (user_id, token),
+    CONSTRAINT undo_operation_user_fk FOREIGN KEY (user_id) REFERENCES user_account (id)
+);
+
+CREATE TABLE calendar_feed
+(
+    user_id    INT         PRIMARY KEY,
+    token      CHAR(64)    NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT calendar_feed_user_fk FOREIGN KEY (user_id) REFERENCES user_account (id)
+);
+
+CREATE TABLE caldav_object
+(
+    task_id INT          PRIMARY KEY,
+    name    VARCHAR(255) NOT NULL,
+    uid     VARCHAR(255) NOT NULL,
+
+    CONSTRAINT caldav_object_task_fk FOREIGN KEY (task_id) REFERENCES task (id) ON DELETE CASCADE
+);
+
+CREATE TABLE webhook
+(
+    id          SERIAL        PRIMARY KEY,
+    url         VARCHAR(2048) NOT NULL,
+    secret      CHAR(64)      NOT NULL,
+    event_types VARCHAR(30)[] NOT NULL,
+    active      BOOLEAN       NOT NULL DEFAULT TRUE,
+    failures    INTEGER       NOT NULL DEFAULT 0,
+    created_at  TIMESTAMPTZ   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    user_id INT NOT NULL,
+
+    CONSTRAINT webhook_user_fk FOREIGN KEY (user_id) REFERENCES user_account (id)
+);
+
+CREATE INDEX webhook_user_id_idx ON webhook (user_id, id);
+
+CREATE TABLE webhook_delivery
+(
+    id              BIGSERIAL   PRIMARY KEY,
+    event_id        BIGINT      NOT NULL,
+    event_type      VARCHAR(30) NOT NULL,
+    attempt         INTEGER     NOT NULL,
+    request_body    TEXT        NOT NULL,
+    response_status INTEGER,
+    error           TEXT        NOT NULL DEFAULT '',
+    duration_ms     INTEGER     NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    webhook_id INT NOT NULL,
+
+    CONSTRAINT webhook_delivery_webhook_fk FOREIGN KEY (webhook_id) REFERENCES webhook (id) ON DELETE CASCADE
+);
+
+CREATE INDEX webhook_delivery_webhook_id_idx ON webhook_delivery (webhook_id, id);
+
+CREATE INDEX webhook_delivery_event_id_idx ON webhook_delivery (event_id);
+
+CREATE TABLE outbox_event
+(
+    id             BIGSERIAL   PRIMARY KEY,
+    aggregate_type VARCHAR(30) NOT NULL,
+    aggregate_id   INT         NOT NULL,
+    event_type     VARCHAR(30) NOT NULL,
+    payload        JSONB       NOT NULL,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    attempts       INTEGER     NOT NULL DEFAULT 0,
+    last_error     TEXT        NOT NULL DEFAULT '',
+    available_at   TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    processed_at   TIMESTAMPTZ,
+    failed_at      TIMESTAMPTZ,
+
+    user_id INT NOT NULL,
+
+    CONSTRAINT outbox_event_user_fk FOREIGN KEY (user_id) REFERENCES user_account (id)
+);
+
+CREATE INDEX outbox_event_pending_idx ON outbox_event (id) WHERE processed_at IS NULL AND failed_at IS NULL;
+CREATE INDEX outbox_event_aggregate_idx ON outbox_event (aggregate_type, aggregate_id, id)
+    WHERE processed_at IS NULL AND failed_at IS NULL;
+CREATE INDEX outbox_event_user_id_idx ON outbox_event (user_id, id);
+
+CREATE TABLE automation
+(
+    id         SERIAL      PRIMARY KEY,
+    name       VARCHAR(50) NOT NULL,
+    trigger    VARCHAR(30) NOT NULL,
+    condition  TEXT        NOT NULL DEFAULT '',
+    actions    JSONB       NOT NULL,
+    active     BOOLEAN     NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    user_id INT NOT NULL,
+
+    CONSTRAINT automation_user_fk FOREIGN KEY (user_id) REFERENCES user_account (id)
+);
+
+CREATE INDEX automation_user_trigger_idx ON automation (user_id, trigger);
+
+CREATE TABLE reminder
+(
+    id                 SERIAL      PRIMARY KEY,
+    remind_at          TIMESTAMPTZ,
+    minutes_before_due INTEGER,
+    status             VARCHAR(10) NOT NULL DEFAULT 'pending',
+    attempts           INTEGER     NOT NULL DEFAULT 0,
+    last_error         TEXT        NOT NULL DEFAULT '',
+    processed_at       TIMESTAMPTZ,
+    locked_until       TIMESTAMPTZ,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    user_id INT NOT NULL,
+    task_id INT NOT NULL,
+
+    CONSTRAINT reminder_schedule_check CHECK ((remind_at IS NULL) <> (minutes_before_due IS NULL)),
+    CONSTRAINT reminder_user_fk        FOREIGN KEY (user_id) REFERENCES user_account (id),
+    CONSTRAINT reminder_task_fk        FOREIGN KEY (task_id) REFERENCES task (id) ON DELETE CASCADE
+);
+
+CREATE INDEX reminder_task_id_idx ON reminder (task_id, id);
+CREATE INDEX reminder_pending_idx ON reminder (id) WHERE status = 'pending';
+
+CREATE TABLE notification_preference
+(
+    user_id             INT     PRIMARY KEY,
+    email               BOOLEAN NOT NULL DEFAULT TRUE,
+    scheduled_reminders BOOLEAN NOT NULL DEFAULT TRUE,
+    due_date_reminders  BOOLEAN NOT NULL DEFAULT TRUE,
+
+    CONSTRAINT notification_preference_user_fk FOREIGN KEY (user_id) REFERENCES user_account (id)
+);
+
+CREATE TABLE job
+(
+    id           BIGSERIAL   PRIMARY KEY,
+    kind         VARCHAR(50) NOT NULL,
+    payload      JSONB       NOT NULL DEFAULT '{}',
+    status       VARCHAR(10) NOT NULL DEFAULT 'queued',
+    attempts     INTEGER     NOT NULL DEFAULT 0,
+    max_attempts INTEGER     NOT NULL DEFAULT 5,
+    last_error   TEXT        NOT NULL DEFAULT '',
+    run_at       TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    schedule     VARCHAR(50),
+    user_id      INT,
+    locked_by    VARCHAR(100),
+    locked_until TIMESTAMPTZ,
+    started_at   TIMESTAMPTZ,
+    finished_at  TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT job_user_fk FOREIGN KEY (user_id) REFERENCES user_account (id)
+);
+
+CREATE INDEX job_queued_idx ON job (run_at, id) WHERE status = 'queued';
+CREATE INDEX job_running_idx ON job (locked_until) WHERE status = 'running';
+CREATE INDEX job_status_idx ON job (status, id);
+CREATE UNIQUE INDEX job_schedule_active_idx ON job (schedule) WHERE status IN ('queued', 'running');
+CREATE INDEX job_user_idx ON job (user_id) WHERE user_id IS NOT NULL;
+
+CREATE TABLE job_schedule
+(
+    name        VARCHAR(50) PRIMARY KEY,
+    kind        VARCHAR(50) NOT NULL,
+    spec        VARCHAR(50) NOT NULL,
+    payload     JSONB       NOT NULL DEFAULT '{}',
+    next_run_at TIMESTAMPTZ NOT NULL
+);
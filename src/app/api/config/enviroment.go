@@ -1,16 +1,111 @@
 package config
 
 import (
+	"context"
 	"fmt"
+	"github.com/labstack/gommon/log"
 	"os"
 	"todo/src/app/api/endpoints/routes"
+	"todo/src/app/rpc"
+	"todo/src/core/automation"
+	"todo/src/core/changefeed"
+	"todo/src/core/events"
+	eventbus "todo/src/core/interfaces/events"
+	interfaces "todo/src/core/interfaces/services"
+	"todo/src/core/jobs"
+	"todo/src/core/notifications"
+	"todo/src/core/outbox"
+	"todo/src/core/services"
+	"todo/src/infra/mailer"
+	"todo/src/infra/postgres"
+	"todo/src/infra/webhook"
 )
 
 func NewServer() {
 	loadEnvFile()
 
-	app := routes.LoadRoutes()
+	bus := events.NewBus()
+	app := routes.LoadRoutes(streamChanges(), bus)
+	relayOutbox()
+	runAutomations(bus)
+	runJobs()
+
+	if grpcPort := os.Getenv("GRPC_PORT"); grpcPort != "" {
+		grpcAddress := fmt.Sprintf("%s:%s", os.Getenv("HOST"), grpcPort)
+		go func() {
+			log.Fatal(rpc.Serve(rpc.NewServer(bus), grpcAddress))
+		}()
+	}
 
 	address := fmt.Sprintf("%s:%s", os.Getenv("HOST"), os.Getenv("PORT"))
 	app.Logger.Fatal(app.Start(address))
 }
+
+// streamChanges feeds the change feed of the instance from the outbox, so that it streams the changes made through
+// every instance
+func streamChanges() *changefeed.Hub {
+	hub := changefeed.NewHub(postgres.NewChangeFeedPostgresRepository(postgres.NewPostgresConnectionManager()))
+	go hub.Run(context.Background())
+	return hub
+}
+
+// relayOutbox queues the posts of the changes made to the tasks and collections, once committed, to the webhooks of
+// their accounts, which the jobs make
+func relayOutbox() {
+	connectionManager := postgres.NewPostgresConnectionManager()
+	dispatcher := outbox.NewDispatcher(postgres.NewOutboxPostgresRepository(connectionManager))
+	dispatcher.Register(newWebhookService())
+
+	go dispatcher.Run(context.Background())
+}
+
+// runAutomations runs the automations of the accounts on the domain events emitted by the services to the bus
+func runAutomations(bus *events.Bus) {
+	connectionManager := postgres.NewPostgresConnectionManager()
+	undoRepository := postgres.NewUndoPostgresRepository(connectionManager)
+	taskRepository := postgres.NewTaskPostgresRepository(connectionManager)
+	collectionRepository := postgres.NewCollectionPostgresRepository(connectionManager)
+	engine := automation.NewEngine(postgres.NewAutomationPostgresRepository(connectionManager), bus,
+		func(emitter eventbus.IEmitter) interfaces.ITask {
+			return services.NewTaskService(taskRepository, undoRepository, emitter)
+		},
+		func(emitter eventbus.IEmitter) interfaces.ICollection {
+			return services.NewCollectionService(collectionRepository, undoRepository, emitter)
+		})
+	engine.Subscribe()
+}
+
+// newWebhookService posts the events to the webhooks, as a handler of the outbox queueing the posts and of their jobs
+func newWebhookService() *services.Webhook {
+	return services.NewWebhookService(postgres.NewWebhookPostgresRepository(postgres.NewPostgresConnectionManager()),
+		webhook.NewWebhookHttpSender())
+}
+
+// runJobs runs the background jobs along with the other instances, emailing the reminders of the tasks once they are
+// due, posting the events to the webhooks, importing the transfers queued by the accounts and purging the processed
+// outbox events and the finished jobs
+func runJobs() {
+	connectionManager := postgres.NewPostgresConnectionManager()
+	jobRepository := postgres.NewJobPostgresRepository(connectionManager)
+	runner := jobs.NewRunner(jobRepository, jobs.DefaultConcurrency)
+	dispatcher := outbox.NewDispatcher(postgres.NewOutboxPostgresRepository(connectionManager))
+	transferService := services.NewTransferService(postgres.NewTransferPostgresRepository(connectionManager),
+		jobRepository)
+	runner.Register(outbox.PurgeJobKind, jobs.HandlerFunc(dispatcher.Purge))
+	runner.Register(jobs.PurgeJobKind, jobs.HandlerFunc(runner.Purge))
+	runner.Register(services.ImportJobKind, jobs.HandlerFunc(transferService.RunImport))
+	runner.Register(services.WebhookDeliveryJobKind, jobs.HandlerFunc(newWebhookService().Deliver))
+	schedules := map[string]string{outbox.PurgeJobKind: "@hourly", jobs.PurgeJobKind: "@daily"}
+	if os.Getenv("SMTP_HOST") != "" {
+		runner.Register(notifications.ReminderJobKind, notifications.NewNotifier(
+			postgres.NewNotificationPostgresRepository(connectionManager), mailer.NewSmtpMailer()))
+		schedules[notifications.ReminderJobKind] = "* * * * *"
+	}
+	for kind, spec := range schedules {
+		if err := runner.Schedule(kind, spec, kind); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	go runner.Run(context.Background())
+}
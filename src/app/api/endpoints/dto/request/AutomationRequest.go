@@ -0,0 +1,17 @@
+package request
+
+type Automation struct {
+	Name      string             `json:"name"`
+	Trigger   string             `json:"trigger"`
+	Condition string             `json:"condition"`
+	Actions   []AutomationAction `json:"actions"`
+	Active    *bool              `json:"active"`
+}
+
+type AutomationAction struct {
+	Type         string `json:"type"`
+	CollectionId int    `json:"collection_id"`
+	Tag          string `json:"tag"`
+	Finished     bool   `json:"finished"`
+	Text         string `json:"text"`
+}
@@ -0,0 +1,7 @@
+package request
+
+type GraphQL struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
@@ -0,0 +1,14 @@
+package request
+
+import "time"
+
+type Reminder struct {
+	RemindAt         *time.Time `json:"remind_at"`
+	MinutesBeforeDue *int       `json:"minutes_before_due"`
+}
+
+type NotificationPreferences struct {
+	Email              *bool `json:"email"`
+	ScheduledReminders *bool `json:"scheduled_reminders"`
+	DueDateReminders   *bool `json:"due_date_reminders"`
+}
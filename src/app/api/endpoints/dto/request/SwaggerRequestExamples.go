@@ -16,7 +16,81 @@ type SwaggerCollectionRequest struct {
 }
 
 type SwaggerTaskRequest struct {
-	Description  string `json:"description"   example:"Task example"`
+	Description  string   `json:"description"   example:"Task example"`
+	Finished     bool     `json:"finished"      example:"false"`
+	DueDate      string   `json:"due_date"      example:"2024-01-31T18:00:00Z"`
+	Tags         []string `json:"tags"          example:"work,urgent"`
+	CollectionId int      `json:"collection_id" example:"1"`
+}
+
+type SwaggerTaskBatchRequest struct {
+	Mode       string                        `json:"mode"       example:"atomic"`
+	Operations []SwaggerTaskOperationRequest `json:"operations"`
+}
+
+type SwaggerTaskOperationRequest struct {
+	Operation string              `json:"op"   example:"update"`
+	Id        int                 `json:"id"      example:"1"`
+	Version   int                 `json:"version" example:"3"`
+	Task      *SwaggerTaskRequest `json:"task"`
+}
+
+type SwaggerTransferRequest struct {
+	Collections []SwaggerTransferCollectionRequest `json:"collections"`
+	Tasks       []SwaggerTransferTaskRequest       `json:"tasks"`
+}
+
+type SwaggerTransferCollectionRequest struct {
+	Id        int    `json:"id"         example:"1"`
+	Name      string `json:"name"       example:"Collection example"`
+	CreatedAt string `json:"created_at" example:"2024-01-01T12:00:00Z"`
+}
+
+type SwaggerTransferTaskRequest struct {
+	Id           int      `json:"id"            example:"1"`
+	Description  string   `json:"description"   example:"Task example"`
+	Finished     bool     `json:"finished"      example:"false"`
+	CollectionId int      `json:"collection_id" example:"1"`
+	CreatedAt    string   `json:"created_at"    example:"2024-01-01T12:00:00Z"`
+	DueDate      string   `json:"due_date"      example:"2024-01-31T18:00:00Z"`
+	Tags         []string `json:"tags"          example:"work,urgent"`
+}
+
+type SwaggerGraphQLRequest struct {
+	Query         string                 `json:"query"         example:"query { me { collections { name tasks { description finished } } } }"`
+	OperationName string                 `json:"operationName" example:""`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+type SwaggerWebhookRequest struct {
+	Url        string   `json:"url"         example:"https://example.com/hooks/todo"`
+	EventTypes []string `json:"event_types" example:"task.created,task.finished"`
+	Active     bool     `json:"active"      example:"true"`
+}
+
+type SwaggerAutomationRequest struct {
+	Name      string                           `json:"name"      example:"Triage the bugs"`
+	Trigger   string                           `json:"trigger"   example:"task.created"`
+	Condition string                           `json:"condition" example:"tag:bug"`
+	Actions   []SwaggerAutomationActionRequest `json:"actions"`
+	Active    bool                             `json:"active"    example:"true"`
+}
+
+type SwaggerAutomationActionRequest struct {
+	Type         string `json:"type"          example:"move_to_collection"`
+	CollectionId int    `json:"collection_id" example:"3"`
+	Tag          string `json:"tag"           example:""`
 	Finished     bool   `json:"finished"      example:"false"`
-	CollectionId int    `json:"collection_id" example:"1"`
+	Text         string `json:"text"          example:""`
+}
+
+type SwaggerReminderRequest struct {
+	RemindAt         string `json:"remind_at"          example:"2030-01-01T09:00:00Z"`
+	MinutesBeforeDue int    `json:"minutes_before_due" example:"60"`
+}
+
+type SwaggerNotificationPreferencesRequest struct {
+	Email              bool `json:"email"               example:"true"`
+	ScheduledReminders bool `json:"scheduled_reminders" example:"true"`
+	DueDateReminders   bool `json:"due_date_reminders"  example:"false"`
 }
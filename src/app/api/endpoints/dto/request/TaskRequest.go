@@ -1,7 +1,23 @@
 package request
 
+import "time"
+
 type Task struct {
-	Description  string `json:"description"`
-	Finished     bool   `json:"finished"`
-	CollectionId int    `json:"collection_id"`
+	Description  string     `json:"description"`
+	Finished     bool       `json:"finished"`
+	DueDate      *time.Time `json:"due_date"`
+	Tags         []string   `json:"tags"`
+	CollectionId int        `json:"collection_id"`
+}
+
+type TaskBatch struct {
+	Mode       string          `json:"mode"`
+	Operations []TaskOperation `json:"operations"`
+}
+
+type TaskOperation struct {
+	Operation string `json:"op"`
+	Id        int    `json:"id"`
+	Version   int    `json:"version"`
+	Task      *Task  `json:"task"`
 }
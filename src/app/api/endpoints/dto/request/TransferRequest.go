@@ -0,0 +1,24 @@
+package request
+
+import "time"
+
+type Transfer struct {
+	Collections []TransferCollection `json:"collections"`
+	Tasks       []TransferTask       `json:"tasks"`
+}
+
+type TransferCollection struct {
+	Id        int        `json:"id"`
+	Name      string     `json:"name"`
+	CreatedAt *time.Time `json:"created_at"`
+}
+
+type TransferTask struct {
+	Id           int        `json:"id"`
+	Description  string     `json:"description"`
+	Finished     bool       `json:"finished"`
+	CollectionId int        `json:"collection_id"`
+	CreatedAt    *time.Time `json:"created_at"`
+	DueDate      *time.Time `json:"due_date"`
+	Tags         []string   `json:"tags"`
+}
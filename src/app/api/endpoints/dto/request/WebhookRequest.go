@@ -0,0 +1,7 @@
+package request
+
+type Webhook struct {
+	Url        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	Active     *bool    `json:"active"`
+}
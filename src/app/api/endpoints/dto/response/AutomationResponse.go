@@ -0,0 +1,52 @@
+package response
+
+import (
+	"time"
+	"todo/src/core/domain"
+)
+
+type Automation struct {
+	Id        int                `json:"id"`
+	Name      string             `json:"name"`
+	Trigger   string             `json:"trigger"`
+	Condition string             `json:"condition"`
+	Actions   []AutomationAction `json:"actions"`
+	Active    bool               `json:"active"`
+	CreatedAt *time.Time         `json:"created_at,omitempty"`
+}
+
+// AutomationAction has only the parameters read by its type
+type AutomationAction struct {
+	Type         string `json:"type"`
+	CollectionId int    `json:"collection_id,omitempty"`
+	Tag          string `json:"tag,omitempty"`
+	Finished     *bool  `json:"finished,omitempty"`
+	Text         string `json:"text,omitempty"`
+}
+
+func NewAutomation(automation domain.Automation) *Automation {
+	actions := []AutomationAction{}
+	for _, action := range automation.Actions() {
+		actionResponse := AutomationAction{
+			Type:         action.Kind(),
+			CollectionId: action.CollectionId(),
+			Tag:          action.Tag(),
+			Text:         action.Text(),
+		}
+		if action.Kind() == domain.ActionSetFinished {
+			finished := action.Finished()
+			actionResponse.Finished = &finished
+		}
+		actions = append(actions, actionResponse)
+	}
+
+	return &Automation{
+		Id:        automation.Id(),
+		Name:      automation.Name(),
+		Trigger:   automation.Trigger(),
+		Condition: automation.Condition(),
+		Actions:   actions,
+		Active:    automation.Active(),
+		CreatedAt: optionalTime(automation.CreatedAt()),
+	}
+}
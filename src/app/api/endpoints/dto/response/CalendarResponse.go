@@ -0,0 +1,13 @@
+package response
+
+type CalendarFeed struct {
+	Token string `json:"token"`
+	Url   string `json:"url"`
+}
+
+func NewCalendarFeed(token, url string) *CalendarFeed {
+	return &CalendarFeed{
+		Token: token,
+		Url:   url,
+	}
+}
@@ -0,0 +1,50 @@
+package response
+
+import (
+	"time"
+	"todo/src/core/domain"
+)
+
+type ChangeEvent struct {
+	Id         int64     `json:"id"`
+	Type       string    `json:"type"`
+	Entity     string    `json:"entity"`
+	EntityId   int       `json:"entity_id"`
+	Version    int       `json:"version,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+func NewChangeEvent(event domain.ChangeEvent) *ChangeEvent {
+	return &ChangeEvent{
+		Id:         event.Id(),
+		Type:       event.Type(),
+		Entity:     event.Entity(),
+		EntityId:   event.EntityId(),
+		Version:    event.Version(),
+		OccurredAt: event.OccurredAt(),
+	}
+}
+
+const ChangeFeedResyncType = "resync"
+
+// ChangeFeedResync tells the client to read the tasks and collections of the user again, as some of the events it
+// has missed are no longer kept
+type ChangeFeedResync struct {
+	Type string `json:"type"`
+}
+
+func NewChangeFeedResync() *ChangeFeedResync {
+	return &ChangeFeedResync{Type: ChangeFeedResyncType}
+}
+
+type ChangeFeedToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func NewChangeFeedToken(token string, expiresAt time.Time) *ChangeFeedToken {
+	return &ChangeFeedToken{
+		Token:     token,
+		ExpiresAt: expiresAt,
+	}
+}
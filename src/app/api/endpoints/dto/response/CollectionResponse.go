@@ -1,15 +1,44 @@
 package response
 
-import "todo/src/core/domain"
+import (
+	"time"
+	"todo/src/core/domain"
+)
 
 type Collection struct {
-	Id   int    `json:"id"`
-	Name string `json:"name"`
+	Id         int        `json:"id"`
+	Name       string     `json:"name,omitempty"`
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+	Tasks      *[]Task    `json:"tasks,omitempty"`
+	Tags       *[]string  `json:"tags,omitempty"`
+	Version    int        `json:"version,omitempty"`
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
 }
 
 func NewCollection(collection domain.Collection) *Collection {
 	return &Collection{
-		Id:   collection.Id(),
-		Name: collection.Name(),
+		Id:         collection.Id(),
+		Name:       collection.Name(),
+		CreatedAt:  optionalTime(collection.CreatedAt()),
+		Version:    collection.Version(),
+		ArchivedAt: collection.ArchivedAt(),
 	}
 }
+
+// NewExpandedCollection embeds the related resources requested, even when they are empty
+func NewExpandedCollection(collection domain.Collection, expansion domain.Expansion) *Collection {
+	collectionResponse := NewCollection(collection)
+	if expansion.Includes(domain.ExpandTasks) {
+		tasks := []Task{}
+		for _, task := range collection.Tasks() {
+			tasks = append(tasks, *NewTask(task))
+		}
+		collectionResponse.Tasks = &tasks
+	}
+	if expansion.Includes(domain.ExpandTags) {
+		tags := append([]string{}, collection.Tags()...)
+		collectionResponse.Tags = &tags
+	}
+
+	return collectionResponse
+}
@@ -0,0 +1,91 @@
+package response
+
+import (
+	"encoding/json"
+	"time"
+	"todo/src/core/domain"
+)
+
+type Job struct {
+	Id          int64           `json:"id"`
+	Kind        string          `json:"kind"`
+	Payload     json.RawMessage `json:"payload"`
+	Status      string          `json:"status"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	LastError   string          `json:"last_error,omitempty"`
+	RunAt       time.Time       `json:"run_at"`
+	Schedule    string          `json:"schedule,omitempty"`
+	UserId      int             `json:"user_id,omitempty"`
+	LockedBy    string          `json:"locked_by,omitempty"`
+	LockedUntil *time.Time      `json:"locked_until,omitempty"`
+	StartedAt   *time.Time      `json:"started_at,omitempty"`
+	FinishedAt  *time.Time      `json:"finished_at,omitempty"`
+	CreatedAt   *time.Time      `json:"created_at,omitempty"`
+}
+
+func NewJob(job domain.Job) *Job {
+	return &Job{
+		Id:          job.Id(),
+		Kind:        job.Kind(),
+		Payload:     job.Payload(),
+		Status:      job.Status(),
+		Attempts:    job.Attempts(),
+		MaxAttempts: job.MaxAttempts(),
+		LastError:   job.LastError(),
+		RunAt:       job.RunAt(),
+		Schedule:    job.Schedule(),
+		UserId:      job.UserId(),
+		LockedBy:    job.LockedBy(),
+		LockedUntil: job.LockedUntil(),
+		StartedAt:   job.StartedAt(),
+		FinishedAt:  job.FinishedAt(),
+		CreatedAt:   optionalTime(job.CreatedAt()),
+	}
+}
+
+// ImportJob tells how far the import queued by an account has gone, without the details of the instances running it
+type ImportJob struct {
+	Id         int64      `json:"id"`
+	Status     string     `json:"status"`
+	LastError  string     `json:"last_error,omitempty"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+}
+
+func NewImportJob(job domain.Job) *ImportJob {
+	return &ImportJob{
+		Id:         job.Id(),
+		Status:     job.Status(),
+		LastError:  job.LastError(),
+		StartedAt:  job.StartedAt(),
+		FinishedAt: job.FinishedAt(),
+		CreatedAt:  optionalTime(job.CreatedAt()),
+	}
+}
+
+func NewQueuedImportJob(jobId int64) *ImportJob {
+	return &ImportJob{
+		Id:     jobId,
+		Status: domain.JobQueued,
+	}
+}
+
+type JobSchedule struct {
+	Name      string          `json:"name"`
+	Kind      string          `json:"kind"`
+	Spec      string          `json:"spec"`
+	Payload   json.RawMessage `json:"payload"`
+	NextRunAt time.Time       `json:"next_run_at"`
+}
+
+func NewJobSchedule(schedule domain.JobSchedule) *JobSchedule {
+	return &JobSchedule{
+		Name:      schedule.Name(),
+		Kind:      schedule.Kind(),
+		Spec:      schedule.Spec(),
+		Payload:   schedule.Payload(),
+		NextRunAt: schedule.NextRunAt(),
+	}
+}
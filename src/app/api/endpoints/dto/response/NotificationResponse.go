@@ -0,0 +1,46 @@
+package response
+
+import (
+	"time"
+	"todo/src/core/domain"
+)
+
+type Reminder struct {
+	Id               int        `json:"id"`
+	TaskId           int        `json:"task_id"`
+	RemindAt         *time.Time `json:"remind_at,omitempty"`
+	MinutesBeforeDue *int       `json:"minutes_before_due,omitempty"`
+	Status           string     `json:"status"`
+	Attempts         int        `json:"attempts"`
+	LastError        string     `json:"last_error,omitempty"`
+	ProcessedAt      *time.Time `json:"processed_at,omitempty"`
+	CreatedAt        *time.Time `json:"created_at,omitempty"`
+}
+
+func NewReminder(reminder domain.Reminder) *Reminder {
+	return &Reminder{
+		Id:               reminder.Id(),
+		TaskId:           reminder.TaskId(),
+		RemindAt:         reminder.RemindAt(),
+		MinutesBeforeDue: reminder.MinutesBeforeDue(),
+		Status:           reminder.Status(),
+		Attempts:         reminder.Attempts(),
+		LastError:        reminder.LastError(),
+		ProcessedAt:      optionalTime(reminder.ProcessedAt()),
+		CreatedAt:        optionalTime(reminder.CreatedAt()),
+	}
+}
+
+type NotificationPreferences struct {
+	Email              bool `json:"email"`
+	ScheduledReminders bool `json:"scheduled_reminders"`
+	DueDateReminders   bool `json:"due_date_reminders"`
+}
+
+func NewNotificationPreferences(preferences domain.NotificationPreferences) *NotificationPreferences {
+	return &NotificationPreferences{
+		Email:              preferences.Email(),
+		ScheduledReminders: preferences.ScheduledReminders(),
+		DueDateReminders:   preferences.DueDateReminders(),
+	}
+}
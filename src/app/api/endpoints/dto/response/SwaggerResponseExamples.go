@@ -12,8 +12,21 @@ type SwaggerIdResponse struct {
 }
 
 type SwaggerCollectionResponse struct {
-	Id   int    `json:"id"   example:"1"`
-	Name string `json:"name" example:"Collection example"`
+	Id         int    `json:"id"          example:"1"`
+	Name       string `json:"name"        example:"Collection example"`
+	CreatedAt  string `json:"created_at"  example:"2024-01-01T12:00:00Z"`
+	Version    int    `json:"version"     example:"1"`
+	ArchivedAt string `json:"archived_at" example:"2024-01-02T12:00:00Z"`
+}
+
+type SwaggerExpandedCollectionResponse struct {
+	Id         int                   `json:"id"          example:"1"`
+	Name       string                `json:"name"        example:"Collection example"`
+	CreatedAt  string                `json:"created_at"  example:"2024-01-01T12:00:00Z"`
+	Tasks      []SwaggerTaskResponse `json:"tasks"`
+	Tags       []string              `json:"tags"        example:"work,urgent"`
+	Version    int                   `json:"version"     example:"1"`
+	ArchivedAt string                `json:"archived_at" example:"2024-01-02T12:00:00Z"`
 }
 
 type SwaggerTaskResponse struct {
@@ -21,6 +34,170 @@ type SwaggerTaskResponse struct {
 	Description string                     `json:"description" example:"Description example"`
 	Finished    bool                       `json:"finished"    example:"false"`
 	Collection  *SwaggerCollectionResponse `json:"collection"`
+	CreatedAt   string                     `json:"created_at"  example:"2024-01-01T12:00:00Z"`
+	DueDate     string                     `json:"due_date"    example:"2024-01-31T18:00:00Z"`
+	Tags        []string                   `json:"tags"        example:"work,urgent"`
+	Version     int                        `json:"version"     example:"1"`
+}
+
+type SwaggerTaskPageResponse struct {
+	Data []SwaggerTaskResponse `json:"data"`
+	Next string                `json:"next" example:"eyJ1IjoxLCJzIjoiaWQiLCJvIjoiYXNjIiwiaSI6MjB9.q7Kc..."`
+	Prev string                `json:"prev" example:"eyJ1IjoxLCJzIjoiaWQiLCJvIjoiYXNjIiwiaSI6MX0.Xw3h..."`
+}
+
+type SwaggerTaskBatchResponse struct {
+	Mode      string                               `json:"mode"      example:"atomic"`
+	Committed bool                                 `json:"committed" example:"true"`
+	Results   []SwaggerTaskOperationResultResponse `json:"results"`
+	UndoToken string                               `json:"undo_token" example:"4f1c2a9e8b7d6c5e4f3a2b1c0d9e8f7a"`
+}
+
+type SwaggerTransferResponse struct {
+	Collections []SwaggerTransferCollectionResponse `json:"collections"`
+	Tasks       []SwaggerTransferTaskResponse       `json:"tasks"`
+}
+
+type SwaggerTransferCollectionResponse struct {
+	Id        int    `json:"id"         example:"1"`
+	Name      string `json:"name"       example:"Collection example"`
+	CreatedAt string `json:"created_at" example:"2024-01-01T12:00:00Z"`
+}
+
+type SwaggerTransferTaskResponse struct {
+	Id           int      `json:"id"            example:"1"`
+	Description  string   `json:"description"   example:"Task example"`
+	Finished     bool     `json:"finished"      example:"false"`
+	CollectionId int      `json:"collection_id" example:"1"`
+	CreatedAt    string   `json:"created_at"    example:"2024-01-01T12:00:00Z"`
+	DueDate      string   `json:"due_date"      example:"2024-01-31T18:00:00Z"`
+	Tags         []string `json:"tags"          example:"work,urgent"`
+}
+
+type SwaggerTransferResultResponse struct {
+	CollectionIds map[string]int `json:"collection_ids"`
+	TaskIds       map[string]int `json:"task_ids"`
+}
+
+type SwaggerImportJobResponse struct {
+	Id         int    `json:"id"          example:"1"`
+	Status     string `json:"status"      example:"succeeded"`
+	LastError  string `json:"last_error"  example:""`
+	StartedAt  string `json:"started_at"  example:"2024-01-01T12:00:01Z"`
+	FinishedAt string `json:"finished_at" example:"2024-01-01T12:00:02Z"`
+	CreatedAt  string `json:"created_at"  example:"2024-01-01T12:00:00Z"`
+}
+
+type SwaggerCalendarFeedResponse struct {
+	Token string `json:"token" example:"9f2c4e...b71a"`
+	Url   string `json:"url"   example:"https://example.com/api/calendar/9f2c4e...b71a/tasks.ics"`
+}
+
+type SwaggerChangeFeedTokenResponse struct {
+	Token     string `json:"token"      example:"eyJhbGciOi...x7Qk"`
+	ExpiresAt string `json:"expires_at" example:"2024-01-01T12:01:00Z"`
+}
+
+type SwaggerTaskImportResponse struct {
+	CollectionId int      `json:"collection_id,omitempty" example:"3"`
+	Ids          []int    `json:"ids"                     example:"10,11"`
+	Warnings     []string `json:"warnings,omitempty"      example:"To-do 1: Only the first occurrence has been imported"`
+}
+
+type SwaggerTaskOperationResultResponse struct {
+	Index     int                          `json:"index"  example:"0"`
+	Operation string                       `json:"op"     example:"update"`
+	Status    int                          `json:"status" example:"204"`
+	Id        int                          `json:"id"     example:"1"`
+	Error     *SwaggerGenericErrorResponse `json:"error"`
+}
+
+type SwaggerGraphQLResponse struct {
+	Data   map[string]interface{}        `json:"data"`
+	Errors []SwaggerGraphQLErrorResponse `json:"errors"`
+}
+
+type SwaggerGraphQLErrorResponse struct {
+	Message    string                 `json:"message"    example:"Not Found"`
+	Path       []string               `json:"path"       example:"updateTask"`
+	Extensions map[string]interface{} `json:"extensions"`
+}
+
+type SwaggerWebhookResponse struct {
+	Id         int      `json:"id"          example:"1"`
+	Url        string   `json:"url"         example:"https://example.com/hooks/todo"`
+	EventTypes []string `json:"event_types" example:"task.created,task.finished"`
+	Active     bool     `json:"active"      example:"true"`
+	Failures   int      `json:"failures"    example:"0"`
+	CreatedAt  string   `json:"created_at"  example:"2024-01-01T12:00:00Z"`
+	Secret     string   `json:"secret"      example:"5b1e0c...9d4f"`
+}
+
+type SwaggerWebhookDeliveryResponse struct {
+	Id             int                    `json:"id"              example:"1"`
+	EventId        int                    `json:"event_id"        example:"1718000000000001"`
+	EventType      string                 `json:"event_type"      example:"task.finished"`
+	Attempt        int                    `json:"attempt"         example:"1"`
+	Request        map[string]interface{} `json:"request"`
+	ResponseStatus int                    `json:"response_status" example:"200"`
+	Error          string                 `json:"error"           example:""`
+	Succeeded      bool                   `json:"succeeded"       example:"true"`
+	DurationMs     int                    `json:"duration_ms"     example:"120"`
+	CreatedAt      string                 `json:"created_at"      example:"2024-01-01T12:00:00Z"`
+}
+
+type SwaggerAutomationResponse struct {
+	Id        int                               `json:"id"         example:"1"`
+	Name      string                            `json:"name"       example:"Triage the bugs"`
+	Trigger   string                            `json:"trigger"    example:"task.created"`
+	Condition string                            `json:"condition"  example:"tag:bug"`
+	Actions   []SwaggerAutomationActionResponse `json:"actions"`
+	Active    bool                              `json:"active"     example:"true"`
+	CreatedAt string                            `json:"created_at" example:"2024-01-01T12:00:00Z"`
+}
+
+type SwaggerAutomationActionResponse struct {
+	Type         string `json:"type"          example:"move_to_collection"`
+	CollectionId int    `json:"collection_id" example:"3"`
+}
+
+type SwaggerReminderResponse struct {
+	Id               int    `json:"id"                 example:"1"`
+	TaskId           int    `json:"task_id"            example:"1"`
+	MinutesBeforeDue int    `json:"minutes_before_due" example:"60"`
+	Status           string `json:"status"             example:"sent"`
+	Attempts         int    `json:"attempts"           example:"0"`
+	ProcessedAt      string `json:"processed_at"       example:"2024-01-02T08:00:00Z"`
+	CreatedAt        string `json:"created_at"         example:"2024-01-01T12:00:00Z"`
+}
+
+type SwaggerNotificationPreferencesResponse struct {
+	Email              bool `json:"email"               example:"true"`
+	ScheduledReminders bool `json:"scheduled_reminders" example:"true"`
+	DueDateReminders   bool `json:"due_date_reminders"  example:"false"`
+}
+
+type SwaggerJobResponse struct {
+	Id          int                    `json:"id"           example:"1"`
+	Kind        string                 `json:"kind"         example:"reminders.send"`
+	Payload     map[string]interface{} `json:"payload"`
+	Status      string                 `json:"status"       example:"failed"`
+	Attempts    int                    `json:"attempts"     example:"5"`
+	MaxAttempts int                    `json:"max_attempts" example:"5"`
+	LastError   string                 `json:"last_error"   example:"dial tcp: connection refused"`
+	RunAt       string                 `json:"run_at"       example:"2024-01-01T12:00:00Z"`
+	Schedule    string                 `json:"schedule"     example:"reminders.send"`
+	StartedAt   string                 `json:"started_at"   example:"2024-01-01T12:05:10Z"`
+	FinishedAt  string                 `json:"finished_at"  example:"2024-01-01T12:05:11Z"`
+	CreatedAt   string                 `json:"created_at"   example:"2024-01-01T12:00:00Z"`
+}
+
+type SwaggerJobScheduleResponse struct {
+	Name      string                 `json:"name"        example:"outbox.purge"`
+	Kind      string                 `json:"kind"        example:"outbox.purge"`
+	Spec      string                 `json:"spec"        example:"@hourly"`
+	Payload   map[string]interface{} `json:"payload"`
+	NextRunAt string                 `json:"next_run_at" example:"2024-01-01T13:00:00Z"`
 }
 
 type SwaggerGenericErrorResponse struct {
@@ -39,6 +216,10 @@ type SwaggerForbiddenResponse struct {
 	Message string `json:"message" example:"Oops! You do not have access to this information."`
 }
 
+type SwaggerPreconditionFailedResponse struct {
+	Message string `json:"message" example:"The resource has been changed since it was last read. Fetch it again and retry."`
+}
+
 type SwaggerBadRequestResponse struct {
 	Message string `json:"message" example:"The request format is invalid."`
 }
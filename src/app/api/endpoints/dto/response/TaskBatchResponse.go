@@ -0,0 +1,16 @@
+package response
+
+type TaskBatch struct {
+	Mode      string                `json:"mode"`
+	Committed bool                  `json:"committed"`
+	Results   []TaskOperationResult `json:"results"`
+	UndoToken string                `json:"undo_token,omitempty"`
+}
+
+type TaskOperationResult struct {
+	Index     int                   `json:"index"`
+	Operation string                `json:"op"`
+	Status    int                   `json:"status"`
+	Id        int                   `json:"id,omitempty"`
+	Error     *GenericErrorResponse `json:"error,omitempty"`
+}
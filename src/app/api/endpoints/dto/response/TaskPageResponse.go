@@ -0,0 +1,22 @@
+package response
+
+import "todo/src/core/domain"
+
+type TaskPage struct {
+	Data []Task  `json:"data"`
+	Next *string `json:"next"`
+	Prev *string `json:"prev"`
+}
+
+func NewTaskPage(tasks []domain.Task, next, prev *string) *TaskPage {
+	data := []Task{}
+	for _, task := range tasks {
+		data = append(data, *NewTask(task))
+	}
+
+	return &TaskPage{
+		Data: data,
+		Next: next,
+		Prev: prev,
+	}
+}
@@ -1,12 +1,19 @@
 package response
 
-import "todo/src/core/domain"
+import (
+	"time"
+	"todo/src/core/domain"
+)
 
 type Task struct {
 	Id          int         `json:"id"`
 	Description string      `json:"description"`
 	Finished    bool        `json:"finished"`
 	Collection  *Collection `json:"collection"`
+	CreatedAt   *time.Time  `json:"created_at,omitempty"`
+	DueDate     *time.Time  `json:"due_date,omitempty"`
+	Tags        []string    `json:"tags,omitempty"`
+	Version     int         `json:"version,omitempty"`
 }
 
 func NewTask(task domain.Task) *Task {
@@ -17,5 +24,19 @@ func NewTask(task domain.Task) *Task {
 		Description: task.Description(),
 		Finished:    task.Finished(),
 		Collection:  collection,
+		CreatedAt:   optionalTime(task.CreatedAt()),
+		DueDate:     task.DueDate(),
+		Tags:        task.Tags(),
+		Version:     task.Version(),
 	}
 }
+
+// NewExpandedTask embeds the collection of the task when requested, only referencing it by its ID otherwise
+func NewExpandedTask(task domain.Task, expansion domain.Expansion) *Task {
+	taskResponse := NewTask(task)
+	if !expansion.Includes(domain.ExpandCollection) {
+		taskResponse.Collection = &Collection{Id: task.Collection().Id()}
+	}
+
+	return taskResponse
+}
@@ -0,0 +1,105 @@
+package response
+
+import (
+	"time"
+	"todo/src/core/domain"
+)
+
+type TransferCollection struct {
+	Id        int        `json:"id"`
+	Name      string     `json:"name"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+type TransferTask struct {
+	Id           int        `json:"id"`
+	Description  string     `json:"description"`
+	Finished     bool       `json:"finished"`
+	CollectionId int        `json:"collection_id,omitempty"`
+	CreatedAt    *time.Time `json:"created_at,omitempty"`
+	DueDate      *time.Time `json:"due_date,omitempty"`
+	Tags         []string   `json:"tags,omitempty"`
+}
+
+// TaskImportResult has the IDs of the imported tasks, in the order of the file, and the ID of their collection when
+// they were all imported into the same one
+type TaskImportResult struct {
+	CollectionId int      `json:"collection_id,omitempty"`
+	Ids          []int    `json:"ids"`
+	Warnings     []string `json:"warnings,omitempty"`
+}
+
+// Transfer has the collections and tasks of a transfer, with the IDs of the file
+type Transfer struct {
+	Collections []TransferCollection `json:"collections"`
+	Tasks       []TransferTask       `json:"tasks"`
+}
+
+type TransferResult struct {
+	CollectionIds map[int]int `json:"collection_ids"`
+	TaskIds       map[int]int `json:"task_ids"`
+}
+
+func NewTransferCollection(collection domain.Collection) *TransferCollection {
+	return &TransferCollection{
+		Id:        collection.Id(),
+		Name:      collection.Name(),
+		CreatedAt: optionalTime(collection.CreatedAt()),
+	}
+}
+
+func NewTransferTask(task domain.Task) *TransferTask {
+	return &TransferTask{
+		Id:           task.Id(),
+		Description:  task.Description(),
+		Finished:     task.Finished(),
+		CollectionId: task.Collection().Id(),
+		CreatedAt:    optionalTime(task.CreatedAt()),
+		DueDate:      task.DueDate(),
+		Tags:         task.Tags(),
+	}
+}
+
+func NewTransfer(transfer domain.Transfer) *Transfer {
+	collections := []TransferCollection{}
+	for _, collection := range transfer.Collections() {
+		collections = append(collections, *NewTransferCollection(collection))
+	}
+	tasks := []TransferTask{}
+	for _, task := range transfer.Tasks() {
+		tasks = append(tasks, *NewTransferTask(task))
+	}
+
+	return &Transfer{
+		Collections: collections,
+		Tasks:       tasks,
+	}
+}
+
+func NewTransferResult(result domain.TransferResult) *TransferResult {
+	return &TransferResult{
+		CollectionIds: result.CollectionIds(),
+		TaskIds:       result.TaskIds(),
+	}
+}
+
+func NewTaskImportResult(ids []int) *TaskImportResult {
+	return &TaskImportResult{
+		Ids: ids,
+	}
+}
+
+// NewTaskImportResultWithWarnings tells what has been lost when importing the tasks
+func NewTaskImportResultWithWarnings(ids []int, warnings []string) *TaskImportResult {
+	return &TaskImportResult{
+		Ids:      ids,
+		Warnings: warnings,
+	}
+}
+
+func NewCollectionTaskImportResult(collectionId int, ids []int) *TaskImportResult {
+	return &TaskImportResult{
+		CollectionId: collectionId,
+		Ids:          ids,
+	}
+}
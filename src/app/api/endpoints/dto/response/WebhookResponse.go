@@ -0,0 +1,57 @@
+package response
+
+import (
+	"encoding/json"
+	"time"
+	"todo/src/core/domain"
+)
+
+type Webhook struct {
+	Id         int        `json:"id"`
+	Url        string     `json:"url"`
+	EventTypes []string   `json:"event_types"`
+	Active     bool       `json:"active"`
+	Failures   int        `json:"failures"`
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+	Secret     string     `json:"secret,omitempty"`
+}
+
+func NewWebhook(webhook domain.Webhook) *Webhook {
+	return &Webhook{
+		Id:         webhook.Id(),
+		Url:        webhook.Url(),
+		EventTypes: append([]string{}, webhook.EventTypes()...),
+		Active:     webhook.Active(),
+		Failures:   webhook.Failures(),
+		CreatedAt:  optionalTime(webhook.CreatedAt()),
+		Secret:     webhook.Secret(),
+	}
+}
+
+type WebhookDelivery struct {
+	Id             int64           `json:"id"`
+	EventId        int64           `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Attempt        int             `json:"attempt"`
+	Request        json.RawMessage `json:"request"`
+	ResponseStatus int             `json:"response_status,omitempty"`
+	Error          string          `json:"error,omitempty"`
+	Succeeded      bool            `json:"succeeded"`
+	DurationMs     int64           `json:"duration_ms"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+func NewWebhookDelivery(delivery domain.WebhookDelivery) *WebhookDelivery {
+	return &WebhookDelivery{
+		Id:             delivery.Id(),
+		EventId:        delivery.EventId(),
+		EventType:      delivery.EventType(),
+		Attempt:        delivery.Attempt(),
+		Request:        json.RawMessage(delivery.RequestBody()),
+		ResponseStatus: delivery.ResponseStatus(),
+		Error:          delivery.ErrorMessage(),
+		Succeeded:      delivery.Succeeded(),
+		DurationMs:     delivery.Duration().Milliseconds(),
+		CreatedAt:      delivery.CreatedAt(),
+	}
+}
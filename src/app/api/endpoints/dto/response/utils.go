@@ -0,0 +1,10 @@
+package response
+
+import "time"
+
+func optionalTime(value time.Time) *time.Time {
+	if value.IsZero() {
+		return nil
+	}
+	return &value
+}
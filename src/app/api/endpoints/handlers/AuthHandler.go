@@ -7,6 +7,7 @@ import (
 	"todo/src/app/api/endpoints/dto/response"
 	"todo/src/app/api/endpoints/handlers/msgs"
 	"todo/src/core/domain"
+	eventbus "todo/src/core/interfaces/events"
 	interfaces "todo/src/core/interfaces/services"
 	"todo/src/core/services"
 	"todo/src/infra/postgres"
@@ -16,10 +17,10 @@ type Auth struct {
 	service interfaces.IAuth
 }
 
-func NewAuthHandler() *Auth {
+func NewAuthHandler(emitter eventbus.IEmitter) *Auth {
 	connectionManager := postgres.NewPostgresConnectionManager()
 	repository := postgres.NewAuthPostgresRepository(connectionManager)
-	service := services.NewAuthService(repository)
+	service := services.NewAuthService(repository, emitter)
 	return &Auth{service}
 }
 
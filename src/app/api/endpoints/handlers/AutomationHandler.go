@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+	"todo/src/app/api/endpoints/dto/request"
+	"todo/src/app/api/endpoints/dto/response"
+	"todo/src/app/api/endpoints/handlers/msgs"
+	"todo/src/core/domain"
+	interfaces "todo/src/core/interfaces/services"
+	"todo/src/core/projecterrors/todoerrors"
+	"todo/src/core/services"
+	"todo/src/infra/postgres"
+)
+
+type Automation struct {
+	service interfaces.IAutomation
+}
+
+func NewAutomationHandler() *Automation {
+	connectionManager := postgres.NewPostgresConnectionManager()
+	repository := postgres.NewAutomationPostgresRepository(connectionManager)
+	service := services.NewAutomationService(repository)
+	return &Automation{service}
+}
+
+// Create
+// @ID 			CreateAutomation
+// @Summary		Create an automation
+// @Tags 		Automation
+// @Description Route that registers a rule run on each event of the account with its trigger whose task or collection meets its condition. The triggers are task.created, task.updated, task.finished, collection.created and collection.completed, the last one following the update that finishes the last unfinished task of a collection.
+// @Description The condition is a filter expression such as those of the tasks, empty meaning always. The conditions of the collection triggers can only compare the collection and the text of its name.
+// @Description The actions of the task triggers are move_to_collection (collection_id), add_tag and remove_tag (tag), set_finished (finished) and delete_task. The actions of the collection triggers are rename_collection (text, where {name} is the current name), create_task (text) and archive_collection, which sets the archived_at of the collection.
+// @Description The changes made by the actions can trigger other automations, up to 3 in a row, and an automation acts at most once on a task or collection for each change made by the user.
+// @Accept 		json
+// @Produce 	json
+// @Security	bearerAuth
+// @Param 	    userId          path       int                                 true    "User ID"    default(1)
+// @Param 		automationJson  body 		request.SwaggerAutomationRequest    true    "Name, trigger, condition, actions and whether the automation is active, which it is by default"
+// @Param 	    Idempotency-Key header      string                           false   "Unique key that makes retries of this request return the first response"
+// @Success 	201             {object} 	response.SwaggerIdResponse                 "Automation successfully registered"
+// @Failure 	400             {object} 	response.SwaggerBadRequestResponse         "The user has made a bad request"
+// @Failure 	401             {object}    response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403             {object}    response.SwaggerForbiddenResponse   	   "The user does not have access to this information"
+// @Failure 	422 		    {object} 	response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Failure 	500 		    {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/user/{userId}/automation  [post]
+func (h Automation) Create(ctx echo.Context) error {
+	userId, err := convertToPositiveInteger(ctx.Param("userId"), msgs.UserId)
+	if err != nil {
+		log.Error(err)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.UserId, msgs.ConversionError)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
+	}
+	automationData, validationErr, err := bindAutomation(ctx, -1)
+	if err != nil {
+		log.Error(err)
+		return writeBadRequestError(ctx, msgs.RequestFormatError)
+	}
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+
+	automationId, err := h.service.Create(*automationData, userId)
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	responseReturned := map[string]int{"id": automationId}
+	return writeCreatedResponse(ctx, responseReturned)
+}
+
+// FindAll
+// @ID 			FindAutomations
+// @Summary		List the automations
+// @Tags 		Automation
+// @Produce 	json
+// @Security	bearerAuth
+// @Param 	    userId       path       int                  true                  "User ID"    default(1)
+// @Success 	200 		 {array} 	response.SwaggerAutomationResponse         "Successful request"
+// @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403          {object}   response.SwaggerForbiddenResponse 	       "The user does not have access to this information"
+// @Failure 	422 		 {object} 	response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Failure 	500 		 {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/user/{userId}/automation  [get]
+func (h Automation) FindAll(ctx echo.Context) error {
+	userId, err := convertToPositiveInteger(ctx.Param("userId"), msgs.UserId)
+	if err != nil {
+		log.Error(err)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.UserId, msgs.ConversionError)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
+	}
+
+	automationList, err := h.service.FindAll(userId)
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	automationResponseList := []response.Automation{}
+	for _, automationData := range automationList {
+		automationResponseList = append(automationResponseList, *response.NewAutomation(automationData))
+	}
+	return writeAcceptResponse(ctx, automationResponseList)
+}
+
+// FindById
+// @ID 			FindAutomationById
+// @Summary		Find an automation
+// @Tags 		Automation
+// @Produce 	json
+// @Security	bearerAuth
+// @Param 	    userId          path       int                  true               "User ID"          default(1)
+// @Param 	    automationId    path       int                  true               "Automation ID"    default(1)
+// @Success 	200 		    {object} 	response.SwaggerAutomationResponse         "Successful request"
+// @Failure 	401             {object}    response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403             {object}    response.SwaggerForbiddenResponse 	       "The user does not have access to this information"
+// @Failure 	404 		    {object} 	response.SwaggerNotFoundErrorResponse 	   "The user has requested a non-existent resource"
+// @Failure 	422 		    {object} 	response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Failure 	500 		    {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/user/{userId}/automation/{automationId}  [get]
+func (h Automation) FindById(ctx echo.Context) error {
+	userId, automationId, validationErr := parseAutomationParams(ctx)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+
+	automationData, err := h.service.FindById(automationId, userId)
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	return writeAcceptResponse(ctx, response.NewAutomation(*automationData))
+}
+
+// Update
+// @ID 			UpdateAutomation
+// @Summary		Update an automation
+// @Tags 		Automation
+// @Description Route that replaces the name, the trigger, the condition, the actions and the state of an automation.
+// @Accept 		json
+// @Produce 	json
+// @Security	bearerAuth
+// @Param 	    userId          path       int                                 true    "User ID"          default(1)
+// @Param 	    automationId    path       int                                 true    "Automation ID"    default(1)
+// @Param 		automationJson  body 		request.SwaggerAutomationRequest    true    "Name, trigger, condition, actions and whether the automation is active, which it is by default"
+// @Success 	204             {object}    nil                                        "Automation successfully edited"
+// @Failure 	400             {object} 	response.SwaggerBadRequestResponse         "The user has made a bad request"
+// @Failure 	401             {object}    response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403             {object}    response.SwaggerForbiddenResponse   	   "The user does not have access to this information"
+// @Failure 	404 		    {object} 	response.SwaggerNotFoundErrorResponse 	   "The user has requested a non-existent resource"
+// @Failure 	422 		    {object} 	response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Failure 	500 		    {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/user/{userId}/automation/{automationId}  [put]
+func (h Automation) Update(ctx echo.Context) error {
+	userId, automationId, validationErr := parseAutomationParams(ctx)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+	automationData, validationErr, err := bindAutomation(ctx, automationId)
+	if err != nil {
+		log.Error(err)
+		return writeBadRequestError(ctx, msgs.RequestFormatError)
+	}
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+
+	if err = h.service.Update(*automationData, userId); err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	return writeNoContentResponse(ctx)
+}
+
+// Delete
+// @ID 			DeleteAutomation
+// @Summary		Delete an automation
+// @Tags 		Automation
+// @Security	bearerAuth
+// @Param 	    userId          path       int                  true               "User ID"          default(1)
+// @Param 	    automationId    path       int                  true               "Automation ID"    default(1)
+// @Success 	204             {object}    nil                                        "Automation successfully deleted"
+// @Failure 	401             {object}    response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403             {object}    response.SwaggerForbiddenResponse 	       "The user does not have access to this information"
+// @Failure 	404 		    {object} 	response.SwaggerNotFoundErrorResponse 	   "The user has requested a non-existent resource"
+// @Failure 	422 		    {object} 	response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Failure 	500 		    {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/user/{userId}/automation/{automationId}  [delete]
+func (h Automation) Delete(ctx echo.Context) error {
+	userId, automationId, validationErr := parseAutomationParams(ctx)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+
+	if err := h.service.Delete(automationId, userId); err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	return writeNoContentResponse(ctx)
+}
+
+func parseAutomationParams(ctx echo.Context) (int, int, *todoerrors.Validation) {
+	invalidFields := todoerrors.InvalidFields{}
+	userId, err := convertToPositiveInteger(ctx.Param("userId"), msgs.UserId)
+	if err != nil {
+		invalidFields.AppendField(msgs.UserId, msgs.ConversionError)
+	}
+	automationId, err := convertToPositiveInteger(ctx.Param("automationId"), msgs.AutomationId)
+	if err != nil {
+		invalidFields.AppendField(msgs.AutomationId, msgs.ConversionError)
+	}
+	if invalidFields.HasInvalidFields() {
+		return 0, 0, todoerrors.NewValidationError(msgs.InvalidQueryParams, invalidFields)
+	}
+
+	return userId, automationId, nil
+}
+
+// bindAutomation reads the automation of the request, which is active unless told otherwise
+func bindAutomation(ctx echo.Context, automationId int) (*domain.Automation, *todoerrors.Validation, error) {
+	var requestData request.Automation
+	if err := ctx.Bind(&requestData); err != nil {
+		return nil, nil, err
+	}
+	active := requestData.Active == nil || *requestData.Active
+
+	var actions []domain.AutomationAction
+	for _, action := range requestData.Actions {
+		actions = append(actions, *domain.NewAutomationAction(action.Type, action.CollectionId, action.Tag,
+			action.Finished, action.Text))
+	}
+
+	automationData, validationErr := domain.NewValidatedAutomation(automationId, requestData.Name,
+		requestData.Trigger, requestData.Condition, actions, active)
+	return automationData, validationErr, nil
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"testing"
+	"todo/src/core/domain"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+type MockAutomationService struct {
+	mock.Mock
+}
+
+func (m *MockAutomationService) Create(automation domain.Automation, userId int) (int, error) {
+	args := m.Called(automation, userId)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockAutomationService) Update(automation domain.Automation, userId int) error {
+	args := m.Called(automation, userId)
+	return args.Error(0)
+}
+
+func (m *MockAutomationService) Delete(automationId, userId int) error {
+	args := m.Called(automationId, userId)
+	return args.Error(0)
+}
+
+func (m *MockAutomationService) FindById(automationId, userId int) (*domain.Automation, error) {
+	args := m.Called(automationId, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.Automation), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAutomationService) FindAll(userId int) ([]domain.Automation, error) {
+	args := m.Called(userId)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.Automation), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestAutomation_Create(t *testing.T) {
+	t.Run("should return 201 with the ID of the automation", func(t *testing.T) {
+		context, responseData := newWebhookContext(http.MethodPost, "/automation",
+			`{"name": " Triage ", "trigger": "Task.Created", "condition": "tag:bug",
+			"actions": [{"type": "move_to_collection", "collection_id": 7, "tag": "ignored"}]}`,
+			[]string{"userId"}, []string{"1"})
+		mockService := new(MockAutomationService)
+		automationHandler := Automation{mockService}
+		mockService.On("Create", mock.MatchedBy(func(automation domain.Automation) bool {
+			return automation.Name() == "Triage" && automation.Trigger() == domain.EventTaskCreated &&
+				automation.Active() && automation.Actions()[0].CollectionId() == 7 &&
+				automation.Actions()[0].Tag() == ""
+		}), 1).Return(4, nil)
+
+		_ = automationHandler.Create(context)
+
+		assert.Equal(t, http.StatusCreated, responseData.Code)
+		assert.JSONEq(t, `{"id": 4}`, responseData.Body.String())
+	})
+
+	t.Run("should return 422 when the trigger or the actions are not valid", func(t *testing.T) {
+		context, responseData := newWebhookContext(http.MethodPost, "/automation",
+			`{"name": "Archive", "trigger": "collection.archived", "actions": [{"type": "teleport"}]}`,
+			[]string{"userId"}, []string{"1"})
+		automationHandler := Automation{new(MockAutomationService)}
+
+		_ = automationHandler.Create(context)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, responseData.Code)
+		assert.Contains(t, responseData.Body.String(), "Automation Trigger")
+		assert.Contains(t, responseData.Body.String(), "Action 1")
+	})
+
+	t.Run("should return 422 when a collection trigger has a task condition", func(t *testing.T) {
+		context, responseData := newWebhookContext(http.MethodPost, "/automation",
+			`{"name": "Archive", "trigger": "collection.completed", "condition": "tag:bug",
+			"actions": [{"type": "rename_collection", "text": "[Done] {name}"}]}`,
+			[]string{"userId"}, []string{"1"})
+		automationHandler := Automation{new(MockAutomationService)}
+
+		_ = automationHandler.Create(context)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, responseData.Code)
+		assert.Contains(t, responseData.Body.String(), "Automation Condition")
+	})
+}
+
+func TestAutomation_FindById(t *testing.T) {
+	t.Run("should return the automation with the parameters of each action", func(t *testing.T) {
+		context, responseData := newWebhookContext(http.MethodGet, "/automation/4", "",
+			[]string{"userId", "automationId"}, []string{"1", "4"})
+		mockService := new(MockAutomationService)
+		automationHandler := Automation{mockService}
+		automation := domain.NewAutomation(4, "Close bugs", domain.EventTaskUpdated, "tag:fixed",
+			[]domain.AutomationAction{
+				*domain.NewAutomationAction(domain.ActionSetFinished, 0, "", false, ""),
+				*domain.NewAutomationAction(domain.ActionRemoveTag, 0, "fixed", false, ""),
+			}, false)
+		mockService.On("FindById", 4, 1).Return(automation, nil)
+
+		_ = automationHandler.FindById(context)
+
+		assert.Equal(t, http.StatusOK, responseData.Code)
+		assert.JSONEq(t, `{"id": 4, "name": "Close bugs", "trigger": "task.updated", "condition": "tag:fixed",
+			"actions": [{"type": "set_finished", "finished": false}, {"type": "remove_tag", "tag": "fixed"}],
+			"active": false}`, responseData.Body.String())
+	})
+}
+
+func TestAutomation_Update(t *testing.T) {
+	t.Run("should return 404 when the automation does not exist", func(t *testing.T) {
+		context, responseData := newWebhookContext(http.MethodPut, "/automation/9",
+			`{"name": "Triage", "trigger": "task.created", "actions": [{"type": "add_tag", "tag": "new"}]}`,
+			[]string{"userId", "automationId"}, []string{"1", "9"})
+		mockService := new(MockAutomationService)
+		automationHandler := Automation{mockService}
+		mockService.On("Update", mock.Anything, 1).Return(todoerrors.NewNotFoundError())
+
+		_ = automationHandler.Update(context)
+
+		assert.Equal(t, http.StatusNotFound, responseData.Code)
+	})
+}
@@ -0,0 +1,535 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+	"hash/fnv"
+	"net/http"
+	"net/url"
+	"strings"
+	"todo/src/app/api/endpoints/handlers/msgs"
+	"todo/src/core/domain"
+	"todo/src/core/icalendar"
+	eventbus "todo/src/core/interfaces/events"
+	interfaces "todo/src/core/interfaces/services"
+	"todo/src/core/projecterrors/todoerrors"
+	"todo/src/core/services"
+	"todo/src/infra/postgres"
+)
+
+const (
+	calDavPath           = "/api/caldav/user/"
+	calDavCapabilities   = "1, calendar-access"
+	calDavAllowedMethods = "OPTIONS, PROPFIND, REPORT, GET, PUT, DELETE"
+	calDavDisplayName    = "Tasks"
+	calDavObjectType     = mimeTextCalendar + "; charset=utf-8; component=VTODO"
+	depthResource        = "0"
+)
+
+// CalDav exposes each collection of the user as a CalDAV task calendar, whose objects are the tasks of the collection.
+// The tasks are changed through the task service, so that CalDAV clients follow the same rules as the API.
+type CalDav struct {
+	service           interfaces.ICalDav
+	taskService       interfaces.ITask
+	collectionService interfaces.ICollection
+}
+
+func NewCalDavHandler(emitter eventbus.IEmitter) *CalDav {
+	connectionManager := postgres.NewPostgresConnectionManager()
+	repository := postgres.NewCalDavPostgresRepository(connectionManager)
+	undoRepository := postgres.NewUndoPostgresRepository(connectionManager)
+	taskRepository := postgres.NewTaskPostgresRepository(connectionManager)
+	collectionRepository := postgres.NewCollectionPostgresRepository(connectionManager)
+	return &CalDav{
+		service:           services.NewCalDavService(repository),
+		taskService:       services.NewTaskService(taskRepository, undoRepository, emitter),
+		collectionService: services.NewCollectionService(collectionRepository, undoRepository, emitter),
+	}
+}
+
+// Options
+// @ID 			CalDavOptions
+// @Summary		CalDAV capabilities
+// @Tags 		CalDAV
+// @Description Route that tells CalDAV clients the supported methods and the calendar-access capability. The CalDAV routes accept HTTP Basic authentication with the email and password of the user, besides the bearer token.
+// @Security	basicAuth
+// @Param 	    userId       path       int                  true                  "User ID"    default(1)
+// @Success 	200 		 {object} 	nil                                        "Successful request"
+// @Router 		/caldav/user/{userId}  [options]
+func (h CalDav) Options(ctx echo.Context) error {
+	header := ctx.Response().Header()
+	header.Set(headerDav, calDavCapabilities)
+	header.Set(echo.HeaderAllow, calDavAllowedMethods)
+
+	return ctx.NoContent(http.StatusOK)
+}
+
+// PropfindHome answers for the principal of the user, which is also its calendar home, and for its calendars when
+// the depth is not zero. It is not described in the Swagger documentation, which has no WebDAV methods.
+func (h CalDav) PropfindHome(ctx echo.Context) error {
+	userId, err := convertToPositiveInteger(ctx.Param("userId"), msgs.UserId)
+	if err != nil {
+		log.Error(err)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.UserId, msgs.ConversionError)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
+	}
+	request, err := readPropfind(ctx.Request().Body)
+	if err != nil {
+		log.Error(err)
+		return writeBadRequestError(ctx, msgs.RequestFormatError)
+	}
+
+	responses := []davResponse{newDavResponse(calDavHomeHref(userId), *request, homeProperties(userId))}
+	if ctx.Request().Header.Get(headerDepth) != depthResource {
+		collections, err := h.findAllCollections(userId)
+		if err != nil {
+			log.Error(err)
+			return handleServiceErrors(ctx, err)
+		}
+		for _, collection := range collections {
+			objects, err := h.service.FindObjects(collection.Id(), userId)
+			if err != nil {
+				log.Error(err)
+				return handleServiceErrors(ctx, err)
+			}
+			responses = append(responses, newDavResponse(calDavCalendarHref(userId, collection.Id()), *request,
+				calendarProperties(userId, collection, objects)))
+		}
+	}
+
+	return writeMultistatus(ctx, responses)
+}
+
+// PropfindCalendar answers for the calendar of a collection, and for its objects when the depth is not zero
+func (h CalDav) PropfindCalendar(ctx echo.Context) error {
+	userId, collectionId, validationErr := calDavParams(ctx)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+	request, err := readPropfind(ctx.Request().Body)
+	if err != nil {
+		log.Error(err)
+		return writeBadRequestError(ctx, msgs.RequestFormatError)
+	}
+
+	collection, err := h.collectionService.FindById(collectionId, userId, *domain.NewExpansion())
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+	objects, err := h.service.FindObjects(collectionId, userId)
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	calendarHref := calDavCalendarHref(userId, collectionId)
+	responses := []davResponse{newDavResponse(calendarHref, *request,
+		calendarProperties(userId, *collection, objects))}
+	if ctx.Request().Header.Get(headerDepth) != depthResource {
+		for _, object := range objects {
+			responses = append(responses, newDavResponse(calDavObjectHref(calendarHref, object.Name()), *request,
+				objectProperties(object, false)))
+		}
+	}
+
+	return writeMultistatus(ctx, responses)
+}
+
+func (h CalDav) PropfindObject(ctx echo.Context) error {
+	userId, collectionId, validationErr := calDavParams(ctx)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+	request, err := readPropfind(ctx.Request().Body)
+	if err != nil {
+		log.Error(err)
+		return writeBadRequestError(ctx, msgs.RequestFormatError)
+	}
+
+	object, err := h.service.FindObject(ctx.Param("object"), collectionId, userId)
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	href := calDavObjectHref(calDavCalendarHref(userId, collectionId), object.Name())
+	return writeMultistatus(ctx, []davResponse{newDavResponse(href, *request, objectProperties(*object, false))})
+}
+
+// Report answers the calendar-query and calendar-multiget reports of a calendar. The queries only select the
+// component, every to-do of the calendar being returned whatever the filters on its properties.
+func (h CalDav) Report(ctx echo.Context) error {
+	userId, collectionId, validationErr := calDavParams(ctx)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+	report, err := readReport(ctx.Request().Body)
+	if err != nil {
+		log.Error(err)
+		return writeBadRequestError(ctx, msgs.RequestFormatError)
+	}
+	if report.XMLName != calDavCalendarQuery && report.XMLName != calDavCalendarMultiget {
+		log.Error(msgs.UnsupportedReport)
+		return writeBadRequestError(ctx, msgs.UnsupportedReport)
+	}
+
+	objects, err := h.service.FindObjects(collectionId, userId)
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	calendarHref := calDavCalendarHref(userId, collectionId)
+	responses := []davResponse{}
+	if report.XMLName == calDavCalendarQuery {
+		if report.Filter.matchesTodos() {
+			for _, object := range objects {
+				responses = append(responses, newDavResponse(calDavObjectHref(calendarHref, object.Name()),
+					report.Prop, objectProperties(object, true)))
+			}
+		}
+		return writeMultistatus(ctx, responses)
+	}
+
+	objectsByHref := map[string]domain.CalendarObject{}
+	for _, object := range objects {
+		objectsByHref[calDavObjectHref(calendarHref, object.Name())] = object
+	}
+	for _, href := range report.Hrefs {
+		href = normalizeHref(href)
+		if object, ok := objectsByHref[href]; ok {
+			responses = append(responses, newDavResponse(href, report.Prop, objectProperties(object, true)))
+		} else {
+			responses = append(responses, newDavNotFoundResponse(href))
+		}
+	}
+
+	return writeMultistatus(ctx, responses)
+}
+
+// Get
+// @ID 			CalDavGet
+// @Summary		Get a calendar object
+// @Tags 		CalDAV
+// @Description Route that returns a task of the collection as an iCalendar object with a single VTODO. The ETag changes with the task version.
+// @Produce 	text/calendar
+// @Security	basicAuth
+// @Param 	    userId       path       int                  true                  "User ID"    default(1)
+// @Param 	    collectionId path       int                  true                  "Collection ID"    default(1)
+// @Param 	    object       path       string               true                  "Object name"    default(task-1.ics)
+// @Param 		If-None-Match header 	string 				 false 				   "ETag the client already has"
+// @Success 	200 		 {string} 	string                                     "Successful request"
+// @Success 	304 		 {object} 	nil                                        "The object has not changed"
+// @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403          {object}   response.SwaggerForbiddenResponse 	       "The user does not have access to this information"
+// @Failure 	404 		 {object} 	response.SwaggerNotFoundErrorResponse 	   "The object does not exist"
+// @Failure 	422 		 {object} 	response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Failure 	500 		 {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/caldav/user/{userId}/{collectionId}/{object}  [get]
+func (h CalDav) Get(ctx echo.Context) error {
+	userId, collectionId, validationErr := calDavParams(ctx)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+
+	object, err := h.service.FindObject(ctx.Param("object"), collectionId, userId)
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	etag := entityTag(object.Task().Version())
+	ctx.Response().Header().Set(headerETag, etag)
+	for _, tag := range splitEntityTags(ctx.Request().Header.Get(headerIfNoneMatch)) {
+		if tag == anyEntityTag || strings.TrimPrefix(tag, weakTagPrefix) == etag {
+			return ctx.NoContent(http.StatusNotModified)
+		}
+	}
+
+	return ctx.Blob(http.StatusOK, mimeTextCalendar+"; charset=utf-8", encodeCalendarObject(*object))
+}
+
+// Put
+// @ID 			CalDavPut
+// @Summary		Create or update a calendar object
+// @Tags 		CalDAV
+// @Description Route that creates a task in the collection from an iCalendar object with a single VTODO, or updates the task of the object. The name and UID of the new objects are kept. An If-Match header only updates the task when it still has the ETag, and an If-None-Match header with * only creates it. No ETag is returned, since the stored task only keeps the summary, status, due date and categories of the to-do. Recurring to-dos, with an RRULE, are stored as their first occurrence, as the tasks have no recurrence.
+// @Accept 		text/calendar
+// @Security	basicAuth
+// @Param 	    userId       path       int                  true                  "User ID"    default(1)
+// @Param 	    collectionId path       int                  true                  "Collection ID"    default(1)
+// @Param 	    object       path       string               true                  "Object name"    default(task-1.ics)
+// @Param 		If-Match 	 header 	string 				 false 				   "ETag the task must have"
+// @Param 		If-None-Match header 	string 				 false 				   "* to only create the object"
+// @Success 	201 		 {object} 	nil                                        "Object successfully created"
+// @Success 	204 		 {object} 	nil                                        "Object successfully updated"
+// @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403          {object}   response.SwaggerForbiddenResponse 	       "The user does not have access to this information"
+// @Failure 	404 		 {object} 	response.SwaggerNotFoundErrorResponse 	   "The collection does not exist"
+// @Failure 	412 		 {object} 	response.SwaggerPreconditionFailedResponse "The object does not match the precondition"
+// @Failure 	422 		 {object} 	response.SwaggerValidationErrorResponse    "The object is not valid"
+// @Failure 	500 		 {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/caldav/user/{userId}/{collectionId}/{object}  [put]
+func (h CalDav) Put(ctx echo.Context) error {
+	userId, collectionId, validationErr := calDavParams(ctx)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+	decodedObject, warnings, validationErr := icalendar.DecodeObject(ctx.Request().Body)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+	for _, warning := range warnings {
+		log.Warn(warning)
+	}
+	name := ctx.Param("object")
+	newObject, validationErr := domain.NewValidatedCalendarObject(name, decodedObject.Uid(), decodedObject.Task())
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+
+	storedObject, err := h.service.FindObject(name, collectionId, userId)
+	if _, notFound := err.(*todoerrors.NotFound); notFound {
+		return h.createObject(ctx, *newObject, collectionId, userId)
+	} else if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	if ctx.Request().Header.Get(headerIfNoneMatch) == anyEntityTag {
+		log.Error(msgs.ObjectAlreadyExists)
+		return handleServiceErrors(ctx, todoerrors.NewPreconditionFailedError())
+	}
+	version, err := ifMatchVersion(ctx, func() (int, error) {
+		return storedObject.Task().Version(), nil
+	})
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	task := calendarTask(storedObject.Task().Id(), collectionId, *newObject.Task())
+	task.SetVersion(version)
+	if _, err = h.taskService.Update(*task, userId); err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	return writeNoContentResponse(ctx)
+}
+
+// createObject creates the task, then keeps the name and UID of the object. The task is deleted again if they cannot
+// be kept, since the client would not find it under its name.
+func (h CalDav) createObject(ctx echo.Context, object domain.CalendarObject, collectionId, userId int) error {
+	if ctx.Request().Header.Get(headerIfMatch) != "" {
+		log.Error(msgs.ObjectNotFound)
+		return handleServiceErrors(ctx, todoerrors.NewPreconditionFailedError())
+	}
+
+	task := calendarTask(0, collectionId, *object.Task())
+	id, err := h.taskService.Create(*task, userId)
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	createdTask := calendarTask(id, collectionId, *task)
+	err = h.service.SaveObject(*domain.NewCalendarObject(object.Name(), object.Uid(), createdTask), userId)
+	if err != nil {
+		log.Error(err)
+		if _, deleteErr := h.taskService.Delete(id, userId, 0); deleteErr != nil {
+			log.Error(deleteErr)
+		}
+		return handleServiceErrors(ctx, err)
+	}
+
+	return ctx.NoContent(http.StatusCreated)
+}
+
+// Delete
+// @ID 			CalDavDelete
+// @Summary		Delete a calendar object
+// @Tags 		CalDAV
+// @Description Route that deletes the task of the object. An If-Match header only deletes it when it still has the ETag.
+// @Security	basicAuth
+// @Param 	    userId       path       int                  true                  "User ID"    default(1)
+// @Param 	    collectionId path       int                  true                  "Collection ID"    default(1)
+// @Param 	    object       path       string               true                  "Object name"    default(task-1.ics)
+// @Param 		If-Match 	 header 	string 				 false 				   "ETag the task must have"
+// @Success 	204 		 {object} 	nil                                        "Object successfully deleted"
+// @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403          {object}   response.SwaggerForbiddenResponse 	       "The user does not have access to this information"
+// @Failure 	404 		 {object} 	response.SwaggerNotFoundErrorResponse 	   "The object does not exist"
+// @Failure 	412 		 {object} 	response.SwaggerPreconditionFailedResponse "The task no longer has the ETag"
+// @Failure 	422 		 {object} 	response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Failure 	500 		 {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/caldav/user/{userId}/{collectionId}/{object}  [delete]
+func (h CalDav) Delete(ctx echo.Context) error {
+	userId, collectionId, validationErr := calDavParams(ctx)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+
+	object, err := h.service.FindObject(ctx.Param("object"), collectionId, userId)
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+	version, err := ifMatchVersion(ctx, func() (int, error) {
+		return object.Task().Version(), nil
+	})
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	if _, err = h.taskService.Delete(object.Task().Id(), userId, version); err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	return writeNoContentResponse(ctx)
+}
+
+func (h CalDav) findAllCollections(userId int) ([]domain.Collection, error) {
+	var collections []domain.Collection
+	filter := domain.NewCollectionFilter("", nil, nil)
+	for offset := 0; ; offset += domain.MaxPageLimit {
+		pagination := domain.NewPagination(domain.MaxPageLimit, offset, "id", domain.SortAscending)
+		page, _, err := h.collectionService.FindAll(userId, *filter, *pagination)
+		if err != nil {
+			return nil, err
+		}
+		collections = append(collections, page...)
+		if len(page) < domain.MaxPageLimit {
+			return collections, nil
+		}
+	}
+}
+
+func calDavParams(ctx echo.Context) (int, int, *todoerrors.Validation) {
+	invalidFields := todoerrors.InvalidFields{}
+	userId, err := convertToPositiveInteger(ctx.Param("userId"), msgs.UserId)
+	if err != nil {
+		invalidFields.AppendField(msgs.UserId, msgs.ConversionError)
+	}
+	collectionId, err := convertToPositiveInteger(ctx.Param("collectionId"), msgs.CollectionId)
+	if err != nil {
+		invalidFields.AppendField(msgs.CollectionId, msgs.ConversionError)
+	}
+	if invalidFields.HasInvalidFields() {
+		return 0, 0, todoerrors.NewValidationError(msgs.InvalidCalDavParams, invalidFields)
+	}
+
+	return userId, collectionId, nil
+}
+
+func calDavHomeHref(userId int) string {
+	return fmt.Sprintf("%s%d/", calDavPath, userId)
+}
+
+func calDavCalendarHref(userId, collectionId int) string {
+	return fmt.Sprintf("%s%d/", calDavHomeHref(userId), collectionId)
+}
+
+func calDavObjectHref(calendarHref, name string) string {
+	return calendarHref + url.PathEscape(name)
+}
+
+// normalizeHref reduces the hrefs of a multiget, which may be absolute URLs and escape other characters, to the
+// paths the objects are listed with
+func normalizeHref(href string) string {
+	parsedUrl, err := url.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return href
+	}
+	index := strings.LastIndex(parsedUrl.Path, "/")
+
+	return calDavObjectHref(parsedUrl.Path[:index+1], parsedUrl.Path[index+1:])
+}
+
+func homeProperties(userId int) []davProperty {
+	href := calDavHomeHref(userId)
+	return []davProperty{
+		newDavProperty(davResourceType, `<collection xmlns="DAV:"/><principal xmlns="DAV:"/>`),
+		newDavTextProperty(davDisplayName, calDavDisplayName),
+		newDavHrefProperty(davCurrentUserPrincipal, href),
+		newDavHrefProperty(davPrincipalUrl, href),
+		newDavHrefProperty(calDavCalendarHomeSet, href),
+	}
+}
+
+func calendarProperties(userId int, collection domain.Collection, objects []domain.CalendarObject) []davProperty {
+	return []davProperty{
+		newDavProperty(davResourceType,
+			`<collection xmlns="DAV:"/><calendar xmlns="urn:ietf:params:xml:ns:caldav"/>`),
+		newDavTextProperty(davDisplayName, collection.Name()),
+		newDavHrefProperty(davCurrentUserPrincipal, calDavHomeHref(userId)),
+		newDavProperty(calDavSupportedComponentSet, `<comp xmlns="urn:ietf:params:xml:ns:caldav" name="VTODO"/>`),
+		newDavProperty(davSupportedReportSet,
+			`<supported-report xmlns="DAV:"><report><calendar-query xmlns="urn:ietf:params:xml:ns:caldav"/>`+
+				`</report></supported-report><supported-report xmlns="DAV:"><report>`+
+				`<calendar-multiget xmlns="urn:ietf:params:xml:ns:caldav"/></report></supported-report>`),
+		newDavProperty(davCurrentUserPrivilegeSet,
+			`<privilege xmlns="DAV:"><read/></privilege><privilege xmlns="DAV:"><write/></privilege>`),
+		newDavTextProperty(calendarServerGetCTag, calendarTag(collection, objects)),
+	}
+}
+
+func objectProperties(object domain.CalendarObject, withCalendarData bool) []davProperty {
+	properties := []davProperty{
+		newDavProperty(davResourceType, ""),
+		newDavTextProperty(davGetETag, entityTag(object.Task().Version())),
+		newDavTextProperty(davGetContentType, calDavObjectType),
+	}
+	if withCalendarData {
+		properties = append(properties,
+			newDavTextProperty(calDavCalendarData, string(encodeCalendarObject(object))))
+	}
+
+	return properties
+}
+
+// calendarTag changes whenever the collection is renamed or one of its tasks is created, changed or deleted, so
+// that clients only read the objects again when the calendar has changed
+func calendarTag(collection domain.Collection, objects []domain.CalendarObject) string {
+	hash := fnv.New64a()
+	_, _ = fmt.Fprintf(hash, "%d", collection.Version())
+	for _, object := range objects {
+		_, _ = fmt.Fprintf(hash, ";%d:%d", object.Task().Id(), object.Task().Version())
+	}
+
+	return fmt.Sprintf("%x", hash.Sum64())
+}
+
+func encodeCalendarObject(object domain.CalendarObject) []byte {
+	var output bytes.Buffer
+	encoder := icalendar.NewEncoder(&output)
+	// Writing to a buffer never fails
+	_ = encoder.Begin(object.Task().Collection().Name())
+	_ = encoder.EncodeObject(object)
+	_ = encoder.End()
+
+	return output.Bytes()
+}
+
+// calendarTask is the task of a to-do, placed in the collection of the calendar
+func calendarTask(id, collectionId int, todo domain.Task) *domain.Task {
+	task := domain.NewTask(id, todo.Description(), todo.Finished(), domain.NewCollection(collectionId, ""))
+	task.SetDueDate(todo.DueDate())
+	task.SetTags(todo.Tags())
+
+	return task
+}
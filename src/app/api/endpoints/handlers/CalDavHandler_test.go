@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"todo/src/core/domain"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+type MockCalDavService struct {
+	mock.Mock
+}
+
+func (m *MockCalDavService) FindObjects(collectionId, userId int) ([]domain.CalendarObject, error) {
+	args := m.Called(collectionId, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.CalendarObject), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCalDavService) FindObject(name string, collectionId, userId int) (*domain.CalendarObject, error) {
+	args := m.Called(name, collectionId, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.CalendarObject), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCalDavService) SaveObject(object domain.CalendarObject, userId int) error {
+	args := m.Called(object, userId)
+	return args.Error(0)
+}
+
+func newCalDavContext(method, target, body string, headers map[string]string, names,
+	values []string) (echo.Context, *httptest.ResponseRecorder) {
+	requestData := httptest.NewRequest(method, target, strings.NewReader(body))
+	for name, value := range headers {
+		requestData.Header.Set(name, value)
+	}
+	responseData := httptest.NewRecorder()
+	context := echo.New().NewContext(requestData, responseData)
+	context.SetParamNames(names...)
+	context.SetParamValues(values...)
+
+	return context, responseData
+}
+
+func newCalendarObject(name, uid string, taskId, version int) *domain.CalendarObject {
+	task := domain.NewTask(taskId, "Run", false, domain.NewCollection(3, "Sport"))
+	task.SetVersion(version)
+	return domain.NewCalendarObject(name, uid, task)
+}
+
+func TestCalDav_PropfindCalendar(t *testing.T) {
+	t.Run("should return 207 with the calendar and the ETags of its objects", func(t *testing.T) {
+		body := `<?xml version="1.0"?><d:propfind xmlns:d="DAV:" xmlns:cs="http://calendarserver.org/ns/">` +
+			`<d:prop><d:resourcetype/><d:getetag/><cs:getctag/></d:prop></d:propfind>`
+		context, responseData := newCalDavContext(echo.PROPFIND, "/api/caldav/user/1/3/", body,
+			map[string]string{"Depth": "1"}, []string{"userId", "collectionId"}, []string{"1", "3"})
+		mockService := new(MockCalDavService)
+		mockCollectionService := new(MockCollectionService)
+		calDavHandler := CalDav{service: mockService, collectionService: mockCollectionService}
+		mockCollectionService.On("FindById", 3, 1, *domain.NewExpansion()).Return(domain.NewCollection(3, "Sport"), nil)
+		mockService.On("FindObjects", 3, 1).Return([]domain.CalendarObject{
+			*newCalendarObject("task-4.ics", "", 4, 2),
+			*newCalendarObject("A1 B2.ics", "A1B2", 7, 1),
+		}, nil)
+
+		_ = calDavHandler.PropfindCalendar(context)
+
+		multistatus := responseData.Body.String()
+		assert.Equal(t, http.StatusMultiStatus, responseData.Code)
+		assert.Contains(t, multistatus, `<href xmlns="DAV:">/api/caldav/user/1/3/</href>`)
+		assert.Contains(t, multistatus, `<calendar xmlns="urn:ietf:params:xml:ns:caldav"/>`)
+		assert.Contains(t, multistatus, `<getctag xmlns="http://calendarserver.org/ns/">`)
+		assert.Contains(t, multistatus, `<href xmlns="DAV:">/api/caldav/user/1/3/A1%20B2.ics</href>`)
+		assert.Contains(t, multistatus, `<getetag xmlns="DAV:">&#34;2&#34;</getetag>`)
+	})
+}
+
+func TestCalDav_Report(t *testing.T) {
+	t.Run("should return the calendar data of the hrefs and 404 for the unknown ones", func(t *testing.T) {
+		body := `<c:calendar-multiget xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">` +
+			`<d:prop><d:getetag/><c:calendar-data/></d:prop>` +
+			`<d:href>https://example.com/api/caldav/user/1/3/task-4.ics</d:href>` +
+			`<d:href>/api/caldav/user/1/3/unknown.ics</d:href></c:calendar-multiget>`
+		context, responseData := newCalDavContext(echo.REPORT, "/api/caldav/user/1/3/", body, nil,
+			[]string{"userId", "collectionId"}, []string{"1", "3"})
+		mockService := new(MockCalDavService)
+		calDavHandler := CalDav{service: mockService}
+		mockService.On("FindObjects", 3, 1).Return([]domain.CalendarObject{
+			*newCalendarObject("task-4.ics", "", 4, 2),
+		}, nil)
+
+		_ = calDavHandler.Report(context)
+
+		multistatus := responseData.Body.String()
+		assert.Equal(t, http.StatusMultiStatus, responseData.Code)
+		assert.Contains(t, multistatus, "UID:task-4@todo-rest-api")
+		assert.Contains(t, multistatus, `<href xmlns="DAV:">/api/caldav/user/1/3/unknown.ics</href>`+
+			`<status xmlns="DAV:">HTTP/1.1 404 Not Found</status>`)
+	})
+
+	t.Run("should return no object to a query for events", func(t *testing.T) {
+		body := `<c:calendar-query xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav"><d:prop><d:getetag/>` +
+			`</d:prop><c:filter><c:comp-filter name="VCALENDAR"><c:comp-filter name="VEVENT"/></c:comp-filter>` +
+			`</c:filter></c:calendar-query>`
+		context, responseData := newCalDavContext(echo.REPORT, "/api/caldav/user/1/3/", body, nil,
+			[]string{"userId", "collectionId"}, []string{"1", "3"})
+		mockService := new(MockCalDavService)
+		calDavHandler := CalDav{service: mockService}
+		mockService.On("FindObjects", 3, 1).Return([]domain.CalendarObject{
+			*newCalendarObject("task-4.ics", "", 4, 2),
+		}, nil)
+
+		_ = calDavHandler.Report(context)
+
+		assert.Equal(t, http.StatusMultiStatus, responseData.Code)
+		assert.NotContains(t, responseData.Body.String(), "task-4.ics")
+	})
+}
+
+func TestCalDav_Get(t *testing.T) {
+	t.Run("should return the object with its ETag, and 304 when the client already has it", func(t *testing.T) {
+		mockService := new(MockCalDavService)
+		calDavHandler := CalDav{service: mockService}
+		mockService.On("FindObject", "A1B2.ics", 3, 1).Return(newCalendarObject("A1B2.ics", "A1B2", 7, 5), nil)
+		names, values := []string{"userId", "collectionId", "object"}, []string{"1", "3", "A1B2.ics"}
+
+		context, responseData := newCalDavContext(http.MethodGet, "/api/caldav/user/1/3/A1B2.ics", "", nil,
+			names, values)
+		_ = calDavHandler.Get(context)
+		cachedContext, cachedResponseData := newCalDavContext(http.MethodGet, "/api/caldav/user/1/3/A1B2.ics", "",
+			map[string]string{"If-None-Match": `"5"`}, names, values)
+		_ = calDavHandler.Get(cachedContext)
+
+		assert.Equal(t, http.StatusOK, responseData.Code)
+		assert.Equal(t, `"5"`, responseData.Header().Get("ETag"))
+		assert.Contains(t, responseData.Body.String(), "UID:A1B2\r\n")
+		assert.Equal(t, http.StatusNotModified, cachedResponseData.Code)
+	})
+}
+
+func TestCalDav_Put(t *testing.T) {
+	calendar := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nBEGIN:VTODO\r\nUID:A1B2\r\nSUMMARY:Swim\r\n" +
+		"STATUS:COMPLETED\r\nEND:VTODO\r\nEND:VCALENDAR\r\n"
+	names, values := []string{"userId", "collectionId", "object"}, []string{"1", "3", "A1B2.ics"}
+
+	t.Run("should return 201 and keep the name and UID of a new object", func(t *testing.T) {
+		context, responseData := newCalDavContext(http.MethodPut, "/api/caldav/user/1/3/A1B2.ics", calendar,
+			map[string]string{"If-None-Match": "*"}, names, values)
+		mockService := new(MockCalDavService)
+		mockTaskService := new(MockTaskService)
+		calDavHandler := CalDav{service: mockService, taskService: mockTaskService}
+		mockService.On("FindObject", "A1B2.ics", 3, 1).Return(nil, todoerrors.NewNotFoundError())
+		mockTaskService.On("Create", mock.MatchedBy(func(task domain.Task) bool {
+			return task.Description() == "Swim" && task.Finished() && task.Collection().Id() == 3
+		}), 1).Return(9, nil)
+		mockService.On("SaveObject", mock.MatchedBy(func(object domain.CalendarObject) bool {
+			return object.Name() == "A1B2.ics" && object.Uid() == "A1B2" && object.Task().Id() == 9
+		}), 1).Return(nil)
+
+		_ = calDavHandler.Put(context)
+
+		assert.Equal(t, http.StatusCreated, responseData.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should update the task with the version of the If-Match header", func(t *testing.T) {
+		context, responseData := newCalDavContext(http.MethodPut, "/api/caldav/user/1/3/A1B2.ics", calendar,
+			map[string]string{"If-Match": `"4"`}, names, values)
+		mockService := new(MockCalDavService)
+		mockTaskService := new(MockTaskService)
+		calDavHandler := CalDav{service: mockService, taskService: mockTaskService}
+		mockService.On("FindObject", "A1B2.ics", 3, 1).Return(newCalendarObject("A1B2.ics", "A1B2", 7, 5), nil)
+		mockTaskService.On("Update", mock.MatchedBy(func(task domain.Task) bool {
+			return task.Id() == 7 && task.Version() == 4 && task.Description() == "Swim"
+		}), 1).Return("", todoerrors.NewPreconditionFailedError())
+
+		_ = calDavHandler.Put(context)
+
+		assert.Equal(t, http.StatusPreconditionFailed, responseData.Code)
+	})
+
+	t.Run("should return 412 when the object exists and If-None-Match is *", func(t *testing.T) {
+		context, responseData := newCalDavContext(http.MethodPut, "/api/caldav/user/1/3/A1B2.ics", calendar,
+			map[string]string{"If-None-Match": "*"}, names, values)
+		mockService := new(MockCalDavService)
+		mockTaskService := new(MockTaskService)
+		calDavHandler := CalDav{service: mockService, taskService: mockTaskService}
+		mockService.On("FindObject", "A1B2.ics", 3, 1).Return(newCalendarObject("A1B2.ics", "A1B2", 7, 5), nil)
+
+		_ = calDavHandler.Put(context)
+
+		assert.Equal(t, http.StatusPreconditionFailed, responseData.Code)
+		mockTaskService.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should store a recurring to-do as its first occurrence", func(t *testing.T) {
+		recurring := strings.Replace(calendar, "STATUS:COMPLETED", "RRULE:FREQ=DAILY", 1)
+		context, responseData := newCalDavContext(http.MethodPut, "/api/caldav/user/1/3/A1B2.ics", recurring,
+			map[string]string{}, names, values)
+		mockService := new(MockCalDavService)
+		mockTaskService := new(MockTaskService)
+		calDavHandler := CalDav{service: mockService, taskService: mockTaskService}
+		mockService.On("FindObject", "A1B2.ics", 3, 1).Return(nil, todoerrors.NewNotFoundError())
+		mockTaskService.On("Create", mock.MatchedBy(func(task domain.Task) bool {
+			return task.Description() == "Swim" && !task.Finished()
+		}), 1).Return(9, nil)
+		mockService.On("SaveObject", mock.Anything, 1).Return(nil)
+
+		_ = calDavHandler.Put(context)
+
+		assert.Equal(t, http.StatusCreated, responseData.Code)
+		mockTaskService.AssertExpectations(t)
+	})
+}
+
+func TestCalDav_Delete(t *testing.T) {
+	t.Run("should delete the task of the object", func(t *testing.T) {
+		context, responseData := newCalDavContext(http.MethodDelete, "/api/caldav/user/1/3/task-4.ics", "", nil,
+			[]string{"userId", "collectionId", "object"}, []string{"1", "3", "task-4.ics"})
+		mockService := new(MockCalDavService)
+		mockTaskService := new(MockTaskService)
+		calDavHandler := CalDav{service: mockService, taskService: mockTaskService}
+		mockService.On("FindObject", "task-4.ics", 3, 1).Return(newCalendarObject("task-4.ics", "", 4, 2), nil)
+		mockTaskService.On("Delete", 4, 1, 0).Return("", nil)
+
+		_ = calDavHandler.Delete(context)
+
+		assert.Equal(t, http.StatusNoContent, responseData.Code)
+		mockTaskService.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,264 @@
+package handlers
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+	"net/http"
+	"todo/src/app/api/endpoints/dto/response"
+	"todo/src/app/api/endpoints/handlers/msgs"
+	"todo/src/core/domain"
+	"todo/src/core/icalendar"
+	interfaces "todo/src/core/interfaces/services"
+	"todo/src/core/projecterrors/todoerrors"
+	"todo/src/core/services"
+	"todo/src/infra/postgres"
+)
+
+const (
+	mimeTextCalendar   = "text/calendar"
+	calendarFileName   = "tasks.ics"
+	calendarFeedPrefix = "/api/calendar/"
+)
+
+type Calendar struct {
+	service interfaces.ICalendar
+}
+
+func NewCalendarHandler() *Calendar {
+	connectionManager := postgres.NewPostgresConnectionManager()
+	repository := postgres.NewCalendarPostgresRepository(connectionManager)
+	service := services.NewCalendarService(repository)
+	return &Calendar{service}
+}
+
+// CreateFeed
+// @ID 			CreateCalendarFeed
+// @Summary		Create the calendar feed URL
+// @Tags 		Calendar
+// @Description Route that creates the secret URL of the iCalendar feed with the tasks of the user, which calendar apps can subscribe to without the bearer token. Appending /collection/{collectionId} before /tasks.ics limits the feed to a collection. Creating it again replaces the previous URL, which stops working.
+// @Produce 	json
+// @Security	bearerAuth
+// @Param 	    userId       path       int                  true                  "User ID"    default(1)
+// @Success 	201 		 {object} 	response.SwaggerCalendarFeedResponse       "Calendar feed successfully created"
+// @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403          {object}   response.SwaggerForbiddenResponse 	       "The user does not have access to this information"
+// @Failure 	422 		 {object} 	response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Failure 	500 		 {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/user/{userId}/calendar/feed  [post]
+func (h Calendar) CreateFeed(ctx echo.Context) error {
+	userId, err := convertToPositiveInteger(ctx.Param("userId"), msgs.UserId)
+	if err != nil {
+		log.Error(err)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.UserId, msgs.ConversionError)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
+	}
+
+	token, err := h.service.CreateFeedToken(userId)
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	return writeCreatedResponse(ctx, response.NewCalendarFeed(token, calendarFeedUrl(ctx, token)))
+}
+
+// FindFeed
+// @ID 			FindCalendarFeed
+// @Summary		Get the calendar feed URL
+// @Tags 		Calendar
+// @Description Route that returns the secret URL of the iCalendar feed with the tasks of the user.
+// @Produce 	json
+// @Security	bearerAuth
+// @Param 	    userId       path       int                  true                  "User ID"    default(1)
+// @Success 	200 		 {object} 	response.SwaggerCalendarFeedResponse       "Successful request"
+// @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403          {object}   response.SwaggerForbiddenResponse 	       "The user does not have access to this information"
+// @Failure 	404 		 {object} 	response.SwaggerNotFoundErrorResponse 	   "The user has not created a calendar feed"
+// @Failure 	422 		 {object} 	response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Failure 	500 		 {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/user/{userId}/calendar/feed  [get]
+func (h Calendar) FindFeed(ctx echo.Context) error {
+	userId, err := convertToPositiveInteger(ctx.Param("userId"), msgs.UserId)
+	if err != nil {
+		log.Error(err)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.UserId, msgs.ConversionError)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
+	}
+
+	token, err := h.service.FindFeedToken(userId)
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, response.NewCalendarFeed(token, calendarFeedUrl(ctx, token)))
+}
+
+// DeleteFeed
+// @ID 			DeleteCalendarFeed
+// @Summary		Delete the calendar feed URL
+// @Tags 		Calendar
+// @Description Route that revokes the secret URL of the iCalendar feed, which stops working for every calendar app subscribed to it.
+// @Security	bearerAuth
+// @Param 	    userId       path       int                  true                  "User ID"    default(1)
+// @Success 	204 		 {object} 	nil                                        "Calendar feed successfully deleted"
+// @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403          {object}   response.SwaggerForbiddenResponse 	       "The user does not have access to this information"
+// @Failure 	404 		 {object} 	response.SwaggerNotFoundErrorResponse 	   "The user has not created a calendar feed"
+// @Failure 	422 		 {object} 	response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Failure 	500 		 {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/user/{userId}/calendar/feed  [delete]
+func (h Calendar) DeleteFeed(ctx echo.Context) error {
+	userId, err := convertToPositiveInteger(ctx.Param("userId"), msgs.UserId)
+	if err != nil {
+		log.Error(err)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.UserId, msgs.ConversionError)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
+	}
+
+	err = h.service.DeleteFeedToken(userId)
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	return writeNoContentResponse(ctx)
+}
+
+// Feed
+// @ID 			CalendarFeed
+// @Summary		Calendar feed of the tasks
+// @Tags 		Calendar
+// @Description Route that streams the tasks of the user as an RFC 5545 iCalendar with a VTODO per task. It is authenticated by the secret token of the URL instead of the bearer token, so that calendar apps can subscribe to it.
+// @Produce 	text/calendar
+// @Param 	    token        path       string               true                  "Calendar feed token"
+// @Success 	200 		 {string} 	string                                     "Successful request"
+// @Failure 	404 		 {object} 	response.SwaggerNotFoundErrorResponse 	   "The calendar feed does not exist"
+// @Failure 	500 		 {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/calendar/{token}/tasks.ics  [get]
+func (h Calendar) Feed(ctx echo.Context) error {
+	return h.writeFeed(ctx, 0)
+}
+
+// CollectionFeed
+// @ID 			CollectionCalendarFeed
+// @Summary		Calendar feed of the tasks of a collection
+// @Tags 		Calendar
+// @Description Route that streams the tasks of a collection of the user as an RFC 5545 iCalendar with a VTODO per task. It is authenticated by the secret token of the URL instead of the bearer token, so that calendar apps can subscribe to it.
+// @Produce 	text/calendar
+// @Param 	    token        path       string               true                  "Calendar feed token"
+// @Param 	    collectionId path       int                  true                  "Collection ID"    default(1)
+// @Success 	200 		 {string} 	string                                     "Successful request"
+// @Failure 	404 		 {object} 	response.SwaggerNotFoundErrorResponse 	   "The calendar feed or the collection does not exist"
+// @Failure 	422 		 {object} 	response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Failure 	500 		 {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/calendar/{token}/collection/{collectionId}/tasks.ics  [get]
+func (h Calendar) CollectionFeed(ctx echo.Context) error {
+	collectionId, err := convertToPositiveInteger(ctx.Param("collectionId"), msgs.CollectionId)
+	if err != nil {
+		log.Error(err)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.CollectionId, msgs.ConversionError)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
+	}
+
+	return h.writeFeed(ctx, collectionId)
+}
+
+func (h Calendar) writeFeed(ctx echo.Context, collectionId int) error {
+	feed, err := h.service.FindFeed(ctx.Param("token"), collectionId)
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	stream := newExportStream(ctx, mimeTextCalendar+"; charset=utf-8", calendarFileName)
+	stream.start("")
+	encoder := icalendar.NewEncoder(ctx.Response())
+	if err = encoder.Begin(feed.Name()); err != nil {
+		log.Error(err)
+		return nil
+	}
+	err = h.service.ExportTasks(*feed, func(task domain.Task) error {
+		return encoder.Encode(task)
+	})
+	if err != nil {
+		// the calendar is left unterminated, so that clients do not take a partial feed as complete
+		log.Error(err)
+		return nil
+	}
+	if err = encoder.End(); err != nil {
+		log.Error(err)
+	}
+
+	return nil
+}
+
+// Import
+// @ID 			ImportCalendar
+// @Summary		Import the to-dos of an iCalendar file
+// @Tags 		Calendar
+// @Description Route that creates a task for each VTODO of an .ics file, sent as text/calendar or as multipart/form-data in the file field. The SUMMARY becomes the description, a COMPLETED status finishes the task, and DUE and CATEGORIES become the due date and tags. Other components are ignored. Recurring to-dos, with an RRULE, are imported as their first occurrence, as the tasks have no recurrence, and listed in the warnings of the response. Every to-do is validated first and nothing is imported if any of them is invalid.
+// @Accept 		text/calendar
+// @Accept 		mpfd
+// @Produce 	json
+// @Security	bearerAuth
+// @Param 	    userId        path       int                 true                  "User ID"    default(1)
+// @Param 	    collection_id query      int                 false                 "ID of the collection of the imported tasks, which go to the Imported collection otherwise"
+// @Param 		file 	      formData 	 file                false                 "iCalendar file"
+// @Success 	201 		 {object} 	response.SwaggerTaskImportResponse         "Tasks successfully imported"
+// @Failure 	400 		 {object} 	response.SwaggerBadRequestResponse         "The user has made a bad request"
+// @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403          {object}   response.SwaggerForbiddenResponse 	       "The user does not have access to this information"
+// @Failure 	415 		 {object} 	response.SwaggerGenericErrorResponse       "The format of the file is not supported"
+// @Failure 	422 		 {object} 	response.SwaggerValidationErrorResponse    "Some to-dos could not be imported because they are not valid"
+// @Failure 	500 		 {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/user/{userId}/calendar/import  [post]
+func (h Calendar) Import(ctx echo.Context) error {
+	invalidFields := todoerrors.InvalidFields{}
+	userId, err := convertToPositiveInteger(ctx.Param("userId"), msgs.UserId)
+	if err != nil {
+		log.Error(err)
+		invalidFields.AppendField(msgs.UserId, msgs.ConversionError)
+	}
+	collectionId := 0
+	if value := ctx.QueryParam("collection_id"); value != "" {
+		collectionId, err = convertToPositiveInteger(value, msgs.CollectionId)
+		if err != nil {
+			log.Error(err)
+			invalidFields.AppendField(msgs.CollectionId, msgs.ConversionError)
+		}
+	}
+	if invalidFields.HasInvalidFields() {
+		return writeValidationError(ctx, *todoerrors.NewValidationError(msgs.InvalidQueryParams, invalidFields))
+	}
+
+	file, err := openUploadedFile(ctx, mimeTextCalendar)
+	if err != nil {
+		log.Error(err)
+		return writeUploadError(ctx, err, mimeTextCalendar)
+	}
+	defer file.Close()
+
+	tasks, warnings, validationErr := icalendar.Decode(file)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+
+	ids, err := h.service.Import(tasks, collectionId, userId)
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	return writeCreatedResponse(ctx, response.NewTaskImportResultWithWarnings(ids, warnings))
+}
+
+// calendarFeedUrl is absolute, since it is pasted as is into the calendar apps
+func calendarFeedUrl(ctx echo.Context, token string) string {
+	return ctx.Scheme() + "://" + ctx.Request().Host + calendarFeedPrefix + token + "/" + calendarFileName
+}
@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"bytes"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"todo/src/core/domain"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+type MockCalendarService struct {
+	mock.Mock
+}
+
+func (m *MockCalendarService) CreateFeedToken(userId int) (string, error) {
+	args := m.Called(userId)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockCalendarService) FindFeedToken(userId int) (string, error) {
+	args := m.Called(userId)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockCalendarService) DeleteFeedToken(userId int) error {
+	args := m.Called(userId)
+	return args.Error(0)
+}
+
+func (m *MockCalendarService) FindFeed(token string, collectionId int) (*domain.CalendarFeed, error) {
+	args := m.Called(token, collectionId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.CalendarFeed), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCalendarService) ExportTasks(feed domain.CalendarFeed, write func(task domain.Task) error) error {
+	args := m.Called(feed)
+	if tasks, ok := args.Get(0).([]domain.Task); ok {
+		for _, task := range tasks {
+			if err := write(task); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockCalendarService) Import(tasks []domain.Task, collectionId, userId int) ([]int, error) {
+	args := m.Called(tasks, collectionId, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).([]int), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func newCalendarContext(method, target, contentType string, body *bytes.Buffer, names, values []string) (echo.Context,
+	*httptest.ResponseRecorder) {
+	if body == nil {
+		body = &bytes.Buffer{}
+	}
+	requestData := httptest.NewRequest(method, target, body)
+	if contentType != "" {
+		requestData.Header.Set("Content-Type", contentType)
+	}
+	responseData := httptest.NewRecorder()
+	context := echo.New().NewContext(requestData, responseData)
+	context.SetParamNames(names...)
+	context.SetParamValues(values...)
+
+	return context, responseData
+}
+
+func TestCalendar_CreateFeed(t *testing.T) {
+	t.Run("should return 201 with the absolute URL of the feed", func(t *testing.T) {
+		context, responseData := newCalendarContext(http.MethodPost, "/user/1/calendar/feed", "", nil,
+			[]string{"userId"}, []string{"1"})
+		mockService := new(MockCalendarService)
+		calendarHandler := Calendar{service: mockService}
+		mockService.On("CreateFeedToken", 1).Return("abc123", nil)
+
+		_ = calendarHandler.CreateFeed(context)
+
+		assert.Equal(t, http.StatusCreated, responseData.Code)
+		assert.Equal(t, "{\"token\":\"abc123\",\"url\":\"http://example.com/api/calendar/abc123/tasks.ics\"}\n",
+			responseData.Body.String())
+	})
+}
+
+func TestCalendar_FindFeed(t *testing.T) {
+	t.Run("should return 404 when the user has not created a feed", func(t *testing.T) {
+		context, responseData := newCalendarContext(http.MethodGet, "/user/1/calendar/feed", "", nil,
+			[]string{"userId"}, []string{"1"})
+		mockService := new(MockCalendarService)
+		calendarHandler := Calendar{service: mockService}
+		mockService.On("FindFeedToken", 1).Return("", todoerrors.NewNotFoundError())
+
+		_ = calendarHandler.FindFeed(context)
+
+		assert.Equal(t, http.StatusNotFound, responseData.Code)
+	})
+}
+
+func TestCalendar_Feed(t *testing.T) {
+	t.Run("should stream the tasks of the collection as VTODO components", func(t *testing.T) {
+		context, responseData := newCalendarContext(http.MethodGet, "/calendar/abc123/collection/3/tasks.ics", "",
+			nil, []string{"token", "collectionId"}, []string{"abc123", "3"})
+		mockService := new(MockCalendarService)
+		calendarHandler := Calendar{service: mockService}
+		feed := domain.NewCalendarFeed("abc123", 1, domain.NewCollection(3, "Sport"))
+		mockService.On("FindFeed", "abc123", 3).Return(feed, nil)
+		mockService.On("ExportTasks", *feed).Return([]domain.Task{
+			*domain.NewTask(4, "Run", false, domain.NewCollection(3, "Sport")),
+		}, nil)
+
+		_ = calendarHandler.CollectionFeed(context)
+
+		calendar := responseData.Body.String()
+		assert.Equal(t, http.StatusOK, responseData.Code)
+		assert.Equal(t, "text/calendar; charset=utf-8", responseData.Header().Get("Content-Type"))
+		assert.Contains(t, calendar, "X-WR-CALNAME:Sport\r\n")
+		assert.Contains(t, calendar, "UID:task-4@todo-rest-api\r\n")
+		assert.True(t, strings.HasSuffix(calendar, "END:VCALENDAR\r\n"))
+	})
+
+	t.Run("should return 404 when the token does not exist", func(t *testing.T) {
+		context, responseData := newCalendarContext(http.MethodGet, "/calendar/unknown/tasks.ics", "", nil,
+			[]string{"token"}, []string{"unknown"})
+		mockService := new(MockCalendarService)
+		calendarHandler := Calendar{service: mockService}
+		mockService.On("FindFeed", "unknown", 0).Return(nil, todoerrors.NewNotFoundError())
+
+		_ = calendarHandler.Feed(context)
+
+		assert.Equal(t, http.StatusNotFound, responseData.Code)
+		mockService.AssertNotCalled(t, "ExportTasks", mock.Anything)
+	})
+}
+
+func TestCalendar_Import(t *testing.T) {
+	calendar := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nBEGIN:VTODO\r\nSUMMARY:Run\r\nEND:VTODO\r\n" +
+		"BEGIN:VTODO\r\nSUMMARY:Rest\r\nSTATUS:COMPLETED\r\nEND:VTODO\r\nEND:VCALENDAR\r\n"
+
+	t.Run("should return 201 with the IDs of the tasks of an .ics body", func(t *testing.T) {
+		context, responseData := newCalendarContext(http.MethodPost, "/user/1/calendar/import?collection_id=3",
+			"text/calendar", bytes.NewBufferString(calendar), []string{"userId"}, []string{"1"})
+		mockService := new(MockCalendarService)
+		calendarHandler := Calendar{service: mockService}
+		mockService.On("Import", mock.MatchedBy(func(tasks []domain.Task) bool {
+			return len(tasks) == 2 && tasks[0].Description() == "Run" && tasks[1].Finished()
+		}), 3, 1).Return([]int{10, 11}, nil)
+
+		_ = calendarHandler.Import(context)
+
+		assert.Equal(t, http.StatusCreated, responseData.Code)
+		assert.Equal(t, "{\"ids\":[10,11]}\n", responseData.Body.String())
+	})
+
+	t.Run("should return 201 with a warning for each recurring to-do", func(t *testing.T) {
+		recurring := strings.Replace(calendar, "SUMMARY:Run\r\n", "SUMMARY:Run\r\nRRULE:FREQ=DAILY\r\n", 1)
+		context, responseData := newCalendarContext(http.MethodPost, "/user/1/calendar/import?collection_id=3",
+			"text/calendar", bytes.NewBufferString(recurring), []string{"userId"}, []string{"1"})
+		mockService := new(MockCalendarService)
+		calendarHandler := Calendar{service: mockService}
+		mockService.On("Import", mock.Anything, 3, 1).Return([]int{10, 11}, nil)
+
+		_ = calendarHandler.Import(context)
+
+		assert.Equal(t, http.StatusCreated, responseData.Code)
+		assert.Equal(t, "{\"ids\":[10,11],\"warnings\":[\"To-do 1: Only the first occurrence has been imported, "+
+			"as the tasks have no recurrence.\"]}\n", responseData.Body.String())
+	})
+
+	t.Run("should return 201 when the file is sent as multipart/form-data", func(t *testing.T) {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		file, _ := writer.CreateFormFile("file", "tasks.ics")
+		_, _ = file.Write([]byte(calendar))
+		_ = writer.Close()
+		context, responseData := newCalendarContext(http.MethodPost, "/user/1/calendar/import",
+			writer.FormDataContentType(), body, []string{"userId"}, []string{"1"})
+		mockService := new(MockCalendarService)
+		calendarHandler := Calendar{service: mockService}
+		mockService.On("Import", mock.Anything, 0, 1).Return([]int{10, 11}, nil)
+
+		_ = calendarHandler.Import(context)
+
+		assert.Equal(t, http.StatusCreated, responseData.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return 422 without importing when a to-do is invalid", func(t *testing.T) {
+		invalidCalendar := "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nDUE:tomorrow\r\nEND:VTODO\r\nEND:VCALENDAR\r\n"
+		context, responseData := newCalendarContext(http.MethodPost, "/user/1/calendar/import", "text/calendar",
+			bytes.NewBufferString(invalidCalendar), []string{"userId"}, []string{"1"})
+		mockService := new(MockCalendarService)
+		calendarHandler := Calendar{service: mockService}
+
+		_ = calendarHandler.Import(context)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, responseData.Code)
+		assert.Contains(t, responseData.Body.String(), "To-do 1")
+		mockService.AssertNotCalled(t, "Import", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return 415 when the format is not supported", func(t *testing.T) {
+		context, responseData := newCalendarContext(http.MethodPost, "/user/1/calendar/import", "application/json",
+			bytes.NewBufferString("{}"), []string{"userId"}, []string{"1"})
+		calendarHandler := Calendar{service: new(MockCalendarService)}
+
+		_ = calendarHandler.Import(context)
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, responseData.Code)
+	})
+}
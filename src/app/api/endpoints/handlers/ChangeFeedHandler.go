@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+	"golang.org/x/net/websocket"
+	"net/http"
+	"strconv"
+	"time"
+	"todo/src/app/api/endpoints/dto/response"
+	"todo/src/app/api/endpoints/handlers/msgs"
+	"todo/src/core/domain"
+	interfaces "todo/src/core/interfaces/changefeed"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+const (
+	mimeTextEventStream = "text/event-stream"
+	headerLastEventId   = "Last-Event-ID"
+	heartbeatInterval   = 25 * time.Second
+)
+
+// ChangeFeed streams the changes made to the tasks and collections of the user, so that clients don't have to poll
+type ChangeFeed struct {
+	feed interfaces.IChangeFeed
+}
+
+func NewChangeFeedHandler(feed interfaces.IChangeFeed) *ChangeFeed {
+	return &ChangeFeed{feed}
+}
+
+// CreateToken
+// @ID 			CreateChangeFeedToken
+// @Summary		Create a change feed token
+// @Tags 		Events
+// @Description Route that issues a token valid for one minute, which opens the change feeds of the user in the access_token parameter instead of the Authorization header, for the clients that can't set it, such as the EventSource and WebSocket APIs of the browsers. The token opens nothing else.
+// @Produce 	json
+// @Security	bearerAuth
+// @Param 	    userId       path       int                  true                  "User ID"    default(1)
+// @Success 	201 		 {object} 	response.SwaggerChangeFeedTokenResponse    "Token successfully created"
+// @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403          {object}   response.SwaggerForbiddenResponse 	       "The user does not have access to this information"
+// @Failure 	422 		 {object} 	response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Failure 	500 		 {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/user/{userId}/events/token  [post]
+func (h ChangeFeed) CreateToken(ctx echo.Context) error {
+	userId, err := convertToPositiveInteger(ctx.Param("userId"), msgs.UserId)
+	if err != nil {
+		log.Error(err)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.UserId, msgs.ConversionError)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
+	}
+
+	token, expiresAt, err := domain.NewAccount(userId, "", "", "", "").GenerateChangeFeedToken()
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, todoerrors.NewUnexpectedInternalError(msgs.TokenGenerationError))
+	}
+
+	return writeCreatedResponse(ctx, response.NewChangeFeedToken(token, expiresAt))
+}
+
+// Stream
+// @ID 			StreamChanges
+// @Summary		Stream the changes as Server-Sent Events
+// @Tags 		Events
+// @Description Route that streams the changes made to the tasks and collections of the user as Server-Sent Events, named by their type, such as task.created, collection.updated or task.deleted. The data of each event has the ID and the version of the entity, which can then be read again. The changes made through every instance of the API are streamed, a second or so after they have been made. A client that reconnects with the Last-Event-ID header, or the last_event_id parameter, first receives the events it has missed, which are kept for 7 days, and may receive an event twice. When its last event is no longer kept, or it has missed more than 1000 events, it receives a resync event instead, telling it to read the tasks and collections again. A comment is sent every 25 seconds to keep the connection open.
+// @Produce 	text/event-stream
+// @Security	bearerAuth
+// @Param 	    userId           path       int                  true               "User ID"    default(1)
+// @Param 	    Last-Event-ID    header     int                  false              "ID of the last event received"
+// @Param 	    last_event_id    query      int                  false              "ID of the last event received, for the clients that can't set the header"
+// @Param 	    access_token     query      string               false              "Change feed token, for the clients that can't set the Authorization header"
+// @Success 	200 		 {object} 	response.ChangeEvent                       "Stream of change events"
+// @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403          {object}   response.SwaggerForbiddenResponse 	       "The user does not have access to this information"
+// @Failure 	422 		 {object} 	response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Router 		/user/{userId}/events  [get]
+func (h ChangeFeed) Stream(ctx echo.Context) error {
+	userId, lastEventId, validationErr := parseChangeFeedParams(ctx)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+
+	missedEvents, subscription, err := h.feed.Subscribe(userId, lastEventId)
+	if err != nil && !errors.Is(err, domain.ErrChangeFeedResync) {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+	defer subscription.Close()
+
+	header := ctx.Response().Header()
+	header.Set(echo.HeaderContentType, mimeTextEventStream)
+	header.Set("Cache-Control", "no-cache")
+	header.Set("X-Accel-Buffering", "no")
+	ctx.Response().WriteHeader(http.StatusOK)
+	if err != nil {
+		if _, err = fmt.Fprintf(ctx.Response(), "event: %s\ndata: {\"type\":%q}\n\n", response.ChangeFeedResyncType,
+			response.ChangeFeedResyncType); err != nil {
+			log.Error(err)
+			return nil
+		}
+	}
+	for _, event := range missedEvents {
+		if err := writeServerSentEvent(ctx, event); err != nil {
+			log.Error(err)
+			return nil
+		}
+	}
+	ctx.Response().Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Request().Context().Done():
+			return nil
+		case event, ok := <-subscription.Events():
+			if !ok {
+				return nil
+			}
+			if err := writeServerSentEvent(ctx, event); err != nil {
+				log.Error(err)
+				return nil
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(ctx.Response(), ": heartbeat\n\n"); err != nil {
+				log.Error(err)
+				return nil
+			}
+		}
+		ctx.Response().Flush()
+	}
+}
+
+// WebSocket
+// @ID 			StreamChangesWebSocket
+// @Summary		Stream the changes over a WebSocket
+// @Tags 		Events
+// @Description Route that upgrades the connection to a WebSocket, on which the changes made to the tasks and collections of the user are sent as JSON text messages, with the same fields as the Server-Sent Events. A client that reconnects with the last_event_id parameter first receives the events it has missed, which are kept for 7 days, and may receive an event twice. When its last event is no longer kept, or it has missed more than 1000 events, it receives a message of type resync instead, telling it to read the tasks and collections again. The messages sent by the client are ignored.
+// @Security	bearerAuth
+// @Param 	    userId           path       int                  true               "User ID"    default(1)
+// @Param 	    last_event_id    query      int                  false              "ID of the last event received"
+// @Param 	    access_token     query      string               false              "Change feed token, for the clients that can't set the Authorization header"
+// @Success 	101 		 {object} 	response.ChangeEvent                       "Switching to the WebSocket protocol"
+// @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403          {object}   response.SwaggerForbiddenResponse 	       "The user does not have access to this information"
+// @Failure 	422 		 {object} 	response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Router 		/user/{userId}/events/ws  [get]
+func (h ChangeFeed) WebSocket(ctx echo.Context) error {
+	userId, lastEventId, validationErr := parseChangeFeedParams(ctx)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+
+	missedEvents, subscription, err := h.feed.Subscribe(userId, lastEventId)
+	if err != nil && !errors.Is(err, domain.ErrChangeFeedResync) {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+	resync := err != nil
+	defer subscription.Close()
+
+	// The origin is not checked, as the connection is authorized by a token rather than by cookies
+	server := websocket.Server{Handler: func(connection *websocket.Conn) {
+		defer connection.Close()
+
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			var message []byte
+			for websocket.Message.Receive(connection, &message) == nil {
+			}
+		}()
+
+		if resync {
+			if err := websocket.JSON.Send(connection, response.NewChangeFeedResync()); err != nil {
+				log.Error(err)
+				return
+			}
+		}
+		for _, event := range missedEvents {
+			if err := websocket.JSON.Send(connection, response.NewChangeEvent(event)); err != nil {
+				log.Error(err)
+				return
+			}
+		}
+		for {
+			select {
+			case <-closed:
+				return
+			case event, ok := <-subscription.Events():
+				if !ok {
+					return
+				}
+				if err := websocket.JSON.Send(connection, response.NewChangeEvent(event)); err != nil {
+					log.Error(err)
+					return
+				}
+			}
+		}
+	}}
+	server.ServeHTTP(ctx.Response(), ctx.Request())
+	return nil
+}
+
+func parseChangeFeedParams(ctx echo.Context) (int, int64, *todoerrors.Validation) {
+	userId, err := convertToPositiveInteger(ctx.Param("userId"), msgs.UserId)
+	if err != nil {
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.UserId, msgs.ConversionError)
+		return 0, 0, todoerrors.NewValidationError(err.Error(), invalidFields)
+	}
+
+	value := ctx.Request().Header.Get(headerLastEventId)
+	if value == "" {
+		value = ctx.QueryParam("last_event_id")
+	}
+	if value == "" {
+		return userId, 0, nil
+	}
+	lastEventId, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || lastEventId < 0 {
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.LastEventId, msgs.ConversionError)
+		return 0, 0, todoerrors.NewValidationError(msgs.InvalidQueryParams, invalidFields)
+	}
+
+	return userId, lastEventId, nil
+}
+
+func writeServerSentEvent(ctx echo.Context, event domain.ChangeEvent) error {
+	data, err := json.Marshal(response.NewChangeEvent(event))
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(ctx.Response(), "id: %d\nevent: %s\ndata: %s\n\n", event.Id(), event.Type(), data)
+	return err
+}
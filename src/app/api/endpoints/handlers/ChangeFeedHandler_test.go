@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/websocket"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"todo/src/app/api/endpoints/dto/response"
+	"todo/src/core/domain"
+	interfaces "todo/src/core/interfaces/changefeed"
+)
+
+// stubFeed returns its missed events and hands the subscription of the handler to the test, which sends it the new
+// events and closes it to end the stream
+type stubFeed struct {
+	missedEvents  []domain.ChangeEvent
+	err           error
+	lastEventIds  chan int64
+	subscriptions chan *stubSubscription
+}
+
+func newStubFeed(missedEvents []domain.ChangeEvent, err error) stubFeed {
+	return stubFeed{missedEvents, err, make(chan int64, 1), make(chan *stubSubscription, 1)}
+}
+
+func (f stubFeed) Subscribe(_ int, lastEventId int64) ([]domain.ChangeEvent, interfaces.ISubscription, error) {
+	subscription := &stubSubscription{make(chan domain.ChangeEvent, 8)}
+	f.lastEventIds <- lastEventId
+	f.subscriptions <- subscription
+	return f.missedEvents, subscription, f.err
+}
+
+type stubSubscription struct {
+	events chan domain.ChangeEvent
+}
+
+func (s *stubSubscription) Events() <-chan domain.ChangeEvent {
+	return s.events
+}
+
+func (s *stubSubscription) Close() {}
+
+func newChangeEvent(id int64, entity, action string, entityId, version int) domain.ChangeEvent {
+	event := domain.NewChangeEvent(1, entity, action, entityId, version)
+	event.SetId(id)
+	return *event
+}
+
+func streamChanges(t *testing.T, feed stubFeed, lastEventId string, newEvents ...domain.ChangeEvent) string {
+	requestData := httptest.NewRequest(http.MethodGet, "/events", nil)
+	requestData.Header.Set(headerLastEventId, lastEventId)
+	responseData := httptest.NewRecorder()
+	echoContext := echo.New().NewContext(requestData, responseData)
+	echoContext.SetParamNames("userId")
+	echoContext.SetParamValues("1")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = ChangeFeed{feed}.Stream(echoContext)
+	}()
+	subscription := <-feed.subscriptions
+	for _, event := range newEvents {
+		subscription.events <- event
+	}
+	close(subscription.events)
+	<-done
+
+	assert.Equal(t, http.StatusOK, responseData.Code)
+	assert.Equal(t, mimeTextEventStream, responseData.Header().Get(echo.HeaderContentType))
+	return responseData.Body.String()
+}
+
+func TestChangeFeed_Stream(t *testing.T) {
+	t.Run("should send the missed events and then the new ones of the user", func(t *testing.T) {
+		feed := newStubFeed([]domain.ChangeEvent{
+			newChangeEvent(12, domain.ChangeEntityTask, domain.ChangeActionUpdated, 5, 2),
+		}, nil)
+
+		body := streamChanges(t, feed, "11",
+			newChangeEvent(14, domain.ChangeEntityCollection, domain.ChangeActionDeleted, 3, 0))
+
+		assert.Equal(t, int64(11), <-feed.lastEventIds)
+		assert.Equal(t, 2, strings.Count(body, "id: "))
+		assert.Contains(t, body, "id: 12\nevent: task.updated\ndata: {")
+		assert.Contains(t, body, `"entity":"collection","entity_id":3`)
+		assert.Less(t, strings.Index(body, "task.updated"), strings.Index(body, "collection.deleted"))
+	})
+
+	t.Run("should tell the client to resync when its missed events are no longer kept", func(t *testing.T) {
+		feed := newStubFeed(nil, domain.ErrChangeFeedResync)
+
+		body := streamChanges(t, feed, "3",
+			newChangeEvent(14, domain.ChangeEntityTask, domain.ChangeActionCreated, 6, 1))
+
+		assert.True(t, strings.HasPrefix(body, "event: resync\ndata: {\"type\":\"resync\"}\n\n"))
+		assert.Contains(t, body, "id: 14\nevent: task.created")
+	})
+
+	t.Run("should return 422 when the last event ID is not a number", func(t *testing.T) {
+		requestData := httptest.NewRequest(http.MethodGet, "/events?last_event_id=abc", nil)
+		responseData := httptest.NewRecorder()
+		echoContext := echo.New().NewContext(requestData, responseData)
+		echoContext.SetParamNames("userId")
+		echoContext.SetParamValues("1")
+		changeFeedHandler := ChangeFeed{newStubFeed(nil, nil)}
+
+		_ = changeFeedHandler.Stream(echoContext)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, responseData.Code)
+	})
+}
+
+func TestChangeFeed_WebSocket(t *testing.T) {
+	t.Run("should send the events of the user as JSON messages", func(t *testing.T) {
+		feed := newStubFeed(nil, nil)
+		changeFeedHandler := ChangeFeed{feed}
+		router := echo.New()
+		router.GET("/user/:userId/events/ws", changeFeedHandler.WebSocket)
+		server := httptest.NewServer(router)
+		defer server.Close()
+
+		url := "ws" + strings.TrimPrefix(server.URL, "http") + "/user/1/events/ws"
+		connection, err := websocket.Dial(url, "", server.URL)
+		assert.Nil(t, err)
+		defer connection.Close()
+		subscription := <-feed.subscriptions
+		subscription.events <- newChangeEvent(9, domain.ChangeEntityTask, domain.ChangeActionDeleted, 5, 0)
+
+		var event response.ChangeEvent
+		_ = connection.SetReadDeadline(time.Now().Add(time.Second))
+		err = websocket.JSON.Receive(connection, &event)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "task.deleted", event.Type)
+		assert.Equal(t, 5, event.EntityId)
+		assert.Equal(t, int64(9), event.Id)
+	})
+}
+
+func TestChangeFeed_CreateToken(t *testing.T) {
+	t.Run("should issue a token that opens only the change feed of the user", func(t *testing.T) {
+		t.Setenv("SERVER_SECRET", "secret")
+		requestData := httptest.NewRequest(http.MethodPost, "/events/token", nil)
+		responseData := httptest.NewRecorder()
+		echoContext := echo.New().NewContext(requestData, responseData)
+		echoContext.SetParamNames("userId")
+		echoContext.SetParamValues("7")
+
+		_ = ChangeFeed{newStubFeed(nil, nil)}.CreateToken(echoContext)
+
+		assert.Equal(t, http.StatusCreated, responseData.Code)
+		var token response.ChangeFeedToken
+		assert.NoError(t, json.Unmarshal(responseData.Body.Bytes(), &token))
+		account, err := domain.NewAccountFromChangeFeedToken(token.Token)
+		assert.NoError(t, err)
+		assert.Equal(t, 7, account.Id())
+		assert.WithinDuration(t, time.Now().Add(domain.ChangeFeedTokenLifetime), token.ExpiresAt, 5*time.Second)
+		_, err = domain.NewAccountFromToken(token.Token)
+		assert.Error(t, err)
+	})
+}
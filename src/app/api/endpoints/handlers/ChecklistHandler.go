@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+	"todo/src/app/api/endpoints/dto/response"
+	"todo/src/app/api/endpoints/handlers/msgs"
+	"todo/src/core/domain"
+	eventbus "todo/src/core/interfaces/events"
+	interfaces "todo/src/core/interfaces/services"
+	"todo/src/core/markdown"
+	"todo/src/core/projecterrors/todoerrors"
+	"todo/src/core/services"
+	"todo/src/infra/postgres"
+)
+
+const (
+	mimeTextMarkdown            = "text/markdown"
+	mimeTextMarkdownCharsetUTF8 = mimeTextMarkdown + "; charset=UTF-8"
+	checklistFileName           = "collection-%d.md"
+	checklistCollectionId       = 1
+)
+
+// Checklist moves the tasks of a collection in and out of a Markdown checklist
+type Checklist struct {
+	collectionService interfaces.ICollection
+	transferService   interfaces.ITransfer
+}
+
+func NewChecklistHandler(emitter eventbus.IEmitter) *Checklist {
+	connectionManager := postgres.NewPostgresConnectionManager()
+	collectionRepository := postgres.NewCollectionPostgresRepository(connectionManager)
+	undoRepository := postgres.NewUndoPostgresRepository(connectionManager)
+	transferRepository := postgres.NewTransferPostgresRepository(connectionManager)
+	jobRepository := postgres.NewJobPostgresRepository(connectionManager)
+	return &Checklist{
+		collectionService: services.NewCollectionService(collectionRepository, undoRepository, emitter),
+		transferService:   services.NewTransferService(transferRepository, jobRepository),
+	}
+}
+
+// Export
+// @ID 			ExportChecklist
+// @Summary		Export a collection as a Markdown checklist
+// @Tags 		Collection
+// @Description Route that returns the collection as a Markdown document titled with its name, with a - [ ] item for each open task and a - [x] item for each finished one. The tasks have no subtasks, so the checklist is never nested.
+// @Produce 	text/markdown
+// @Security	bearerAuth
+// @Param 	    userId       path       int                  true                  "User ID"    default(1)
+// @Param 	    collectionId path       int                  true                  "Collection ID"
+// @Success 	200 		 {string} 	string                                     "Successful request"
+// @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403          {object}   response.SwaggerForbiddenResponse 	       "The user does not have access to this information"
+// @Failure 	404 		 {object} 	response.SwaggerNotFoundErrorResponse      "The collection was not found"
+// @Failure 	422 		 {object} 	response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Failure 	500 		 {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/user/{userId}/collection/{collectionId}/export.md  [get]
+func (h Checklist) Export(ctx echo.Context) error {
+	userId, err := convertToPositiveInteger(ctx.Param("userId"), msgs.UserId)
+	if err != nil {
+		log.Error(err)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.UserId, msgs.ConversionError)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
+	}
+	collectionId, err := convertToPositiveInteger(ctx.Param("collectionId"), msgs.CollectionId)
+	if err != nil {
+		log.Error(err)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.CollectionId, msgs.ConversionError)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
+	}
+
+	collection, err := h.collectionService.FindById(collectionId, userId, *domain.NewExpansion(domain.ExpandTasks))
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	newExportStream(ctx, mimeTextMarkdownCharsetUTF8, fmt.Sprintf(checklistFileName, collectionId)).start("")
+	if err = markdown.Encode(ctx.Response(), *collection); err != nil {
+		log.Error(err)
+	}
+
+	return nil
+}
+
+// Import
+// @ID 			ImportChecklist
+// @Summary		Import a Markdown checklist as a new collection
+// @Tags 		Collection
+// @Description Route that creates a collection with a task for each - [ ] or - [x] item of a Markdown document, sent as text/markdown or as multipart/form-data in the file field. The nested items become tasks of their own, and the other lines are ignored. The collection is named by the name parameter or else by the first heading of the document. Every item is validated first and nothing is imported if any of them is invalid.
+// @Accept 		text/markdown
+// @Accept 		mpfd
+// @Produce 	json
+// @Security	bearerAuth
+// @Param 	    userId       path       int                  true                  "User ID"    default(1)
+// @Param 	    name         query      string               false                 "Name of the collection, instead of the first heading"
+// @Param 		file 	     formData 	file                 false                 "Markdown checklist"
+// @Success 	201 		 {object} 	response.SwaggerTaskImportResponse         "Collection and tasks successfully imported"
+// @Failure 	400 		 {object} 	response.SwaggerBadRequestResponse         "The user has made a bad request"
+// @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403          {object}   response.SwaggerForbiddenResponse 	       "The user does not have access to this information"
+// @Failure 	415 		 {object} 	response.SwaggerGenericErrorResponse       "The format of the file is not supported"
+// @Failure 	422 		 {object} 	response.SwaggerValidationErrorResponse    "Some items could not be imported because they are not valid"
+// @Failure 	500 		 {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/user/{userId}/collection/import.md  [post]
+func (h Checklist) Import(ctx echo.Context) error {
+	userId, err := convertToPositiveInteger(ctx.Param("userId"), msgs.UserId)
+	if err != nil {
+		log.Error(err)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.UserId, msgs.ConversionError)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
+	}
+
+	checklist, validationErr := readChecklist(ctx)
+	if checklist == nil {
+		return validationErr
+	}
+	name := ctx.QueryParam("name")
+	if name == "" {
+		name = checklist.Name()
+	}
+	if _, nameErr := domain.NewValidatedCollection(checklistCollectionId, name); nameErr != nil {
+		log.Error(msgs.MissingChecklistName)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.Name, msgs.MissingChecklistName)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(msgs.MissingChecklistName, invalidFields))
+	}
+
+	collection := domain.NewCollection(checklistCollectionId, name)
+	tasks := make([]domain.Task, len(checklist.Tasks()))
+	for index, task := range checklist.Tasks() {
+		tasks[index] = *domain.NewTask(index+1, task.Description(), task.Finished(), collection)
+	}
+	transfer, transferErr := domain.NewValidatedTransfer([]domain.Collection{*collection}, tasks)
+	if transferErr != nil {
+		log.Error(transferErr)
+		return writeValidationError(ctx, *transferErr)
+	}
+
+	result, err := h.transferService.Import(*transfer, userId)
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	ids := make([]int, len(tasks))
+	for index, task := range tasks {
+		ids[index] = result.TaskIds()[task.Id()]
+	}
+	return writeCreatedResponse(ctx,
+		response.NewCollectionTaskImportResult(result.CollectionIds()[checklistCollectionId], ids))
+}
+
+// ImportIntoCollection
+// @ID 			ImportChecklistIntoCollection
+// @Summary		Import a Markdown checklist into a collection
+// @Tags 		Collection
+// @Description Route that adds a task to the collection for each - [ ] or - [x] item of a Markdown document, sent as text/markdown or as multipart/form-data in the file field. The nested items become tasks of their own, and the headings and other lines are ignored. Every item is validated first and nothing is imported if any of them is invalid.
+// @Accept 		text/markdown
+// @Accept 		mpfd
+// @Produce 	json
+// @Security	bearerAuth
+// @Param 	    userId       path       int                  true                  "User ID"    default(1)
+// @Param 	    collectionId path       int                  true                  "Collection ID"
+// @Param 		file 	     formData 	file                 false                 "Markdown checklist"
+// @Success 	201 		 {object} 	response.SwaggerTaskImportResponse         "Tasks successfully imported"
+// @Failure 	400 		 {object} 	response.SwaggerBadRequestResponse         "The user has made a bad request"
+// @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403          {object}   response.SwaggerForbiddenResponse 	       "The user does not have access to this information"
+// @Failure 	415 		 {object} 	response.SwaggerGenericErrorResponse       "The format of the file is not supported"
+// @Failure 	422 		 {object} 	response.SwaggerValidationErrorResponse    "Some items could not be imported because they are not valid, or the collection was not found"
+// @Failure 	500 		 {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/user/{userId}/collection/{collectionId}/import.md  [post]
+func (h Checklist) ImportIntoCollection(ctx echo.Context) error {
+	userId, err := convertToPositiveInteger(ctx.Param("userId"), msgs.UserId)
+	if err != nil {
+		log.Error(err)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.UserId, msgs.ConversionError)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
+	}
+	collectionId, err := convertToPositiveInteger(ctx.Param("collectionId"), msgs.CollectionId)
+	if err != nil {
+		log.Error(err)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.CollectionId, msgs.ConversionError)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
+	}
+
+	checklist, validationErr := readChecklist(ctx)
+	if checklist == nil {
+		return validationErr
+	}
+	collection := domain.NewCollection(collectionId, "")
+	tasks := make([]domain.Task, len(checklist.Tasks()))
+	for index, task := range checklist.Tasks() {
+		tasks[index] = *domain.NewTask(0, task.Description(), task.Finished(), collection)
+	}
+
+	ids, err := h.transferService.ImportTasks(tasks, userId)
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	return writeCreatedResponse(ctx, response.NewCollectionTaskImportResult(collectionId, ids))
+}
+
+// readChecklist decodes the uploaded checklist. When it cannot, the error has already been written and is returned
+// with a nil checklist.
+func readChecklist(ctx echo.Context) (*domain.Collection, error) {
+	file, err := openUploadedFile(ctx, mimeTextMarkdown)
+	if err != nil {
+		log.Error(err)
+		return nil, writeUploadError(ctx, err, mimeTextMarkdown)
+	}
+	defer file.Close()
+
+	checklist, validationErr := markdown.Decode(file)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return nil, writeValidationError(ctx, *validationErr)
+	}
+
+	return checklist, nil
+}
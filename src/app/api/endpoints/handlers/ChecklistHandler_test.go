@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"bytes"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"todo/src/core/domain"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+func newChecklistContext(method, target, contentType string, body *bytes.Buffer, names,
+	values []string) (echo.Context, *httptest.ResponseRecorder) {
+	if body == nil {
+		body = &bytes.Buffer{}
+	}
+	requestData := httptest.NewRequest(method, target, body)
+	if contentType != "" {
+		requestData.Header.Set("Content-Type", contentType)
+	}
+	responseData := httptest.NewRecorder()
+	context := echo.New().NewContext(requestData, responseData)
+	context.SetParamNames(names...)
+	context.SetParamValues(values...)
+
+	return context, responseData
+}
+
+func TestChecklist_Export(t *testing.T) {
+	t.Run("should return the tasks of the collection as a Markdown checklist", func(t *testing.T) {
+		context, responseData := newChecklistContext(http.MethodGet, "/user/1/collection/3/export.md", "", nil,
+			[]string{"userId", "collectionId"}, []string{"1", "3"})
+		mockCollectionService := new(MockCollectionService)
+		checklistHandler := Checklist{collectionService: mockCollectionService}
+		collection := domain.NewCollection(3, "Sport")
+		collection.SetTasks([]domain.Task{
+			*domain.NewTask(4, "Run", true, collection),
+			*domain.NewTask(7, "Swim", false, collection),
+		})
+		mockCollectionService.On("FindById", 3, 1, *domain.NewExpansion(domain.ExpandTasks)).Return(collection, nil)
+
+		_ = checklistHandler.Export(context)
+
+		assert.Equal(t, http.StatusOK, responseData.Code)
+		assert.Equal(t, "text/markdown; charset=UTF-8", responseData.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="collection-3.md"`, responseData.Header().Get("Content-Disposition"))
+		assert.Equal(t, "# Sport\n\n- [x] Run\n- [ ] Swim\n", responseData.Body.String())
+	})
+
+	t.Run("should return 404 when the collection does not exist", func(t *testing.T) {
+		context, responseData := newChecklistContext(http.MethodGet, "/user/1/collection/3/export.md", "", nil,
+			[]string{"userId", "collectionId"}, []string{"1", "3"})
+		mockCollectionService := new(MockCollectionService)
+		checklistHandler := Checklist{collectionService: mockCollectionService}
+		mockCollectionService.On("FindById", 3, 1, *domain.NewExpansion(domain.ExpandTasks)).
+			Return(nil, todoerrors.NewNotFoundError())
+
+		_ = checklistHandler.Export(context)
+
+		assert.Equal(t, http.StatusNotFound, responseData.Code)
+	})
+}
+
+func TestChecklist_Import(t *testing.T) {
+	checklist := "# Sport\n\n- [x] Run\n  - [ ] Swim\n"
+
+	t.Run("should return 201 with the new collection named by the heading", func(t *testing.T) {
+		context, responseData := newChecklistContext(http.MethodPost, "/user/1/collection/import.md",
+			"text/markdown", bytes.NewBufferString(checklist), []string{"userId"}, []string{"1"})
+		mockTransferService := new(MockTransferService)
+		checklistHandler := Checklist{transferService: mockTransferService}
+		mockTransferService.On("Import", mock.MatchedBy(func(transfer domain.Transfer) bool {
+			return transfer.Collections()[0].Name() == "Sport" && len(transfer.Tasks()) == 2 &&
+				transfer.Tasks()[0].Finished() && transfer.Tasks()[1].Collection().Id() == 1
+		}), 1).Return(domain.NewTransferResult(map[int]int{1: 9}, map[int]int{1: 20, 2: 21}), nil)
+
+		_ = checklistHandler.Import(context)
+
+		assert.Equal(t, http.StatusCreated, responseData.Code)
+		assert.Equal(t, "{\"collection_id\":9,\"ids\":[20,21]}\n", responseData.Body.String())
+	})
+
+	t.Run("should return 422 when the collection has no name", func(t *testing.T) {
+		context, responseData := newChecklistContext(http.MethodPost, "/user/1/collection/import.md",
+			"text/markdown", bytes.NewBufferString("- [ ] Run\n"), []string{"userId"}, []string{"1"})
+		mockTransferService := new(MockTransferService)
+		checklistHandler := Checklist{transferService: mockTransferService}
+
+		_ = checklistHandler.Import(context)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, responseData.Code)
+		mockTransferService.AssertNotCalled(t, "Import", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return 415 when the file is not Markdown", func(t *testing.T) {
+		context, responseData := newChecklistContext(http.MethodPost, "/user/1/collection/import.md",
+			"application/json", bytes.NewBufferString("{}"), []string{"userId"}, []string{"1"})
+		checklistHandler := Checklist{}
+
+		_ = checklistHandler.Import(context)
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, responseData.Code)
+	})
+}
+
+func TestChecklist_ImportIntoCollection(t *testing.T) {
+	t.Run("should return 201 with the IDs of the tasks added to the collection", func(t *testing.T) {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		file, _ := writer.CreateFormFile("file", "sport.md")
+		_, _ = file.Write([]byte("# Other name\n- [ ] Run\n- [X] Swim\n"))
+		_ = writer.Close()
+		context, responseData := newChecklistContext(http.MethodPost, "/user/1/collection/3/import.md",
+			writer.FormDataContentType(), body, []string{"userId", "collectionId"}, []string{"1", "3"})
+		mockTransferService := new(MockTransferService)
+		checklistHandler := Checklist{transferService: mockTransferService}
+		mockTransferService.On("ImportTasks", mock.MatchedBy(func(tasks []domain.Task) bool {
+			return len(tasks) == 2 && tasks[0].Collection().Id() == 3 && tasks[1].Finished()
+		}), 1).Return([]int{40, 41}, nil)
+
+		_ = checklistHandler.ImportIntoCollection(context)
+
+		assert.Equal(t, http.StatusCreated, responseData.Code)
+		assert.Equal(t, "{\"collection_id\":3,\"ids\":[40,41]}\n", responseData.Body.String())
+	})
+
+	t.Run("should return 422 when the collection does not exist", func(t *testing.T) {
+		context, responseData := newChecklistContext(http.MethodPost, "/user/1/collection/3/import.md",
+			"text/markdown", bytes.NewBufferString("- [ ] Run\n"), []string{"userId", "collectionId"},
+			[]string{"1", "3"})
+		mockTransferService := new(MockTransferService)
+		checklistHandler := Checklist{transferService: mockTransferService}
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField("collection", "The reported collection was not found.")
+		mockTransferService.On("ImportTasks", mock.Anything, 1).
+			Return(nil, todoerrors.NewValidationError("The reported collection was not found.", invalidFields))
+
+		_ = checklistHandler.ImportIntoCollection(context)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, responseData.Code)
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"todo/src/app/api/endpoints/dto/response"
 	"todo/src/app/api/endpoints/handlers/msgs"
 	"todo/src/core/domain"
+	eventbus "todo/src/core/interfaces/events"
 	interfaces "todo/src/core/interfaces/services"
 	"todo/src/core/projecterrors/todoerrors"
 	"todo/src/core/services"
@@ -17,10 +18,11 @@ type Collection struct {
 	service interfaces.ICollection
 }
 
-func NewCollectionHandler() *Collection {
+func NewCollectionHandler(emitter eventbus.IEmitter) *Collection {
 	connectionManager := postgres.NewPostgresConnectionManager()
 	repository := postgres.NewCollectionPostgresRepository(connectionManager)
-	service := services.NewCollectionService(repository)
+	undoRepository := postgres.NewUndoPostgresRepository(connectionManager)
+	service := services.NewCollectionService(repository, undoRepository, emitter)
 	return &Collection{service}
 }
 
@@ -37,8 +39,10 @@ func NewCollectionHandler() *Collection {
 // @Security	bearerAuth
 // @Param 	    userId       path       int                                true    "User ID"    default(1)
 // @Param 		authJson 	 body 		request.SwaggerCollectionRequest   true    "JSON responsible for sending all collection registration data to the database"
+// @Param 	    Idempotency-Key header      string             false                   "Unique key that makes retries of this request return the first response"
 // @Success 	201          {object} 	response.SwaggerIdResponse                 "Collection successfully registered"
 // @Failure 	400          {object} 	response.SwaggerBadRequestResponse         "The user has made a bad request"
+// @Failure 	409 		 {object} 	response.SwaggerConflictErrorResponse      "A request with the same idempotency key is still being processed"
 // @Failure 	422          {object} 	response.SwaggerValidationErrorResponse    "The user has made a bad request"
 // @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
 // @Failure 	403          {object}   response.SwaggerForbiddenResponse   	   "The user does not have access to this information"
@@ -92,6 +96,7 @@ func (h Collection) Create(ctx echo.Context) error {
 // @Param 	    userId          path        int                                 true   "User ID"          default(1)
 // @Param 	    collectionId    path        int                                 true   "Collection ID"    default(1)
 // @Param 		authJson 	    body 	    request.SwaggerCollectionRequest    true   "JSON responsible for sending the data needed to update the collection in the database"
+// @Param 	    If-Match        header      string             false                   "ETag of the last read version of the collection"
 // @Success 	204             {object}    nil 									   "Collection successfully edited"
 // @Failure 	400             {object} 	response.SwaggerBadRequestResponse         "The user has made a bad request"
 // @Failure 	422             {object} 	response.SwaggerValidationErrorResponse    "The user has made a bad request"
@@ -99,6 +104,7 @@ func (h Collection) Create(ctx echo.Context) error {
 // @Failure 	403             {object}    response.SwaggerForbiddenResponse   	   "The user does not have access to this information"
 // @Failure 	404             {object}    response.SwaggerNotFoundErrorResponse 	   "The user has requested a non-existent resource"
 // @Failure 	422             {object}    response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Failure 	412             {object}    response.SwaggerPreconditionFailedResponse "The collection has been changed since it was last read"
 // @Failure 	500             {object}    response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
 // @Router 		/user/{userId}/collection/{collectionId}  [put]
 func (h Collection) Update(ctx echo.Context) error {
@@ -131,6 +137,96 @@ func (h Collection) Update(ctx echo.Context) error {
 			*collectionErr.InvalidFields()))
 	}
 
+	version, err := ifMatchVersion(ctx, h.currentVersion(collectionId, userId))
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+	collection.SetVersion(version)
+
+	err = h.service.Update(*collection, userId)
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	return writeNoContentResponse(ctx)
+}
+
+// Patch
+// @ID 			PatchCollection
+// @Summary		Partially update a collection
+// @Tags 		Collection
+// @Description Route that allows editing only some data of a collection, keeping the others as they are stored. The body can be a JSON Merge Patch (RFC 7396) with the fields to change, or a JSON Patch (RFC 6902) with the operations to apply on the collection document below:
+// @Description |   Name   |  Type  | Description	     |
+// @Description |----------|--------|------------------|
+// @Description |   name   | string | Collection name  |
+// @Accept 		application/merge-patch+json
+// @Accept 		application/json-patch+json
+// @Produce 	json
+// @Security	bearerAuth
+// @Param 	    userId          path        int                                 true   "User ID"          default(1)
+// @Param 	    collectionId    path        int                                 true   "Collection ID"    default(1)
+// @Param 		patchJson 	    body 	    object                              true   "Merge patch, e.g. {\"name\": \"Work\"}, or JSON Patch, e.g. [{\"op\": \"replace\", \"path\": \"/name\", \"value\": \"Work\"}]"
+// @Param 	    If-Match        header      string             false                   "ETag of the last read version of the collection"
+// @Success 	204             {object}    nil 									   "Collection successfully edited"
+// @Failure 	400             {object} 	response.SwaggerBadRequestResponse         "The patch document is malformed"
+// @Failure 	401             {object}    response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403             {object}    response.SwaggerForbiddenResponse   	   "The user does not have access to this information"
+// @Failure 	404             {object}    response.SwaggerNotFoundErrorResponse 	   "The user has requested a non-existent resource"
+// @Failure 	415             {object}    response.SwaggerGenericErrorResponse 	   "The patch format is not supported"
+// @Failure 	422             {object}    response.SwaggerValidationErrorResponse    "The patch could not be applied or the patched collection is not valid"
+// @Failure 	412             {object}    response.SwaggerPreconditionFailedResponse "The collection has been changed since it was last read"
+// @Failure 	500             {object}    response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/user/{userId}/collection/{collectionId}  [patch]
+func (h Collection) Patch(ctx echo.Context) error {
+	userId, err := convertToPositiveInteger(ctx.Param("userId"), msgs.UserId)
+	if err != nil {
+		log.Error(err)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.UserId, msgs.ConversionError)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
+	}
+	collectionId, err := convertToPositiveInteger(ctx.Param("collectionId"), msgs.CollectionId)
+	if err != nil {
+		log.Error(err)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.CollectionId, msgs.ConversionError)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
+	}
+
+	storedCollection, err := h.service.FindById(collectionId, userId, *domain.NewExpansion())
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+	expectedVersion, err := ifMatchVersion(ctx, func() (int, error) { return storedCollection.Version(), nil })
+	if err == nil && expectedVersion != 0 && expectedVersion != storedCollection.Version() {
+		err = todoerrors.NewPreconditionFailedError()
+	}
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+	var requestData request.Collection
+	original := request.Collection{Name: storedCollection.Name()}
+	if patchErr := applyPatchDocument(ctx, original, &requestData); patchErr != nil {
+		log.Error(patchErr)
+		return writePatchDocumentError(ctx, patchErr)
+	}
+	collection, collectionErr := domain.NewValidatedCollection(
+		collectionId,
+		requestData.Name,
+	)
+	if collectionErr != nil {
+		log.Error(collectionErr)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(collectionErr.Error(),
+			*collectionErr.InvalidFields()))
+	}
+
+	// The patch was applied on the stored version, so it must not overwrite a concurrent change
+	collection.SetVersion(storedCollection.Version())
+
 	err = h.service.Update(*collection, userId)
 	if err != nil {
 		log.Error(err)
@@ -148,12 +244,15 @@ func (h Collection) Update(ctx echo.Context) error {
 // @Security	bearerAuth
 // @Param 	    userId          path    int                  true                  "User ID"          default(1)
 // @Param 	    collectionId    path    int                  true                  "Collection ID"    default(1)
+// @Param 	    If-Match        header      string             false                   "ETag of the last read version of the collection"
 // @Success 	204 		 {object} 	nil                                        "Collection successfully deleted"
+// @Header 		204 		 {string} 	Undo-Token                                 "Token that restores the collection"
 // @Failure 	422          {object} 	response.SwaggerValidationErrorResponse    "The user has made a bad request"
 // @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
 // @Failure 	403          {object}   response.SwaggerForbiddenResponse   	   "The user does not have access to this information"
 // @Failure 	404 		 {object} 	response.SwaggerNotFoundErrorResponse 	   "The user has requested a non-existent resource"
 // @Failure 	422 		 {object} 	response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Failure 	412             {object}    response.SwaggerPreconditionFailedResponse "The collection has been changed since it was last read"
 // @Failure 	500 		 {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
 // @Router 		/user/{userId}/collection/{collectionId}  [delete]
 func (h Collection) Delete(ctx echo.Context) error {
@@ -172,15 +271,82 @@ func (h Collection) Delete(ctx echo.Context) error {
 		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
 	}
 
-	err = h.service.Delete(collectionId, userId)
+	version, err := ifMatchVersion(ctx, h.currentVersion(collectionId, userId))
 	if err != nil {
 		log.Error(err)
 		return handleServiceErrors(ctx, err)
 	}
 
+	undoToken, err := h.service.Delete(collectionId, userId, version)
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	setUndoToken(ctx, undoToken)
 	return writeNoContentResponse(ctx)
 }
 
+// FindById
+// @ID 			FindCollectionById
+// @Summary 	Search a collection by ID
+// @Tags 		Collection
+// @Description Route that allows searching a user collection registered in the system. The expand parameter embeds related resources in the response:
+// @Description | Value |                  Description                   |
+// @Description |-------|------------------------------------------------|
+// @Description | tasks | All tasks of the collection                    |
+// @Description | tags  | Tags used by the tasks of the collection       |
+// @Produce		json
+// @Security	bearerAuth
+// @Param 	    userId          path        int                true                    "User ID"          default(1)
+// @Param 	    collectionId    path        int                true                    "Collection ID"    default(1)
+// @Param 		expand    		query     	string             false                   "Comma separated related resources, e.g. tasks,tags"
+// @Param 	    If-None-Match   header      string             false                   "ETag of the cached collection, answered with 304 when it has not changed"
+// @Success 	200             {object}    response.SwaggerExpandedCollectionResponse "Successful request"
+// @Header 		200             {string}    ETag                                       "Version of the collection"
+// @Success 	304             {object}    nil                                        "The collection has not changed"
+// @Failure 	400             {object}    response.SwaggerValidationErrorResponse    "The user has made a bad request"
+// @Failure 	401             {object}    response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403             {object}    response.SwaggerForbiddenResponse   	   "The user does not have access to this information"
+// @Failure 	404             {object} 	response.SwaggerNotFoundErrorResponse 	   "The user has requested a non-existent resource"
+// @Failure 	422             {object} 	response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Failure 	500             {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/user/{userId}/collection/{collectionId}    [get]
+func (h Collection) FindById(ctx echo.Context) error {
+	userId, err := convertToPositiveInteger(ctx.Param("userId"), msgs.UserId)
+	if err != nil {
+		log.Error(err)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.UserId, msgs.ConversionError)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
+	}
+	collectionId, err := convertToPositiveInteger(ctx.Param("collectionId"), msgs.CollectionId)
+	if err != nil {
+		log.Error(err)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.CollectionId, msgs.ConversionError)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
+	}
+	expansion, validationErr := parseExpansion(ctx, domain.CollectionExpansionFields)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+
+	collection, err := h.service.FindById(collectionId, userId, *expansion)
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	collectionResponse := response.NewExpandedCollection(*collection, *expansion)
+	etag := entityTag(collection.Version())
+	if len(expansion.Fields()) > 0 {
+		etag = expandedEntityTag(collection.Version(), collectionResponse)
+	}
+	return writeNotModifiedOrAccept(ctx, etag, collectionResponse)
+}
+
 // FindAll
 // @ID 			FindAllCollections
 // @Summary 	Lists all user collections
@@ -188,8 +354,19 @@ func (h Collection) Delete(ctx echo.Context) error {
 // @Description Route that allows searching all user collections in the system
 // @Produce		json
 // @Security	bearerAuth
-// @Param 		userId    path      int                 true                   "User ID"    default(1)
-// @Success 	200       {array} 	response.SwaggerCollectionResponse         "Successful request"
+// @Param 		userId    		path      int                 true                   "User ID"    default(1)
+// @Param 		limit    		query     int                 false                  "Maximum number of collections returned (1-100)"    default(20)
+// @Param 		offset    		query     int                 false                  "Number of collections skipped"
+// @Param 		page    		query     int                 false                  "Page number, used when offset is not informed"
+// @Param 		size    		query     int                 false                  "Page size, used when limit is not informed"
+// @Param 		sort    		query     string              false                  "Sort field"    Enums(id, name, created_at)
+// @Param 		order    		query     string              false                  "Sort direction"    Enums(asc, desc)
+// @Param 		search    		query     string              false                  "Only collections whose name contains this text"
+// @Param 		created_from    query     string              false                  "Only collections created from this date (YYYY-MM-DD or RFC 3339)"
+// @Param 		created_to    	query     string              false                  "Only collections created until this date (YYYY-MM-DD or RFC 3339)"
+// @Success 	200       		{array} 	response.SwaggerCollectionResponse     "Successful request"
+// @Header 		200       		{integer} 	X-Total-Count                          "Total number of collections matching the filters"
+// @Header 		200       		{string} 	Link                                   "Links to the first, previous, next and last pages"
 // @Failure 	422       {object} 	response.SwaggerValidationErrorResponse    "The user has made a bad request"
 // @Failure 	401       {object}  response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
 // @Failure 	403       {object} 	response.SwaggerForbiddenResponse          "The user does not have access to this information"
@@ -206,7 +383,18 @@ func (h Collection) FindAll(ctx echo.Context) error {
 		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
 	}
 
-	collectionList, err := h.service.FindAll(userId)
+	filter, validationErr := parseCollectionFilter(ctx)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+	pagination, validationErr := parsePagination(ctx, domain.CollectionSortFields)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+
+	collectionList, total, err := h.service.FindAll(userId, *filter, *pagination)
 	if err != nil {
 		log.Error(err)
 		return handleServiceErrors(ctx, err)
@@ -216,5 +404,17 @@ func (h Collection) FindAll(ctx echo.Context) error {
 	for _, collection := range collectionList {
 		collectionResponseList = append(collectionResponseList, *response.NewCollection(collection))
 	}
+	setPaginationHeaders(ctx, *pagination, total)
 	return writeAcceptResponse(ctx, collectionResponseList)
 }
+
+// currentVersion reads the version of the stored collection, used when the If-Match header lists several entity tags
+func (h Collection) currentVersion(collectionId, userId int) func() (int, error) {
+	return func() (int, error) {
+		collection, err := h.service.FindById(collectionId, userId, *domain.NewExpansion())
+		if err != nil {
+			return -1, err
+		}
+		return collection.Version(), nil
+	}
+}
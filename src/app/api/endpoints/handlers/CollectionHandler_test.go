@@ -8,7 +8,9 @@ import (
 	"github.com/stretchr/testify/mock"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 	"todo/src/app/api/endpoints/dto/request"
 	"todo/src/core/domain"
 	"todo/src/core/projecterrors/todoerrors"
@@ -28,19 +30,51 @@ func (m *MockCollectionService) Update(collection domain.Collection, userId int)
 	return args.Error(0)
 }
 
-func (m *MockCollectionService) Delete(collectionId, userId int) error {
+func (m *MockCollectionService) Delete(collectionId, userId, version int) (string, error) {
+	args := m.Called(collectionId, userId, version)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockCollectionService) Archive(collectionId, userId int) error {
 	args := m.Called(collectionId, userId)
 	return args.Error(0)
 }
 
-func (m *MockCollectionService) FindAll(userId int) ([]domain.Collection, error) {
-	args := m.Called(userId)
+func (m *MockCollectionService) FindByIds(collectionIds []int, userId int) ([]domain.Collection, error) {
+	args := m.Called(collectionIds, userId)
 	if args.Get(0) != nil {
 		return args.Get(0).([]domain.Collection), args.Error(1)
 	}
 	return nil, args.Error(1)
 }
 
+func (m *MockCollectionService) FindTasksOfCollections(collectionIds []int,
+	userId int) (map[int][]domain.Task, error) {
+	args := m.Called(collectionIds, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).(map[int][]domain.Task), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCollectionService) FindById(collectionId, userId int,
+	expansion domain.Expansion) (*domain.Collection, error) {
+	args := m.Called(collectionId, userId, expansion)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.Collection), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCollectionService) FindAll(userId int, filter domain.CollectionFilter,
+	pagination domain.Pagination) ([]domain.Collection, int, error) {
+	args := m.Called(userId, filter, pagination)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.Collection), args.Int(1), args.Error(2)
+	}
+	return nil, args.Int(1), args.Error(2)
+}
+
 func TestCollection_Create(t *testing.T) {
 	t.Run("should return 201 when the request is successful", func(t *testing.T) {
 		input := request.Collection{Name: "Test collection"}
@@ -287,6 +321,121 @@ func TestCollection_Update(t *testing.T) {
 	})
 }
 
+func TestCollection_Patch(t *testing.T) {
+	t.Run("should return 204 when the merge patch is applied on the stored collection", func(t *testing.T) {
+		requestData := httptest.NewRequest(http.MethodPatch, "/user/1/collection/2",
+			strings.NewReader("{\"name\":\"Renamed collection\"}"))
+		requestData.Header.Set("Content-Type", "application/merge-patch+json")
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId", "collectionId")
+		context.SetParamValues("1", "2")
+
+		mockService := new(MockCollectionService)
+		collectionHandler := Collection{service: mockService}
+		mockService.On("FindById", 2, 1, *domain.NewExpansion()).Return(domain.NewCollection(2, "Stored collection"), nil)
+		mockService.On("Update", *domain.NewCollection(2, "Renamed collection"), 1).Return(nil)
+
+		_ = collectionHandler.Patch(context)
+
+		assert.Equal(t, http.StatusNoContent, responseData.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return 422 when the patched collection is not valid", func(t *testing.T) {
+		requestData := httptest.NewRequest(http.MethodPatch, "/user/1/collection/2",
+			strings.NewReader("[{\"op\":\"remove\",\"path\":\"/name\"}]"))
+		requestData.Header.Set("Content-Type", "application/json-patch+json")
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId", "collectionId")
+		context.SetParamValues("1", "2")
+
+		mockService := new(MockCollectionService)
+		collectionHandler := Collection{service: mockService}
+		mockService.On("FindById", 2, 1, *domain.NewExpansion()).Return(domain.NewCollection(2, "Stored collection"), nil)
+
+		_ = collectionHandler.Patch(context)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, responseData.Code)
+		mockService.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return 404 when the collection does not exist", func(t *testing.T) {
+		requestData := httptest.NewRequest(http.MethodPatch, "/user/1/collection/2",
+			strings.NewReader("{\"name\":\"Renamed collection\"}"))
+		requestData.Header.Set("Content-Type", "application/merge-patch+json")
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId", "collectionId")
+		context.SetParamValues("1", "2")
+
+		mockService := new(MockCollectionService)
+		collectionHandler := Collection{service: mockService}
+		mockService.On("FindById", 2, 1, *domain.NewExpansion()).Return(nil, todoerrors.NewNotFoundError())
+
+		_ = collectionHandler.Patch(context)
+
+		assert.Equal(t, http.StatusNotFound, responseData.Code)
+	})
+}
+
+func TestCollection_Preconditions(t *testing.T) {
+	t.Run("should send the If-Match version along with the delete", func(t *testing.T) {
+		requestData := httptest.NewRequest(http.MethodDelete, "/user/1/collection/2", nil)
+		requestData.Header.Set("If-Match", "\"3\"")
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId", "collectionId")
+		context.SetParamValues("1", "2")
+
+		mockService := new(MockCollectionService)
+		collectionHandler := Collection{service: mockService}
+		mockService.On("Delete", 2, 1, 3).Return("", todoerrors.NewPreconditionFailedError())
+
+		_ = collectionHandler.Delete(context)
+
+		assert.Equal(t, http.StatusPreconditionFailed, responseData.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return a weak ETag that changes with the expanded tasks", func(t *testing.T) {
+		collection := domain.NewCollection(2, "Collection")
+		collection.SetVersion(3)
+		expansion := *domain.NewExpansion(domain.ExpandTasks)
+		mockService := new(MockCollectionService)
+		collectionHandler := Collection{service: mockService}
+		mockService.On("FindById", 2, 1, expansion).Return(collection, nil).Once()
+
+		requestData := httptest.NewRequest(http.MethodGet, "/user/1/collection/2?expand=tasks", nil)
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId", "collectionId")
+		context.SetParamValues("1", "2")
+
+		_ = collectionHandler.FindById(context)
+		etag := responseData.Header().Get("ETag")
+
+		changedCollection := domain.NewCollection(2, "Collection")
+		changedCollection.SetVersion(3)
+		changedCollection.SetTasks([]domain.Task{*domain.NewTask(4, "Task", false, domain.NewCollection(2, ""))})
+		mockService.On("FindById", 2, 1, expansion).Return(changedCollection, nil).Once()
+
+		requestData = httptest.NewRequest(http.MethodGet, "/user/1/collection/2?expand=tasks", nil)
+		requestData.Header.Set("If-None-Match", etag)
+		responseData = httptest.NewRecorder()
+		context = echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId", "collectionId")
+		context.SetParamValues("1", "2")
+
+		_ = collectionHandler.FindById(context)
+
+		assert.True(t, strings.HasPrefix(etag, "W/\"3-"))
+		assert.Equal(t, http.StatusOK, responseData.Code)
+		assert.NotEqual(t, etag, responseData.Header().Get("ETag"))
+	})
+}
+
 func TestCollection_Delete(t *testing.T) {
 	t.Run("should return 204 when the request is successful", func(t *testing.T) {
 		requestData := httptest.NewRequest(http.MethodDelete, "/user/1/collection/2", nil)
@@ -298,7 +447,7 @@ func TestCollection_Delete(t *testing.T) {
 
 		mockService := new(MockCollectionService)
 		collectionHandler := Collection{service: mockService}
-		mockService.On("Delete", mock.Anything, mock.Anything).Return(nil)
+		mockService.On("Delete", mock.Anything, mock.Anything, mock.Anything).Return("", nil)
 
 		_ = collectionHandler.Delete(context)
 
@@ -359,7 +508,7 @@ func TestCollection_Delete(t *testing.T) {
 		mockService := new(MockCollectionService)
 		collectionHandler := Collection{service: mockService}
 		serviceErr := todoerrors.NewUnexpectedInternalError("Service layer error")
-		mockService.On("Delete", mock.Anything, mock.Anything).Return(serviceErr)
+		mockService.On("Delete", mock.Anything, mock.Anything, mock.Anything).Return("", serviceErr)
 
 		_ = collectionHandler.Delete(context)
 
@@ -370,6 +519,88 @@ func TestCollection_Delete(t *testing.T) {
 	})
 }
 
+func TestCollection_FindById(t *testing.T) {
+	t.Run("should return 200 with the expanded tasks and tags", func(t *testing.T) {
+		requestData := httptest.NewRequest(http.MethodGet, "/user/1/collection/2?expand=tasks,tags", nil)
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId", "collectionId")
+		context.SetParamValues("1", "2")
+
+		mockService := new(MockCollectionService)
+		collectionHandler := Collection{service: mockService}
+		collection := domain.NewCollection(2, "Collection")
+		task := domain.NewTask(3, "Task", false, domain.NewCollection(2, "Collection"))
+		task.SetTags([]string{"work"})
+		collection.SetTasks([]domain.Task{*task})
+		collection.SetTags([]string{"work"})
+		expansion := *domain.NewExpansion(domain.ExpandTasks, domain.ExpandTags)
+		mockService.On("FindById", 2, 1, expansion).Return(collection, nil)
+
+		_ = collectionHandler.FindById(context)
+
+		expectedBody := "{\"id\":2,\"name\":\"Collection\",\"tasks\":[{\"id\":3,\"description\":\"Task\"," +
+			"\"finished\":false,\"collection\":{\"id\":2,\"name\":\"Collection\"},\"tags\":[\"work\"]}]," +
+			"\"tags\":[\"work\"]}\n"
+
+		assert.Equal(t, http.StatusOK, responseData.Code)
+		assert.Equal(t, expectedBody, responseData.Body.String())
+	})
+
+	t.Run("should return empty expansions instead of omitting them", func(t *testing.T) {
+		requestData := httptest.NewRequest(http.MethodGet, "/user/1/collection/2?expand=tasks", nil)
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId", "collectionId")
+		context.SetParamValues("1", "2")
+
+		mockService := new(MockCollectionService)
+		collectionHandler := Collection{service: mockService}
+		expansion := *domain.NewExpansion(domain.ExpandTasks)
+		mockService.On("FindById", 2, 1, expansion).Return(domain.NewCollection(2, "Collection"), nil)
+
+		_ = collectionHandler.FindById(context)
+
+		assert.Equal(t, http.StatusOK, responseData.Code)
+		assert.Equal(t, "{\"id\":2,\"name\":\"Collection\",\"tasks\":[]}\n", responseData.Body.String())
+	})
+
+	t.Run("should return 422 when the expand value is not allowed", func(t *testing.T) {
+		requestData := httptest.NewRequest(http.MethodGet, "/user/1/collection/2?expand=owner", nil)
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId", "collectionId")
+		context.SetParamValues("1", "2")
+
+		mockService := new(MockCollectionService)
+		collectionHandler := Collection{service: mockService}
+
+		_ = collectionHandler.FindById(context)
+
+		expectedBody := "{\"message\":\"Invalid expansion details.\",\"invalid_fields\":[{\"name\":\"Expand\"," +
+			"\"description\":\"The expand value provided is invalid. The allowed values are: tasks, tags\"}]}\n"
+
+		assert.Equal(t, http.StatusUnprocessableEntity, responseData.Code)
+		assert.Equal(t, expectedBody, responseData.Body.String())
+	})
+
+	t.Run("should return 404 when the collection does not exist", func(t *testing.T) {
+		requestData := httptest.NewRequest(http.MethodGet, "/user/1/collection/2", nil)
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId", "collectionId")
+		context.SetParamValues("1", "2")
+
+		mockService := new(MockCollectionService)
+		collectionHandler := Collection{service: mockService}
+		mockService.On("FindById", 2, 1, *domain.NewExpansion()).Return(nil, todoerrors.NewNotFoundError())
+
+		_ = collectionHandler.FindById(context)
+
+		assert.Equal(t, http.StatusNotFound, responseData.Code)
+	})
+}
+
 func TestCollection_FindAll(t *testing.T) {
 	t.Run("should return 200 when the request is successful", func(t *testing.T) {
 		requestData := httptest.NewRequest(http.MethodGet, "/user/1/collection", nil)
@@ -385,7 +616,7 @@ func TestCollection_FindAll(t *testing.T) {
 			*domain.NewCollection(1, "Test Collection 1"),
 			*domain.NewCollection(2, "Test Collection 2"),
 		}
-		mockService.On("FindAll", mock.Anything).Return(collections, nil)
+		mockService.On("FindAll", mock.Anything, mock.Anything, mock.Anything).Return(collections, 2, nil)
 
 		_ = collectionHandler.FindAll(context)
 
@@ -427,7 +658,7 @@ func TestCollection_FindAll(t *testing.T) {
 		mockService := new(MockCollectionService)
 		collectionHandler := Collection{service: mockService}
 		serviceErr := todoerrors.NewUnexpectedInternalError("Service layer error")
-		mockService.On("FindAll", mock.Anything).Return(nil, serviceErr)
+		mockService.On("FindAll", mock.Anything, mock.Anything, mock.Anything).Return(nil, 0, serviceErr)
 
 		_ = collectionHandler.FindAll(context)
 
@@ -436,4 +667,46 @@ func TestCollection_FindAll(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, responseData.Code)
 		assert.Equal(t, expectedBody, responseData.Body.String())
 	})
+
+	t.Run("should return 422 when the created range is inverted", func(t *testing.T) {
+		requestData := httptest.NewRequest(http.MethodGet,
+			"/user/1/collection?created_from=2024-02-01&created_to=2024-01-01", nil)
+		requestData.Header.Set("Content-Type", "application/json")
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId")
+		context.SetParamValues("1")
+
+		mockService := new(MockCollectionService)
+		collectionHandler := Collection{service: mockService}
+
+		_ = collectionHandler.FindAll(context)
+
+		expectedBody := "{\"message\":\"Invalid filter details.\",\"invalid_fields\":[{\"name\":\"Created Range\"," +
+			"\"description\":\"The created range provided is invalid. The start must not be after the end.\"}]}\n"
+
+		assert.Equal(t, http.StatusUnprocessableEntity, responseData.Code)
+		assert.Equal(t, expectedBody, responseData.Body.String())
+	})
+
+	t.Run("should include the whole end day of a plain date", func(t *testing.T) {
+		requestData := httptest.NewRequest(http.MethodGet,
+			"/user/1/collection?created_from=2024-05-10&created_to=2024-05-10", nil)
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId")
+		context.SetParamValues("1")
+
+		mockService := new(MockCollectionService)
+		collectionHandler := Collection{service: mockService}
+		mockService.On("FindAll", 1, mock.MatchedBy(func(filter domain.CollectionFilter) bool {
+			return filter.CreatedFrom().Equal(time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)) &&
+				filter.CreatedTo().Equal(time.Date(2024, 5, 10, 23, 59, 59, 999999000, time.UTC))
+		}), mock.Anything).Return([]domain.Collection{}, 0, nil)
+
+		_ = collectionHandler.FindAll(context)
+
+		assert.Equal(t, http.StatusOK, responseData.Code)
+		mockService.AssertExpectations(t)
+	})
 }
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+	"net/http"
+	"strings"
+	"todo/src/app/api/endpoints/dto/request"
+	"todo/src/app/api/endpoints/handlers/msgs"
+	"todo/src/app/api/graph"
+	"todo/src/core/domain"
+	eventbus "todo/src/core/interfaces/events"
+	"todo/src/core/services"
+	"todo/src/infra/postgres"
+)
+
+type GraphQL struct {
+	schema *graph.Schema
+}
+
+func NewGraphQLHandler(emitter eventbus.IEmitter) *GraphQL {
+	connectionManager := postgres.NewPostgresConnectionManager()
+	undoRepository := postgres.NewUndoPostgresRepository(connectionManager)
+	taskRepository := postgres.NewTaskPostgresRepository(connectionManager)
+	collectionRepository := postgres.NewCollectionPostgresRepository(connectionManager)
+	return &GraphQL{graph.NewSchema(
+		services.NewTaskService(taskRepository, undoRepository, emitter),
+		services.NewCollectionService(collectionRepository, undoRepository, emitter),
+	)}
+}
+
+// Query
+// @ID 			QueryGraphQL
+// @Summary		Run a GraphQL operation
+// @Tags 		GraphQL
+// @Description Route that runs GraphQL queries and mutations on the account, collections and tasks of the signed in user, whose ID is read from the token. Nested selections are read in batches, one query per level, such as the tasks of every collection in a list. The errors of the operation are returned with status 200, their extensions holding a code such as NOT_FOUND or VALIDATION.
+// @Accept 		json
+// @Produce 	json
+// @Security	bearerAuth
+// @Param 		graphqlJson  body 		request.SwaggerGraphQLRequest         true      "GraphQL query, with the operation name and variables"
+// @Success 	200 		 {object} 	response.SwaggerGraphQLResponse             "Operation executed"
+// @Failure 	400 		 {object} 	response.SwaggerBadRequestResponse          "The request has no GraphQL query"
+// @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	    "The user is not authorized to make this request"
+// @Router 		/graphql  [post]
+func (h GraphQL) Query(ctx echo.Context) error {
+	accountId, _ := ctx.Get(AccountIdKey).(int)
+	email, _ := ctx.Get(AccountEmailKey).(string)
+	var requestData request.GraphQL
+	if err := ctx.Bind(&requestData); err != nil {
+		log.Error(err)
+		return writeBadRequestError(ctx, msgs.RequestFormatError)
+	}
+	if strings.TrimSpace(requestData.Query) == "" {
+		log.Error(msgs.MissingGraphQLQuery)
+		return writeBadRequestError(ctx, msgs.MissingGraphQLQuery)
+	}
+
+	account := domain.NewAccount(accountId, "", email, "", "")
+	result := h.schema.Exec(ctx.Request().Context(), *account, requestData.Query, requestData.OperationName,
+		requestData.Variables)
+	return ctx.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"todo/src/app/api/endpoints/dto/request"
+	"todo/src/app/api/graph"
+	"todo/src/core/domain"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+func newGraphQLContext(requestBody []byte) (echo.Context, *httptest.ResponseRecorder) {
+	requestData := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewBuffer(requestBody))
+	requestData.Header.Set("Content-Type", "application/json")
+	responseData := httptest.NewRecorder()
+	context := echo.New().NewContext(requestData, responseData)
+	context.Set(AccountIdKey, 1)
+	context.Set(AccountEmailKey, "example@example.com")
+
+	return context, responseData
+}
+
+func newGraphQLRequest(query string, variables map[string]interface{}) []byte {
+	requestBody, _ := json.Marshal(request.GraphQL{Query: query, Variables: variables})
+	return requestBody
+}
+
+func TestGraphQL_Query(t *testing.T) {
+	t.Run("should read the tasks of every collection in a single call", func(t *testing.T) {
+		context, responseData := newGraphQLContext(newGraphQLRequest(
+			`{ me { email collections { name tasks { description } } } }`, nil))
+		mockCollectionService := new(MockCollectionService)
+		graphqlHandler := GraphQL{graph.NewSchema(new(MockTaskService), mockCollectionService)}
+		work, home := domain.NewCollection(1, "Work"), domain.NewCollection(2, "Home")
+		mockCollectionService.On("FindAll", 1, mock.Anything, mock.Anything).
+			Return([]domain.Collection{*work, *home}, 2, nil)
+		mockCollectionService.On("FindTasksOfCollections", []int{1, 2}, 1).Return(map[int][]domain.Task{
+			1: {*domain.NewTask(5, "Report", false, work), *domain.NewTask(6, "Meeting", true, work)},
+		}, nil).Once()
+
+		_ = graphqlHandler.Query(context)
+
+		assert.Equal(t, http.StatusOK, responseData.Code)
+		assert.JSONEq(t, `{"data": {"me": {"email": "example@example.com", "collections": [
+			{"name": "Work", "tasks": [{"description": "Report"}, {"description": "Meeting"}]},
+			{"name": "Home", "tasks": []}
+		]}}}`, responseData.Body.String())
+		mockCollectionService.AssertExpectations(t)
+	})
+
+	t.Run("should read the collections of every task in a single call", func(t *testing.T) {
+		context, responseData := newGraphQLContext(newGraphQLRequest(
+			`{ me { tasks(finished: false) { id collection { name version } } } }`, nil))
+		mockTaskService := new(MockTaskService)
+		mockCollectionService := new(MockCollectionService)
+		graphqlHandler := GraphQL{graph.NewSchema(mockTaskService, mockCollectionService)}
+		work, home := domain.NewCollection(3, "Work"), domain.NewCollection(4, "Home")
+		home.SetVersion(2)
+		mockTaskService.On("FindAll", 1, mock.MatchedBy(func(filter domain.TaskFilter) bool {
+			return filter.Finished() != nil && !*filter.Finished()
+		}), mock.Anything).Return([]domain.Task{
+			*domain.NewTask(7, "Report", false, domain.NewCollection(3, "")),
+			*domain.NewTask(8, "Clean", false, domain.NewCollection(4, "")),
+			*domain.NewTask(9, "Call", false, domain.NewCollection(3, "")),
+			*domain.NewTask(10, "Read", false, domain.NewCollection(0, "")),
+		}, 4, nil)
+		mockCollectionService.On("FindByIds", []int{3, 4}, 1).
+			Return([]domain.Collection{*work, *home}, nil).Once()
+
+		_ = graphqlHandler.Query(context)
+
+		assert.Equal(t, http.StatusOK, responseData.Code)
+		assert.JSONEq(t, `{"data": {"me": {"tasks": [
+			{"id": 7, "collection": {"name": "Work", "version": 0}},
+			{"id": 8, "collection": {"name": "Home", "version": 2}},
+			{"id": 9, "collection": {"name": "Work", "version": 0}},
+			{"id": 10, "collection": null}
+		]}}}`, responseData.Body.String())
+		mockCollectionService.AssertExpectations(t)
+	})
+
+	t.Run("should create the task and return its stored state", func(t *testing.T) {
+		context, responseData := newGraphQLContext(newGraphQLRequest(
+			`mutation Create($input: TaskInput!) { createTask(input: $input) { id description tags } }`,
+			map[string]interface{}{"input": map[string]interface{}{
+				"description": "Run", "tags": []string{"Sport"}, "collectionId": 3,
+			}}))
+		mockTaskService := new(MockTaskService)
+		graphqlHandler := GraphQL{graph.NewSchema(mockTaskService, new(MockCollectionService))}
+		storedTask := domain.NewTask(12, "Run", false, domain.NewCollection(3, "Sport"))
+		storedTask.SetTags([]string{"sport"})
+		mockTaskService.On("Create", mock.MatchedBy(func(task domain.Task) bool {
+			return task.Description() == "Run" && task.Collection().Id() == 3 && task.Tags()[0] == "sport"
+		}), 1).Return(12, nil)
+		mockTaskService.On("FindById", 12, 1).Return(storedTask, nil)
+
+		_ = graphqlHandler.Query(context)
+
+		assert.Equal(t, http.StatusOK, responseData.Code)
+		assert.JSONEq(t, `{"data": {"createTask": {"id": 12, "description": "Run", "tags": ["sport"]}}}`,
+			responseData.Body.String())
+	})
+
+	t.Run("should return the error code in the extensions when the service fails", func(t *testing.T) {
+		context, responseData := newGraphQLContext(newGraphQLRequest(
+			`mutation { deleteTask(id: 4, version: 2) }`, nil))
+		mockTaskService := new(MockTaskService)
+		graphqlHandler := GraphQL{graph.NewSchema(mockTaskService, new(MockCollectionService))}
+		mockTaskService.On("Delete", 4, 1, 2).Return("", todoerrors.NewNotFoundError())
+
+		_ = graphqlHandler.Query(context)
+
+		var responseBody struct {
+			Errors []struct {
+				Message    string                 `json:"message"`
+				Extensions map[string]interface{} `json:"extensions"`
+			} `json:"errors"`
+		}
+		_ = json.Unmarshal(responseData.Body.Bytes(), &responseBody)
+		assert.Equal(t, http.StatusOK, responseData.Code)
+		assert.Len(t, responseBody.Errors, 1)
+		assert.Equal(t, "NOT_FOUND", responseBody.Errors[0].Extensions["code"])
+	})
+
+	t.Run("should return the invalid fields when the input is not valid", func(t *testing.T) {
+		context, responseData := newGraphQLContext(newGraphQLRequest(
+			`mutation { updateCollection(id: 2, name: "  ") { id } }`, nil))
+		graphqlHandler := GraphQL{graph.NewSchema(new(MockTaskService), new(MockCollectionService))}
+
+		_ = graphqlHandler.Query(context)
+
+		assert.Equal(t, http.StatusOK, responseData.Code)
+		assert.Contains(t, responseData.Body.String(), `"code":"VALIDATION"`)
+		assert.Contains(t, responseData.Body.String(), `"invalid_fields":[{`)
+	})
+
+	t.Run("should return null when the collection does not exist", func(t *testing.T) {
+		context, responseData := newGraphQLContext(newGraphQLRequest(`{ collection(id: 9) { name } }`, nil))
+		mockCollectionService := new(MockCollectionService)
+		graphqlHandler := GraphQL{graph.NewSchema(new(MockTaskService), mockCollectionService)}
+		mockCollectionService.On("FindById", 9, 1, mock.Anything).Return(nil, todoerrors.NewNotFoundError())
+
+		_ = graphqlHandler.Query(context)
+
+		assert.Equal(t, http.StatusOK, responseData.Code)
+		assert.JSONEq(t, `{"data": {"collection": null}}`, responseData.Body.String())
+	})
+
+	t.Run("should return 400 when the request has no query", func(t *testing.T) {
+		context, responseData := newGraphQLContext(newGraphQLRequest(" ", nil))
+		graphqlHandler := GraphQL{graph.NewSchema(new(MockTaskService), new(MockCollectionService))}
+
+		_ = graphqlHandler.Query(context)
+
+		assert.Equal(t, http.StatusBadRequest, responseData.Code)
+	})
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+	"todo/src/app/api/endpoints/dto/response"
+	"todo/src/app/api/endpoints/handlers/msgs"
+	"todo/src/core/domain"
+	interfaces "todo/src/core/interfaces/services"
+	"todo/src/core/projecterrors/todoerrors"
+	"todo/src/core/services"
+	"todo/src/infra/postgres"
+)
+
+type Job struct {
+	service interfaces.IJob
+}
+
+func NewJobHandler() *Job {
+	connectionManager := postgres.NewPostgresConnectionManager()
+	repository := postgres.NewJobPostgresRepository(connectionManager)
+	service := services.NewJobService(repository)
+	return &Job{service}
+}
+
+// FindAll
+// @ID 			FindJobs
+// @Summary		List the background jobs
+// @Tags 		Admin
+// @Description Route that lists the background jobs run by the instances of the API, such as the reminders and the purges, with their status, which is queued, running, succeeded or failed. A failed job has no attempts left, and its last error tells why. The total is returned in the X-Total-Count header and the pages in the Link header.
+// @Description Only the accounts whose email is in ADMIN_EMAILS can use this route.
+// @Produce 	json
+// @Security	bearerAuth
+// @Param 		status    	 query      string               false                 "Status: queued, running, succeeded or failed"
+// @Param 		limit    	 query      int                  false                 "Maximum number of jobs, from 1 to 100"    default(20)
+// @Param 		offset    	 query      int                  false                 "Number of jobs skipped"                   default(0)
+// @Param 		sort    	 query      string               false                 "Sort field: id, run_at or created_at"     default(id)
+// @Param 		order    	 query      string               false                 "Sort order: asc or desc"                  default(asc)
+// @Success 	200 		 {array} 	response.SwaggerJobResponse                "Successful request"
+// @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403          {object}   response.SwaggerForbiddenResponse 	       "The user is not an administrator"
+// @Failure 	422 		 {object} 	response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Failure 	500 		 {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/admin/job  [get]
+func (h Job) FindAll(ctx echo.Context) error {
+	filter, validationErr := domain.NewValidatedJobFilter(ctx.QueryParam("status"))
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+	pagination, validationErr := parsePagination(ctx, domain.JobSortFields)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+
+	jobList, total, err := h.service.FindAll(*filter, *pagination)
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	jobResponseList := []response.Job{}
+	for _, job := range jobList {
+		jobResponseList = append(jobResponseList, *response.NewJob(job))
+	}
+	setPaginationHeaders(ctx, *pagination, total)
+	return writeAcceptResponse(ctx, jobResponseList)
+}
+
+// FindById
+// @ID 			FindJobById
+// @Summary		Find a background job
+// @Tags 		Admin
+// @Description Route that finds a background job with its payload, its attempts and the worker holding it while it runs.
+// @Description Only the accounts whose email is in ADMIN_EMAILS can use this route.
+// @Produce 	json
+// @Security	bearerAuth
+// @Param 	    jobId        path       int                  true                  "Job ID"    default(1)
+// @Success 	200 		 {object} 	response.SwaggerJobResponse                "Successful request"
+// @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403          {object}   response.SwaggerForbiddenResponse 	       "The user is not an administrator"
+// @Failure 	404 		 {object} 	response.SwaggerNotFoundErrorResponse 	   "The user has requested a non-existent resource"
+// @Failure 	422 		 {object} 	response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Failure 	500 		 {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/admin/job/{jobId}  [get]
+func (h Job) FindById(ctx echo.Context) error {
+	jobId, validationErr := parseJobParams(ctx)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+
+	job, err := h.service.FindById(jobId)
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	return writeAcceptResponse(ctx, response.NewJob(*job))
+}
+
+// Retry
+// @ID 			RetryJob
+// @Summary		Retry a failed background job
+// @Tags 		Admin
+// @Description Route that queues a failed job again to be run at once, with all its attempts. A scheduled job can't be retried while its schedule has another job queued or running.
+// @Description Only the accounts whose email is in ADMIN_EMAILS can use this route.
+// @Security	bearerAuth
+// @Param 	    jobId        path       int                  true                  "Job ID"    default(1)
+// @Success 	204          {object}   nil                                        "Job successfully queued again"
+// @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403          {object}   response.SwaggerForbiddenResponse 	       "The user is not an administrator"
+// @Failure 	404 		 {object} 	response.SwaggerNotFoundErrorResponse 	   "The job does not exist or has not failed"
+// @Failure 	409 		 {object} 	response.SwaggerConflictErrorResponse      "The schedule of the job has another job queued or running"
+// @Failure 	422 		 {object} 	response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Failure 	500 		 {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/admin/job/{jobId}/retry  [post]
+func (h Job) Retry(ctx echo.Context) error {
+	jobId, validationErr := parseJobParams(ctx)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+
+	if err := h.service.Retry(jobId); err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	return writeNoContentResponse(ctx)
+}
+
+// FindSchedules
+// @ID 			FindJobSchedules
+// @Summary		List the schedules of the background jobs
+// @Tags 		Admin
+// @Description Route that lists the schedules queueing the recurring jobs, with their cron expression, read in UTC, and the time of their next run. A schedule skips its runs while its previous job is queued or running.
+// @Description Only the accounts whose email is in ADMIN_EMAILS can use this route.
+// @Produce 	json
+// @Security	bearerAuth
+// @Success 	200 		 {array} 	response.SwaggerJobScheduleResponse        "Successful request"
+// @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403          {object}   response.SwaggerForbiddenResponse 	       "The user is not an administrator"
+// @Failure 	500 		 {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/admin/job/schedule  [get]
+func (h Job) FindSchedules(ctx echo.Context) error {
+	scheduleList, err := h.service.FindSchedules()
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	scheduleResponseList := []response.JobSchedule{}
+	for _, schedule := range scheduleList {
+		scheduleResponseList = append(scheduleResponseList, *response.NewJobSchedule(schedule))
+	}
+	return writeAcceptResponse(ctx, scheduleResponseList)
+}
+
+func parseJobParams(ctx echo.Context) (int64, *todoerrors.Validation) {
+	jobId, err := convertToPositiveInteger(ctx.Param("jobId"), msgs.JobId)
+	if err != nil {
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.JobId, msgs.ConversionError)
+		return 0, todoerrors.NewValidationError(msgs.InvalidQueryParams, invalidFields)
+	}
+
+	return int64(jobId), nil
+}
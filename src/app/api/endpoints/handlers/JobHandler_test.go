@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"testing"
+	"time"
+	"todo/src/core/domain"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+type MockJobService struct {
+	mock.Mock
+}
+
+func (m *MockJobService) FindAll(filter domain.JobFilter, pagination domain.Pagination) ([]domain.Job, int, error) {
+	args := m.Called(filter, pagination)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.Job), args.Int(1), args.Error(2)
+	}
+	return nil, 0, args.Error(2)
+}
+
+func (m *MockJobService) FindById(jobId int64) (*domain.Job, error) {
+	args := m.Called(jobId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.Job), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockJobService) FindSchedules() ([]domain.JobSchedule, error) {
+	args := m.Called()
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.JobSchedule), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockJobService) Retry(jobId int64) error {
+	args := m.Called(jobId)
+	return args.Error(0)
+}
+
+func TestJob_FindAll(t *testing.T) {
+	t.Run("should return the failed jobs with their total", func(t *testing.T) {
+		context, responseData := newWebhookContext(http.MethodGet, "/admin/job?status=Failed&limit=1", "", nil, nil)
+		mockService := new(MockJobService)
+		jobHandler := Job{mockService}
+		runAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		failed := domain.NewJob(4, "reminders.send", nil, runAt)
+		failed.SetState(domain.JobFailed, 5, 5, "connection refused")
+		failed.SetSchedule("reminders.send")
+		mockService.On("FindAll", *domain.NewJobFilter(domain.JobFailed), mock.MatchedBy(
+			func(pagination domain.Pagination) bool {
+				return pagination.Limit() == 1
+			})).Return([]domain.Job{*failed}, 3, nil)
+
+		_ = jobHandler.FindAll(context)
+
+		assert.Equal(t, http.StatusOK, responseData.Code)
+		assert.Equal(t, "3", responseData.Header().Get(headerTotalCount))
+		assert.JSONEq(t, `[{"id": 4, "kind": "reminders.send", "payload": {}, "status": "failed", "attempts": 5,
+			"max_attempts": 5, "last_error": "connection refused", "run_at": "2024-01-01T12:00:00Z",
+			"schedule": "reminders.send"}]`, responseData.Body.String())
+	})
+
+	t.Run("should return 422 when the status does not exist", func(t *testing.T) {
+		context, responseData := newWebhookContext(http.MethodGet, "/admin/job?status=lost", "", nil, nil)
+		jobHandler := Job{new(MockJobService)}
+
+		_ = jobHandler.FindAll(context)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, responseData.Code)
+		assert.Contains(t, responseData.Body.String(), "queued, running, succeeded, failed")
+	})
+}
+
+func TestJob_FindById(t *testing.T) {
+	t.Run("should return 404 when the job does not exist", func(t *testing.T) {
+		context, responseData := newWebhookContext(http.MethodGet, "/admin/job/9", "", []string{"jobId"},
+			[]string{"9"})
+		mockService := new(MockJobService)
+		jobHandler := Job{mockService}
+		mockService.On("FindById", int64(9)).Return(nil, todoerrors.NewNotFoundError())
+
+		_ = jobHandler.FindById(context)
+
+		assert.Equal(t, http.StatusNotFound, responseData.Code)
+	})
+}
+
+func TestJob_Retry(t *testing.T) {
+	t.Run("should return 204 once the failed job is queued again", func(t *testing.T) {
+		context, responseData := newWebhookContext(http.MethodPost, "/admin/job/4/retry", "", []string{"jobId"},
+			[]string{"4"})
+		mockService := new(MockJobService)
+		jobHandler := Job{mockService}
+		mockService.On("Retry", int64(4)).Return(nil)
+
+		_ = jobHandler.Retry(context)
+
+		assert.Equal(t, http.StatusNoContent, responseData.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return 409 when the schedule of the job has another job queued", func(t *testing.T) {
+		context, responseData := newWebhookContext(http.MethodPost, "/admin/job/4/retry", "", []string{"jobId"},
+			[]string{"4"})
+		mockService := new(MockJobService)
+		jobHandler := Job{mockService}
+		mockService.On("Retry", int64(4)).Return(todoerrors.NewConflictError("schedule"))
+
+		_ = jobHandler.Retry(context)
+
+		assert.Equal(t, http.StatusConflict, responseData.Code)
+	})
+
+	t.Run("should return 422 when the job ID is not a positive integer", func(t *testing.T) {
+		context, responseData := newWebhookContext(http.MethodPost, "/admin/job/x/retry", "", []string{"jobId"},
+			[]string{"x"})
+		jobHandler := Job{new(MockJobService)}
+
+		_ = jobHandler.Retry(context)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, responseData.Code)
+		assert.Contains(t, responseData.Body.String(), "Job ID")
+	})
+}
+
+func TestJob_FindSchedules(t *testing.T) {
+	t.Run("should return the schedules with their next run", func(t *testing.T) {
+		context, responseData := newWebhookContext(http.MethodGet, "/admin/job/schedule", "", nil, nil)
+		mockService := new(MockJobService)
+		jobHandler := Job{mockService}
+		schedule, _ := domain.NewJobSchedule("outbox.purge", "outbox.purge", "@hourly", nil)
+		schedule.SetNextRunAt(time.Date(2024, 1, 1, 13, 0, 0, 0, time.UTC))
+		mockService.On("FindSchedules").Return([]domain.JobSchedule{*schedule}, nil)
+
+		_ = jobHandler.FindSchedules(context)
+
+		assert.Equal(t, http.StatusOK, responseData.Code)
+		assert.JSONEq(t, `[{"name": "outbox.purge", "kind": "outbox.purge", "spec": "@hourly", "payload": {},
+			"next_run_at": "2024-01-01T13:00:00Z"}]`, responseData.Body.String())
+	})
+}
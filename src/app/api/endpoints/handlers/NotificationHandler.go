@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+	"todo/src/app/api/endpoints/dto/request"
+	"todo/src/app/api/endpoints/dto/response"
+	"todo/src/app/api/endpoints/handlers/msgs"
+	"todo/src/core/domain"
+	interfaces "todo/src/core/interfaces/services"
+	"todo/src/core/projecterrors/todoerrors"
+	"todo/src/core/services"
+	"todo/src/infra/postgres"
+)
+
+type Notification struct {
+	service interfaces.INotification
+}
+
+func NewNotificationHandler() *Notification {
+	connectionManager := postgres.NewPostgresConnectionManager()
+	repository := postgres.NewNotificationPostgresRepository(connectionManager)
+	service := services.NewNotificationService(repository)
+	return &Notification{service}
+}
+
+// CreateReminder
+// @ID 			CreateReminder
+// @Summary		Create a reminder
+// @Tags 		Notification
+// @Description Route that sets an email reminder on a task, either at a time in the future (remind_at) or a number of minutes before the due date of the task (minutes_before_due), but not both. A reminder before the due date follows the changes made to it, and is not sent while the task has none.
+// @Description The reminders of finished tasks are skipped, as are the ones turned off by the notification preferences of the account. A reminder that can't be sent is retried a few times before it is marked as failed.
+// @Accept 		json
+// @Produce 	json
+// @Security	bearerAuth
+// @Param 	    userId          path       int                               true    "User ID"    default(1)
+// @Param 	    taskId          path       int                               true    "Task ID"    default(1)
+// @Param 		reminderJson    body 		request.SwaggerReminderRequest    true    "Time of the reminder or minutes before the due date"
+// @Param 	    Idempotency-Key header      string                           false   "Unique key that makes retries of this request return the first response"
+// @Success 	201             {object} 	response.SwaggerIdResponse                 "Reminder successfully registered"
+// @Failure 	400             {object} 	response.SwaggerBadRequestResponse         "The user has made a bad request"
+// @Failure 	401             {object}    response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403             {object}    response.SwaggerForbiddenResponse   	   "The user does not have access to this information"
+// @Failure 	404 		    {object} 	response.SwaggerNotFoundErrorResponse 	   "The task was not found"
+// @Failure 	422 		    {object} 	response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Failure 	500 		    {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/user/{userId}/task/{taskId}/reminder  [post]
+func (h Notification) CreateReminder(ctx echo.Context) error {
+	userId, taskId, _, validationErr := parseReminderParams(ctx, false)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+	var requestData request.Reminder
+	if err := ctx.Bind(&requestData); err != nil {
+		log.Error(err)
+		return writeBadRequestError(ctx, msgs.RequestFormatError)
+	}
+	reminder, validationErr := domain.NewValidatedReminder(-1, taskId, requestData.RemindAt,
+		requestData.MinutesBeforeDue)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+
+	reminderId, err := h.service.CreateReminder(*reminder, userId)
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	responseReturned := map[string]int{"id": reminderId}
+	return writeCreatedResponse(ctx, responseReturned)
+}
+
+// FindReminders
+// @ID 			FindReminders
+// @Summary		List the reminders of a task
+// @Tags 		Notification
+// @Description Route that lists the reminders of a task with their status, which is pending, sent, skipped or failed.
+// @Produce 	json
+// @Security	bearerAuth
+// @Param 	    userId       path       int                  true                  "User ID"    default(1)
+// @Param 	    taskId       path       int                  true                  "Task ID"    default(1)
+// @Success 	200 		 {array} 	response.SwaggerReminderResponse           "Successful request"
+// @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403          {object}   response.SwaggerForbiddenResponse 	       "The user does not have access to this information"
+// @Failure 	422 		 {object} 	response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Failure 	500 		 {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/user/{userId}/task/{taskId}/reminder  [get]
+func (h Notification) FindReminders(ctx echo.Context) error {
+	userId, taskId, _, validationErr := parseReminderParams(ctx, false)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+
+	reminderList, err := h.service.FindReminders(taskId, userId)
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	reminderResponseList := []response.Reminder{}
+	for _, reminder := range reminderList {
+		reminderResponseList = append(reminderResponseList, *response.NewReminder(reminder))
+	}
+	return writeAcceptResponse(ctx, reminderResponseList)
+}
+
+// DeleteReminder
+// @ID 			DeleteReminder
+// @Summary		Delete a reminder
+// @Tags 		Notification
+// @Security	bearerAuth
+// @Param 	    userId          path       int                  true               "User ID"        default(1)
+// @Param 	    taskId          path       int                  true               "Task ID"        default(1)
+// @Param 	    reminderId      path       int                  true               "Reminder ID"    default(1)
+// @Success 	204             {object}    nil                                        "Reminder successfully deleted"
+// @Failure 	401             {object}    response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403             {object}    response.SwaggerForbiddenResponse 	       "The user does not have access to this information"
+// @Failure 	404 		    {object} 	response.SwaggerNotFoundErrorResponse 	   "The user has requested a non-existent resource"
+// @Failure 	422 		    {object} 	response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Failure 	500 		    {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/user/{userId}/task/{taskId}/reminder/{reminderId}  [delete]
+func (h Notification) DeleteReminder(ctx echo.Context) error {
+	userId, taskId, reminderId, validationErr := parseReminderParams(ctx, true)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+
+	if err := h.service.DeleteReminder(reminderId, taskId, userId); err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	return writeNoContentResponse(ctx)
+}
+
+// FindPreferences
+// @ID 			FindNotificationPreferences
+// @Summary		Find the notification preferences
+// @Tags 		Notification
+// @Description Route that returns which reminders are emailed to the account: email turns all of them on or off, scheduled_reminders are the ones set at a time and due_date_reminders the ones set before the due date. All of them are sent by default.
+// @Produce 	json
+// @Security	bearerAuth
+// @Param 	    userId       path       int                  true                  "User ID"    default(1)
+// @Success 	200 		 {object} 	response.SwaggerNotificationPreferencesResponse    "Successful request"
+// @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403          {object}   response.SwaggerForbiddenResponse 	       "The user does not have access to this information"
+// @Failure 	422 		 {object} 	response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Failure 	500 		 {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/user/{userId}/notification/preferences  [get]
+func (h Notification) FindPreferences(ctx echo.Context) error {
+	userId, err := convertToPositiveInteger(ctx.Param("userId"), msgs.UserId)
+	if err != nil {
+		log.Error(err)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.UserId, msgs.ConversionError)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
+	}
+
+	preferences, err := h.service.FindPreferences(userId)
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	return writeAcceptResponse(ctx, response.NewNotificationPreferences(*preferences))
+}
+
+// UpdatePreferences
+// @ID 			UpdateNotificationPreferences
+// @Summary		Update the notification preferences
+// @Tags 		Notification
+// @Description Route that replaces the notification preferences of the account, the ones left out being turned on. The reminders turned off are skipped once they are due rather than kept for later.
+// @Accept 		json
+// @Produce 	json
+// @Security	bearerAuth
+// @Param 	    userId             path       int                                              true    "User ID"    default(1)
+// @Param 		preferencesJson    body 		request.SwaggerNotificationPreferencesRequest    true    "Reminders sent to the account"
+// @Success 	204                {object}    nil                                        "Preferences successfully edited"
+// @Failure 	400                {object} 	response.SwaggerBadRequestResponse         "The user has made a bad request"
+// @Failure 	401                {object}    response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403                {object}    response.SwaggerForbiddenResponse   	   "The user does not have access to this information"
+// @Failure 	422 		       {object} 	response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Failure 	500 		       {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/user/{userId}/notification/preferences  [put]
+func (h Notification) UpdatePreferences(ctx echo.Context) error {
+	userId, err := convertToPositiveInteger(ctx.Param("userId"), msgs.UserId)
+	if err != nil {
+		log.Error(err)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.UserId, msgs.ConversionError)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
+	}
+	var requestData request.NotificationPreferences
+	if err = ctx.Bind(&requestData); err != nil {
+		log.Error(err)
+		return writeBadRequestError(ctx, msgs.RequestFormatError)
+	}
+	preferences := domain.NewNotificationPreferences(turnedOn(requestData.Email),
+		turnedOn(requestData.ScheduledReminders), turnedOn(requestData.DueDateReminders))
+
+	if err = h.service.UpdatePreferences(*preferences, userId); err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	return writeNoContentResponse(ctx)
+}
+
+// parseReminderParams reads the ID of the reminder only when the route has one
+func parseReminderParams(ctx echo.Context, withReminder bool) (int, int, int, *todoerrors.Validation) {
+	invalidFields := todoerrors.InvalidFields{}
+	userId, err := convertToPositiveInteger(ctx.Param("userId"), msgs.UserId)
+	if err != nil {
+		invalidFields.AppendField(msgs.UserId, msgs.ConversionError)
+	}
+	taskId, err := convertToPositiveInteger(ctx.Param("taskId"), msgs.TaskId)
+	if err != nil {
+		invalidFields.AppendField(msgs.TaskId, msgs.ConversionError)
+	}
+	reminderId := 0
+	if withReminder {
+		reminderId, err = convertToPositiveInteger(ctx.Param("reminderId"), msgs.ReminderId)
+		if err != nil {
+			invalidFields.AppendField(msgs.ReminderId, msgs.ConversionError)
+		}
+	}
+	if invalidFields.HasInvalidFields() {
+		return 0, 0, 0, todoerrors.NewValidationError(msgs.InvalidQueryParams, invalidFields)
+	}
+
+	return userId, taskId, reminderId, nil
+}
+
+// turnedOn reads a preference left out as turned on
+func turnedOn(preference *bool) bool {
+	return preference == nil || *preference
+}
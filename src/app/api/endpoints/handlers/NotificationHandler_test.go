@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"testing"
+	"time"
+	"todo/src/core/domain"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+type MockNotificationService struct {
+	mock.Mock
+}
+
+func (m *MockNotificationService) CreateReminder(reminder domain.Reminder, userId int) (int, error) {
+	args := m.Called(reminder, userId)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockNotificationService) DeleteReminder(reminderId, taskId, userId int) error {
+	args := m.Called(reminderId, taskId, userId)
+	return args.Error(0)
+}
+
+func (m *MockNotificationService) FindReminders(taskId, userId int) ([]domain.Reminder, error) {
+	args := m.Called(taskId, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.Reminder), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockNotificationService) FindPreferences(userId int) (*domain.NotificationPreferences, error) {
+	args := m.Called(userId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.NotificationPreferences), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockNotificationService) UpdatePreferences(preferences domain.NotificationPreferences, userId int) error {
+	args := m.Called(preferences, userId)
+	return args.Error(0)
+}
+
+func TestNotification_CreateReminder(t *testing.T) {
+	t.Run("should return 201 with the ID of the reminder set before the due date", func(t *testing.T) {
+		context, responseData := newWebhookContext(http.MethodPost, "/task/5/reminder",
+			`{"minutes_before_due": 60}`, []string{"userId", "taskId"}, []string{"1", "5"})
+		mockService := new(MockNotificationService)
+		notificationHandler := Notification{mockService}
+		mockService.On("CreateReminder", mock.MatchedBy(func(reminder domain.Reminder) bool {
+			return reminder.TaskId() == 5 && reminder.RemindAt() == nil && *reminder.MinutesBeforeDue() == 60
+		}), 1).Return(8, nil)
+
+		_ = notificationHandler.CreateReminder(context)
+
+		assert.Equal(t, http.StatusCreated, responseData.Code)
+		assert.JSONEq(t, `{"id": 8}`, responseData.Body.String())
+	})
+
+	t.Run("should return 422 when the reminder has both a time and minutes before the due date", func(t *testing.T) {
+		context, responseData := newWebhookContext(http.MethodPost, "/task/5/reminder",
+			`{"remind_at": "2099-01-01T09:00:00Z", "minutes_before_due": 60}`, []string{"userId", "taskId"},
+			[]string{"1", "5"})
+		notificationHandler := Notification{new(MockNotificationService)}
+
+		_ = notificationHandler.CreateReminder(context)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, responseData.Code)
+		assert.Contains(t, responseData.Body.String(), "Reminder")
+	})
+
+	t.Run("should return 422 when the time of the reminder has passed", func(t *testing.T) {
+		context, responseData := newWebhookContext(http.MethodPost, "/task/5/reminder",
+			`{"remind_at": "2020-01-01T09:00:00Z"}`, []string{"userId", "taskId"}, []string{"1", "5"})
+		notificationHandler := Notification{new(MockNotificationService)}
+
+		_ = notificationHandler.CreateReminder(context)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, responseData.Code)
+		assert.Contains(t, responseData.Body.String(), "Remind At")
+	})
+
+	t.Run("should return 404 when the task does not exist", func(t *testing.T) {
+		context, responseData := newWebhookContext(http.MethodPost, "/task/9/reminder",
+			`{"minutes_before_due": 0}`, []string{"userId", "taskId"}, []string{"1", "9"})
+		mockService := new(MockNotificationService)
+		notificationHandler := Notification{mockService}
+		mockService.On("CreateReminder", mock.Anything, 1).Return(-1, todoerrors.NewNotFoundError())
+
+		_ = notificationHandler.CreateReminder(context)
+
+		assert.Equal(t, http.StatusNotFound, responseData.Code)
+	})
+}
+
+func TestNotification_FindReminders(t *testing.T) {
+	t.Run("should return the reminders with their status", func(t *testing.T) {
+		context, responseData := newWebhookContext(http.MethodGet, "/task/5/reminder", "",
+			[]string{"userId", "taskId"}, []string{"1", "5"})
+		mockService := new(MockNotificationService)
+		notificationHandler := Notification{mockService}
+		remindAt := time.Date(2030, 1, 1, 9, 0, 0, 0, time.UTC)
+		scheduled := domain.NewReminder(2, 5, &remindAt, nil)
+		minutesBeforeDue := 30
+		failed := domain.NewReminder(3, 5, nil, &minutesBeforeDue)
+		failed.SetDelivery(domain.ReminderFailed, 5, "connection refused")
+		mockService.On("FindReminders", 5, 1).Return([]domain.Reminder{*scheduled, *failed}, nil)
+
+		_ = notificationHandler.FindReminders(context)
+
+		assert.Equal(t, http.StatusOK, responseData.Code)
+		assert.JSONEq(t, `[{"id": 2, "task_id": 5, "remind_at": "2030-01-01T09:00:00Z", "status": "pending",
+			"attempts": 0}, {"id": 3, "task_id": 5, "minutes_before_due": 30, "status": "failed", "attempts": 5,
+			"last_error": "connection refused"}]`, responseData.Body.String())
+	})
+}
+
+func TestNotification_UpdatePreferences(t *testing.T) {
+	t.Run("should turn on the preferences left out", func(t *testing.T) {
+		context, responseData := newWebhookContext(http.MethodPut, "/notification/preferences",
+			`{"due_date_reminders": false}`, []string{"userId"}, []string{"1"})
+		mockService := new(MockNotificationService)
+		notificationHandler := Notification{mockService}
+		mockService.On("UpdatePreferences", *domain.NewNotificationPreferences(true, true, false), 1).Return(nil)
+
+		_ = notificationHandler.UpdatePreferences(context)
+
+		assert.Equal(t, http.StatusNoContent, responseData.Code)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestNotification_FindPreferences(t *testing.T) {
+	t.Run("should return the preferences of the account", func(t *testing.T) {
+		context, responseData := newWebhookContext(http.MethodGet, "/notification/preferences", "",
+			[]string{"userId"}, []string{"1"})
+		mockService := new(MockNotificationService)
+		notificationHandler := Notification{mockService}
+		mockService.On("FindPreferences", 1).Return(domain.NewNotificationPreferences(false, true, true), nil)
+
+		_ = notificationHandler.FindPreferences(context)
+
+		assert.Equal(t, http.StatusOK, responseData.Code)
+		assert.JSONEq(t, `{"email": false, "scheduled_reminders": true, "due_date_reminders": true}`,
+			responseData.Body.String())
+	})
+}
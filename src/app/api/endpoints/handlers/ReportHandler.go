@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+	"net/http"
+	"time"
+	"todo/src/app/api/endpoints/handlers/msgs"
+	"todo/src/core/domain"
+	eventbus "todo/src/core/interfaces/events"
+	interfaces "todo/src/core/interfaces/services"
+	"todo/src/core/pdf"
+	"todo/src/core/projecterrors/todoerrors"
+	"todo/src/core/services"
+	"todo/src/infra/postgres"
+)
+
+const (
+	mimeApplicationPdf       = "application/pdf"
+	taskReportFileName       = "tasks.pdf"
+	collectionReportFileName = "collection-%d.pdf"
+)
+
+// Report renders printable PDF reports of the tasks of the user
+type Report struct {
+	taskService       interfaces.ITask
+	collectionService interfaces.ICollection
+}
+
+func NewReportHandler(emitter eventbus.IEmitter) *Report {
+	connectionManager := postgres.NewPostgresConnectionManager()
+	undoRepository := postgres.NewUndoPostgresRepository(connectionManager)
+	taskRepository := postgres.NewTaskPostgresRepository(connectionManager)
+	collectionRepository := postgres.NewCollectionPostgresRepository(connectionManager)
+	return &Report{
+		taskService:       services.NewTaskService(taskRepository, undoRepository, emitter),
+		collectionService: services.NewCollectionService(collectionRepository, undoRepository, emitter),
+	}
+}
+
+// Tasks
+// @ID 			TaskReport
+// @Summary		Report the tasks as PDF
+// @Tags 		Report
+// @Description Route that renders the tasks of the user as a printable PDF, grouped by collection, with the finished ones struck through. The header has the account, the date and the counts of finished and open tasks. The tasks can be narrowed with the same filters as the task list, and the report must have at most 2000 tasks.
+// @Produce 	application/pdf
+// @Security	bearerAuth
+// @Param 	    userId       path       int                  true                  "User ID"    default(1)
+// @Param 		finished    	query     bool                false                  "Only tasks with this completion status"
+// @Param 		collection_id   query     int                 false                  "Only tasks of this collection"
+// @Param 		search    		query     string              false                  "Only tasks whose description contains this text"
+// @Param 		created_from    query     string              false                  "Only tasks created from this date (YYYY-MM-DD or RFC 3339)"
+// @Param 		created_to    	query     string              false                  "Only tasks created until this date (YYYY-MM-DD or RFC 3339)"
+// @Param 		filter    		query     string              false                  "Filter expression, e.g. (tag:work or tag:urgent) and not finished and due < +7d"
+// @Success 	200 		 {string} 	string                                     "Successful request"
+// @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403          {object}   response.SwaggerForbiddenResponse 	       "The user does not have access to this information"
+// @Failure 	422 		 {object} 	response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Failure 	500 		 {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/user/{userId}/report/tasks.pdf  [get]
+func (h Report) Tasks(ctx echo.Context) error {
+	userId, err := convertToPositiveInteger(ctx.Param("userId"), msgs.UserId)
+	if err != nil {
+		log.Error(err)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.UserId, msgs.ConversionError)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
+	}
+	filter, validationErr := parseTaskFilter(ctx)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+
+	tasks, err := h.findTasks(userId, *filter)
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+	if len(tasks) > pdf.MaxReportTasks {
+		message := fmt.Sprintf(msgs.TooManyReportTasks, pdf.MaxReportTasks)
+		log.Error(message)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.Report, message)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(message, invalidFields))
+	}
+
+	report := pdf.NewReport(msgs.TaskReport, reportAccount(ctx, userId), time.Now().UTC())
+	report.AddTasks(tasks)
+	return writeReport(ctx, report, taskReportFileName)
+}
+
+// Collection
+// @ID 			CollectionReport
+// @Summary		Report a collection as PDF
+// @Tags 		Report
+// @Description Route that renders the tasks of the collection as a printable PDF, with the finished ones struck through. The header has the account, the date and the counts of finished and open tasks.
+// @Produce 	application/pdf
+// @Security	bearerAuth
+// @Param 	    userId       path       int                  true                  "User ID"    default(1)
+// @Param 	    collectionId path       int                  true                  "Collection ID"
+// @Success 	200 		 {string} 	string                                     "Successful request"
+// @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403          {object}   response.SwaggerForbiddenResponse 	       "The user does not have access to this information"
+// @Failure 	404 		 {object} 	response.SwaggerNotFoundErrorResponse      "The collection was not found"
+// @Failure 	422 		 {object} 	response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Failure 	500 		 {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/user/{userId}/report/collection/{collectionId}/tasks.pdf  [get]
+func (h Report) Collection(ctx echo.Context) error {
+	userId, err := convertToPositiveInteger(ctx.Param("userId"), msgs.UserId)
+	if err != nil {
+		log.Error(err)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.UserId, msgs.ConversionError)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
+	}
+	collectionId, err := convertToPositiveInteger(ctx.Param("collectionId"), msgs.CollectionId)
+	if err != nil {
+		log.Error(err)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.CollectionId, msgs.ConversionError)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
+	}
+
+	collection, err := h.collectionService.FindById(collectionId, userId, *domain.NewExpansion(domain.ExpandTasks))
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	report := pdf.NewReport(collection.Name(), reportAccount(ctx, userId), time.Now().UTC())
+	report.AddCollection(*collection)
+	return writeReport(ctx, report, fmt.Sprintf(collectionReportFileName, collectionId))
+}
+
+// findTasks reads the pages of the filtered tasks until there are none left or the report would be too large, in
+// which case one task more than the limit is returned
+func (h Report) findTasks(userId int, filter domain.TaskFilter) ([]domain.Task, error) {
+	var tasks []domain.Task
+	var cursor *domain.Cursor
+	for {
+		pagination := domain.NewKeysetPagination(domain.MaxPageLimit, "id", domain.SortAscending, cursor)
+		page, err := h.taskService.FindPage(userId, filter, *pagination)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, page.Tasks()...)
+		cursor = page.Next()
+		if cursor == nil || len(tasks) > pdf.MaxReportTasks {
+			return tasks, nil
+		}
+	}
+}
+
+// reportAccount names the account by the email the middlewares found, or else by the user ID
+func reportAccount(ctx echo.Context, userId int) string {
+	if email, ok := ctx.Get(AccountEmailKey).(string); ok && email != "" {
+		return email
+	}
+	return fmt.Sprintf(msgs.UserAccount, userId)
+}
+
+// writeReport renders the whole report before answering, so that a rendering error is still reported with its status
+func writeReport(ctx echo.Context, report *pdf.Report, fileName string) error {
+	var output bytes.Buffer
+	if err := report.Write(&output); err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("inline; filename=%q", fileName))
+	return ctx.Blob(http.StatusOK, mimeApplicationPdf, output.Bytes())
+}
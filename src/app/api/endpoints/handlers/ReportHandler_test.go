@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"todo/src/core/domain"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+func newReportContext(target string, names, values []string) (echo.Context, *httptest.ResponseRecorder) {
+	requestData := httptest.NewRequest(http.MethodGet, target, nil)
+	responseData := httptest.NewRecorder()
+	context := echo.New().NewContext(requestData, responseData)
+	context.SetParamNames(names...)
+	context.SetParamValues(values...)
+
+	return context, responseData
+}
+
+func TestReport_Tasks(t *testing.T) {
+	t.Run("should read every page of the filtered tasks into the PDF", func(t *testing.T) {
+		context, responseData := newReportContext("/user/1/report/tasks.pdf?finished=false", []string{"userId"},
+			[]string{"1"})
+		context.Set(AccountEmailKey, "example@example.com")
+		mockTaskService := new(MockTaskService)
+		reportHandler := Report{taskService: mockTaskService}
+		sport := domain.NewCollection(3, "Sport")
+		nextCursor := domain.NewCursor("id", "asc", 4, time.Time{}, false)
+		mockTaskService.On("FindPage", 1, mock.MatchedBy(func(filter domain.TaskFilter) bool {
+			return filter.Finished() != nil && !*filter.Finished()
+		}), mock.MatchedBy(func(pagination domain.KeysetPagination) bool {
+			return pagination.Cursor() == nil && pagination.Limit() == 100
+		})).Return(domain.NewTaskPage([]domain.Task{*domain.NewTask(4, "Run", false, sport)}, nextCursor, nil), nil)
+		mockTaskService.On("FindPage", 1, mock.Anything, mock.MatchedBy(func(pagination domain.KeysetPagination) bool {
+			return pagination.Cursor() != nil && pagination.Cursor().Id() == 4
+		})).Return(domain.NewTaskPage([]domain.Task{*domain.NewTask(7, "Swim", false, sport)}, nil, nil), nil)
+
+		_ = reportHandler.Tasks(context)
+
+		body := responseData.Body.String()
+		assert.Equal(t, http.StatusOK, responseData.Code)
+		assert.Equal(t, "application/pdf", responseData.Header().Get("Content-Type"))
+		assert.Equal(t, `inline; filename="tasks.pdf"`, responseData.Header().Get("Content-Disposition"))
+		assert.True(t, strings.HasPrefix(body, "%PDF-"))
+		assert.Contains(t, body, "(Account: example@example.com)")
+		assert.Contains(t, body, "(2 tasks: 0 finished, 2 open)")
+		assert.Contains(t, body, "(Swim)")
+	})
+
+	t.Run("should return 422 when the filter is invalid", func(t *testing.T) {
+		context, responseData := newReportContext("/user/1/report/tasks.pdf?finished=maybe", []string{"userId"},
+			[]string{"1"})
+		mockTaskService := new(MockTaskService)
+		reportHandler := Report{taskService: mockTaskService}
+
+		_ = reportHandler.Tasks(context)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, responseData.Code)
+		mockTaskService.AssertNotCalled(t, "FindPage", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestReport_Collection(t *testing.T) {
+	t.Run("should name the report after the collection and the account after the user ID", func(t *testing.T) {
+		context, responseData := newReportContext("/user/1/report/collection/3/tasks.pdf",
+			[]string{"userId", "collectionId"}, []string{"1", "3"})
+		mockCollectionService := new(MockCollectionService)
+		reportHandler := Report{collectionService: mockCollectionService}
+		collection := domain.NewCollection(3, "Sport")
+		collection.SetTasks([]domain.Task{*domain.NewTask(4, "Run", true, collection)})
+		mockCollectionService.On("FindById", 3, 1, *domain.NewExpansion(domain.ExpandTasks)).Return(collection, nil)
+
+		_ = reportHandler.Collection(context)
+
+		body := responseData.Body.String()
+		assert.Equal(t, http.StatusOK, responseData.Code)
+		assert.Equal(t, `inline; filename="collection-3.pdf"`, responseData.Header().Get("Content-Disposition"))
+		assert.Contains(t, body, "/Title (Sport)")
+		assert.Contains(t, body, "(Account: User 1)")
+		assert.Contains(t, body, "(1 of 1 finished)")
+	})
+
+	t.Run("should return 404 when the collection does not exist", func(t *testing.T) {
+		context, responseData := newReportContext("/user/1/report/collection/3/tasks.pdf",
+			[]string{"userId", "collectionId"}, []string{"1", "3"})
+		mockCollectionService := new(MockCollectionService)
+		reportHandler := Report{collectionService: mockCollectionService}
+		mockCollectionService.On("FindById", 3, 1, *domain.NewExpansion(domain.ExpandTasks)).
+			Return(nil, todoerrors.NewNotFoundError())
+
+		_ = reportHandler.Collection(context)
+
+		assert.Equal(t, http.StatusNotFound, responseData.Code)
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"todo/src/app/api/endpoints/dto/response"
 	"todo/src/app/api/endpoints/handlers/msgs"
 	"todo/src/core/domain"
+	eventbus "todo/src/core/interfaces/events"
 	interfaces "todo/src/core/interfaces/services"
 	"todo/src/core/projecterrors/todoerrors"
 	"todo/src/core/services"
@@ -17,10 +18,11 @@ type Task struct {
 	service interfaces.ITask
 }
 
-func NewTaskHandler() *Task {
+func NewTaskHandler(emitter eventbus.IEmitter) *Task {
 	connectionManager := postgres.NewPostgresConnectionManager()
 	repository := postgres.NewTaskPostgresRepository(connectionManager)
-	service := services.NewTaskService(repository)
+	undoRepository := postgres.NewUndoPostgresRepository(connectionManager)
+	service := services.NewTaskService(repository, undoRepository, emitter)
 	return &Task{service}
 }
 
@@ -33,16 +35,20 @@ func NewTaskHandler() *Task {
 // @Description |---------------|--------|-------------|---------------------------------------------------|
 // @Description | description   | string |             | Task description                                  |
 // @Description | finished      |  bool  |             | If the task has been completed                    |
+// @Description | due_date      | string |             | Date by which the task is due (RFC 3339)          |
+// @Description | tags          | array  |             | Tags of the task, without spaces                  |
 // @Description | collection_id |  int   |             | ID of the collection to which the task is related |
 // @Accept 		json
 // @Produce 	json
 // @Security	bearerAuth
 // @Param 	    userId       path       int                              true      "User ID"    default(1)
 // @Param 		authJson 	 body 		request.SwaggerTaskRequest       true      "JSON responsible for sending all task registration data to the database"
+// @Param 	    Idempotency-Key header      string             false                   "Unique key that makes retries of this request return the first response"
 // @Success 	201 		 {object} 	response.SwaggerIdResponse                 "Task successfully registered"
 // @Failure 	400 		 {object} 	response.SwaggerValidationErrorResponse    "The user has made a bad request"
 // @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
 // @Failure 	403          {object}   response.SwaggerForbiddenResponse 	       "The user does not have access to this information"
+// @Failure 	409 		 {object} 	response.SwaggerConflictErrorResponse      "A request with the same idempotency key is still being processed"
 // @Failure 	422 		 {object} 	response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
 // @Failure 	500 		 {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
 // @Router 		/user/{userId}/task  [post]
@@ -59,13 +65,11 @@ func (h Task) Create(ctx echo.Context) error {
 		log.Error(err)
 		return writeBadRequestError(ctx, msgs.RequestFormatError)
 	}
-	collection := domain.NewCollection(requestData.CollectionId, "")
-	task := domain.NewTask(
-		-1,
-		requestData.Description,
-		requestData.Finished,
-		collection,
-	)
+	task, validationErr := newTaskFromRequest(-1, requestData)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
 
 	userIdCreated, err := h.service.Create(*task, userId)
 	if err != nil {
@@ -86,6 +90,8 @@ func (h Task) Create(ctx echo.Context) error {
 // @Description |---------------|--------|-------------|---------------------------------------------------|
 // @Description | description   | string |             | Task description                                  |
 // @Description | finished      |  bool  |             | If the task has been completed                    |
+// @Description | due_date      | string |             | Date by which the task is due (RFC 3339)          |
+// @Description | tags          | array  |             | Tags of the task, without spaces                  |
 // @Description | collection_id |  int   |             | ID of the collection to which the task is related |
 // @Accept 		json
 // @Produce 	json
@@ -93,12 +99,15 @@ func (h Task) Create(ctx echo.Context) error {
 // @Param 	    userId      path        int                             true       "User ID"    default(1)
 // @Param 	    taskId      path        int                             true       "Task ID"    default(1)
 // @Param 		authJson    body 	    request.SwaggerTaskRequest      true       "JSON responsible for sending the data needed to update the task in the database"
+// @Param 	    If-Match        header      string             false                   "ETag of the last read version of the task"
 // @Success 	204         {object}    nil 									   "Task successfully edited"
+// @Header 		204         {string}    Undo-Token                                 "Token that reverses the change"
 // @Failure 	400         {object}    response.SwaggerValidationErrorResponse    "The user has made a bad request"
 // @Failure 	401         {object}    response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
 // @Failure 	403         {object}    response.SwaggerForbiddenResponse 	       "The user does not have access to this information"
 // @Failure 	404         {object}    response.SwaggerNotFoundErrorResponse 	   "The user has requested a non-existent resource"
 // @Failure 	422         {object}    response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Failure 	412             {object}    response.SwaggerPreconditionFailedResponse "The task has been changed since it was last read"
 // @Failure 	500         {object}    response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
 // @Router 		/user/{userId}/task/{taskId}  [put]
 func (h Task) Update(ctx echo.Context) error {
@@ -121,20 +130,108 @@ func (h Task) Update(ctx echo.Context) error {
 		log.Error(err)
 		return writeBadRequestError(ctx, msgs.RequestFormatError)
 	}
-	collection := domain.NewCollection(requestData.CollectionId, "")
-	task := domain.NewTask(
-		taskId,
-		requestData.Description,
-		requestData.Finished,
-		collection,
-	)
+	task, validationErr := newTaskFromRequest(taskId, requestData)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+	version, err := ifMatchVersion(ctx, h.currentVersion(taskId, userId))
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+	task.SetVersion(version)
+
+	undoToken, err := h.service.Update(*task, userId)
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	setUndoToken(ctx, undoToken)
+	return writeNoContentResponse(ctx)
+}
+
+// Patch
+// @ID 			PatchTask
+// @Summary		Partially update a task
+// @Tags 		Task
+// @Description Route that allows editing only some data of a task, keeping the others as they are stored. The body can be a JSON Merge Patch (RFC 7396) with the fields to change, or a JSON Patch (RFC 6902) with the operations to apply on the task document below:
+// @Description |      Name     |  Type  |                    Description                    |
+// @Description |---------------|--------|---------------------------------------------------|
+// @Description | description   | string | Task description                                  |
+// @Description | finished      |  bool  | If the task has been completed                    |
+// @Description | due_date      | string | Date by which the task is due (RFC 3339)          |
+// @Description | tags          | array  | Tags of the task, without spaces                  |
+// @Description | collection_id |  int   | ID of the collection to which the task is related |
+// @Accept 		application/merge-patch+json
+// @Accept 		application/json-patch+json
+// @Produce 	json
+// @Security	bearerAuth
+// @Param 	    userId      path        int                             true       "User ID"    default(1)
+// @Param 	    taskId      path        int                             true       "Task ID"    default(1)
+// @Param 		patchJson   body 	    object                          true       "Merge patch, e.g. {\"finished\": true}, or JSON Patch, e.g. [{\"op\": \"add\", \"path\": \"/tags/-\", \"value\": \"urgent\"}]"
+// @Param 	    If-Match        header      string             false                   "ETag of the last read version of the task"
+// @Success 	204         {object}    nil 									   "Task successfully edited"
+// @Header 		204         {string}    Undo-Token                                 "Token that reverses the change"
+// @Failure 	400         {object}    response.SwaggerBadRequestResponse         "The patch document is malformed"
+// @Failure 	401         {object}    response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403         {object}    response.SwaggerForbiddenResponse 	       "The user does not have access to this information"
+// @Failure 	404         {object}    response.SwaggerNotFoundErrorResponse 	   "The user has requested a non-existent resource"
+// @Failure 	415         {object}    response.SwaggerGenericErrorResponse 	   "The patch format is not supported"
+// @Failure 	422         {object}    response.SwaggerValidationErrorResponse    "The patch could not be applied or the patched task is not valid"
+// @Failure 	412             {object}    response.SwaggerPreconditionFailedResponse "The task has been changed since it was last read"
+// @Failure 	500         {object}    response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/user/{userId}/task/{taskId}  [patch]
+func (h Task) Patch(ctx echo.Context) error {
+	userId, err := convertToPositiveInteger(ctx.Param("userId"), msgs.UserId)
+	if err != nil {
+		log.Error(err)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.UserId, msgs.ConversionError)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
+	}
+	taskId, err := convertToPositiveInteger(ctx.Param("taskId"), msgs.TaskId)
+	if err != nil {
+		log.Error(err)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.TaskId, msgs.ConversionError)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
+	}
+
+	storedTask, err := h.service.FindById(taskId, userId)
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+	expectedVersion, err := ifMatchVersion(ctx, func() (int, error) { return storedTask.Version(), nil })
+	if err == nil && expectedVersion != 0 && expectedVersion != storedTask.Version() {
+		err = todoerrors.NewPreconditionFailedError()
+	}
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+	var requestData request.Task
+	if patchErr := applyPatchDocument(ctx, newTaskRequest(*storedTask), &requestData); patchErr != nil {
+		log.Error(patchErr)
+		return writePatchDocumentError(ctx, patchErr)
+	}
+	task, validationErr := newTaskFromRequest(taskId, requestData)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+	// The patch was applied on the stored version, so it must not overwrite a concurrent change
+	task.SetVersion(storedTask.Version())
 
-	err = h.service.Update(*task, userId)
+	undoToken, err := h.service.Update(*task, userId)
 	if err != nil {
 		log.Error(err)
 		return handleServiceErrors(ctx, err)
 	}
 
+	setUndoToken(ctx, undoToken)
 	return writeNoContentResponse(ctx)
 }
 
@@ -146,12 +243,15 @@ func (h Task) Update(ctx echo.Context) error {
 // @Security	bearerAuth
 // @Param 	    userId       path       int                  true                  "User ID"    default(1)
 // @Param 	    taskId       path       int                  true                  "Task ID"    default(1)
+// @Param 	    If-Match        header      string             false                   "ETag of the last read version of the task"
 // @Success 	204 		 {object} 	nil                                        "Task successfully deleted"
+// @Header 		204 		 {string} 	Undo-Token                                 "Token that restores the task"
 // @Failure 	400 		 {object} 	response.SwaggerValidationErrorResponse    "The user has made a bad request"
 // @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
 // @Failure 	403          {object}   response.SwaggerForbiddenResponse 	       "The user does not have access to this information"
 // @Failure 	404 		 {object} 	response.SwaggerNotFoundErrorResponse 	   "The user has requested a non-existent resource"
 // @Failure 	422 		 {object} 	response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Failure 	412             {object}    response.SwaggerPreconditionFailedResponse "The task has been changed since it was last read"
 // @Failure 	500 		 {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
 // @Router 		/user/{userId}/task/{taskId}  [delete]
 func (h Task) Delete(ctx echo.Context) error {
@@ -170,24 +270,169 @@ func (h Task) Delete(ctx echo.Context) error {
 		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
 	}
 
-	err = h.service.Delete(taskId, userId)
+	version, err := ifMatchVersion(ctx, h.currentVersion(taskId, userId))
 	if err != nil {
 		log.Error(err)
 		return handleServiceErrors(ctx, err)
 	}
 
+	undoToken, err := h.service.Delete(taskId, userId, version)
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	setUndoToken(ctx, undoToken)
 	return writeNoContentResponse(ctx)
 }
 
+// Batch
+// @ID 			BatchTasks
+// @Summary		Create, update and delete many tasks at once
+// @Tags 		Task
+// @Description Route that allows applying up to 100 operations on the user tasks in a single request. Each operation has the following data:
+// @Description |  Name  |  Type  |   Required  |                          Description                           |
+// @Description |--------|--------|-------------|----------------------------------------------------------------|
+// @Description | op     | string |      X      | Operation type: create, update or delete                       |
+// @Description | id     |  int   |             | ID of the task, required by update and delete                  |
+// @Description | version |  int  |             | Version the stored task must have, as in the If-Match header   |
+// @Description | task   | object |             | Task data, as in the task registration, required by create and update |
+// @Description In the atomic mode (default) the operations run in a single transaction and none of them is applied if one fails, the others being reported with the status 424. In the best_effort mode each operation is applied on its own. The response has the status of every operation, in the same order as the request.
+// @Accept 		json
+// @Produce 	json
+// @Security	bearerAuth
+// @Param 	    userId       path       int                              true      "User ID"    default(1)
+// @Param 		batchJson 	 body 		request.SwaggerTaskBatchRequest  true      "JSON with the batch mode (atomic or best_effort) and the operations"
+// @Param 	    Idempotency-Key header      string             false                   "Unique key that makes retries of this request return the first response"
+// @Success 	200 		 {object} 	response.SwaggerTaskBatchResponse          "All operations were applied"
+// @Success 	207 		 {object} 	response.SwaggerTaskBatchResponse          "Some operations have failed"
+// @Header 		200,207 	 {string} 	Undo-Token                                 "Token that reverses the applied operations"
+// @Failure 	400 		 {object} 	response.SwaggerValidationErrorResponse    "The user has made a bad request"
+// @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403          {object}   response.SwaggerForbiddenResponse 	       "The user does not have access to this information"
+// @Failure 	409 		 {object} 	response.SwaggerConflictErrorResponse      "A request with the same idempotency key is still being processed"
+// @Failure 	422 		 {object} 	response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Failure 	500 		 {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/user/{userId}/task/batch  [post]
+func (h Task) Batch(ctx echo.Context) error {
+	userId, err := convertToPositiveInteger(ctx.Param("userId"), msgs.UserId)
+	if err != nil {
+		log.Error(err)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.UserId, msgs.ConversionError)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
+	}
+	var requestData request.TaskBatch
+	if err = ctx.Bind(&requestData); err != nil {
+		log.Error(err)
+		return writeBadRequestError(ctx, msgs.RequestFormatError)
+	}
+	batch, validationErr := parseTaskBatch(requestData)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+
+	batchResult, err := h.service.Batch(*batch, userId)
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	status, batchResponse := newTaskBatchResponse(*batch, *batchResult)
+	setUndoToken(ctx, batchResult.UndoToken())
+	return ctx.JSON(status, batchResponse)
+}
+
+// FindById
+// @ID 			FindTaskById
+// @Summary 	Search a task by ID
+// @Tags 		Task
+// @Description Route that allows searching a user task registered in the system, with its tags and the ID of its collection
+// @Produce		json
+// @Security	bearerAuth
+// @Param 	    userId      path        int                true                    "User ID"    default(1)
+// @Param 	    taskId      path        int                true                    "Task ID"    default(1)
+// @Param 		expand    	query     	string             false                   "Related resources to embed, collection"
+// @Param 	    If-None-Match   header      string             false                   "ETag of the cached task, answered with 304 when it has not changed"
+// @Success 	200         {object}    response.SwaggerTaskResponse               "Successful request"
+// @Header 		200             {string}    ETag                                       "Version of the task, weak when the collection is embedded"
+// @Success 	304             {object}    nil                                        "The task has not changed"
+// @Failure 	400         {object}    response.SwaggerValidationErrorResponse    "The user has made a bad request"
+// @Failure 	401         {object}    response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403         {object}    response.SwaggerForbiddenResponse 	       "The user does not have access to this information"
+// @Failure 	404         {object}    response.SwaggerNotFoundErrorResponse 	   "The user has requested a non-existent resource"
+// @Failure 	422         {object}    response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Failure 	500         {object}    response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/user/{userId}/task/{taskId}  [get]
+func (h Task) FindById(ctx echo.Context) error {
+	userId, err := convertToPositiveInteger(ctx.Param("userId"), msgs.UserId)
+	if err != nil {
+		log.Error(err)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.UserId, msgs.ConversionError)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
+	}
+	taskId, err := convertToPositiveInteger(ctx.Param("taskId"), msgs.TaskId)
+	if err != nil {
+		log.Error(err)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.TaskId, msgs.ConversionError)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
+	}
+	expansion, validationErr := parseExpansion(ctx, domain.TaskExpansionFields)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+
+	task, err := h.service.FindById(taskId, userId)
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	taskResponse := response.NewExpandedTask(*task, *expansion)
+	etag := entityTag(task.Version())
+	if len(expansion.Fields()) > 0 {
+		etag = expandedEntityTag(task.Version(), taskResponse)
+	}
+	return writeNotModifiedOrAccept(ctx, etag, taskResponse)
+}
+
 // FindAll
 // @ID 			FindAllTasks
 // @Summary 	Lists all user tasks
 // @Tags 		Task
-// @Description Route that allows searching all user tasks in the system
+// @Description Route that allows searching all user tasks in the system. Besides the simple filters, the filter parameter accepts an expression combining the terms below with and, or, not and parentheses (terms side by side are joined with and):
+// @Description |    Term    |     Operators     |                       Values                        |
+// @Description |------------|-------------------|-----------------------------------------------------|
+// @Description | text       | : !=              | Text contained in the description                   |
+// @Description | finished   | : !=              | true or false, alone it means finished:true         |
+// @Description | tag        | : !=              | Tag name                                            |
+// @Description | collection | : !=              | Collection ID or name                               |
+// @Description | due        | : != < <= > >=    | Date, RFC 3339 time, now, today, +7d, -12h or none  |
+// @Description | created    | : != < <= > >=    | Date, RFC 3339 time, now, today, +7d, -12h          |
 // @Produce		json
 // @Security	bearerAuth
-// @Param 		userId    path      int                 true                   "User ID"    default(1)
-// @Success 	200       {array} 	response.SwaggerTaskResponse               "Successful request"
+// @Param 		userId    		path      int                 true                   "User ID"    default(1)
+// @Param 		limit    		query     int                 false                  "Maximum number of tasks returned (1-100)"    default(20)
+// @Param 		offset    		query     int                 false                  "Number of tasks skipped"
+// @Param 		page    		query     int                 false                  "Page number, used when offset is not informed"
+// @Param 		size    		query     int                 false                  "Page size, used when limit is not informed"
+// @Param 		sort    		query     string              false                  "Sort field"    Enums(id, description, finished, collection_id, collection_name, created_at, due_date)
+// @Param 		order    		query     string              false                  "Sort direction"    Enums(asc, desc)
+// @Param 		finished    	query     bool                false                  "Only tasks with this completion status"
+// @Param 		collection_id   query     int                 false                  "Only tasks of this collection"
+// @Param 		search    		query     string              false                  "Only tasks whose description contains this text"
+// @Param 		filter    		query     string              false                  "Filter expression, e.g. (tag:work or tag:urgent) and not finished and due < +7d"
+// @Param 		created_from    query     string              false                  "Only tasks created from this date (YYYY-MM-DD or RFC 3339)"
+// @Param 		created_to    	query     string              false                  "Only tasks created until this date (YYYY-MM-DD or RFC 3339)"
+// @Success 	200       		{array} 	response.SwaggerTaskResponse           "Successful request"
+// @Param 		cursor    		query     string              false                  "Enables cursor pagination; empty for the first page, then the next or prev value of the previous response"
+// @Success 	200       		{object} 	response.SwaggerTaskPageResponse       "Successful request with cursor pagination"
+// @Header 		200       		{integer} 	X-Total-Count                          "Total number of tasks matching the filters"
+// @Header 		200       		{string} 	Link                                   "Links to the first, previous, next and last pages"
 // @Failure 	400       {object} 	response.SwaggerValidationErrorResponse    "The user has made a bad request"
 // @Failure 	401       {object}  response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
 // @Failure 	403       {object} 	response.SwaggerForbiddenResponse 	       "The user does not have access to this information"
@@ -204,7 +449,21 @@ func (h Task) FindAll(ctx echo.Context) error {
 		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
 	}
 
-	taskList, err := h.service.FindAll(userId)
+	filter, validationErr := parseTaskFilter(ctx)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+	if isCursorPagination(ctx) {
+		return h.findPage(ctx, userId, *filter)
+	}
+	pagination, validationErr := parsePagination(ctx, domain.TaskSortFields)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+
+	taskList, total, err := h.service.FindAll(userId, *filter, *pagination)
 	if err != nil {
 		log.Error(err)
 		return handleServiceErrors(ctx, err)
@@ -214,6 +473,7 @@ func (h Task) FindAll(ctx echo.Context) error {
 	for _, task := range taskList {
 		taskResponseList = append(taskResponseList, *response.NewTask(task))
 	}
+	setPaginationHeaders(ctx, *pagination, total)
 	return writeAcceptResponse(ctx, taskResponseList)
 }
 
@@ -226,7 +486,22 @@ func (h Task) FindAll(ctx echo.Context) error {
 // @Security	bearerAuth
 // @Param 	    userId          path        int                true                    "User ID"          default(1)
 // @Param 	    collectionId    path        int                true                    "Collection ID"    default(1)
-// @Success 	200             {object}    response.SwaggerTaskResponse               "Successful request"
+// @Param 		limit    		query     	int                false                   "Maximum number of tasks returned (1-100)"    default(20)
+// @Param 		offset    		query     	int                false                   "Number of tasks skipped"
+// @Param 		page    		query     	int                false                   "Page number, used when offset is not informed"
+// @Param 		size    		query     	int                false                   "Page size, used when limit is not informed"
+// @Param 		sort    		query     	string             false                   "Sort field"    Enums(id, description, finished, collection_id, collection_name, created_at, due_date)
+// @Param 		order    		query     	string             false                   "Sort direction"    Enums(asc, desc)
+// @Param 		finished    	query     	bool               false                   "Only tasks with this completion status"
+// @Param 		search    		query     	string             false                   "Only tasks whose description contains this text"
+// @Param 		filter    		query     	string             false                   "Filter expression, e.g. (tag:work or tag:urgent) and not finished and due < +7d"
+// @Param 		created_from    query     	string             false                   "Only tasks created from this date (YYYY-MM-DD or RFC 3339)"
+// @Param 		created_to    	query     	string             false                   "Only tasks created until this date (YYYY-MM-DD or RFC 3339)"
+// @Success 	200             {array}     response.SwaggerTaskResponse               "Successful request"
+// @Param 		cursor    		query     	string             false                   "Enables cursor pagination; empty for the first page, then the next or prev value of the previous response"
+// @Success 	200             {object}    response.SwaggerTaskPageResponse           "Successful request with cursor pagination"
+// @Header 		200       		{integer} 	X-Total-Count                              "Total number of tasks matching the filters"
+// @Header 		200       		{string} 	Link                                       "Links to the first, previous, next and last pages"
 // @Failure 	400             {object}    response.SwaggerValidationErrorResponse    "The user has made a bad request"
 // @Failure 	401             {object}    response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
 // @Failure 	403             {object}    response.SwaggerForbiddenResponse   	   "The user does not have access to this information"
@@ -250,7 +525,22 @@ func (h Task) FindByCollectionId(ctx echo.Context) error {
 		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
 	}
 
-	taskList, err := h.service.FindByCollectionId(collectionId, userId)
+	filter, validationErr := parseTaskFilter(ctx)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+	if isCursorPagination(ctx) {
+		filter.SetCollectionId(collectionId)
+		return h.findPage(ctx, userId, *filter)
+	}
+	pagination, validationErr := parsePagination(ctx, domain.TaskSortFields)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+
+	taskList, total, err := h.service.FindByCollectionId(collectionId, userId, *filter, *pagination)
 	if err != nil {
 		log.Error(err)
 		return handleServiceErrors(ctx, err)
@@ -260,5 +550,67 @@ func (h Task) FindByCollectionId(ctx echo.Context) error {
 	for _, task := range taskList {
 		taskResponseList = append(taskResponseList, *response.NewTask(task))
 	}
+	setPaginationHeaders(ctx, *pagination, total)
 	return writeAcceptResponse(ctx, taskResponseList)
 }
+
+func (h Task) findPage(ctx echo.Context, userId int, filter domain.TaskFilter) error {
+	pagination, validationErr := parseKeysetPagination(ctx, userId)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+
+	page, err := h.service.FindPage(userId, filter, *pagination)
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	pageResponse := response.NewTaskPage(
+		page.Tasks(),
+		encodeCursor(page.Next(), userId),
+		encodeCursor(page.Previous(), userId),
+	)
+	return writeAcceptResponse(ctx, pageResponse)
+}
+
+// currentVersion reads the version of the stored task, used when the If-Match header lists several entity tags
+func (h Task) currentVersion(taskId, userId int) func() (int, error) {
+	return func() (int, error) {
+		task, err := h.service.FindById(taskId, userId)
+		if err != nil {
+			return -1, err
+		}
+		return task.Version(), nil
+	}
+}
+
+func newTaskFromRequest(taskId int, requestData request.Task) (*domain.Task, *todoerrors.Validation) {
+	tags, validationErr := domain.NewValidatedTags(requestData.Tags)
+	if validationErr != nil {
+		return nil, validationErr
+	}
+	collection := domain.NewCollection(requestData.CollectionId, "")
+	task := domain.NewTask(
+		taskId,
+		requestData.Description,
+		requestData.Finished,
+		collection,
+	)
+	task.SetDueDate(requestData.DueDate)
+	task.SetTags(tags)
+
+	return task, nil
+}
+
+// newTaskRequest builds the document on which the task patches are applied
+func newTaskRequest(task domain.Task) request.Task {
+	return request.Task{
+		Description:  task.Description(),
+		Finished:     task.Finished(),
+		DueDate:      task.DueDate(),
+		Tags:         append([]string{}, task.Tags()...),
+		CollectionId: task.Collection().Id(),
+	}
+}
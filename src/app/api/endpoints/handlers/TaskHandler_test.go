@@ -8,9 +8,13 @@ import (
 	"github.com/stretchr/testify/mock"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 	"todo/src/app/api/endpoints/dto/request"
+	"todo/src/app/api/endpoints/dto/response"
 	"todo/src/core/domain"
+	"todo/src/core/filterexpr"
 	"todo/src/core/projecterrors/todoerrors"
 )
 
@@ -23,28 +27,55 @@ func (m *MockTaskService) Create(task domain.Task, userId int) (int, error) {
 	return args.Int(0), args.Error(1)
 }
 
-func (m *MockTaskService) Update(task domain.Task, userId int) error {
+func (m *MockTaskService) Update(task domain.Task, userId int) (string, error) {
 	args := m.Called(task, userId)
-	return args.Error(0)
+	return args.String(0), args.Error(1)
 }
 
-func (m *MockTaskService) Delete(taskId, userId int) error {
+func (m *MockTaskService) Delete(taskId, userId, version int) (string, error) {
+	args := m.Called(taskId, userId, version)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockTaskService) FindById(taskId, userId int) (*domain.Task, error) {
 	args := m.Called(taskId, userId)
-	return args.Error(0)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.Task), args.Error(1)
+	}
+	return nil, args.Error(1)
 }
 
-func (m *MockTaskService) FindAll(userId int) ([]domain.Task, error) {
-	args := m.Called(userId)
+func (m *MockTaskService) Batch(batch domain.TaskBatch, userId int) (*domain.TaskBatchResult, error) {
+	args := m.Called(batch, userId)
 	if args.Get(0) != nil {
-		return args.Get(0).([]domain.Task), args.Error(1)
+		return args.Get(0).(*domain.TaskBatchResult), args.Error(1)
 	}
 	return nil, args.Error(1)
 }
 
-func (m *MockTaskService) FindByCollectionId(collectionId, userId int) ([]domain.Task, error) {
-	args := m.Called(collectionId, userId)
+func (m *MockTaskService) FindAll(userId int, filter domain.TaskFilter,
+	pagination domain.Pagination) ([]domain.Task, int, error) {
+	args := m.Called(userId, filter, pagination)
 	if args.Get(0) != nil {
-		return args.Get(0).([]domain.Task), args.Error(1)
+		return args.Get(0).([]domain.Task), args.Int(1), args.Error(2)
+	}
+	return nil, args.Int(1), args.Error(2)
+}
+
+func (m *MockTaskService) FindByCollectionId(collectionId, userId int, filter domain.TaskFilter,
+	pagination domain.Pagination) ([]domain.Task, int, error) {
+	args := m.Called(collectionId, userId, filter, pagination)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.Task), args.Int(1), args.Error(2)
+	}
+	return nil, args.Int(1), args.Error(2)
+}
+
+func (m *MockTaskService) FindPage(userId int, filter domain.TaskFilter,
+	pagination domain.KeysetPagination) (*domain.TaskPage, error) {
+	args := m.Called(userId, filter, pagination)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.TaskPage), args.Error(1)
 	}
 	return nil, args.Error(1)
 }
@@ -152,7 +183,7 @@ func TestTask_Update(t *testing.T) {
 
 		mockService := new(MockTaskService)
 		taskHandler := Task{service: mockService}
-		mockService.On("Update", mock.Anything, mock.Anything).Return(nil)
+		mockService.On("Update", mock.Anything, mock.Anything).Return("", nil)
 
 		_ = taskHandler.Update(context)
 
@@ -240,7 +271,7 @@ func TestTask_Update(t *testing.T) {
 		mockService := new(MockTaskService)
 		taskHandler := Task{service: mockService}
 		serviceErr := todoerrors.NewUnexpectedInternalError("Service layer error")
-		mockService.On("Update", mock.Anything, mock.Anything).Return(serviceErr)
+		mockService.On("Update", mock.Anything, mock.Anything).Return("", serviceErr)
 
 		_ = taskHandler.Update(context)
 
@@ -251,6 +282,302 @@ func TestTask_Update(t *testing.T) {
 	})
 }
 
+func TestTask_Preconditions(t *testing.T) {
+	t.Run("should send the If-Match version along with the update", func(t *testing.T) {
+		input := request.Task{Description: "Edited task", CollectionId: 1}
+		requestBody, _ := json.Marshal(input)
+		requestData := httptest.NewRequest(http.MethodPut, "/user/1/task/2", bytes.NewBuffer(requestBody))
+		requestData.Header.Set("Content-Type", "application/json")
+		requestData.Header.Set("If-Match", "\"4\"")
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId", "taskId")
+		context.SetParamValues("1", "2")
+
+		mockService := new(MockTaskService)
+		taskHandler := Task{service: mockService}
+		mockService.On("Update", mock.MatchedBy(func(task domain.Task) bool {
+			return task.Version() == 4
+		}), 1).Return("", nil)
+
+		_ = taskHandler.Update(context)
+
+		assert.Equal(t, http.StatusNoContent, responseData.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return 412 when the task has been changed since it was read", func(t *testing.T) {
+		input := request.Task{Description: "Edited task", CollectionId: 1}
+		requestBody, _ := json.Marshal(input)
+		requestData := httptest.NewRequest(http.MethodPut, "/user/1/task/2", bytes.NewBuffer(requestBody))
+		requestData.Header.Set("Content-Type", "application/json")
+		requestData.Header.Set("If-Match", "\"4\"")
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId", "taskId")
+		context.SetParamValues("1", "2")
+
+		mockService := new(MockTaskService)
+		taskHandler := Task{service: mockService}
+		mockService.On("Update", mock.Anything, 1).Return("", todoerrors.NewPreconditionFailedError())
+
+		_ = taskHandler.Update(context)
+
+		expectedBody := "{\"message\":\"The resource has been changed since it was last read. Fetch it again and " +
+			"retry.\"}\n"
+
+		assert.Equal(t, http.StatusPreconditionFailed, responseData.Code)
+		assert.Equal(t, expectedBody, responseData.Body.String())
+	})
+
+	t.Run("should return 412 when If-Match only has weak entity tags", func(t *testing.T) {
+		requestData := httptest.NewRequest(http.MethodDelete, "/user/1/task/2", nil)
+		requestData.Header.Set("If-Match", "W/\"4\"")
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId", "taskId")
+		context.SetParamValues("1", "2")
+
+		mockService := new(MockTaskService)
+		taskHandler := Task{service: mockService}
+
+		_ = taskHandler.Delete(context)
+
+		assert.Equal(t, http.StatusPreconditionFailed, responseData.Code)
+		mockService.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should resolve If-Match lists against the stored version", func(t *testing.T) {
+		requestData := httptest.NewRequest(http.MethodDelete, "/user/1/task/2", nil)
+		requestData.Header.Set("If-Match", "\"3\", \"5\"")
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId", "taskId")
+		context.SetParamValues("1", "2")
+
+		mockService := new(MockTaskService)
+		taskHandler := Task{service: mockService}
+		task := domain.NewTask(2, "Task", false, domain.NewCollection(1, "Collection"))
+		task.SetVersion(5)
+		mockService.On("FindById", 2, 1).Return(task, nil)
+		mockService.On("Delete", 2, 1, 5).Return("", nil)
+
+		_ = taskHandler.Delete(context)
+
+		assert.Equal(t, http.StatusNoContent, responseData.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return 412 when the patched task does not match If-Match", func(t *testing.T) {
+		requestData := httptest.NewRequest(http.MethodPatch, "/user/1/task/2",
+			strings.NewReader("{\"finished\":true}"))
+		requestData.Header.Set("Content-Type", "application/merge-patch+json")
+		requestData.Header.Set("If-Match", "\"4\"")
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId", "taskId")
+		context.SetParamValues("1", "2")
+
+		mockService := new(MockTaskService)
+		taskHandler := Task{service: mockService}
+		task := domain.NewTask(2, "Task", false, domain.NewCollection(1, "Collection"))
+		task.SetVersion(5)
+		mockService.On("FindById", 2, 1).Return(task, nil)
+
+		_ = taskHandler.Patch(context)
+
+		assert.Equal(t, http.StatusPreconditionFailed, responseData.Code)
+		mockService.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return the ETag and 304 when If-None-Match has it", func(t *testing.T) {
+		task := domain.NewTask(2, "Task", false, domain.NewCollection(1, "Collection"))
+		task.SetVersion(5)
+		mockService := new(MockTaskService)
+		taskHandler := Task{service: mockService}
+		mockService.On("FindById", 2, 1).Return(task, nil)
+
+		requestData := httptest.NewRequest(http.MethodGet, "/user/1/task/2", nil)
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId", "taskId")
+		context.SetParamValues("1", "2")
+
+		_ = taskHandler.FindById(context)
+
+		assert.Equal(t, http.StatusOK, responseData.Code)
+		assert.Equal(t, "\"5\"", responseData.Header().Get("ETag"))
+
+		requestData = httptest.NewRequest(http.MethodGet, "/user/1/task/2", nil)
+		requestData.Header.Set("If-None-Match", responseData.Header().Get("ETag"))
+		responseData = httptest.NewRecorder()
+		context = echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId", "taskId")
+		context.SetParamValues("1", "2")
+
+		_ = taskHandler.FindById(context)
+
+		assert.Equal(t, http.StatusNotModified, responseData.Code)
+		assert.Empty(t, responseData.Body.String())
+	})
+
+	t.Run("should return a weak ETag changing with the collection embedded", func(t *testing.T) {
+		etagOf := func(collectionName string) string {
+			task := domain.NewTask(2, "Task", false, domain.NewCollection(1, collectionName))
+			task.SetVersion(5)
+			mockService := new(MockTaskService)
+			taskHandler := Task{service: mockService}
+			mockService.On("FindById", 2, 1).Return(task, nil)
+
+			requestData := httptest.NewRequest(http.MethodGet, "/user/1/task/2?expand=collection", nil)
+			responseData := httptest.NewRecorder()
+			context := echo.New().NewContext(requestData, responseData)
+			context.SetParamNames("userId", "taskId")
+			context.SetParamValues("1", "2")
+
+			_ = taskHandler.FindById(context)
+
+			return responseData.Header().Get("ETag")
+		}
+
+		etag := etagOf("Collection")
+
+		assert.True(t, strings.HasPrefix(etag, "W/\"5-"))
+		assert.NotEqual(t, etag, etagOf("Renamed"))
+	})
+}
+
+func TestTask_Patch(t *testing.T) {
+	storedTask := func() *domain.Task {
+		task := domain.NewTask(2, "Stored task", true, domain.NewCollection(3, "Stored collection"))
+		task.SetTags([]string{"work"})
+		task.SetVersion(7)
+		return task
+	}
+
+	t.Run("should keep the fields missing from the merge patch", func(t *testing.T) {
+		requestData := httptest.NewRequest(http.MethodPatch, "/user/1/task/2",
+			strings.NewReader("{\"description\":\"Edited task\"}"))
+		requestData.Header.Set("Content-Type", "application/merge-patch+json")
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId", "taskId")
+		context.SetParamValues("1", "2")
+
+		mockService := new(MockTaskService)
+		taskHandler := Task{service: mockService}
+		mockService.On("FindById", 2, 1).Return(storedTask(), nil)
+		mockService.On("Update", mock.MatchedBy(func(task domain.Task) bool {
+			return task.Id() == 2 && task.Description() == "Edited task" && task.Finished() && task.Version() == 7 &&
+				task.Collection().Id() == 3 && len(task.Tags()) == 1
+		}), 1).Return("", nil)
+
+		_ = taskHandler.Patch(context)
+
+		assert.Equal(t, http.StatusNoContent, responseData.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should apply the JSON patch operations on the stored task", func(t *testing.T) {
+		requestData := httptest.NewRequest(http.MethodPatch, "/user/1/task/2",
+			strings.NewReader("[{\"op\":\"test\",\"path\":\"/finished\",\"value\":true},"+
+				"{\"op\":\"add\",\"path\":\"/tags/-\",\"value\":\"urgent\"},"+
+				"{\"op\":\"replace\",\"path\":\"/finished\",\"value\":false}]"))
+		requestData.Header.Set("Content-Type", "application/json-patch+json")
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId", "taskId")
+		context.SetParamValues("1", "2")
+
+		mockService := new(MockTaskService)
+		taskHandler := Task{service: mockService}
+		mockService.On("FindById", 2, 1).Return(storedTask(), nil)
+		mockService.On("Update", mock.MatchedBy(func(task domain.Task) bool {
+			return task.Description() == "Stored task" && !task.Finished() &&
+				assert.ObjectsAreEqual([]string{"work", "urgent"}, task.Tags())
+		}), 1).Return("", nil)
+
+		_ = taskHandler.Patch(context)
+
+		assert.Equal(t, http.StatusNoContent, responseData.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return 422 when a JSON patch operation cannot be applied", func(t *testing.T) {
+		requestData := httptest.NewRequest(http.MethodPatch, "/user/1/task/2",
+			strings.NewReader("[{\"op\":\"test\",\"path\":\"/finished\",\"value\":false}]"))
+		requestData.Header.Set("Content-Type", "application/json-patch+json")
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId", "taskId")
+		context.SetParamValues("1", "2")
+
+		mockService := new(MockTaskService)
+		taskHandler := Task{service: mockService}
+		mockService.On("FindById", 2, 1).Return(storedTask(), nil)
+
+		_ = taskHandler.Patch(context)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, responseData.Code)
+		mockService.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return 422 when the patch adds an unknown field", func(t *testing.T) {
+		requestData := httptest.NewRequest(http.MethodPatch, "/user/1/task/2",
+			strings.NewReader("{\"priority\":1}"))
+		requestData.Header.Set("Content-Type", "application/merge-patch+json")
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId", "taskId")
+		context.SetParamValues("1", "2")
+
+		mockService := new(MockTaskService)
+		taskHandler := Task{service: mockService}
+		mockService.On("FindById", 2, 1).Return(storedTask(), nil)
+
+		_ = taskHandler.Patch(context)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, responseData.Code)
+		mockService.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return 400 when the patch document is malformed", func(t *testing.T) {
+		requestData := httptest.NewRequest(http.MethodPatch, "/user/1/task/2", strings.NewReader("{finished"))
+		requestData.Header.Set("Content-Type", "application/merge-patch+json")
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId", "taskId")
+		context.SetParamValues("1", "2")
+
+		mockService := new(MockTaskService)
+		taskHandler := Task{service: mockService}
+		mockService.On("FindById", 2, 1).Return(storedTask(), nil)
+
+		_ = taskHandler.Patch(context)
+
+		assert.Equal(t, http.StatusBadRequest, responseData.Code)
+	})
+
+	t.Run("should return 415 when the patch format is not supported", func(t *testing.T) {
+		requestData := httptest.NewRequest(http.MethodPatch, "/user/1/task/2",
+			strings.NewReader("{\"finished\":true}"))
+		requestData.Header.Set("Content-Type", "application/json")
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId", "taskId")
+		context.SetParamValues("1", "2")
+
+		mockService := new(MockTaskService)
+		taskHandler := Task{service: mockService}
+		mockService.On("FindById", 2, 1).Return(storedTask(), nil)
+
+		_ = taskHandler.Patch(context)
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, responseData.Code)
+		mockService.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
+
 func TestTask_Delete(t *testing.T) {
 	t.Run("should return 204 when the request is successful", func(t *testing.T) {
 		requestData := httptest.NewRequest(http.MethodDelete, "/user/1/task/2", nil)
@@ -262,7 +589,7 @@ func TestTask_Delete(t *testing.T) {
 
 		mockService := new(MockTaskService)
 		taskHandler := Task{service: mockService}
-		mockService.On("Delete", mock.Anything, mock.Anything).Return(nil)
+		mockService.On("Delete", mock.Anything, mock.Anything, mock.Anything).Return("", nil)
 
 		_ = taskHandler.Delete(context)
 
@@ -270,6 +597,23 @@ func TestTask_Delete(t *testing.T) {
 		assert.Empty(t, responseData.Body)
 	})
 
+	t.Run("should return the undo token when the task can be restored", func(t *testing.T) {
+		requestData := httptest.NewRequest(http.MethodDelete, "/user/1/task/2", nil)
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId", "taskId")
+		context.SetParamValues("1", "2")
+
+		mockService := new(MockTaskService)
+		taskHandler := Task{service: mockService}
+		mockService.On("Delete", 2, 1, 0).Return("4f1c2a9e8b7d6c5e4f3a2b1c0d9e8f7a", nil)
+
+		_ = taskHandler.Delete(context)
+
+		assert.Equal(t, http.StatusNoContent, responseData.Code)
+		assert.Equal(t, "4f1c2a9e8b7d6c5e4f3a2b1c0d9e8f7a", responseData.Header().Get("Undo-Token"))
+	})
+
 	t.Run("should return 422 when user ID is not a positive integer", func(t *testing.T) {
 		requestData := httptest.NewRequest(http.MethodDelete, "/user/4f626b9f-ee9a-4e41-a6a6-44d4833dfdfa/task/2",
 			nil)
@@ -323,7 +667,7 @@ func TestTask_Delete(t *testing.T) {
 		mockService := new(MockTaskService)
 		taskHandler := Task{service: mockService}
 		serviceErr := todoerrors.NewUnexpectedInternalError("Service layer error")
-		mockService.On("Delete", mock.Anything, mock.Anything).Return(serviceErr)
+		mockService.On("Delete", mock.Anything, mock.Anything, mock.Anything).Return("", serviceErr)
 
 		_ = taskHandler.Delete(context)
 
@@ -334,6 +678,264 @@ func TestTask_Delete(t *testing.T) {
 	})
 }
 
+func TestTask_Batch(t *testing.T) {
+	t.Run("should return 200 with the status of each operation when the batch is applied", func(t *testing.T) {
+		input := request.TaskBatch{Operations: []request.TaskOperation{
+			{Operation: "create", Task: &request.Task{Description: "New task", CollectionId: 1}},
+			{Operation: "update", Id: 2, Task: &request.Task{Description: "Edited task", Finished: true}},
+			{Operation: "delete", Id: 3},
+		}}
+		requestBody, _ := json.Marshal(input)
+		requestData := httptest.NewRequest(http.MethodPost, "/user/1/task/batch", bytes.NewBuffer(requestBody))
+		requestData.Header.Set("Content-Type", "application/json")
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId")
+		context.SetParamValues("1")
+
+		mockService := new(MockTaskService)
+		taskHandler := Task{service: mockService}
+		results := []domain.TaskOperationResult{
+			*domain.NewTaskOperationResult(10, nil),
+			*domain.NewTaskOperationResult(2, nil),
+			*domain.NewTaskOperationResult(3, nil),
+		}
+		mockService.On("Batch", mock.MatchedBy(func(batch domain.TaskBatch) bool {
+			return batch.Atomic() && len(batch.Operations()) == 3 && batch.Operations()[1].TaskId() == 2 &&
+				batch.Operations()[1].Task().Finished()
+		}), 1).Return(domain.NewTaskBatchResult(results, true), nil)
+
+		_ = taskHandler.Batch(context)
+
+		expectedBody := "{\"mode\":\"atomic\",\"committed\":true,\"results\":[" +
+			"{\"index\":0,\"op\":\"create\",\"status\":201,\"id\":10}," +
+			"{\"index\":1,\"op\":\"update\",\"status\":204,\"id\":2}," +
+			"{\"index\":2,\"op\":\"delete\",\"status\":204,\"id\":3}]}\n"
+
+		assert.Equal(t, http.StatusOK, responseData.Code)
+		assert.Equal(t, expectedBody, responseData.Body.String())
+	})
+
+	t.Run("should return 207 reporting the failed and rolled back operations when the atomic batch fails",
+		func(t *testing.T) {
+			input := request.TaskBatch{Mode: "atomic", Operations: []request.TaskOperation{
+				{Operation: "delete", Id: 2},
+				{Operation: "delete", Id: 3},
+			}}
+			requestBody, _ := json.Marshal(input)
+			requestData := httptest.NewRequest(http.MethodPost, "/user/1/task/batch", bytes.NewBuffer(requestBody))
+			requestData.Header.Set("Content-Type", "application/json")
+			responseData := httptest.NewRecorder()
+			context := echo.New().NewContext(requestData, responseData)
+			context.SetParamNames("userId")
+			context.SetParamValues("1")
+
+			mockService := new(MockTaskService)
+			taskHandler := Task{service: mockService}
+			results := []domain.TaskOperationResult{
+				*domain.NewTaskOperationResult(2, todoerrors.NewFailedDependencyError()),
+				*domain.NewTaskOperationResult(3, todoerrors.NewNotFoundError()),
+			}
+			mockService.On("Batch", mock.Anything, 1).Return(domain.NewTaskBatchResult(results, false), nil)
+
+			_ = taskHandler.Batch(context)
+
+			expectedBody := "{\"mode\":\"atomic\",\"committed\":false,\"results\":[" +
+				"{\"index\":0,\"op\":\"delete\",\"status\":424,\"error\":{\"message\":" +
+				"\"The operation was not applied because another operation it depends on has failed.\"}}," +
+				"{\"index\":1,\"op\":\"delete\",\"status\":404,\"error\":{\"message\":\"Not Found\"}}]}\n"
+
+			assert.Equal(t, http.StatusMultiStatus, responseData.Code)
+			assert.Equal(t, expectedBody, responseData.Body.String())
+		})
+
+	t.Run("should pass the best effort mode to the service", func(t *testing.T) {
+		input := request.TaskBatch{Mode: "best_effort", Operations: []request.TaskOperation{
+			{Operation: "delete", Id: 2},
+		}}
+		requestBody, _ := json.Marshal(input)
+		requestData := httptest.NewRequest(http.MethodPost, "/user/1/task/batch", bytes.NewBuffer(requestBody))
+		requestData.Header.Set("Content-Type", "application/json")
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId")
+		context.SetParamValues("1")
+
+		mockService := new(MockTaskService)
+		taskHandler := Task{service: mockService}
+		results := []domain.TaskOperationResult{*domain.NewTaskOperationResult(2, nil)}
+		mockService.On("Batch", mock.MatchedBy(func(batch domain.TaskBatch) bool {
+			return !batch.Atomic()
+		}), 1).Return(domain.NewTaskBatchResult(results, true), nil)
+
+		_ = taskHandler.Batch(context)
+
+		assert.Equal(t, http.StatusOK, responseData.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return 422 without calling the service when an operation is invalid", func(t *testing.T) {
+		input := request.TaskBatch{Mode: "sometimes", Operations: []request.TaskOperation{
+			{Operation: "delete", Id: 2},
+			{Operation: "update", Id: 0, Task: &request.Task{}},
+			{Operation: "move", Id: 3},
+			{Operation: "create"},
+		}}
+		requestBody, _ := json.Marshal(input)
+		requestData := httptest.NewRequest(http.MethodPost, "/user/1/task/batch", bytes.NewBuffer(requestBody))
+		requestData.Header.Set("Content-Type", "application/json")
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId")
+		context.SetParamValues("1")
+
+		mockService := new(MockTaskService)
+		taskHandler := Task{service: mockService}
+
+		_ = taskHandler.Batch(context)
+
+		expectedBody := "{\"message\":\"The batch provided is invalid.\",\"invalid_fields\":[" +
+			"{\"name\":\"Mode\",\"description\":\"The mode provided is invalid. The allowed modes are: atomic, " +
+			"best_effort.\"},{\"name\":\"Operation 1\",\"description\":\"The operation must reference a task ID " +
+			"greater than zero.\"},{\"name\":\"Operation 2\",\"description\":\"The operation type is invalid. The " +
+			"allowed types are: create, update, delete.\"},{\"name\":\"Operation 3\",\"description\":\"The " +
+			"operation must have the task data.\"}]}\n"
+
+		assert.Equal(t, http.StatusUnprocessableEntity, responseData.Code)
+		assert.Equal(t, expectedBody, responseData.Body.String())
+		mockService.AssertNotCalled(t, "Batch", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return 422 when the batch has no operations", func(t *testing.T) {
+		requestData := httptest.NewRequest(http.MethodPost, "/user/1/task/batch",
+			strings.NewReader("{\"operations\":[]}"))
+		requestData.Header.Set("Content-Type", "application/json")
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId")
+		context.SetParamValues("1")
+
+		mockService := new(MockTaskService)
+		taskHandler := Task{service: mockService}
+
+		_ = taskHandler.Batch(context)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, responseData.Code)
+		assert.Contains(t, responseData.Body.String(), "The batch must have between 1 and 100 operations.")
+	})
+
+	t.Run("should return 400 when request body is not a valid JSON", func(t *testing.T) {
+		requestData := httptest.NewRequest(http.MethodPost, "/user/1/task/batch", strings.NewReader("{operations"))
+		requestData.Header.Set("Content-Type", "application/json")
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId")
+		context.SetParamValues("1")
+
+		mockService := new(MockTaskService)
+		taskHandler := Task{service: mockService}
+
+		_ = taskHandler.Batch(context)
+
+		assert.Equal(t, http.StatusBadRequest, responseData.Code)
+	})
+}
+
+func TestTask_FindById(t *testing.T) {
+	t.Run("should return 200 when the request is successful", func(t *testing.T) {
+		requestData := httptest.NewRequest(http.MethodGet, "/user/1/task/2", nil)
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId", "taskId")
+		context.SetParamValues("1", "2")
+
+		mockService := new(MockTaskService)
+		taskHandler := Task{service: mockService}
+		task := domain.NewTask(2, "Task", true, domain.NewCollection(3, "Collection"))
+		mockService.On("FindById", 2, 1).Return(task, nil)
+
+		_ = taskHandler.FindById(context)
+
+		expectedBody := "{\"id\":2,\"description\":\"Task\",\"finished\":true,\"collection\":{\"id\":3}}\n"
+
+		assert.Equal(t, http.StatusOK, responseData.Code)
+		assert.Equal(t, expectedBody, responseData.Body.String())
+	})
+
+	t.Run("should return 200 with the collection embedded when it is asked to be expanded", func(t *testing.T) {
+		requestData := httptest.NewRequest(http.MethodGet, "/user/1/task/2?expand=collection", nil)
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId", "taskId")
+		context.SetParamValues("1", "2")
+
+		mockService := new(MockTaskService)
+		taskHandler := Task{service: mockService}
+		task := domain.NewTask(2, "Task", false, domain.NewCollection(3, "Collection"))
+		task.SetTags([]string{"home"})
+		mockService.On("FindById", 2, 1).Return(task, nil)
+
+		_ = taskHandler.FindById(context)
+
+		expectedBody := "{\"id\":2,\"description\":\"Task\",\"finished\":false,\"collection\":{\"id\":3," +
+			"\"name\":\"Collection\"},\"tags\":[\"home\"]}\n"
+
+		assert.Equal(t, http.StatusOK, responseData.Code)
+		assert.Equal(t, expectedBody, responseData.Body.String())
+	})
+
+	t.Run("should return 422 when the expand value is not allowed", func(t *testing.T) {
+		requestData := httptest.NewRequest(http.MethodGet, "/user/1/task/2?expand=tags", nil)
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId", "taskId")
+		context.SetParamValues("1", "2")
+
+		mockService := new(MockTaskService)
+		taskHandler := Task{service: mockService}
+
+		_ = taskHandler.FindById(context)
+
+		expectedBody := "{\"message\":\"Invalid expansion details.\",\"invalid_fields\":[{\"name\":\"Expand\"," +
+			"\"description\":\"The expand value provided is invalid. The allowed values are: collection\"}]}\n"
+
+		assert.Equal(t, http.StatusUnprocessableEntity, responseData.Code)
+		assert.Equal(t, expectedBody, responseData.Body.String())
+		mockService.AssertNotCalled(t, "FindById", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return 422 when task ID is not a positive integer", func(t *testing.T) {
+		requestData := httptest.NewRequest(http.MethodGet, "/user/1/task/abc", nil)
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId", "taskId")
+		context.SetParamValues("1", "abc")
+
+		mockService := new(MockTaskService)
+		taskHandler := Task{service: mockService}
+
+		_ = taskHandler.FindById(context)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, responseData.Code)
+	})
+
+	t.Run("should return 404 when the task does not exist", func(t *testing.T) {
+		requestData := httptest.NewRequest(http.MethodGet, "/user/1/task/2", nil)
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId", "taskId")
+		context.SetParamValues("1", "2")
+
+		mockService := new(MockTaskService)
+		taskHandler := Task{service: mockService}
+		mockService.On("FindById", 2, 1).Return(nil, todoerrors.NewNotFoundError())
+
+		_ = taskHandler.FindById(context)
+
+		assert.Equal(t, http.StatusNotFound, responseData.Code)
+	})
+}
+
 func TestTask_FindAll(t *testing.T) {
 	t.Run("should return 200 when the request is successful", func(t *testing.T) {
 		requestData := httptest.NewRequest(http.MethodGet, "/user/1/task", nil)
@@ -351,7 +953,7 @@ func TestTask_FindAll(t *testing.T) {
 			*domain.NewTask(2, "Test Task 2", false,
 				domain.NewCollection(1, "Test Collection 1")),
 		}
-		mockService.On("FindAll", mock.Anything).Return(tasks, nil)
+		mockService.On("FindAll", mock.Anything, mock.Anything, mock.Anything).Return(tasks, 2, nil)
 
 		_ = taskHandler.FindAll(context)
 
@@ -395,7 +997,7 @@ func TestTask_FindAll(t *testing.T) {
 		mockService := new(MockTaskService)
 		taskHandler := Task{service: mockService}
 		serviceErr := todoerrors.NewUnexpectedInternalError("Service layer error")
-		mockService.On("FindAll", mock.Anything).Return(nil, serviceErr)
+		mockService.On("FindAll", mock.Anything, mock.Anything, mock.Anything).Return(nil, 0, serviceErr)
 
 		_ = taskHandler.FindAll(context)
 
@@ -404,6 +1006,204 @@ func TestTask_FindAll(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, responseData.Code)
 		assert.Equal(t, expectedBody, responseData.Body.String())
 	})
+
+	t.Run("should return pagination headers when the request is successful", func(t *testing.T) {
+		requestData := httptest.NewRequest(http.MethodGet, "/user/1/task?page=2&size=2&sort=description&order=desc",
+			nil)
+		requestData.Header.Set("Content-Type", "application/json")
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId")
+		context.SetParamValues("1")
+
+		mockService := new(MockTaskService)
+		taskHandler := Task{service: mockService}
+		tasks := []domain.Task{
+			*domain.NewTask(3, "Test Task 3", false,
+				domain.NewCollection(1, "Test Collection 1")),
+		}
+		expectedPagination := *domain.NewPagination(2, 2, "description", domain.SortDescending)
+		mockService.On("FindAll", 1, mock.Anything, expectedPagination).Return(tasks, 5, nil)
+
+		_ = taskHandler.FindAll(context)
+
+		expectedLink := "</user/1/task?limit=2&offset=0&order=desc&sort=description>; rel=\"first\", " +
+			"</user/1/task?limit=2&offset=0&order=desc&sort=description>; rel=\"prev\", " +
+			"</user/1/task?limit=2&offset=4&order=desc&sort=description>; rel=\"next\", " +
+			"</user/1/task?limit=2&offset=4&order=desc&sort=description>; rel=\"last\""
+
+		assert.Equal(t, http.StatusOK, responseData.Code)
+		assert.Equal(t, "5", responseData.Header().Get("X-Total-Count"))
+		assert.Equal(t, expectedLink, responseData.Header().Get("Link"))
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return 422 when the pagination parameters are invalid", func(t *testing.T) {
+		requestData := httptest.NewRequest(http.MethodGet, "/user/1/task?limit=500&sort=password", nil)
+		requestData.Header.Set("Content-Type", "application/json")
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId")
+		context.SetParamValues("1")
+
+		mockService := new(MockTaskService)
+		taskHandler := Task{service: mockService}
+
+		_ = taskHandler.FindAll(context)
+
+		expectedBody := "{\"message\":\"Invalid pagination details.\",\"invalid_fields\":[{\"name\":\"Limit\"," +
+			"\"description\":\"The limit provided is invalid. The limit must be between 1 and 100.\"},{\"name\":" +
+			"\"Sort\",\"description\":\"The sort field provided is invalid. The allowed fields are: id, description, " +
+			"finished, collection_id, collection_name, created_at, due_date\"}]}\n"
+
+		assert.Equal(t, http.StatusUnprocessableEntity, responseData.Code)
+		assert.Equal(t, expectedBody, responseData.Body.String())
+		mockService.AssertNotCalled(t, "FindAll", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should pass the parsed filter expression to the service", func(t *testing.T) {
+		requestData := httptest.NewRequest(http.MethodGet, "/user/1/task?filter=tag:work+and+not+finished", nil)
+		requestData.Header.Set("Content-Type", "application/json")
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId")
+		context.SetParamValues("1")
+
+		mockService := new(MockTaskService)
+		taskHandler := Task{service: mockService}
+		expectedExpression := filterexpr.And{
+			Left: filterexpr.Comparison{Field: filterexpr.FieldTag, Operator: filterexpr.OperatorEqual, Value: "work"},
+			Right: filterexpr.Not{Operand: filterexpr.Comparison{Field: filterexpr.FieldFinished,
+				Operator: filterexpr.OperatorEqual, Value: true}},
+		}
+		hasExpectedExpression := mock.MatchedBy(func(filter domain.TaskFilter) bool {
+			return assert.ObjectsAreEqual(expectedExpression, filter.Expression())
+		})
+		mockService.On("FindAll", 1, hasExpectedExpression, mock.Anything).Return([]domain.Task{}, 0, nil)
+
+		_ = taskHandler.FindAll(context)
+
+		assert.Equal(t, http.StatusOK, responseData.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return 422 pointing at the offending token when the filter expression is invalid",
+		func(t *testing.T) {
+			requestData := httptest.NewRequest(http.MethodGet, "/user/1/task?filter=tag:work+and+owner:me", nil)
+			requestData.Header.Set("Content-Type", "application/json")
+			responseData := httptest.NewRecorder()
+			context := echo.New().NewContext(requestData, responseData)
+			context.SetParamNames("userId")
+			context.SetParamValues("1")
+
+			mockService := new(MockTaskService)
+			taskHandler := Task{service: mockService}
+
+			_ = taskHandler.FindAll(context)
+
+			expectedBody := "{\"message\":\"The filter expression provided is invalid.\",\"invalid_fields\":[{" +
+				"\"name\":\"Filter\",\"description\":\"Unknown field at position 14: \\\"owner\\\"\"}]}\n"
+
+			assert.Equal(t, http.StatusUnprocessableEntity, responseData.Code)
+			assert.Equal(t, expectedBody, responseData.Body.String())
+		})
+
+	t.Run("should return 422 when the filter parameters are invalid", func(t *testing.T) {
+		requestData := httptest.NewRequest(http.MethodGet, "/user/1/task?finished=maybe&created_from=yesterday",
+			nil)
+		requestData.Header.Set("Content-Type", "application/json")
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId")
+		context.SetParamValues("1")
+
+		mockService := new(MockTaskService)
+		taskHandler := Task{service: mockService}
+
+		_ = taskHandler.FindAll(context)
+
+		expectedBody := "{\"message\":\"The query parameters provided are invalid.\",\"invalid_fields\":[{" +
+			"\"name\":\"Finished\",\"description\":\"Conversion error.\"},{\"name\":\"Created From\"," +
+			"\"description\":\"Conversion error.\"}]}\n"
+
+		assert.Equal(t, http.StatusUnprocessableEntity, responseData.Code)
+		assert.Equal(t, expectedBody, responseData.Body.String())
+	})
+}
+
+func TestTask_FindPage(t *testing.T) {
+	t.Run("should return a page with signed cursors that can be followed", func(t *testing.T) {
+		requestData := httptest.NewRequest(http.MethodGet, "/user/1/task?cursor=&limit=1&sort=created_at", nil)
+		requestData.Header.Set("Content-Type", "application/json")
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId")
+		context.SetParamValues("1")
+
+		mockService := new(MockTaskService)
+		taskHandler := Task{service: mockService}
+		createdAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		task := domain.NewTask(1, "Test Task 1", false, domain.NewCollection(1, "Test Collection 1"))
+		task.SetCreatedAt(createdAt)
+		nextCursor := domain.NewCursor("created_at", domain.SortAscending, 1, createdAt, false)
+		page := domain.NewTaskPage([]domain.Task{*task}, nextCursor, nil)
+		expectedPagination := *domain.NewKeysetPagination(1, "created_at", domain.SortAscending, nil)
+		mockService.On("FindPage", 1, mock.Anything, expectedPagination).Return(page, nil)
+
+		_ = taskHandler.FindAll(context)
+
+		var pageResponse response.TaskPage
+		_ = json.Unmarshal(responseData.Body.Bytes(), &pageResponse)
+
+		assert.Equal(t, http.StatusOK, responseData.Code)
+		assert.Len(t, pageResponse.Data, 1)
+		assert.Nil(t, pageResponse.Prev)
+		assert.NotNil(t, pageResponse.Next)
+
+		cursor, validationErr := decodeCursor(*pageResponse.Next, 1)
+		assert.Nil(t, validationErr)
+		assert.Equal(t, nextCursor, cursor)
+	})
+
+	t.Run("should return 422 when the cursor has been tampered with", func(t *testing.T) {
+		cursor := *encodeCursor(domain.NewCursor("id", domain.SortAscending, 20, time.Time{}, false), 1)
+		tamperedCursor := strings.Replace(cursor, cursor[:4], "eyJ2", 1)
+		requestData := httptest.NewRequest(http.MethodGet, "/user/1/task?cursor="+tamperedCursor, nil)
+		requestData.Header.Set("Content-Type", "application/json")
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId")
+		context.SetParamValues("1")
+
+		mockService := new(MockTaskService)
+		taskHandler := Task{service: mockService}
+
+		_ = taskHandler.FindAll(context)
+
+		expectedBody := "{\"message\":\"The query parameters provided are invalid.\",\"invalid_fields\":[{" +
+			"\"name\":\"Cursor\",\"description\":\"The cursor provided is invalid or has been tampered with.\"}]}\n"
+
+		assert.Equal(t, http.StatusUnprocessableEntity, responseData.Code)
+		assert.Equal(t, expectedBody, responseData.Body.String())
+	})
+
+	t.Run("should return 422 when the cursor belongs to another user", func(t *testing.T) {
+		cursor := *encodeCursor(domain.NewCursor("id", domain.SortAscending, 20, time.Time{}, false), 2)
+		requestData := httptest.NewRequest(http.MethodGet, "/user/1/task?cursor="+cursor, nil)
+		requestData.Header.Set("Content-Type", "application/json")
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId")
+		context.SetParamValues("1")
+
+		mockService := new(MockTaskService)
+		taskHandler := Task{service: mockService}
+
+		_ = taskHandler.FindAll(context)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, responseData.Code)
+		mockService.AssertNotCalled(t, "FindPage", mock.Anything, mock.Anything, mock.Anything)
+	})
 }
 
 func TestTask_FindByCollectionId(t *testing.T) {
@@ -423,7 +1223,8 @@ func TestTask_FindByCollectionId(t *testing.T) {
 			*domain.NewTask(2, "Test Task 2", false,
 				domain.NewCollection(2, "Test Collection 2")),
 		}
-		mockService.On("FindByCollectionId", mock.Anything, mock.Anything).Return(tasks, nil)
+		mockService.On("FindByCollectionId", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(tasks,
+			2, nil)
 
 		_ = taskHandler.FindByCollectionId(context)
 
@@ -487,8 +1288,8 @@ func TestTask_FindByCollectionId(t *testing.T) {
 		mockService := new(MockTaskService)
 		taskHandler := Task{service: mockService}
 		serviceErr := todoerrors.NewUnexpectedInternalError("Service layer error")
-		mockService.On("FindByCollectionId", mock.Anything, mock.Anything).Return(nil,
-			serviceErr)
+		mockService.On("FindByCollectionId", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil,
+			0, serviceErr)
 
 		_ = taskHandler.FindByCollectionId(context)
 
@@ -0,0 +1,629 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"todo/src/app/api/endpoints/dto/request"
+	"todo/src/app/api/endpoints/dto/response"
+	"todo/src/app/api/endpoints/handlers/msgs"
+	"todo/src/core/domain"
+	"todo/src/core/importers"
+	interfaces "todo/src/core/interfaces/services"
+	"todo/src/core/projecterrors/todoerrors"
+	"todo/src/core/services"
+	"todo/src/core/todotxt"
+	"todo/src/infra/postgres"
+)
+
+const (
+	mimeTextCSV         = "text/csv"
+	csvFlushRows        = 100
+	formFieldCollection = "collection"
+	formFieldTask       = "task"
+	todoTxtFileName     = "todo.txt"
+)
+
+type Transfer struct {
+	service   interfaces.ITransfer
+	importers *importers.Registry
+}
+
+func NewTransferHandler() *Transfer {
+	connectionManager := postgres.NewPostgresConnectionManager()
+	repository := postgres.NewTransferPostgresRepository(connectionManager)
+	service := services.NewTransferService(repository, postgres.NewJobPostgresRepository(connectionManager))
+	return &Transfer{service, importers.NewDefaultRegistry()}
+}
+
+// Export
+// @ID 			Export
+// @Summary		Export all collections and tasks as JSON
+// @Tags 		Transfer
+// @Description Route that streams all collections and tasks of the user as a JSON document, in the same format accepted by the import route.
+// @Produce 	json
+// @Security	bearerAuth
+// @Param 	    userId       path       int                  true                  "User ID"    default(1)
+// @Success 	200 		 {object} 	response.SwaggerTransferResponse           "Successful request"
+// @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403          {object}   response.SwaggerForbiddenResponse 	       "The user does not have access to this information"
+// @Failure 	422 		 {object} 	response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Failure 	500 		 {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/user/{userId}/export  [get]
+func (h Transfer) Export(ctx echo.Context) error {
+	userId, err := convertToPositiveInteger(ctx.Param("userId"), msgs.UserId)
+	if err != nil {
+		log.Error(err)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.UserId, msgs.ConversionError)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
+	}
+
+	stream := newExportStream(ctx, echo.MIMEApplicationJSONCharsetUTF8, "export.json")
+	encoder := json.NewEncoder(ctx.Response())
+	separator := ""
+	writeItem := func(item interface{}) error {
+		if _, err := fmt.Fprint(ctx.Response(), separator); err != nil {
+			return err
+		}
+		separator = ","
+		return encoder.Encode(item)
+	}
+
+	tasksStarted := false
+	startTasks := func() error {
+		stream.start(`{"collections":[`)
+		if tasksStarted {
+			return nil
+		}
+		tasksStarted = true
+		separator = ""
+		_, err := fmt.Fprint(ctx.Response(), `],"tasks":[`)
+		return err
+	}
+
+	err = h.service.Export(userId, func(collection domain.Collection) error {
+		stream.start(`{"collections":[`)
+		return writeItem(response.NewTransferCollection(collection))
+	}, func(task domain.Task) error {
+		if err := startTasks(); err != nil {
+			return err
+		}
+		return writeItem(response.NewTransferTask(task))
+	})
+	if err != nil {
+		log.Error(err)
+		return stream.fail(err)
+	}
+	if err = startTasks(); err != nil {
+		log.Error(err)
+		return nil
+	}
+	if _, err = fmt.Fprintln(ctx.Response(), "]}"); err != nil {
+		log.Error(err)
+	}
+
+	return nil
+}
+
+// ExportCollectionsCsv
+// @ID 			ExportCollectionsCsv
+// @Summary		Export all collections as CSV
+// @Tags 		Transfer
+// @Description Route that streams all collections of the user as CSV, with the columns id, name and user_id.
+// @Produce 	text/csv
+// @Security	bearerAuth
+// @Param 	    userId       path       int                  true                  "User ID"    default(1)
+// @Success 	200 		 {string} 	string                                     "Successful request"
+// @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403          {object}   response.SwaggerForbiddenResponse 	       "The user does not have access to this information"
+// @Failure 	422 		 {object} 	response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Failure 	500 		 {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/user/{userId}/export/collection.csv  [get]
+func (h Transfer) ExportCollectionsCsv(ctx echo.Context) error {
+	userId, err := convertToPositiveInteger(ctx.Param("userId"), msgs.UserId)
+	if err != nil {
+		log.Error(err)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.UserId, msgs.ConversionError)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
+	}
+
+	stream := newExportStream(ctx, mimeTextCSV, "collection.csv")
+	writer := newCsvStreamWriter(ctx, stream, collectionCsvColumns)
+	err = h.service.ExportCollections(userId, func(collection domain.Collection) error {
+		return writer.write(collectionCsvRecord(collection, userId))
+	})
+	if err != nil {
+		log.Error(err)
+		return stream.fail(err)
+	}
+
+	return writer.close()
+}
+
+// ExportTasksCsv
+// @ID 			ExportTasksCsv
+// @Summary		Export all tasks as CSV
+// @Tags 		Transfer
+// @Description Route that streams all tasks of the user as CSV, with the columns id, description, finished, user_id and collection_id. The due dates and tags are only exported as JSON.
+// @Produce 	text/csv
+// @Security	bearerAuth
+// @Param 	    userId       path       int                  true                  "User ID"    default(1)
+// @Success 	200 		 {string} 	string                                     "Successful request"
+// @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403          {object}   response.SwaggerForbiddenResponse 	       "The user does not have access to this information"
+// @Failure 	422 		 {object} 	response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Failure 	500 		 {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/user/{userId}/export/task.csv  [get]
+func (h Transfer) ExportTasksCsv(ctx echo.Context) error {
+	userId, err := convertToPositiveInteger(ctx.Param("userId"), msgs.UserId)
+	if err != nil {
+		log.Error(err)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.UserId, msgs.ConversionError)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
+	}
+
+	stream := newExportStream(ctx, mimeTextCSV, "task.csv")
+	writer := newCsvStreamWriter(ctx, stream, taskCsvColumns)
+	err = h.service.ExportTasks(userId, func(task domain.Task) error {
+		return writer.write(taskCsvRecord(task, userId))
+	})
+	if err != nil {
+		log.Error(err)
+		return stream.fail(err)
+	}
+
+	return writer.close()
+}
+
+// ExportTodoTxt
+// @ID 			ExportTodoTxt
+// @Summary		Export all tasks as todo.txt
+// @Tags 		Transfer
+// @Description Route that streams all tasks of the user as a todo.txt file, one task per line. The collection becomes the project, with the spaces of its name replaced by underscores, the tags become the contexts and the due date the due key. Finished tasks have no dates, since the first date of a completed task would be read as its completion date.
+// @Produce 	plain
+// @Security	bearerAuth
+// @Param 	    userId       path       int                  true                  "User ID"    default(1)
+// @Success 	200 		 {string} 	string                                     "Successful request"
+// @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403          {object}   response.SwaggerForbiddenResponse 	       "The user does not have access to this information"
+// @Failure 	422 		 {object} 	response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Failure 	500 		 {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/user/{userId}/export/todo.txt  [get]
+func (h Transfer) ExportTodoTxt(ctx echo.Context) error {
+	userId, err := convertToPositiveInteger(ctx.Param("userId"), msgs.UserId)
+	if err != nil {
+		log.Error(err)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.UserId, msgs.ConversionError)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
+	}
+
+	stream := newExportStream(ctx, echo.MIMETextPlainCharsetUTF8, todoTxtFileName)
+	encoder := todotxt.NewEncoder(ctx.Response())
+	err = h.service.ExportTasks(userId, func(task domain.Task) error {
+		stream.start("")
+		return encoder.Encode(task)
+	})
+	if err != nil {
+		log.Error(err)
+		return stream.fail(err)
+	}
+	stream.start("")
+
+	return nil
+}
+
+// Import
+// @ID 			Import
+// @Summary		Import collections and tasks
+// @Tags 		Transfer
+// @Description Route that creates the collections and tasks of a file exported by this API, as a JSON document or as multipart/form-data with the CSV files in the collection and task fields. The IDs of the file are only used to relate the tasks to their collections, which are created with new IDs, and the tasks without a collection of the file go to the Imported collection. Every row is validated first and nothing is imported if any of them is invalid, the invalid fields naming the rows by their position in the file. With async=true the rows are imported in the background, and the response has the job whose status is read from the route in the Location header.
+// @Accept 		json
+// @Accept 		mpfd
+// @Produce 	json
+// @Security	bearerAuth
+// @Param 	    userId       path       int                              true      "User ID"    default(1)
+// @Param 	    async        query      bool                             false     "Import in the background"
+// @Param 		importJson 	 body 		request.SwaggerTransferRequest   false     "JSON with the collections and tasks to import"
+// @Param 		collection 	 formData 	file                             false     "CSV file with the collections"
+// @Param 		task 	     formData 	file                             false     "CSV file with the tasks"
+// @Param 	    Idempotency-Key header      string               false                 "Unique key that makes retries of this request return the first response"
+// @Success 	201 		 {object} 	response.SwaggerTransferResultResponse     "Collections and tasks successfully imported"
+// @Success 	202 		 {object} 	response.SwaggerImportJobResponse          "Import queued"
+// @Header 		202          {string}   Location                                   "Route of the status of the import"
+// @Failure 	400 		 {object} 	response.SwaggerBadRequestResponse         "The user has made a bad request"
+// @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403          {object}   response.SwaggerForbiddenResponse 	       "The user does not have access to this information"
+// @Failure 	415 		 {object} 	response.SwaggerGenericErrorResponse       "The format of the file is not supported"
+// @Failure 	422 		 {object} 	response.SwaggerValidationErrorResponse    "Some rows could not be imported because they are not valid"
+// @Failure 	500 		 {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/user/{userId}/import  [post]
+func (h Transfer) Import(ctx echo.Context) error {
+	userId, err := convertToPositiveInteger(ctx.Param("userId"), msgs.UserId)
+	if err != nil {
+		log.Error(err)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.UserId, msgs.ConversionError)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
+	}
+	async, validationErr := parseBoolQueryParam(ctx, "async", msgs.Async)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+
+	invalidFields := todoerrors.InvalidFields{}
+	var collections []domain.Collection
+	var tasks []domain.Task
+	mediaType, _, _ := mime.ParseMediaType(ctx.Request().Header.Get(echo.HeaderContentType))
+	switch mediaType {
+	case echo.MIMEApplicationJSON:
+		var requestData request.Transfer
+		if err = ctx.Bind(&requestData); err != nil {
+			log.Error(err)
+			return writeBadRequestError(ctx, msgs.RequestFormatError)
+		}
+		collections, tasks = parseTransferRequest(requestData, &invalidFields)
+	case echo.MIMEMultipartForm:
+		collectionRows, err := readFormCsvRows(ctx, formFieldCollection, msgs.CollectionFile,
+			requiredCollectionCsvColumns, &invalidFields)
+		if err != nil {
+			log.Error(err)
+			return writeBadRequestError(ctx, msgs.RequestFormatError)
+		}
+		taskRows, err := readFormCsvRows(ctx, formFieldTask, msgs.TaskFile, requiredTaskCsvColumns, &invalidFields)
+		if err != nil {
+			log.Error(err)
+			return writeBadRequestError(ctx, msgs.RequestFormatError)
+		}
+		collections = parseCollectionCsv(collectionRows, &invalidFields)
+		tasks = parseTaskCsv(taskRows, &invalidFields)
+	default:
+		log.Error(msgs.UnsupportedImportFormat)
+		return ctx.JSON(http.StatusUnsupportedMediaType,
+			response.GenericErrorResponse{Message: msgs.UnsupportedImportFormat})
+	}
+	if invalidFields.HasInvalidFields() {
+		log.Error(msgs.InvalidImportDetails)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(msgs.InvalidImportDetails, invalidFields))
+	}
+	transfer, validationErr := domain.NewValidatedTransfer(collections, tasks)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+	if async {
+		return h.enqueueImport(ctx, *transfer, userId)
+	}
+
+	result, err := h.service.Import(*transfer, userId)
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	return writeCreatedResponse(ctx, response.NewTransferResult(*result))
+}
+
+// ImportTodoTxt
+// @ID 			ImportTodoTxt
+// @Summary		Import the tasks of a todo.txt file
+// @Tags 		Transfer
+// @Description Route that creates a task for each line of a todo.txt file, sent as text/plain or as multipart/form-data in the file field. The first project names the collection of the task, the underscores being read as spaces, and the collections of the user are matched by name, ignoring the case, or created. The lines without a project go to the Imported collection. The contexts become the tags, the due key the due date and the creation date is kept. The priority and the completion date are not stored, and the other projects and key:value extras are kept in the description. Every line is validated first and nothing is imported if any of them is invalid.
+// @Accept 		plain
+// @Accept 		mpfd
+// @Produce 	json
+// @Security	bearerAuth
+// @Param 	    userId       path       int                  true                  "User ID"    default(1)
+// @Param 		file 	     formData 	file                 false                 "todo.txt file"
+// @Param 	    Idempotency-Key header      string               false                 "Unique key that makes retries of this request return the first response"
+// @Success 	201 		 {object} 	response.SwaggerTaskImportResponse         "Tasks successfully imported"
+// @Failure 	400 		 {object} 	response.SwaggerBadRequestResponse         "The user has made a bad request"
+// @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403          {object}   response.SwaggerForbiddenResponse 	       "The user does not have access to this information"
+// @Failure 	415 		 {object} 	response.SwaggerGenericErrorResponse       "The format of the file is not supported"
+// @Failure 	422 		 {object} 	response.SwaggerValidationErrorResponse    "Some lines could not be imported because they are not valid"
+// @Failure 	500 		 {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/user/{userId}/import/todo.txt  [post]
+func (h Transfer) ImportTodoTxt(ctx echo.Context) error {
+	userId, err := convertToPositiveInteger(ctx.Param("userId"), msgs.UserId)
+	if err != nil {
+		log.Error(err)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.UserId, msgs.ConversionError)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
+	}
+
+	file, err := openUploadedFile(ctx, echo.MIMETextPlain)
+	if err != nil {
+		log.Error(err)
+		return writeUploadError(ctx, err, echo.MIMETextPlain)
+	}
+	defer file.Close()
+
+	tasks, validationErr := todotxt.Decode(file)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+
+	ids, err := h.service.ImportTasks(tasks, userId)
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	return writeCreatedResponse(ctx, response.NewTaskImportResult(ids))
+}
+
+// ImportFrom
+// @ID 			ImportFrom
+// @Summary		Import the export of another tool
+// @Tags 		Transfer
+// @Description Route that creates the collections and tasks of the export of another tool, sent as the request body or as multipart/form-data in the file field. The supported sources are:
+// @Description |     Source     | Format                                                      | Collections      | Tasks |
+// @Description |----------------|-------------------------------------------------------------|------------------|-------|
+// @Description | todoist        | JSON of the sync API, or CSV template of a single project   | Projects         | Items, with their labels as tags |
+// @Description | trello         | JSON export of a board                                      | The board        | Open cards, with their list and labels as tags |
+// @Description | microsoft-todo | JSON of the lists of the Microsoft Graph API with their tasks | Lists          | Tasks, with their categories as tags |
+// @Description The Todoist CSV template is imported into a collection named by the name parameter, or else by the name of the file. The texts longer than the API accepts are cut. With dry_run=true nothing is created and the response has the collections and tasks that would be, with the IDs used to relate them. With async=true the file is imported in the background, as the import route does.
+// @Accept 		json
+// @Accept 		text/csv
+// @Accept 		mpfd
+// @Produce 	json
+// @Security	bearerAuth
+// @Param 	    userId       path       int                  true                  "User ID"    default(1)
+// @Param 	    source       path       string               true                  "Tool that exported the file"    Enums(todoist, trello, microsoft-todo)
+// @Param 	    dry_run      query      bool                 false                 "Only report what would be created"
+// @Param 	    async        query      bool                 false                 "Import in the background"
+// @Param 	    name         query      string               false                 "Name of the collection of a single project file"
+// @Param 		file 	     formData 	file                 false                 "Exported file"
+// @Param 	    Idempotency-Key header      string               false                 "Unique key that makes retries of this request return the first response"
+// @Success 	200 		 {object} 	response.SwaggerTransferResponse           "Collections and tasks that would be created by the import"
+// @Success 	201 		 {object} 	response.SwaggerTransferResultResponse     "Collections and tasks successfully imported"
+// @Success 	202 		 {object} 	response.SwaggerImportJobResponse          "Import queued"
+// @Header 		202          {string}   Location                                   "Route of the status of the import"
+// @Failure 	400 		 {object} 	response.SwaggerBadRequestResponse         "The user has made a bad request"
+// @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403          {object}   response.SwaggerForbiddenResponse 	       "The user does not have access to this information"
+// @Failure 	415 		 {object} 	response.SwaggerGenericErrorResponse       "The format of the file is not supported"
+// @Failure 	422 		 {object} 	response.SwaggerValidationErrorResponse    "The source is not supported or the file is not valid"
+// @Failure 	500 		 {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/user/{userId}/import/{source}  [post]
+func (h Transfer) ImportFrom(ctx echo.Context) error {
+	userId, err := convertToPositiveInteger(ctx.Param("userId"), msgs.UserId)
+	if err != nil {
+		log.Error(err)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.UserId, msgs.ConversionError)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
+	}
+	importer, ok := h.importers.Find(ctx.Param("source"))
+	if !ok {
+		message := fmt.Sprintf(msgs.UnsupportedSource, strings.Join(h.importers.Sources(), ", "))
+		log.Error(message)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.Source, message)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(message, invalidFields))
+	}
+	dryRun, validationErr := parseBoolQueryParam(ctx, "dry_run", msgs.DryRun)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+	async, validationErr := parseBoolQueryParam(ctx, "async", msgs.Async)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+
+	file, err := openUploadedFile(ctx, echo.MIMEApplicationJSON, mimeTextCSV)
+	if err != nil {
+		log.Error(err)
+		return writeUploadError(ctx, err, echo.MIMEApplicationJSON, mimeTextCSV)
+	}
+	defer file.Close()
+
+	name := ctx.QueryParam("name")
+	if name == "" {
+		name = uploadedFileName(ctx)
+	}
+	transfer, validationErr := importer.Decode(file, name)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+	if dryRun {
+		return writeAcceptResponse(ctx, response.NewTransfer(*transfer))
+	}
+	if async {
+		return h.enqueueImport(ctx, *transfer, userId)
+	}
+
+	result, err := h.service.Import(*transfer, userId)
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	return writeCreatedResponse(ctx, response.NewTransferResult(*result))
+}
+
+// FindImport
+// @ID 			FindImport
+// @Summary		Find an import made in the background
+// @Tags 		Transfer
+// @Description Route that tells how far an import made with async=true has gone, its status being queued, running, succeeded or failed. A failed import has imported nothing, and its last error tells why. The import is not tried again, as an import whose instance has stopped after committing it would otherwise be imported twice.
+// @Produce 	json
+// @Security	bearerAuth
+// @Param 	    userId       path       int                  true                  "User ID"    default(1)
+// @Param 	    jobId        path       int                  true                  "Job ID"     default(1)
+// @Success 	200 		 {object} 	response.SwaggerImportJobResponse          "Successful request"
+// @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403          {object}   response.SwaggerForbiddenResponse 	       "The user does not have access to this information"
+// @Failure 	404 		 {object} 	response.SwaggerNotFoundErrorResponse 	   "The user has requested a non-existent resource"
+// @Failure 	422 		 {object} 	response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Failure 	500 		 {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/user/{userId}/import/job/{jobId}  [get]
+func (h Transfer) FindImport(ctx echo.Context) error {
+	userId, err := convertToPositiveInteger(ctx.Param("userId"), msgs.UserId)
+	if err != nil {
+		log.Error(err)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.UserId, msgs.ConversionError)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
+	}
+	jobId, validationErr := parseJobParams(ctx)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+
+	job, err := h.service.FindImport(jobId, userId)
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	return writeAcceptResponse(ctx, response.NewImportJob(*job))
+}
+
+// enqueueImport answers with the queued job, the Location header pointing to the route of its status
+func (h Transfer) enqueueImport(ctx echo.Context, transfer domain.Transfer, userId int) error {
+	jobId, err := h.service.EnqueueImport(transfer, userId)
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	path := ctx.Request().URL.Path
+	importPath := path[:strings.LastIndex(path, "/import")+len("/import")]
+	ctx.Response().Header().Set(echo.HeaderLocation, fmt.Sprintf("%s/job/%d", importPath, jobId))
+	return ctx.JSON(http.StatusAccepted, response.NewQueuedImportJob(jobId))
+}
+
+// parseBoolQueryParam reads an optional boolean query parameter, false when it is missing
+func parseBoolQueryParam(ctx echo.Context, name, field string) (bool, *todoerrors.Validation) {
+	value := ctx.QueryParam(name)
+	if value == "" {
+		return false, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(field, msgs.ConversionError)
+		return false, todoerrors.NewValidationError(msgs.InvalidQueryParams, invalidFields)
+	}
+
+	return parsed, nil
+}
+
+// readFormCsvRows reads the CSV file sent in the form field, a missing file having no rows
+func readFormCsvRows(ctx echo.Context, field, fileName string, requiredColumns []string,
+	invalidFields *todoerrors.InvalidFields) ([]map[string]string, error) {
+	fileHeader, err := ctx.FormFile(field)
+	if errors.Is(err, http.ErrMissingFile) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return readCsvRows(file, fileName, requiredColumns, invalidFields), nil
+}
+
+// exportStream delays the response until the first row has been read, so that the errors raised before it are
+// still reported with their status. Once the response has started, an error can only cut it short.
+type exportStream struct {
+	ctx         echo.Context
+	contentType string
+	fileName    string
+	started     bool
+}
+
+func newExportStream(ctx echo.Context, contentType, fileName string) *exportStream {
+	return &exportStream{ctx: ctx, contentType: contentType, fileName: fileName}
+}
+
+func (s *exportStream) start(prefix string) {
+	if s.started {
+		return
+	}
+	s.started = true
+
+	header := s.ctx.Response().Header()
+	header.Set(echo.HeaderContentType, s.contentType)
+	header.Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", s.fileName))
+	s.ctx.Response().WriteHeader(http.StatusOK)
+	if _, err := fmt.Fprint(s.ctx.Response(), prefix); err != nil {
+		log.Error(err)
+	}
+}
+
+func (s *exportStream) fail(err error) error {
+	if s.started {
+		return nil
+	}
+	return handleServiceErrors(s.ctx, err)
+}
+
+type csvStreamWriter struct {
+	ctx    echo.Context
+	stream *exportStream
+	writer *csv.Writer
+	header []string
+	rows   int
+}
+
+func newCsvStreamWriter(ctx echo.Context, stream *exportStream, header []string) *csvStreamWriter {
+	return &csvStreamWriter{ctx: ctx, stream: stream, writer: csv.NewWriter(ctx.Response()), header: header}
+}
+
+func (w *csvStreamWriter) write(record []string) error {
+	w.begin()
+	if err := w.writer.Write(record); err != nil {
+		return err
+	}
+	w.rows++
+	if w.rows%csvFlushRows == 0 {
+		w.writer.Flush()
+		w.ctx.Response().Flush()
+	}
+
+	return w.writer.Error()
+}
+
+// close writes the header even when there are no rows
+func (w *csvStreamWriter) close() error {
+	w.begin()
+	w.writer.Flush()
+	if err := w.writer.Error(); err != nil {
+		log.Error(err)
+	}
+
+	return nil
+}
+
+func (w *csvStreamWriter) begin() {
+	if w.stream.started {
+		return
+	}
+	w.stream.start("")
+	if err := w.writer.Write(w.header); err != nil {
+		log.Error(err)
+	}
+}
@@ -0,0 +1,469 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"todo/src/core/domain"
+	"todo/src/core/importers"
+	"todo/src/core/projecterrors/todoerrors"
+	"todo/src/core/services"
+)
+
+type MockTransferService struct {
+	mock.Mock
+}
+
+func (m *MockTransferService) Export(userId int, writeCollection func(collection domain.Collection) error,
+	writeTask func(task domain.Task) error) error {
+	args := m.Called(userId)
+	if collections, ok := args.Get(0).([]domain.Collection); ok {
+		for _, collection := range collections {
+			if err := writeCollection(collection); err != nil {
+				return err
+			}
+		}
+	}
+	if tasks, ok := args.Get(1).([]domain.Task); ok {
+		for _, task := range tasks {
+			if err := writeTask(task); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(2)
+}
+
+func (m *MockTransferService) ExportCollections(userId int, write func(collection domain.Collection) error) error {
+	args := m.Called(userId)
+	if collections, ok := args.Get(0).([]domain.Collection); ok {
+		for _, collection := range collections {
+			if err := write(collection); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockTransferService) ExportTasks(userId int, write func(task domain.Task) error) error {
+	args := m.Called(userId)
+	if tasks, ok := args.Get(0).([]domain.Task); ok {
+		for _, task := range tasks {
+			if err := write(task); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockTransferService) Import(transfer domain.Transfer, userId int) (*domain.TransferResult, error) {
+	args := m.Called(transfer, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.TransferResult), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockTransferService) EnqueueImport(transfer domain.Transfer, userId int) (int64, error) {
+	args := m.Called(transfer, userId)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTransferService) FindImport(jobId int64, userId int) (*domain.Job, error) {
+	args := m.Called(jobId, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.Job), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockTransferService) ImportTasks(tasks []domain.Task, userId int) ([]int, error) {
+	args := m.Called(tasks, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).([]int), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func newTransferContext(method, target, contentType string, body *bytes.Buffer) (echo.Context,
+	*httptest.ResponseRecorder) {
+	if body == nil {
+		body = &bytes.Buffer{}
+	}
+	requestData := httptest.NewRequest(method, target, body)
+	if contentType != "" {
+		requestData.Header.Set("Content-Type", contentType)
+	}
+	responseData := httptest.NewRecorder()
+	context := echo.New().NewContext(requestData, responseData)
+	context.SetParamNames("userId")
+	context.SetParamValues("1")
+
+	return context, responseData
+}
+
+func TestTransfer_Export(t *testing.T) {
+	collections := []domain.Collection{*domain.NewCollection(1, "Study"), *domain.NewCollection(3, "Sport")}
+	tasks := []domain.Task{
+		*domain.NewTask(1, "Study Math", true, domain.NewCollection(1, "Study")),
+		*domain.NewTask(5, "Read, then write", false, domain.NewCollection(0, "")),
+	}
+
+	t.Run("should stream the tasks as CSV with the fixture columns", func(t *testing.T) {
+		context, responseData := newTransferContext(http.MethodGet, "/user/1/export/task.csv", "", nil)
+		mockService := new(MockTransferService)
+		transferHandler := Transfer{service: mockService}
+		mockService.On("ExportTasks", 1).Return(tasks, nil)
+
+		_ = transferHandler.ExportTasksCsv(context)
+
+		expectedBody := "id,description,finished,user_id,collection_id\n" +
+			"1,Study Math,true,1,1\n" +
+			"5,\"Read, then write\",false,1,\n"
+
+		assert.Equal(t, http.StatusOK, responseData.Code)
+		assert.Equal(t, "text/csv", responseData.Header().Get("Content-Type"))
+		assert.Equal(t, expectedBody, responseData.Body.String())
+	})
+
+	t.Run("should write only the header when there are no collections", func(t *testing.T) {
+		context, responseData := newTransferContext(http.MethodGet, "/user/1/export/collection.csv", "", nil)
+		mockService := new(MockTransferService)
+		transferHandler := Transfer{service: mockService}
+		mockService.On("ExportCollections", 1).Return(nil, nil)
+
+		_ = transferHandler.ExportCollectionsCsv(context)
+
+		assert.Equal(t, http.StatusOK, responseData.Code)
+		assert.Equal(t, "id,name,user_id\n", responseData.Body.String())
+	})
+
+	t.Run("should stream the collections and tasks as JSON", func(t *testing.T) {
+		context, responseData := newTransferContext(http.MethodGet, "/user/1/export", "", nil)
+		mockService := new(MockTransferService)
+		transferHandler := Transfer{service: mockService}
+		mockService.On("Export", 1).Return(collections, tasks, nil)
+
+		_ = transferHandler.Export(context)
+
+		var exported map[string][]map[string]interface{}
+		err := json.Unmarshal(responseData.Body.Bytes(), &exported)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, responseData.Code)
+		assert.Len(t, exported["collections"], 2)
+		assert.Len(t, exported["tasks"], 2)
+		assert.Equal(t, "Sport", exported["collections"][1]["name"])
+		assert.Equal(t, float64(1), exported["tasks"][0]["collection_id"])
+	})
+
+	t.Run("should write valid JSON when there is nothing to export", func(t *testing.T) {
+		context, responseData := newTransferContext(http.MethodGet, "/user/1/export", "", nil)
+		mockService := new(MockTransferService)
+		transferHandler := Transfer{service: mockService}
+		mockService.On("Export", 1).Return(nil, nil, nil)
+
+		_ = transferHandler.Export(context)
+
+		assert.Equal(t, http.StatusOK, responseData.Code)
+		assert.Equal(t, "{\"collections\":[],\"tasks\":[]}\n", responseData.Body.String())
+	})
+
+	t.Run("should return 500 when the export fails before any row is written", func(t *testing.T) {
+		context, responseData := newTransferContext(http.MethodGet, "/user/1/export/task.csv", "", nil)
+		mockService := new(MockTransferService)
+		transferHandler := Transfer{service: mockService}
+		mockService.On("ExportTasks", 1).Return(nil,
+			todoerrors.NewUnexpectedInternalError("Oops! An unexpected error has occurred."))
+
+		_ = transferHandler.ExportTasksCsv(context)
+
+		assert.Equal(t, http.StatusInternalServerError, responseData.Code)
+		assert.Equal(t, "{\"message\":\"Oops! An unexpected error has occurred.\"}\n", responseData.Body.String())
+	})
+}
+
+func TestTransfer_Import(t *testing.T) {
+	t.Run("should return 201 with the new IDs when the JSON file is valid", func(t *testing.T) {
+		body := bytes.NewBufferString(`{"collections":[{"id":7,"name":"Study"}],` +
+			`"tasks":[{"id":1,"description":"Study Math","finished":true,"collection_id":7,"tags":["School"]}]}`)
+		context, responseData := newTransferContext(http.MethodPost, "/user/1/import", "application/json", body)
+		mockService := new(MockTransferService)
+		transferHandler := Transfer{service: mockService}
+		mockService.On("Import", mock.MatchedBy(func(transfer domain.Transfer) bool {
+			return len(transfer.Collections()) == 1 && transfer.Tasks()[0].Collection().Id() == 7 &&
+				transfer.Tasks()[0].Tags()[0] == "school"
+		}), 1).Return(domain.NewTransferResult(map[int]int{7: 21}, map[int]int{1: 40}), nil)
+
+		_ = transferHandler.Import(context)
+
+		assert.Equal(t, http.StatusCreated, responseData.Code)
+		assert.Equal(t, "{\"collection_ids\":{\"7\":21},\"task_ids\":{\"1\":40}}\n", responseData.Body.String())
+	})
+
+	t.Run("should return 202 with the queued job when the import is asked to be made in the background",
+		func(t *testing.T) {
+			body := bytes.NewBufferString(`{"collections":[{"id":7,"name":"Study"}]}`)
+			context, responseData := newTransferContext(http.MethodPost, "/api/user/1/import?async=true",
+				"application/json", body)
+			mockService := new(MockTransferService)
+			transferHandler := Transfer{service: mockService}
+			mockService.On("EnqueueImport", mock.MatchedBy(func(transfer domain.Transfer) bool {
+				return len(transfer.Collections()) == 1 && transfer.Collections()[0].Name() == "Study"
+			}), 1).Return(int64(12), nil)
+
+			_ = transferHandler.Import(context)
+
+			assert.Equal(t, http.StatusAccepted, responseData.Code)
+			assert.Equal(t, "/api/user/1/import/job/12", responseData.Header().Get(echo.HeaderLocation))
+			assert.Equal(t, "{\"id\":12,\"status\":\"queued\"}\n", responseData.Body.String())
+			mockService.AssertNotCalled(t, "Import", mock.Anything, mock.Anything)
+		})
+
+	t.Run("should return 422 when async is not a boolean", func(t *testing.T) {
+		context, responseData := newTransferContext(http.MethodPost, "/user/1/import?async=soon", "application/json",
+			bytes.NewBufferString(`{}`))
+		mockService := new(MockTransferService)
+		transferHandler := Transfer{service: mockService}
+
+		_ = transferHandler.Import(context)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, responseData.Code)
+		mockService.AssertNotCalled(t, "EnqueueImport", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return 201 when the CSV files are valid", func(t *testing.T) {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		collectionFile, _ := writer.CreateFormFile("collection", "collection.csv")
+		_, _ = collectionFile.Write([]byte("id,name,user_id\n1,Study,9\n3,Sport,9\n"))
+		taskFile, _ := writer.CreateFormFile("task", "task.csv")
+		_, _ = taskFile.Write([]byte("id,description,finished,user_id,collection_id\n1,Run,false,9,3\n2,Rest,true,9,\n"))
+		_ = writer.Close()
+		context, responseData := newTransferContext(http.MethodPost, "/user/1/import",
+			writer.FormDataContentType(), body)
+		mockService := new(MockTransferService)
+		transferHandler := Transfer{service: mockService}
+		mockService.On("Import", mock.MatchedBy(func(transfer domain.Transfer) bool {
+			return len(transfer.Collections()) == 2 && len(transfer.Tasks()) == 2 &&
+				transfer.Tasks()[0].Collection().Id() == 3 && transfer.Tasks()[1].Collection().Id() == 0
+		}), 1).Return(domain.NewTransferResult(map[int]int{1: 5, 3: 6}, map[int]int{1: 8, 2: 9}), nil)
+
+		_ = transferHandler.Import(context)
+
+		assert.Equal(t, http.StatusCreated, responseData.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return 422 with every invalid row without importing", func(t *testing.T) {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		taskFile, _ := writer.CreateFormFile("task", "task.csv")
+		_, _ = taskFile.Write([]byte("id,description,finished\n1,Run,maybe\nx,Rest,true\n"))
+		_ = writer.Close()
+		context, responseData := newTransferContext(http.MethodPost, "/user/1/import",
+			writer.FormDataContentType(), body)
+		mockService := new(MockTransferService)
+		transferHandler := Transfer{service: mockService}
+
+		_ = transferHandler.Import(context)
+
+		expectedBody := "{\"message\":\"The import provided is invalid.\",\"invalid_fields\":[" +
+			"{\"name\":\"Task 1\",\"description\":\"The finished value provided is invalid. It must be true or false.\"}," +
+			"{\"name\":\"Task 2\",\"description\":\"The ID provided is invalid. The ID must be a positive integer.\"}]}\n"
+
+		assert.Equal(t, http.StatusUnprocessableEntity, responseData.Code)
+		assert.Equal(t, expectedBody, responseData.Body.String())
+		mockService.AssertNotCalled(t, "Import", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return 422 when a task refers to a collection not in the file", func(t *testing.T) {
+		body := bytes.NewBufferString(`{"tasks":[{"id":1,"description":"Run","collection_id":3}]}`)
+		context, responseData := newTransferContext(http.MethodPost, "/user/1/import", "application/json", body)
+		mockService := new(MockTransferService)
+		transferHandler := Transfer{service: mockService}
+
+		_ = transferHandler.Import(context)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, responseData.Code)
+		assert.True(t, strings.Contains(responseData.Body.String(), "The collection provided is not in the file."))
+		mockService.AssertNotCalled(t, "Import", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return 415 when the format is not supported", func(t *testing.T) {
+		context, responseData := newTransferContext(http.MethodPost, "/user/1/import", "text/plain",
+			bytes.NewBufferString("Run"))
+		mockService := new(MockTransferService)
+		transferHandler := Transfer{service: mockService}
+
+		_ = transferHandler.Import(context)
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, responseData.Code)
+	})
+}
+
+func TestTransfer_TodoTxt(t *testing.T) {
+	t.Run("should stream the tasks as todo.txt lines", func(t *testing.T) {
+		context, responseData := newTransferContext(http.MethodGet, "/user/1/export/todo.txt", "", nil)
+		mockService := new(MockTransferService)
+		transferHandler := Transfer{service: mockService}
+		task := domain.NewTask(1, "Study Math", false, domain.NewCollection(1, "Study"))
+		task.SetTags([]string{"school"})
+		mockService.On("ExportTasks", 1).Return([]domain.Task{
+			*task,
+			*domain.NewTask(5, "Rest", true, domain.NewCollection(0, "")),
+		}, nil)
+
+		_ = transferHandler.ExportTodoTxt(context)
+
+		assert.Equal(t, http.StatusOK, responseData.Code)
+		assert.Equal(t, "text/plain; charset=UTF-8", responseData.Header().Get("Content-Type"))
+		assert.Equal(t, "Study Math +Study @school\nx Rest\n", responseData.Body.String())
+	})
+
+	t.Run("should return 201 with the IDs of the tasks of the uploaded file", func(t *testing.T) {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		file, _ := writer.CreateFormFile("file", "todo.txt")
+		_, _ = file.Write([]byte("(A) Study Math +Study @school\nx Rest\n"))
+		_ = writer.Close()
+		context, responseData := newTransferContext(http.MethodPost, "/user/1/import/todo.txt",
+			writer.FormDataContentType(), body)
+		mockService := new(MockTransferService)
+		transferHandler := Transfer{service: mockService}
+		mockService.On("ImportTasks", mock.MatchedBy(func(tasks []domain.Task) bool {
+			return len(tasks) == 2 && tasks[0].Collection().Name() == "Study" && tasks[1].Finished()
+		}), 1).Return([]int{40, 41}, nil)
+
+		_ = transferHandler.ImportTodoTxt(context)
+
+		assert.Equal(t, http.StatusCreated, responseData.Code)
+		assert.Equal(t, "{\"ids\":[40,41]}\n", responseData.Body.String())
+	})
+
+	t.Run("should return 422 with the invalid lines without importing", func(t *testing.T) {
+		context, responseData := newTransferContext(http.MethodPost, "/user/1/import/todo.txt", "text/plain",
+			bytes.NewBufferString("Study Math\n@school\n"))
+		mockService := new(MockTransferService)
+		transferHandler := Transfer{service: mockService}
+
+		_ = transferHandler.ImportTodoTxt(context)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, responseData.Code)
+		assert.Contains(t, responseData.Body.String(), "Line 2")
+		mockService.AssertNotCalled(t, "ImportTasks", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTransfer_ImportFrom(t *testing.T) {
+	board := `{"id":"b1","name":"Launch","lists":[{"id":"l1","name":"Doing"}],` +
+		`"cards":[{"name":"Write the post","idList":"l1","dueComplete":true}]}`
+
+	t.Run("should report what would be created without importing in a dry run", func(t *testing.T) {
+		context, responseData := newTransferContext(http.MethodPost, "/user/1/import/trello?dry_run=true",
+			"application/json", bytes.NewBufferString(board))
+		context.SetParamNames("userId", "source")
+		context.SetParamValues("1", "trello")
+		mockService := new(MockTransferService)
+		transferHandler := Transfer{service: mockService, importers: importers.NewDefaultRegistry()}
+
+		_ = transferHandler.ImportFrom(context)
+
+		assert.Equal(t, http.StatusOK, responseData.Code)
+		assert.Equal(t, `{"collections":[{"id":1,"name":"Launch"}],"tasks":[{"id":1,"description":"Write the post",`+
+			`"finished":true,"collection_id":1,"tags":["doing"]}]}`+"\n", responseData.Body.String())
+		mockService.AssertNotCalled(t, "Import", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return 201 with the new IDs of the imported collections and tasks", func(t *testing.T) {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		file, _ := writer.CreateFormFile("file", "Errands.csv")
+		_, _ = file.Write([]byte("TYPE,CONTENT,PRIORITY\ntask,Call the bank,4\n"))
+		_ = writer.Close()
+		context, responseData := newTransferContext(http.MethodPost, "/user/1/import/todoist",
+			writer.FormDataContentType(), body)
+		context.SetParamNames("userId", "source")
+		context.SetParamValues("1", "todoist")
+		mockService := new(MockTransferService)
+		transferHandler := Transfer{service: mockService, importers: importers.NewDefaultRegistry()}
+		mockService.On("Import", mock.MatchedBy(func(transfer domain.Transfer) bool {
+			return transfer.Collections()[0].Name() == "Errands" && transfer.Tasks()[0].Description() == "Call the bank"
+		}), 1).Return(domain.NewTransferResult(map[int]int{1: 7}, map[int]int{1: 30}), nil)
+
+		_ = transferHandler.ImportFrom(context)
+
+		assert.Equal(t, http.StatusCreated, responseData.Code)
+		assert.Equal(t, "{\"collection_ids\":{\"1\":7},\"task_ids\":{\"1\":30}}\n", responseData.Body.String())
+	})
+
+	t.Run("should queue the import of the file with the route of the job under the import", func(t *testing.T) {
+		context, responseData := newTransferContext(http.MethodPost, "/api/user/1/import/trello?async=1",
+			"application/json", bytes.NewBufferString(board))
+		context.SetParamNames("userId", "source")
+		context.SetParamValues("1", "trello")
+		mockService := new(MockTransferService)
+		transferHandler := Transfer{service: mockService, importers: importers.NewDefaultRegistry()}
+		mockService.On("EnqueueImport", mock.Anything, 1).Return(int64(4), nil)
+
+		_ = transferHandler.ImportFrom(context)
+
+		assert.Equal(t, http.StatusAccepted, responseData.Code)
+		assert.Equal(t, "/api/user/1/import/job/4", responseData.Header().Get(echo.HeaderLocation))
+	})
+
+	t.Run("should return 422 when the source is not supported", func(t *testing.T) {
+		context, responseData := newTransferContext(http.MethodPost, "/user/1/import/asana", "application/json",
+			bytes.NewBufferString(board))
+		context.SetParamNames("userId", "source")
+		context.SetParamValues("1", "asana")
+		transferHandler := Transfer{importers: importers.NewDefaultRegistry()}
+
+		_ = transferHandler.ImportFrom(context)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, responseData.Code)
+		assert.Contains(t, responseData.Body.String(), "microsoft-todo, todoist, trello")
+	})
+}
+
+func TestTransfer_FindImport(t *testing.T) {
+	t.Run("should return the status of the import of the user", func(t *testing.T) {
+		context, responseData := newTransferContext(http.MethodGet, "/user/1/import/job/12", "", nil)
+		context.SetParamNames("userId", "jobId")
+		context.SetParamValues("1", "12")
+		mockService := new(MockTransferService)
+		transferHandler := Transfer{service: mockService}
+		job := domain.NewJob(12, services.ImportJobKind, []byte(`{"collections":[]}`), time.Now())
+		job.SetState(domain.JobFailed, 1, 1, "the connection was lost")
+		mockService.On("FindImport", int64(12), 1).Return(job, nil)
+
+		_ = transferHandler.FindImport(context)
+
+		assert.Equal(t, http.StatusOK, responseData.Code)
+		assert.Equal(t, "{\"id\":12,\"status\":\"failed\",\"last_error\":\"the connection was lost\"}\n",
+			responseData.Body.String())
+	})
+
+	t.Run("should return 404 when the job is not an import of the user", func(t *testing.T) {
+		context, responseData := newTransferContext(http.MethodGet, "/user/1/import/job/12", "", nil)
+		context.SetParamNames("userId", "jobId")
+		context.SetParamValues("1", "12")
+		mockService := new(MockTransferService)
+		transferHandler := Transfer{service: mockService}
+		mockService.On("FindImport", int64(12), 1).Return(nil, todoerrors.NewNotFoundError())
+
+		_ = transferHandler.FindImport(context)
+
+		assert.Equal(t, http.StatusNotFound, responseData.Code)
+	})
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+	"todo/src/app/api/endpoints/handlers/msgs"
+	interfaces "todo/src/core/interfaces/services"
+	"todo/src/core/projecterrors/todoerrors"
+	"todo/src/core/services"
+	"todo/src/infra/postgres"
+)
+
+const headerUndoToken = "Undo-Token"
+
+type Undo struct {
+	service interfaces.IUndo
+}
+
+func NewUndoHandler() *Undo {
+	connectionManager := postgres.NewPostgresConnectionManager()
+	repository := postgres.NewUndoPostgresRepository(connectionManager)
+	service := services.NewUndoService(repository)
+	return &Undo{service}
+}
+
+// Undo
+// @ID 			Undo
+// @Summary		Undo an operation
+// @Tags 		Undo
+// @Description Route that reverses an operation with the token returned in its Undo-Token header. Task updates, task deletions, task batches and collection deletions can be undone for 10 minutes, once each. The operation is reversed as a whole, and only if none of the affected data has changed since.
+// @Security	bearerAuth
+// @Param 	    userId       path       int                  true                  "User ID"    default(1)
+// @Param 	    token        path       string               true                  "Undo token"
+// @Success 	204 		 {object} 	nil                                        "Operation successfully undone"
+// @Failure 	400 		 {object} 	response.SwaggerValidationErrorResponse    "The user has made a bad request"
+// @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403          {object}   response.SwaggerForbiddenResponse 	       "The user does not have access to this information"
+// @Failure 	404 		 {object} 	response.SwaggerNotFoundErrorResponse 	   "The token does not exist, has expired or has already been used"
+// @Failure 	409 		 {object} 	response.SwaggerConflictErrorResponse      "The affected data has changed since the operation"
+// @Failure 	500 		 {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/user/{userId}/undo/{token}  [post]
+func (h Undo) Undo(ctx echo.Context) error {
+	userId, err := convertToPositiveInteger(ctx.Param("userId"), msgs.UserId)
+	if err != nil {
+		log.Error(err)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.UserId, msgs.ConversionError)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
+	}
+
+	err = h.service.Undo(ctx.Param("token"), userId)
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	return writeNoContentResponse(ctx)
+}
+
+// setUndoToken tells the client the token that reverses the operation, if it can be undone
+func setUndoToken(ctx echo.Context, undoToken string) {
+	if undoToken != "" {
+		ctx.Response().Header().Set(headerUndoToken, undoToken)
+	}
+}
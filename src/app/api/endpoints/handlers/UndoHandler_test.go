@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+type MockUndoService struct {
+	mock.Mock
+}
+
+func (m *MockUndoService) Undo(token string, userId int) error {
+	args := m.Called(token, userId)
+	return args.Error(0)
+}
+
+func TestUndo_Undo(t *testing.T) {
+	token := "4f1c2a9e8b7d6c5e4f3a2b1c0d9e8f7a"
+
+	t.Run("should return 204 when the operation is undone", func(t *testing.T) {
+		requestData := httptest.NewRequest(http.MethodPost, "/user/1/undo/"+token, nil)
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId", "token")
+		context.SetParamValues("1", token)
+
+		mockService := new(MockUndoService)
+		undoHandler := Undo{service: mockService}
+		mockService.On("Undo", token, 1).Return(nil)
+
+		_ = undoHandler.Undo(context)
+
+		assert.Equal(t, http.StatusNoContent, responseData.Code)
+		assert.Empty(t, responseData.Body)
+	})
+
+	t.Run("should return 404 when the token does not exist or has expired", func(t *testing.T) {
+		requestData := httptest.NewRequest(http.MethodPost, "/user/1/undo/"+token, nil)
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId", "token")
+		context.SetParamValues("1", token)
+
+		mockService := new(MockUndoService)
+		undoHandler := Undo{service: mockService}
+		mockService.On("Undo", token, 1).Return(todoerrors.NewNotFoundError())
+
+		_ = undoHandler.Undo(context)
+
+		assert.Equal(t, http.StatusNotFound, responseData.Code)
+	})
+
+	t.Run("should return 409 when the data has changed since the operation", func(t *testing.T) {
+		requestData := httptest.NewRequest(http.MethodPost, "/user/1/undo/"+token, nil)
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId", "token")
+		context.SetParamValues("1", token)
+
+		mockService := new(MockUndoService)
+		undoHandler := Undo{service: mockService}
+		mockService.On("Undo", token, 1).Return(todoerrors.NewConflictError("task"))
+
+		_ = undoHandler.Undo(context)
+
+		expectedBody := "{\"message\":\"It is not possible to perform the operation because there are conflicting " +
+			"and/or duplicate data.\",\"conflicts\":[\"task\"]}\n"
+
+		assert.Equal(t, http.StatusConflict, responseData.Code)
+		assert.Equal(t, expectedBody, responseData.Body.String())
+	})
+
+	t.Run("should return 422 when user ID is not a positive integer", func(t *testing.T) {
+		requestData := httptest.NewRequest(http.MethodPost, "/user/abc/undo/"+token, nil)
+		responseData := httptest.NewRecorder()
+		context := echo.New().NewContext(requestData, responseData)
+		context.SetParamNames("userId", "token")
+		context.SetParamValues("abc", token)
+
+		mockService := new(MockUndoService)
+		undoHandler := Undo{service: mockService}
+
+		_ = undoHandler.Undo(context)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, responseData.Code)
+		mockService.AssertNotCalled(t, "Undo", mock.Anything, mock.Anything)
+	})
+}
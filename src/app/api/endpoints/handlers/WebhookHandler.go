@@ -0,0 +1,288 @@
+package handlers
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+	"todo/src/app/api/endpoints/dto/request"
+	"todo/src/app/api/endpoints/dto/response"
+	"todo/src/app/api/endpoints/handlers/msgs"
+	"todo/src/core/domain"
+	interfaces "todo/src/core/interfaces/services"
+	"todo/src/core/projecterrors/todoerrors"
+	"todo/src/core/services"
+	"todo/src/infra/postgres"
+	"todo/src/infra/webhook"
+)
+
+type Webhook struct {
+	service interfaces.IWebhook
+}
+
+func NewWebhookHandler() *Webhook {
+	connectionManager := postgres.NewPostgresConnectionManager()
+	repository := postgres.NewWebhookPostgresRepository(connectionManager)
+	service := services.NewWebhookService(repository, webhook.NewWebhookHttpSender())
+	return &Webhook{service}
+}
+
+// Create
+// @ID 			CreateWebhook
+// @Summary		Create a webhook
+// @Tags 		Webhook
+// @Description Route that registers a URL to which the events of the account with one of the chosen types are posted as JSON. The types are task.created, task.updated, task.finished, task.deleted, collection.created, collection.updated and collection.deleted, task.finished being sent when an update finishes a task that was not finished. The URL must point to a public host: private, loopback and link-local addresses are refused, both when the webhook is registered and when its host is resolved to post the events.
+// @Description The requests have the X-Todo-Event, X-Todo-Event-Id, X-Todo-Timestamp and X-Todo-Signature headers, the signature being sha256= followed by the hexadecimal HMAC-SHA256 of the timestamp, a dot and the body, keyed with the secret of the webhook. The secret is only returned by this route.
+// @Description A delivery is attempted up to 5 times, waiting 10, 20, 40 and 80 seconds between the attempts, until the webhook answers with a 2xx status. The events are posted in the background once the changes are committed, so that a retried delivery can arrive after the deliveries of later events; the version in the payload tells them apart. The webhook is disabled after 5 failed deliveries in a row.
+// @Accept 		json
+// @Produce 	json
+// @Security	bearerAuth
+// @Param 	    userId       path       int                              true    "User ID"    default(1)
+// @Param 		webhookJson  body 		request.SwaggerWebhookRequest    true    "URL, event types and whether the webhook is active, which it is by default"
+// @Param 	    Idempotency-Key header      string                           false   "Unique key that makes retries of this request return the first response"
+// @Success 	201          {object} 	response.SwaggerWebhookResponse            "Webhook successfully registered"
+// @Failure 	400          {object} 	response.SwaggerBadRequestResponse         "The user has made a bad request"
+// @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403          {object}   response.SwaggerForbiddenResponse   	   "The user does not have access to this information"
+// @Failure 	422 		 {object} 	response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Failure 	500 		 {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/user/{userId}/webhook  [post]
+func (h Webhook) Create(ctx echo.Context) error {
+	userId, err := convertToPositiveInteger(ctx.Param("userId"), msgs.UserId)
+	if err != nil {
+		log.Error(err)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.UserId, msgs.ConversionError)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
+	}
+	webhookData, validationErr, err := bindWebhook(ctx, -1)
+	if err != nil {
+		log.Error(err)
+		return writeBadRequestError(ctx, msgs.RequestFormatError)
+	}
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+
+	createdWebhook, err := h.service.Create(*webhookData, userId)
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	return writeCreatedResponse(ctx, response.NewWebhook(*createdWebhook))
+}
+
+// FindAll
+// @ID 			FindWebhooks
+// @Summary		List the webhooks
+// @Tags 		Webhook
+// @Description Route that lists the webhooks of the account, with the number of deliveries that have failed in a row. A webhook disabled after too many failures can be enabled again by updating it as active.
+// @Produce 	json
+// @Security	bearerAuth
+// @Param 	    userId       path       int                  true                  "User ID"    default(1)
+// @Success 	200 		 {array} 	response.SwaggerWebhookResponse            "Successful request"
+// @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403          {object}   response.SwaggerForbiddenResponse 	       "The user does not have access to this information"
+// @Failure 	422 		 {object} 	response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Failure 	500 		 {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/user/{userId}/webhook  [get]
+func (h Webhook) FindAll(ctx echo.Context) error {
+	userId, err := convertToPositiveInteger(ctx.Param("userId"), msgs.UserId)
+	if err != nil {
+		log.Error(err)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.UserId, msgs.ConversionError)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(err.Error(), invalidFields))
+	}
+
+	webhookList, err := h.service.FindAll(userId)
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	webhookResponseList := []response.Webhook{}
+	for _, webhookData := range webhookList {
+		webhookResponseList = append(webhookResponseList, *response.NewWebhook(webhookData))
+	}
+	return writeAcceptResponse(ctx, webhookResponseList)
+}
+
+// FindById
+// @ID 			FindWebhookById
+// @Summary		Find a webhook
+// @Tags 		Webhook
+// @Produce 	json
+// @Security	bearerAuth
+// @Param 	    userId       path       int                  true                  "User ID"       default(1)
+// @Param 	    webhookId    path       int                  true                  "Webhook ID"    default(1)
+// @Success 	200 		 {object} 	response.SwaggerWebhookResponse            "Successful request"
+// @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403          {object}   response.SwaggerForbiddenResponse 	       "The user does not have access to this information"
+// @Failure 	404 		 {object} 	response.SwaggerNotFoundErrorResponse 	   "The user has requested a non-existent resource"
+// @Failure 	422 		 {object} 	response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Failure 	500 		 {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/user/{userId}/webhook/{webhookId}  [get]
+func (h Webhook) FindById(ctx echo.Context) error {
+	userId, webhookId, validationErr := parseWebhookParams(ctx)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+
+	webhookData, err := h.service.FindById(webhookId, userId)
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	return writeAcceptResponse(ctx, response.NewWebhook(*webhookData))
+}
+
+// Update
+// @ID 			UpdateWebhook
+// @Summary		Update a webhook
+// @Tags 		Webhook
+// @Description Route that replaces the URL, the event types and the state of a webhook, keeping its secret. Enabling a webhook again clears its failures.
+// @Accept 		json
+// @Produce 	json
+// @Security	bearerAuth
+// @Param 	    userId       path       int                              true    "User ID"       default(1)
+// @Param 	    webhookId    path       int                              true    "Webhook ID"    default(1)
+// @Param 		webhookJson  body 		request.SwaggerWebhookRequest    true    "URL, event types and whether the webhook is active, which it is by default"
+// @Success 	204          {object}   nil                                        "Webhook successfully edited"
+// @Failure 	400          {object} 	response.SwaggerBadRequestResponse         "The user has made a bad request"
+// @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403          {object}   response.SwaggerForbiddenResponse   	   "The user does not have access to this information"
+// @Failure 	404 		 {object} 	response.SwaggerNotFoundErrorResponse 	   "The user has requested a non-existent resource"
+// @Failure 	422 		 {object} 	response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Failure 	500 		 {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/user/{userId}/webhook/{webhookId}  [put]
+func (h Webhook) Update(ctx echo.Context) error {
+	userId, webhookId, validationErr := parseWebhookParams(ctx)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+	webhookData, validationErr, err := bindWebhook(ctx, webhookId)
+	if err != nil {
+		log.Error(err)
+		return writeBadRequestError(ctx, msgs.RequestFormatError)
+	}
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+
+	if err = h.service.Update(*webhookData, userId); err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	return writeNoContentResponse(ctx)
+}
+
+// Delete
+// @ID 			DeleteWebhook
+// @Summary		Delete a webhook
+// @Tags 		Webhook
+// @Description Route that deletes a webhook along with its delivery log. The deliveries already started are still attempted.
+// @Security	bearerAuth
+// @Param 	    userId       path       int                  true                  "User ID"       default(1)
+// @Param 	    webhookId    path       int                  true                  "Webhook ID"    default(1)
+// @Success 	204          {object}   nil                                        "Webhook successfully deleted"
+// @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403          {object}   response.SwaggerForbiddenResponse 	       "The user does not have access to this information"
+// @Failure 	404 		 {object} 	response.SwaggerNotFoundErrorResponse 	   "The user has requested a non-existent resource"
+// @Failure 	422 		 {object} 	response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Failure 	500 		 {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/user/{userId}/webhook/{webhookId}  [delete]
+func (h Webhook) Delete(ctx echo.Context) error {
+	userId, webhookId, validationErr := parseWebhookParams(ctx)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+
+	if err := h.service.Delete(webhookId, userId); err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	return writeNoContentResponse(ctx)
+}
+
+// FindDeliveries
+// @ID 			FindWebhookDeliveries
+// @Summary		List the deliveries of a webhook
+// @Tags 		Webhook
+// @Description Route that lists the attempts made to post the events to a webhook, with the request body, the response status or the error that prevented a response, and the time the webhook took to answer. The total is returned in the X-Total-Count header and the pages in the Link header.
+// @Produce 	json
+// @Security	bearerAuth
+// @Param 	    userId       path       int                  true                  "User ID"       default(1)
+// @Param 	    webhookId    path       int                  true                  "Webhook ID"    default(1)
+// @Param 		limit    	 query      int                  false                 "Maximum number of deliveries, from 1 to 100"    default(20)
+// @Param 		offset    	 query      int                  false                 "Number of deliveries skipped"                   default(0)
+// @Param 		sort    	 query      string               false                 "Sort field: id or created_at"                   default(id)
+// @Param 		order    	 query      string               false                 "Sort order: asc or desc"                        default(asc)
+// @Success 	200 		 {array} 	response.SwaggerWebhookDeliveryResponse    "Successful request"
+// @Failure 	401          {object}   response.SwaggerUnauthorizedResponse 	   "The user is not authorized to make this request"
+// @Failure 	403          {object}   response.SwaggerForbiddenResponse 	       "The user does not have access to this information"
+// @Failure 	404 		 {object} 	response.SwaggerNotFoundErrorResponse 	   "The user has requested a non-existent resource"
+// @Failure 	422 		 {object} 	response.SwaggerValidationErrorResponse    "Some entered data could not be processed because it is not valid"
+// @Failure 	500 		 {object} 	response.SwaggerGenericErrorResponse       "An unexpected server error has occurred"
+// @Router 		/user/{userId}/webhook/{webhookId}/delivery  [get]
+func (h Webhook) FindDeliveries(ctx echo.Context) error {
+	userId, webhookId, validationErr := parseWebhookParams(ctx)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+	pagination, validationErr := parsePagination(ctx, domain.WebhookDeliverySortFields)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return writeValidationError(ctx, *validationErr)
+	}
+
+	deliveryList, total, err := h.service.FindDeliveries(webhookId, userId, *pagination)
+	if err != nil {
+		log.Error(err)
+		return handleServiceErrors(ctx, err)
+	}
+
+	deliveryResponseList := []response.WebhookDelivery{}
+	for _, delivery := range deliveryList {
+		deliveryResponseList = append(deliveryResponseList, *response.NewWebhookDelivery(delivery))
+	}
+	setPaginationHeaders(ctx, *pagination, total)
+	return writeAcceptResponse(ctx, deliveryResponseList)
+}
+
+func parseWebhookParams(ctx echo.Context) (int, int, *todoerrors.Validation) {
+	invalidFields := todoerrors.InvalidFields{}
+	userId, err := convertToPositiveInteger(ctx.Param("userId"), msgs.UserId)
+	if err != nil {
+		invalidFields.AppendField(msgs.UserId, msgs.ConversionError)
+	}
+	webhookId, err := convertToPositiveInteger(ctx.Param("webhookId"), msgs.WebhookId)
+	if err != nil {
+		invalidFields.AppendField(msgs.WebhookId, msgs.ConversionError)
+	}
+	if invalidFields.HasInvalidFields() {
+		return 0, 0, todoerrors.NewValidationError(msgs.InvalidQueryParams, invalidFields)
+	}
+
+	return userId, webhookId, nil
+}
+
+// bindWebhook reads the webhook of the request, which is active unless told otherwise
+func bindWebhook(ctx echo.Context, webhookId int) (*domain.Webhook, *todoerrors.Validation, error) {
+	var requestData request.Webhook
+	if err := ctx.Bind(&requestData); err != nil {
+		return nil, nil, err
+	}
+	active := requestData.Active == nil || *requestData.Active
+
+	webhookData, validationErr := domain.NewValidatedWebhook(webhookId, requestData.Url, requestData.EventTypes, active)
+	return webhookData, validationErr, nil
+}
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"bytes"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"todo/src/core/domain"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+type MockWebhookService struct {
+	mock.Mock
+}
+
+func (m *MockWebhookService) Create(webhook domain.Webhook, userId int) (*domain.Webhook, error) {
+	args := m.Called(webhook, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.Webhook), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockWebhookService) Update(webhook domain.Webhook, userId int) error {
+	args := m.Called(webhook, userId)
+	return args.Error(0)
+}
+
+func (m *MockWebhookService) Delete(webhookId, userId int) error {
+	args := m.Called(webhookId, userId)
+	return args.Error(0)
+}
+
+func (m *MockWebhookService) FindById(webhookId, userId int) (*domain.Webhook, error) {
+	args := m.Called(webhookId, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.Webhook), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockWebhookService) FindAll(userId int) ([]domain.Webhook, error) {
+	args := m.Called(userId)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.Webhook), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockWebhookService) FindDeliveries(webhookId, userId int,
+	pagination domain.Pagination) ([]domain.WebhookDelivery, int, error) {
+	args := m.Called(webhookId, userId, pagination)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.WebhookDelivery), args.Int(1), args.Error(2)
+	}
+	return nil, 0, args.Error(2)
+}
+
+func newWebhookContext(method, target, body string, names, values []string) (echo.Context,
+	*httptest.ResponseRecorder) {
+	requestData := httptest.NewRequest(method, target, bytes.NewBufferString(body))
+	requestData.Header.Set("Content-Type", "application/json")
+	responseData := httptest.NewRecorder()
+	context := echo.New().NewContext(requestData, responseData)
+	context.SetParamNames(names...)
+	context.SetParamValues(values...)
+
+	return context, responseData
+}
+
+func TestWebhook_Create(t *testing.T) {
+	t.Run("should return the webhook with its secret", func(t *testing.T) {
+		context, responseData := newWebhookContext(http.MethodPost, "/webhook",
+			`{"url": " https://example.com/hook ", "event_types": ["Task.Finished", "task.finished", "task.created"]}`,
+			[]string{"userId"}, []string{"1"})
+		mockService := new(MockWebhookService)
+		webhookHandler := Webhook{mockService}
+		createdWebhook := domain.NewWebhook(3, "https://example.com/hook", []string{"task.finished", "task.created"},
+			true)
+		createdWebhook.SetSecret("secret")
+		mockService.On("Create", mock.MatchedBy(func(webhook domain.Webhook) bool {
+			return webhook.Url() == "https://example.com/hook" && webhook.Active() &&
+				len(webhook.EventTypes()) == 2 && webhook.EventTypes()[0] == "task.finished"
+		}), 1).Return(createdWebhook, nil)
+
+		_ = webhookHandler.Create(context)
+
+		assert.Equal(t, http.StatusCreated, responseData.Code)
+		assert.JSONEq(t, `{"id": 3, "url": "https://example.com/hook", "event_types": ["task.finished", "task.created"],
+			"active": true, "failures": 0, "secret": "secret"}`, responseData.Body.String())
+	})
+
+	t.Run("should return 422 when the URL or the event types are not valid", func(t *testing.T) {
+		context, responseData := newWebhookContext(http.MethodPost, "/webhook",
+			`{"url": "ftp://example.com", "event_types": ["task.archived"]}`, []string{"userId"}, []string{"1"})
+		webhookHandler := Webhook{new(MockWebhookService)}
+
+		_ = webhookHandler.Create(context)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, responseData.Code)
+		assert.Contains(t, responseData.Body.String(), "Webhook URL")
+		assert.Contains(t, responseData.Body.String(), "Webhook Event Types")
+	})
+
+	t.Run("should return 422 when the URL points to a host that is not public", func(t *testing.T) {
+		for _, url := range []string{"http://169.254.169.254/latest/meta-data", "http://localhost:8080/hook",
+			"https://10.0.0.5/hook", "http://[::1]/hook", "http://[::ffff:192.168.1.1]/hook", "http://100.64.0.1/hook",
+			"http://198.18.0.1/hook", "http://0.1.2.3/hook", "http://[64:ff9b::a9fe:a9fe]/hook"} {
+			context, responseData := newWebhookContext(http.MethodPost, "/webhook",
+				`{"url": "`+url+`", "event_types": ["task.created"]}`, []string{"userId"}, []string{"1"})
+			mockService := new(MockWebhookService)
+			webhookHandler := Webhook{mockService}
+
+			_ = webhookHandler.Create(context)
+
+			assert.Equal(t, http.StatusUnprocessableEntity, responseData.Code, url)
+			assert.Contains(t, responseData.Body.String(), "private, loopback or link-local", url)
+			mockService.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		}
+	})
+}
+
+func TestWebhook_Update(t *testing.T) {
+	t.Run("should disable the webhook when asked", func(t *testing.T) {
+		context, responseData := newWebhookContext(http.MethodPut, "/webhook/3",
+			`{"url": "https://example.com/hook", "event_types": ["collection.deleted"], "active": false}`,
+			[]string{"userId", "webhookId"}, []string{"1", "3"})
+		mockService := new(MockWebhookService)
+		webhookHandler := Webhook{mockService}
+		mockService.On("Update", mock.MatchedBy(func(webhook domain.Webhook) bool {
+			return webhook.Id() == 3 && !webhook.Active()
+		}), 1).Return(nil)
+
+		_ = webhookHandler.Update(context)
+
+		assert.Equal(t, http.StatusNoContent, responseData.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return 404 when the webhook does not exist", func(t *testing.T) {
+		context, responseData := newWebhookContext(http.MethodPut, "/webhook/9",
+			`{"url": "https://example.com/hook", "event_types": ["task.created"]}`,
+			[]string{"userId", "webhookId"}, []string{"1", "9"})
+		mockService := new(MockWebhookService)
+		webhookHandler := Webhook{mockService}
+		mockService.On("Update", mock.Anything, 1).Return(todoerrors.NewNotFoundError())
+
+		_ = webhookHandler.Update(context)
+
+		assert.Equal(t, http.StatusNotFound, responseData.Code)
+	})
+}
+
+func TestWebhook_FindDeliveries(t *testing.T) {
+	t.Run("should return the attempts with their outcome and timing", func(t *testing.T) {
+		context, responseData := newWebhookContext(http.MethodGet, "/webhook/3/delivery?order=desc", "",
+			[]string{"userId", "webhookId"}, []string{"1", "3"})
+		mockService := new(MockWebhookService)
+		webhookHandler := Webhook{mockService}
+		createdAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		failed := domain.NewWebhookDelivery(3, 70, "task.finished", 2, `{"id":70}`)
+		failed.SetId(9)
+		failed.SetResult(0, "connection refused", 15*time.Millisecond)
+		failed.SetCreatedAt(createdAt)
+		answered := domain.NewWebhookDelivery(3, 70, "task.finished", 1, `{"id":70}`)
+		answered.SetId(8)
+		answered.SetResult(http.StatusInternalServerError, "", 120*time.Millisecond)
+		answered.SetCreatedAt(createdAt)
+		mockService.On("FindDeliveries", 3, 1, mock.MatchedBy(func(pagination domain.Pagination) bool {
+			return pagination.IsDescending()
+		})).Return([]domain.WebhookDelivery{*failed, *answered}, 2, nil)
+
+		_ = webhookHandler.FindDeliveries(context)
+
+		assert.Equal(t, http.StatusOK, responseData.Code)
+		assert.Equal(t, "2", responseData.Header().Get(headerTotalCount))
+		assert.JSONEq(t, `[
+			{"id": 9, "event_id": 70, "event_type": "task.finished", "attempt": 2, "request": {"id": 70},
+				"error": "connection refused", "succeeded": false, "duration_ms": 15, "created_at": "2024-01-01T12:00:00Z"},
+			{"id": 8, "event_id": 70, "event_type": "task.finished", "attempt": 1, "request": {"id": 70},
+				"response_status": 500, "succeeded": false, "duration_ms": 120, "created_at": "2024-01-01T12:00:00Z"}
+		]`, responseData.Body.String())
+	})
+
+	t.Run("should return 422 when the webhook ID is not valid", func(t *testing.T) {
+		context, responseData := newWebhookContext(http.MethodGet, "/webhook/abc/delivery", "",
+			[]string{"userId", "webhookId"}, []string{"1", "abc"})
+		webhookHandler := Webhook{new(MockWebhookService)}
+
+		_ = webhookHandler.FindDeliveries(context)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, responseData.Code)
+	})
+}
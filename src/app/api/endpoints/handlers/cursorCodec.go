@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"strings"
+	"time"
+	"todo/src/app/api/endpoints/handlers/msgs"
+	"todo/src/core/domain"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+type cursorPayload struct {
+	UserId        int       `json:"u"`
+	SortField     string    `json:"s"`
+	SortDirection string    `json:"o"`
+	Id            int       `json:"i"`
+	CreatedAt     time.Time `json:"c"`
+	Backward      bool      `json:"b,omitempty"`
+}
+
+// encodeCursor serializes the cursor and signs it with the server secret, so clients cannot forge or alter it
+func encodeCursor(cursor *domain.Cursor, userId int) *string {
+	if cursor == nil {
+		return nil
+	}
+
+	payload, _ := json.Marshal(cursorPayload{
+		UserId:        userId,
+		SortField:     cursor.SortField(),
+		SortDirection: cursor.SortDirection(),
+		Id:            cursor.Id(),
+		CreatedAt:     cursor.CreatedAt(),
+		Backward:      cursor.Backward(),
+	})
+	encodedPayload := base64.RawURLEncoding.EncodeToString(payload)
+	encodedCursor := encodedPayload + "." + base64.RawURLEncoding.EncodeToString(signCursor(encodedPayload))
+
+	return &encodedCursor
+}
+
+func decodeCursor(encodedCursor string, userId int) (*domain.Cursor, *todoerrors.Validation) {
+	if encodedCursor == "" {
+		return nil, nil
+	}
+
+	invalidFields := todoerrors.InvalidFields{}
+	invalidFields.AppendField(msgs.Cursor, msgs.InvalidCursor)
+	cursorErr := todoerrors.NewValidationError(msgs.InvalidQueryParams, invalidFields)
+
+	encodedPayload, encodedSignature, found := strings.Cut(encodedCursor, ".")
+	if !found {
+		return nil, cursorErr
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, signCursor(encodedPayload)) {
+		return nil, cursorErr
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return nil, cursorErr
+	}
+	var decodedPayload cursorPayload
+	if err = json.Unmarshal(payload, &decodedPayload); err != nil || decodedPayload.UserId != userId {
+		return nil, cursorErr
+	}
+
+	return domain.NewCursor(decodedPayload.SortField, decodedPayload.SortDirection, decodedPayload.Id,
+		decodedPayload.CreatedAt, decodedPayload.Backward), nil
+}
+
+func signCursor(encodedPayload string) []byte {
+	mac := hmac.New(sha256.New, []byte(os.Getenv("SERVER_SECRET")))
+	mac.Write([]byte(encodedPayload))
+	return mac.Sum(nil)
+}
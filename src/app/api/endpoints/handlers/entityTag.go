@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"hash/fnv"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+const (
+	headerETag        = "ETag"
+	headerIfMatch     = "If-Match"
+	headerIfNoneMatch = "If-None-Match"
+	weakTagPrefix     = "W/"
+	anyEntityTag      = "*"
+)
+
+// entityTag is the strong ETag of a task or collection, derived from its row version
+func entityTag(version int) string {
+	return fmt.Sprintf("%q", strconv.Itoa(version))
+}
+
+// expandedEntityTag is a weak ETag that also changes with the related resources embedded in the response
+func expandedEntityTag(version int, data interface{}) string {
+	body, _ := json.Marshal(data)
+	hash := fnv.New64a()
+	_, _ = hash.Write(body)
+
+	return fmt.Sprintf("%s\"%d-%x\"", weakTagPrefix, version, hash.Sum64())
+}
+
+// ifMatchVersion returns the version the stored entity must have according to the If-Match header, zero when the
+// header is absent or "*". When several entity tags are listed, currentVersion resolves the one that matches.
+func ifMatchVersion(ctx echo.Context, currentVersion func() (int, error)) (int, error) {
+	header := ctx.Request().Header.Get(headerIfMatch)
+	if header == "" {
+		return 0, nil
+	}
+
+	var versions []int
+	for _, tag := range splitEntityTags(header) {
+		if tag == anyEntityTag {
+			return 0, nil
+		}
+		// If-Match uses the strong comparison, so weak tags never match
+		if strings.HasPrefix(tag, weakTagPrefix) {
+			continue
+		}
+		version, err := strconv.Atoi(strings.Trim(tag, "\""))
+		if err == nil && version > 0 {
+			versions = append(versions, version)
+		}
+	}
+	switch len(versions) {
+	case 0:
+		return -1, todoerrors.NewPreconditionFailedError()
+	case 1:
+		return versions[0], nil
+	}
+
+	version, err := currentVersion()
+	if err != nil {
+		return -1, err
+	}
+	if !slices.Contains(versions, version) {
+		return -1, todoerrors.NewPreconditionFailedError()
+	}
+
+	return version, nil
+}
+
+// writeNotModifiedOrAccept sets the ETag and answers 304 when the If-None-Match header already has it
+func writeNotModifiedOrAccept(ctx echo.Context, etag string, data interface{}) error {
+	ctx.Response().Header().Set(headerETag, etag)
+
+	for _, tag := range splitEntityTags(ctx.Request().Header.Get(headerIfNoneMatch)) {
+		// If-None-Match uses the weak comparison
+		if tag == anyEntityTag || strings.TrimPrefix(tag, weakTagPrefix) == strings.TrimPrefix(etag, weakTagPrefix) {
+			return ctx.NoContent(http.StatusNotModified)
+		}
+	}
+
+	return writeAcceptResponse(ctx, data)
+}
+
+func splitEntityTags(header string) []string {
+	var tags []string
+	for _, tag := range strings.Split(header, ",") {
+		if formattedTag := strings.TrimSpace(tag); formattedTag != "" {
+			tags = append(tags, formattedTag)
+		}
+	}
+
+	return tags
+}
@@ -1,7 +1,32 @@
 package msgs
 
 const (
-	UserId       = "User ID"
-	CollectionId = "Collection ID"
-	TaskId       = "Task ID"
+	UserId         = "User ID"
+	CollectionId   = "Collection ID"
+	TaskId         = "Task ID"
+	Limit          = "Limit"
+	Offset         = "Offset"
+	Page           = "Page"
+	Size           = "Size"
+	Finished       = "Finished"
+	CreatedFrom    = "Created From"
+	CreatedTo      = "Created To"
+	Cursor         = "Cursor"
+	BatchMode      = "Mode"
+	Operation      = "Operation %d"
+	Collection     = "Collection %d"
+	Task           = "Task %d"
+	CollectionFile = "Collection File"
+	TaskFile       = "Task File"
+	File           = "File"
+	Name           = "Name"
+	Source         = "Source"
+	DryRun         = "Dry Run"
+	Async          = "Async"
+	Report         = "Report"
+	LastEventId    = "Last Event ID"
+	WebhookId      = "Webhook ID"
+	AutomationId   = "Automation ID"
+	ReminderId     = "Reminder ID"
+	JobId          = "Job ID"
 )
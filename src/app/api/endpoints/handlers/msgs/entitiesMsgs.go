@@ -1,5 +1,7 @@
 package msgs
 
 const (
-	Request = "Request"
+	Request     = "Request"
+	UserAccount = "User %d"
+	TaskReport  = "Tasks"
 )
@@ -6,4 +6,32 @@ const (
 	ForbiddenError          = "Oops! You do not have access to this information."
 	ConversionError         = "Conversion error."
 	RequestFormatError      = "The request format is invalid."
+	InvalidQueryParams      = "The query parameters provided are invalid."
+	InvalidPage             = "The page must be greater than zero."
+	UnsupportedPatchFormat  = "The patch format is not supported. Use application/merge-patch+json or application/json-patch+json."
+	PatchNotApplicable      = "The patch could not be applied: "
+	InvalidBatchDetails     = "The batch provided is invalid."
+	InvalidBatchMode        = "The mode provided is invalid. The allowed modes are: atomic, best_effort."
+	InvalidOperationType    = "The operation type is invalid. The allowed types are: create, update, delete."
+	InvalidOperationTaskId  = "The operation must reference a task ID greater than zero."
+	MissingOperationTask    = "The operation must have the task data."
+	InvalidCursor           = "The cursor provided is invalid or has been tampered with."
+	TokenGenerationError    = "The token could not be generated."
+	UnsupportedImportFormat = "The import format is not supported. Use application/json, or multipart/form-data with the collection and task CSV files."
+	InvalidImportDetails    = "The import provided is invalid."
+	InvalidCsvFile          = "The file is not a valid CSV file: "
+	MissingCsvColumn        = "The file must have the column: "
+	InvalidCsvId            = "The ID provided is invalid. The ID must be a positive integer."
+	InvalidCsvFinished      = "The finished value provided is invalid. It must be true or false."
+	InvalidCsvCollectionId  = "The collection ID provided is invalid. It must be a positive integer or empty."
+	UnsupportedUploadType   = "The file format is not supported. Use %s, or multipart/form-data with the file field."
+	MissingUploadFile       = "The file must be sent in the file field."
+	UnsupportedSource       = "The import source is not supported. The supported sources are: %s."
+	TooManyReportTasks      = "The report must have at most %d tasks. Narrow it with the filters."
+	MissingChecklistName    = "The collection must be named by the name parameter or by a heading of the checklist."
+	InvalidCalDavParams     = "The CalDAV path parameters provided are invalid."
+	UnsupportedReport       = "The report is not supported. The supported reports are: calendar-query, calendar-multiget."
+	ObjectAlreadyExists     = "The calendar object already exists."
+	ObjectNotFound          = "The calendar object does not exist."
+	MissingGraphQLQuery     = "The request must have a GraphQL query."
 )
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/labstack/echo/v4"
+	"io"
+	"mime"
+	"net/http"
+	"todo/src/app/api/endpoints/dto/response"
+	"todo/src/app/api/endpoints/handlers/msgs"
+)
+
+const (
+	mimeMergePatch = "application/merge-patch+json"
+	mimeJSONPatch  = "application/json-patch+json"
+)
+
+// applyPatchDocument applies the JSON Merge Patch (RFC 7396) or JSON Patch (RFC 6902) of the request body on the
+// JSON representation of the stored entity and decodes the patched document into target.
+func applyPatchDocument(ctx echo.Context, original interface{}, target interface{}) *echo.HTTPError {
+	mediaType, _, err := mime.ParseMediaType(ctx.Request().Header.Get(echo.HeaderContentType))
+	if err != nil || (mediaType != mimeMergePatch && mediaType != mimeJSONPatch) {
+		return echo.NewHTTPError(http.StatusUnsupportedMediaType, msgs.UnsupportedPatchFormat)
+	}
+	patchData, err := io.ReadAll(ctx.Request().Body)
+	if err != nil || !json.Valid(patchData) {
+		return echo.NewHTTPError(http.StatusBadRequest, msgs.RequestFormatError)
+	}
+	originalData, err := json.Marshal(original)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, msgs.UnexpectedInternalError)
+	}
+
+	var patchedData []byte
+	if mediaType == mimeMergePatch {
+		patchedData, err = jsonpatch.MergePatch(originalData, patchData)
+	} else {
+		var patch jsonpatch.Patch
+		patch, err = jsonpatch.DecodePatch(patchData)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, msgs.RequestFormatError)
+		}
+		patchedData, err = patch.Apply(originalData)
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, msgs.PatchNotApplicable+err.Error())
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(patchedData))
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(target); err != nil {
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, msgs.PatchNotApplicable+err.Error())
+	}
+
+	return nil
+}
+
+func writePatchDocumentError(ctx echo.Context, err *echo.HTTPError) error {
+	return ctx.JSON(err.Code, response.GenericErrorResponse{Message: err.Message.(string)})
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"todo/src/app/api/endpoints/handlers/msgs"
+	"todo/src/core/domain"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+const (
+	dateLayout       = "2006-01-02"
+	headerLink       = "Link"
+	headerTotalCount = "X-Total-Count"
+)
+
+func parsePagination(ctx echo.Context, allowedSortFields []string) (*domain.Pagination, *todoerrors.Validation) {
+	invalidFields := todoerrors.InvalidFields{}
+	limit := parseLimit(ctx, &invalidFields)
+	offset := parseIntQueryParam(ctx, "offset", 0, msgs.Offset, &invalidFields)
+	if ctx.QueryParam("offset") == "" && ctx.QueryParam("page") != "" {
+		page := parseIntQueryParam(ctx, "page", 1, msgs.Page, &invalidFields)
+		if page < 1 {
+			invalidFields.AppendField(msgs.Page, msgs.InvalidPage)
+		}
+		offset = (page - 1) * limit
+	}
+	if invalidFields.HasInvalidFields() {
+		return nil, todoerrors.NewValidationError(msgs.InvalidQueryParams, invalidFields)
+	}
+
+	return domain.NewValidatedPagination(limit, offset, ctx.QueryParam("sort"), ctx.QueryParam("order"),
+		allowedSortFields)
+}
+
+func parseKeysetPagination(ctx echo.Context, userId int) (*domain.KeysetPagination, *todoerrors.Validation) {
+	invalidFields := todoerrors.InvalidFields{}
+	limit := parseLimit(ctx, &invalidFields)
+	if invalidFields.HasInvalidFields() {
+		return nil, todoerrors.NewValidationError(msgs.InvalidQueryParams, invalidFields)
+	}
+	cursor, validationErr := decodeCursor(ctx.QueryParam("cursor"), userId)
+	if validationErr != nil {
+		return nil, validationErr
+	}
+
+	return domain.NewValidatedKeysetPagination(limit, ctx.QueryParam("sort"), ctx.QueryParam("order"), cursor)
+}
+
+// isCursorPagination reports whether the client asked for keyset pagination, which an empty cursor parameter
+// does for the first page
+func isCursorPagination(ctx echo.Context) bool {
+	return ctx.QueryParams().Has("cursor")
+}
+
+func parseLimit(ctx echo.Context, invalidFields *todoerrors.InvalidFields) int {
+	if ctx.QueryParam("limit") == "" {
+		return parseIntQueryParam(ctx, "size", domain.DefaultPageLimit, msgs.Size, invalidFields)
+	}
+	return parseIntQueryParam(ctx, "limit", domain.DefaultPageLimit, msgs.Limit, invalidFields)
+}
+
+func parseTaskFilter(ctx echo.Context) (*domain.TaskFilter, *todoerrors.Validation) {
+	invalidFields := todoerrors.InvalidFields{}
+	var finished *bool
+	if value := ctx.QueryParam("finished"); value != "" {
+		parsedValue, err := strconv.ParseBool(value)
+		if err != nil {
+			invalidFields.AppendField(msgs.Finished, msgs.ConversionError)
+		} else {
+			finished = &parsedValue
+		}
+	}
+	collectionId := parseIntQueryParam(ctx, "collection_id", 0, msgs.CollectionId, &invalidFields)
+	createdFrom := parseTimeQueryParam(ctx, "created_from", msgs.CreatedFrom, false, &invalidFields)
+	createdTo := parseTimeQueryParam(ctx, "created_to", msgs.CreatedTo, true, &invalidFields)
+	if invalidFields.HasInvalidFields() {
+		return nil, todoerrors.NewValidationError(msgs.InvalidQueryParams, invalidFields)
+	}
+
+	return domain.NewValidatedTaskFilter(finished, collectionId, ctx.QueryParam("search"), createdFrom, createdTo,
+		ctx.QueryParam("filter"))
+}
+
+func parseCollectionFilter(ctx echo.Context) (*domain.CollectionFilter, *todoerrors.Validation) {
+	invalidFields := todoerrors.InvalidFields{}
+	createdFrom := parseTimeQueryParam(ctx, "created_from", msgs.CreatedFrom, false, &invalidFields)
+	createdTo := parseTimeQueryParam(ctx, "created_to", msgs.CreatedTo, true, &invalidFields)
+	if invalidFields.HasInvalidFields() {
+		return nil, todoerrors.NewValidationError(msgs.InvalidQueryParams, invalidFields)
+	}
+
+	return domain.NewValidatedCollectionFilter(ctx.QueryParam("search"), createdFrom, createdTo)
+}
+
+func parseExpansion(ctx echo.Context, allowedFields []string) (*domain.Expansion, *todoerrors.Validation) {
+	return domain.NewValidatedExpansion(ctx.QueryParam("expand"), allowedFields)
+}
+
+func parseIntQueryParam(ctx echo.Context, name string, defaultValue int, fieldName string,
+	invalidFields *todoerrors.InvalidFields) int {
+	value := ctx.QueryParam(name)
+	if value == "" {
+		return defaultValue
+	}
+	intValue, err := strconv.Atoi(value)
+	if err != nil {
+		invalidFields.AppendField(fieldName, msgs.ConversionError)
+		return defaultValue
+	}
+
+	return intValue
+}
+
+// parseTimeQueryParam accepts both RFC 3339 timestamps and plain dates (YYYY-MM-DD). A plain date taken as the end of
+// a range is moved to the last microsecond of its day, the precision of Postgres, so that the whole day is included.
+func parseTimeQueryParam(ctx echo.Context, name string, fieldName string, endOfDay bool,
+	invalidFields *todoerrors.InvalidFields) *time.Time {
+	value := ctx.QueryParam(name)
+	if value == "" {
+		return nil
+	}
+	if parsedValue, err := time.Parse(time.RFC3339, value); err == nil {
+		return &parsedValue
+	}
+	if parsedValue, err := time.Parse(dateLayout, value); err == nil {
+		if endOfDay {
+			parsedValue = parsedValue.AddDate(0, 0, 1).Add(-time.Microsecond)
+		}
+		return &parsedValue
+	}
+	invalidFields.AppendField(fieldName, msgs.ConversionError)
+
+	return nil
+}
+
+func setPaginationHeaders(ctx echo.Context, pagination domain.Pagination, total int) {
+	ctx.Response().Header().Set(headerTotalCount, strconv.Itoa(total))
+
+	limit := pagination.Limit()
+	offset := pagination.Offset()
+	lastOffset := 0
+	if total > 0 {
+		lastOffset = ((total - 1) / limit) * limit
+	}
+
+	links := []string{pageLink(ctx.Request().URL, limit, 0, "first")}
+	if offset > 0 {
+		links = append(links, pageLink(ctx.Request().URL, limit, max(offset-limit, 0), "prev"))
+	}
+	if offset+limit < total {
+		links = append(links, pageLink(ctx.Request().URL, limit, offset+limit, "next"))
+	}
+	links = append(links, pageLink(ctx.Request().URL, limit, lastOffset, "last"))
+
+	ctx.Response().Header().Set(headerLink, strings.Join(links, ", "))
+}
+
+func pageLink(requestUrl *url.URL, limit, offset int, rel string) string {
+	query := requestUrl.Query()
+	query.Del("page")
+	query.Del("size")
+	query.Set("limit", strconv.Itoa(limit))
+	query.Set("offset", strconv.Itoa(offset))
+	link := url.URL{Path: requestUrl.Path, RawQuery: query.Encode()}
+
+	return fmt.Sprintf("<%s>; rel=\"%s\"", link.String(), rel)
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"todo/src/app/api/endpoints/dto/request"
+	"todo/src/app/api/endpoints/dto/response"
+	"todo/src/app/api/endpoints/handlers/msgs"
+	"todo/src/core/domain"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+const (
+	batchModeAtomic     = "atomic"
+	batchModeBestEffort = "best_effort"
+)
+
+// parseTaskBatch validates every operation up front, so that a malformed batch is rejected as a whole instead of
+// being partially applied.
+func parseTaskBatch(requestData request.TaskBatch) (*domain.TaskBatch, *todoerrors.Validation) {
+	invalidFields := todoerrors.InvalidFields{}
+
+	atomic := true
+	switch requestData.Mode {
+	case "", batchModeAtomic:
+	case batchModeBestEffort:
+		atomic = false
+	default:
+		invalidFields.AppendField(msgs.BatchMode, msgs.InvalidBatchMode)
+	}
+
+	var operations []domain.TaskOperation
+	for index, operationData := range requestData.Operations {
+		operation, description := newTaskOperation(operationData)
+		if description != "" {
+			invalidFields.AppendField(fmt.Sprintf(msgs.Operation, index), description)
+			continue
+		}
+		operations = append(operations, *operation)
+	}
+	if invalidFields.HasInvalidFields() {
+		return nil, todoerrors.NewValidationError(msgs.InvalidBatchDetails, invalidFields)
+	}
+
+	return domain.NewValidatedTaskBatch(operations, atomic)
+}
+
+func newTaskOperation(operationData request.TaskOperation) (*domain.TaskOperation, string) {
+	switch operationData.Operation {
+	case domain.TaskOperationCreate, domain.TaskOperationUpdate:
+		if operationData.Operation == domain.TaskOperationUpdate && operationData.Id <= 0 {
+			return nil, msgs.InvalidOperationTaskId
+		}
+		if operationData.Task == nil {
+			return nil, msgs.MissingOperationTask
+		}
+		taskId := operationData.Id
+		if operationData.Operation == domain.TaskOperationCreate {
+			taskId = -1
+		}
+		task, validationErr := newTaskFromRequest(taskId, *operationData.Task)
+		if validationErr != nil {
+			return nil, validationErr.InvalidFields().Fields()[0].Description()
+		}
+		task.SetVersion(operationData.Version)
+		return domain.NewTaskOperation(operationData.Operation, taskId, operationData.Version, task), ""
+	case domain.TaskOperationDelete:
+		if operationData.Id <= 0 {
+			return nil, msgs.InvalidOperationTaskId
+		}
+		return domain.NewTaskOperation(operationData.Operation, operationData.Id, operationData.Version, nil), ""
+	default:
+		return nil, msgs.InvalidOperationType
+	}
+}
+
+func newTaskBatchResponse(batch domain.TaskBatch, batchResult domain.TaskBatchResult) (int, response.TaskBatch) {
+	mode := batchModeAtomic
+	if !batch.Atomic() {
+		mode = batchModeBestEffort
+	}
+
+	status := http.StatusOK
+	results := []response.TaskOperationResult{}
+	for index, result := range batchResult.Results() {
+		operation := batch.Operations()[index]
+		resultResponse := response.TaskOperationResult{
+			Index:     index,
+			Operation: operation.Kind(),
+			Status:    http.StatusNoContent,
+		}
+		if result.Err() != nil {
+			operationStatus, errorResponse := serviceErrorResponse(result.Err())
+			resultResponse.Status = operationStatus
+			resultResponse.Error = &errorResponse
+			status = http.StatusMultiStatus
+		} else {
+			resultResponse.Id = result.TaskId()
+			if operation.Kind() == domain.TaskOperationCreate {
+				resultResponse.Status = http.StatusCreated
+			}
+		}
+		results = append(results, resultResponse)
+	}
+
+	return status, response.TaskBatch{
+		Mode:      mode,
+		Committed: batchResult.Committed(),
+		Results:   results,
+		UndoToken: batchResult.UndoToken(),
+	}
+}
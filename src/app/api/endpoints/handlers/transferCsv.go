@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"todo/src/app/api/endpoints/dto/request"
+	"todo/src/app/api/endpoints/handlers/msgs"
+	"todo/src/core/domain"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+// The CSV files have the layout of the files in res/fixtures, so that they can also be loaded with COPY
+var (
+	collectionCsvColumns         = []string{"id", "name", "user_id"}
+	taskCsvColumns               = []string{"id", "description", "finished", "user_id", "collection_id"}
+	requiredCollectionCsvColumns = []string{"id", "name"}
+	requiredTaskCsvColumns       = []string{"id", "description", "finished"}
+)
+
+func collectionCsvRecord(collection domain.Collection, userId int) []string {
+	return []string{
+		strconv.Itoa(collection.Id()),
+		collection.Name(),
+		strconv.Itoa(userId),
+	}
+}
+
+func taskCsvRecord(task domain.Task, userId int) []string {
+	collectionId := ""
+	if task.Collection().Id() != 0 {
+		collectionId = strconv.Itoa(task.Collection().Id())
+	}
+
+	return []string{
+		strconv.Itoa(task.Id()),
+		task.Description(),
+		strconv.FormatBool(task.Finished()),
+		strconv.Itoa(userId),
+		collectionId,
+	}
+}
+
+// readCsvRows reads a CSV file with a header, returning each row as a map from column to value. Only the required
+// columns must be in the header, the user_id column being ignored as the rows are imported into the request account.
+func readCsvRows(file io.Reader, fileName string, requiredColumns []string,
+	invalidFields *todoerrors.InvalidFields) []map[string]string {
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		invalidFields.AppendField(fileName, msgs.InvalidCsvFile+err.Error())
+		return nil
+	}
+	if len(records) == 0 {
+		return nil
+	}
+
+	header := map[string]int{}
+	for index, column := range records[0] {
+		header[strings.TrimSpace(column)] = index
+	}
+	missingColumn := false
+	for _, column := range requiredColumns {
+		if _, ok := header[column]; !ok {
+			invalidFields.AppendField(fileName, msgs.MissingCsvColumn+column)
+			missingColumn = true
+		}
+	}
+	if missingColumn {
+		return nil
+	}
+
+	var rows []map[string]string
+	for _, record := range records[1:] {
+		row := map[string]string{}
+		for column, index := range header {
+			row[column] = record[index]
+		}
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+func parseCollectionCsv(rows []map[string]string, invalidFields *todoerrors.InvalidFields) []domain.Collection {
+	var collections []domain.Collection
+	for index, row := range rows {
+		id, err := strconv.Atoi(strings.TrimSpace(row["id"]))
+		if err != nil {
+			invalidFields.AppendField(fmt.Sprintf(msgs.Collection, index+1), msgs.InvalidCsvId)
+		}
+		collections = append(collections, *domain.NewCollection(id, row["name"]))
+	}
+
+	return collections
+}
+
+func parseTaskCsv(rows []map[string]string, invalidFields *todoerrors.InvalidFields) []domain.Task {
+	var tasks []domain.Task
+	for index, row := range rows {
+		rowName := fmt.Sprintf(msgs.Task, index+1)
+		id, err := strconv.Atoi(strings.TrimSpace(row["id"]))
+		if err != nil {
+			invalidFields.AppendField(rowName, msgs.InvalidCsvId)
+		}
+		finished, err := strconv.ParseBool(strings.TrimSpace(row["finished"]))
+		if err != nil {
+			invalidFields.AppendField(rowName, msgs.InvalidCsvFinished)
+		}
+		collectionId := 0
+		if value := strings.TrimSpace(row["collection_id"]); value != "" {
+			collectionId, err = strconv.Atoi(value)
+			if err != nil || collectionId <= 0 {
+				invalidFields.AppendField(rowName, msgs.InvalidCsvCollectionId)
+			}
+		}
+		task := domain.NewTask(id, row["description"], finished, domain.NewCollection(collectionId, ""))
+		tasks = append(tasks, *task)
+	}
+
+	return tasks
+}
+
+func parseTransferRequest(requestData request.Transfer,
+	invalidFields *todoerrors.InvalidFields) ([]domain.Collection, []domain.Task) {
+	var collections []domain.Collection
+	for _, collectionData := range requestData.Collections {
+		collection := domain.NewCollection(collectionData.Id, collectionData.Name)
+		if collectionData.CreatedAt != nil {
+			collection.SetCreatedAt(*collectionData.CreatedAt)
+		}
+		collections = append(collections, *collection)
+	}
+
+	var tasks []domain.Task
+	for index, taskData := range requestData.Tasks {
+		tags, validationErr := domain.NewValidatedTags(taskData.Tags)
+		if validationErr != nil {
+			invalidFields.AppendField(fmt.Sprintf(msgs.Task, index+1),
+				validationErr.InvalidFields().Fields()[0].Description())
+		}
+		collection := domain.NewCollection(taskData.CollectionId, "")
+		task := domain.NewTask(taskData.Id, taskData.Description, taskData.Finished, collection)
+		if taskData.CreatedAt != nil {
+			task.SetCreatedAt(*taskData.CreatedAt)
+		}
+		task.SetDueDate(taskData.DueDate)
+		task.SetTags(tags)
+		tasks = append(tasks, *task)
+	}
+
+	return collections, tasks
+}
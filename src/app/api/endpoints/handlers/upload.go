@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+	"todo/src/app/api/endpoints/dto/response"
+	"todo/src/app/api/endpoints/handlers/msgs"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+const formFieldFile = "file"
+
+var errUnsupportedUpload = errors.New(msgs.UnsupportedUploadType)
+
+// openUploadedFile opens the file sent as the request body with one of the media types, or in the file field of a
+// multipart/form-data request
+func openUploadedFile(ctx echo.Context, mediaTypes ...string) (io.ReadCloser, error) {
+	requestMediaType, _, _ := mime.ParseMediaType(ctx.Request().Header.Get(echo.HeaderContentType))
+	if slices.Contains(mediaTypes, requestMediaType) {
+		return ctx.Request().Body, nil
+	} else if requestMediaType == echo.MIMEMultipartForm {
+		fileHeader, err := ctx.FormFile(formFieldFile)
+		if err != nil {
+			return nil, err
+		}
+		return fileHeader.Open()
+	}
+
+	return nil, errUnsupportedUpload
+}
+
+// uploadedFileName is the name of the file sent in the file field, without its extension, and is empty when the file
+// was sent as the request body
+func uploadedFileName(ctx echo.Context) string {
+	fileHeader, err := ctx.FormFile(formFieldFile)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(fileHeader.Filename, filepath.Ext(fileHeader.Filename))
+}
+
+// writeUploadError answers the errors of openUploadedFile
+func writeUploadError(ctx echo.Context, err error, mediaTypes ...string) error {
+	if errors.Is(err, errUnsupportedUpload) {
+		return ctx.JSON(http.StatusUnsupportedMediaType, response.GenericErrorResponse{
+			Message: fmt.Sprintf(msgs.UnsupportedUploadType, strings.Join(mediaTypes, ", ")),
+		})
+	} else if errors.Is(err, http.ErrMissingFile) {
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.File, msgs.MissingUploadFile)
+		return writeValidationError(ctx, *todoerrors.NewValidationError(msgs.MissingUploadFile, invalidFields))
+	}
+
+	return writeBadRequestError(ctx, msgs.RequestFormatError)
+}
@@ -11,31 +11,44 @@ import (
 	"todo/src/core/projecterrors/todoerrors"
 )
 
+// AccountEmailKey is the context key of the email of the signed in account, set by the authorization middlewares
+const AccountEmailKey = "accountEmail"
+
+// AccountIdKey is the context key of the ID of the signed in account, set on the routes without a user ID in the path
+const AccountIdKey = "accountId"
+
 func handleServiceErrors(ctx echo.Context, err error) error {
+	status, errorResponse := serviceErrorResponse(err)
+	return ctx.JSON(status, errorResponse)
+}
+
+func WriteServiceError(ctx echo.Context, err error) error {
+	return handleServiceErrors(ctx, err)
+}
+
+func serviceErrorResponse(err error) (int, response.GenericErrorResponse) {
 	switch castedErr := err.(type) {
 	case *todoerrors.Conflict:
-		return writeConflictError(ctx, *castedErr)
+		return http.StatusConflict, response.GenericErrorResponse{Message: err.Error(), Conflicts: castedErr.Fields()}
 	case *todoerrors.Unauthorized:
-		return WriteUnauthorizedError(ctx, err.Error())
+		return http.StatusUnauthorized, response.GenericErrorResponse{Message: err.Error()}
 	case *todoerrors.MissingInfo:
-		return writeMissingInfoError(ctx, *castedErr)
+		return http.StatusBadRequest, response.GenericErrorResponse{Message: err.Error()}
 	case *todoerrors.Validation:
-		return writeValidationError(ctx, *castedErr)
+		return http.StatusUnprocessableEntity, validationErrorResponse(*castedErr)
 	case *todoerrors.NotFound:
-		return writeNotFoundError(ctx, err.Error())
+		return http.StatusNotFound, response.GenericErrorResponse{Message: err.Error()}
+	case *todoerrors.PreconditionFailed:
+		return http.StatusPreconditionFailed, response.GenericErrorResponse{Message: err.Error()}
+	case *todoerrors.FailedDependency:
+		return http.StatusFailedDependency, response.GenericErrorResponse{Message: err.Error()}
 	case *todoerrors.UnexpectedInternal:
-		return writeUnexpectedInternalError(ctx, *castedErr)
+		return http.StatusInternalServerError, response.GenericErrorResponse{Message: err.Error()}
 	default:
-		return writeUnexpectedError(ctx, msgs.UnexpectedInternalError)
+		return http.StatusInternalServerError, response.GenericErrorResponse{Message: msgs.UnexpectedInternalError}
 	}
 }
 
-func writeConflictError(ctx echo.Context, err todoerrors.Conflict) error {
-	conflictFields := err.Fields()
-	conflictErr := &response.GenericErrorResponse{Message: err.Error(), Conflicts: conflictFields}
-	return ctx.JSON(http.StatusConflict, *conflictErr)
-}
-
 func WriteUnauthorizedError(ctx echo.Context, message string) error {
 	return ctx.JSON(http.StatusUnauthorized, response.GenericErrorResponse{Message: message})
 }
@@ -48,12 +61,11 @@ func writeBadRequestError(ctx echo.Context, message string) error {
 	return ctx.JSON(http.StatusBadRequest, response.GenericErrorResponse{Message: message})
 }
 
-func writeMissingInfoError(ctx echo.Context, err todoerrors.MissingInfo) error {
-	missingInfoErr := &response.GenericErrorResponse{Message: err.Error()}
-	return ctx.JSON(http.StatusBadRequest, *missingInfoErr)
+func writeValidationError(ctx echo.Context, err todoerrors.Validation) error {
+	return ctx.JSON(http.StatusUnprocessableEntity, validationErrorResponse(err))
 }
 
-func writeValidationError(ctx echo.Context, err todoerrors.Validation) error {
+func validationErrorResponse(err todoerrors.Validation) response.GenericErrorResponse {
 	var invalidFields todoerrors.InvalidFields
 
 	for _, field := range err.InvalidFields().Fields() {
@@ -70,25 +82,10 @@ func writeValidationError(ctx echo.Context, err todoerrors.Validation) error {
 		invalidFieldsResponse = append(invalidFieldsResponse, *invalidField)
 	}
 
-	validationErr := response.GenericErrorResponse{
+	return response.GenericErrorResponse{
 		Message:       err.Error(),
 		InvalidFields: invalidFieldsResponse,
 	}
-
-	return ctx.JSON(http.StatusUnprocessableEntity, validationErr)
-}
-
-func writeNotFoundError(ctx echo.Context, message string) error {
-	return ctx.JSON(http.StatusNotFound, response.GenericErrorResponse{Message: message})
-}
-
-func writeUnexpectedError(ctx echo.Context, message string) error {
-	return ctx.JSON(http.StatusInternalServerError, response.GenericErrorResponse{Message: message})
-}
-
-func writeUnexpectedInternalError(ctx echo.Context, err todoerrors.UnexpectedInternal) error {
-	unexpectedInternalErr := &response.GenericErrorResponse{Message: err.Error()}
-	return ctx.JSON(http.StatusInternalServerError, *unexpectedInternalErr)
 }
 
 func writeAcceptResponse(ctx echo.Context, data interface{}) error {
@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"encoding/xml"
+	"errors"
+	"github.com/labstack/echo/v4"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+const (
+	namespaceDav            = "DAV:"
+	namespaceCalDav         = "urn:ietf:params:xml:ns:caldav"
+	namespaceCalendarServer = "http://calendarserver.org/ns/"
+	headerDepth             = "Depth"
+	headerDav               = "DAV"
+	mimeApplicationXml      = "application/xml; charset=utf-8"
+	statusOk                = "HTTP/1.1 200 OK"
+	statusNotFound          = "HTTP/1.1 404 Not Found"
+)
+
+var (
+	davResourceType             = xml.Name{Space: namespaceDav, Local: "resourcetype"}
+	davDisplayName              = xml.Name{Space: namespaceDav, Local: "displayname"}
+	davCurrentUserPrincipal     = xml.Name{Space: namespaceDav, Local: "current-user-principal"}
+	davPrincipalUrl             = xml.Name{Space: namespaceDav, Local: "principal-URL"}
+	davCurrentUserPrivilegeSet  = xml.Name{Space: namespaceDav, Local: "current-user-privilege-set"}
+	davSupportedReportSet       = xml.Name{Space: namespaceDav, Local: "supported-report-set"}
+	davGetETag                  = xml.Name{Space: namespaceDav, Local: "getetag"}
+	davGetContentType           = xml.Name{Space: namespaceDav, Local: "getcontenttype"}
+	calDavCalendarHomeSet       = xml.Name{Space: namespaceCalDav, Local: "calendar-home-set"}
+	calDavSupportedComponentSet = xml.Name{Space: namespaceCalDav, Local: "supported-calendar-component-set"}
+	calDavCalendarData          = xml.Name{Space: namespaceCalDav, Local: "calendar-data"}
+	calDavCalendarQuery         = xml.Name{Space: namespaceCalDav, Local: "calendar-query"}
+	calDavCalendarMultiget      = xml.Name{Space: namespaceCalDav, Local: "calendar-multiget"}
+	calendarServerGetCTag       = xml.Name{Space: namespaceCalendarServer, Local: "getctag"}
+)
+
+// davProperty is a WebDAV property with its value already written as XML
+type davProperty struct {
+	XMLName  xml.Name
+	InnerXml string `xml:",innerxml"`
+}
+
+func newDavProperty(name xml.Name, innerXml string) davProperty {
+	return davProperty{XMLName: name, InnerXml: innerXml}
+}
+
+func newDavTextProperty(name xml.Name, text string) davProperty {
+	var builder strings.Builder
+	_ = xml.EscapeText(&builder, []byte(text))
+	return newDavProperty(name, builder.String())
+}
+
+func newDavHrefProperty(name xml.Name, href string) davProperty {
+	var builder strings.Builder
+	_ = xml.EscapeText(&builder, []byte(href))
+	return newDavProperty(name, `<href xmlns="DAV:">`+builder.String()+`</href>`)
+}
+
+type davMultistatus struct {
+	XMLName   xml.Name      `xml:"DAV: multistatus"`
+	Responses []davResponse `xml:"DAV: response"`
+}
+
+type davResponse struct {
+	Href      string        `xml:"DAV: href"`
+	Status    string        `xml:"DAV: status,omitempty"`
+	Propstats []davPropstat `xml:"DAV: propstat"`
+}
+
+type davPropstat struct {
+	Properties []davProperty `xml:"DAV: prop>property"`
+	Status     string        `xml:"DAV: status"`
+}
+
+// davPropertyRequest holds the properties asked by a PROPFIND or REPORT, all of them when none is named
+type davPropertyRequest struct {
+	Names    []davPropertyName `xml:",any"`
+	AllProp  bool              `xml:"-"`
+	PropName bool              `xml:"-"`
+}
+
+type davPropertyName struct {
+	XMLName xml.Name
+}
+
+type davPropfind struct {
+	XMLName  xml.Name           `xml:"DAV: propfind"`
+	AllProp  *struct{}          `xml:"DAV: allprop"`
+	PropName *struct{}          `xml:"DAV: propname"`
+	Prop     davPropertyRequest `xml:"DAV: prop"`
+}
+
+// davReport is either a calendar-query, which is told apart by its filter, or a calendar-multiget with the hrefs
+type davReport struct {
+	XMLName xml.Name
+	Prop    davPropertyRequest `xml:"DAV: prop"`
+	Hrefs   []string           `xml:"DAV: href"`
+	Filter  *davCompFilter     `xml:"urn:ietf:params:xml:ns:caldav filter>comp-filter"`
+}
+
+type davCompFilter struct {
+	Name        string          `xml:"name,attr"`
+	CompFilters []davCompFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+}
+
+// readPropfind reads the properties asked by a PROPFIND, an empty body asking for all of them
+func readPropfind(body io.Reader) (*davPropertyRequest, error) {
+	var propfind davPropfind
+	if err := xml.NewDecoder(body).Decode(&propfind); errors.Is(err, io.EOF) {
+		return &davPropertyRequest{AllProp: true}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	request := propfind.Prop
+	request.AllProp = propfind.AllProp != nil || (propfind.PropName == nil && len(request.Names) == 0)
+	request.PropName = propfind.PropName != nil
+
+	return &request, nil
+}
+
+func readReport(body io.Reader) (*davReport, error) {
+	var report davReport
+	if err := xml.NewDecoder(body).Decode(&report); err != nil {
+		return nil, err
+	}
+	report.Prop.AllProp = len(report.Prop.Names) == 0
+
+	return &report, nil
+}
+
+// matchesTodos tells whether a calendar-query filter can match to-dos. The filters on the properties of the to-dos
+// are not evaluated, so every to-do of the calendar is returned to them.
+func (f *davCompFilter) matchesTodos() bool {
+	if f == nil || len(f.CompFilters) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(f.CompFilters, func(filter davCompFilter) bool {
+		return strings.EqualFold(filter.Name, "VTODO")
+	})
+}
+
+// newDavResponse answers the asked properties with those of the resource, and lists the unknown ones as not found
+func newDavResponse(href string, request davPropertyRequest, properties []davProperty) davResponse {
+	var found, missing []davProperty
+	switch {
+	case request.PropName:
+		for _, property := range properties {
+			found = append(found, newDavProperty(property.XMLName, ""))
+		}
+	case request.AllProp:
+		found = properties
+	default:
+		for _, name := range request.Names {
+			index := slices.IndexFunc(properties, func(property davProperty) bool {
+				return property.XMLName == name.XMLName
+			})
+			if index < 0 {
+				missing = append(missing, newDavProperty(name.XMLName, ""))
+				continue
+			}
+			found = append(found, properties[index])
+		}
+	}
+
+	response := davResponse{Href: href}
+	if len(found) > 0 {
+		response.Propstats = append(response.Propstats, davPropstat{Properties: found, Status: statusOk})
+	}
+	if len(missing) > 0 {
+		response.Propstats = append(response.Propstats, davPropstat{Properties: missing, Status: statusNotFound})
+	}
+
+	return response
+}
+
+func newDavNotFoundResponse(href string) davResponse {
+	return davResponse{Href: href, Status: statusNotFound}
+}
+
+func writeMultistatus(ctx echo.Context, responses []davResponse) error {
+	body, err := xml.Marshal(davMultistatus{Responses: responses})
+	if err != nil {
+		return err
+	}
+
+	return ctx.Blob(http.StatusMultiStatus, mimeApplicationXml, append([]byte(xml.Header), body...))
+}
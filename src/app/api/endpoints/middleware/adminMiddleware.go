@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+	"os"
+	"slices"
+	"strings"
+	"todo/src/app/api/endpoints/handlers"
+	"todo/src/app/api/endpoints/handlers/msgs"
+)
+
+type adminMiddleware struct {
+	authMiddleware
+	emails []string
+}
+
+// NewAdminMiddleware reads the administrators from the comma separated emails of ADMIN_EMAILS, nobody being an
+// administrator when it is empty
+func NewAdminMiddleware() *adminMiddleware {
+	var emails []string
+	for _, email := range strings.Split(os.Getenv("ADMIN_EMAILS"), ",") {
+		if formattedEmail := strings.ToLower(strings.TrimSpace(email)); formattedEmail != "" {
+			emails = append(emails, formattedEmail)
+		}
+	}
+	return &adminMiddleware{emails: emails}
+}
+
+// Authorize validates the token of the administration routes, which only the accounts with the email of an
+// administrator can use
+func (m adminMiddleware) Authorize(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		account, err := m.parseAccount(ctx)
+		if err != nil {
+			log.Error(err)
+			return handlers.WriteUnauthorizedError(ctx, err.Error())
+		}
+
+		email := strings.ToLower(account.Email())
+		if email == "" || !slices.Contains(m.emails, email) {
+			return handlers.WriteForbiddenError(ctx, msgs.ForbiddenError)
+		}
+		ctx.Set(handlers.AccountIdKey, account.Id())
+		ctx.Set(handlers.AccountEmailKey, account.Email())
+
+		return next(ctx)
+	}
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"todo/src/core/domain"
+)
+
+func newAdminContext(t *testing.T, email string) (echo.Context, *httptest.ResponseRecorder) {
+	requestData := httptest.NewRequest(http.MethodGet, "/admin/job", nil)
+	if email != "" {
+		token, err := domain.NewAccount(1, "Ann", email, "", "").GenerateToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		requestData.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	}
+	responseData := httptest.NewRecorder()
+
+	return echo.New().NewContext(requestData, responseData), responseData
+}
+
+func TestAdminMiddleware_Authorize(t *testing.T) {
+	next := func(ctx echo.Context) error {
+		return ctx.NoContent(http.StatusNoContent)
+	}
+
+	t.Run("should let the administrators in whatever the case of their email", func(t *testing.T) {
+		t.Setenv("SERVER_SECRET", "secret")
+		t.Setenv("ADMIN_EMAILS", " ops@example.com, Ann@Example.com ")
+		context, responseData := newAdminContext(t, "ann@example.COM")
+
+		_ = NewAdminMiddleware().Authorize(next)(context)
+
+		assert.Equal(t, http.StatusNoContent, responseData.Code)
+	})
+
+	t.Run("should return 403 for the accounts that are not administrators", func(t *testing.T) {
+		t.Setenv("SERVER_SECRET", "secret")
+		t.Setenv("ADMIN_EMAILS", "ops@example.com")
+		context, responseData := newAdminContext(t, "ann@example.com")
+
+		_ = NewAdminMiddleware().Authorize(next)(context)
+
+		assert.Equal(t, http.StatusForbidden, responseData.Code)
+	})
+
+	t.Run("should return 403 for everyone when there are no administrators", func(t *testing.T) {
+		t.Setenv("SERVER_SECRET", "secret")
+		t.Setenv("ADMIN_EMAILS", "")
+		context, responseData := newAdminContext(t, "ann@example.com")
+
+		_ = NewAdminMiddleware().Authorize(next)(context)
+
+		assert.Equal(t, http.StatusForbidden, responseData.Code)
+	})
+
+	t.Run("should return 401 without a token", func(t *testing.T) {
+		t.Setenv("ADMIN_EMAILS", "ann@example.com")
+		context, responseData := newAdminContext(t, "")
+
+		_ = NewAdminMiddleware().Authorize(next)(context)
+
+		assert.Equal(t, http.StatusUnauthorized, responseData.Code)
+	})
+}
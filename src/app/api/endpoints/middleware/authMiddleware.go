@@ -1,14 +1,13 @@
 package middleware
 
 import (
-	"fmt"
-	"github.com/golang-jwt/jwt"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/gommon/log"
-	"os"
+	"strconv"
 	"strings"
 	"todo/src/app/api/endpoints/handlers"
 	"todo/src/app/api/endpoints/handlers/msgs"
+	"todo/src/core/domain"
 	"todo/src/core/projecterrors/todoerrors"
 )
 
@@ -20,39 +19,75 @@ func NewAuthMiddleware() *authMiddleware {
 
 func (m authMiddleware) Authorize(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(ctx echo.Context) error {
-		authHeader := ctx.Request().Header.Get("Authorization")
-		userId := ctx.Param("userId")
-		token, err := m.getToken(authHeader)
+		account, err := m.parseAccount(ctx)
 		if err != nil {
 			log.Error(err)
 			return handlers.WriteUnauthorizedError(ctx, err.Error())
 		}
 
-		secretKey := os.Getenv("SERVER_SECRET")
-		newToken, err := jwt.Parse(
-			token,
-			func(token *jwt.Token) (interface{}, error) {
-				return []byte(secretKey), nil
-			},
-		)
+		return m.authorizeAccount(ctx, *account, next)
+	}
+}
+
+// AuthorizeChangeFeed also accepts a change feed token in the access_token parameter, as the EventSource and
+// WebSocket APIs of the browsers can't set the Authorization header
+func (m authMiddleware) AuthorizeChangeFeed(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		var account *domain.Account
+		var err error
+		if token := ctx.QueryParam("access_token"); token != "" {
+			account, err = domain.NewAccountFromChangeFeedToken(token)
+		} else {
+			account, err = m.parseAccount(ctx)
+		}
 		if err != nil {
 			log.Error(err)
-			return handlers.WriteUnauthorizedError(ctx, msgs.UnauthorizedError)
+			return handlers.WriteUnauthorizedError(ctx, err.Error())
 		}
 
-		if !newToken.Valid {
-			return handlers.WriteUnauthorizedError(ctx, msgs.UnauthorizedError)
+		return m.authorizeAccount(ctx, *account, next)
+	}
+}
+
+func (m authMiddleware) authorizeAccount(ctx echo.Context, account domain.Account, next echo.HandlerFunc) error {
+	if strconv.Itoa(account.Id()) != ctx.Param("userId") {
+		return handlers.WriteForbiddenError(ctx, msgs.ForbiddenError)
+	}
+	if account.Email() != "" {
+		ctx.Set(handlers.AccountEmailKey, account.Email())
+	}
+
+	return next(ctx)
+}
+
+// Authenticate validates the token of the routes that have no user ID in the path, setting the ID of the signed in
+// account in the context instead
+func (m authMiddleware) Authenticate(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		account, err := m.parseAccount(ctx)
+		if err != nil {
+			log.Error(err)
+			return handlers.WriteUnauthorizedError(ctx, err.Error())
 		}
 
-		claims := newToken.Claims.(jwt.MapClaims)
-		if fmt.Sprint(claims["id"]) != userId {
-			return handlers.WriteForbiddenError(ctx, msgs.ForbiddenError)
+		ctx.Set(handlers.AccountIdKey, account.Id())
+		if account.Email() != "" {
+			ctx.Set(handlers.AccountEmailKey, account.Email())
 		}
 
 		return next(ctx)
 	}
 }
 
+func (m authMiddleware) parseAccount(ctx echo.Context) (*domain.Account, error) {
+	token, err := m.getToken(ctx.Request().Header.Get("Authorization"))
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.NewAccountFromToken(token)
+}
+
 func (m authMiddleware) getToken(authHeader string) (string, error) {
 	splitAuthHeader := strings.Split(authHeader, "Bearer")
 	if len(splitAuthHeader) < 2 {
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"todo/src/core/domain"
+)
+
+func newChangeFeedContext(target, authorization string) (echo.Context, *httptest.ResponseRecorder) {
+	requestData := httptest.NewRequest(http.MethodGet, target, nil)
+	if authorization != "" {
+		requestData.Header.Set(echo.HeaderAuthorization, authorization)
+	}
+	responseData := httptest.NewRecorder()
+	context := echo.New().NewContext(requestData, responseData)
+	context.SetParamNames("userId")
+	context.SetParamValues("1")
+
+	return context, responseData
+}
+
+func TestAuthMiddleware_AuthorizeChangeFeed(t *testing.T) {
+	next := func(ctx echo.Context) error {
+		return ctx.NoContent(http.StatusNoContent)
+	}
+
+	t.Run("should let the change feed token of the user in", func(t *testing.T) {
+		t.Setenv("SERVER_SECRET", "secret")
+		token, _, err := domain.NewAccount(1, "Ann", "ann@example.com", "", "").GenerateChangeFeedToken()
+		assert.NoError(t, err)
+		context, responseData := newChangeFeedContext("/user/1/events?access_token="+token, "")
+
+		_ = NewAuthMiddleware().AuthorizeChangeFeed(next)(context)
+
+		assert.Equal(t, http.StatusNoContent, responseData.Code)
+	})
+
+	t.Run("should return 401 for an account token in the query", func(t *testing.T) {
+		t.Setenv("SERVER_SECRET", "secret")
+		token, err := domain.NewAccount(1, "Ann", "ann@example.com", "", "").GenerateToken()
+		assert.NoError(t, err)
+		context, responseData := newChangeFeedContext("/user/1/events?access_token="+token, "")
+
+		_ = NewAuthMiddleware().AuthorizeChangeFeed(next)(context)
+
+		assert.Equal(t, http.StatusUnauthorized, responseData.Code)
+	})
+
+	t.Run("should return 401 for a change feed token in the header of the other routes", func(t *testing.T) {
+		t.Setenv("SERVER_SECRET", "secret")
+		token, _, err := domain.NewAccount(1, "Ann", "ann@example.com", "", "").GenerateChangeFeedToken()
+		assert.NoError(t, err)
+		context, responseData := newChangeFeedContext("/user/1/task", "Bearer "+token)
+
+		_ = NewAuthMiddleware().Authorize(next)(context)
+
+		assert.Equal(t, http.StatusUnauthorized, responseData.Code)
+	})
+}
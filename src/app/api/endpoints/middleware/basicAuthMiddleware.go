@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+	"strconv"
+	"strings"
+	"todo/src/app/api/endpoints/handlers"
+	"todo/src/app/api/endpoints/handlers/msgs"
+	"todo/src/core/domain"
+	eventbus "todo/src/core/interfaces/events"
+	interfaces "todo/src/core/interfaces/services"
+	"todo/src/core/services"
+	"todo/src/infra/postgres"
+)
+
+const basicAuthChallenge = `Basic realm="todo-rest-api", charset="UTF-8"`
+
+type basicAuthMiddleware struct {
+	service interfaces.IAuth
+}
+
+func NewBasicAuthMiddleware(emitter eventbus.IEmitter) *basicAuthMiddleware {
+	connectionManager := postgres.NewPostgresConnectionManager()
+	repository := postgres.NewAuthPostgresRepository(connectionManager)
+	service := services.NewAuthService(repository, emitter)
+	return &basicAuthMiddleware{service}
+}
+
+// Authorize signs in with the email and password of the HTTP Basic credentials, which is all most CalDAV clients
+// support. Requests with a bearer token are authorized as in the rest of the API.
+func (m basicAuthMiddleware) Authorize(next echo.HandlerFunc) echo.HandlerFunc {
+	bearerAuthorization := NewAuthMiddleware().Authorize(next)
+	return func(ctx echo.Context) error {
+		if strings.HasPrefix(ctx.Request().Header.Get(echo.HeaderAuthorization), "Bearer") {
+			return bearerAuthorization(ctx)
+		}
+
+		email, password, ok := ctx.Request().BasicAuth()
+		if !ok {
+			ctx.Response().Header().Set(echo.HeaderWWWAuthenticate, basicAuthChallenge)
+			return handlers.WriteUnauthorizedError(ctx, msgs.UnauthorizedError)
+		}
+
+		account, err := m.service.SignIn(*domain.NewAccount(0, "", email, password, ""))
+		if err != nil {
+			log.Error(err)
+			ctx.Response().Header().Set(echo.HeaderWWWAuthenticate, basicAuthChallenge)
+			return handlers.WriteUnauthorizedError(ctx, msgs.UnauthorizedError)
+		}
+
+		if strconv.Itoa(account.Id()) != ctx.Param("userId") {
+			return handlers.WriteForbiddenError(ctx, msgs.ForbiddenError)
+		}
+		ctx.Set(handlers.AccountEmailKey, account.Email())
+
+		return next(ctx)
+	}
+}
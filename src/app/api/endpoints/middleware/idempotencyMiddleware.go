@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+	"io"
+	"net/http"
+	"strconv"
+	"todo/src/app/api/endpoints/handlers"
+	"todo/src/core/domain"
+	interfaces "todo/src/core/interfaces/services"
+	"todo/src/core/services"
+	"todo/src/infra/postgres"
+)
+
+const (
+	headerIdempotencyKey     = "Idempotency-Key"
+	headerIdempotentReplayed = "Idempotent-Replayed"
+)
+
+// replayedHeaders are the headers set by the handlers that tell the client about the result of the request
+var replayedHeaders = []string{echo.HeaderLocation, "Undo-Token", "ETag"}
+
+type idempotencyMiddleware struct {
+	service interfaces.IIdempotency
+}
+
+func NewIdempotencyMiddleware() *idempotencyMiddleware {
+	connectionManager := postgres.NewPostgresConnectionManager()
+	repository := postgres.NewIdempotencyPostgresRepository(connectionManager)
+	service := services.NewIdempotencyService(repository)
+	return &idempotencyMiddleware{service}
+}
+
+// Handle stores the response of requests sent with an Idempotency-Key header and replays it, byte for byte and
+// along with the headers about its result, when the request is retried with the same key. It must run after the
+// authorization, which validates the user ID.
+func (m idempotencyMiddleware) Handle(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		key := ctx.Request().Header.Get(headerIdempotencyKey)
+		userId, err := strconv.Atoi(ctx.Param("userId"))
+		if key == "" || err != nil {
+			return next(ctx)
+		}
+
+		body, err := io.ReadAll(ctx.Request().Body)
+		if err != nil {
+			log.Error(err)
+			return next(ctx)
+		}
+		ctx.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+		request, validationErr := domain.NewValidatedIdempotentRequest(key, m.fingerprint(ctx, body))
+		if validationErr != nil {
+			log.Error(validationErr)
+			return handlers.WriteServiceError(ctx, validationErr)
+		}
+		storedRequest, err := m.service.Reserve(*request, userId)
+		if err != nil {
+			log.Error(err)
+			return handlers.WriteServiceError(ctx, err)
+		}
+		if storedRequest != nil {
+			return m.replay(ctx, *storedRequest)
+		}
+
+		recorder := &responseRecorder{ResponseWriter: ctx.Response().Writer}
+		ctx.Response().Writer = recorder
+		err = next(ctx)
+		if err != nil || ctx.Response().Status >= http.StatusInternalServerError {
+			if releaseErr := m.service.Release(*request, userId); releaseErr != nil {
+				log.Error(releaseErr)
+			}
+			return err
+		}
+
+		headers := map[string]string{}
+		for _, header := range replayedHeaders {
+			if value := ctx.Response().Header().Get(header); value != "" {
+				headers[header] = value
+			}
+		}
+		request.SetResponse(ctx.Response().Status, ctx.Response().Header().Get(echo.HeaderContentType), headers,
+			recorder.body.Bytes())
+		if completeErr := m.service.Complete(*request, userId); completeErr != nil {
+			log.Error(completeErr)
+		}
+
+		return nil
+	}
+}
+
+func (m idempotencyMiddleware) fingerprint(ctx echo.Context, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(ctx.Request().Method + " " + ctx.Request().URL.RequestURI() + "\n"))
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func (m idempotencyMiddleware) replay(ctx echo.Context, request domain.IdempotentRequest) error {
+	ctx.Response().Header().Set(headerIdempotentReplayed, "true")
+	for header, value := range request.Headers() {
+		ctx.Response().Header().Set(header, value)
+	}
+	if len(request.Body()) == 0 {
+		return ctx.NoContent(request.Status())
+	}
+
+	return ctx.Blob(request.Status(), request.ContentType(), request.Body())
+}
+
+// responseRecorder keeps a copy of the response body written to the client
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
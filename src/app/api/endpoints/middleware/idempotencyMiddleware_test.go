@@ -0,0 +1,163 @@
+package middleware
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"todo/src/core/domain"
+	"todo/src/core/projecterrors/todoerrors"
+	"todo/src/core/services/msgs"
+)
+
+type MockIdempotencyService struct {
+	mock.Mock
+}
+
+func (m *MockIdempotencyService) Reserve(request domain.IdempotentRequest,
+	userId int) (*domain.IdempotentRequest, error) {
+	args := m.Called(request, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.IdempotentRequest), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockIdempotencyService) Complete(request domain.IdempotentRequest, userId int) error {
+	args := m.Called(request, userId)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyService) Release(request domain.IdempotentRequest, userId int) error {
+	args := m.Called(request, userId)
+	return args.Error(0)
+}
+
+func newIdempotentContext(key, body string) (echo.Context, *httptest.ResponseRecorder) {
+	requestData := httptest.NewRequest(http.MethodPost, "/user/1/task", strings.NewReader(body))
+	requestData.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		requestData.Header.Set(headerIdempotencyKey, key)
+	}
+	response := httptest.NewRecorder()
+	ctx := echo.New().NewContext(requestData, response)
+	ctx.SetParamNames("userId")
+	ctx.SetParamValues("1")
+
+	return ctx, response
+}
+
+func TestIdempotency_Handle(t *testing.T) {
+	created := func(ctx echo.Context) error {
+		return ctx.JSON(http.StatusCreated, map[string]int{"id": 1})
+	}
+
+	t.Run("should call the handler without reserving when no key is sent", func(t *testing.T) {
+		mockService := new(MockIdempotencyService)
+		ctx, response := newIdempotentContext("", `{"name":"Task"}`)
+
+		err := idempotencyMiddleware{mockService}.Handle(created)(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, response.Code)
+		mockService.AssertNotCalled(t, "Reserve", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should store the response of the first request", func(t *testing.T) {
+		mockService := new(MockIdempotencyService)
+		mockService.On("Reserve", mock.Anything, 1).Return(nil, nil)
+		mockService.On("Complete", mock.MatchedBy(func(request domain.IdempotentRequest) bool {
+			return request.Completed() && request.Status() == http.StatusCreated &&
+				string(request.Body()) == "{\"id\":1}\n"
+		}), 1).Return(nil)
+		ctx, recorder := newIdempotentContext("key-1", `{"name":"Task"}`)
+
+		err := idempotencyMiddleware{mockService}.Handle(created)(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should replay the stored response when the key is reused", func(t *testing.T) {
+		storedRequest := domain.NewIdempotentRequest("key-1", "fingerprint")
+		storedRequest.SetResponse(http.StatusCreated, echo.MIMEApplicationJSON, nil, []byte(`{"id":7}`))
+		mockService := new(MockIdempotencyService)
+		mockService.On("Reserve", mock.Anything, 1).Return(storedRequest, nil)
+		ctx, recorder := newIdempotentContext("key-1", `{"name":"Task"}`)
+		called := false
+
+		err := idempotencyMiddleware{mockService}.Handle(func(ctx echo.Context) error {
+			called = true
+			return created(ctx)
+		})(ctx)
+
+		assert.NoError(t, err)
+		assert.False(t, called)
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+		assert.Equal(t, `{"id":7}`, recorder.Body.String())
+		assert.Equal(t, "true", recorder.Header().Get(headerIdempotentReplayed))
+	})
+
+	t.Run("should store the headers about the result along with the response", func(t *testing.T) {
+		mockService := new(MockIdempotencyService)
+		mockService.On("Reserve", mock.Anything, 1).Return(nil, nil)
+		mockService.On("Complete", mock.Anything, 1).Return(nil)
+		ctx, _ := newIdempotentContext("key-1", `{"name":"Task"}`)
+
+		err := idempotencyMiddleware{mockService}.Handle(func(ctx echo.Context) error {
+			ctx.Response().Header().Set(echo.HeaderLocation, "/api/user/1/task/7")
+			ctx.Response().Header().Set("Undo-Token", "0123456789abcdef")
+			ctx.Response().Header().Set("X-Request-Id", "request-1")
+			return created(ctx)
+		})(ctx)
+
+		assert.NoError(t, err)
+		completedRequest := mockService.Calls[1].Arguments.Get(0).(domain.IdempotentRequest)
+		assert.Equal(t, map[string]string{echo.HeaderLocation: "/api/user/1/task/7", "Undo-Token": "0123456789abcdef"},
+			completedRequest.Headers())
+	})
+
+	t.Run("should replay the stored headers with the response", func(t *testing.T) {
+		storedRequest := domain.NewIdempotentRequest("key-1", "fingerprint")
+		storedRequest.SetResponse(http.StatusNoContent, "", map[string]string{"Undo-Token": "0123456789abcdef"}, nil)
+		mockService := new(MockIdempotencyService)
+		mockService.On("Reserve", mock.Anything, 1).Return(storedRequest, nil)
+		ctx, recorder := newIdempotentContext("key-1", `{"name":"Task"}`)
+
+		err := idempotencyMiddleware{mockService}.Handle(created)(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, recorder.Code)
+		assert.Equal(t, "0123456789abcdef", recorder.Header().Get("Undo-Token"))
+		assert.Equal(t, "true", recorder.Header().Get(headerIdempotentReplayed))
+	})
+
+	t.Run("should return 422 when the key is reused with a different request", func(t *testing.T) {
+		mockService := new(MockIdempotencyService)
+		mockService.On("Reserve", mock.Anything, 1).Return(nil, todoerrors.NewValidationError(msgs.ReusedIdempotencyKey, todoerrors.InvalidFields{}))
+		ctx, recorder := newIdempotentContext("key-1", `{"name":"Other task"}`)
+
+		err := idempotencyMiddleware{mockService}.Handle(created)(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	})
+
+	t.Run("should release the key when the request fails", func(t *testing.T) {
+		mockService := new(MockIdempotencyService)
+		mockService.On("Reserve", mock.Anything, 1).Return(nil, nil)
+		mockService.On("Release", mock.Anything, 1).Return(nil)
+		ctx, _ := newIdempotentContext("key-1", `{"name":"Task"}`)
+
+		_ = idempotencyMiddleware{mockService}.Handle(func(ctx echo.Context) error {
+			return ctx.NoContent(http.StatusInternalServerError)
+		})(ctx)
+
+		mockService.AssertCalled(t, "Release", mock.Anything, 1)
+		mockService.AssertNotCalled(t, "Complete", mock.Anything, mock.Anything)
+	})
+}
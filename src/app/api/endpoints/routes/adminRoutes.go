@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"todo/src/app/api/endpoints/handlers"
+	"todo/src/app/api/endpoints/middleware"
+)
+
+func loadAdminRoutes(group *echo.Group) {
+	adminGroup := group.Group("/admin")
+	adminMiddleware := middleware.NewAdminMiddleware()
+	adminGroup.Use(adminMiddleware.Authorize)
+
+	jobHandler := handlers.NewJobHandler()
+
+	adminGroup.GET("/job", jobHandler.FindAll)
+	adminGroup.GET("/job/schedule", jobHandler.FindSchedules)
+	adminGroup.GET("/job/:jobId", jobHandler.FindById)
+	adminGroup.POST("/job/:jobId/retry", jobHandler.Retry)
+}
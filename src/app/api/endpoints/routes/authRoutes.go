@@ -3,12 +3,13 @@ package routes
 import (
 	"github.com/labstack/echo/v4"
 	"todo/src/app/api/endpoints/handlers"
+	eventbus "todo/src/core/interfaces/events"
 )
 
-func loadAuthRoutes(group *echo.Group) {
+func loadAuthRoutes(group *echo.Group, emitter eventbus.IEmitter) {
 	authGroup := group.Group("/auth")
 
-	authHandler := handlers.NewAuthHandler()
+	authHandler := handlers.NewAuthHandler(emitter)
 
 	authGroup.POST("/signup", authHandler.SignUp)
 	authGroup.POST("/signin", authHandler.SignIn)
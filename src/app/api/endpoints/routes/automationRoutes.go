@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"todo/src/app/api/endpoints/handlers"
+	"todo/src/app/api/endpoints/middleware"
+)
+
+func loadAutomationRoutes(group *echo.Group) {
+	automationGroup := group.Group("/automation")
+	authMiddleware := middleware.NewAuthMiddleware()
+	automationGroup.Use(authMiddleware.Authorize)
+
+	idempotencyMiddleware := middleware.NewIdempotencyMiddleware()
+	automationHandler := handlers.NewAutomationHandler()
+
+	automationGroup.POST("", automationHandler.Create, idempotencyMiddleware.Handle)
+	automationGroup.GET("", automationHandler.FindAll)
+	automationGroup.GET("/:automationId", automationHandler.FindById)
+	automationGroup.PUT("/:automationId", automationHandler.Update)
+	automationGroup.DELETE("/:automationId", automationHandler.Delete)
+}
@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"todo/src/app/api/endpoints/handlers"
+	"todo/src/app/api/endpoints/middleware"
+	eventbus "todo/src/core/interfaces/events"
+)
+
+// loadCalDavRoutes registers every resource with and without its trailing slash, which CalDAV clients use
+// interchangeably for the collections
+func loadCalDavRoutes(group *echo.Group, emitter eventbus.IEmitter) {
+	basicAuthMiddleware := middleware.NewBasicAuthMiddleware(emitter)
+	calDavGroup := group.Group("/caldav/user/:userId")
+	calDavGroup.Use(basicAuthMiddleware.Authorize)
+
+	calDavHandler := handlers.NewCalDavHandler(emitter)
+
+	for _, path := range []string{"", "/"} {
+		calDavGroup.OPTIONS(path, calDavHandler.Options)
+		calDavGroup.Add(echo.PROPFIND, path, calDavHandler.PropfindHome)
+		calDavGroup.OPTIONS("/:collectionId"+path, calDavHandler.Options)
+		calDavGroup.Add(echo.PROPFIND, "/:collectionId"+path, calDavHandler.PropfindCalendar)
+		calDavGroup.Add(echo.REPORT, "/:collectionId"+path, calDavHandler.Report)
+	}
+	calDavGroup.Add(echo.PROPFIND, "/:collectionId/:object", calDavHandler.PropfindObject)
+	calDavGroup.GET("/:collectionId/:object", calDavHandler.Get)
+	calDavGroup.PUT("/:collectionId/:object", calDavHandler.Put)
+	calDavGroup.DELETE("/:collectionId/:object", calDavHandler.Delete)
+}
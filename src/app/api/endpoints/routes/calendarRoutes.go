@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"todo/src/app/api/endpoints/handlers"
+	"todo/src/app/api/endpoints/middleware"
+)
+
+func loadCalendarRoutes(group *echo.Group) {
+	authMiddleware := middleware.NewAuthMiddleware()
+	calendarGroup := group.Group("/calendar")
+	calendarGroup.Use(authMiddleware.Authorize)
+
+	calendarHandler := handlers.NewCalendarHandler()
+
+	calendarGroup.POST("/feed", calendarHandler.CreateFeed)
+	calendarGroup.GET("/feed", calendarHandler.FindFeed)
+	calendarGroup.DELETE("/feed", calendarHandler.DeleteFeed)
+	calendarGroup.POST("/import", calendarHandler.Import)
+}
+
+// loadCalendarFeedRoutes registers the feeds, authenticated by the secret token of their URL
+func loadCalendarFeedRoutes(group *echo.Group) {
+	feedGroup := group.Group("/calendar/:token")
+
+	calendarHandler := handlers.NewCalendarHandler()
+
+	feedGroup.GET("/tasks.ics", calendarHandler.Feed)
+	feedGroup.GET("/collection/:collectionId/tasks.ics", calendarHandler.CollectionFeed)
+}
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"todo/src/app/api/endpoints/handlers"
+	"todo/src/app/api/endpoints/middleware"
+	interfaces "todo/src/core/interfaces/changefeed"
+)
+
+func loadChangeFeedRoutes(group *echo.Group, feed interfaces.IChangeFeed) {
+	eventsGroup := group.Group("/events")
+	authMiddleware := middleware.NewAuthMiddleware()
+
+	changeFeedHandler := handlers.NewChangeFeedHandler(feed)
+
+	eventsGroup.GET("", changeFeedHandler.Stream, authMiddleware.AuthorizeChangeFeed)
+	eventsGroup.GET("/ws", changeFeedHandler.WebSocket, authMiddleware.AuthorizeChangeFeed)
+	eventsGroup.POST("/token", changeFeedHandler.CreateToken, authMiddleware.Authorize)
+}
@@ -4,19 +4,27 @@ import (
 	"github.com/labstack/echo/v4"
 	"todo/src/app/api/endpoints/handlers"
 	"todo/src/app/api/endpoints/middleware"
+	eventbus "todo/src/core/interfaces/events"
 )
 
-func loadCollectionRoutes(group *echo.Group) {
+func loadCollectionRoutes(group *echo.Group, emitter eventbus.IEmitter) {
 	collectionGroup := group.Group("/collection")
 	authMiddleware := middleware.NewAuthMiddleware()
 	collectionGroup.Use(authMiddleware.Authorize)
 
-	collectionHandler := handlers.NewCollectionHandler()
-	taskHandler := handlers.NewTaskHandler()
+	idempotencyMiddleware := middleware.NewIdempotencyMiddleware()
+	collectionHandler := handlers.NewCollectionHandler(emitter)
+	taskHandler := handlers.NewTaskHandler(emitter)
+	checklistHandler := handlers.NewChecklistHandler(emitter)
 
-	collectionGroup.POST("", collectionHandler.Create)
+	collectionGroup.POST("", collectionHandler.Create, idempotencyMiddleware.Handle)
 	collectionGroup.PUT("/:collectionId", collectionHandler.Update)
+	collectionGroup.PATCH("/:collectionId", collectionHandler.Patch)
 	collectionGroup.DELETE("/:collectionId", collectionHandler.Delete)
 	collectionGroup.GET("", collectionHandler.FindAll)
+	collectionGroup.GET("/:collectionId", collectionHandler.FindById)
 	collectionGroup.GET("/:collectionId/task", taskHandler.FindByCollectionId)
+	collectionGroup.GET("/:collectionId/export.md", checklistHandler.Export)
+	collectionGroup.POST("/import.md", checklistHandler.Import)
+	collectionGroup.POST("/:collectionId/import.md", checklistHandler.ImportIntoCollection)
 }
@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"todo/src/app/api/endpoints/handlers"
+	"todo/src/app/api/endpoints/middleware"
+	eventbus "todo/src/core/interfaces/events"
+)
+
+func loadGraphQLRoutes(group *echo.Group, emitter eventbus.IEmitter) {
+	authMiddleware := middleware.NewAuthMiddleware()
+
+	graphqlHandler := handlers.NewGraphQLHandler(emitter)
+
+	group.POST("/graphql", graphqlHandler.Query, authMiddleware.Authenticate)
+}
@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"todo/src/app/api/endpoints/handlers"
+	"todo/src/app/api/endpoints/middleware"
+)
+
+func loadNotificationRoutes(group *echo.Group) {
+	authMiddleware := middleware.NewAuthMiddleware()
+	idempotencyMiddleware := middleware.NewIdempotencyMiddleware()
+	notificationHandler := handlers.NewNotificationHandler()
+
+	reminderGroup := group.Group("/task/:taskId/reminder")
+	reminderGroup.Use(authMiddleware.Authorize)
+	reminderGroup.POST("", notificationHandler.CreateReminder, idempotencyMiddleware.Handle)
+	reminderGroup.GET("", notificationHandler.FindReminders)
+	reminderGroup.DELETE("/:reminderId", notificationHandler.DeleteReminder)
+
+	notificationGroup := group.Group("/notification")
+	notificationGroup.Use(authMiddleware.Authorize)
+	notificationGroup.GET("/preferences", notificationHandler.FindPreferences)
+	notificationGroup.PUT("/preferences", notificationHandler.UpdatePreferences)
+}
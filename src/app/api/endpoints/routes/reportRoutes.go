@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"todo/src/app/api/endpoints/handlers"
+	"todo/src/app/api/endpoints/middleware"
+	eventbus "todo/src/core/interfaces/events"
+)
+
+func loadReportRoutes(group *echo.Group, emitter eventbus.IEmitter) {
+	reportGroup := group.Group("/report")
+	authMiddleware := middleware.NewAuthMiddleware()
+	reportGroup.Use(authMiddleware.Authorize)
+
+	reportHandler := handlers.NewReportHandler(emitter)
+
+	reportGroup.GET("/tasks.pdf", reportHandler.Tasks)
+	reportGroup.GET("/collection/:collectionId/tasks.pdf", reportHandler.Collection)
+}
@@ -3,21 +3,35 @@ package routes
 import (
 	"github.com/labstack/echo-contrib/echoprometheus"
 	"github.com/labstack/echo/v4"
+	interfaces "todo/src/core/interfaces/changefeed"
+	eventbus "todo/src/core/interfaces/events"
 )
 
-func LoadRoutes() *echo.Echo {
+func LoadRoutes(changeFeed interfaces.IChangeFeed, emitter eventbus.IEmitter) *echo.Echo {
 	router := echo.New()
 
 	router.Use(echoprometheus.NewMiddleware("todo-rest-api"))
 	router.GET("/metrics", echoprometheus.NewHandler())
 
 	apiGroup := router.Group("/api")
-	loadAuthRoutes(apiGroup)
+	loadAuthRoutes(apiGroup, emitter)
 	loadDocumentationRoutes(apiGroup)
+	loadCalendarFeedRoutes(apiGroup)
+	loadCalDavRoutes(apiGroup, emitter)
+	loadGraphQLRoutes(apiGroup, emitter)
+	loadAdminRoutes(apiGroup)
 
 	userGroup := apiGroup.Group("/user/:userId")
-	loadTaskRoutes(userGroup)
-	loadCollectionRoutes(userGroup)
+	loadTaskRoutes(userGroup, emitter)
+	loadCollectionRoutes(userGroup, emitter)
+	loadUndoRoutes(userGroup)
+	loadTransferRoutes(userGroup)
+	loadCalendarRoutes(userGroup)
+	loadReportRoutes(userGroup, emitter)
+	loadChangeFeedRoutes(userGroup, changeFeed)
+	loadWebhookRoutes(userGroup)
+	loadAutomationRoutes(userGroup)
+	loadNotificationRoutes(userGroup)
 
 	return router
 }
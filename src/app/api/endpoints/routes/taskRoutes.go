@@ -4,17 +4,22 @@ import (
 	"github.com/labstack/echo/v4"
 	"todo/src/app/api/endpoints/handlers"
 	"todo/src/app/api/endpoints/middleware"
+	eventbus "todo/src/core/interfaces/events"
 )
 
-func loadTaskRoutes(group *echo.Group) {
+func loadTaskRoutes(group *echo.Group, emitter eventbus.IEmitter) {
 	taskGroup := group.Group("/task")
 	authMiddleware := middleware.NewAuthMiddleware()
 	taskGroup.Use(authMiddleware.Authorize)
 
-	taskHandler := handlers.NewTaskHandler()
+	idempotencyMiddleware := middleware.NewIdempotencyMiddleware()
+	taskHandler := handlers.NewTaskHandler(emitter)
 
-	taskGroup.POST("", taskHandler.Create)
+	taskGroup.POST("", taskHandler.Create, idempotencyMiddleware.Handle)
+	taskGroup.POST("/batch", taskHandler.Batch, idempotencyMiddleware.Handle)
 	taskGroup.PUT("/:taskId", taskHandler.Update)
+	taskGroup.PATCH("/:taskId", taskHandler.Patch)
 	taskGroup.DELETE("/:taskId", taskHandler.Delete)
 	taskGroup.GET("", taskHandler.FindAll)
+	taskGroup.GET("/:taskId", taskHandler.FindById)
 }
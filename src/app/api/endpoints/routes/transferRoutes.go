@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"todo/src/app/api/endpoints/handlers"
+	"todo/src/app/api/endpoints/middleware"
+)
+
+func loadTransferRoutes(group *echo.Group) {
+	authMiddleware := middleware.NewAuthMiddleware()
+	exportGroup := group.Group("/export")
+	exportGroup.Use(authMiddleware.Authorize)
+	importGroup := group.Group("/import")
+	importGroup.Use(authMiddleware.Authorize)
+
+	idempotencyMiddleware := middleware.NewIdempotencyMiddleware()
+	transferHandler := handlers.NewTransferHandler()
+
+	exportGroup.GET("", transferHandler.Export)
+	exportGroup.GET("/collection.csv", transferHandler.ExportCollectionsCsv)
+	exportGroup.GET("/task.csv", transferHandler.ExportTasksCsv)
+	exportGroup.GET("/todo.txt", transferHandler.ExportTodoTxt)
+	importGroup.POST("", transferHandler.Import, idempotencyMiddleware.Handle)
+	importGroup.POST("/todo.txt", transferHandler.ImportTodoTxt, idempotencyMiddleware.Handle)
+	importGroup.GET("/job/:jobId", transferHandler.FindImport)
+	importGroup.POST("/:source", transferHandler.ImportFrom, idempotencyMiddleware.Handle)
+}
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"todo/src/app/api/endpoints/handlers"
+	"todo/src/app/api/endpoints/middleware"
+)
+
+func loadUndoRoutes(group *echo.Group) {
+	undoGroup := group.Group("/undo")
+	authMiddleware := middleware.NewAuthMiddleware()
+	undoGroup.Use(authMiddleware.Authorize)
+
+	undoHandler := handlers.NewUndoHandler()
+
+	undoGroup.POST("/:token", undoHandler.Undo)
+}
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+	"todo/src/app/api/endpoints/handlers"
+	"todo/src/app/api/endpoints/middleware"
+)
+
+func loadWebhookRoutes(group *echo.Group) {
+	webhookGroup := group.Group("/webhook")
+	authMiddleware := middleware.NewAuthMiddleware()
+	webhookGroup.Use(authMiddleware.Authorize)
+
+	idempotencyMiddleware := middleware.NewIdempotencyMiddleware()
+	webhookHandler := handlers.NewWebhookHandler()
+
+	webhookGroup.POST("", webhookHandler.Create, idempotencyMiddleware.Handle)
+	webhookGroup.GET("", webhookHandler.FindAll)
+	webhookGroup.GET("/:webhookId", webhookHandler.FindById)
+	webhookGroup.PUT("/:webhookId", webhookHandler.Update)
+	webhookGroup.DELETE("/:webhookId", webhookHandler.Delete)
+	webhookGroup.GET("/:webhookId/delivery", webhookHandler.FindDeliveries)
+}
@@ -0,0 +1,55 @@
+package graph
+
+import (
+	"todo/src/app/api/graph/msgs"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+// resolverError exposes the type of the service errors in the extensions of the GraphQL errors, since every response
+// of the endpoint has the same HTTP status
+type resolverError struct {
+	err error
+}
+
+func newResolverError(err error) *resolverError {
+	return &resolverError{err}
+}
+
+func (e resolverError) Error() string {
+	switch e.err.(type) {
+	case *todoerrors.Conflict, *todoerrors.Unauthorized, *todoerrors.MissingInfo, *todoerrors.Validation,
+		*todoerrors.NotFound, *todoerrors.PreconditionFailed, *todoerrors.FailedDependency,
+		*todoerrors.UnexpectedInternal:
+		return e.err.Error()
+	default:
+		return msgs.UnexpectedError
+	}
+}
+
+func (e resolverError) Extensions() map[string]interface{} {
+	switch castedErr := e.err.(type) {
+	case *todoerrors.Conflict:
+		return map[string]interface{}{"code": msgs.CodeConflict, "conflicts": castedErr.Fields()}
+	case *todoerrors.Unauthorized:
+		return map[string]interface{}{"code": msgs.CodeUnauthorized}
+	case *todoerrors.MissingInfo:
+		return map[string]interface{}{"code": msgs.CodeBadRequest}
+	case *todoerrors.Validation:
+		invalidFields := []map[string]string{}
+		for _, field := range castedErr.InvalidFields().Fields() {
+			invalidFields = append(invalidFields, map[string]string{
+				"name":        field.Name(),
+				"description": field.Description(),
+			})
+		}
+		return map[string]interface{}{"code": msgs.CodeValidation, "invalid_fields": invalidFields}
+	case *todoerrors.NotFound:
+		return map[string]interface{}{"code": msgs.CodeNotFound}
+	case *todoerrors.PreconditionFailed:
+		return map[string]interface{}{"code": msgs.CodePreconditionFailed}
+	case *todoerrors.FailedDependency:
+		return map[string]interface{}{"code": msgs.CodeFailedDependency}
+	default:
+		return map[string]interface{}{"code": msgs.CodeInternal}
+	}
+}
@@ -0,0 +1,133 @@
+package graph
+
+import (
+	"context"
+	"sync"
+	"todo/src/core/domain"
+	interfaces "todo/src/core/interfaces/services"
+)
+
+type contextKey string
+
+const loadersKey contextKey = "loaders"
+
+// batchLoader reads values by key for a single request. The keys primed before the values are read, such as the IDs
+// of every collection in a list, are read together on the first load, so nested selections cost one query per level
+// instead of one query per parent.
+type batchLoader[V any] struct {
+	mutex   sync.Mutex
+	fetch   func(keys []int) (map[int]V, error)
+	pending []int
+	values  map[int]V
+	missing map[int]bool
+	errors  map[int]error
+}
+
+func newBatchLoader[V any](fetch func(keys []int) (map[int]V, error)) *batchLoader[V] {
+	return &batchLoader[V]{
+		fetch:   fetch,
+		values:  map[int]V{},
+		missing: map[int]bool{},
+		errors:  map[int]error{},
+	}
+}
+
+// prime queues the keys to be read with the next load
+func (l *batchLoader[V]) prime(keys ...int) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for _, key := range keys {
+		l.queue(key)
+	}
+}
+
+// store caches a value already read, so that loading its key does not query it again
+func (l *batchLoader[V]) store(key int, value V) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.values[key] = value
+}
+
+// load reads the value of the key, together with every key queued so far. The boolean is false when no value
+// exists for the key.
+func (l *batchLoader[V]) load(key int) (V, bool, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.queue(key)
+	if len(l.pending) > 0 {
+		keys := l.pending
+		l.pending = nil
+		values, err := l.fetch(keys)
+		for _, pendingKey := range keys {
+			if err != nil {
+				l.errors[pendingKey] = err
+				continue
+			}
+			if value, ok := values[pendingKey]; ok {
+				l.values[pendingKey] = value
+			} else {
+				l.missing[pendingKey] = true
+			}
+		}
+	}
+
+	value, ok := l.values[key]
+	return value, ok, l.errors[key]
+}
+
+// queue adds the key to the pending keys unless it has been read or queued already. The mutex must be held.
+func (l *batchLoader[V]) queue(key int) {
+	if _, ok := l.values[key]; ok {
+		return
+	}
+	if _, ok := l.errors[key]; ok || l.missing[key] {
+		return
+	}
+	for _, pendingKey := range l.pending {
+		if pendingKey == key {
+			return
+		}
+	}
+	l.pending = append(l.pending, key)
+}
+
+// loaders holds the batch loaders of a request, which are never shared between users
+type loaders struct {
+	collections *batchLoader[domain.Collection]
+	tasks       *batchLoader[[]domain.Task]
+}
+
+func newLoaders(collectionService interfaces.ICollection, userId int) *loaders {
+	return &loaders{
+		collections: newBatchLoader(func(keys []int) (map[int]domain.Collection, error) {
+			collectionList, err := collectionService.FindByIds(keys, userId)
+			if err != nil {
+				return nil, err
+			}
+			collections := map[int]domain.Collection{}
+			for _, collection := range collectionList {
+				collections[collection.Id()] = collection
+			}
+			return collections, nil
+		}),
+		tasks: newBatchLoader(func(keys []int) (map[int][]domain.Task, error) {
+			tasksByCollection, err := collectionService.FindTasksOfCollections(keys, userId)
+			if err != nil {
+				return nil, err
+			}
+			for _, key := range keys {
+				if _, ok := tasksByCollection[key]; !ok {
+					tasksByCollection[key] = []domain.Task{}
+				}
+			}
+			return tasksByCollection, nil
+		}),
+	}
+}
+
+func loadersFromContext(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey).(*loaders)
+}
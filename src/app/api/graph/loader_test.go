@@ -0,0 +1,66 @@
+package graph
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+func TestBatchLoader(t *testing.T) {
+	t.Run("should read the primed keys in a single fetch", func(t *testing.T) {
+		var fetchedKeys [][]int
+		loader := newBatchLoader(func(keys []int) (map[int]string, error) {
+			fetchedKeys = append(fetchedKeys, keys)
+			return map[int]string{1: "one", 3: "three"}, nil
+		})
+		loader.prime(1, 2, 3, 1)
+
+		var waitGroup sync.WaitGroup
+		for _, key := range []int{1, 2, 3} {
+			waitGroup.Add(1)
+			go func() {
+				defer waitGroup.Done()
+				_, _, _ = loader.load(key)
+			}()
+		}
+		waitGroup.Wait()
+		value, ok, err := loader.load(1)
+		_, missingOk, _ := loader.load(2)
+
+		assert.Nil(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "one", value)
+		assert.False(t, missingOk)
+		assert.Equal(t, [][]int{{1, 2, 3}}, fetchedKeys)
+	})
+
+	t.Run("should not fetch the stored values", func(t *testing.T) {
+		loader := newBatchLoader(func(keys []int) (map[int]string, error) {
+			t.Fatalf("unexpected fetch of %v", keys)
+			return nil, nil
+		})
+		loader.store(4, "four")
+
+		value, ok, err := loader.load(4)
+
+		assert.Nil(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "four", value)
+	})
+
+	t.Run("should return the fetch error for every key read with it", func(t *testing.T) {
+		fetchErr := errors.New("connection refused")
+		loader := newBatchLoader(func(keys []int) (map[int]string, error) {
+			return nil, fetchErr
+		})
+		loader.prime(5)
+
+		_, _, loadErr := loader.load(6)
+		_, ok, primedErr := loader.load(5)
+
+		assert.Equal(t, fetchErr, loadErr)
+		assert.Equal(t, fetchErr, primedErr)
+		assert.False(t, ok)
+	})
+}
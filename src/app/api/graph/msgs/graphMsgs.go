@@ -0,0 +1,13 @@
+package msgs
+
+const (
+	CodeConflict           = "CONFLICT"
+	CodeUnauthorized       = "UNAUTHORIZED"
+	CodeBadRequest         = "BAD_REQUEST"
+	CodeValidation         = "VALIDATION"
+	CodeNotFound           = "NOT_FOUND"
+	CodePreconditionFailed = "PRECONDITION_FAILED"
+	CodeFailedDependency   = "FAILED_DEPENDENCY"
+	CodeInternal           = "INTERNAL"
+	UnexpectedError        = "An unexpected error has occurred"
+)
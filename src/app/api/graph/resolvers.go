@@ -0,0 +1,215 @@
+package graph
+
+import (
+	"context"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/labstack/gommon/log"
+	"todo/src/core/domain"
+	interfaces "todo/src/core/interfaces/services"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+type rootResolver struct {
+	taskService       interfaces.ITask
+	collectionService interfaces.ICollection
+}
+
+type idArgs struct {
+	Id int32
+}
+
+type deleteArgs struct {
+	Id      int32
+	Version *int32
+}
+
+type taskInput struct {
+	Description  string
+	Finished     bool
+	DueDate      *graphql.Time
+	Tags         *[]string
+	CollectionId *int32
+}
+
+func (r rootResolver) Me(ctx context.Context) *accountResolver {
+	return &accountResolver{r, accountFromContext(ctx)}
+}
+
+// Collection resolves to null when the user has no collection with the ID
+func (r rootResolver) Collection(ctx context.Context, args idArgs) (*collectionResolver, error) {
+	userId := accountFromContext(ctx).Id()
+	collection, err := r.collectionService.FindById(int(args.Id), userId, *domain.NewExpansion())
+	if _, ok := err.(*todoerrors.NotFound); ok {
+		return nil, nil
+	}
+	if err != nil {
+		log.Error(err)
+		return nil, newResolverError(err)
+	}
+
+	return newCollectionResolvers(ctx, *collection)[0], nil
+}
+
+// Task resolves to null when the user has no task with the ID
+func (r rootResolver) Task(ctx context.Context, args idArgs) (*taskResolver, error) {
+	userId := accountFromContext(ctx).Id()
+	task, err := r.taskService.FindById(int(args.Id), userId)
+	if _, ok := err.(*todoerrors.NotFound); ok {
+		return nil, nil
+	}
+	if err != nil {
+		log.Error(err)
+		return nil, newResolverError(err)
+	}
+
+	return newTaskResolvers(ctx, *task)[0], nil
+}
+
+func (r rootResolver) CreateCollection(ctx context.Context, args struct{ Name string }) (*collectionResolver, error) {
+	collection, validationErr := domain.NewValidatedCollection(-1, args.Name)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return nil, newResolverError(validationErr)
+	}
+
+	userId := accountFromContext(ctx).Id()
+	collectionId, err := r.collectionService.Create(*collection, userId)
+	if err != nil {
+		log.Error(err)
+		return nil, newResolverError(err)
+	}
+
+	return r.findCollection(ctx, collectionId)
+}
+
+func (r rootResolver) UpdateCollection(ctx context.Context, args struct {
+	Id      int32
+	Name    string
+	Version *int32
+}) (*collectionResolver, error) {
+	collection, validationErr := domain.NewValidatedCollection(int(args.Id), args.Name)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return nil, newResolverError(validationErr)
+	}
+	collection.SetVersion(versionArg(args.Version))
+
+	userId := accountFromContext(ctx).Id()
+	if err := r.collectionService.Update(*collection, userId); err != nil {
+		log.Error(err)
+		return nil, newResolverError(err)
+	}
+
+	return r.findCollection(ctx, int(args.Id))
+}
+
+func (r rootResolver) DeleteCollection(ctx context.Context, args deleteArgs) (bool, error) {
+	userId := accountFromContext(ctx).Id()
+	if _, err := r.collectionService.Delete(int(args.Id), userId, versionArg(args.Version)); err != nil {
+		log.Error(err)
+		return false, newResolverError(err)
+	}
+
+	return true, nil
+}
+
+func (r rootResolver) CreateTask(ctx context.Context, args struct{ Input taskInput }) (*taskResolver, error) {
+	task, validationErr := newTaskFromInput(-1, args.Input)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return nil, newResolverError(validationErr)
+	}
+
+	userId := accountFromContext(ctx).Id()
+	taskId, err := r.taskService.Create(*task, userId)
+	if err != nil {
+		log.Error(err)
+		return nil, newResolverError(err)
+	}
+
+	return r.findTask(ctx, taskId)
+}
+
+func (r rootResolver) UpdateTask(ctx context.Context, args struct {
+	Id      int32
+	Input   taskInput
+	Version *int32
+}) (*taskResolver, error) {
+	task, validationErr := newTaskFromInput(int(args.Id), args.Input)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return nil, newResolverError(validationErr)
+	}
+	task.SetVersion(versionArg(args.Version))
+
+	userId := accountFromContext(ctx).Id()
+	if _, err := r.taskService.Update(*task, userId); err != nil {
+		log.Error(err)
+		return nil, newResolverError(err)
+	}
+
+	return r.findTask(ctx, int(args.Id))
+}
+
+func (r rootResolver) DeleteTask(ctx context.Context, args deleteArgs) (bool, error) {
+	userId := accountFromContext(ctx).Id()
+	if _, err := r.taskService.Delete(int(args.Id), userId, versionArg(args.Version)); err != nil {
+		log.Error(err)
+		return false, newResolverError(err)
+	}
+
+	return true, nil
+}
+
+// findCollection reads the collection a mutation has changed, so that the client can select its new state
+func (r rootResolver) findCollection(ctx context.Context, collectionId int) (*collectionResolver, error) {
+	collection, err := r.collectionService.FindById(collectionId, accountFromContext(ctx).Id(),
+		*domain.NewExpansion())
+	if err != nil {
+		log.Error(err)
+		return nil, newResolverError(err)
+	}
+
+	return newCollectionResolvers(ctx, *collection)[0], nil
+}
+
+// findTask reads the task a mutation has changed, so that the client can select its new state
+func (r rootResolver) findTask(ctx context.Context, taskId int) (*taskResolver, error) {
+	task, err := r.taskService.FindById(taskId, accountFromContext(ctx).Id())
+	if err != nil {
+		log.Error(err)
+		return nil, newResolverError(err)
+	}
+
+	return newTaskResolvers(ctx, *task)[0], nil
+}
+
+func newTaskFromInput(taskId int, input taskInput) (*domain.Task, *todoerrors.Validation) {
+	var tags []string
+	if input.Tags != nil {
+		tags = *input.Tags
+	}
+	validatedTags, validationErr := domain.NewValidatedTags(tags)
+	if validationErr != nil {
+		return nil, validationErr
+	}
+	collectionId := 0
+	if input.CollectionId != nil {
+		collectionId = int(*input.CollectionId)
+	}
+	task := domain.NewTask(taskId, input.Description, input.Finished, domain.NewCollection(collectionId, ""))
+	if input.DueDate != nil {
+		task.SetDueDate(&input.DueDate.Time)
+	}
+	task.SetTags(validatedTags)
+
+	return task, nil
+}
+
+// versionArg returns the expected version of an update or deletion, zero meaning any version
+func versionArg(version *int32) int {
+	if version == nil {
+		return 0
+	}
+	return int(*version)
+}
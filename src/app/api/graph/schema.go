@@ -0,0 +1,43 @@
+package graph
+
+import (
+	"context"
+	_ "embed"
+	"github.com/graph-gophers/graphql-go"
+	"todo/src/core/domain"
+	interfaces "todo/src/core/interfaces/services"
+)
+
+const accountKey contextKey = "account"
+
+//go:embed schema.graphql
+var schemaDefinition string
+
+// Schema executes the GraphQL operations on accounts, collections and tasks with the same services as the REST routes
+type Schema struct {
+	schema            *graphql.Schema
+	collectionService interfaces.ICollection
+}
+
+func NewSchema(taskService interfaces.ITask, collectionService interfaces.ICollection) *Schema {
+	resolver := &rootResolver{
+		taskService:       taskService,
+		collectionService: collectionService,
+	}
+	return &Schema{
+		schema:            graphql.MustParseSchema(schemaDefinition, resolver, graphql.MaxDepth(10)),
+		collectionService: collectionService,
+	}
+}
+
+// Exec runs the operation on behalf of the signed in account, with batch loaders of its own
+func (s Schema) Exec(ctx context.Context, account domain.Account, query, operationName string,
+	variables map[string]interface{}) *graphql.Response {
+	ctx = context.WithValue(ctx, accountKey, account)
+	ctx = context.WithValue(ctx, loadersKey, newLoaders(s.collectionService, account.Id()))
+	return s.schema.Exec(ctx, query, operationName, variables)
+}
+
+func accountFromContext(ctx context.Context) domain.Account {
+	return ctx.Value(accountKey).(domain.Account)
+}
@@ -0,0 +1,196 @@
+package graph
+
+import (
+	"context"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/labstack/gommon/log"
+	"todo/src/core/domain"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+type accountResolver struct {
+	root    rootResolver
+	account domain.Account
+}
+
+func (r accountResolver) Id() int32 {
+	return int32(r.account.Id())
+}
+
+func (r accountResolver) Email() string {
+	return r.account.Email()
+}
+
+func (r accountResolver) Collections(ctx context.Context, args struct {
+	Limit  int32
+	Offset int32
+	Text   *string
+}) ([]*collectionResolver, error) {
+	pagination, validationErr := newPagination(args.Limit, args.Offset, domain.CollectionSortFields)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return nil, newResolverError(validationErr)
+	}
+	filter := domain.NewCollectionFilter(stringArg(args.Text), nil, nil)
+
+	collectionList, _, err := r.root.collectionService.FindAll(r.account.Id(), *filter, *pagination)
+	if err != nil {
+		log.Error(err)
+		return nil, newResolverError(err)
+	}
+
+	return newCollectionResolvers(ctx, collectionList...), nil
+}
+
+func (r accountResolver) Tasks(ctx context.Context, args struct {
+	Limit        int32
+	Offset       int32
+	Finished     *bool
+	CollectionId *int32
+	Text         *string
+}) ([]*taskResolver, error) {
+	pagination, validationErr := newPagination(args.Limit, args.Offset, domain.TaskSortFields)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return nil, newResolverError(validationErr)
+	}
+	collectionId := 0
+	if args.CollectionId != nil {
+		collectionId = int(*args.CollectionId)
+	}
+	filter, validationErr := domain.NewValidatedTaskFilter(args.Finished, collectionId, stringArg(args.Text), nil,
+		nil, "")
+	if validationErr != nil {
+		log.Error(validationErr)
+		return nil, newResolverError(validationErr)
+	}
+
+	taskList, _, err := r.root.taskService.FindAll(r.account.Id(), *filter, *pagination)
+	if err != nil {
+		log.Error(err)
+		return nil, newResolverError(err)
+	}
+
+	return newTaskResolvers(ctx, taskList...), nil
+}
+
+type collectionResolver struct {
+	collection domain.Collection
+}
+
+// newCollectionResolvers caches the collections and queues their IDs, so that the tasks of all of them are read
+// in a single query
+func newCollectionResolvers(ctx context.Context, collections ...domain.Collection) []*collectionResolver {
+	loaders := loadersFromContext(ctx)
+	resolvers := make([]*collectionResolver, len(collections))
+	for index, collection := range collections {
+		loaders.collections.store(collection.Id(), collection)
+		loaders.tasks.prime(collection.Id())
+		resolvers[index] = &collectionResolver{collection}
+	}
+	return resolvers
+}
+
+func (r collectionResolver) Id() int32 {
+	return int32(r.collection.Id())
+}
+
+func (r collectionResolver) Name() string {
+	return r.collection.Name()
+}
+
+func (r collectionResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: r.collection.CreatedAt()}
+}
+
+func (r collectionResolver) Version() int32 {
+	return int32(r.collection.Version())
+}
+
+func (r collectionResolver) Tasks(ctx context.Context) ([]*taskResolver, error) {
+	taskList, _, err := loadersFromContext(ctx).tasks.load(r.collection.Id())
+	if err != nil {
+		log.Error(err)
+		return nil, newResolverError(err)
+	}
+
+	return newTaskResolvers(ctx, taskList...), nil
+}
+
+type taskResolver struct {
+	task domain.Task
+}
+
+// newTaskResolvers queues the IDs of the collections of the tasks, so that all of them are read in a single query
+func newTaskResolvers(ctx context.Context, tasks ...domain.Task) []*taskResolver {
+	loaders := loadersFromContext(ctx)
+	resolvers := make([]*taskResolver, len(tasks))
+	for index, task := range tasks {
+		if task.Collection() != nil && task.Collection().Id() > 0 {
+			loaders.collections.prime(task.Collection().Id())
+		}
+		resolvers[index] = &taskResolver{task}
+	}
+	return resolvers
+}
+
+func (r taskResolver) Id() int32 {
+	return int32(r.task.Id())
+}
+
+func (r taskResolver) Description() string {
+	return r.task.Description()
+}
+
+func (r taskResolver) Finished() bool {
+	return r.task.Finished()
+}
+
+func (r taskResolver) DueDate() *graphql.Time {
+	if r.task.DueDate() == nil {
+		return nil
+	}
+	return &graphql.Time{Time: *r.task.DueDate()}
+}
+
+func (r taskResolver) Tags() []string {
+	if r.task.Tags() == nil {
+		return []string{}
+	}
+	return r.task.Tags()
+}
+
+func (r taskResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: r.task.CreatedAt()}
+}
+
+func (r taskResolver) Version() int32 {
+	return int32(r.task.Version())
+}
+
+func (r taskResolver) Collection(ctx context.Context) (*collectionResolver, error) {
+	if r.task.Collection() == nil || r.task.Collection().Id() <= 0 {
+		return nil, nil
+	}
+	collection, ok, err := loadersFromContext(ctx).collections.load(r.task.Collection().Id())
+	if err != nil {
+		log.Error(err)
+		return nil, newResolverError(err)
+	}
+	if !ok {
+		return nil, nil
+	}
+
+	return newCollectionResolvers(ctx, collection)[0], nil
+}
+
+func newPagination(limit, offset int32, allowedSortFields []string) (*domain.Pagination, *todoerrors.Validation) {
+	return domain.NewValidatedPagination(int(limit), int(offset), "", "", allowedSortFields)
+}
+
+func stringArg(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,90 @@
+package rpc
+
+import (
+	"context"
+	"github.com/labstack/gommon/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"strings"
+	"todo/src/app/rpc/msgs"
+	"todo/src/app/rpc/pb"
+	"todo/src/core/domain"
+)
+
+type contextKey string
+
+const (
+	accountKey       contextKey = "account"
+	authorizationKey            = "authorization"
+)
+
+// publicMethods are the methods called without an access token, which they are meant to issue
+var publicMethods = map[string]bool{
+	pb.AuthService_SignUp_FullMethodName: true,
+	pb.AuthService_SignIn_FullMethodName: true,
+}
+
+// authenticateUnary reads the account of the access token sent in the authorization metadata, as the
+// authorization middleware of the REST API does with the Authorization header
+func authenticateUnary(ctx context.Context, request interface{}, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+	if publicMethods[info.FullMethod] {
+		return handler(ctx, request)
+	}
+
+	account, err := authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(context.WithValue(ctx, accountKey, *account), request)
+}
+
+func authenticateStream(server interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+	if publicMethods[info.FullMethod] {
+		return handler(server, stream)
+	}
+
+	account, err := authenticate(stream.Context())
+	if err != nil {
+		return err
+	}
+	return handler(server, &authenticatedStream{stream, context.WithValue(stream.Context(), accountKey, *account)})
+}
+
+func authenticate(ctx context.Context) (*domain.Account, error) {
+	values := metadata.ValueFromIncomingContext(ctx, authorizationKey)
+	if len(values) == 0 {
+		log.Error(msgs.MissingToken)
+		return nil, status.Error(codes.Unauthenticated, msgs.MissingToken)
+	}
+	token, found := strings.CutPrefix(strings.TrimSpace(values[0]), "Bearer")
+	if !found || strings.TrimSpace(token) == "" {
+		log.Error(msgs.MissingToken)
+		return nil, status.Error(codes.Unauthenticated, msgs.MissingToken)
+	}
+
+	account, err := domain.NewAccountFromToken(strings.TrimSpace(token))
+	if err != nil {
+		log.Error(err)
+		return nil, statusError(err)
+	}
+	return account, nil
+}
+
+// accountId returns the ID of the account authenticated by the interceptors
+func accountId(ctx context.Context) int {
+	return ctx.Value(accountKey).(domain.Account).Id()
+}
+
+// authenticatedStream carries the authenticated account in the context of a server stream
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s authenticatedStream) Context() context.Context {
+	return s.ctx
+}
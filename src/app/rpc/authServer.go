@@ -0,0 +1,48 @@
+package rpc
+
+import (
+	"context"
+	"github.com/labstack/gommon/log"
+	"todo/src/app/rpc/pb"
+	"todo/src/core/domain"
+	interfaces "todo/src/core/interfaces/services"
+)
+
+type authServer struct {
+	pb.UnimplementedAuthServiceServer
+	service interfaces.IAuth
+}
+
+func (s authServer) SignUp(_ context.Context, request *pb.AccountRequest) (*pb.Account, error) {
+	account, validationErr := domain.NewValidatedAccount(-1, request.GetName(), request.GetEmail(),
+		request.GetPassword(), "")
+	if validationErr != nil {
+		log.Error(validationErr)
+		return nil, statusError(validationErr)
+	}
+
+	account, err := s.service.SignUp(*account)
+	if err != nil {
+		log.Error(err)
+		return nil, statusError(err)
+	}
+
+	return newAccountMessage(*account), nil
+}
+
+func (s authServer) SignIn(_ context.Context, request *pb.AccountRequest) (*pb.Account, error) {
+	account, validationErr := domain.NewValidatedAccount(-1, request.GetName(), request.GetEmail(),
+		request.GetPassword(), "")
+	if validationErr != nil {
+		log.Error(validationErr)
+		return nil, statusError(validationErr)
+	}
+
+	account, err := s.service.SignIn(*account)
+	if err != nil {
+		log.Error(err)
+		return nil, statusError(err)
+	}
+
+	return newAccountMessage(*account), nil
+}
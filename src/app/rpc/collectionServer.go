@@ -0,0 +1,144 @@
+package rpc
+
+import (
+	"context"
+	"github.com/labstack/gommon/log"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"strings"
+	"todo/src/app/rpc/pb"
+	"todo/src/core/domain"
+	interfaces "todo/src/core/interfaces/services"
+)
+
+type collectionServer struct {
+	pb.UnimplementedCollectionServiceServer
+	service     interfaces.ICollection
+	taskService interfaces.ITask
+}
+
+func (s collectionServer) CreateCollection(ctx context.Context, request *pb.CollectionRequest) (*pb.IdResponse,
+	error) {
+	collection, validationErr := domain.NewValidatedCollection(-1, request.GetName())
+	if validationErr != nil {
+		log.Error(validationErr)
+		return nil, statusError(validationErr)
+	}
+
+	collectionId, err := s.service.Create(*collection, accountId(ctx))
+	if err != nil {
+		log.Error(err)
+		return nil, statusError(err)
+	}
+
+	return &pb.IdResponse{Id: int64(collectionId)}, nil
+}
+
+func (s collectionServer) UpdateCollection(ctx context.Context, request *pb.UpdateCollectionRequest) (*emptypb.Empty,
+	error) {
+	collection, validationErr := domain.NewValidatedCollection(int(request.GetId()), request.GetName())
+	if validationErr != nil {
+		log.Error(validationErr)
+		return nil, statusError(validationErr)
+	}
+	collection.SetVersion(int(request.GetVersion()))
+
+	if err := s.service.Update(*collection, accountId(ctx)); err != nil {
+		log.Error(err)
+		return nil, statusError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s collectionServer) DeleteCollection(ctx context.Context, request *pb.DeleteRequest) (*pb.UndoResponse,
+	error) {
+	undoToken, err := s.service.Delete(int(request.GetId()), accountId(ctx), int(request.GetVersion()))
+	if err != nil {
+		log.Error(err)
+		return nil, statusError(err)
+	}
+
+	return &pb.UndoResponse{UndoToken: undoToken}, nil
+}
+
+func (s collectionServer) GetCollection(ctx context.Context, request *pb.GetCollectionRequest) (*pb.Collection,
+	error) {
+	expansion, validationErr := domain.NewValidatedExpansion(strings.Join(request.GetExpand(), ","),
+		domain.CollectionExpansionFields)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return nil, statusError(validationErr)
+	}
+
+	collection, err := s.service.FindById(int(request.GetId()), accountId(ctx), *expansion)
+	if err != nil {
+		log.Error(err)
+		return nil, statusError(err)
+	}
+
+	return newCollectionMessage(*collection), nil
+}
+
+func (s collectionServer) ListCollections(ctx context.Context, request *pb.ListCollectionsRequest) (*pb.CollectionList,
+	error) {
+	paginationMessage := request.GetPagination()
+	limit := int(paginationMessage.GetLimit())
+	if limit == 0 {
+		limit = domain.DefaultPageLimit
+	}
+	pagination, validationErr := domain.NewValidatedPagination(limit, int(paginationMessage.GetOffset()),
+		paginationMessage.GetSort(), paginationMessage.GetOrder(), domain.CollectionSortFields)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return nil, statusError(validationErr)
+	}
+	filter, validationErr := domain.NewValidatedCollectionFilter(request.GetText(), timeOf(request.GetCreatedFrom()),
+		timeOf(request.GetCreatedTo()))
+	if validationErr != nil {
+		log.Error(validationErr)
+		return nil, statusError(validationErr)
+	}
+
+	collectionList, total, err := s.service.FindAll(accountId(ctx), *filter, *pagination)
+	if err != nil {
+		log.Error(err)
+		return nil, statusError(err)
+	}
+
+	collectionListMessage := &pb.CollectionList{Total: int64(total)}
+	for _, collection := range collectionList {
+		collectionListMessage.Collections = append(collectionListMessage.Collections, newCollectionMessage(collection))
+	}
+	return collectionListMessage, nil
+}
+
+// ListCollectionTasks sends the tasks as their pages are read, stopping when the client cancels the call
+func (s collectionServer) ListCollectionTasks(request *pb.ListCollectionTasksRequest,
+	stream grpc.ServerStreamingServer[pb.Task]) error {
+	filter, validationErr := newTaskFilter(request.GetFilter())
+	if validationErr != nil {
+		log.Error(validationErr)
+		return statusError(validationErr)
+	}
+
+	userId := accountId(stream.Context())
+	for offset := 0; ; offset += domain.MaxPageLimit {
+		pagination := domain.NewPagination(domain.MaxPageLimit, offset, "id", domain.SortAscending)
+		taskList, total, err := s.taskService.FindByCollectionId(int(request.GetCollectionId()), userId, *filter,
+			*pagination)
+		if err != nil {
+			log.Error(err)
+			return statusError(err)
+		}
+		for _, task := range taskList {
+			if err = stream.Send(newTaskMessage(task)); err != nil {
+				log.Error(err)
+				return err
+			}
+		}
+		if len(taskList) == 0 || offset+len(taskList) >= total {
+			return nil
+		}
+	}
+}
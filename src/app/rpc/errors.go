@@ -0,0 +1,65 @@
+package rpc
+
+import (
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"todo/src/app/rpc/msgs"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+// statusCode maps the service errors onto the gRPC codes closest to the HTTP statuses of the REST API
+func statusCode(err error) codes.Code {
+	switch err.(type) {
+	case *todoerrors.Conflict:
+		return codes.AlreadyExists
+	case *todoerrors.Unauthorized:
+		return codes.Unauthenticated
+	case *todoerrors.MissingInfo, *todoerrors.Validation:
+		return codes.InvalidArgument
+	case *todoerrors.NotFound:
+		return codes.NotFound
+	case *todoerrors.PreconditionFailed:
+		return codes.FailedPrecondition
+	case *todoerrors.FailedDependency:
+		return codes.Aborted
+	default:
+		return codes.Internal
+	}
+}
+
+// statusError converts a service error into a gRPC status. The invalid fields of validation errors are sent as
+// BadRequest details, and the conflicting fields as ResourceInfo details.
+func statusError(err error) error {
+	code := statusCode(err)
+	message := err.Error()
+	if code == codes.Internal {
+		if _, ok := err.(*todoerrors.UnexpectedInternal); !ok {
+			message = msgs.UnexpectedServerError
+		}
+	}
+	errorStatus := status.New(code, message)
+
+	switch castedErr := err.(type) {
+	case *todoerrors.Validation:
+		badRequest := &errdetails.BadRequest{}
+		for _, field := range castedErr.InvalidFields().Fields() {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       field.Name(),
+				Description: field.Description(),
+			})
+		}
+		if detailedStatus, detailsErr := errorStatus.WithDetails(badRequest); detailsErr == nil {
+			errorStatus = detailedStatus
+		}
+	case *todoerrors.Conflict:
+		for _, field := range castedErr.Fields() {
+			resourceInfo := &errdetails.ResourceInfo{ResourceName: field, Description: message}
+			if detailedStatus, detailsErr := errorStatus.WithDetails(resourceInfo); detailsErr == nil {
+				errorStatus = detailedStatus
+			}
+		}
+	}
+
+	return errorStatus.Err()
+}
@@ -0,0 +1,92 @@
+package rpc
+
+import (
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"time"
+	"todo/src/app/rpc/pb"
+	"todo/src/core/domain"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+func newAccountMessage(account domain.Account) *pb.Account {
+	return &pb.Account{
+		Id:          int64(account.Id()),
+		Name:        account.Name(),
+		Email:       account.Email(),
+		AccessToken: account.Token(),
+	}
+}
+
+func newCollectionMessage(collection domain.Collection) *pb.Collection {
+	collectionMessage := &pb.Collection{
+		Id:        int64(collection.Id()),
+		Name:      collection.Name(),
+		CreatedAt: timestamppb.New(collection.CreatedAt()),
+		Version:   int64(collection.Version()),
+		Tags:      collection.Tags(),
+	}
+	for _, task := range collection.Tasks() {
+		collectionMessage.Tasks = append(collectionMessage.Tasks, newTaskMessage(task))
+	}
+	return collectionMessage
+}
+
+func newTaskMessage(task domain.Task) *pb.Task {
+	taskMessage := &pb.Task{
+		Id:          int64(task.Id()),
+		Description: task.Description(),
+		Finished:    task.Finished(),
+		Tags:        task.Tags(),
+		CreatedAt:   timestamppb.New(task.CreatedAt()),
+		Version:     int64(task.Version()),
+	}
+	if task.DueDate() != nil {
+		taskMessage.DueDate = timestamppb.New(*task.DueDate())
+	}
+	if task.Collection() != nil {
+		taskMessage.CollectionId = int64(task.Collection().Id())
+		taskMessage.CollectionName = task.Collection().Name()
+	}
+	return taskMessage
+}
+
+func newTaskFromMessage(taskId int, taskMessage *pb.TaskRequest) (*domain.Task, *todoerrors.Validation) {
+	tags, validationErr := domain.NewValidatedTags(taskMessage.GetTags())
+	if validationErr != nil {
+		return nil, validationErr
+	}
+	task := domain.NewTask(
+		taskId,
+		taskMessage.GetDescription(),
+		taskMessage.GetFinished(),
+		domain.NewCollection(int(taskMessage.GetCollectionId()), ""),
+	)
+	task.SetDueDate(timeOf(taskMessage.GetDueDate()))
+	task.SetTags(tags)
+
+	return task, nil
+}
+
+func newTaskFilter(filterMessage *pb.TaskFilter) (*domain.TaskFilter, *todoerrors.Validation) {
+	var finished *bool
+	if filterMessage != nil {
+		finished = filterMessage.Finished
+	}
+	return domain.NewValidatedTaskFilter(
+		finished,
+		int(filterMessage.GetCollectionId()),
+		filterMessage.GetText(),
+		timeOf(filterMessage.GetCreatedFrom()),
+		timeOf(filterMessage.GetCreatedTo()),
+		filterMessage.GetExpression(),
+	)
+}
+
+// timeOf converts an optional timestamp, leaving it unset when the message has none
+func timeOf(timestamp *timestamppb.Timestamp) *time.Time {
+	if timestamp == nil {
+		return nil
+	}
+	value := timestamp.AsTime()
+	return &value
+}
@@ -0,0 +1,13 @@
+package msgs
+
+const (
+	MissingToken          = "The access token must be sent in the authorization metadata as Bearer <token>."
+	MissingTask           = "The task data must be informed."
+	InvalidOperationKind  = "The operation kind is invalid. The allowed kinds are: KIND_CREATE, KIND_UPDATE, KIND_DELETE."
+	InvalidOperationId    = "The operation must reference a task ID greater than zero."
+	InvalidBatchDetails   = "The batch provided is invalid."
+	InvalidTaskDetails    = "The task provided is invalid."
+	Task                  = "task"
+	Operation             = "operations[%d]"
+	UnexpectedServerError = "Oops! An unexpected error has occurred."
+)
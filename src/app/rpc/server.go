@@ -0,0 +1,49 @@
+package rpc
+
+import (
+	"google.golang.org/grpc"
+	"net"
+	"todo/src/app/rpc/pb"
+	eventbus "todo/src/core/interfaces/events"
+	interfaces "todo/src/core/interfaces/services"
+	"todo/src/core/services"
+	"todo/src/infra/postgres"
+)
+
+//go:generate protoc -I proto --go_out=../../.. --go_opt=module=todo --go-grpc_out=../../.. --go-grpc_opt=module=todo todo.proto
+
+// NewServer builds the gRPC server of the auth, collection and task services, which share the database with the
+// REST API
+func NewServer(emitter eventbus.IEmitter) *grpc.Server {
+	connectionManager := postgres.NewPostgresConnectionManager()
+	undoRepository := postgres.NewUndoPostgresRepository(connectionManager)
+	authRepository := postgres.NewAuthPostgresRepository(connectionManager)
+	taskRepository := postgres.NewTaskPostgresRepository(connectionManager)
+	collectionRepository := postgres.NewCollectionPostgresRepository(connectionManager)
+	return newServer(
+		services.NewAuthService(authRepository, emitter),
+		services.NewTaskService(taskRepository, undoRepository, emitter),
+		services.NewCollectionService(collectionRepository, undoRepository, emitter),
+	)
+}
+
+func newServer(authService interfaces.IAuth, taskService interfaces.ITask,
+	collectionService interfaces.ICollection) *grpc.Server {
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(authenticateUnary),
+		grpc.ChainStreamInterceptor(authenticateStream),
+	)
+	pb.RegisterAuthServiceServer(server, authServer{service: authService})
+	pb.RegisterTaskServiceServer(server, taskServer{service: taskService})
+	pb.RegisterCollectionServiceServer(server, collectionServer{service: collectionService, taskService: taskService})
+	return server
+}
+
+// Serve listens on the address until the server is stopped
+func Serve(server *grpc.Server, address string) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	return server.Serve(listener)
+}
@@ -0,0 +1,378 @@
+package rpc
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"io"
+	"net"
+	"testing"
+	"time"
+	"todo/src/app/rpc/pb"
+	"todo/src/core/domain"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+type MockAuthService struct {
+	mock.Mock
+}
+
+func (m *MockAuthService) SignUp(account domain.Account) (*domain.Account, error) {
+	args := m.Called(account)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.Account), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAuthService) SignIn(account domain.Account) (*domain.Account, error) {
+	args := m.Called(account)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.Account), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+type MockTaskService struct {
+	mock.Mock
+}
+
+func (m *MockTaskService) Create(task domain.Task, userId int) (int, error) {
+	args := m.Called(task, userId)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTaskService) Update(task domain.Task, userId int) (string, error) {
+	args := m.Called(task, userId)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockTaskService) Delete(taskId, userId, version int) (string, error) {
+	args := m.Called(taskId, userId, version)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockTaskService) FindById(taskId, userId int) (*domain.Task, error) {
+	args := m.Called(taskId, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.Task), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockTaskService) Batch(batch domain.TaskBatch, userId int) (*domain.TaskBatchResult, error) {
+	args := m.Called(batch, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.TaskBatchResult), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockTaskService) FindAll(userId int, filter domain.TaskFilter,
+	pagination domain.Pagination) ([]domain.Task, int, error) {
+	args := m.Called(userId, filter, pagination)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.Task), args.Int(1), args.Error(2)
+	}
+	return nil, args.Int(1), args.Error(2)
+}
+
+func (m *MockTaskService) FindByCollectionId(collectionId, userId int, filter domain.TaskFilter,
+	pagination domain.Pagination) ([]domain.Task, int, error) {
+	args := m.Called(collectionId, userId, filter, pagination)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.Task), args.Int(1), args.Error(2)
+	}
+	return nil, args.Int(1), args.Error(2)
+}
+
+func (m *MockTaskService) FindPage(userId int, filter domain.TaskFilter,
+	pagination domain.KeysetPagination) (*domain.TaskPage, error) {
+	args := m.Called(userId, filter, pagination)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.TaskPage), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+type MockCollectionService struct {
+	mock.Mock
+}
+
+func (m *MockCollectionService) Create(collection domain.Collection, userId int) (int, error) {
+	args := m.Called(collection, userId)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockCollectionService) Update(collection domain.Collection, userId int) error {
+	args := m.Called(collection, userId)
+	return args.Error(0)
+}
+
+func (m *MockCollectionService) Delete(collectionId, userId, version int) (string, error) {
+	args := m.Called(collectionId, userId, version)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockCollectionService) Archive(collectionId, userId int) error {
+	args := m.Called(collectionId, userId)
+	return args.Error(0)
+}
+
+func (m *MockCollectionService) FindById(collectionId, userId int,
+	expansion domain.Expansion) (*domain.Collection, error) {
+	args := m.Called(collectionId, userId, expansion)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.Collection), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCollectionService) FindByIds(collectionIds []int, userId int) ([]domain.Collection, error) {
+	args := m.Called(collectionIds, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.Collection), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCollectionService) FindTasksOfCollections(collectionIds []int,
+	userId int) (map[int][]domain.Task, error) {
+	args := m.Called(collectionIds, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).(map[int][]domain.Task), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCollectionService) FindAll(userId int, filter domain.CollectionFilter,
+	pagination domain.Pagination) ([]domain.Collection, int, error) {
+	args := m.Called(userId, filter, pagination)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.Collection), args.Int(1), args.Error(2)
+	}
+	return nil, args.Int(1), args.Error(2)
+}
+
+// newTestConnection serves the services over an in-memory listener, returning a connection to it
+func newTestConnection(t *testing.T, authService *MockAuthService, taskService *MockTaskService,
+	collectionService *MockCollectionService) *grpc.ClientConn {
+	listener := bufconn.Listen(1024 * 1024)
+	server := newServer(authService, taskService, collectionService)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	connection, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.Nil(t, err)
+	t.Cleanup(func() {
+		_ = connection.Close()
+		server.Stop()
+	})
+	return connection
+}
+
+// newAuthenticatedContext sends the access token of the account with the calls
+func newAuthenticatedContext(t *testing.T, userId int) context.Context {
+	t.Setenv("SERVER_SECRET", "testSecret")
+	account := domain.NewAccount(userId, "", "example@example.com", "", "")
+	token, err := account.GenerateToken()
+	assert.Nil(t, err)
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func TestAuthentication(t *testing.T) {
+	t.Run("should reject the calls without an access token", func(t *testing.T) {
+		connection := newTestConnection(t, new(MockAuthService), new(MockTaskService), new(MockCollectionService))
+
+		_, err := pb.NewTaskServiceClient(connection).GetTask(context.Background(), &pb.IdRequest{Id: 1})
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("should reject the calls with a token signed by another secret", func(t *testing.T) {
+		connection := newTestConnection(t, new(MockAuthService), new(MockTaskService), new(MockCollectionService))
+		ctx := newAuthenticatedContext(t, 1)
+		t.Setenv("SERVER_SECRET", "anotherSecret")
+
+		_, err := pb.NewTaskServiceClient(connection).GetTask(ctx, &pb.IdRequest{Id: 1})
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("should sign in without an access token", func(t *testing.T) {
+		mockAuthService := new(MockAuthService)
+		connection := newTestConnection(t, mockAuthService, new(MockTaskService), new(MockCollectionService))
+		mockAuthService.On("SignIn", mock.Anything).
+			Return(domain.NewAccount(3, "Example", "example@example.com", "", "token"), nil)
+
+		account, err := pb.NewAuthServiceClient(connection).SignIn(context.Background(),
+			&pb.AccountRequest{Email: "example@example.com", Password: "ex@mplePassw0rd"})
+
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), account.GetId())
+		assert.Equal(t, "token", account.GetAccessToken())
+	})
+}
+
+func TestTaskServer(t *testing.T) {
+	t.Run("should read the task of the authenticated account", func(t *testing.T) {
+		mockTaskService := new(MockTaskService)
+		connection := newTestConnection(t, new(MockAuthService), mockTaskService, new(MockCollectionService))
+		task := domain.NewTask(4, "Run", true, domain.NewCollection(2, "Sport"))
+		dueDate := time.Date(2024, 1, 31, 18, 0, 0, 0, time.UTC)
+		task.SetDueDate(&dueDate)
+		mockTaskService.On("FindById", 4, 7).Return(task, nil)
+
+		taskMessage, err := pb.NewTaskServiceClient(connection).GetTask(newAuthenticatedContext(t, 7),
+			&pb.IdRequest{Id: 4})
+
+		assert.Nil(t, err)
+		assert.Equal(t, "Run", taskMessage.GetDescription())
+		assert.Equal(t, "Sport", taskMessage.GetCollectionName())
+		assert.Equal(t, dueDate, taskMessage.GetDueDate().AsTime())
+	})
+
+	t.Run("should map the service errors onto status codes", func(t *testing.T) {
+		mockTaskService := new(MockTaskService)
+		connection := newTestConnection(t, new(MockAuthService), mockTaskService, new(MockCollectionService))
+		mockTaskService.On("Delete", 4, 7, 2).Return("", todoerrors.NewPreconditionFailedError())
+		mockTaskService.On("FindById", 5, 7).Return(nil, todoerrors.NewNotFoundError())
+		client := pb.NewTaskServiceClient(connection)
+		ctx := newAuthenticatedContext(t, 7)
+
+		_, deleteErr := client.DeleteTask(ctx, &pb.DeleteRequest{Id: 4, Version: 2})
+		_, findErr := client.GetTask(ctx, &pb.IdRequest{Id: 5})
+
+		assert.Equal(t, codes.FailedPrecondition, status.Code(deleteErr))
+		assert.Equal(t, codes.NotFound, status.Code(findErr))
+	})
+
+	t.Run("should send the invalid fields as details", func(t *testing.T) {
+		connection := newTestConnection(t, new(MockAuthService), new(MockTaskService), new(MockCollectionService))
+
+		_, err := pb.NewTaskServiceClient(connection).CreateTask(newAuthenticatedContext(t, 7),
+			&pb.TaskRequest{Description: "Run", Tags: []string{"two words"}})
+
+		errorStatus := status.Convert(err)
+		assert.Equal(t, codes.InvalidArgument, errorStatus.Code())
+		assert.Len(t, errorStatus.Details(), 1)
+		badRequest, ok := errorStatus.Details()[0].(*errdetails.BadRequest)
+		assert.True(t, ok)
+		assert.Equal(t, "Task Tags", badRequest.GetFieldViolations()[0].GetField())
+	})
+
+	t.Run("should stream every page of the tasks", func(t *testing.T) {
+		mockTaskService := new(MockTaskService)
+		connection := newTestConnection(t, new(MockAuthService), mockTaskService, new(MockCollectionService))
+		nextCursor := domain.NewCursor("id", "asc", 4, time.Time{}, false)
+		mockTaskService.On("FindPage", 7, mock.MatchedBy(func(filter domain.TaskFilter) bool {
+			return filter.Finished() != nil && !*filter.Finished()
+		}), mock.MatchedBy(func(pagination domain.KeysetPagination) bool {
+			return pagination.Cursor() == nil
+		})).Return(domain.NewTaskPage([]domain.Task{*domain.NewTask(4, "Run", false, nil)}, nextCursor, nil), nil)
+		mockTaskService.On("FindPage", 7, mock.Anything, mock.MatchedBy(func(pagination domain.KeysetPagination) bool {
+			return pagination.Cursor() != nil && pagination.Cursor().Id() == 4
+		})).Return(domain.NewTaskPage([]domain.Task{*domain.NewTask(9, "Swim", false, nil)}, nil, nil), nil)
+		finished := false
+
+		stream, err := pb.NewTaskServiceClient(connection).ListTasks(newAuthenticatedContext(t, 7),
+			&pb.ListTasksRequest{Filter: &pb.TaskFilter{Finished: &finished}})
+		assert.Nil(t, err)
+		var descriptions []string
+		for {
+			taskMessage, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			assert.Nil(t, err)
+			descriptions = append(descriptions, taskMessage.GetDescription())
+		}
+
+		assert.Equal(t, []string{"Run", "Swim"}, descriptions)
+	})
+
+	t.Run("should report the result of every operation of the batch", func(t *testing.T) {
+		mockTaskService := new(MockTaskService)
+		connection := newTestConnection(t, new(MockAuthService), mockTaskService, new(MockCollectionService))
+		mockTaskService.On("Batch", mock.MatchedBy(func(batch domain.TaskBatch) bool {
+			return !batch.Atomic() && len(batch.Operations()) == 2
+		}), 7).Return(domain.NewTaskBatchResult([]domain.TaskOperationResult{
+			*domain.NewTaskOperationResult(10, nil),
+			*domain.NewTaskOperationResult(3, todoerrors.NewNotFoundError()),
+		}, true), nil)
+
+		batchResponse, err := pb.NewTaskServiceClient(connection).BatchTasks(newAuthenticatedContext(t, 7),
+			&pb.TaskBatchRequest{BestEffort: true, Operations: []*pb.TaskOperation{
+				{Kind: pb.TaskOperation_KIND_CREATE, Task: &pb.TaskRequest{Description: "Run"}},
+				{Kind: pb.TaskOperation_KIND_DELETE, Id: 3},
+			}})
+
+		assert.Nil(t, err)
+		assert.True(t, batchResponse.GetCommitted())
+		assert.Equal(t, int32(codes.OK), batchResponse.GetResults()[0].GetCode())
+		assert.Equal(t, int32(codes.NotFound), batchResponse.GetResults()[1].GetCode())
+	})
+}
+
+func TestCollectionServer(t *testing.T) {
+	t.Run("should read the collection with the expansion", func(t *testing.T) {
+		mockCollectionService := new(MockCollectionService)
+		connection := newTestConnection(t, new(MockAuthService), new(MockTaskService), mockCollectionService)
+		collection := domain.NewCollection(2, "Sport")
+		collection.SetTasks([]domain.Task{*domain.NewTask(4, "Run", false, collection)})
+		mockCollectionService.On("FindById", 2, 7, *domain.NewExpansion(domain.ExpandTasks)).Return(collection, nil)
+
+		collectionMessage, err := pb.NewCollectionServiceClient(connection).GetCollection(
+			newAuthenticatedContext(t, 7), &pb.GetCollectionRequest{Id: 2, Expand: []string{"tasks"}})
+
+		assert.Nil(t, err)
+		assert.Equal(t, "Sport", collectionMessage.GetName())
+		assert.Equal(t, "Run", collectionMessage.GetTasks()[0].GetDescription())
+	})
+
+	t.Run("should stream the tasks of the collection page by page", func(t *testing.T) {
+		mockTaskService := new(MockTaskService)
+		connection := newTestConnection(t, new(MockAuthService), mockTaskService, new(MockCollectionService))
+		firstPage := make([]domain.Task, domain.MaxPageLimit)
+		for index := range firstPage {
+			firstPage[index] = *domain.NewTask(index+1, "Task", false, nil)
+		}
+		mockTaskService.On("FindByCollectionId", 2, 7, mock.Anything, mock.MatchedBy(
+			func(pagination domain.Pagination) bool {
+				return pagination.Offset() == 0
+			})).Return(firstPage, domain.MaxPageLimit+1, nil)
+		mockTaskService.On("FindByCollectionId", 2, 7, mock.Anything, mock.MatchedBy(
+			func(pagination domain.Pagination) bool {
+				return pagination.Offset() == domain.MaxPageLimit
+			})).Return([]domain.Task{*domain.NewTask(500, "Last", false, nil)}, domain.MaxPageLimit+1, nil)
+
+		stream, err := pb.NewCollectionServiceClient(connection).ListCollectionTasks(newAuthenticatedContext(t, 7),
+			&pb.ListCollectionTasksRequest{CollectionId: 2})
+		assert.Nil(t, err)
+		var taskIds []int64
+		for {
+			taskMessage, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			assert.Nil(t, err)
+			taskIds = append(taskIds, taskMessage.GetId())
+		}
+
+		assert.Len(t, taskIds, domain.MaxPageLimit+1)
+		assert.Equal(t, int64(500), taskIds[domain.MaxPageLimit])
+		mockTaskService.AssertNumberOfCalls(t, "FindByCollectionId", 2)
+	})
+}
@@ -0,0 +1,195 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"github.com/labstack/gommon/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"todo/src/app/rpc/msgs"
+	"todo/src/app/rpc/pb"
+	"todo/src/core/domain"
+	interfaces "todo/src/core/interfaces/services"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+// taskPageSize is the number of tasks read at a time while streaming a task list
+const taskPageSize = domain.MaxPageLimit
+
+type taskServer struct {
+	pb.UnimplementedTaskServiceServer
+	service interfaces.ITask
+}
+
+func (s taskServer) CreateTask(ctx context.Context, request *pb.TaskRequest) (*pb.IdResponse, error) {
+	task, validationErr := newTaskFromMessage(-1, request)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return nil, statusError(validationErr)
+	}
+
+	taskId, err := s.service.Create(*task, accountId(ctx))
+	if err != nil {
+		log.Error(err)
+		return nil, statusError(err)
+	}
+
+	return &pb.IdResponse{Id: int64(taskId)}, nil
+}
+
+func (s taskServer) UpdateTask(ctx context.Context, request *pb.UpdateTaskRequest) (*pb.UndoResponse, error) {
+	if request.GetTask() == nil {
+		log.Error(msgs.MissingTask)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.Task, msgs.MissingTask)
+		return nil, statusError(todoerrors.NewValidationError(msgs.InvalidTaskDetails, invalidFields))
+	}
+	task, validationErr := newTaskFromMessage(int(request.GetId()), request.GetTask())
+	if validationErr != nil {
+		log.Error(validationErr)
+		return nil, statusError(validationErr)
+	}
+	task.SetVersion(int(request.GetVersion()))
+
+	undoToken, err := s.service.Update(*task, accountId(ctx))
+	if err != nil {
+		log.Error(err)
+		return nil, statusError(err)
+	}
+
+	return &pb.UndoResponse{UndoToken: undoToken}, nil
+}
+
+func (s taskServer) DeleteTask(ctx context.Context, request *pb.DeleteRequest) (*pb.UndoResponse, error) {
+	undoToken, err := s.service.Delete(int(request.GetId()), accountId(ctx), int(request.GetVersion()))
+	if err != nil {
+		log.Error(err)
+		return nil, statusError(err)
+	}
+
+	return &pb.UndoResponse{UndoToken: undoToken}, nil
+}
+
+func (s taskServer) GetTask(ctx context.Context, request *pb.IdRequest) (*pb.Task, error) {
+	task, err := s.service.FindById(int(request.GetId()), accountId(ctx))
+	if err != nil {
+		log.Error(err)
+		return nil, statusError(err)
+	}
+
+	return newTaskMessage(*task), nil
+}
+
+func (s taskServer) BatchTasks(ctx context.Context, request *pb.TaskBatchRequest) (*pb.TaskBatchResponse, error) {
+	batch, validationErr := newTaskBatch(request)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return nil, statusError(validationErr)
+	}
+
+	batchResult, err := s.service.Batch(*batch, accountId(ctx))
+	if err != nil {
+		log.Error(err)
+		return nil, statusError(err)
+	}
+
+	batchResponse := &pb.TaskBatchResponse{
+		Committed: batchResult.Committed(),
+		UndoToken: batchResult.UndoToken(),
+	}
+	for _, result := range batchResult.Results() {
+		resultMessage := &pb.TaskOperationResult{TaskId: int64(result.TaskId()), Code: int32(codes.OK)}
+		if result.Err() != nil {
+			resultMessage.Code = int32(statusCode(result.Err()))
+			resultMessage.Message = result.Err().Error()
+		}
+		batchResponse.Results = append(batchResponse.Results, resultMessage)
+	}
+	return batchResponse, nil
+}
+
+// ListTasks sends the tasks as their pages are read, stopping when the client cancels the call
+func (s taskServer) ListTasks(request *pb.ListTasksRequest, stream grpc.ServerStreamingServer[pb.Task]) error {
+	filter, validationErr := newTaskFilter(request.GetFilter())
+	if validationErr != nil {
+		log.Error(validationErr)
+		return statusError(validationErr)
+	}
+	pagination, validationErr := domain.NewValidatedKeysetPagination(taskPageSize, request.GetSort(),
+		request.GetOrder(), nil)
+	if validationErr != nil {
+		log.Error(validationErr)
+		return statusError(validationErr)
+	}
+
+	userId := accountId(stream.Context())
+	for {
+		page, err := s.service.FindPage(userId, *filter, *pagination)
+		if err != nil {
+			log.Error(err)
+			return statusError(err)
+		}
+		for _, task := range page.Tasks() {
+			if err = stream.Send(newTaskMessage(task)); err != nil {
+				log.Error(err)
+				return err
+			}
+		}
+		if page.Next() == nil {
+			return nil
+		}
+		pagination = domain.NewKeysetPagination(taskPageSize, pagination.SortField(), pagination.SortDirection(),
+			page.Next())
+	}
+}
+
+// newTaskBatch validates every operation up front, so that a malformed batch is rejected as a whole
+func newTaskBatch(request *pb.TaskBatchRequest) (*domain.TaskBatch, *todoerrors.Validation) {
+	invalidFields := todoerrors.InvalidFields{}
+	var operations []domain.TaskOperation
+	for index, operationMessage := range request.GetOperations() {
+		operation, description := newTaskOperation(operationMessage)
+		if description != "" {
+			invalidFields.AppendField(fmt.Sprintf(msgs.Operation, index), description)
+			continue
+		}
+		operations = append(operations, *operation)
+	}
+	if invalidFields.HasInvalidFields() {
+		return nil, todoerrors.NewValidationError(msgs.InvalidBatchDetails, invalidFields)
+	}
+
+	return domain.NewValidatedTaskBatch(operations, !request.GetBestEffort())
+}
+
+func newTaskOperation(operationMessage *pb.TaskOperation) (*domain.TaskOperation, string) {
+	taskId, version := int(operationMessage.GetId()), int(operationMessage.GetVersion())
+	switch operationMessage.GetKind() {
+	case pb.TaskOperation_KIND_CREATE, pb.TaskOperation_KIND_UPDATE:
+		kind := domain.TaskOperationCreate
+		if operationMessage.GetKind() == pb.TaskOperation_KIND_UPDATE {
+			if taskId <= 0 {
+				return nil, msgs.InvalidOperationId
+			}
+			kind = domain.TaskOperationUpdate
+		} else {
+			taskId = -1
+		}
+		if operationMessage.GetTask() == nil {
+			return nil, msgs.MissingTask
+		}
+		task, validationErr := newTaskFromMessage(taskId, operationMessage.GetTask())
+		if validationErr != nil {
+			return nil, validationErr.InvalidFields().Fields()[0].Description()
+		}
+		task.SetVersion(version)
+		return domain.NewTaskOperation(kind, taskId, version, task), ""
+	case pb.TaskOperation_KIND_DELETE:
+		if taskId <= 0 {
+			return nil, msgs.InvalidOperationId
+		}
+		return domain.NewTaskOperation(domain.TaskOperationDelete, taskId, version, nil), ""
+	default:
+		return nil, msgs.InvalidOperationKind
+	}
+}
@@ -0,0 +1,112 @@
+package automation
+
+import (
+	"slices"
+	"strings"
+	"time"
+	"todo/src/core/domain"
+	"todo/src/core/filterexpr"
+)
+
+// matchesTask evaluates the condition against the task the way the filters of the tasks do in the database, a nil
+// condition being met by every task
+func matchesTask(expression filterexpr.Expression, task domain.Task) bool {
+	switch node := expression.(type) {
+	case filterexpr.And:
+		return matchesTask(node.Left, task) && matchesTask(node.Right, task)
+	case filterexpr.Or:
+		return matchesTask(node.Left, task) || matchesTask(node.Right, task)
+	case filterexpr.Not:
+		return !matchesTask(node.Operand, task)
+	case filterexpr.Comparison:
+		return compareTask(node, task)
+	default:
+		return true
+	}
+}
+
+func compareTask(comparison filterexpr.Comparison, task domain.Task) bool {
+	negated := comparison.Operator == filterexpr.OperatorNotEqual
+
+	switch comparison.Field {
+	case filterexpr.FieldText:
+		return containsText(task.Description(), comparison.Value) != negated
+	case filterexpr.FieldTag:
+		return slices.Contains(task.Tags(), comparison.Value.(string)) != negated
+	case filterexpr.FieldFinished:
+		return (task.Finished() == comparison.Value.(bool)) != negated
+	case filterexpr.FieldCollection:
+		return compareCollection(comparison, task.Collection())
+	case filterexpr.FieldDue:
+		return compareDate(comparison, task.DueDate())
+	case filterexpr.FieldCreated:
+		createdAt := task.CreatedAt()
+		return compareDate(comparison, &createdAt)
+	default:
+		return false
+	}
+}
+
+// matchesCollection evaluates the condition against the collection, whose name is the text compared
+func matchesCollection(expression filterexpr.Expression, collection domain.Collection) bool {
+	switch node := expression.(type) {
+	case filterexpr.And:
+		return matchesCollection(node.Left, collection) && matchesCollection(node.Right, collection)
+	case filterexpr.Or:
+		return matchesCollection(node.Left, collection) || matchesCollection(node.Right, collection)
+	case filterexpr.Not:
+		return !matchesCollection(node.Operand, collection)
+	case filterexpr.Comparison:
+		if node.Field == filterexpr.FieldText {
+			return containsText(collection.Name(), node.Value) != (node.Operator == filterexpr.OperatorNotEqual)
+		}
+		return compareCollection(node, &collection)
+	default:
+		return true
+	}
+}
+
+func containsText(text string, value interface{}) bool {
+	return strings.Contains(strings.ToLower(text), strings.ToLower(value.(string)))
+}
+
+// compareCollection compares the ID or, case-insensitively, the name of the collection, which no comparison matches
+// when there is none
+func compareCollection(comparison filterexpr.Comparison, collection *domain.Collection) bool {
+	if collection == nil || collection.Id() == 0 {
+		return false
+	}
+
+	negated := comparison.Operator == filterexpr.OperatorNotEqual
+	if id, isId := comparison.Value.(int); isId {
+		return (collection.Id() == id) != negated
+	}
+	return strings.EqualFold(collection.Name(), comparison.Value.(string)) != negated
+}
+
+// compareDate matches a missing date only with "none", as NULL does in the database
+func compareDate(comparison filterexpr.Comparison, date *time.Time) bool {
+	negated := comparison.Operator == filterexpr.OperatorNotEqual
+	if comparison.Value == nil {
+		return (date == nil) != negated
+	}
+	if date == nil {
+		return false
+	}
+
+	value := *comparison.Value.(*time.Time)
+	switch comparison.Operator {
+	case filterexpr.OperatorEqual:
+		return date.Equal(value)
+	case filterexpr.OperatorNotEqual:
+		return !date.Equal(value)
+	case filterexpr.OperatorLess:
+		return date.Before(value)
+	case filterexpr.OperatorLessOrEqual:
+		return !date.After(value)
+	case filterexpr.OperatorGreater:
+		return date.After(value)
+	default:
+		return !date.Before(value)
+	}
+}
@@ -0,0 +1,263 @@
+package automation
+
+import (
+	"github.com/labstack/gommon/log"
+	"slices"
+	"strconv"
+	"time"
+	"todo/src/core/domain"
+	"todo/src/core/events"
+	eventbus "todo/src/core/interfaces/events"
+	"todo/src/core/interfaces/repository"
+	"todo/src/core/interfaces/services"
+)
+
+// Engine runs the automations of the accounts on the domain events, making their changes through the services.
+//
+// Each event emitted by a request starts a run, whose actions are made through services of their own, which emit
+// their events to the run rather than to the engine. The events caused by an automation are thus handled by the run
+// they come from, along the same call chain, whatever else the account does meanwhile. A run keeps two guards against
+// loops: an automation acts at most once on each task or collection during the run, and the events caused by too many
+// automations in a row are left out.
+type Engine struct {
+	repository     repository.IAutomation
+	bus            *events.Bus
+	newTasks       func(emitter eventbus.IEmitter) services.ITask
+	newCollections func(emitter eventbus.IEmitter) services.ICollection
+}
+
+// run holds the events of a run waiting to be handled, each with the number of automations that led to it, and the
+// services whose events are added to the run
+type run struct {
+	engine      *Engine
+	queue       []queuedEvent
+	depth       int
+	acted       map[string]bool
+	tasks       services.ITask
+	collections services.ICollection
+}
+
+type queuedEvent struct {
+	event domain.DomainEvent
+	depth int
+}
+
+// NewEngine takes the constructors of the services the runs act through, which emit the events to the emitter given
+func NewEngine(repository repository.IAutomation, bus *events.Bus,
+	newTasks func(emitter eventbus.IEmitter) services.ITask,
+	newCollections func(emitter eventbus.IEmitter) services.ICollection) *Engine {
+	return &Engine{
+		repository:     repository,
+		bus:            bus,
+		newTasks:       newTasks,
+		newCollections: newCollections,
+	}
+}
+
+// Subscribe hooks the engine to the events of the bus. The engine must be a sync subscriber, so that a run ends
+// before the service that started it returns.
+func (e *Engine) Subscribe() {
+	e.bus.Subscribe(events.AllEvents, events.Sync, e)
+}
+
+// Handle starts a run with the event, which is emitted by a service outside of any run
+func (e *Engine) Handle(event domain.DomainEvent) error {
+	if !triggers(event) {
+		return nil
+	}
+
+	current := &run{engine: e, queue: []queuedEvent{{event, 0}}, acted: map[string]bool{}}
+	current.tasks = e.newTasks(current)
+	current.collections = e.newCollections(current)
+	for len(current.queue) > 0 {
+		next := current.queue[0]
+		current.queue = current.queue[1:]
+		current.depth = next.depth
+		if next.depth > domain.MaxAutomationDepth {
+			log.Warnf("The %s event of the account %d was left out after %d automations in a row", next.event.Name(),
+				next.event.UserId(), next.depth)
+			continue
+		}
+		e.handle(next.event, current)
+	}
+	return nil
+}
+
+func triggers(event domain.DomainEvent) bool {
+	switch event.(type) {
+	case domain.TaskCreated, domain.TaskUpdated, domain.TaskFinished, domain.CollectionCreated:
+		return true
+	}
+	return false
+}
+
+// Emit adds the event caused by the automation being handled to the run, and hands it to the other subscribers
+func (r *run) Emit(event domain.DomainEvent) {
+	if triggers(event) {
+		r.queue = append(r.queue, queuedEvent{event, r.depth + 1})
+	}
+	r.engine.bus.EmitExcept(event, r.engine)
+}
+
+func (e *Engine) handle(event domain.DomainEvent, current *run) {
+	userId := event.UserId()
+	switch typedEvent := event.(type) {
+	case domain.TaskCreated:
+		e.runOnTask(userId, domain.EventTaskCreated, typedEvent.Task().Id(), current)
+	case domain.TaskUpdated:
+		e.runOnTask(userId, domain.EventTaskUpdated, typedEvent.Task().Id(), current)
+	case domain.TaskFinished:
+		e.runOnTask(userId, domain.EventTaskFinished, typedEvent.Task().Id(), current)
+		if collection := typedEvent.Task().Collection(); collection != nil && collection.Id() != 0 &&
+			e.completed(collection.Id(), userId, current) {
+			e.runOnCollection(userId, domain.AutomationCollectionCompleted, collection.Id(), current)
+		}
+	case domain.CollectionCreated:
+		e.runOnCollection(userId, domain.EventCollectionCreated, typedEvent.Collection().Id(), current)
+	}
+}
+
+// completed tells whether every task of the collection is finished
+func (e *Engine) completed(collectionId, userId int, current *run) bool {
+	tasksByCollection, err := current.collections.FindTasksOfCollections([]int{collectionId}, userId)
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+
+	tasks := tasksByCollection[collectionId]
+	return len(tasks) > 0 && !slices.ContainsFunc(tasks, func(task domain.Task) bool {
+		return !task.Finished()
+	})
+}
+
+func (e *Engine) runOnTask(userId int, trigger string, taskId int, current *run) {
+	for _, automation := range e.triggered(userId, trigger) {
+		key := strconv.Itoa(automation.Id()) + ":task:" + strconv.Itoa(taskId)
+		if current.acted[key] {
+			continue
+		}
+		task, err := current.tasks.FindById(taskId, userId)
+		if err != nil {
+			return
+		}
+		condition, validationErr := automation.ParsedCondition(time.Now())
+		if validationErr != nil || !matchesTask(condition, *task) {
+			continue
+		}
+
+		current.acted[key] = true
+		if err = e.actOnTask(automation, *task, userId, current.tasks); err != nil {
+			log.Errorf("The automation %d failed on the task %d: %v", automation.Id(), taskId, err)
+		}
+	}
+}
+
+func (e *Engine) runOnCollection(userId int, trigger string, collectionId int, current *run) {
+	for _, automation := range e.triggered(userId, trigger) {
+		key := strconv.Itoa(automation.Id()) + ":collection:" + strconv.Itoa(collectionId)
+		if current.acted[key] {
+			continue
+		}
+		collection, err := current.collections.FindById(collectionId, userId, *domain.NewExpansion())
+		if err != nil {
+			return
+		}
+		condition, validationErr := automation.ParsedCondition(time.Now())
+		if validationErr != nil || !matchesCollection(condition, *collection) {
+			continue
+		}
+
+		current.acted[key] = true
+		if err = e.actOnCollection(automation, *collection, userId, current); err != nil {
+			log.Errorf("The automation %d failed on the collection %d: %v", automation.Id(), collectionId, err)
+		}
+	}
+}
+
+func (e *Engine) triggered(userId int, trigger string) []domain.Automation {
+	automationList, err := e.repository.FindTriggered(userId, trigger)
+	if err != nil {
+		log.Error(err)
+		return nil
+	}
+
+	return automationList
+}
+
+// actOnTask makes the changes of the actions in a single update of the task, unless one of them deletes it
+func (e *Engine) actOnTask(automation domain.Automation, task domain.Task, userId int, tasks services.ITask) error {
+	collection := task.Collection()
+	tags := slices.Clone(task.Tags())
+	finished := task.Finished()
+
+	for _, action := range automation.Actions() {
+		switch action.Kind() {
+		case domain.ActionMoveToCollection:
+			collection = domain.NewCollection(action.CollectionId(), "")
+		case domain.ActionAddTag:
+			if !slices.Contains(tags, action.Tag()) {
+				tags = append(tags, action.Tag())
+			}
+		case domain.ActionRemoveTag:
+			tags = slices.DeleteFunc(tags, func(tag string) bool {
+				return tag == action.Tag()
+			})
+		case domain.ActionSetFinished:
+			finished = action.Finished()
+		case domain.ActionDeleteTask:
+			_, err := tasks.Delete(task.Id(), userId, task.Version())
+			return err
+		}
+	}
+
+	if finished == task.Finished() && slices.Equal(tags, task.Tags()) && sameCollection(collection, task.Collection()) {
+		return nil
+	}
+
+	changedTask := domain.NewTask(task.Id(), task.Description(), finished, collection)
+	changedTask.SetDueDate(task.DueDate())
+	changedTask.SetTags(tags)
+	changedTask.SetVersion(task.Version())
+	_, err := tasks.Update(*changedTask, userId)
+	return err
+}
+
+func sameCollection(collection, otherCollection *domain.Collection) bool {
+	if collection == nil || otherCollection == nil {
+		return collection == otherCollection
+	}
+	return collection.Id() == otherCollection.Id()
+}
+
+// actOnCollection renames the collection before archiving it, the archive changing its version
+func (e *Engine) actOnCollection(automation domain.Automation, collection domain.Collection, userId int,
+	current *run) error {
+	name := collection.Name()
+	archived := false
+	for _, action := range automation.Actions() {
+		switch action.Kind() {
+		case domain.ActionRenameCollection:
+			name = action.CollectionName(name)
+		case domain.ActionCreateTask:
+			task := domain.NewTask(0, action.Text(), false, domain.NewCollection(collection.Id(), ""))
+			if _, err := current.tasks.Create(*task, userId); err != nil {
+				return err
+			}
+		case domain.ActionArchiveCollection:
+			archived = true
+		}
+	}
+
+	if name != collection.Name() {
+		renamedCollection := domain.NewCollection(collection.Id(), name)
+		renamedCollection.SetVersion(collection.Version())
+		if err := current.collections.Update(*renamedCollection, userId); err != nil {
+			return err
+		}
+	}
+	if archived {
+		return current.collections.Archive(collection.Id(), userId)
+	}
+	return nil
+}
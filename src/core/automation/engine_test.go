@@ -0,0 +1,243 @@
+package automation
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"slices"
+	"testing"
+	"time"
+	"todo/src/core/domain"
+	"todo/src/core/events"
+	eventbus "todo/src/core/interfaces/events"
+	"todo/src/core/interfaces/services"
+)
+
+type memoryAutomations struct {
+	automations []domain.Automation
+}
+
+func (r *memoryAutomations) Create(domain.Automation, int) (int, error) { return 0, nil }
+func (r *memoryAutomations) Update(domain.Automation, int) error        { return nil }
+func (r *memoryAutomations) Delete(int, int) error                      { return nil }
+func (r *memoryAutomations) FindById(int, int) (*domain.Automation, error) {
+	return nil, nil
+}
+func (r *memoryAutomations) FindAll(int) ([]domain.Automation, error) { return r.automations, nil }
+
+func (r *memoryAutomations) FindTriggered(_ int, trigger string) ([]domain.Automation, error) {
+	var automationList []domain.Automation
+	for _, automation := range r.automations {
+		if automation.Trigger() == trigger && automation.Active() {
+			automationList = append(automationList, automation)
+		}
+	}
+	return automationList, nil
+}
+
+// memoryStore keeps the tasks and collections of the memory services
+type memoryStore struct {
+	tasks       map[int]domain.Task
+	collections map[int]domain.Collection
+	updates     int
+}
+
+// memoryTasks emits the events of the tasks to its emitter the way the services do
+type memoryTasks struct {
+	*memoryStore
+	emitter eventbus.IEmitter
+}
+
+func (s *memoryTasks) Create(task domain.Task, userId int) (int, error) {
+	id := len(s.tasks) + 1
+	stored := domain.NewTask(id, task.Description(), task.Finished(), task.Collection())
+	stored.SetTags(task.Tags())
+	stored.SetVersion(1)
+	s.tasks[id] = *stored
+	s.emitter.Emit(*domain.NewTaskCreated(userId, *stored))
+	return id, nil
+}
+
+func (s *memoryTasks) Update(task domain.Task, userId int) (string, error) {
+	previousTask, found := s.tasks[task.Id()]
+	if !found {
+		return "", errors.New("not found")
+	}
+	s.updates++
+	task.SetVersion(previousTask.Version() + 1)
+	s.tasks[task.Id()] = task
+	s.emitter.Emit(*domain.NewTaskUpdated(userId, task, previousTask))
+	if task.Finished() && !previousTask.Finished() {
+		s.emitter.Emit(*domain.NewTaskFinished(userId, task))
+	}
+	return "", nil
+}
+
+func (s *memoryTasks) Delete(taskId, _, _ int) (string, error) {
+	delete(s.tasks, taskId)
+	return "", nil
+}
+
+func (s *memoryTasks) FindById(taskId, _ int) (*domain.Task, error) {
+	task, found := s.tasks[taskId]
+	if !found {
+		return nil, errors.New("not found")
+	}
+	return &task, nil
+}
+
+func (s *memoryTasks) Batch(domain.TaskBatch, int) (*domain.TaskBatchResult, error) { return nil, nil }
+func (s *memoryTasks) FindAll(int, domain.TaskFilter, domain.Pagination) ([]domain.Task, int, error) {
+	return nil, 0, nil
+}
+func (s *memoryTasks) FindByCollectionId(int, int, domain.TaskFilter,
+	domain.Pagination) ([]domain.Task, int, error) {
+	return nil, 0, nil
+}
+func (s *memoryTasks) FindPage(int, domain.TaskFilter, domain.KeysetPagination) (*domain.TaskPage, error) {
+	return nil, nil
+}
+
+// memoryCollections changes the collections kept in the store of the tasks
+type memoryCollections struct {
+	*memoryStore
+	emitter eventbus.IEmitter
+}
+
+func (s memoryCollections) Create(domain.Collection, int) (int, error) { return 0, nil }
+func (s memoryCollections) Update(collection domain.Collection, _ int) error {
+	s.collections[collection.Id()] = collection
+	return nil
+}
+func (s memoryCollections) Delete(int, int, int) (string, error) { return "", nil }
+func (s memoryCollections) Archive(collectionId, userId int) error {
+	collection := s.collections[collectionId]
+	archivedAt := time.Now()
+	collection.SetArchivedAt(&archivedAt)
+	s.collections[collectionId] = collection
+	s.emitter.Emit(*domain.NewCollectionUpdated(userId, collection))
+	return nil
+}
+func (s memoryCollections) FindById(collectionId, _ int, _ domain.Expansion) (*domain.Collection, error) {
+	collection, found := s.collections[collectionId]
+	if !found {
+		return nil, errors.New("not found")
+	}
+	return &collection, nil
+}
+func (s memoryCollections) FindByIds([]int, int) ([]domain.Collection, error) { return nil, nil }
+func (s memoryCollections) FindTasksOfCollections(collectionIds []int, _ int) (map[int][]domain.Task, error) {
+	tasksByCollection := map[int][]domain.Task{}
+	for _, task := range s.tasks {
+		if task.Collection() != nil && slices.Contains(collectionIds, task.Collection().Id()) {
+			tasksByCollection[task.Collection().Id()] = append(tasksByCollection[task.Collection().Id()], task)
+		}
+	}
+	return tasksByCollection, nil
+}
+func (s memoryCollections) FindAll(int, domain.CollectionFilter,
+	domain.Pagination) ([]domain.Collection, int, error) {
+	return nil, 0, nil
+}
+
+// newTestEngine returns the services of the requests, which emit their events to the bus of the engine
+func newTestEngine(automations ...domain.Automation) (*memoryTasks, memoryCollections, *events.Bus) {
+	bus := events.NewBus()
+	store := &memoryStore{tasks: map[int]domain.Task{}, collections: map[int]domain.Collection{}}
+	NewEngine(&memoryAutomations{automations}, bus,
+		func(emitter eventbus.IEmitter) services.ITask {
+			return &memoryTasks{store, emitter}
+		},
+		func(emitter eventbus.IEmitter) services.ICollection {
+			return memoryCollections{store, emitter}
+		}).Subscribe()
+	return &memoryTasks{store, bus}, memoryCollections{store, bus}, bus
+}
+
+func newAutomation(id int, trigger, condition string, actions ...domain.AutomationAction) domain.Automation {
+	return *domain.NewAutomation(id, "Automation", trigger, condition, actions, true)
+}
+
+func TestEngine(t *testing.T) {
+	t.Run("should move the created task that meets the condition", func(t *testing.T) {
+		tasks, _, _ := newTestEngine(newAutomation(1, domain.EventTaskCreated, "tag:bug",
+			*domain.NewAutomationAction(domain.ActionMoveToCollection, 7, "", false, ""),
+			*domain.NewAutomationAction(domain.ActionAddTag, 0, "triage", false, "")))
+		tasks.collections[7] = *domain.NewCollection(7, "Triage")
+		bug := domain.NewTask(0, "Crash on start", false, nil)
+		bug.SetTags([]string{"bug"})
+
+		bugId, _ := tasks.Create(*bug, 1)
+		choreId, _ := tasks.Create(*domain.NewTask(0, "Buy milk", false, nil), 1)
+
+		assert.Equal(t, 7, tasks.tasks[bugId].Collection().Id())
+		assert.Equal(t, []string{"bug", "triage"}, tasks.tasks[bugId].Tags())
+		assert.Nil(t, tasks.tasks[choreId].Collection())
+		assert.Equal(t, 1, tasks.updates)
+	})
+
+	t.Run("should stop the automations that trigger each other", func(t *testing.T) {
+		tasks, _, _ := newTestEngine(
+			newAutomation(1, domain.EventTaskUpdated, "tag:ping",
+				*domain.NewAutomationAction(domain.ActionRemoveTag, 0, "ping", false, ""),
+				*domain.NewAutomationAction(domain.ActionAddTag, 0, "pong", false, "")),
+			newAutomation(2, domain.EventTaskUpdated, "tag:pong",
+				*domain.NewAutomationAction(domain.ActionRemoveTag, 0, "pong", false, ""),
+				*domain.NewAutomationAction(domain.ActionAddTag, 0, "ping", false, "")))
+		taskId, _ := tasks.Create(*domain.NewTask(0, "Loop", false, nil), 1)
+		task := tasks.tasks[taskId]
+		task.SetTags([]string{"ping"})
+
+		_, _ = tasks.Update(task, 1)
+
+		assert.Equal(t, 3, tasks.updates)
+		assert.Equal(t, []string{"ping"}, tasks.tasks[taskId].Tags())
+	})
+
+	t.Run("should rename the collection whose last unfinished task is finished", func(t *testing.T) {
+		tasks, collections, _ := newTestEngine(newAutomation(1, domain.AutomationCollectionCompleted, "",
+			*domain.NewAutomationAction(domain.ActionRenameCollection, 0, "", false, "[Done] {name}")))
+		tasks.collections[4] = *domain.NewCollection(4, "Trip")
+		firstId, _ := tasks.Create(*domain.NewTask(0, "Book hotel", false, domain.NewCollection(4, "")), 1)
+		secondId, _ := tasks.Create(*domain.NewTask(0, "Pack", false, domain.NewCollection(4, "")), 1)
+
+		first := tasks.tasks[firstId]
+		_, _ = tasks.Update(*domain.NewTask(firstId, first.Description(), true, first.Collection()), 1)
+		collection, _ := collections.FindById(4, 1, *domain.NewExpansion())
+		assert.Equal(t, "Trip", collection.Name())
+
+		second := tasks.tasks[secondId]
+		_, _ = tasks.Update(*domain.NewTask(secondId, second.Description(), true, second.Collection()), 1)
+		collection, _ = collections.FindById(4, 1, *domain.NewExpansion())
+		assert.Equal(t, "[Done] Trip", collection.Name())
+	})
+
+	t.Run("should archive the collection whose tasks are all finished", func(t *testing.T) {
+		tasks, collections, _ := newTestEngine(newAutomation(1, domain.AutomationCollectionCompleted, "",
+			*domain.NewAutomationAction(domain.ActionArchiveCollection, 0, "", false, "")))
+		tasks.collections[4] = *domain.NewCollection(4, "Trip")
+		taskId, _ := tasks.Create(*domain.NewTask(0, "Pack", false, domain.NewCollection(4, "")), 1)
+
+		task := tasks.tasks[taskId]
+		_, _ = tasks.Update(*domain.NewTask(taskId, task.Description(), true, task.Collection()), 1)
+
+		collection, _ := collections.FindById(4, 1, *domain.NewExpansion())
+		assert.NotNil(t, collection.ArchivedAt())
+		assert.Equal(t, "Trip", collection.Name())
+	})
+
+	t.Run("should hand the events of its actions to the other subscribers once", func(t *testing.T) {
+		tasks, _, bus := newTestEngine(newAutomation(1, domain.EventTaskCreated, "",
+			*domain.NewAutomationAction(domain.ActionAddTag, 0, "inbox", false, "")))
+		var received []string
+		bus.Subscribe(events.AllEvents, events.Sync, events.SubscriberFunc(func(event domain.DomainEvent) error {
+			received = append(received, event.Name())
+			return nil
+		}))
+
+		taskId, _ := tasks.Create(*domain.NewTask(0, "Buy milk", false, nil), 1)
+
+		assert.ElementsMatch(t, []string{domain.EventTaskCreated, domain.EventTaskUpdated}, received)
+		assert.Equal(t, []string{"inbox"}, tasks.tasks[taskId].Tags())
+		assert.Equal(t, 1, tasks.updates)
+	})
+}
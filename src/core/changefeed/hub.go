@@ -0,0 +1,182 @@
+package changefeed
+
+import (
+	"context"
+	"github.com/labstack/gommon/log"
+	"sync"
+	"time"
+	"todo/src/core/domain"
+	interfaces "todo/src/core/interfaces/changefeed"
+	"todo/src/core/interfaces/repository"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+const (
+	// MaxMissedEvents is the number of missed events above which a client must read the data of the user again
+	MaxMissedEvents = 1000
+	subscriberSize  = 64
+	batchSize       = 100
+	pollInterval    = 500 * time.Millisecond
+	// gapTimeout is how long an ID skipped in the outbox is looked for, its transaction committing after the ones of
+	// the later IDs, before it is taken as rolled back
+	gapTimeout = time.Minute
+	maxGaps    = 1000
+)
+
+// Hub tails the outbox, where every instance of the API writes the change events along with the changes, and
+// delivers the events to the subscriptions of their user held by this instance. The missed events are read from the
+// outbox as well, so that a client can resume from the last event it has received as long as the event is kept.
+type Hub struct {
+	repository    repository.IChangeFeed
+	mutex         sync.Mutex
+	started       bool
+	lastId        int64
+	gaps          map[int64]time.Time
+	subscriptions map[int]map[*Subscription]struct{}
+}
+
+func NewHub(repository repository.IChangeFeed) *Hub {
+	return &Hub{
+		repository:    repository,
+		gaps:          map[int64]time.Time{},
+		subscriptions: map[int]map[*Subscription]struct{}{},
+	}
+}
+
+// Run delivers the events as they are written until the context is done
+func (h *Hub) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			delivered, err := h.Poll()
+			if err != nil || delivered < batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll delivers the events written since the previous poll, starting after the last event written at the first
+// one, and returns the number of events read
+func (h *Hub) Poll() (int, error) {
+	if !h.started {
+		lastId, err := h.repository.LastEventId()
+		if err != nil {
+			return 0, err
+		}
+		h.lastId = lastId
+		h.started = true
+		return 0, nil
+	}
+
+	now := time.Now()
+	gapIds := make([]int64, 0, len(h.gaps))
+	for id, skippedAt := range h.gaps {
+		if now.Sub(skippedAt) > gapTimeout {
+			delete(h.gaps, id)
+			continue
+		}
+		gapIds = append(gapIds, id)
+	}
+
+	eventList, err := h.repository.FindEvents(h.lastId, gapIds, batchSize)
+	if err != nil {
+		return 0, err
+	}
+	for _, event := range eventList {
+		if _, skipped := h.gaps[event.Id()]; skipped {
+			delete(h.gaps, event.Id())
+		} else if event.Id() > h.lastId {
+			for id := max(h.lastId+1, event.Id()-maxGaps); id < event.Id(); id++ {
+				h.gaps[id] = now
+			}
+			h.lastId = event.Id()
+		}
+		h.publish(event)
+	}
+
+	return len(eventList), nil
+}
+
+// publish delivers the event without waiting, ending the subscriptions that are too far behind
+func (h *Hub) publish(event domain.ChangeEvent) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	for subscription := range h.subscriptions[event.UserId()] {
+		select {
+		case subscription.events <- event:
+		default:
+			h.remove(subscription)
+		}
+	}
+}
+
+// Subscribe registers the subscription before reading the missed events, so that none is lost in between, although
+// an event may then be received twice. It fails with domain.ErrChangeFeedResync, along with the subscription, when the
+// last event received is no longer kept or too many events have been missed.
+func (h *Hub) Subscribe(userId int, lastEventId int64) ([]domain.ChangeEvent, interfaces.ISubscription, error) {
+	h.mutex.Lock()
+	subscription := &Subscription{hub: h, userId: userId, events: make(chan domain.ChangeEvent, subscriberSize)}
+	if h.subscriptions[userId] == nil {
+		h.subscriptions[userId] = map[*Subscription]struct{}{}
+	}
+	h.subscriptions[userId][subscription] = struct{}{}
+	h.mutex.Unlock()
+
+	if lastEventId == 0 {
+		return nil, subscription, nil
+	}
+	missedEvents, found, err := h.repository.FindMissedEvents(userId, lastEventId, MaxMissedEvents+1)
+	if err != nil {
+		log.Error(err)
+		subscription.Close()
+		return nil, nil, todoerrors.ConvertRepositoryErrorToServiceError(err, h.repository.FindMissedEvents)
+	}
+	if !found || len(missedEvents) > MaxMissedEvents {
+		return nil, subscription, domain.ErrChangeFeedResync
+	}
+
+	return missedEvents, subscription, nil
+}
+
+func (h *Hub) remove(subscription *Subscription) {
+	subscriptions, ok := h.subscriptions[subscription.userId]
+	if !ok {
+		return
+	}
+	if _, ok = subscriptions[subscription]; !ok {
+		return
+	}
+
+	delete(subscriptions, subscription)
+	if len(subscriptions) == 0 {
+		delete(h.subscriptions, subscription.userId)
+	}
+	close(subscription.events)
+}
+
+type Subscription struct {
+	hub    *Hub
+	userId int
+	events chan domain.ChangeEvent
+}
+
+func (s *Subscription) Events() <-chan domain.ChangeEvent {
+	return s.events
+}
+
+func (s *Subscription) Close() {
+	s.hub.mutex.Lock()
+	defer s.hub.mutex.Unlock()
+
+	s.hub.remove(s)
+}
@@ -0,0 +1,156 @@
+package changefeed
+
+import (
+	"github.com/stretchr/testify/assert"
+	"slices"
+	"testing"
+	"todo/src/core/domain"
+)
+
+// memoryOutbox keeps the committed events the way the outbox of the instances does
+type memoryOutbox struct {
+	events []domain.ChangeEvent
+}
+
+func (o *memoryOutbox) write(id int64, userId int, action string, entityId int) {
+	event := domain.NewChangeEvent(userId, domain.ChangeEntityTask, action, entityId, 1)
+	event.SetId(id)
+	o.events = append(o.events, *event)
+	slices.SortFunc(o.events, func(event, otherEvent domain.ChangeEvent) int {
+		return int(event.Id() - otherEvent.Id())
+	})
+}
+
+func (o *memoryOutbox) LastEventId() (int64, error) {
+	if len(o.events) == 0 {
+		return 0, nil
+	}
+	return o.events[len(o.events)-1].Id(), nil
+}
+
+func (o *memoryOutbox) FindEvents(afterId int64, ids []int64, limit int) ([]domain.ChangeEvent, error) {
+	var eventList []domain.ChangeEvent
+	for _, event := range o.events {
+		if len(eventList) < limit && (event.Id() > afterId || slices.Contains(ids, event.Id())) {
+			eventList = append(eventList, event)
+		}
+	}
+	return eventList, nil
+}
+
+func (o *memoryOutbox) FindMissedEvents(userId int, lastEventId int64, limit int) ([]domain.ChangeEvent, bool,
+	error) {
+	found := slices.ContainsFunc(o.events, func(event domain.ChangeEvent) bool {
+		return event.Id() == lastEventId && event.UserId() == userId
+	})
+	if !found {
+		return nil, false, nil
+	}
+
+	var eventList []domain.ChangeEvent
+	for _, event := range o.events {
+		if len(eventList) < limit && event.UserId() == userId && event.Id() > lastEventId {
+			eventList = append(eventList, event)
+		}
+	}
+	return eventList, true, nil
+}
+
+func receivedIds(subscription *Subscription) []int64 {
+	var ids []int64
+	for len(subscription.events) > 0 {
+		ids = append(ids, (<-subscription.events).Id())
+	}
+	return ids
+}
+
+func TestHub(t *testing.T) {
+	t.Run("should deliver the events written by any instance only to the subscriptions of their user",
+		func(t *testing.T) {
+			outbox := &memoryOutbox{}
+			outbox.write(1, 1, domain.ChangeActionCreated, 4)
+			hub := NewHub(outbox)
+			_, _ = hub.Poll()
+			_, subscription, _ := hub.Subscribe(1, 0)
+			_, otherSubscription, _ := hub.Subscribe(2, 0)
+			defer subscription.Close()
+			defer otherSubscription.Close()
+
+			outbox.write(2, 1, domain.ChangeActionCreated, 5)
+			delivered, err := hub.Poll()
+
+			assert.NoError(t, err)
+			assert.Equal(t, 1, delivered)
+			event := <-subscription.Events()
+			assert.Equal(t, int64(2), event.Id())
+			assert.Equal(t, "task.created", event.Type())
+			assert.Len(t, otherSubscription.Events(), 0)
+		})
+
+	t.Run("should deliver the event whose transaction has committed after the one of a later event",
+		func(t *testing.T) {
+			outbox := &memoryOutbox{}
+			hub := NewHub(outbox)
+			_, _ = hub.Poll()
+			_, subscription, _ := hub.Subscribe(1, 0)
+			defer subscription.Close()
+
+			outbox.write(1, 1, domain.ChangeActionCreated, 5)
+			outbox.write(3, 1, domain.ChangeActionCreated, 7)
+			_, _ = hub.Poll()
+			outbox.write(2, 1, domain.ChangeActionCreated, 6)
+			_, _ = hub.Poll()
+			_, _ = hub.Poll()
+
+			assert.Equal(t, []int64{1, 3, 2}, receivedIds(subscription.(*Subscription)))
+		})
+
+	t.Run("should replay the events of the user written after the last event received", func(t *testing.T) {
+		outbox := &memoryOutbox{}
+		outbox.write(1, 1, domain.ChangeActionCreated, 5)
+		outbox.write(2, 1, domain.ChangeActionUpdated, 5)
+		outbox.write(3, 2, domain.ChangeActionCreated, 6)
+		outbox.write(4, 1, domain.ChangeActionDeleted, 5)
+		hub := NewHub(outbox)
+
+		missedEvents, subscription, err := hub.Subscribe(1, 1)
+		defer subscription.Close()
+
+		assert.NoError(t, err)
+		assert.Len(t, missedEvents, 2)
+		assert.Equal(t, "task.updated", missedEvents[0].Type())
+		assert.Equal(t, "task.deleted", missedEvents[1].Type())
+	})
+
+	t.Run("should ask for a resync when the last event received is no longer kept", func(t *testing.T) {
+		outbox := &memoryOutbox{}
+		outbox.write(8, 1, domain.ChangeActionCreated, 5)
+		hub := NewHub(outbox)
+
+		missedEvents, subscription, err := hub.Subscribe(1, 3)
+		defer subscription.Close()
+
+		assert.ErrorIs(t, err, domain.ErrChangeFeedResync)
+		assert.Empty(t, missedEvents)
+		assert.NotNil(t, subscription)
+	})
+
+	t.Run("should end the subscription that falls behind", func(t *testing.T) {
+		outbox := &memoryOutbox{}
+		hub := NewHub(outbox)
+		_, _ = hub.Poll()
+		_, subscription, _ := hub.Subscribe(1, 0)
+
+		for taskId := 1; taskId <= subscriberSize+1; taskId++ {
+			outbox.write(int64(taskId), 1, domain.ChangeActionCreated, taskId)
+		}
+		_, _ = hub.Poll()
+
+		received := 0
+		for range subscription.Events() {
+			received++
+		}
+		subscription.Close()
+		assert.Equal(t, subscriberSize, received)
+	})
+}
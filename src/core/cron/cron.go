@@ -0,0 +1,181 @@
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSearch bounds the search for the next time, as some expressions never match, such as the 30th of February
+const maxSearch = 5 * 366 * 24 * time.Hour
+
+// descriptors are the shorthands accepted in place of the five fields
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+type bounds struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteBounds     = bounds{"minute", 0, 59, nil}
+	hourBounds       = bounds{"hour", 0, 23, nil}
+	dayOfMonthBounds = bounds{"day of month", 1, 31, nil}
+	monthBounds      = bounds{"month", 1, 12, map[string]int{"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5,
+		"jun": 6, "jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12}}
+	// dayOfWeekBounds take 7 as Sunday too
+	dayOfWeekBounds = bounds{"day of week", 0, 7, map[string]int{"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4,
+		"fri": 5, "sat": 6}}
+)
+
+// Schedule is a cron expression of five fields, the minute, hour, day of month, month and day of week, read in UTC.
+// Each field is *, a value, a range such as 1-5 or a list of them, any of which may have a step such as */15. When
+// both days are restricted, a time matches either of them, as in the classic cron.
+type Schedule struct {
+	minute     uint64
+	hour       uint64
+	dayOfMonth uint64
+	month      uint64
+	dayOfWeek  uint64
+	// anyDay is set when one of the days is *, so that the other one alone chooses the days
+	anyDay bool
+}
+
+func Parse(spec string) (*Schedule, error) {
+	formattedSpec := strings.ToLower(strings.TrimSpace(spec))
+	if expanded, found := descriptors[formattedSpec]; found {
+		formattedSpec = expanded
+	}
+	fields := strings.Fields(formattedSpec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("the cron expression %q must have 5 fields", spec)
+	}
+
+	schedule := &Schedule{}
+	var anyDayOfMonth, anyDayOfWeek bool
+	var err error
+	if schedule.minute, _, err = parseField(fields[0], minuteBounds); err != nil {
+		return nil, err
+	}
+	if schedule.hour, _, err = parseField(fields[1], hourBounds); err != nil {
+		return nil, err
+	}
+	if schedule.dayOfMonth, anyDayOfMonth, err = parseField(fields[2], dayOfMonthBounds); err != nil {
+		return nil, err
+	}
+	if schedule.month, _, err = parseField(fields[3], monthBounds); err != nil {
+		return nil, err
+	}
+	if schedule.dayOfWeek, anyDayOfWeek, err = parseField(fields[4], dayOfWeekBounds); err != nil {
+		return nil, err
+	}
+	if schedule.dayOfWeek&(1<<7) != 0 {
+		schedule.dayOfWeek |= 1
+	}
+	schedule.anyDay = anyDayOfMonth || anyDayOfWeek
+
+	return schedule, nil
+}
+
+// parseField returns the values of the field as a set of bits, and whether the field is *
+func parseField(field string, fieldBounds bounds) (uint64, bool, error) {
+	var values uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if index := strings.Index(part, "/"); index >= 0 {
+			parsedStep, err := strconv.Atoi(part[index+1:])
+			if err != nil || parsedStep <= 0 {
+				return 0, false, fmt.Errorf("the step of the %s in %q is invalid", fieldBounds.name, part)
+			}
+			rangePart, step = part[:index], parsedStep
+		}
+
+		var start, end int
+		switch {
+		case rangePart == "*":
+			start, end = fieldBounds.min, fieldBounds.max
+		case strings.Contains(rangePart, "-"):
+			limits := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if start, err = parseValue(limits[0], fieldBounds); err != nil {
+				return 0, false, err
+			}
+			if end, err = parseValue(limits[1], fieldBounds); err != nil {
+				return 0, false, err
+			}
+		default:
+			var err error
+			if start, err = parseValue(rangePart, fieldBounds); err != nil {
+				return 0, false, err
+			}
+			end = start
+			if step > 1 {
+				end = fieldBounds.max
+			}
+		}
+		if start > end {
+			return 0, false, fmt.Errorf("the range of the %s in %q is reversed", fieldBounds.name, part)
+		}
+
+		for value := start; value <= end; value += step {
+			values |= 1 << value
+		}
+	}
+
+	return values, field == "*", nil
+}
+
+func parseValue(value string, fieldBounds bounds) (int, error) {
+	if named, found := fieldBounds.names[value]; found {
+		return named, nil
+	}
+	parsedValue, err := strconv.Atoi(value)
+	if err != nil || parsedValue < fieldBounds.min || parsedValue > fieldBounds.max {
+		return 0, fmt.Errorf("the %s %q is invalid, it must be between %d and %d", fieldBounds.name, value,
+			fieldBounds.min, fieldBounds.max)
+	}
+	return parsedValue, nil
+}
+
+// Next is the first time matching the schedule after the given time, at the start of its minute, or the zero time
+// when the schedule never matches
+func (s Schedule) Next(after time.Time) time.Time {
+	next := after.UTC().Truncate(time.Minute).Add(time.Minute)
+	limit := next.Add(maxSearch)
+
+	for next.Before(limit) {
+		switch {
+		case s.month&(1<<int(next.Month())) == 0:
+			next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		case !s.matchesDay(next):
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, time.UTC)
+		case s.hour&(1<<next.Hour()) == 0:
+			next = next.Truncate(time.Hour).Add(time.Hour)
+		case s.minute&(1<<next.Minute()) == 0:
+			next = next.Add(time.Minute)
+		default:
+			return next
+		}
+	}
+
+	return time.Time{}
+}
+
+func (s Schedule) matchesDay(date time.Time) bool {
+	dayOfMonth := s.dayOfMonth&(1<<date.Day()) != 0
+	dayOfWeek := s.dayOfWeek&(1<<int(date.Weekday())) != 0
+	if s.anyDay {
+		return dayOfMonth && dayOfWeek
+	}
+	return dayOfMonth || dayOfWeek
+}
@@ -0,0 +1,58 @@
+package cron
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSchedule_Next(t *testing.T) {
+	// 2024-01-31 was a Wednesday
+	after := time.Date(2024, 1, 31, 10, 17, 42, 0, time.UTC)
+	cases := []struct {
+		name     string
+		spec     string
+		expected time.Time
+	}{
+		{"should run at the next minute", "* * * * *", time.Date(2024, 1, 31, 10, 18, 0, 0, time.UTC)},
+		{"should step through the minutes", "*/15 * * * *", time.Date(2024, 1, 31, 10, 30, 0, 0, time.UTC)},
+		{"should read the descriptors", "@hourly", time.Date(2024, 1, 31, 11, 0, 0, 0, time.UTC)},
+		{"should move to the next day", "30 9 * * *", time.Date(2024, 2, 1, 9, 30, 0, 0, time.UTC)},
+		{"should read lists and ranges", "0 8-9,18 * * *", time.Date(2024, 1, 31, 18, 0, 0, 0, time.UTC)},
+		{"should read the names of the days", "0 9 * * mon-fri", time.Date(2024, 2, 1, 9, 0, 0, 0, time.UTC)},
+		{"should take 7 as Sunday", "0 0 * * 7", time.Date(2024, 2, 4, 0, 0, 0, 0, time.UTC)},
+		{"should skip the months without the day", "0 0 31 * *", time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)},
+		{"should match either day when both are restricted", "0 0 15 * fri",
+			time.Date(2024, 2, 2, 0, 0, 0, 0, time.UTC)},
+		{"should reach a leap day", "0 0 29 feb *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"should return the zero time when the schedule never matches", "0 0 30 2 *", time.Time{}},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			schedule, err := Parse(testCase.spec)
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, schedule.Next(after))
+		})
+	}
+
+	t.Run("should read the time in UTC", func(t *testing.T) {
+		schedule, _ := Parse("0 12 * * *")
+		local := time.Date(2024, 1, 31, 13, 0, 0, 0, time.FixedZone("UTC+2", 2*60*60))
+
+		assert.Equal(t, time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC), schedule.Next(local))
+	})
+}
+
+func TestParse(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *",
+		"* * * * 8", "*/0 * * * *", "5-1 * * * *", "a * * * *", "@every 5m"} {
+		t.Run("should reject "+spec, func(t *testing.T) {
+			schedule, err := Parse(spec)
+
+			assert.Nil(t, schedule)
+			assert.Error(t, err)
+		})
+	}
+}
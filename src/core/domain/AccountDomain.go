@@ -11,6 +11,12 @@ import (
 	"todo/src/core/projecterrors/todoerrors"
 )
 
+const (
+	// ChangeFeedTokenLifetime is short, as the change feed token is sent in the URL, which may be logged
+	ChangeFeedTokenLifetime = time.Minute
+	changeFeedTokenScope    = "changefeed"
+)
+
 type Account struct {
 	id       int
 	name     string
@@ -55,6 +61,52 @@ func NewAccount(id int, name, email, password, token string) *Account {
 	}
 }
 
+// NewAccountFromToken reads the ID and email of the account that has been issued the access token, failing when the
+// token has not been signed with the secret of the server or has expired
+func NewAccountFromToken(token string) (*Account, error) {
+	return newAccountFromToken(token, "")
+}
+
+// NewAccountFromChangeFeedToken reads the ID of the account that has been issued the change feed token, which only
+// opens the change feed
+func NewAccountFromChangeFeedToken(token string) (*Account, error) {
+	return newAccountFromToken(token, changeFeedTokenScope)
+}
+
+// newAccountFromToken fails as well when the token has not been issued for the scope, the access tokens having none
+func newAccountFromToken(token, scope string) (*Account, error) {
+	secretKey := os.Getenv("SERVER_SECRET")
+	parsedToken, err := jwt.Parse(
+		token,
+		func(token *jwt.Token) (interface{}, error) {
+			return []byte(secretKey), nil
+		},
+	)
+	if err != nil {
+		log.Error(err)
+		return nil, todoerrors.NewUnauthorizedError()
+	}
+	if !parsedToken.Valid {
+		return nil, todoerrors.NewUnauthorizedError()
+	}
+
+	claims := parsedToken.Claims.(jwt.MapClaims)
+	id, ok := claims["id"].(float64)
+	if !ok || id <= 0 {
+		return nil, todoerrors.NewUnauthorizedError()
+	}
+	if tokenScope, _ := claims["scope"].(string); tokenScope != scope {
+		return nil, todoerrors.NewUnauthorizedError()
+	}
+	email, _ := claims["email"].(string)
+
+	return &Account{
+		id:    int(id),
+		email: email,
+		token: token,
+	}, nil
+}
+
 func (d Account) Id() int {
 	return d.id
 }
@@ -95,6 +147,28 @@ func (d *Account) GenerateToken() (string, error) {
 	return signedToken, nil
 }
 
+// GenerateChangeFeedToken issues a short-lived token that only opens the change feed of the account, for the clients
+// that can't set the Authorization header, such as EventSource and WebSocket in the browsers
+func (d Account) GenerateChangeFeedToken() (string, time.Time, error) {
+	expiresAt := time.Now().Add(ChangeFeedTokenLifetime)
+	claims := jwt.MapClaims{
+		"exp":   expiresAt.Unix(),
+		"iat":   time.Now().Unix(),
+		"iss":   "To Do List - REST API",
+		"id":    d.id,
+		"scope": changeFeedTokenScope,
+	}
+	secretKey := os.Getenv("SERVER_SECRET")
+
+	signedToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secretKey))
+	if err != nil {
+		log.Error(err)
+		return "", time.Time{}, err
+	}
+
+	return signedToken, expiresAt, nil
+}
+
 func (d Account) buildClaims() *jwt.MapClaims {
 	now := time.Now().Unix()
 	exp := time.Now().Add(time.Minute * 60).Unix()
@@ -0,0 +1,260 @@
+package domain
+
+import (
+	"fmt"
+	"github.com/labstack/gommon/log"
+	"slices"
+	"strings"
+	"time"
+	"todo/src/core/domain/msgs"
+	"todo/src/core/filterexpr"
+	"todo/src/core/projecterrors/todoerrors"
+	"unicode/utf8"
+)
+
+const (
+	// AutomationCollectionCompleted follows the update that finishes the last unfinished task of a collection
+	AutomationCollectionCompleted = "collection.completed"
+	MaxAutomationNameLength       = 50
+	MaxAutomationActions          = 10
+	// MaxAutomationDepth is the number of times the changes made by the automations can trigger other automations
+	MaxAutomationDepth = 3
+
+	ActionMoveToCollection = "move_to_collection"
+	ActionAddTag           = "add_tag"
+	ActionRemoveTag        = "remove_tag"
+	ActionSetFinished      = "set_finished"
+	ActionDeleteTask       = "delete_task"
+	ActionRenameCollection = "rename_collection"
+	ActionCreateTask       = "create_task"
+	// ActionArchiveCollection archives the collection, which is kept along with its tasks
+	ActionArchiveCollection = "archive_collection"
+	// AutomationNamePlaceholder is replaced with the current name of the collection by rename_collection
+	AutomationNamePlaceholder = "{name}"
+)
+
+var (
+	AutomationTriggers = []string{EventTaskCreated, EventTaskUpdated, EventTaskFinished, EventCollectionCreated,
+		AutomationCollectionCompleted}
+	// taskAutomationActions act on the task of the event, the other actions on its collection
+	taskAutomationActions = []string{ActionMoveToCollection, ActionAddTag, ActionRemoveTag, ActionSetFinished,
+		ActionDeleteTask}
+	collectionAutomationActions = []string{ActionRenameCollection, ActionCreateTask, ActionArchiveCollection}
+	// collectionConditionFields are the fields of the conditions that a collection can be compared with, the text
+	// being its name
+	collectionConditionFields = []filterexpr.Field{filterexpr.FieldCollection, filterexpr.FieldText}
+)
+
+// Automation runs its actions on the task or collection of each event of the account with its trigger that meets
+// its condition, a filter expression such as those of the tasks
+type Automation struct {
+	id        int
+	name      string
+	trigger   string
+	condition string
+	actions   []AutomationAction
+	active    bool
+	createdAt time.Time
+}
+
+func NewValidatedAutomation(id int, name, trigger, condition string, actions []AutomationAction,
+	active bool) (*Automation, *todoerrors.Validation) {
+	invalidFields := todoerrors.InvalidFields{}
+	formattedName := strings.TrimSpace(name)
+	if formattedName == "" || utf8.RuneCountInString(formattedName) > MaxAutomationNameLength {
+		invalidFields.AppendField(msgs.AutomationName, fmt.Sprintf(msgs.InvalidAutomationName, MaxAutomationNameLength))
+	}
+
+	formattedTrigger := strings.ToLower(strings.TrimSpace(trigger))
+	if !slices.Contains(AutomationTriggers, formattedTrigger) {
+		invalidFields.AppendField(msgs.AutomationTrigger,
+			msgs.InvalidAutomationTrigger+strings.Join(AutomationTriggers, ", "))
+	}
+	onCollection := IsCollectionTrigger(formattedTrigger)
+
+	formattedCondition := strings.TrimSpace(condition)
+	expression, validationErr := filterexpr.Parse(formattedCondition, time.Now())
+	if validationErr != nil {
+		for _, field := range validationErr.InvalidFields().Fields() {
+			invalidFields.AppendField(msgs.AutomationCondition, field.Description())
+		}
+	} else if onCollection && !conditionFieldsIn(expression, collectionConditionFields) {
+		invalidFields.AppendField(msgs.AutomationCondition, msgs.InvalidAutomationCollectionCondition)
+	}
+
+	allowedActions := taskAutomationActions
+	if onCollection {
+		allowedActions = collectionAutomationActions
+	}
+	if len(actions) == 0 || len(actions) > MaxAutomationActions {
+		invalidFields.AppendField(msgs.AutomationActions, fmt.Sprintf(msgs.InvalidAutomationActions,
+			MaxAutomationActions))
+	}
+	var formattedActions []AutomationAction
+	for index, action := range actions {
+		formattedAction, description := validateAutomationAction(action, allowedActions)
+		if description != "" {
+			invalidFields.AppendField(fmt.Sprintf(msgs.AutomationAction, index+1), description)
+			continue
+		}
+		formattedActions = append(formattedActions, *formattedAction)
+	}
+
+	if invalidFields.HasInvalidFields() {
+		log.Error(msgs.InvalidAutomationDetails)
+		return nil, todoerrors.NewValidationError(msgs.InvalidAutomationDetails, invalidFields)
+	}
+
+	return NewAutomation(id, formattedName, formattedTrigger, formattedCondition, formattedActions, active), nil
+}
+
+func NewAutomation(id int, name, trigger, condition string, actions []AutomationAction, active bool) *Automation {
+	return &Automation{
+		id:        id,
+		name:      name,
+		trigger:   trigger,
+		condition: condition,
+		actions:   actions,
+		active:    active,
+	}
+}
+
+func (d Automation) Id() int {
+	return d.id
+}
+
+func (d Automation) Name() string {
+	return d.name
+}
+
+func (d Automation) Trigger() string {
+	return d.trigger
+}
+
+// Condition is the filter expression the task or collection must meet, empty when every one of them does
+func (d Automation) Condition() string {
+	return d.condition
+}
+
+// ParsedCondition resolves the relative dates of the condition against now, nil meaning no condition
+func (d Automation) ParsedCondition(now time.Time) (filterexpr.Expression, *todoerrors.Validation) {
+	return filterexpr.Parse(d.condition, now)
+}
+
+func (d Automation) Actions() []AutomationAction {
+	return d.actions
+}
+
+func (d Automation) Active() bool {
+	return d.active
+}
+
+func (d Automation) CreatedAt() time.Time {
+	return d.createdAt
+}
+
+func (d *Automation) SetCreatedAt(createdAt time.Time) {
+	d.createdAt = createdAt
+}
+
+// IsCollectionTrigger tells whether the automations with the trigger act on a collection rather than on a task
+func IsCollectionTrigger(trigger string) bool {
+	return trigger == EventCollectionCreated || trigger == AutomationCollectionCompleted
+}
+
+// AutomationAction is a change made by an automation. Each kind reads its own parameters: the collection of
+// move_to_collection, the tag of add_tag and remove_tag, the state of set_finished, and the text of
+// rename_collection and create_task, being the new name and the description of the task.
+type AutomationAction struct {
+	kind         string
+	collectionId int
+	tag          string
+	finished     bool
+	text         string
+}
+
+func NewAutomationAction(kind string, collectionId int, tag string, finished bool, text string) *AutomationAction {
+	return &AutomationAction{
+		kind:         kind,
+		collectionId: collectionId,
+		tag:          tag,
+		finished:     finished,
+		text:         text,
+	}
+}
+
+func (d AutomationAction) Kind() string {
+	return d.kind
+}
+
+func (d AutomationAction) CollectionId() int {
+	return d.collectionId
+}
+
+func (d AutomationAction) Tag() string {
+	return d.tag
+}
+
+func (d AutomationAction) Finished() bool {
+	return d.finished
+}
+
+func (d AutomationAction) Text() string {
+	return d.text
+}
+
+// CollectionName is the name rename_collection gives to the collection, which is cut to the maximum length
+func (d AutomationAction) CollectionName(currentName string) string {
+	name := []rune(strings.TrimSpace(strings.ReplaceAll(d.text, AutomationNamePlaceholder, currentName)))
+	if len(name) > MaxCollectionNameLength {
+		name = name[:MaxCollectionNameLength]
+	}
+	return string(name)
+}
+
+// validateAutomationAction keeps only the parameters of the kind of action, returning the reason it is invalid
+func validateAutomationAction(action AutomationAction, allowedActions []string) (*AutomationAction, string) {
+	kind := strings.ToLower(strings.TrimSpace(action.Kind()))
+	if !slices.Contains(allowedActions, kind) {
+		return nil, msgs.InvalidAutomationActionKind + strings.Join(allowedActions, ", ")
+	}
+
+	switch kind {
+	case ActionMoveToCollection:
+		if action.CollectionId() <= 0 {
+			return nil, msgs.InvalidAutomationCollection
+		}
+		return NewAutomationAction(kind, action.CollectionId(), "", false, ""), ""
+	case ActionAddTag, ActionRemoveTag:
+		tags, validationErr := NewValidatedTags([]string{action.Tag()})
+		if validationErr != nil {
+			return nil, msgs.InvalidTaskTag
+		}
+		return NewAutomationAction(kind, 0, tags[0], false, ""), ""
+	case ActionSetFinished:
+		return NewAutomationAction(kind, 0, "", action.Finished(), ""), ""
+	case ActionRenameCollection, ActionCreateTask:
+		text := strings.TrimSpace(action.Text())
+		if text == "" || utf8.RuneCountInString(text) > MaxTaskDescriptionLength {
+			return nil, fmt.Sprintf(msgs.InvalidAutomationText, MaxTaskDescriptionLength)
+		}
+		return NewAutomationAction(kind, 0, "", false, text), ""
+	default:
+		return NewAutomationAction(kind, 0, "", false, ""), ""
+	}
+}
+
+func conditionFieldsIn(expression filterexpr.Expression, fields []filterexpr.Field) bool {
+	switch node := expression.(type) {
+	case filterexpr.And:
+		return conditionFieldsIn(node.Left, fields) && conditionFieldsIn(node.Right, fields)
+	case filterexpr.Or:
+		return conditionFieldsIn(node.Left, fields) && conditionFieldsIn(node.Right, fields)
+	case filterexpr.Not:
+		return conditionFieldsIn(node.Operand, fields)
+	case filterexpr.Comparison:
+		return slices.Contains(fields, node.Field)
+	default:
+		return true
+	}
+}
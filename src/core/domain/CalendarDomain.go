@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"fmt"
+	"github.com/labstack/gommon/log"
+	"strings"
+	"todo/src/core/domain/msgs"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+const DefaultCalendarName = "Tasks"
+
+// CalendarFeed is the calendar of the tasks of an account, or of one of its collections, reached through the secret
+// token of the account instead of its credentials
+type CalendarFeed struct {
+	token      string
+	userId     int
+	collection *Collection
+}
+
+func NewCalendarFeed(token string, userId int, collection *Collection) *CalendarFeed {
+	return &CalendarFeed{
+		token:      token,
+		userId:     userId,
+		collection: collection,
+	}
+}
+
+func (d CalendarFeed) Token() string {
+	return d.token
+}
+
+func (d CalendarFeed) UserId() int {
+	return d.userId
+}
+
+// Collection is nil when the feed has the tasks of the whole account
+func (d CalendarFeed) Collection() *Collection {
+	return d.collection
+}
+
+func (d CalendarFeed) Name() string {
+	if d.collection != nil {
+		return d.collection.Name()
+	}
+	return DefaultCalendarName
+}
+
+const MaxCalendarObjectNameLength = 255
+
+// CalendarObject is a task as a resource of a CalDAV calendar. The tasks created by CalDAV clients keep the name and
+// UID chosen by the client, the others being named after their ID.
+type CalendarObject struct {
+	name string
+	uid  string
+	task *Task
+}
+
+func NewValidatedCalendarObject(name, uid string, task *Task) (*CalendarObject, *todoerrors.Validation) {
+	invalidFields := todoerrors.InvalidFields{}
+	if name == "" || len(name) > MaxCalendarObjectNameLength || strings.Contains(name, "/") {
+		invalidFields.AppendField(msgs.CalendarObjectName,
+			fmt.Sprintf(msgs.InvalidCalendarObjectName, MaxCalendarObjectNameLength))
+	}
+	if uid == "" || len(uid) > MaxCalendarObjectNameLength {
+		invalidFields.AppendField(msgs.CalendarObjectUid,
+			fmt.Sprintf(msgs.InvalidCalendarObjectUid, MaxCalendarObjectNameLength))
+	}
+
+	if invalidFields.HasInvalidFields() {
+		log.Error(msgs.InvalidCalendarObjectDetails)
+		return nil, todoerrors.NewValidationError(msgs.InvalidCalendarObjectDetails, invalidFields)
+	}
+
+	return NewCalendarObject(name, uid, task), nil
+}
+
+func NewCalendarObject(name, uid string, task *Task) *CalendarObject {
+	return &CalendarObject{
+		name: name,
+		uid:  uid,
+		task: task,
+	}
+}
+
+func (d CalendarObject) Name() string {
+	return d.name
+}
+
+// Uid is empty when the task was not created by a CalDAV client
+func (d CalendarObject) Uid() string {
+	return d.uid
+}
+
+func (d CalendarObject) Task() *Task {
+	return d.task
+}
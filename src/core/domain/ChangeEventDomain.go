@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	ChangeEntityTask       = "task"
+	ChangeEntityCollection = "collection"
+	ChangeActionCreated    = "created"
+	ChangeActionUpdated    = "updated"
+	ChangeActionDeleted    = "deleted"
+)
+
+// ErrChangeFeedResync tells that the events following the last one received by a client are no longer kept, so that
+// it must read the tasks and collections of the user again
+var ErrChangeFeedResync = errors.New("the events after the last event received are no longer kept")
+
+// ChangeEvent tells that a task or collection of a user has changed, so that the clients of the user can read it
+// again. The ID is the one of the event in the outbox, and grows with each event.
+type ChangeEvent struct {
+	id         int64
+	userId     int
+	entity     string
+	action     string
+	entityId   int
+	version    int
+	occurredAt time.Time
+	task       *Task
+	collection *Collection
+	finishing  bool
+}
+
+func NewChangeEvent(userId int, entity, action string, entityId, version int) *ChangeEvent {
+	return &ChangeEvent{
+		userId:     userId,
+		entity:     entity,
+		action:     action,
+		entityId:   entityId,
+		version:    version,
+		occurredAt: time.Now(),
+	}
+}
+
+func (d ChangeEvent) Id() int64 {
+	return d.id
+}
+
+func (d *ChangeEvent) SetId(id int64) {
+	d.id = id
+}
+
+func (d ChangeEvent) UserId() int {
+	return d.userId
+}
+
+func (d ChangeEvent) Entity() string {
+	return d.entity
+}
+
+func (d ChangeEvent) Action() string {
+	return d.action
+}
+
+// Type is the entity and the action of the event, such as task.created
+func (d ChangeEvent) Type() string {
+	return d.entity + "." + d.action
+}
+
+func (d ChangeEvent) EntityId() int {
+	return d.entityId
+}
+
+// Version is the version of the entity after the change, zero when it has been deleted or is not known
+func (d ChangeEvent) Version() int {
+	return d.version
+}
+
+func (d ChangeEvent) OccurredAt() time.Time {
+	return d.occurredAt
+}
+
+func (d *ChangeEvent) SetOccurredAt(occurredAt time.Time) {
+	d.occurredAt = occurredAt
+}
+
+// Task is the state of the task after the change, nil when it has been deleted or is not known
+func (d ChangeEvent) Task() *Task {
+	return d.task
+}
+
+// SetTask keeps the state of the task, telling whether the change has finished it
+func (d *ChangeEvent) SetTask(task *Task, previousTask *Task) {
+	d.task = task
+	d.finishing = task != nil && task.Finished() && previousTask != nil && !previousTask.Finished()
+}
+
+// Collection is the state of the collection after the change, nil when it has been deleted or is not known
+func (d ChangeEvent) Collection() *Collection {
+	return d.collection
+}
+
+func (d *ChangeEvent) SetCollection(collection *Collection) {
+	d.collection = collection
+}
+
+// SetFinishesTask keeps whether the change has finished the task, when the previous state of the task is not at hand
+func (d *ChangeEvent) SetFinishesTask(finishes bool) {
+	d.finishing = finishes
+}
+
+// FinishesTask reports whether the change has finished a task that was not finished
+func (d ChangeEvent) FinishesTask() bool {
+	return d.finishing
+}
@@ -3,13 +3,19 @@ package domain
 import (
 	"github.com/labstack/gommon/log"
 	"strings"
+	"time"
 	"todo/src/core/domain/msgs"
 	"todo/src/core/projecterrors/todoerrors"
 )
 
 type Collection struct {
-	id   int
-	name string
+	id         int
+	name       string
+	createdAt  time.Time
+	version    int
+	archivedAt *time.Time
+	tasks      []Task
+	tags       []string
 }
 
 func NewValidatedCollection(id int, name string) (*Collection, *todoerrors.Validation) {
@@ -41,3 +47,46 @@ func (d Collection) Id() int {
 func (d Collection) Name() string {
 	return d.name
 }
+
+func (d Collection) CreatedAt() time.Time {
+	return d.createdAt
+}
+
+func (d *Collection) SetCreatedAt(createdAt time.Time) {
+	d.createdAt = createdAt
+}
+
+// ArchivedAt is nil while the collection is not archived
+func (d Collection) ArchivedAt() *time.Time {
+	return d.archivedAt
+}
+
+func (d *Collection) SetArchivedAt(archivedAt *time.Time) {
+	d.archivedAt = archivedAt
+}
+
+func (d Collection) Tasks() []Task {
+	return d.tasks
+}
+
+func (d *Collection) SetTasks(tasks []Task) {
+	d.tasks = tasks
+}
+
+func (d Collection) Tags() []string {
+	return d.tags
+}
+
+func (d *Collection) SetTags(tags []string) {
+	d.tags = tags
+}
+
+// Version is incremented on every change of the collection. When updating, it holds the version the stored collection is
+// expected to have, zero meaning any version.
+func (d Collection) Version() int {
+	return d.version
+}
+
+func (d *Collection) SetVersion(version int) {
+	d.version = version
+}
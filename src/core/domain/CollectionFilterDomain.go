@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"github.com/labstack/gommon/log"
+	"strings"
+	"time"
+	"todo/src/core/domain/msgs"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+type CollectionFilter struct {
+	text        string
+	createdFrom *time.Time
+	createdTo   *time.Time
+}
+
+func NewValidatedCollectionFilter(text string, createdFrom, createdTo *time.Time) (*CollectionFilter,
+	*todoerrors.Validation) {
+	if createdFrom != nil && createdTo != nil && createdFrom.After(*createdTo) {
+		log.Error(msgs.InvalidFilterCreatedRange)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.FilterCreatedRange, msgs.InvalidFilterCreatedRange)
+		return nil, todoerrors.NewValidationError(msgs.InvalidFilterDetails, invalidFields)
+	}
+
+	return NewCollectionFilter(text, createdFrom, createdTo), nil
+}
+
+func NewCollectionFilter(text string, createdFrom, createdTo *time.Time) *CollectionFilter {
+	return &CollectionFilter{
+		text:        strings.TrimSpace(text),
+		createdFrom: createdFrom,
+		createdTo:   createdTo,
+	}
+}
+
+func (d CollectionFilter) Text() string {
+	return d.text
+}
+
+func (d CollectionFilter) CreatedFrom() *time.Time {
+	return d.createdFrom
+}
+
+func (d CollectionFilter) CreatedTo() *time.Time {
+	return d.createdTo
+}
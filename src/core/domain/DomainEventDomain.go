@@ -0,0 +1,187 @@
+package domain
+
+import "time"
+
+const (
+	EventTaskCreated       = "task.created"
+	EventTaskUpdated       = "task.updated"
+	EventTaskFinished      = "task.finished"
+	EventTaskDeleted       = "task.deleted"
+	EventCollectionCreated = "collection.created"
+	EventCollectionUpdated = "collection.updated"
+	EventCollectionDeleted = "collection.deleted"
+	EventAccountSignedUp   = "account.signed_up"
+)
+
+// DomainEvent tells about something the services have done on behalf of an account, once it has been stored
+type DomainEvent interface {
+	Name() string
+	UserId() int
+	OccurredAt() time.Time
+}
+
+type eventHeader struct {
+	userId     int
+	occurredAt time.Time
+}
+
+func newEventHeader(userId int) eventHeader {
+	return eventHeader{userId: userId, occurredAt: time.Now()}
+}
+
+func (d eventHeader) UserId() int {
+	return d.userId
+}
+
+func (d eventHeader) OccurredAt() time.Time {
+	return d.occurredAt
+}
+
+type TaskCreated struct {
+	eventHeader
+	task Task
+}
+
+func NewTaskCreated(userId int, task Task) *TaskCreated {
+	return &TaskCreated{newEventHeader(userId), task}
+}
+
+func (TaskCreated) Name() string {
+	return EventTaskCreated
+}
+
+// Task is the task as it has been stored, with its ID and version
+func (d TaskCreated) Task() Task {
+	return d.task
+}
+
+type TaskUpdated struct {
+	eventHeader
+	task         Task
+	previousTask Task
+}
+
+func NewTaskUpdated(userId int, task, previousTask Task) *TaskUpdated {
+	return &TaskUpdated{newEventHeader(userId), task, previousTask}
+}
+
+func (TaskUpdated) Name() string {
+	return EventTaskUpdated
+}
+
+func (d TaskUpdated) Task() Task {
+	return d.task
+}
+
+// PreviousTask is the task as it was before the change
+func (d TaskUpdated) PreviousTask() Task {
+	return d.previousTask
+}
+
+// TaskFinished follows the update that has finished a task that was not finished
+type TaskFinished struct {
+	eventHeader
+	task Task
+}
+
+func NewTaskFinished(userId int, task Task) *TaskFinished {
+	return &TaskFinished{newEventHeader(userId), task}
+}
+
+func (TaskFinished) Name() string {
+	return EventTaskFinished
+}
+
+func (d TaskFinished) Task() Task {
+	return d.task
+}
+
+type TaskDeleted struct {
+	eventHeader
+	task Task
+}
+
+func NewTaskDeleted(userId int, task Task) *TaskDeleted {
+	return &TaskDeleted{newEventHeader(userId), task}
+}
+
+func (TaskDeleted) Name() string {
+	return EventTaskDeleted
+}
+
+// Task is the task as it was before it was deleted
+func (d TaskDeleted) Task() Task {
+	return d.task
+}
+
+type CollectionCreated struct {
+	eventHeader
+	collection Collection
+}
+
+func NewCollectionCreated(userId int, collection Collection) *CollectionCreated {
+	return &CollectionCreated{newEventHeader(userId), collection}
+}
+
+func (CollectionCreated) Name() string {
+	return EventCollectionCreated
+}
+
+func (d CollectionCreated) Collection() Collection {
+	return d.collection
+}
+
+type CollectionUpdated struct {
+	eventHeader
+	collection Collection
+}
+
+func NewCollectionUpdated(userId int, collection Collection) *CollectionUpdated {
+	return &CollectionUpdated{newEventHeader(userId), collection}
+}
+
+func (CollectionUpdated) Name() string {
+	return EventCollectionUpdated
+}
+
+// Collection is the collection after the change, whose version is only known when the change was made to a given one
+func (d CollectionUpdated) Collection() Collection {
+	return d.collection
+}
+
+type CollectionDeleted struct {
+	eventHeader
+	collection Collection
+}
+
+func NewCollectionDeleted(userId int, collection Collection) *CollectionDeleted {
+	return &CollectionDeleted{newEventHeader(userId), collection}
+}
+
+func (CollectionDeleted) Name() string {
+	return EventCollectionDeleted
+}
+
+// Collection is the collection as it was before it was deleted
+func (d CollectionDeleted) Collection() Collection {
+	return d.collection
+}
+
+type AccountSignedUp struct {
+	eventHeader
+	account Account
+}
+
+// NewAccountSignedUp keeps the account without its password
+func NewAccountSignedUp(account Account) *AccountSignedUp {
+	return &AccountSignedUp{newEventHeader(account.Id()),
+		*NewAccount(account.Id(), account.Name(), account.Email(), "", "")}
+}
+
+func (AccountSignedUp) Name() string {
+	return EventAccountSignedUp
+}
+
+func (d AccountSignedUp) Account() Account {
+	return d.account
+}
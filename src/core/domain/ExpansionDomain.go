@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"github.com/labstack/gommon/log"
+	"slices"
+	"strings"
+	"todo/src/core/domain/msgs"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+const (
+	ExpandTasks      = "tasks"
+	ExpandTags       = "tags"
+	ExpandCollection = "collection"
+)
+
+var CollectionExpansionFields = []string{ExpandTasks, ExpandTags}
+
+// TaskExpansionFields are the related resources embedded in a task on request, its collection being otherwise only
+// referenced by its ID
+var TaskExpansionFields = []string{ExpandCollection}
+
+// Expansion lists the related resources to be embedded in a single resource response
+type Expansion struct {
+	fields []string
+}
+
+// NewValidatedExpansion parses a comma separated list of related resources, such as "tasks,tags"
+func NewValidatedExpansion(expand string, allowedFields []string) (*Expansion, *todoerrors.Validation) {
+	var fields []string
+	for _, field := range strings.Split(expand, ",") {
+		formattedField := strings.ToLower(strings.TrimSpace(field))
+		if formattedField == "" {
+			continue
+		}
+		if !slices.Contains(allowedFields, formattedField) {
+			log.Error(msgs.InvalidExpansionDetails)
+			invalidFields := todoerrors.InvalidFields{}
+			invalidFields.AppendField(msgs.Expansion, msgs.InvalidExpansion+strings.Join(allowedFields, ", "))
+			return nil, todoerrors.NewValidationError(msgs.InvalidExpansionDetails, invalidFields)
+		}
+		if !slices.Contains(fields, formattedField) {
+			fields = append(fields, formattedField)
+		}
+	}
+
+	return NewExpansion(fields...), nil
+}
+
+func NewExpansion(fields ...string) *Expansion {
+	return &Expansion{fields}
+}
+
+func (d Expansion) Fields() []string {
+	return d.fields
+}
+
+func (d Expansion) Includes(field string) bool {
+	return slices.Contains(d.fields, field)
+}
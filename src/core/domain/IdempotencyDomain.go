@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"github.com/labstack/gommon/log"
+	"strings"
+	"time"
+	"todo/src/core/domain/msgs"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+const (
+	IdempotencyKeyTTL       = 24 * time.Hour
+	MaxIdempotencyKeyLength = 255
+)
+
+// IdempotentRequest is a request identified by a client key, whose response is kept to be replayed on retries
+type IdempotentRequest struct {
+	key         string
+	fingerprint string
+	status      int
+	contentType string
+	headers     map[string]string
+	body        []byte
+	completed   bool
+}
+
+func NewValidatedIdempotentRequest(key, fingerprint string) (*IdempotentRequest, *todoerrors.Validation) {
+	formattedKey := strings.TrimSpace(key)
+	if formattedKey == "" || len(formattedKey) > MaxIdempotencyKeyLength {
+		log.Error(msgs.InvalidIdempotencyKey)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.IdempotencyKey, msgs.InvalidIdempotencyKey)
+		return nil, todoerrors.NewValidationError(msgs.InvalidIdempotencyDetails, invalidFields)
+	}
+
+	return NewIdempotentRequest(formattedKey, fingerprint), nil
+}
+
+func NewIdempotentRequest(key, fingerprint string) *IdempotentRequest {
+	return &IdempotentRequest{
+		key:         key,
+		fingerprint: fingerprint,
+	}
+}
+
+func (d IdempotentRequest) Key() string {
+	return d.key
+}
+
+// Fingerprint identifies the method, path and body of the request, so a key can't be reused for another request
+func (d IdempotentRequest) Fingerprint() string {
+	return d.fingerprint
+}
+
+func (d IdempotentRequest) Status() int {
+	return d.status
+}
+
+func (d IdempotentRequest) ContentType() string {
+	return d.contentType
+}
+
+// Headers are the headers of the response that are replayed along with its body, such as Location
+func (d IdempotentRequest) Headers() map[string]string {
+	return d.headers
+}
+
+func (d IdempotentRequest) Body() []byte {
+	return d.body
+}
+
+// Completed is false while the first request with the key is still being processed
+func (d IdempotentRequest) Completed() bool {
+	return d.completed
+}
+
+func (d *IdempotentRequest) SetResponse(status int, contentType string, headers map[string]string, body []byte) {
+	d.status = status
+	d.contentType = contentType
+	d.headers = headers
+	d.body = body
+	d.completed = true
+}
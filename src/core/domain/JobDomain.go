@@ -0,0 +1,266 @@
+package domain
+
+import (
+	"fmt"
+	"github.com/labstack/gommon/log"
+	"slices"
+	"strings"
+	"time"
+	"todo/src/core/cron"
+	"todo/src/core/domain/msgs"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	// JobFailed is the state of the jobs that have no attempts left, kept until they are retried or purged
+	JobFailed             = "failed"
+	DefaultJobMaxAttempts = 5
+	// JobLease is how long a job is held by the instance that claimed it, after which another instance takes it over,
+	// as the one that claimed it is taken to have stopped
+	JobLease           = 5 * time.Minute
+	JobRetention       = 7 * 24 * time.Hour
+	FailedJobRetention = 30 * 24 * time.Hour
+	jobFirstRetryWait  = 10 * time.Second
+	jobMaxRetryWait    = time.Hour
+)
+
+var (
+	JobStatuses   = []string{JobQueued, JobRunning, JobSucceeded, JobFailed}
+	JobSortFields = []string{"id", "run_at", "created_at"}
+)
+
+// Job is a unit of background work of a kind, run by one of the instances once its time has come and retried after
+// a wait when it fails, until it has no attempts left. The payload is the JSON document its handler reads.
+type Job struct {
+	id          int64
+	kind        string
+	payload     []byte
+	status      string
+	attempts    int
+	maxAttempts int
+	lastError   string
+	runAt       time.Time
+	schedule    string
+	userId      int
+	lockedBy    string
+	lockedUntil *time.Time
+	startedAt   *time.Time
+	finishedAt  *time.Time
+	createdAt   time.Time
+}
+
+func NewJob(id int64, kind string, payload []byte, runAt time.Time) *Job {
+	if len(payload) == 0 {
+		payload = []byte("{}")
+	}
+	return &Job{
+		id:          id,
+		kind:        kind,
+		payload:     payload,
+		status:      JobQueued,
+		maxAttempts: DefaultJobMaxAttempts,
+		runAt:       runAt,
+	}
+}
+
+func (d Job) Id() int64 {
+	return d.id
+}
+
+func (d Job) Kind() string {
+	return d.kind
+}
+
+func (d Job) Payload() []byte {
+	return d.payload
+}
+
+func (d Job) Status() string {
+	return d.status
+}
+
+// Attempts is the number of times the job has been claimed, including the current run
+func (d Job) Attempts() int {
+	return d.attempts
+}
+
+func (d Job) MaxAttempts() int {
+	return d.maxAttempts
+}
+
+func (d Job) LastError() string {
+	return d.lastError
+}
+
+// RunAt is when the job is due, or due again after a failure
+func (d Job) RunAt() time.Time {
+	return d.runAt
+}
+
+// Schedule is the name of the schedule that queued the job, empty when it has been queued on its own
+func (d Job) Schedule() string {
+	return d.schedule
+}
+
+// LockedBy is the worker running the job
+func (d Job) LockedBy() string {
+	return d.lockedBy
+}
+
+func (d Job) LockedUntil() *time.Time {
+	return d.lockedUntil
+}
+
+func (d Job) StartedAt() *time.Time {
+	return d.startedAt
+}
+
+func (d Job) FinishedAt() *time.Time {
+	return d.finishedAt
+}
+
+func (d Job) CreatedAt() time.Time {
+	return d.createdAt
+}
+
+// HasAttemptsLeft tells whether the job is retried when its current run fails
+func (d Job) HasAttemptsLeft() bool {
+	return d.attempts < d.maxAttempts
+}
+
+func (d *Job) SetState(status string, attempts, maxAttempts int, lastError string) {
+	d.status = status
+	d.attempts = attempts
+	d.maxAttempts = maxAttempts
+	d.lastError = lastError
+}
+
+func (d *Job) SetSchedule(schedule string) {
+	d.schedule = schedule
+}
+
+// UserId is the account the job works for, zero when the job works for all of them
+func (d Job) UserId() int {
+	return d.userId
+}
+
+func (d *Job) SetUserId(userId int) {
+	d.userId = userId
+}
+
+func (d *Job) SetLock(lockedBy string, lockedUntil *time.Time) {
+	d.lockedBy = lockedBy
+	d.lockedUntil = lockedUntil
+}
+
+func (d *Job) SetTimes(startedAt, finishedAt *time.Time, createdAt time.Time) {
+	d.startedAt = startedAt
+	d.finishedAt = finishedAt
+	d.createdAt = createdAt
+}
+
+// JobRetryWait doubles the wait after each failed attempt, up to a limit
+func JobRetryWait(attempts int) time.Duration {
+	wait := jobFirstRetryWait
+	for attempt := 1; attempt < attempts && wait < jobMaxRetryWait; attempt++ {
+		wait *= 2
+	}
+
+	return min(wait, jobMaxRetryWait)
+}
+
+// JobSchedule queues a job of its kind each time its cron expression matches. Each schedule is saved under its name,
+// so that the instances share its next run and a single one of them queues each job.
+type JobSchedule struct {
+	name      string
+	kind      string
+	spec      string
+	payload   []byte
+	schedule  *cron.Schedule
+	nextRunAt time.Time
+}
+
+func NewJobSchedule(name, kind, spec string, payload []byte) (*JobSchedule, error) {
+	schedule, err := cron.Parse(spec)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.Next(time.Now()).IsZero() {
+		return nil, fmt.Errorf("the cron expression %q never matches", spec)
+	}
+	if len(payload) == 0 {
+		payload = []byte("{}")
+	}
+
+	return &JobSchedule{
+		name:     name,
+		kind:     kind,
+		spec:     spec,
+		payload:  payload,
+		schedule: schedule,
+	}, nil
+}
+
+func (d JobSchedule) Name() string {
+	return d.name
+}
+
+func (d JobSchedule) Kind() string {
+	return d.kind
+}
+
+func (d JobSchedule) Spec() string {
+	return d.spec
+}
+
+func (d JobSchedule) Payload() []byte {
+	return d.payload
+}
+
+// NextRunAt is when the schedule queues its next job, as saved
+func (d JobSchedule) NextRunAt() time.Time {
+	return d.nextRunAt
+}
+
+func (d *JobSchedule) SetNextRunAt(nextRunAt time.Time) {
+	d.nextRunAt = nextRunAt
+}
+
+// Next is the first time the schedule matches after the given time
+func (d JobSchedule) Next(after time.Time) time.Time {
+	return d.schedule.Next(after)
+}
+
+// JobFilter chooses the jobs listed by their status, all of them when it is empty
+type JobFilter struct {
+	status string
+}
+
+func NewValidatedJobFilter(status string) (*JobFilter, *todoerrors.Validation) {
+	formattedStatus := strings.ToLower(strings.TrimSpace(status))
+
+	invalidFields := todoerrors.InvalidFields{}
+	if formattedStatus != "" && !slices.Contains(JobStatuses, formattedStatus) {
+		invalidFields.AppendField(msgs.JobStatus, msgs.InvalidJobStatus+strings.Join(JobStatuses, ", "))
+	}
+
+	if invalidFields.HasInvalidFields() {
+		log.Error(msgs.InvalidFilterDetails)
+		return nil, todoerrors.NewValidationError(msgs.InvalidFilterDetails, invalidFields)
+	}
+
+	return NewJobFilter(formattedStatus), nil
+}
+
+func NewJobFilter(status string) *JobFilter {
+	return &JobFilter{
+		status: status,
+	}
+}
+
+func (d JobFilter) Status() string {
+	return d.status
+}
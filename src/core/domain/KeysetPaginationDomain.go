@@ -0,0 +1,164 @@
+package domain
+
+import (
+	"github.com/labstack/gommon/log"
+	"slices"
+	"strings"
+	"time"
+	"todo/src/core/domain/msgs"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+// TaskCursorSortFields lists the sort fields backed by an index on the task table, the only ones usable with cursors
+var TaskCursorSortFields = []string{"id", "created_at"}
+
+type Cursor struct {
+	sortField     string
+	sortDirection string
+	id            int
+	createdAt     time.Time
+	backward      bool
+}
+
+func NewCursor(sortField, sortDirection string, id int, createdAt time.Time, backward bool) *Cursor {
+	return &Cursor{
+		sortField:     sortField,
+		sortDirection: sortDirection,
+		id:            id,
+		createdAt:     createdAt,
+		backward:      backward,
+	}
+}
+
+func (d Cursor) SortField() string {
+	return d.sortField
+}
+
+func (d Cursor) SortDirection() string {
+	return d.sortDirection
+}
+
+func (d Cursor) Id() int {
+	return d.id
+}
+
+func (d Cursor) CreatedAt() time.Time {
+	return d.createdAt
+}
+
+func (d Cursor) Backward() bool {
+	return d.backward
+}
+
+type KeysetPagination struct {
+	limit         int
+	sortField     string
+	sortDirection string
+	cursor        *Cursor
+}
+
+// NewValidatedKeysetPagination builds a keyset pagination, taking the sort from the cursor when one is informed so
+// that every page of a traversal is read in the same order
+func NewValidatedKeysetPagination(limit int, sortField, sortDirection string,
+	cursor *Cursor) (*KeysetPagination, *todoerrors.Validation) {
+	if cursor != nil {
+		sortField = cursor.SortField()
+		sortDirection = cursor.SortDirection()
+	}
+
+	invalidFields := todoerrors.InvalidFields{}
+	if limit < 1 || limit > MaxPageLimit {
+		invalidFields.AppendField(msgs.PaginationLimit, msgs.InvalidPaginationLimit)
+	}
+
+	formattedSortField := strings.ToLower(strings.TrimSpace(sortField))
+	if formattedSortField == "" {
+		formattedSortField = "id"
+	} else if !slices.Contains(TaskCursorSortFields, formattedSortField) {
+		invalidFields.AppendField(msgs.PaginationSort,
+			msgs.InvalidPaginationSort+strings.Join(TaskCursorSortFields, ", "))
+	}
+
+	formattedSortDirection := strings.ToLower(strings.TrimSpace(sortDirection))
+	if formattedSortDirection == "" {
+		formattedSortDirection = SortAscending
+	} else if formattedSortDirection != SortAscending && formattedSortDirection != SortDescending {
+		invalidFields.AppendField(msgs.PaginationOrder, msgs.InvalidPaginationOrder)
+	}
+
+	if invalidFields.HasInvalidFields() {
+		log.Error(msgs.InvalidPaginationDetails)
+		return nil, todoerrors.NewValidationError(msgs.InvalidPaginationDetails, invalidFields)
+	}
+
+	return &KeysetPagination{
+		limit:         limit,
+		sortField:     formattedSortField,
+		sortDirection: formattedSortDirection,
+		cursor:        cursor,
+	}, nil
+}
+
+func NewKeysetPagination(limit int, sortField, sortDirection string, cursor *Cursor) *KeysetPagination {
+	return &KeysetPagination{
+		limit:         limit,
+		sortField:     sortField,
+		sortDirection: sortDirection,
+		cursor:        cursor,
+	}
+}
+
+func (d KeysetPagination) Limit() int {
+	return d.limit
+}
+
+func (d KeysetPagination) SortField() string {
+	return d.sortField
+}
+
+func (d KeysetPagination) SortDirection() string {
+	return d.sortDirection
+}
+
+func (d KeysetPagination) IsDescending() bool {
+	return d.sortDirection == SortDescending
+}
+
+func (d KeysetPagination) Cursor() *Cursor {
+	return d.cursor
+}
+
+// IsBackward reports whether the page is read towards the beginning of the list, which inverts the query order
+func (d KeysetPagination) IsBackward() bool {
+	return d.cursor != nil && d.cursor.Backward()
+}
+
+func (d KeysetPagination) CursorFor(task Task, backward bool) *Cursor {
+	return NewCursor(d.sortField, d.sortDirection, task.Id(), task.CreatedAt(), backward)
+}
+
+type TaskPage struct {
+	tasks    []Task
+	next     *Cursor
+	previous *Cursor
+}
+
+func NewTaskPage(tasks []Task, next, previous *Cursor) *TaskPage {
+	return &TaskPage{
+		tasks:    tasks,
+		next:     next,
+		previous: previous,
+	}
+}
+
+func (d TaskPage) Tasks() []Task {
+	return d.tasks
+}
+
+func (d TaskPage) Next() *Cursor {
+	return d.next
+}
+
+func (d TaskPage) Previous() *Cursor {
+	return d.previous
+}
@@ -0,0 +1,75 @@
+package domain
+
+// NotificationPreferences choose the reminders sent to an account, all of them being sent by default
+type NotificationPreferences struct {
+	email              bool
+	scheduledReminders bool
+	dueDateReminders   bool
+}
+
+func NewNotificationPreferences(email, scheduledReminders, dueDateReminders bool) *NotificationPreferences {
+	return &NotificationPreferences{
+		email:              email,
+		scheduledReminders: scheduledReminders,
+		dueDateReminders:   dueDateReminders,
+	}
+}
+
+func DefaultNotificationPreferences() *NotificationPreferences {
+	return NewNotificationPreferences(true, true, true)
+}
+
+// Email turns all the emails of the account on or off
+func (d NotificationPreferences) Email() bool {
+	return d.email
+}
+
+// ScheduledReminders are the reminders set at a time
+func (d NotificationPreferences) ScheduledReminders() bool {
+	return d.scheduledReminders
+}
+
+// DueDateReminders are the reminders set before the due date of their task
+func (d NotificationPreferences) DueDateReminders() bool {
+	return d.dueDateReminders
+}
+
+func (d NotificationPreferences) Allows(reminder Reminder) bool {
+	if reminder.IsBeforeDue() {
+		return d.email && d.dueDateReminders
+	}
+	return d.email && d.scheduledReminders
+}
+
+// Mail is an email with both an HTML and a plain text body, the latter for the clients that don't show HTML
+type Mail struct {
+	to      string
+	subject string
+	text    string
+	html    string
+}
+
+func NewMail(to, subject, text, html string) *Mail {
+	return &Mail{
+		to:      to,
+		subject: subject,
+		text:    text,
+		html:    html,
+	}
+}
+
+func (d Mail) To() string {
+	return d.to
+}
+
+func (d Mail) Subject() string {
+	return d.subject
+}
+
+func (d Mail) Text() string {
+	return d.text
+}
+
+func (d Mail) Html() string {
+	return d.html
+}
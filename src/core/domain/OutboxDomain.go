@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
+const (
+	MaxOutboxAttempts    = 10
+	OutboxRetention      = 7 * 24 * time.Hour
+	outboxFirstRetryWait = time.Second
+	outboxMaxRetryWait   = 10 * time.Minute
+)
+
+// ErrOutboxEventDeferred tells that the event is left pending as it is, without counting an attempt, because it must
+// wait for an earlier event of its aggregate
+var ErrOutboxEventDeferred = errors.New("the event waits for an earlier event of its aggregate")
+
+// OutboxEvent is a change event written in the same transaction as the change, which stays pending until it has been
+// handled. The ID of the event is the one of the outbox, and grows in the order the events have been written.
+type OutboxEvent struct {
+	event       ChangeEvent
+	attempts    int
+	availableAt time.Time
+}
+
+func NewOutboxEvent(event ChangeEvent, attempts int, availableAt time.Time) *OutboxEvent {
+	return &OutboxEvent{
+		event:       event,
+		attempts:    attempts,
+		availableAt: availableAt,
+	}
+}
+
+func (d OutboxEvent) Event() ChangeEvent {
+	return d.event
+}
+
+// Attempts is the number of times handling the event has failed
+func (d OutboxEvent) Attempts() int {
+	return d.attempts
+}
+
+// AvailableAt is when the event can be handled again after a failure
+func (d OutboxEvent) AvailableAt() time.Time {
+	return d.availableAt
+}
+
+// Aggregate identifies the task or collection changed by the event, whose events are handled in order
+func (d OutboxEvent) Aggregate() string {
+	return d.event.Entity() + ":" + strconv.Itoa(d.event.EntityId())
+}
+
+// OutboxRetryWait doubles the wait after each failed attempt, up to a limit
+func OutboxRetryWait(attempts int) time.Duration {
+	wait := outboxFirstRetryWait
+	for attempt := 1; attempt < attempts && wait < outboxMaxRetryWait; attempt++ {
+		wait *= 2
+	}
+
+	return min(wait, outboxMaxRetryWait)
+}
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"github.com/labstack/gommon/log"
+	"slices"
+	"strings"
+	"todo/src/core/domain/msgs"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+const (
+	SortAscending    = "asc"
+	SortDescending   = "desc"
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+var (
+	TaskSortFields       = []string{"id", "description", "finished", "collection_id", "collection_name", "created_at", "due_date"}
+	CollectionSortFields = []string{"id", "name", "created_at"}
+)
+
+type Pagination struct {
+	limit         int
+	offset        int
+	sortField     string
+	sortDirection string
+}
+
+func NewValidatedPagination(limit, offset int, sortField, sortDirection string,
+	allowedSortFields []string) (*Pagination, *todoerrors.Validation) {
+	invalidFields := todoerrors.InvalidFields{}
+	if limit < 1 || limit > MaxPageLimit {
+		invalidFields.AppendField(msgs.PaginationLimit, msgs.InvalidPaginationLimit)
+	}
+	if offset < 0 {
+		invalidFields.AppendField(msgs.PaginationOffset, msgs.InvalidPaginationOffset)
+	}
+
+	formattedSortField := strings.ToLower(strings.TrimSpace(sortField))
+	if formattedSortField == "" {
+		formattedSortField = "id"
+	} else if !slices.Contains(allowedSortFields, formattedSortField) {
+		invalidFields.AppendField(msgs.PaginationSort, msgs.InvalidPaginationSort+strings.Join(allowedSortFields, ", "))
+	}
+
+	formattedSortDirection := strings.ToLower(strings.TrimSpace(sortDirection))
+	if formattedSortDirection == "" {
+		formattedSortDirection = SortAscending
+	} else if formattedSortDirection != SortAscending && formattedSortDirection != SortDescending {
+		invalidFields.AppendField(msgs.PaginationOrder, msgs.InvalidPaginationOrder)
+	}
+
+	if invalidFields.HasInvalidFields() {
+		log.Error(msgs.InvalidPaginationDetails)
+		return nil, todoerrors.NewValidationError(msgs.InvalidPaginationDetails, invalidFields)
+	}
+
+	return &Pagination{
+		limit:         limit,
+		offset:        offset,
+		sortField:     formattedSortField,
+		sortDirection: formattedSortDirection,
+	}, nil
+}
+
+func NewPagination(limit, offset int, sortField, sortDirection string) *Pagination {
+	return &Pagination{
+		limit:         limit,
+		offset:        offset,
+		sortField:     sortField,
+		sortDirection: sortDirection,
+	}
+}
+
+func (d Pagination) Limit() int {
+	return d.limit
+}
+
+func (d Pagination) Offset() int {
+	return d.offset
+}
+
+func (d Pagination) SortField() string {
+	return d.sortField
+}
+
+func (d Pagination) SortDirection() string {
+	return d.sortDirection
+}
+
+func (d Pagination) IsDescending() bool {
+	return d.sortDirection == SortDescending
+}
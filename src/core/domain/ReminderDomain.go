@@ -0,0 +1,183 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"github.com/labstack/gommon/log"
+	"time"
+	"todo/src/core/domain/msgs"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+const (
+	ReminderPending = "pending"
+	ReminderSent    = "sent"
+	// ReminderSkipped is the state of the reminders left unsent, as their task is finished or the preferences of
+	// the account turn them off
+	ReminderSkipped             = "skipped"
+	ReminderFailed              = "failed"
+	MaxReminderAttempts         = 5
+	MaxReminderMinutesBeforeDue = 28 * 24 * 60
+	// ReminderLease is how long the reminders claimed by an instance are kept from the other ones while they are sent,
+	// after which they are claimed again as the instance is taken to have stopped
+	ReminderLease = 5 * time.Minute
+)
+
+// ErrReminderSkipped tells that the reminder is done without being sent
+var ErrReminderSkipped = errors.New("the reminder is not to be sent")
+
+// Reminder is an email about a task sent either at a time or a number of minutes before the due date of the task,
+// in which case it follows the changes made to the due date until it is sent
+type Reminder struct {
+	id               int
+	taskId           int
+	remindAt         *time.Time
+	minutesBeforeDue *int
+	status           string
+	attempts         int
+	lastError        string
+	processedAt      time.Time
+	createdAt        time.Time
+}
+
+func NewValidatedReminder(id, taskId int, remindAt *time.Time, minutesBeforeDue *int) (*Reminder,
+	*todoerrors.Validation) {
+	invalidFields := todoerrors.InvalidFields{}
+	switch {
+	case (remindAt == nil) == (minutesBeforeDue == nil):
+		invalidFields.AppendField(msgs.ReminderSchedule, msgs.InvalidReminderSchedule)
+	case remindAt != nil && !remindAt.After(time.Now()):
+		invalidFields.AppendField(msgs.RemindAt, msgs.InvalidReminderTime)
+	case minutesBeforeDue != nil && (*minutesBeforeDue < 0 || *minutesBeforeDue > MaxReminderMinutesBeforeDue):
+		invalidFields.AppendField(msgs.MinutesBeforeDue, fmt.Sprintf(msgs.InvalidReminderMinutesBeforeDue,
+			MaxReminderMinutesBeforeDue))
+	}
+
+	if invalidFields.HasInvalidFields() {
+		log.Error(msgs.InvalidReminderDetails)
+		return nil, todoerrors.NewValidationError(msgs.InvalidReminderDetails, invalidFields)
+	}
+
+	return NewReminder(id, taskId, remindAt, minutesBeforeDue), nil
+}
+
+func NewReminder(id, taskId int, remindAt *time.Time, minutesBeforeDue *int) *Reminder {
+	return &Reminder{
+		id:               id,
+		taskId:           taskId,
+		remindAt:         remindAt,
+		minutesBeforeDue: minutesBeforeDue,
+		status:           ReminderPending,
+	}
+}
+
+func (d Reminder) Id() int {
+	return d.id
+}
+
+func (d Reminder) TaskId() int {
+	return d.taskId
+}
+
+// RemindAt is the time of the reminder, nil when it is set before the due date
+func (d Reminder) RemindAt() *time.Time {
+	return d.remindAt
+}
+
+// MinutesBeforeDue is how long before the due date the reminder is sent, nil when it is set at a time
+func (d Reminder) MinutesBeforeDue() *int {
+	return d.minutesBeforeDue
+}
+
+// IsBeforeDue tells whether the reminder follows the due date of its task
+func (d Reminder) IsBeforeDue() bool {
+	return d.minutesBeforeDue != nil
+}
+
+// SendAt is when the reminder is due, nil when it is set before the due date of a task without one
+func (d Reminder) SendAt(dueDate *time.Time) *time.Time {
+	if !d.IsBeforeDue() {
+		return d.remindAt
+	}
+	if dueDate == nil {
+		return nil
+	}
+	sendAt := dueDate.Add(-time.Duration(*d.minutesBeforeDue) * time.Minute)
+	return &sendAt
+}
+
+func (d Reminder) Status() string {
+	return d.status
+}
+
+// Attempts is the number of times sending the reminder has failed
+func (d Reminder) Attempts() int {
+	return d.attempts
+}
+
+func (d Reminder) LastError() string {
+	return d.lastError
+}
+
+func (d *Reminder) SetDelivery(status string, attempts int, lastError string) {
+	d.status = status
+	d.attempts = attempts
+	d.lastError = lastError
+}
+
+// ProcessedAt is when the reminder has been sent, skipped or given up on
+func (d Reminder) ProcessedAt() time.Time {
+	return d.processedAt
+}
+
+func (d *Reminder) SetProcessedAt(processedAt time.Time) {
+	d.processedAt = processedAt
+}
+
+func (d Reminder) CreatedAt() time.Time {
+	return d.createdAt
+}
+
+func (d *Reminder) SetCreatedAt(createdAt time.Time) {
+	d.createdAt = createdAt
+}
+
+// DueReminder is a reminder whose time has come, along with what is needed to send it
+type DueReminder struct {
+	reminder    Reminder
+	task        Task
+	account     Account
+	preferences NotificationPreferences
+}
+
+func NewDueReminder(reminder Reminder, task Task, account Account,
+	preferences NotificationPreferences) *DueReminder {
+	return &DueReminder{
+		reminder:    reminder,
+		task:        task,
+		account:     account,
+		preferences: preferences,
+	}
+}
+
+func (d DueReminder) Reminder() Reminder {
+	return d.reminder
+}
+
+func (d DueReminder) Task() Task {
+	return d.task
+}
+
+// Account is the owner of the task, to whom the reminder is sent
+func (d DueReminder) Account() Account {
+	return d.account
+}
+
+func (d DueReminder) Preferences() NotificationPreferences {
+	return d.preferences
+}
+
+// Wanted tells whether the reminder is still worth sending
+func (d DueReminder) Wanted() bool {
+	return !d.task.Finished() && d.preferences.Allows(d.reminder)
+}
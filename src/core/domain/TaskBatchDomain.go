@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"fmt"
+	"github.com/labstack/gommon/log"
+	"todo/src/core/domain/msgs"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+const (
+	TaskOperationCreate = "create"
+	TaskOperationUpdate = "update"
+	TaskOperationDelete = "delete"
+	MaxTaskBatchSize    = 100
+)
+
+type TaskOperation struct {
+	kind    string
+	taskId  int
+	version int
+	task    *Task
+}
+
+func NewTaskOperation(kind string, taskId, version int, task *Task) *TaskOperation {
+	return &TaskOperation{
+		kind:    kind,
+		taskId:  taskId,
+		version: version,
+		task:    task,
+	}
+}
+
+func (d TaskOperation) Kind() string {
+	return d.kind
+}
+
+func (d TaskOperation) TaskId() int {
+	return d.taskId
+}
+
+// Version is the version the stored task is expected to have, zero meaning any version
+func (d TaskOperation) Version() int {
+	return d.version
+}
+
+func (d TaskOperation) Task() *Task {
+	return d.task
+}
+
+type TaskBatch struct {
+	operations []TaskOperation
+	atomic     bool
+}
+
+func NewValidatedTaskBatch(operations []TaskOperation, atomic bool) (*TaskBatch, *todoerrors.Validation) {
+	if len(operations) == 0 || len(operations) > MaxTaskBatchSize {
+		log.Error(msgs.InvalidTaskBatchDetails)
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(msgs.TaskBatchOperations, fmt.Sprintf(msgs.InvalidTaskBatchSize, MaxTaskBatchSize))
+		return nil, todoerrors.NewValidationError(msgs.InvalidTaskBatchDetails, invalidFields)
+	}
+
+	return NewTaskBatch(operations, atomic), nil
+}
+
+func NewTaskBatch(operations []TaskOperation, atomic bool) *TaskBatch {
+	return &TaskBatch{
+		operations: operations,
+		atomic:     atomic,
+	}
+}
+
+func (d TaskBatch) Operations() []TaskOperation {
+	return d.operations
+}
+
+// Atomic reports whether the operations must all be applied or none of them
+func (d TaskBatch) Atomic() bool {
+	return d.atomic
+}
+
+type TaskOperationResult struct {
+	taskId int
+	err    error
+}
+
+func NewTaskOperationResult(taskId int, err error) *TaskOperationResult {
+	return &TaskOperationResult{
+		taskId: taskId,
+		err:    err,
+	}
+}
+
+func (d TaskOperationResult) TaskId() int {
+	return d.taskId
+}
+
+func (d TaskOperationResult) Err() error {
+	return d.err
+}
+
+type TaskBatchResult struct {
+	results   []TaskOperationResult
+	committed bool
+	undoToken string
+}
+
+func NewTaskBatchResult(results []TaskOperationResult, committed bool) *TaskBatchResult {
+	return &TaskBatchResult{
+		results:   results,
+		committed: committed,
+	}
+}
+
+func (d TaskBatchResult) Results() []TaskOperationResult {
+	return d.results
+}
+
+// Committed is false when an atomic batch has been rolled back
+func (d TaskBatchResult) Committed() bool {
+	return d.committed
+}
+
+// UndoToken reverses the applied operations, empty when there is nothing to undo
+func (d TaskBatchResult) UndoToken() string {
+	return d.undoToken
+}
+
+func (d *TaskBatchResult) SetUndoToken(undoToken string) {
+	d.undoToken = undoToken
+}
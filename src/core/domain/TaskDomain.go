@@ -1,10 +1,25 @@
 package domain
 
+import (
+	"github.com/labstack/gommon/log"
+	"slices"
+	"strings"
+	"time"
+	"todo/src/core/domain/msgs"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+const MaxTagLength = 30
+
 type Task struct {
 	id          int
 	description string
 	finished    bool
 	collection  *Collection
+	createdAt   time.Time
+	version     int
+	dueDate     *time.Time
+	tags        []string
 }
 
 func NewTask(id int, description string, finished bool, collection *Collection) *Task {
@@ -16,6 +31,25 @@ func NewTask(id int, description string, finished bool, collection *Collection)
 	}
 }
 
+// NewValidatedTags normalizes the tags to lower case without surrounding spaces or duplicates
+func NewValidatedTags(tags []string) ([]string, *todoerrors.Validation) {
+	normalizedTags := []string{}
+	for _, tag := range tags {
+		formattedTag := strings.ToLower(strings.TrimSpace(tag))
+		if formattedTag == "" || len(formattedTag) > MaxTagLength || strings.ContainsAny(formattedTag, " \t\n") {
+			log.Error(msgs.InvalidTaskTag)
+			invalidFields := todoerrors.InvalidFields{}
+			invalidFields.AppendField(msgs.TaskTags, msgs.InvalidTaskTag)
+			return nil, todoerrors.NewValidationError(msgs.InvalidTaskDetails, invalidFields)
+		}
+		if !slices.Contains(normalizedTags, formattedTag) {
+			normalizedTags = append(normalizedTags, formattedTag)
+		}
+	}
+
+	return normalizedTags, nil
+}
+
 func (d Task) Id() int {
 	return d.id
 }
@@ -31,3 +65,37 @@ func (d Task) Finished() bool {
 func (d Task) Collection() *Collection {
 	return d.collection
 }
+
+func (d Task) CreatedAt() time.Time {
+	return d.createdAt
+}
+
+func (d *Task) SetCreatedAt(createdAt time.Time) {
+	d.createdAt = createdAt
+}
+
+func (d Task) DueDate() *time.Time {
+	return d.dueDate
+}
+
+func (d *Task) SetDueDate(dueDate *time.Time) {
+	d.dueDate = dueDate
+}
+
+func (d Task) Tags() []string {
+	return d.tags
+}
+
+func (d *Task) SetTags(tags []string) {
+	d.tags = tags
+}
+
+// Version is incremented on every change of the task. When updating, it holds the version the stored task is
+// expected to have, zero meaning any version.
+func (d Task) Version() int {
+	return d.version
+}
+
+func (d *Task) SetVersion(version int) {
+	d.version = version
+}
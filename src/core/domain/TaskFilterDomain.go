@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"github.com/labstack/gommon/log"
+	"strings"
+	"time"
+	"todo/src/core/domain/msgs"
+	"todo/src/core/filterexpr"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+type TaskFilter struct {
+	finished     *bool
+	collectionId int
+	text         string
+	createdFrom  *time.Time
+	createdTo    *time.Time
+	expression   filterexpr.Expression
+}
+
+func NewValidatedTaskFilter(finished *bool, collectionId int, text string, createdFrom, createdTo *time.Time,
+	expression string) (*TaskFilter, *todoerrors.Validation) {
+	parsedExpression, validationErr := filterexpr.Parse(expression, time.Now())
+	if validationErr != nil {
+		return nil, validationErr
+	}
+
+	invalidFields := todoerrors.InvalidFields{}
+	if collectionId < 0 {
+		invalidFields.AppendField(msgs.FilterCollection, msgs.InvalidFilterCollection)
+	}
+	if createdFrom != nil && createdTo != nil && createdFrom.After(*createdTo) {
+		invalidFields.AppendField(msgs.FilterCreatedRange, msgs.InvalidFilterCreatedRange)
+	}
+
+	if invalidFields.HasInvalidFields() {
+		log.Error(msgs.InvalidFilterDetails)
+		return nil, todoerrors.NewValidationError(msgs.InvalidFilterDetails, invalidFields)
+	}
+
+	return NewTaskFilter(finished, collectionId, text, createdFrom, createdTo, parsedExpression), nil
+}
+
+func NewTaskFilter(finished *bool, collectionId int, text string, createdFrom, createdTo *time.Time,
+	expression filterexpr.Expression) *TaskFilter {
+	return &TaskFilter{
+		finished:     finished,
+		collectionId: collectionId,
+		text:         strings.TrimSpace(text),
+		createdFrom:  createdFrom,
+		createdTo:    createdTo,
+		expression:   expression,
+	}
+}
+
+func (d TaskFilter) Finished() *bool {
+	return d.finished
+}
+
+func (d TaskFilter) CollectionId() int {
+	return d.collectionId
+}
+
+func (d TaskFilter) Text() string {
+	return d.text
+}
+
+func (d TaskFilter) CreatedFrom() *time.Time {
+	return d.createdFrom
+}
+
+func (d TaskFilter) CreatedTo() *time.Time {
+	return d.createdTo
+}
+
+func (d TaskFilter) Expression() filterexpr.Expression {
+	return d.expression
+}
+
+func (d *TaskFilter) SetCollectionId(collectionId int) {
+	d.collectionId = collectionId
+}
@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"fmt"
+	"github.com/labstack/gommon/log"
+	"strings"
+	"todo/src/core/domain/msgs"
+	"todo/src/core/projecterrors/todoerrors"
+	"unicode/utf8"
+)
+
+const (
+	MaxTransferSize          = 10000
+	MaxCollectionNameLength  = 50
+	MaxTaskDescriptionLength = 50
+	// ImportCollectionName names the collection receiving the imported tasks that have none, created with the first
+	// of them, as the tasks are only listed along with their collection
+	ImportCollectionName = "Imported"
+)
+
+// Transfer holds the collections and tasks of an account moved between environments. The IDs are the ones in the
+// transferred file, and the tasks refer to their collections by those IDs, zero meaning no collection.
+type Transfer struct {
+	collections []Collection
+	tasks       []Task
+}
+
+// NewValidatedTransfer reports every invalid row, naming the rows by their position in the file
+func NewValidatedTransfer(collections []Collection, tasks []Task) (*Transfer, *todoerrors.Validation) {
+	invalidFields := todoerrors.InvalidFields{}
+	if len(collections)+len(tasks) > MaxTransferSize {
+		invalidFields.AppendField(msgs.TransferRows, fmt.Sprintf(msgs.InvalidTransferSize, MaxTransferSize))
+	}
+
+	collectionIds := map[int]bool{}
+	for index, collection := range collections {
+		row := fmt.Sprintf(msgs.TransferCollection, index+1)
+		if collection.Id() <= 0 {
+			invalidFields.AppendField(row, msgs.InvalidTransferId)
+		} else if collectionIds[collection.Id()] {
+			invalidFields.AppendField(row, msgs.DuplicatedTransferId)
+		}
+		collectionIds[collection.Id()] = true
+		if collection.Name() == "" || utf8.RuneCountInString(collection.Name()) > MaxCollectionNameLength {
+			invalidFields.AppendField(row, fmt.Sprintf(msgs.InvalidTransferCollectionName, MaxCollectionNameLength))
+		}
+	}
+
+	taskIds := map[int]bool{}
+	for index, task := range tasks {
+		row := fmt.Sprintf(msgs.TransferTask, index+1)
+		if task.Id() <= 0 {
+			invalidFields.AppendField(row, msgs.InvalidTransferId)
+		} else if taskIds[task.Id()] {
+			invalidFields.AppendField(row, msgs.DuplicatedTransferId)
+		}
+		taskIds[task.Id()] = true
+		if strings.TrimSpace(task.Description()) == "" ||
+			utf8.RuneCountInString(task.Description()) > MaxTaskDescriptionLength {
+			invalidFields.AppendField(row, fmt.Sprintf(msgs.InvalidTransferTaskDescription, MaxTaskDescriptionLength))
+		}
+		if collectionId := task.Collection().Id(); collectionId != 0 && !collectionIds[collectionId] {
+			invalidFields.AppendField(row, msgs.InvalidTransferTaskCollection)
+		}
+	}
+
+	if invalidFields.HasInvalidFields() {
+		log.Error(msgs.InvalidTransferDetails)
+		return nil, todoerrors.NewValidationError(msgs.InvalidTransferDetails, invalidFields)
+	}
+
+	return NewTransfer(collections, tasks), nil
+}
+
+func NewTransfer(collections []Collection, tasks []Task) *Transfer {
+	return &Transfer{
+		collections: collections,
+		tasks:       tasks,
+	}
+}
+
+func (d Transfer) Collections() []Collection {
+	return d.collections
+}
+
+func (d Transfer) Tasks() []Task {
+	return d.tasks
+}
+
+type TransferResult struct {
+	collectionIds map[int]int
+	taskIds       map[int]int
+}
+
+func NewTransferResult(collectionIds, taskIds map[int]int) *TransferResult {
+	return &TransferResult{
+		collectionIds: collectionIds,
+		taskIds:       taskIds,
+	}
+}
+
+// CollectionIds maps the collection IDs of the file to the IDs the collections have been created with
+func (d TransferResult) CollectionIds() map[int]int {
+	return d.collectionIds
+}
+
+// TaskIds maps the task IDs of the file to the IDs the tasks have been created with
+func (d TransferResult) TaskIds() map[int]int {
+	return d.taskIds
+}
@@ -0,0 +1,81 @@
+package domain
+
+import "time"
+
+const (
+	UndoTokenTTL         = 10 * time.Minute
+	UndoTaskUpdate       = "task_update"
+	UndoTaskDelete       = "task_delete"
+	UndoTaskBatch        = "task_batch"
+	UndoCollectionDelete = "collection_delete"
+)
+
+// TaskChange is a change made to a task, kept to be reversed
+type TaskChange struct {
+	taskId   int
+	previous *Task
+	version  int
+}
+
+func NewTaskChange(taskId int, previous *Task, version int) *TaskChange {
+	return &TaskChange{
+		taskId:   taskId,
+		previous: previous,
+		version:  version,
+	}
+}
+
+func (d TaskChange) TaskId() int {
+	return d.taskId
+}
+
+// Previous is the task as it was before the change, nil when the task has been created
+func (d TaskChange) Previous() *Task {
+	return d.previous
+}
+
+// Version is the version of the task after the change, zero when the task has been deleted
+func (d TaskChange) Version() int {
+	return d.version
+}
+
+// UndoOperation is a reversible operation, which can be undone with its token until it expires
+type UndoOperation struct {
+	token       string
+	kind        string
+	taskChanges []TaskChange
+	collection  *Collection
+	expiresAt   time.Time
+}
+
+func NewUndoOperation(token, kind string, taskChanges []TaskChange, collection *Collection,
+	expiresAt time.Time) *UndoOperation {
+	return &UndoOperation{
+		token:       token,
+		kind:        kind,
+		taskChanges: taskChanges,
+		collection:  collection,
+		expiresAt:   expiresAt,
+	}
+}
+
+func (d UndoOperation) Token() string {
+	return d.token
+}
+
+func (d UndoOperation) Kind() string {
+	return d.kind
+}
+
+func (d UndoOperation) TaskChanges() []TaskChange {
+	return d.taskChanges
+}
+
+// Collection is the deleted collection, nil when the operation has not deleted one
+func (d UndoOperation) Collection() *Collection {
+	return d.collection
+}
+
+func (d UndoOperation) ExpiresAt() time.Time {
+	return d.expiresAt
+}
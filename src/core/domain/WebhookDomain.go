@@ -0,0 +1,270 @@
+package domain
+
+import (
+	"fmt"
+	"github.com/labstack/gommon/log"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+	"todo/src/core/domain/msgs"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+const (
+	WebhookTaskFinished = "task.finished"
+	MaxWebhookUrlLength = 2048
+	MaxWebhookAttempts  = 5
+	WebhookFailureLimit = 5
+)
+
+var (
+	// WebhookEventTypes are the types of the change events, along with task.finished, sent when an update finishes a
+	// task that was not finished
+	WebhookEventTypes = []string{"task.created", "task.updated", WebhookTaskFinished, "task.deleted",
+		"collection.created", "collection.updated", "collection.deleted"}
+	WebhookDeliverySortFields = []string{"id", "created_at"}
+	// deniedWebhookNetworks are the ranges that are not public without the net package telling so: the shared address
+	// space of the carrier-grade NATs, the benchmarking networks, the "this network" addresses and the NAT64 prefix,
+	// which would reach the IPv4 addresses embedded in it
+	deniedWebhookNetworks = parseNetworks("100.64.0.0/10", "198.18.0.0/15", "0.0.0.0/8", "64:ff9b::/96")
+)
+
+// Webhook is an address to which the events of the account with one of its types are posted, signed with its
+// secret. It is disabled once its deliveries have failed too many times in a row.
+type Webhook struct {
+	id         int
+	url        string
+	secret     string
+	eventTypes []string
+	active     bool
+	failures   int
+	createdAt  time.Time
+}
+
+func NewValidatedWebhook(id int, webhookUrl string, eventTypes []string,
+	active bool) (*Webhook, *todoerrors.Validation) {
+	invalidFields := todoerrors.InvalidFields{}
+	formattedUrl := strings.TrimSpace(webhookUrl)
+	parsedUrl, err := url.Parse(formattedUrl)
+	if err != nil || len(formattedUrl) > MaxWebhookUrlLength || parsedUrl.Host == "" ||
+		(parsedUrl.Scheme != "http" && parsedUrl.Scheme != "https") {
+		invalidFields.AppendField(msgs.WebhookUrl, fmt.Sprintf(msgs.InvalidWebhookUrl, MaxWebhookUrlLength))
+	} else if !publicWebhookHost(parsedUrl.Hostname()) {
+		invalidFields.AppendField(msgs.WebhookUrl, msgs.PrivateWebhookUrl)
+	}
+
+	var formattedEventTypes []string
+	for _, eventType := range eventTypes {
+		formattedEventType := strings.ToLower(strings.TrimSpace(eventType))
+		if !slices.Contains(WebhookEventTypes, formattedEventType) {
+			invalidFields.AppendField(msgs.WebhookEventTypes,
+				msgs.InvalidWebhookEventType+strings.Join(WebhookEventTypes, ", "))
+			break
+		}
+		if !slices.Contains(formattedEventTypes, formattedEventType) {
+			formattedEventTypes = append(formattedEventTypes, formattedEventType)
+		}
+	}
+	if len(eventTypes) == 0 {
+		invalidFields.AppendField(msgs.WebhookEventTypes,
+			msgs.InvalidWebhookEventType+strings.Join(WebhookEventTypes, ", "))
+	}
+
+	if invalidFields.HasInvalidFields() {
+		log.Error(msgs.InvalidWebhookDetails)
+		return nil, todoerrors.NewValidationError(msgs.InvalidWebhookDetails, invalidFields)
+	}
+
+	return NewWebhook(id, formattedUrl, formattedEventTypes, active), nil
+}
+
+// publicWebhookHost rejects the local host names and the IP addresses that are not public. The names that resolve to
+// such addresses are only known when the payloads are posted, which the sender checks.
+func publicWebhookHost(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return PublicWebhookIp(ip)
+	}
+	return true
+}
+
+// PublicWebhookIp tells whether the payloads can be posted to the address, which must not be private, loopback,
+// link-local, such as the 169.254.169.254 of the cloud metadata services, multicast, unspecified nor in one of the
+// denied networks
+func PublicWebhookIp(ip net.IP) bool {
+	if ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	for _, network := range deniedWebhookNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func parseNetworks(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks[i] = network
+	}
+	return networks
+}
+
+func NewWebhook(id int, url string, eventTypes []string, active bool) *Webhook {
+	return &Webhook{
+		id:         id,
+		url:        url,
+		eventTypes: eventTypes,
+		active:     active,
+	}
+}
+
+func (d Webhook) Id() int {
+	return d.id
+}
+
+func (d Webhook) Url() string {
+	return d.url
+}
+
+// Secret signs the payloads, only read when they are sent and when the webhook is created
+func (d Webhook) Secret() string {
+	return d.secret
+}
+
+func (d *Webhook) SetSecret(secret string) {
+	d.secret = secret
+}
+
+func (d Webhook) EventTypes() []string {
+	return d.eventTypes
+}
+
+func (d Webhook) Active() bool {
+	return d.active
+}
+
+// Failures is the number of deliveries that have failed in a row, after all their attempts
+func (d Webhook) Failures() int {
+	return d.failures
+}
+
+func (d *Webhook) SetFailures(failures int) {
+	d.failures = failures
+}
+
+func (d Webhook) CreatedAt() time.Time {
+	return d.createdAt
+}
+
+func (d *Webhook) SetCreatedAt(createdAt time.Time) {
+	d.createdAt = createdAt
+}
+
+// ChangeEventTypes are the webhook event types of the change, task.finished being added to the updates that finish a
+// task
+func ChangeEventTypes(event ChangeEvent) []string {
+	if event.FinishesTask() {
+		return []string{event.Type(), WebhookTaskFinished}
+	}
+	return []string{event.Type()}
+}
+
+// WebhookDelivery is an attempt to post an event to a webhook, kept so that the account can see why it has failed
+type WebhookDelivery struct {
+	id             int64
+	webhookId      int
+	eventId        int64
+	eventType      string
+	attempt        int
+	requestBody    string
+	responseStatus int
+	errorMessage   string
+	duration       time.Duration
+	createdAt      time.Time
+}
+
+func NewWebhookDelivery(webhookId int, eventId int64, eventType string, attempt int,
+	requestBody string) *WebhookDelivery {
+	return &WebhookDelivery{
+		webhookId:   webhookId,
+		eventId:     eventId,
+		eventType:   eventType,
+		attempt:     attempt,
+		requestBody: requestBody,
+		createdAt:   time.Now(),
+	}
+}
+
+func (d WebhookDelivery) Id() int64 {
+	return d.id
+}
+
+func (d *WebhookDelivery) SetId(id int64) {
+	d.id = id
+}
+
+func (d WebhookDelivery) WebhookId() int {
+	return d.webhookId
+}
+
+func (d WebhookDelivery) EventId() int64 {
+	return d.eventId
+}
+
+func (d WebhookDelivery) EventType() string {
+	return d.eventType
+}
+
+func (d WebhookDelivery) Attempt() int {
+	return d.attempt
+}
+
+func (d WebhookDelivery) RequestBody() string {
+	return d.requestBody
+}
+
+// ResponseStatus is the HTTP status of the response, zero when none has been received
+func (d WebhookDelivery) ResponseStatus() int {
+	return d.responseStatus
+}
+
+// ErrorMessage tells why no response has been received
+func (d WebhookDelivery) ErrorMessage() string {
+	return d.errorMessage
+}
+
+func (d WebhookDelivery) Duration() time.Duration {
+	return d.duration
+}
+
+// SetResult keeps the outcome of the attempt, with the error that prevented a response from being received
+func (d *WebhookDelivery) SetResult(responseStatus int, errorMessage string, duration time.Duration) {
+	d.responseStatus = responseStatus
+	d.errorMessage = errorMessage
+	d.duration = duration
+}
+
+func (d WebhookDelivery) CreatedAt() time.Time {
+	return d.createdAt
+}
+
+func (d *WebhookDelivery) SetCreatedAt(createdAt time.Time) {
+	d.createdAt = createdAt
+}
+
+// Succeeded reports whether the webhook has answered with a 2xx status
+func (d WebhookDelivery) Succeeded() bool {
+	return d.errorMessage == "" && d.responseStatus >= 200 && d.responseStatus < 300
+}
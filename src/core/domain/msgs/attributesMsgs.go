@@ -1,7 +1,33 @@
 package msgs
 
 const (
-	AccountEmail    = "Account Email"
-	AccountPassword = "Account Password"
-	CollectionName  = "Collection Name"
+	AccountEmail        = "Account Email"
+	AccountPassword     = "Account Password"
+	CollectionName      = "Collection Name"
+	TaskTags            = "Task Tags"
+	TaskBatchOperations = "Operations"
+	PaginationLimit     = "Limit"
+	PaginationOffset    = "Offset"
+	PaginationSort      = "Sort"
+	PaginationOrder     = "Order"
+	FilterCollection    = "Collection ID"
+	FilterCreatedRange  = "Created Range"
+	IdempotencyKey      = "Idempotency Key"
+	Expansion           = "Expand"
+	TransferRows        = "Rows"
+	TransferCollection  = "Collection %d"
+	TransferTask        = "Task %d"
+	CalendarObjectName  = "Object Name"
+	CalendarObjectUid   = "UID"
+	WebhookUrl          = "Webhook URL"
+	WebhookEventTypes   = "Webhook Event Types"
+	AutomationName      = "Automation Name"
+	AutomationTrigger   = "Automation Trigger"
+	AutomationCondition = "Automation Condition"
+	AutomationActions   = "Automation Actions"
+	AutomationAction    = "Action %d"
+	ReminderSchedule    = "Reminder"
+	RemindAt            = "Remind At"
+	MinutesBeforeDue    = "Minutes Before Due"
+	JobStatus           = "Status"
 )
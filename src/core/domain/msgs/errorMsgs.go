@@ -1,9 +1,52 @@
 package msgs
 
 const (
-	InvalidAccountDetails    = "Invalid account details."
-	InvalidCollectionDetails = "Invalid collection details."
-	InvalidAccountEmail      = "The email provided is invalid."
-	InvalidAccountPassword   = "The password provided is invalid. The password must be between 8 and 50 characters."
-	InvalidCollectionName    = "The name provided is invalid."
+	InvalidAccountDetails                = "Invalid account details."
+	InvalidCollectionDetails             = "Invalid collection details."
+	InvalidTaskDetails                   = "Invalid task details."
+	InvalidTaskBatchDetails              = "Invalid task batch details."
+	InvalidPaginationDetails             = "Invalid pagination details."
+	InvalidFilterDetails                 = "Invalid filter details."
+	InvalidIdempotencyDetails            = "Invalid idempotency details."
+	InvalidExpansionDetails              = "Invalid expansion details."
+	InvalidTransferDetails               = "Invalid import details."
+	InvalidCalendarObjectDetails         = "Invalid calendar object details."
+	InvalidWebhookDetails                = "Invalid webhook details."
+	InvalidAutomationDetails             = "Invalid automation details."
+	InvalidReminderDetails               = "Invalid reminder details."
+	InvalidAccountEmail                  = "The email provided is invalid."
+	InvalidAccountPassword               = "The password provided is invalid. The password must be between 8 and 50 characters."
+	InvalidCollectionName                = "The name provided is invalid."
+	InvalidTaskBatchSize                 = "The batch must have between 1 and %d operations."
+	InvalidTaskTag                       = "The tags provided are invalid. Each tag must have between 1 and 30 characters and no spaces."
+	InvalidPaginationLimit               = "The limit provided is invalid. The limit must be between 1 and 100."
+	InvalidPaginationOffset              = "The offset provided is invalid. The offset must not be negative."
+	InvalidPaginationSort                = "The sort field provided is invalid. The allowed fields are: "
+	InvalidPaginationOrder               = "The order provided is invalid. The order must be asc or desc."
+	InvalidIdempotencyKey                = "The idempotency key provided is invalid. The key must have between 1 and 255 characters."
+	InvalidExpansion                     = "The expand value provided is invalid. The allowed values are: "
+	InvalidFilterCollection              = "The collection provided is invalid."
+	InvalidFilterCreatedRange            = "The created range provided is invalid. The start must not be after the end."
+	InvalidTransferSize                  = "The file must have at most %d collections and tasks."
+	InvalidTransferId                    = "The ID provided is invalid. The ID must be a positive integer."
+	DuplicatedTransferId                 = "The ID provided is repeated in the file."
+	InvalidTransferCollectionName        = "The name provided is invalid. The name must have between 1 and %d characters."
+	InvalidTransferTaskDescription       = "The description provided is invalid. The description must have between 1 and %d characters."
+	InvalidTransferTaskCollection        = "The collection provided is not in the file."
+	InvalidCalendarObjectName            = "The object name provided is invalid. The name must have between 1 and %d characters and no slashes."
+	InvalidCalendarObjectUid             = "The UID provided is invalid. The UID must have between 1 and %d characters."
+	InvalidWebhookUrl                    = "The URL provided is invalid. The URL must be an absolute http or https URL with at most %d characters."
+	PrivateWebhookUrl                    = "The URL provided is invalid. The webhooks can't be posted to private, loopback or link-local hosts."
+	InvalidWebhookEventType              = "The event types provided are invalid. At least one is required, and the allowed types are: "
+	InvalidAutomationName                = "The name provided is invalid. The name must have between 1 and %d characters."
+	InvalidAutomationTrigger             = "The trigger provided is invalid. The allowed triggers are: "
+	InvalidAutomationCollectionCondition = "The condition provided is invalid. The conditions of the collection triggers can only compare the collection and the text of its name."
+	InvalidAutomationActions             = "The actions provided are invalid. The automation must have between 1 and %d actions."
+	InvalidAutomationActionKind          = "The action type provided is invalid. The allowed types for the trigger are: "
+	InvalidAutomationCollection          = "The collection provided is invalid. The collection ID must be a positive integer."
+	InvalidAutomationText                = "The text provided is invalid. The text must have between 1 and %d characters."
+	InvalidReminderSchedule              = "The reminder must have either a time or a number of minutes before the due date, but not both."
+	InvalidReminderTime                  = "The time provided is invalid. The time must be in the future."
+	InvalidReminderMinutesBeforeDue      = "The number of minutes provided is invalid. The number must be between 0 and %d."
+	InvalidJobStatus                     = "The status provided is invalid. The allowed statuses are: "
 )
@@ -0,0 +1,116 @@
+package events
+
+import (
+	"github.com/labstack/gommon/log"
+	"reflect"
+	"sync"
+	"todo/src/core/domain"
+	interfaces "todo/src/core/interfaces/events"
+)
+
+// Mode tells whether a subscriber is called before the service returns or in the background
+type Mode int
+
+const (
+	// Sync subscribers are called one after the other, before the service returns
+	Sync Mode = iota
+	// Async subscribers are each called in their own goroutine, in no given order
+	Async
+)
+
+// AllEvents subscribes to the events of every name
+const AllEvents = ""
+
+// SubscriberFunc lets a function be subscribed as it is
+type SubscriberFunc func(event domain.DomainEvent) error
+
+func (f SubscriberFunc) Handle(event domain.DomainEvent) error {
+	return f(event)
+}
+
+type subscription struct {
+	subscriber interfaces.ISubscriber
+	mode       Mode
+}
+
+// Bus hands each domain event to the subscribers of its name and to the ones of every event. A subscriber that
+// fails or panics is logged without affecting the service nor the other subscribers.
+type Bus struct {
+	mutex         sync.RWMutex
+	subscriptions map[string][]subscription
+	running       sync.WaitGroup
+}
+
+func NewBus() *Bus {
+	return &Bus{subscriptions: map[string][]subscription{}}
+}
+
+// Subscribe registers the subscriber to the events with the name, or to every event with AllEvents
+func (b *Bus) Subscribe(name string, mode Mode, subscriber interfaces.ISubscriber) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.subscriptions[name] = append(b.subscriptions[name], subscription{subscriber, mode})
+}
+
+// On subscribes the handler to the events of the type E, such as domain.TaskFinished
+func On[E domain.DomainEvent](bus *Bus, mode Mode, handler func(event E) error) {
+	bus.Subscribe(AllEvents, mode, SubscriberFunc(func(event domain.DomainEvent) error {
+		typedEvent, ok := event.(E)
+		if !ok {
+			return nil
+		}
+		return handler(typedEvent)
+	}))
+}
+
+func (b *Bus) Emit(event domain.DomainEvent) {
+	b.EmitExcept(event, nil)
+}
+
+// EmitExcept hands the event to the subscribers other than the one given, which handles the event by itself
+func (b *Bus) EmitExcept(event domain.DomainEvent, skipped interfaces.ISubscriber) {
+	b.mutex.RLock()
+	subscriptions := append(append([]subscription{}, b.subscriptions[event.Name()]...),
+		b.subscriptions[AllEvents]...)
+	b.mutex.RUnlock()
+
+	for _, subscription := range subscriptions {
+		if sameSubscriber(subscription.subscriber, skipped) {
+			continue
+		}
+		if subscription.mode == Async {
+			b.running.Add(1)
+			go func() {
+				defer b.running.Done()
+				b.call(subscription.subscriber, event)
+			}()
+			continue
+		}
+		b.call(subscription.subscriber, event)
+	}
+}
+
+// sameSubscriber compares the subscribers only when they can be compared, as the ones of SubscriberFunc can't
+func sameSubscriber(subscriber, otherSubscriber interfaces.ISubscriber) bool {
+	subscriberType := reflect.TypeOf(subscriber)
+	return subscriberType == reflect.TypeOf(otherSubscriber) && subscriberType.Comparable() &&
+		subscriber == otherSubscriber
+}
+
+// Wait blocks until the async subscribers called so far have returned
+func (b *Bus) Wait() {
+	b.running.Wait()
+}
+
+func (b *Bus) call(subscriber interfaces.ISubscriber, event domain.DomainEvent) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			log.Errorf("The subscriber of %s panicked: %v", event.Name(), recovered)
+		}
+	}()
+
+	if err := subscriber.Handle(event); err != nil {
+		log.Errorf("The subscriber of %s failed: %v", event.Name(), err)
+	}
+}
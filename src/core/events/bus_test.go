@@ -0,0 +1,114 @@
+package events
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"todo/src/core/domain"
+)
+
+type countingSubscriber struct {
+	handled int
+}
+
+func (s *countingSubscriber) Handle(domain.DomainEvent) error {
+	s.handled++
+	return nil
+}
+
+func TestBus(t *testing.T) {
+	task := *domain.NewTask(5, "Buy milk", true, nil)
+
+	t.Run("should hand the event to the subscribers of its name and of every event", func(t *testing.T) {
+		bus := NewBus()
+		var received []string
+		bus.Subscribe(domain.EventTaskFinished, Sync, SubscriberFunc(func(event domain.DomainEvent) error {
+			received = append(received, "finished:"+event.Name())
+			return nil
+		}))
+		bus.Subscribe(domain.EventTaskCreated, Sync, SubscriberFunc(func(event domain.DomainEvent) error {
+			received = append(received, "created:"+event.Name())
+			return nil
+		}))
+		bus.Subscribe(AllEvents, Sync, SubscriberFunc(func(event domain.DomainEvent) error {
+			received = append(received, "all:"+event.Name())
+			return nil
+		}))
+
+		bus.Emit(*domain.NewTaskFinished(1, task))
+
+		assert.Equal(t, []string{"finished:task.finished", "all:task.finished"}, received)
+	})
+
+	t.Run("should hand only the events of its type to a typed subscriber", func(t *testing.T) {
+		bus := NewBus()
+		var finishedTasks []int
+		On(bus, Sync, func(event domain.TaskFinished) error {
+			finishedTasks = append(finishedTasks, event.Task().Id())
+			return nil
+		})
+
+		bus.Emit(*domain.NewTaskCreated(1, task))
+		bus.Emit(*domain.NewTaskFinished(1, task))
+
+		assert.Equal(t, []int{5}, finishedTasks)
+	})
+
+	t.Run("should keep calling the subscribers after one has failed or panicked", func(t *testing.T) {
+		bus := NewBus()
+		called := false
+		bus.Subscribe(AllEvents, Sync, SubscriberFunc(func(domain.DomainEvent) error {
+			return errors.New("unavailable")
+		}))
+		bus.Subscribe(AllEvents, Sync, SubscriberFunc(func(domain.DomainEvent) error {
+			panic("broken")
+		}))
+		bus.Subscribe(AllEvents, Sync, SubscriberFunc(func(domain.DomainEvent) error {
+			called = true
+			return nil
+		}))
+
+		assert.NotPanics(t, func() {
+			bus.Emit(*domain.NewAccountSignedUp(*domain.NewAccount(3, "Ann", "ann@example.com", "hash", "")))
+		})
+		assert.True(t, called)
+	})
+
+	t.Run("should call the async subscribers in the background", func(t *testing.T) {
+		bus := NewBus()
+		var mutex sync.Mutex
+		var accounts []domain.Account
+		On(bus, Async, func(event domain.AccountSignedUp) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+			accounts = append(accounts, event.Account())
+			return nil
+		})
+
+		bus.Emit(*domain.NewAccountSignedUp(*domain.NewAccount(3, "Ann", "ann@example.com", "hash", "")))
+		bus.Wait()
+
+		assert.Len(t, accounts, 1)
+		assert.Equal(t, 3, accounts[0].Id())
+		assert.Empty(t, accounts[0].Password())
+	})
+
+	t.Run("should hand the event to every subscriber but the one skipped", func(t *testing.T) {
+		bus := NewBus()
+		skipped, other := &countingSubscriber{}, &countingSubscriber{}
+		called := false
+		bus.Subscribe(AllEvents, Sync, skipped)
+		bus.Subscribe(AllEvents, Sync, other)
+		bus.Subscribe(AllEvents, Sync, SubscriberFunc(func(domain.DomainEvent) error {
+			called = true
+			return nil
+		}))
+
+		bus.EmitExcept(*domain.NewTaskCreated(1, task), skipped)
+
+		assert.Equal(t, 0, skipped.handled)
+		assert.Equal(t, 1, other.handled)
+		assert.True(t, called)
+	})
+}
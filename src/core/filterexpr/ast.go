@@ -0,0 +1,55 @@
+package filterexpr
+
+type Field string
+
+const (
+	FieldText       Field = "text"
+	FieldFinished   Field = "finished"
+	FieldTag        Field = "tag"
+	FieldCollection Field = "collection"
+	FieldDue        Field = "due"
+	FieldCreated    Field = "created"
+)
+
+type Operator string
+
+const (
+	OperatorEqual          Operator = "="
+	OperatorNotEqual       Operator = "!="
+	OperatorLess           Operator = "<"
+	OperatorLessOrEqual    Operator = "<="
+	OperatorGreater        Operator = ">"
+	OperatorGreaterOrEqual Operator = ">="
+)
+
+// Expression is a node of the syntax tree produced by Parse. Its concrete types are And, Or, Not and Comparison.
+type Expression interface {
+	expression()
+}
+
+type And struct {
+	Left  Expression
+	Right Expression
+}
+
+type Or struct {
+	Left  Expression
+	Right Expression
+}
+
+type Not struct {
+	Operand Expression
+}
+
+// Comparison holds a value already converted to the type of its field: bool for finished, string for text and tag,
+// int (ID) or string (name) for collection and *time.Time for the dates, nil standing for "none".
+type Comparison struct {
+	Field    Field
+	Operator Operator
+	Value    interface{}
+}
+
+func (And) expression()        {}
+func (Or) expression()         {}
+func (Not) expression()        {}
+func (Comparison) expression() {}
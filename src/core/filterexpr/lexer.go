@@ -0,0 +1,125 @@
+package filterexpr
+
+import (
+	"strings"
+	"todo/src/core/filterexpr/msgs"
+)
+
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenWord
+	tokenString
+	tokenOperator
+	tokenLeftParen
+	tokenRightParen
+)
+
+type token struct {
+	kind     tokenKind
+	text     string
+	position int
+}
+
+// isKeyword compares case-insensitively, but only words can be keywords: a quoted "and" is a plain value
+func (t token) isKeyword(keyword string) bool {
+	return t.kind == tokenWord && strings.EqualFold(t.text, keyword)
+}
+
+type lexer struct {
+	input    []rune
+	position int
+}
+
+func tokenize(input string) ([]token, *syntaxError) {
+	l := &lexer{input: []rune(input)}
+	var tokens []token
+	for {
+		t, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, t)
+		if t.kind == tokenEnd {
+			return tokens, nil
+		}
+	}
+}
+
+func (l *lexer) next() (token, *syntaxError) {
+	for l.position < len(l.input) && isSpace(l.input[l.position]) {
+		l.position++
+	}
+	start := l.position
+	if start >= len(l.input) {
+		return token{kind: tokenEnd, position: start + 1}, nil
+	}
+
+	current := l.input[start]
+	switch {
+	case current == '(':
+		l.position++
+		return token{kind: tokenLeftParen, text: "(", position: start + 1}, nil
+	case current == ')':
+		l.position++
+		return token{kind: tokenRightParen, text: ")", position: start + 1}, nil
+	case current == '"':
+		return l.readString()
+	case strings.ContainsRune(":=!<>", current):
+		return l.readOperator()
+	case isWordRune(current):
+		for l.position < len(l.input) && isWordRune(l.input[l.position]) {
+			l.position++
+		}
+		return token{kind: tokenWord, text: string(l.input[start:l.position]), position: start + 1}, nil
+	default:
+		return token{}, newSyntaxError(msgs.UnexpectedCharacter, string(current), start+1)
+	}
+}
+
+func (l *lexer) readString() (token, *syntaxError) {
+	start := l.position
+	var value strings.Builder
+	l.position++
+	for l.position < len(l.input) {
+		current := l.input[l.position]
+		switch {
+		case current == '\\' && l.position+1 < len(l.input):
+			value.WriteRune(l.input[l.position+1])
+			l.position += 2
+		case current == '"':
+			l.position++
+			return token{kind: tokenString, text: value.String(), position: start + 1}, nil
+		default:
+			value.WriteRune(current)
+			l.position++
+		}
+	}
+
+	return token{}, newSyntaxError(msgs.UnterminatedString, string(l.input[start:]), start+1)
+}
+
+func (l *lexer) readOperator() (token, *syntaxError) {
+	start := l.position
+	current := l.input[start]
+	l.position++
+	if current == ':' {
+		return token{kind: tokenOperator, text: ":", position: start + 1}, nil
+	}
+	if l.position < len(l.input) && l.input[l.position] == '=' {
+		l.position++
+	} else if current == '!' {
+		return token{}, newSyntaxError(msgs.UnexpectedCharacter, "!", start+1)
+	}
+
+	return token{kind: tokenOperator, text: string(l.input[start:l.position]), position: start + 1}, nil
+}
+
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r'
+}
+
+func isWordRune(r rune) bool {
+	return !isSpace(r) && !strings.ContainsRune(`():=!<>"`, r)
+}
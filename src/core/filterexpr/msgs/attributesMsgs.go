@@ -0,0 +1,5 @@
+package msgs
+
+const (
+	Filter = "Filter"
+)
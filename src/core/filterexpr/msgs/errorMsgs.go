@@ -0,0 +1,16 @@
+package msgs
+
+const (
+	InvalidFilterExpression = "The filter expression provided is invalid."
+	UnterminatedString      = "Unterminated string"
+	UnexpectedCharacter     = "Unexpected character"
+	UnexpectedToken         = "Unexpected token"
+	UnexpectedEnd           = "Unexpected end of the expression"
+	MissingClosingParen     = "Missing closing parenthesis for the one opened"
+	UnknownField            = "Unknown field"
+	UnknownOperator         = "Unknown operator"
+	UnsupportedOperator     = "Operator not supported by the field"
+	InvalidValue            = "Invalid value for the field"
+	ExpressionTooLong       = "The expression exceeds the maximum length of"
+	ExpressionTooDeep       = "The expression exceeds the maximum nesting depth"
+)
@@ -0,0 +1,345 @@
+package filterexpr
+
+import (
+	"fmt"
+	"github.com/labstack/gommon/log"
+	"strconv"
+	"strings"
+	"time"
+	"todo/src/core/filterexpr/msgs"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+const (
+	MaxExpressionLength = 500
+	maxDepth            = 20
+)
+
+var fieldsByName = map[string]Field{
+	"text":        FieldText,
+	"description": FieldText,
+	"finished":    FieldFinished,
+	"tag":         FieldTag,
+	"collection":  FieldCollection,
+	"due":         FieldDue,
+	"created":     FieldCreated,
+}
+
+// operatorsByText are the operators the language accepts, the colon being a shorthand for equality
+var operatorsByText = map[string]Operator{
+	":":  OperatorEqual,
+	"=":  OperatorEqual,
+	"!=": OperatorNotEqual,
+	"<":  OperatorLess,
+	"<=": OperatorLessOrEqual,
+	">":  OperatorGreater,
+	">=": OperatorGreaterOrEqual,
+}
+
+type syntaxError struct {
+	message  string
+	text     string
+	position int
+}
+
+func newSyntaxError(message, text string, position int) *syntaxError {
+	return &syntaxError{message, text, position}
+}
+
+func (err syntaxError) toValidationError() *todoerrors.Validation {
+	description := fmt.Sprintf("%s at position %d", err.message, err.position)
+	if err.text != "" {
+		description = fmt.Sprintf("%s at position %d: %q", err.message, err.position, err.text)
+	}
+	invalidFields := todoerrors.InvalidFields{}
+	invalidFields.AppendField(msgs.Filter, description)
+
+	return todoerrors.NewValidationError(msgs.InvalidFilterExpression, invalidFields)
+}
+
+type parser struct {
+	tokens   []token
+	position int
+	depth    int
+	now      time.Time
+}
+
+// Parse builds the syntax tree of a filter expression such as
+//
+//	(tag:work or tag:urgent) and not finished and due < +7d
+//
+// Terms written side by side are joined with "and". Relative dates are resolved against now. An empty expression
+// results in a nil tree.
+func Parse(input string, now time.Time) (Expression, *todoerrors.Validation) {
+	if strings.TrimSpace(input) == "" {
+		return nil, nil
+	}
+	if len([]rune(input)) > MaxExpressionLength {
+		return nil, newSyntaxError(fmt.Sprintf("%s %d characters", msgs.ExpressionTooLong, MaxExpressionLength),
+			"", MaxExpressionLength+1).toValidationError()
+	}
+
+	tokens, err := tokenize(input)
+	if err != nil {
+		log.Error(err.message)
+		return nil, err.toValidationError()
+	}
+	p := &parser{tokens: tokens, now: now}
+	expression, err := p.parseOr()
+	if err == nil && p.peek().kind != tokenEnd {
+		err = p.unexpected(p.peek())
+	}
+	if err != nil {
+		log.Error(err.message)
+		return nil, err.toValidationError()
+	}
+
+	return expression, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.position]
+}
+
+func (p *parser) advance() token {
+	current := p.tokens[p.position]
+	if current.kind != tokenEnd {
+		p.position++
+	}
+	return current
+}
+
+func (p *parser) parseOr() (Expression, *syntaxError) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().isKeyword("or") {
+		p.advance()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = Or{left, right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expression, *syntaxError) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		next := p.peek()
+		if next.isKeyword("and") {
+			p.advance()
+		} else if !p.startsOperand(next) {
+			return left, nil
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = And{left, right}
+	}
+}
+
+func (p *parser) startsOperand(next token) bool {
+	switch next.kind {
+	case tokenString, tokenLeftParen:
+		return true
+	case tokenWord:
+		return !next.isKeyword("or")
+	default:
+		return false
+	}
+}
+
+func (p *parser) parseNot() (Expression, *syntaxError) {
+	if !p.peek().isKeyword("not") {
+		return p.parsePrimary()
+	}
+
+	current := p.advance()
+	if err := p.enter(current); err != nil {
+		return nil, err
+	}
+	operand, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	p.depth--
+
+	return Not{operand}, nil
+}
+
+func (p *parser) parsePrimary() (Expression, *syntaxError) {
+	current := p.advance()
+	switch current.kind {
+	case tokenLeftParen:
+		if err := p.enter(current); err != nil {
+			return nil, err
+		}
+		expression, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != tokenRightParen {
+			if p.peek().kind == tokenEnd {
+				return nil, newSyntaxError(msgs.MissingClosingParen, "(", current.position)
+			}
+			return nil, p.unexpected(p.peek())
+		}
+		p.advance()
+		p.depth--
+		return expression, nil
+	case tokenString:
+		return Comparison{FieldText, OperatorEqual, current.text}, nil
+	case tokenWord:
+		if current.isKeyword("and") || current.isKeyword("or") {
+			return nil, p.unexpected(current)
+		}
+		return p.parseComparison(current)
+	default:
+		return nil, p.unexpected(current)
+	}
+}
+
+// parseComparison reads "field operator value". A word without an operator is either the boolean field finished
+// or a search on the task description.
+func (p *parser) parseComparison(name token) (Expression, *syntaxError) {
+	if p.peek().kind != tokenOperator {
+		if field, found := fieldsByName[strings.ToLower(name.text)]; found && field == FieldFinished {
+			return Comparison{FieldFinished, OperatorEqual, true}, nil
+		}
+		return Comparison{FieldText, OperatorEqual, name.text}, nil
+	}
+
+	field, found := fieldsByName[strings.ToLower(name.text)]
+	if !found {
+		return nil, newSyntaxError(msgs.UnknownField, name.text, name.position)
+	}
+	operator := p.advance()
+	value := p.advance()
+	if value.kind == tokenEnd {
+		return nil, newSyntaxError(msgs.UnexpectedEnd, "", value.position)
+	}
+	if value.kind != tokenWord && value.kind != tokenString {
+		return nil, p.unexpected(value)
+	}
+
+	parsedOperator, found := operatorsByText[operator.text]
+	if !found {
+		return nil, newSyntaxError(msgs.UnknownOperator, operator.text, operator.position)
+	}
+	return p.convert(field, parsedOperator, operator, value)
+}
+
+func (p *parser) convert(field Field, operator Operator, operatorToken, value token) (Expression, *syntaxError) {
+	isEquality := operator == OperatorEqual || operator == OperatorNotEqual
+	if !isEquality && field != FieldDue && field != FieldCreated {
+		return nil, newSyntaxError(msgs.UnsupportedOperator, operatorToken.text, operatorToken.position)
+	}
+	invalidValue := newSyntaxError(msgs.InvalidValue, value.text, value.position)
+
+	switch field {
+	case FieldFinished:
+		finished, err := strconv.ParseBool(strings.ToLower(value.text))
+		if err != nil {
+			return nil, invalidValue
+		}
+		return Comparison{field, operator, finished}, nil
+	case FieldTag:
+		tag := strings.ToLower(strings.TrimSpace(value.text))
+		if tag == "" {
+			return nil, invalidValue
+		}
+		return Comparison{field, operator, tag}, nil
+	case FieldCollection:
+		if id, err := strconv.Atoi(value.text); err == nil && value.kind == tokenWord {
+			return Comparison{field, operator, id}, nil
+		}
+		return Comparison{field, operator, value.text}, nil
+	case FieldDue, FieldCreated:
+		lowerValue := strings.ToLower(value.text)
+		if lowerValue == "none" || lowerValue == "null" {
+			if !isEquality {
+				return nil, newSyntaxError(msgs.UnsupportedOperator, operatorToken.text, operatorToken.position)
+			}
+			return Comparison{field, operator, nil}, nil
+		}
+		date, found := p.parseDate(value.text)
+		if !found {
+			return nil, invalidValue
+		}
+		return Comparison{field, operator, date}, nil
+	default:
+		return Comparison{field, operator, value.text}, nil
+	}
+}
+
+// parseDate accepts the words now, today, tomorrow and yesterday, offsets from now such as +7d, -12h, +30m or +2w,
+// dates (2006-01-02) and RFC 3339 timestamps
+func (p *parser) parseDate(value string) (*time.Time, bool) {
+	today := time.Date(p.now.Year(), p.now.Month(), p.now.Day(), 0, 0, 0, 0, p.now.Location())
+	var date time.Time
+	switch strings.ToLower(value) {
+	case "now":
+		date = p.now
+	case "today":
+		date = today
+	case "tomorrow":
+		date = today.AddDate(0, 0, 1)
+	case "yesterday":
+		date = today.AddDate(0, 0, -1)
+	default:
+		if offset, found := parseOffset(value); found {
+			date = p.now.Add(offset)
+		} else if parsedDate, err := time.Parse("2006-01-02", value); err == nil {
+			date = parsedDate
+		} else if parsedDate, err = time.Parse(time.RFC3339, value); err == nil {
+			date = parsedDate
+		} else {
+			return nil, false
+		}
+	}
+
+	return &date, true
+}
+
+func parseOffset(value string) (time.Duration, bool) {
+	if len(value) < 3 || (value[0] != '+' && value[0] != '-') {
+		return 0, false
+	}
+	amount, err := strconv.Atoi(value[1 : len(value)-1])
+	if err != nil || amount < 0 {
+		return 0, false
+	}
+	units := map[byte]time.Duration{'m': time.Minute, 'h': time.Hour, 'd': 24 * time.Hour, 'w': 7 * 24 * time.Hour}
+	unit, found := units[value[len(value)-1]]
+	if !found {
+		return 0, false
+	}
+	if value[0] == '-' {
+		amount = -amount
+	}
+
+	return time.Duration(amount) * unit, true
+}
+
+func (p *parser) enter(current token) *syntaxError {
+	p.depth++
+	if p.depth > maxDepth {
+		return newSyntaxError(msgs.ExpressionTooDeep, current.text, current.position)
+	}
+	return nil
+}
+
+func (p *parser) unexpected(current token) *syntaxError {
+	if current.kind == tokenEnd {
+		return newSyntaxError(msgs.UnexpectedEnd, "", current.position)
+	}
+	return newSyntaxError(msgs.UnexpectedToken, current.text, current.position)
+}
@@ -0,0 +1,90 @@
+package filterexpr
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+var now = time.Date(2024, 1, 10, 15, 30, 0, 0, time.UTC)
+
+func TestParse(t *testing.T) {
+	t.Run("should build the tree respecting precedence and parentheses", func(t *testing.T) {
+		expression, err := Parse("(tag:work or tag:Urgent) and not finished and due < +7d", now)
+
+		dueLimit := now.Add(7 * 24 * time.Hour)
+		expected := And{
+			And{
+				Or{Comparison{FieldTag, OperatorEqual, "work"}, Comparison{FieldTag, OperatorEqual, "urgent"}},
+				Not{Comparison{FieldFinished, OperatorEqual, true}},
+			},
+			Comparison{FieldDue, OperatorLess, &dueLimit},
+		}
+
+		assert.Nil(t, err)
+		assert.Equal(t, expected, expression)
+	})
+
+	t.Run("should join terms written side by side with and", func(t *testing.T) {
+		expression, err := Parse(`"buy milk" collection:3 collection!=Work`, now)
+
+		expected := And{
+			And{Comparison{FieldText, OperatorEqual, "buy milk"}, Comparison{FieldCollection, OperatorEqual, 3}},
+			Comparison{FieldCollection, OperatorNotEqual, "Work"},
+		}
+
+		assert.Nil(t, err)
+		assert.Equal(t, expected, expression)
+	})
+
+	t.Run("should resolve absolute, named and empty dates", func(t *testing.T) {
+		expression, err := Parse("created>=2024-01-01 and created<today or due:none", now)
+
+		from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		today := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+		expected := Or{
+			And{
+				Comparison{FieldCreated, OperatorGreaterOrEqual, &from},
+				Comparison{FieldCreated, OperatorLess, &today},
+			},
+			Comparison{FieldDue, OperatorEqual, nil},
+		}
+
+		assert.Nil(t, err)
+		assert.Equal(t, expected, expression)
+	})
+
+	t.Run("should return nil when the expression is empty", func(t *testing.T) {
+		expression, err := Parse("   ", now)
+
+		assert.Nil(t, err)
+		assert.Nil(t, expression)
+	})
+
+	t.Run("should point at the offending token when the expression is invalid", func(t *testing.T) {
+		testCases := map[string]string{
+			"tag:work or":             "Unexpected end of the expression at position 12",
+			"(tag:work or tag:urgent": "Missing closing parenthesis for the one opened at position 1: \"(\"",
+			"tag:work )":              "Unexpected token at position 10: \")\"",
+			"owner:me":                "Unknown field at position 1: \"owner\"",
+			"tag > work":              "Operator not supported by the field at position 5: \">\"",
+			"due < soon":              "Invalid value for the field at position 7: \"soon\"",
+			"finished:maybe":          "Invalid value for the field at position 10: \"maybe\"",
+			"tag:\"work":              "Unterminated string at position 5: \"\\\"work\"",
+			"tag ! work":              "Unexpected character at position 5: \"!\"",
+			"due==today":              "Unknown operator at position 4: \"==\"",
+			"created==now":            "Unknown operator at position 8: \"==\"",
+		}
+
+		for input, expectedDescription := range testCases {
+			expression, err := Parse(input, now)
+
+			assert.Nil(t, expression, input)
+			if assert.NotNil(t, err, input) {
+				assert.Equal(t, "The filter expression provided is invalid.", err.Error())
+				assert.Equal(t, "Filter", err.InvalidFields().Fields()[0].Name())
+				assert.Equal(t, expectedDescription, err.InvalidFields().Fields()[0].Description(), input)
+			}
+		}
+	})
+}
@@ -0,0 +1,275 @@
+package icalendar
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/labstack/gommon/log"
+	"io"
+	"strings"
+	"time"
+	"todo/src/core/domain"
+	"todo/src/core/icalendar/msgs"
+	"todo/src/core/projecterrors/todoerrors"
+	"unicode/utf8"
+)
+
+const maxLineLength = 1024 * 1024
+
+var textUnescaper = strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+
+type contentLine struct {
+	name       string
+	parameters map[string]string
+	value      string
+}
+
+// Decode reads the VTODO components of an RFC 5545 calendar as tasks without ID nor collection. Every invalid
+// to-do is reported, named by its position in the calendar. The recurring to-dos are read as their first occurrence,
+// as tasks have no recurrence, which the warnings returned tell.
+func Decode(reader io.Reader) ([]domain.Task, []string, *todoerrors.Validation) {
+	objects, warnings, err := decodeObjects(reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tasks := make([]domain.Task, len(objects))
+	for index, object := range objects {
+		tasks[index] = *object.Task()
+	}
+
+	return tasks, warnings, nil
+}
+
+// DecodeObject reads a CalDAV calendar object, which must have a single VTODO, as an unnamed object with its UID
+func DecodeObject(reader io.Reader) (*domain.CalendarObject, []string, *todoerrors.Validation) {
+	objects, warnings, err := decodeObjects(reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(objects) != 1 {
+		return nil, nil, newCalendarError(msgs.Calendar, msgs.SingleTodo)
+	}
+
+	return &objects[0], warnings, nil
+}
+
+func decodeObjects(reader io.Reader) ([]domain.CalendarObject, []string, *todoerrors.Validation) {
+	lines, err := unfoldLines(reader)
+	if err != nil {
+		return nil, nil, newCalendarError(msgs.Calendar, err.Error())
+	}
+
+	var objects []domain.CalendarObject
+	var warnings []string
+	invalidFields := todoerrors.InvalidFields{}
+	var components []string
+	var todo []contentLine
+	foundCalendar := false
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		parsedLine, ok := parseContentLine(line)
+		if !ok {
+			return nil, nil, newCalendarError(msgs.Calendar, msgs.InvalidContentLine+line)
+		}
+
+		switch parsedLine.name {
+		case "BEGIN":
+			component := strings.ToUpper(parsedLine.value)
+			components = append(components, component)
+			if component == "VCALENDAR" {
+				foundCalendar = true
+			} else if component == "VTODO" && len(components) == 2 {
+				todo = []contentLine{}
+			}
+		case "END":
+			if len(components) == 0 || components[len(components)-1] != strings.ToUpper(parsedLine.value) {
+				return nil, nil, newCalendarError(msgs.Calendar, msgs.InvalidContentLine+line)
+			}
+			if len(components) == 2 && components[1] == "VTODO" {
+				row := fmt.Sprintf(msgs.Todo, len(objects)+1)
+				objects = append(objects, *newObject(todo, row, &invalidFields, &warnings))
+				todo = nil
+			}
+			components = components[:len(components)-1]
+		default:
+			// Only the properties of the to-do itself are read, not those of the alarms inside it
+			if len(components) == 2 && components[1] == "VTODO" {
+				todo = append(todo, parsedLine)
+			}
+		}
+	}
+
+	if !foundCalendar {
+		return nil, nil, newCalendarError(msgs.Calendar, msgs.MissingCalendar)
+	}
+	if len(components) > 0 {
+		return nil, nil, newCalendarError(msgs.Calendar, msgs.UnterminatedComponent+components[len(components)-1])
+	}
+	if len(objects) > domain.MaxTransferSize {
+		return nil, nil, newCalendarError(msgs.Calendar, fmt.Sprintf(msgs.TooManyTodos, domain.MaxTransferSize))
+	}
+	if invalidFields.HasInvalidFields() {
+		log.Error(msgs.InvalidCalendar)
+		return nil, nil, todoerrors.NewValidationError(msgs.InvalidCalendar, invalidFields)
+	}
+
+	return objects, warnings, nil
+}
+
+// newObject reads a recurring to-do as its first occurrence, whose due date is the DUE of the to-do, dropping its
+// RRULE with a warning
+func newObject(todo []contentLine, row string, invalidFields *todoerrors.InvalidFields,
+	warnings *[]string) *domain.CalendarObject {
+	uid := ""
+	summary := ""
+	finished := false
+	var dueDate *time.Time
+	var categories []string
+	recurring := false
+	for _, property := range todo {
+		switch property.name {
+		case "UID":
+			uid = strings.TrimSpace(property.value)
+		case "SUMMARY":
+			summary = strings.TrimSpace(unescapeText(property.value))
+		case "STATUS":
+			finished = finished || strings.EqualFold(property.value, "COMPLETED")
+		case "COMPLETED":
+			finished = true
+		case "DUE":
+			due, err := parseDateTime(property)
+			if err != nil {
+				invalidFields.AppendField(row, msgs.InvalidDue)
+				continue
+			}
+			dueDate = due
+		case "RRULE":
+			recurring = true
+		case "CATEGORIES":
+			for _, category := range splitText(property.value) {
+				categories = append(categories, unescapeText(category))
+			}
+		}
+	}
+
+	if summary == "" {
+		invalidFields.AppendField(row, msgs.MissingSummary)
+	} else if utf8.RuneCountInString(summary) > domain.MaxTaskDescriptionLength {
+		invalidFields.AppendField(row, fmt.Sprintf(msgs.InvalidSummary, domain.MaxTaskDescriptionLength))
+	}
+	if recurring {
+		*warnings = append(*warnings, row+": "+msgs.RecurringTodo)
+	}
+	tags, validationErr := domain.NewValidatedTags(categories)
+	if validationErr != nil {
+		invalidFields.AppendField(row, validationErr.InvalidFields().Fields()[0].Description())
+	}
+
+	task := domain.NewTask(0, summary, finished, domain.NewCollection(0, ""))
+	task.SetDueDate(dueDate)
+	task.SetTags(tags)
+
+	return domain.NewCalendarObject("", uid, task)
+}
+
+// unfoldLines joins the lines continued with a leading space or tab
+func unfoldLines(reader io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineLength)
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, scanner.Err()
+}
+
+// parseContentLine splits a line in its name, parameters and value, the colons of quoted parameters not ending
+// the name
+func parseContentLine(line string) (contentLine, bool) {
+	quoted := false
+	separator := -1
+	for index, character := range line {
+		if character == '"' {
+			quoted = !quoted
+		} else if character == ':' && !quoted {
+			separator = index
+			break
+		}
+	}
+	if separator <= 0 {
+		return contentLine{}, false
+	}
+
+	parts := strings.Split(line[:separator], ";")
+	parameters := map[string]string{}
+	for _, parameter := range parts[1:] {
+		name, value, _ := strings.Cut(parameter, "=")
+		parameters[strings.ToUpper(name)] = strings.Trim(value, `"`)
+	}
+
+	return contentLine{
+		name:       strings.ToUpper(parts[0]),
+		parameters: parameters,
+		value:      line[separator+1:],
+	}, true
+}
+
+// parseDateTime reads dates, UTC date-times and local date-times, in their TZID time zone when it is known and in
+// UTC otherwise
+func parseDateTime(property contentLine) (*time.Time, error) {
+	value := strings.TrimSpace(property.value)
+	var parsed time.Time
+	var err error
+	if strings.EqualFold(property.parameters["VALUE"], "DATE") || len(value) == len(date) {
+		parsed, err = time.Parse(date, value)
+	} else if strings.HasSuffix(value, "Z") {
+		parsed, err = time.Parse(utcDateTime, value)
+	} else {
+		location := time.UTC
+		if zone, zoneErr := time.LoadLocation(property.parameters["TZID"]); zoneErr == nil {
+			location = zone
+		}
+		parsed, err = time.ParseInLocation(localDateTime, value, location)
+	}
+	if err != nil {
+		return nil, err
+	}
+	parsed = parsed.UTC()
+
+	return &parsed, nil
+}
+
+// splitText splits a list of text values on the commas that are not escaped
+func splitText(value string) []string {
+	var values []string
+	start := 0
+	for index := 0; index < len(value); index++ {
+		if value[index] == '\\' {
+			index++
+		} else if value[index] == ',' {
+			values = append(values, value[start:index])
+			start = index + 1
+		}
+	}
+
+	return append(values, value[start:])
+}
+
+func unescapeText(text string) string {
+	return textUnescaper.Replace(text)
+}
+
+func newCalendarError(field, description string) *todoerrors.Validation {
+	log.Error(description)
+	invalidFields := todoerrors.InvalidFields{}
+	invalidFields.AppendField(field, description)
+	return todoerrors.NewValidationError(msgs.InvalidCalendar, invalidFields)
+}
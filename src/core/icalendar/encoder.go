@@ -0,0 +1,135 @@
+package icalendar
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"todo/src/core/domain"
+	"unicode/utf8"
+)
+
+const (
+	productId      = "-//todo-rest-api//Tasks//EN"
+	maxLineOctets  = 75
+	utcDateTime    = "20060102T150405Z"
+	localDateTime  = "20060102T150405"
+	date           = "20060102"
+	lineTerminator = "\r\n"
+)
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// Encoder writes tasks as the VTODO components of an RFC 5545 calendar, one at a time so that it can be streamed
+type Encoder struct {
+	writer io.Writer
+	stamp  time.Time
+}
+
+func NewEncoder(writer io.Writer) *Encoder {
+	return &Encoder{writer: writer, stamp: time.Now().UTC()}
+}
+
+func (e Encoder) Begin(name string) error {
+	return e.writeLines(
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:"+productId,
+		"CALSCALE:GREGORIAN",
+		"X-WR-CALNAME:"+escapeText(name),
+	)
+}
+
+// Encode writes the task as a VTODO. The tasks have no recurrence, so no RRULE is written, and the decoder reads the
+// to-dos that have one as their first occurrence.
+func (e Encoder) Encode(task domain.Task) error {
+	return e.EncodeObject(*domain.NewCalendarObject("", "", &task))
+}
+
+// EncodeObject writes the task of the object as a VTODO with the UID of the object, or with the UID derived from the
+// task ID when the object has none
+func (e Encoder) EncodeObject(object domain.CalendarObject) error {
+	task := object.Task()
+	uid := object.Uid()
+	if uid == "" {
+		uid = TaskUid(task.Id())
+	}
+	status := "NEEDS-ACTION"
+	if task.Finished() {
+		status = "COMPLETED"
+	}
+	lines := []string{
+		"BEGIN:VTODO",
+		"UID:" + uid,
+		"DTSTAMP:" + e.stamp.Format(utcDateTime),
+		"SUMMARY:" + escapeText(task.Description()),
+		"STATUS:" + status,
+	}
+	if !task.CreatedAt().IsZero() {
+		lines = append(lines, "CREATED:"+task.CreatedAt().UTC().Format(utcDateTime))
+	}
+	if task.DueDate() != nil {
+		lines = append(lines, "DUE:"+task.DueDate().UTC().Format(utcDateTime))
+	}
+	if len(task.Tags()) > 0 {
+		categories := make([]string, len(task.Tags()))
+		for index, tag := range task.Tags() {
+			categories[index] = escapeText(tag)
+		}
+		lines = append(lines, "CATEGORIES:"+strings.Join(categories, ","))
+	}
+	if task.Collection() != nil && task.Collection().Name() != "" {
+		lines = append(lines, "X-TODO-COLLECTION:"+escapeText(task.Collection().Name()))
+	}
+	lines = append(lines, "END:VTODO")
+
+	return e.writeLines(lines...)
+}
+
+func (e Encoder) End() error {
+	return e.writeLines("END:VCALENDAR")
+}
+
+// TaskUid identifies the task in the calendars, the task IDs being unique across accounts
+func TaskUid(taskId int) string {
+	return fmt.Sprintf("task-%d@todo-rest-api", taskId)
+}
+
+func (e Encoder) writeLines(lines ...string) error {
+	var builder strings.Builder
+	for _, line := range lines {
+		builder.WriteString(foldLine(line))
+		builder.WriteString(lineTerminator)
+	}
+	_, err := io.WriteString(e.writer, builder.String())
+
+	return err
+}
+
+func escapeText(text string) string {
+	return textEscaper.Replace(text)
+}
+
+// foldLine splits the lines longer than 75 octets, never in the middle of a UTF-8 character
+func foldLine(line string) string {
+	if len(line) <= maxLineOctets {
+		return line
+	}
+
+	var builder strings.Builder
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		builder.WriteString(line[:cut])
+		builder.WriteString(lineTerminator + " ")
+		line = line[cut:]
+		// The leading space of the continuation counts towards its length
+		limit = maxLineOctets - 1
+	}
+	builder.WriteString(line)
+
+	return builder.String()
+}
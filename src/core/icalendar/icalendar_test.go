@@ -0,0 +1,159 @@
+package icalendar
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+	"todo/src/core/domain"
+)
+
+func TestEncoder(t *testing.T) {
+	t.Run("should write the task as a VTODO with escaped text", func(t *testing.T) {
+		dueDate := time.Date(2024, 1, 31, 18, 0, 0, 0, time.UTC)
+		task := domain.NewTask(12, "Buy milk, eggs; bread", true, domain.NewCollection(3, "Home"))
+		task.SetDueDate(&dueDate)
+		task.SetTags([]string{"shopping", "urgent"})
+		var output bytes.Buffer
+		encoder := NewEncoder(&output)
+
+		_ = encoder.Begin("Tasks")
+		_ = encoder.Encode(*task)
+		_ = encoder.End()
+
+		calendar := output.String()
+		assert.True(t, strings.HasPrefix(calendar, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+		assert.Contains(t, calendar, "UID:task-12@todo-rest-api\r\n")
+		assert.Contains(t, calendar, "SUMMARY:Buy milk\\, eggs\\; bread\r\n")
+		assert.Contains(t, calendar, "STATUS:COMPLETED\r\n")
+		assert.Contains(t, calendar, "DUE:20240131T180000Z\r\n")
+		assert.Contains(t, calendar, "CATEGORIES:shopping,urgent\r\n")
+		assert.True(t, strings.HasSuffix(calendar, "END:VTODO\r\nEND:VCALENDAR\r\n"))
+	})
+
+	t.Run("should fold the lines longer than 75 octets without splitting characters", func(t *testing.T) {
+		line := "SUMMARY:" + strings.Repeat("é", 60)
+
+		folded := foldLine(line)
+
+		parts := strings.Split(folded, "\r\n ")
+		assert.Greater(t, len(parts), 1)
+		for _, part := range parts {
+			assert.LessOrEqual(t, len(part), 75)
+			assert.True(t, strings.ToValidUTF8(part, "") == part)
+		}
+		assert.Equal(t, line, strings.Join(parts, ""))
+	})
+}
+
+func TestDecode(t *testing.T) {
+	t.Run("should read the to-dos of the calendar as tasks", func(t *testing.T) {
+		calendar := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" +
+			"BEGIN:VTODO\r\nUID:1\r\nSUMMARY:Buy milk\\, eggs\r\nSTATUS:NEEDS-ACTION\r\n" +
+			"DUE;VALUE=DATE:20240131\r\nCATEGORIES:Shopping,home\r\n" +
+			"BEGIN:VALARM\r\nACTION:DISPLAY\r\nSUMMARY:Alarm\r\nEND:VALARM\r\nEND:VTODO\r\n" +
+			"BEGIN:VEVENT\r\nSUMMARY:Meeting\r\nEND:VEVENT\r\n" +
+			"BEGIN:VTODO\r\nSUMMARY:Call the\r\n  bank\r\nDUE;TZID=America/Sao_Paulo:20240201T090000\r\n" +
+			"COMPLETED:20240201T100000Z\r\nEND:VTODO\r\nEND:VCALENDAR\r\n"
+
+		tasks, _, err := Decode(strings.NewReader(calendar))
+
+		assert.Nil(t, err)
+		assert.Len(t, tasks, 2)
+		assert.Equal(t, "Buy milk, eggs", tasks[0].Description())
+		assert.False(t, tasks[0].Finished())
+		assert.Equal(t, time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), *tasks[0].DueDate())
+		assert.Equal(t, []string{"shopping", "home"}, tasks[0].Tags())
+		assert.Equal(t, "Call the bank", tasks[1].Description())
+		assert.True(t, tasks[1].Finished())
+		assert.Equal(t, time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC), *tasks[1].DueDate())
+	})
+
+	t.Run("should read back the calendar written by the encoder", func(t *testing.T) {
+		dueDate := time.Date(2024, 3, 5, 8, 30, 0, 0, time.UTC)
+		task := domain.NewTask(1, "Write; the report, today", false, domain.NewCollection(0, ""))
+		task.SetDueDate(&dueDate)
+		task.SetTags([]string{"work"})
+		var output bytes.Buffer
+		encoder := NewEncoder(&output)
+		_ = encoder.Begin("Tasks")
+		_ = encoder.Encode(*task)
+		_ = encoder.End()
+
+		tasks, _, err := Decode(&output)
+
+		assert.Nil(t, err)
+		assert.Equal(t, task.Description(), tasks[0].Description())
+		assert.Equal(t, dueDate, *tasks[0].DueDate())
+		assert.Equal(t, task.Tags(), tasks[0].Tags())
+	})
+
+	t.Run("should report every invalid to-do", func(t *testing.T) {
+		calendar := "BEGIN:VCALENDAR\nBEGIN:VTODO\nSUMMARY:Valid\nEND:VTODO\n" +
+			"BEGIN:VTODO\nDUE:tomorrow\nEND:VTODO\nEND:VCALENDAR\n"
+
+		tasks, _, err := Decode(strings.NewReader(calendar))
+
+		assert.Nil(t, tasks)
+		assert.Len(t, err.InvalidFields().Fields(), 2)
+		assert.Equal(t, "To-do 2", err.InvalidFields().Fields()[0].Name())
+		assert.Equal(t, "The DUE value provided is invalid.", err.InvalidFields().Fields()[0].Description())
+		assert.Equal(t, "The to-do must have a SUMMARY.", err.InvalidFields().Fields()[1].Description())
+	})
+
+	t.Run("should read the recurring to-dos as their first occurrence with a warning", func(t *testing.T) {
+		calendar := "BEGIN:VCALENDAR\nBEGIN:VTODO\nSUMMARY:Water the plants\nDUE;VALUE=DATE:20240105\n" +
+			"RRULE:FREQ=WEEKLY\nEND:VTODO\nBEGIN:VTODO\nSUMMARY:Buy milk\nEND:VTODO\nEND:VCALENDAR\n"
+
+		tasks, warnings, err := Decode(strings.NewReader(calendar))
+
+		assert.Nil(t, err)
+		assert.Len(t, tasks, 2)
+		assert.Equal(t, "Water the plants", tasks[0].Description())
+		assert.Equal(t, time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), *tasks[0].DueDate())
+		assert.Equal(t, []string{"To-do 1: Only the first occurrence has been imported, as the tasks have no " +
+			"recurrence."}, warnings)
+	})
+
+	t.Run("should return an error when the file is not a calendar", func(t *testing.T) {
+		_, _, err := Decode(strings.NewReader("id,description\n1,Run\n"))
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "The calendar provided is invalid.", err.Error())
+	})
+
+	t.Run("should return an error when a component is not terminated", func(t *testing.T) {
+		_, _, err := Decode(strings.NewReader("BEGIN:VCALENDAR\nBEGIN:VTODO\nSUMMARY:Run\n"))
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "The file ends before the end of the component VTODO",
+			err.InvalidFields().Fields()[0].Description())
+	})
+}
+
+func TestDecodeObject(t *testing.T) {
+	t.Run("should read the UID of the single to-do and encode it back", func(t *testing.T) {
+		calendar := "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nUID:A1B2-C3\r\nSUMMARY:Swim\r\nEND:VTODO\r\nEND:VCALENDAR\r\n"
+
+		object, _, err := DecodeObject(strings.NewReader(calendar))
+		var output bytes.Buffer
+		encoder := NewEncoder(&output)
+		_ = encoder.EncodeObject(*object)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "A1B2-C3", object.Uid())
+		assert.Equal(t, "Swim", object.Task().Description())
+		assert.Contains(t, output.String(), "UID:A1B2-C3\r\n")
+	})
+
+	t.Run("should return an error when the object has several to-dos", func(t *testing.T) {
+		calendar := "BEGIN:VCALENDAR\nBEGIN:VTODO\nSUMMARY:Run\nEND:VTODO\nBEGIN:VTODO\nSUMMARY:Swim\nEND:VTODO\n" +
+			"END:VCALENDAR\n"
+
+		_, _, err := DecodeObject(strings.NewReader(calendar))
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "The calendar object must have exactly one VTODO.", err.InvalidFields().Fields()[0].Description())
+	})
+}
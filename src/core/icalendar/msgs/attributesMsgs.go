@@ -0,0 +1,6 @@
+package msgs
+
+const (
+	Calendar = "Calendar"
+	Todo     = "To-do %d"
+)
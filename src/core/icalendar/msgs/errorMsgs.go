@@ -0,0 +1,14 @@
+package msgs
+
+const (
+	InvalidCalendar       = "The calendar provided is invalid."
+	MissingCalendar       = "The file must have a VCALENDAR component."
+	UnterminatedComponent = "The file ends before the end of the component "
+	InvalidContentLine    = "The content line is invalid: "
+	MissingSummary        = "The to-do must have a SUMMARY."
+	InvalidSummary        = "The SUMMARY must have at most %d characters."
+	InvalidDue            = "The DUE value provided is invalid."
+	RecurringTodo         = "Only the first occurrence has been imported, as the tasks have no recurrence."
+	TooManyTodos          = "The calendar must have at most %d to-dos."
+	SingleTodo            = "The calendar object must have exactly one VTODO."
+)
@@ -0,0 +1,133 @@
+package importers
+
+import (
+	"fmt"
+	"github.com/labstack/gommon/log"
+	"slices"
+	"strings"
+	"time"
+	"todo/src/core/domain"
+	"todo/src/core/importers/msgs"
+	interfaces "todo/src/core/interfaces/importers"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+const defaultCollectionName = "Imported"
+
+// Registry finds the importers by the name of their source
+type Registry struct {
+	importers map[string]interfaces.IImporter
+}
+
+func NewRegistry(importers ...interfaces.IImporter) *Registry {
+	registry := &Registry{importers: map[string]interfaces.IImporter{}}
+	for _, importer := range importers {
+		registry.importers[importer.Source()] = importer
+	}
+	return registry
+}
+
+// NewDefaultRegistry has the importers of every supported tool
+func NewDefaultRegistry() *Registry {
+	return NewRegistry(NewTodoistImporter(), NewTrelloImporter(), NewMicrosoftToDoImporter())
+}
+
+func (r Registry) Find(source string) (interfaces.IImporter, bool) {
+	importer, ok := r.importers[strings.ToLower(source)]
+	return importer, ok
+}
+
+func (r Registry) Sources() []string {
+	sources := make([]string, 0, len(r.importers))
+	for source := range r.importers {
+		sources = append(sources, source)
+	}
+	slices.Sort(sources)
+	return sources
+}
+
+// transferBuilder numbers the collections and tasks read from another tool, whose texts are cut to the lengths
+// accepted here instead of failing the whole import
+type transferBuilder struct {
+	collections   []domain.Collection
+	tasks         []domain.Task
+	collectionIds map[string]int
+}
+
+func newTransferBuilder() *transferBuilder {
+	return &transferBuilder{collectionIds: map[string]int{}}
+}
+
+// addCollection returns the ID of the collection with the key, adding it the first time
+func (b *transferBuilder) addCollection(key, name string) int {
+	if id, ok := b.collectionIds[key]; ok {
+		return id
+	}
+	name = truncateText(name, domain.MaxCollectionNameLength)
+	if name == "" {
+		name = defaultCollectionName
+	}
+	id := len(b.collections) + 1
+	b.collections = append(b.collections, *domain.NewCollection(id, name))
+	b.collectionIds[key] = id
+	return id
+}
+
+func (b *transferBuilder) addTask(description string, finished bool, collectionId int, dueDate *time.Time,
+	tags []string) {
+	task := domain.NewTask(len(b.tasks)+1, truncateText(description, domain.MaxTaskDescriptionLength), finished,
+		domain.NewCollection(collectionId, ""))
+	task.SetDueDate(dueDate)
+	task.SetTags(newTags(tags))
+	b.tasks = append(b.tasks, *task)
+}
+
+func (b *transferBuilder) build() (*domain.Transfer, *todoerrors.Validation) {
+	return domain.NewValidatedTransfer(b.collections, b.tasks)
+}
+
+// truncateText joins the lines of the text and cuts it to the maximum number of characters
+func truncateText(text string, maxLength int) string {
+	runes := []rune(strings.Join(strings.Fields(text), " "))
+	if len(runes) > maxLength {
+		runes = runes[:maxLength]
+	}
+	return strings.TrimSpace(string(runes))
+}
+
+// newTags turns the labels of another tool into tags, joining their words with hyphens and skipping the ones too
+// long to be kept
+func newTags(labels []string) []string {
+	tags := []string{}
+	for _, label := range labels {
+		tag := strings.ToLower(strings.Join(strings.Fields(label), "-"))
+		if tag == "" || len(tag) > domain.MaxTagLength || slices.Contains(tags, tag) {
+			continue
+		}
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// parseDueDate reads the dates and the times without zone in the location. The dates the API cannot store, such as
+// the recurring ones of Todoist, are left out.
+func parseDueDate(value string, location *time.Location) *time.Time {
+	if dueDate, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		dueDate = dueDate.UTC()
+		return &dueDate
+	}
+	for _, layout := range []string{"2006-01-02T15:04:05.9999999", time.DateOnly} {
+		if dueDate, err := time.ParseInLocation(layout, value, location); err == nil {
+			dueDate = dueDate.UTC()
+			return &dueDate
+		}
+	}
+	return nil
+}
+
+func newFileError(source, description string) *todoerrors.Validation {
+	log.Error(description)
+	invalidFields := todoerrors.InvalidFields{}
+	invalidFields.AppendField(msgs.File, description)
+	return todoerrors.NewValidationError(fmt.Sprintf(msgs.InvalidFile, source), invalidFields)
+}
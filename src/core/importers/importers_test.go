@@ -0,0 +1,109 @@
+package importers
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Run("should find the importers by source ignoring the case", func(t *testing.T) {
+		registry := NewDefaultRegistry()
+
+		importer, ok := registry.Find("Trello")
+		_, unknown := registry.Find("asana")
+
+		assert.True(t, ok)
+		assert.Equal(t, "trello", importer.Source())
+		assert.False(t, unknown)
+		assert.Equal(t, []string{"microsoft-todo", "todoist", "trello"}, registry.Sources())
+	})
+}
+
+func TestTodoist(t *testing.T) {
+	t.Run("should read the projects and items of the sync JSON", func(t *testing.T) {
+		export := `{"projects":[{"id":"p1","name":"Inbox"},{"id":"p2","name":"Old","is_deleted":true}],
+			"items":[{"project_id":"p1","content":"Buy milk","checked":true,"labels":["Food Shopping"]},
+			{"project_id":"p1","content":"Pay rent","due":{"date":"2024-01-31"}},
+			{"project_id":"p2","content":"Forgotten"},{"project_id":"p1","content":"Gone","is_deleted":true}]}`
+
+		transfer, err := NewTodoistImporter().Decode(strings.NewReader(export), "")
+
+		assert.Nil(t, err)
+		assert.Len(t, transfer.Collections(), 1)
+		assert.Equal(t, "Inbox", transfer.Collections()[0].Name())
+		assert.Len(t, transfer.Tasks(), 3)
+		assert.True(t, transfer.Tasks()[0].Finished())
+		assert.Equal(t, []string{"food-shopping"}, transfer.Tasks()[0].Tags())
+		assert.Equal(t, time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), *transfer.Tasks()[1].DueDate())
+		assert.Equal(t, 0, transfer.Tasks()[2].Collection().Id())
+	})
+
+	t.Run("should read the tasks of the CSV template in a collection with the name of the file", func(t *testing.T) {
+		export := "\uFEFFTYPE,CONTENT,DESCRIPTION,PRIORITY,INDENT,AUTHOR,RESPONSIBLE,DATE,DATE_LANG,TIMEZONE\n" +
+			"section,Errands,,,,,,,,\ntask,Call the bank @phone,,4,1,,,2024-02-01,en,UTC\n" +
+			"note,Ask about the fees,,,,,,,,\ntask,Water the plants,,4,1,,,every day,en,UTC\n"
+
+		transfer, err := NewTodoistImporter().Decode(strings.NewReader(export), "Home")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "Home", transfer.Collections()[0].Name())
+		assert.Len(t, transfer.Tasks(), 2)
+		assert.Equal(t, "Call the bank", transfer.Tasks()[0].Description())
+		assert.Equal(t, []string{"phone"}, transfer.Tasks()[0].Tags())
+		assert.NotNil(t, transfer.Tasks()[0].DueDate())
+		assert.Nil(t, transfer.Tasks()[1].DueDate())
+	})
+
+	t.Run("should return an error when the CSV file is not a Todoist template", func(t *testing.T) {
+		_, err := NewTodoistImporter().Decode(strings.NewReader("id,description\n1,Run\n"), "Home")
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "The CSV file must have the column TYPE", err.InvalidFields().Fields()[0].Description())
+	})
+}
+
+func TestTrello(t *testing.T) {
+	t.Run("should read the open cards of the board with their list as a tag", func(t *testing.T) {
+		export := `{"id":"b1","name":"Launch","lists":[{"id":"l1","name":"In Progress"},{"id":"l2","closed":true}],
+			"cards":[{"name":"Write the announcement for the launch of the new version of the product","idList":"l1",
+			"due":"2024-03-05T08:30:00.000Z","dueComplete":true,"labels":[{"name":"Marketing"},{"color":"red"}]},
+			{"name":"Archived","idList":"l1","closed":true},{"name":"In a closed list","idList":"l2"}]}`
+
+		transfer, err := NewTrelloImporter().Decode(strings.NewReader(export), "")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "Launch", transfer.Collections()[0].Name())
+		assert.Len(t, transfer.Tasks(), 1)
+		assert.Len(t, []rune(transfer.Tasks()[0].Description()), 50)
+		assert.True(t, transfer.Tasks()[0].Finished())
+		assert.Equal(t, []string{"in-progress", "marketing", "red"}, transfer.Tasks()[0].Tags())
+		assert.Equal(t, time.Date(2024, 3, 5, 8, 30, 0, 0, time.UTC), *transfer.Tasks()[0].DueDate())
+	})
+
+	t.Run("should return an error when the file is not JSON", func(t *testing.T) {
+		_, err := NewTrelloImporter().Decode(strings.NewReader("name,list\n"), "")
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "The trello export provided is invalid.", err.Error())
+	})
+}
+
+func TestMicrosoftToDo(t *testing.T) {
+	t.Run("should read each list as a collection with its tasks", func(t *testing.T) {
+		export := `{"value":[{"displayName":"Tasks","tasks":[{"title":"Renew passport","status":"completed"}]},
+			{"displayName":"Groceries","tasks":[{"title":"Eggs","status":"notStarted","categories":["Blue category"],
+			"dueDateTime":{"dateTime":"2024-01-31T00:00:00.0000000","timeZone":"America/Sao_Paulo"}}]}]}`
+
+		transfer, err := NewMicrosoftToDoImporter().Decode(strings.NewReader(export), "")
+
+		assert.Nil(t, err)
+		assert.Len(t, transfer.Collections(), 2)
+		assert.Equal(t, 2, transfer.Tasks()[1].Collection().Id())
+		assert.True(t, transfer.Tasks()[0].Finished())
+		assert.False(t, transfer.Tasks()[1].Finished())
+		assert.Equal(t, []string{"blue-category"}, transfer.Tasks()[1].Tags())
+		assert.Equal(t, time.Date(2024, 1, 31, 3, 0, 0, 0, time.UTC), *transfer.Tasks()[1].DueDate())
+	})
+}
@@ -0,0 +1,81 @@
+package importers
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"todo/src/core/domain"
+	"todo/src/core/importers/msgs"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+const (
+	sourceMicrosoftToDo = "microsoft-todo"
+	microsoftToDoDone   = "completed"
+)
+
+// MicrosoftToDo reads the lists of Microsoft To Do as returned by the Microsoft Graph API, each with its tasks, as
+// collections. The lists may be in the lists or in the value field.
+type MicrosoftToDo struct{}
+
+type microsoftToDoExport struct {
+	Lists []microsoftToDoList `json:"lists"`
+	Value []microsoftToDoList `json:"value"`
+}
+
+type microsoftToDoList struct {
+	DisplayName string              `json:"displayName"`
+	Tasks       []microsoftToDoTask `json:"tasks"`
+}
+
+type microsoftToDoTask struct {
+	Title       string                 `json:"title"`
+	Status      string                 `json:"status"`
+	DueDateTime *microsoftToDoDateTime `json:"dueDateTime"`
+	Categories  []string               `json:"categories"`
+}
+
+type microsoftToDoDateTime struct {
+	DateTime string `json:"dateTime"`
+	TimeZone string `json:"timeZone"`
+}
+
+func NewMicrosoftToDoImporter() *MicrosoftToDo {
+	return &MicrosoftToDo{}
+}
+
+func (i MicrosoftToDo) Source() string {
+	return sourceMicrosoftToDo
+}
+
+func (i MicrosoftToDo) Decode(reader io.Reader, _ string) (*domain.Transfer, *todoerrors.Validation) {
+	var export microsoftToDoExport
+	if err := json.NewDecoder(reader).Decode(&export); err != nil {
+		return nil, newFileError(sourceMicrosoftToDo, msgs.InvalidJson+err.Error())
+	}
+
+	builder := newTransferBuilder()
+	for index, list := range append(export.Lists, export.Value...) {
+		collectionId := builder.addCollection(strconv.Itoa(index), list.DisplayName)
+		for _, task := range list.Tasks {
+			builder.addTask(task.Title, strings.EqualFold(task.Status, microsoftToDoDone), collectionId,
+				task.DueDateTime.parse(), task.Categories)
+		}
+	}
+
+	return builder.build()
+}
+
+// parse reads the date in its time zone. The Windows time zone names, which Go does not know, are read as UTC.
+func (d *microsoftToDoDateTime) parse() *time.Time {
+	if d == nil {
+		return nil
+	}
+	location, err := time.LoadLocation(d.TimeZone)
+	if err != nil {
+		location = time.UTC
+	}
+	return parseDueDate(d.DateTime, location)
+}
@@ -0,0 +1,3 @@
+package msgs
+
+const File = "File"
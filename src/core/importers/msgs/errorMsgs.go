@@ -0,0 +1,8 @@
+package msgs
+
+const (
+	InvalidFile      = "The %s export provided is invalid."
+	InvalidJson      = "The file is not a valid JSON export: "
+	InvalidCsv       = "The file is not a valid CSV export: "
+	MissingCsvColumn = "The CSV file must have the column "
+)
@@ -0,0 +1,173 @@
+package importers
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+	"todo/src/core/domain"
+	"todo/src/core/importers/msgs"
+	"todo/src/core/projecterrors/todoerrors"
+	"unicode"
+)
+
+const (
+	sourceTodoist      = "todoist"
+	todoistTaskType    = "task"
+	todoistTypeColumn  = "TYPE"
+	todoistTextColumn  = "CONTENT"
+	todoistDateColumn  = "DATE"
+	todoistLabelPrefix = "@"
+)
+
+// Todoist reads the JSON of the Todoist sync API, with the projects and items of the account, and the CSV template
+// of a single project, which is named by the name of the file
+type Todoist struct{}
+
+type todoistExport struct {
+	Projects []todoistProject `json:"projects"`
+	Items    []todoistItem    `json:"items"`
+}
+
+type todoistProject struct {
+	Id         string `json:"id"`
+	Name       string `json:"name"`
+	IsArchived bool   `json:"is_archived"`
+	IsDeleted  bool   `json:"is_deleted"`
+}
+
+type todoistItem struct {
+	ProjectId string      `json:"project_id"`
+	Content   string      `json:"content"`
+	Checked   bool        `json:"checked"`
+	IsDeleted bool        `json:"is_deleted"`
+	Labels    []string    `json:"labels"`
+	Due       *todoistDue `json:"due"`
+}
+
+type todoistDue struct {
+	Date string `json:"date"`
+}
+
+func NewTodoistImporter() *Todoist {
+	return &Todoist{}
+}
+
+func (i Todoist) Source() string {
+	return sourceTodoist
+}
+
+func (i Todoist) Decode(reader io.Reader, name string) (*domain.Transfer, *todoerrors.Validation) {
+	bufferedReader := bufio.NewReader(reader)
+	if isJson(bufferedReader) {
+		return i.decodeJson(bufferedReader)
+	}
+	return i.decodeCsv(bufferedReader, name)
+}
+
+// decodeJson leaves out the deleted items and projects, the items of the archived projects being kept
+func (i Todoist) decodeJson(reader io.Reader) (*domain.Transfer, *todoerrors.Validation) {
+	var export todoistExport
+	if err := json.NewDecoder(reader).Decode(&export); err != nil {
+		return nil, newFileError(sourceTodoist, msgs.InvalidJson+err.Error())
+	}
+
+	builder := newTransferBuilder()
+	projectNames := map[string]string{}
+	for _, project := range export.Projects {
+		if !project.IsDeleted {
+			projectNames[project.Id] = project.Name
+		}
+	}
+	for _, item := range export.Items {
+		if item.IsDeleted {
+			continue
+		}
+		collectionId := 0
+		if projectName, ok := projectNames[item.ProjectId]; ok {
+			collectionId = builder.addCollection(item.ProjectId, projectName)
+		}
+		var dueDate *time.Time
+		if item.Due != nil {
+			dueDate = parseDueDate(item.Due.Date, time.UTC)
+		}
+		builder.addTask(item.Content, item.Checked, collectionId, dueDate, item.Labels)
+	}
+
+	return builder.build()
+}
+
+// decodeCsv reads the task rows of the template, whose labels are written in the content. The completed tasks are
+// not in the templates, so every task is open.
+func (i Todoist) decodeCsv(reader io.Reader, name string) (*domain.Transfer, *todoerrors.Validation) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	records, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, newFileError(sourceTodoist, msgs.InvalidCsv+err.Error())
+	}
+	if len(records) == 0 {
+		return nil, newFileError(sourceTodoist, msgs.MissingCsvColumn+todoistTypeColumn)
+	}
+
+	header := map[string]int{}
+	for index, column := range records[0] {
+		header[strings.ToUpper(strings.TrimSpace(column))] = index
+	}
+	for _, column := range []string{todoistTypeColumn, todoistTextColumn} {
+		if _, ok := header[column]; !ok {
+			return nil, newFileError(sourceTodoist, msgs.MissingCsvColumn+column)
+		}
+	}
+
+	builder := newTransferBuilder()
+	collectionId := builder.addCollection(name, name)
+	for _, record := range records[1:] {
+		if csvValue(record, header, todoistTypeColumn) != todoistTaskType {
+			continue
+		}
+		description, labels := splitTodoistLabels(csvValue(record, header, todoistTextColumn))
+		dueDate := parseDueDate(csvValue(record, header, todoistDateColumn), time.UTC)
+		builder.addTask(description, false, collectionId, dueDate, labels)
+	}
+
+	return builder.build()
+}
+
+// splitTodoistLabels takes the @labels out of the content of a task
+func splitTodoistLabels(content string) (string, []string) {
+	var words, labels []string
+	for _, word := range strings.Fields(content) {
+		if label, ok := strings.CutPrefix(word, todoistLabelPrefix); ok && label != "" {
+			labels = append(labels, label)
+			continue
+		}
+		words = append(words, word)
+	}
+	return strings.Join(words, " "), labels
+}
+
+func csvValue(record []string, header map[string]int, column string) string {
+	index, ok := header[column]
+	if !ok || index >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[index])
+}
+
+// isJson tells whether the first character of the file opens a JSON object or array, without consuming it
+func isJson(reader *bufio.Reader) bool {
+	for {
+		character, _, err := reader.ReadRune()
+		if err != nil {
+			return false
+		}
+		if unicode.IsSpace(character) || character == '\uFEFF' {
+			continue
+		}
+		_ = reader.UnreadRune()
+		return character == '{' || character == '['
+	}
+}
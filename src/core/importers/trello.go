@@ -0,0 +1,86 @@
+package importers
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+	"todo/src/core/domain"
+	"todo/src/core/importers/msgs"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+const sourceTrello = "trello"
+
+// Trello reads the JSON export of a board as a collection, with a task for each card. The list of the card becomes a
+// tag, besides its labels, since the lists of a board usually are the stages of its cards.
+type Trello struct{}
+
+type trelloBoard struct {
+	Id    string       `json:"id"`
+	Name  string       `json:"name"`
+	Lists []trelloList `json:"lists"`
+	Cards []trelloCard `json:"cards"`
+}
+
+type trelloList struct {
+	Id     string `json:"id"`
+	Name   string `json:"name"`
+	Closed bool   `json:"closed"`
+}
+
+type trelloCard struct {
+	Name        string        `json:"name"`
+	IdList      string        `json:"idList"`
+	Closed      bool          `json:"closed"`
+	Due         string        `json:"due"`
+	DueComplete bool          `json:"dueComplete"`
+	Labels      []trelloLabel `json:"labels"`
+}
+
+type trelloLabel struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+func NewTrelloImporter() *Trello {
+	return &Trello{}
+}
+
+func (i Trello) Source() string {
+	return sourceTrello
+}
+
+// Decode leaves out the archived cards and the cards of the archived lists
+func (i Trello) Decode(reader io.Reader, _ string) (*domain.Transfer, *todoerrors.Validation) {
+	var board trelloBoard
+	if err := json.NewDecoder(reader).Decode(&board); err != nil {
+		return nil, newFileError(sourceTrello, msgs.InvalidJson+err.Error())
+	}
+
+	lists := map[string]trelloList{}
+	for _, list := range board.Lists {
+		lists[list.Id] = list
+	}
+	builder := newTransferBuilder()
+	collectionId := builder.addCollection(board.Id, board.Name)
+	for _, card := range board.Cards {
+		list, ok := lists[card.IdList]
+		if card.Closed || list.Closed {
+			continue
+		}
+		var labels []string
+		if ok {
+			labels = append(labels, list.Name)
+		}
+		for _, label := range card.Labels {
+			if label.Name != "" {
+				labels = append(labels, label.Name)
+			} else {
+				labels = append(labels, label.Color)
+			}
+		}
+		builder.addTask(card.Name, card.DueComplete, collectionId, parseDueDate(card.Due, time.UTC), labels)
+	}
+
+	return builder.build()
+}
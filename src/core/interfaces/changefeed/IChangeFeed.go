@@ -0,0 +1,18 @@
+package changefeed
+
+import "todo/src/core/domain"
+
+// IChangeFeed streams the changes of each user to their clients
+type IChangeFeed interface {
+	// Subscribe returns the events of the user written after the last event the client has received, zero meaning
+	// none, and the subscription to the next ones. It fails with domain.ErrChangeFeedResync, along with the
+	// subscription, when the missed events can't all be returned.
+	Subscribe(userId int, lastEventId int64) ([]domain.ChangeEvent, ISubscription, error)
+}
+
+// ISubscription receives the events of a user until it is closed. The channel is closed as well when the client
+// falls too far behind, in which case it should subscribe again from its last event.
+type ISubscription interface {
+	Events() <-chan domain.ChangeEvent
+	Close()
+}
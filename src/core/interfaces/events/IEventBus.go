@@ -0,0 +1,14 @@
+package events
+
+import "todo/src/core/domain"
+
+// IEmitter hands the domain events of the services to their subscribers
+type IEmitter interface {
+	Emit(event domain.DomainEvent)
+}
+
+// ISubscriber reacts to the domain events it has been subscribed to. The change has already been stored when it is
+// called, so its error is only logged.
+type ISubscriber interface {
+	Handle(event domain.DomainEvent) error
+}
@@ -0,0 +1,14 @@
+package importers
+
+import (
+	"io"
+	"todo/src/core/domain"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+// IImporter reads the export of another tool as a transfer, whose IDs are only used to relate the tasks to their
+// collections. The name is used for the collection of the formats that do not name it, such as a single project file.
+type IImporter interface {
+	Source() string
+	Decode(reader io.Reader, name string) (*domain.Transfer, *todoerrors.Validation)
+}
@@ -0,0 +1,13 @@
+package jobs
+
+import (
+	"context"
+	"todo/src/core/domain"
+)
+
+// IHandler runs the jobs of a kind. A job is run again when its handler fails, or when the instance running it stops
+// before it has been marked as succeeded, so running it twice must be harmless. The context is done once the lease
+// of the job expires.
+type IHandler interface {
+	Handle(ctx context.Context, job domain.Job) error
+}
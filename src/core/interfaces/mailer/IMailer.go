@@ -0,0 +1,8 @@
+package mailer
+
+import "todo/src/core/domain"
+
+// IMailer sends the emails, failing when the server has not accepted them
+type IMailer interface {
+	Send(mail domain.Mail) error
+}
@@ -0,0 +1,11 @@
+package outbox
+
+import "todo/src/core/domain"
+
+// IHandler reacts to the events relayed from the outbox. An event is handed again when handling it has failed, or
+// when the process stops before it has been marked as processed, so handling it twice must be harmless. The jobs
+// returned are queued in the transaction marking the event as processed, and do the slow work the event calls for,
+// such as posting it, outside of the relay.
+type IHandler interface {
+	Handle(event domain.ChangeEvent) ([]domain.Job, error)
+}
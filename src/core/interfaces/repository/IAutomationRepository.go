@@ -0,0 +1,12 @@
+package repository
+
+import "todo/src/core/domain"
+
+type IAutomation interface {
+	Create(automation domain.Automation, userId int) (int, error)
+	Update(automation domain.Automation, userId int) error
+	Delete(automationId, userId int) error
+	FindById(automationId, userId int) (*domain.Automation, error)
+	FindAll(userId int) ([]domain.Automation, error)
+	FindTriggered(userId int, trigger string) ([]domain.Automation, error)
+}
@@ -0,0 +1,12 @@
+package repository
+
+import "todo/src/core/domain"
+
+type ICalendar interface {
+	SaveFeedToken(token string, userId int) error
+	FindFeedToken(userId int) (string, error)
+	DeleteFeedToken(userId int) error
+	FindFeed(token string, collectionId int) (*domain.CalendarFeed, error)
+	ExportTasks(feed domain.CalendarFeed, write func(task domain.Task) error) error
+	Import(tasks []domain.Task, collectionId, userId int) ([]int, error)
+}
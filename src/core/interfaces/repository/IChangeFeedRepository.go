@@ -0,0 +1,14 @@
+package repository
+
+import "todo/src/core/domain"
+
+type IChangeFeed interface {
+	// LastEventId is the ID of the last event written in the outbox, zero when there are none
+	LastEventId() (int64, error)
+	// FindEvents reads the events of every account written after the ID, along with the ones with one of the IDs
+	// given, in the order of their IDs
+	FindEvents(afterId int64, ids []int64, limit int) ([]domain.ChangeEvent, error)
+	// FindMissedEvents reads the events of the account written after the last event its client has received, telling
+	// whether that event is still kept
+	FindMissedEvents(userId int, lastEventId int64, limit int) ([]domain.ChangeEvent, bool, error)
+}
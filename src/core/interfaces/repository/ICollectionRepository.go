@@ -5,6 +5,12 @@ import "todo/src/core/domain"
 type ICollection interface {
 	Create(collection domain.Collection, userId int) (int, error)
 	Update(collection domain.Collection, userId int) error
-	Delete(collectionId, userId int) error
-	FindAll(userId int) ([]domain.Collection, error)
+	Delete(collectionId, userId, version int) error
+	Archive(collectionId, userId int) error
+	FindById(collectionId, userId int) (*domain.Collection, error)
+	FindByIds(collectionIds []int, userId int) ([]domain.Collection, error)
+	FindTasks(collectionId, userId int) ([]domain.Task, error)
+	FindTasksOfCollections(collectionIds []int, userId int) ([]domain.Task, error)
+	FindTags(collectionId, userId int) ([]string, error)
+	FindAll(userId int, filter domain.CollectionFilter, pagination domain.Pagination) ([]domain.Collection, int, error)
 }
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"time"
+	"todo/src/core/domain"
+)
+
+type IIdempotency interface {
+	Reserve(request domain.IdempotentRequest, userId int, expiredBefore time.Time) (bool, error)
+	Complete(request domain.IdempotentRequest, userId int) error
+	Release(request domain.IdempotentRequest, userId int) error
+	FindByKey(key string, userId int) (*domain.IdempotentRequest, error)
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"time"
+	"todo/src/core/domain"
+)
+
+type IJob interface {
+	Enqueue(job domain.Job) (int64, error)
+	// Claim takes the first jobs of the kinds due at now, along with the running ones whose lease has expired, skipping
+	// the ones being claimed by the other instances. The jobs are returned running, held by the worker until now plus
+	// the lease, with their attempt counted.
+	Claim(workerId string, kinds []string, limit int, now time.Time) ([]domain.Job, error)
+	// Complete marks the job as succeeded, unless the worker no longer holds it
+	Complete(jobId int64, workerId string) error
+	// Fail queues the job again at retryAt, or marks it as failed when final, unless the worker no longer holds it
+	Fail(jobId int64, workerId, lastError string, retryAt time.Time, final bool) error
+	// SaveSchedules writes the schedules, keeping the next run of the ones saved before with the same expression
+	SaveSchedules(schedules []domain.JobSchedule, now time.Time) error
+	// EnqueueScheduled queues a job for each schedule due at now that has none queued or running, and moves the
+	// schedules to their next run, returning the number of jobs queued
+	EnqueueScheduled(now time.Time) (int, error)
+	FindSchedules() ([]domain.JobSchedule, error)
+	FindById(jobId int64) (*domain.Job, error)
+	// FindByIdOfUser finds the job only when it works for the account
+	FindByIdOfUser(jobId int64, userId int) (*domain.Job, error)
+	FindAll(filter domain.JobFilter, pagination domain.Pagination) ([]domain.Job, int, error)
+	// Retry queues a failed job again with all its attempts
+	Retry(jobId int64) error
+	DeleteFinished(succeededBefore, failedBefore time.Time) error
+}
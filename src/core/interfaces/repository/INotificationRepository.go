@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"time"
+	"todo/src/core/domain"
+)
+
+type INotification interface {
+	CreateReminder(reminder domain.Reminder, userId int) (int, error)
+	DeleteReminder(reminderId, taskId, userId int) error
+	FindReminders(taskId, userId int) ([]domain.Reminder, error)
+	FindPreferences(userId int) (*domain.NotificationPreferences, error)
+	UpdatePreferences(preferences domain.NotificationPreferences, userId int) error
+	// Deliver hands the first reminders due at now to deliver, once claimed with a lease that keeps them from the
+	// other instances and outside of any transaction. The reminders delivered are marked as sent or skipped, the
+	// failed ones are retried later until they have no attempts left, and the number of reminders sent or skipped is
+	// returned.
+	Deliver(now time.Time, limit int, deliver func(reminder domain.DueReminder) error) (int, error)
+}
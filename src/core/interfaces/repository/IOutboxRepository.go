@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"time"
+	"todo/src/core/domain"
+)
+
+type IOutbox interface {
+	// Relay hands the first pending events to relay in order, within a transaction held by a single instance at a
+	// time. The events handled are marked as processed along with the queueing of the jobs returned for them, the
+	// failed ones are retried later unless deferred, and the number of events processed is returned.
+	Relay(limit int, relay func(event domain.OutboxEvent) ([]domain.Job, error)) (int, error)
+	DeleteProcessed(before time.Time) error
+}
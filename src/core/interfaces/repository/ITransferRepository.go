@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"todo/src/core/domain"
+)
+
+type ITransfer interface {
+	Export(userId int, writeCollection func(collection domain.Collection) error,
+		writeTask func(task domain.Task) error) error
+	ExportCollections(userId int, write func(collection domain.Collection) error) error
+	ExportTasks(userId int, write func(task domain.Task) error) error
+	// Import rolls back the transfer once the context is done
+	Import(ctx context.Context, transfer domain.Transfer, userId int) (*domain.TransferResult, error)
+	ImportTasks(tasks []domain.Task, userId int) ([]int, error)
+}
@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"time"
+	"todo/src/core/domain"
+)
+
+type IUndo interface {
+	Create(operation domain.UndoOperation, userId int) error
+	Undo(token string, userId int, now time.Time) error
+}
@@ -0,0 +1,16 @@
+package repository
+
+import "todo/src/core/domain"
+
+type IWebhook interface {
+	Create(webhook domain.Webhook, userId int) (int, error)
+	Update(webhook domain.Webhook, userId int) error
+	Delete(webhookId, userId int) error
+	FindById(webhookId, userId int) (*domain.Webhook, error)
+	FindAll(userId int) ([]domain.Webhook, error)
+	FindSubscribed(userId int, eventType string) ([]domain.Webhook, error)
+	CreateDelivery(delivery domain.WebhookDelivery) error
+	FindEventDeliveries(eventId int64) ([]domain.WebhookDelivery, error)
+	FindDeliveries(webhookId, userId int, pagination domain.Pagination) ([]domain.WebhookDelivery, int, error)
+	RecordOutcome(webhookId int, succeeded bool, failureLimit int) (bool, error)
+}
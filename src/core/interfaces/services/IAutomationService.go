@@ -0,0 +1,11 @@
+package services
+
+import "todo/src/core/domain"
+
+type IAutomation interface {
+	Create(automation domain.Automation, userId int) (int, error)
+	Update(automation domain.Automation, userId int) error
+	Delete(automationId, userId int) error
+	FindById(automationId, userId int) (*domain.Automation, error)
+	FindAll(userId int) ([]domain.Automation, error)
+}
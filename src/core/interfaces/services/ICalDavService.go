@@ -0,0 +1,9 @@
+package services
+
+import "todo/src/core/domain"
+
+type ICalDav interface {
+	FindObjects(collectionId, userId int) ([]domain.CalendarObject, error)
+	FindObject(name string, collectionId, userId int) (*domain.CalendarObject, error)
+	SaveObject(object domain.CalendarObject, userId int) error
+}
@@ -0,0 +1,9 @@
+package services
+
+import "todo/src/core/domain"
+
+type IIdempotency interface {
+	Reserve(request domain.IdempotentRequest, userId int) (*domain.IdempotentRequest, error)
+	Complete(request domain.IdempotentRequest, userId int) error
+	Release(request domain.IdempotentRequest, userId int) error
+}
@@ -0,0 +1,10 @@
+package services
+
+import "todo/src/core/domain"
+
+type IJob interface {
+	FindAll(filter domain.JobFilter, pagination domain.Pagination) ([]domain.Job, int, error)
+	FindById(jobId int64) (*domain.Job, error)
+	FindSchedules() ([]domain.JobSchedule, error)
+	Retry(jobId int64) error
+}
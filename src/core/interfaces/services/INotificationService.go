@@ -0,0 +1,11 @@
+package services
+
+import "todo/src/core/domain"
+
+type INotification interface {
+	CreateReminder(reminder domain.Reminder, userId int) (int, error)
+	DeleteReminder(reminderId, taskId, userId int) error
+	FindReminders(taskId, userId int) ([]domain.Reminder, error)
+	FindPreferences(userId int) (*domain.NotificationPreferences, error)
+	UpdatePreferences(preferences domain.NotificationPreferences, userId int) error
+}
@@ -4,8 +4,12 @@ import "todo/src/core/domain"
 
 type ITask interface {
 	Create(task domain.Task, userId int) (int, error)
-	Update(task domain.Task, userId int) error
-	Delete(taskId, userId int) error
-	FindAll(userId int) ([]domain.Task, error)
-	FindByCollectionId(collectionId, userId int) ([]domain.Task, error)
+	Update(task domain.Task, userId int) (string, error)
+	Delete(taskId, userId, version int) (string, error)
+	FindById(taskId, userId int) (*domain.Task, error)
+	Batch(batch domain.TaskBatch, userId int) (*domain.TaskBatchResult, error)
+	FindAll(userId int, filter domain.TaskFilter, pagination domain.Pagination) ([]domain.Task, int, error)
+	FindByCollectionId(collectionId, userId int, filter domain.TaskFilter,
+		pagination domain.Pagination) ([]domain.Task, int, error)
+	FindPage(userId int, filter domain.TaskFilter, pagination domain.KeysetPagination) (*domain.TaskPage, error)
 }
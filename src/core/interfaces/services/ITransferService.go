@@ -0,0 +1,14 @@
+package services
+
+import "todo/src/core/domain"
+
+type ITransfer interface {
+	Export(userId int, writeCollection func(collection domain.Collection) error,
+		writeTask func(task domain.Task) error) error
+	ExportCollections(userId int, write func(collection domain.Collection) error) error
+	ExportTasks(userId int, write func(task domain.Task) error) error
+	Import(transfer domain.Transfer, userId int) (*domain.TransferResult, error)
+	EnqueueImport(transfer domain.Transfer, userId int) (int64, error)
+	FindImport(jobId int64, userId int) (*domain.Job, error)
+	ImportTasks(tasks []domain.Task, userId int) ([]int, error)
+}
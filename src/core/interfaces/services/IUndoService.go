@@ -0,0 +1,5 @@
+package services
+
+type IUndo interface {
+	Undo(token string, userId int) error
+}
@@ -0,0 +1,12 @@
+package services
+
+import "todo/src/core/domain"
+
+type IWebhook interface {
+	Create(webhook domain.Webhook, userId int) (*domain.Webhook, error)
+	Update(webhook domain.Webhook, userId int) error
+	Delete(webhookId, userId int) error
+	FindById(webhookId, userId int) (*domain.Webhook, error)
+	FindAll(userId int) ([]domain.Webhook, error)
+	FindDeliveries(webhookId, userId int, pagination domain.Pagination) ([]domain.WebhookDelivery, int, error)
+}
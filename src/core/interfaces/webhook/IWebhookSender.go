@@ -0,0 +1,9 @@
+package webhook
+
+import "todo/src/core/domain"
+
+// ISender posts the request body of the delivery to the webhook, signed with its secret, and returns the delivery
+// with the outcome of the attempt
+type ISender interface {
+	Send(webhook domain.Webhook, delivery domain.WebhookDelivery) *domain.WebhookDelivery
+}
@@ -0,0 +1,202 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"github.com/labstack/gommon/log"
+	"os"
+	"sync"
+	"time"
+	"todo/src/core/domain"
+	interfaces "todo/src/core/interfaces/jobs"
+	"todo/src/core/interfaces/repository"
+)
+
+const (
+	DefaultConcurrency = 4
+	// PurgeJobKind is the kind of the job deleting the finished jobs once they are old enough, scheduled daily
+	PurgeJobKind = "jobs.purge"
+	pollInterval = time.Second
+)
+
+// HandlerFunc adapts a function to a job handler
+type HandlerFunc func(ctx context.Context, job domain.Job) error
+
+func (f HandlerFunc) Handle(ctx context.Context, job domain.Job) error {
+	return f(ctx, job)
+}
+
+// Runner runs the jobs of the kinds it has handlers for, alongside the runners of the other instances, each job being
+// claimed by a single one of them. The failed jobs are retried after a wait growing with each attempt, and the
+// schedules queue their jobs as their cron expressions match.
+type Runner struct {
+	repository  repository.IJob
+	workerId    string
+	handlers    map[string]interfaces.IHandler
+	schedules   []domain.JobSchedule
+	concurrency int
+}
+
+func NewRunner(repository repository.IJob, concurrency int) *Runner {
+	return &Runner{
+		repository:  repository,
+		workerId:    newWorkerId(),
+		handlers:    map[string]interfaces.IHandler{},
+		concurrency: max(concurrency, 1),
+	}
+}
+
+// newWorkerId names the runner after its host and process, with a random suffix telling apart the runners of a
+// restarted process
+func newWorkerId() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+
+	return fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), hex.EncodeToString(suffix))
+}
+
+// Register sets the handler of a kind, before the runner runs
+func (r *Runner) Register(kind string, handler interfaces.IHandler) {
+	r.handlers[kind] = handler
+}
+
+// Schedule queues a job of the kind each time the cron expression matches, before the runner runs
+func (r *Runner) Schedule(name, spec, kind string) error {
+	schedule, err := domain.NewJobSchedule(name, kind, spec, nil)
+	if err != nil {
+		return err
+	}
+	r.schedules = append(r.schedules, *schedule)
+	return nil
+}
+
+// Run runs the due jobs until the context is done, claiming as many of them as there are free workers
+func (r *Runner) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	slots := make(chan struct{}, r.concurrency)
+	var running sync.WaitGroup
+	defer running.Wait()
+	schedulesSaved := false
+
+	for {
+		if !schedulesSaved {
+			schedulesSaved = r.repository.SaveSchedules(r.schedules, time.Now()) == nil
+		}
+		if schedulesSaved {
+			_, _ = r.repository.EnqueueScheduled(time.Now())
+		}
+
+		for _, job := range r.claim(r.concurrency-len(slots), time.Now()) {
+			slots <- struct{}{}
+			running.Add(1)
+			go func(job domain.Job) {
+				defer func() {
+					<-slots
+					running.Done()
+				}()
+				r.execute(ctx, job)
+			}(job)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunDue runs the first jobs due at now, the scheduled ones included, and waits for them, returning the number of
+// jobs run
+func (r *Runner) RunDue(ctx context.Context, now time.Time) (int, error) {
+	if _, err := r.repository.EnqueueScheduled(now); err != nil {
+		return 0, err
+	}
+	claimed, err := r.repository.Claim(r.workerId, r.kinds(), r.concurrency, now)
+	if err != nil {
+		return 0, err
+	}
+
+	var running sync.WaitGroup
+	for _, job := range claimed {
+		running.Add(1)
+		go func(job domain.Job) {
+			defer running.Done()
+			r.execute(ctx, job)
+		}(job)
+	}
+	running.Wait()
+
+	return len(claimed), nil
+}
+
+func (r *Runner) claim(limit int, now time.Time) []domain.Job {
+	if limit <= 0 || len(r.handlers) == 0 {
+		return nil
+	}
+	claimed, err := r.repository.Claim(r.workerId, r.kinds(), limit, now)
+	if err != nil {
+		return nil
+	}
+	return claimed
+}
+
+func (r *Runner) kinds() []string {
+	kinds := make([]string, 0, len(r.handlers))
+	for kind := range r.handlers {
+		kinds = append(kinds, kind)
+	}
+	return kinds
+}
+
+// execute runs the job until its lease expires, queueing it again when it fails and it has attempts left
+func (r *Runner) execute(ctx context.Context, job domain.Job) {
+	deadline := time.Now().Add(domain.JobLease)
+	if job.LockedUntil() != nil {
+		deadline = *job.LockedUntil()
+	}
+	jobCtx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+
+	err := r.handle(jobCtx, job)
+	if err == nil {
+		if err = r.repository.Complete(job.Id(), r.workerId); err != nil {
+			log.Warnf("The job %d succeeded after its lease expired: %v", job.Id(), err)
+		}
+		return
+	}
+
+	log.Warnf("The job %d of kind %s failed after %d attempts: %v", job.Id(), job.Kind(), job.Attempts(), err)
+	retryAt := time.Now().Add(domain.JobRetryWait(job.Attempts()))
+	if err = r.repository.Fail(job.Id(), r.workerId, err.Error(), retryAt, !job.HasAttemptsLeft()); err != nil {
+		log.Warnf("The failure of the job %d could not be recorded: %v", job.Id(), err)
+	}
+}
+
+// handle reports the panics of the handler as failures of the job
+func (r *Runner) handle(ctx context.Context, job domain.Job) (err error) {
+	handler, found := r.handlers[job.Kind()]
+	if !found {
+		return fmt.Errorf("the job kind %s has no handler", job.Kind())
+	}
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("the handler panicked: %v", recovered)
+		}
+	}()
+
+	return handler.Handle(ctx, job)
+}
+
+// Purge deletes the finished jobs once they are old enough, as a job, keeping the failed ones longer to look into them
+func (r *Runner) Purge(context.Context, domain.Job) error {
+	now := time.Now()
+	return r.repository.DeleteFinished(now.Add(-domain.JobRetention), now.Add(-domain.FailedJobRetention))
+}
@@ -0,0 +1,250 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	"todo/src/core/domain"
+)
+
+// memoryJobs claims and records the jobs the way the Postgres queue does, a single runner claiming each job
+type memoryJobs struct {
+	mutex     sync.Mutex
+	jobs      []*domain.Job
+	schedules []domain.JobSchedule
+	retryAt   map[int64]time.Time
+}
+
+func newMemoryJobs() *memoryJobs {
+	return &memoryJobs{retryAt: map[int64]time.Time{}}
+}
+
+func (m *memoryJobs) Enqueue(job domain.Job) (int64, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	queued := domain.NewJob(int64(len(m.jobs)+1), job.Kind(), job.Payload(), job.RunAt())
+	queued.SetSchedule(job.Schedule())
+	m.jobs = append(m.jobs, queued)
+	return queued.Id(), nil
+}
+
+func (m *memoryJobs) Claim(workerId string, kinds []string, limit int, now time.Time) ([]domain.Job, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	var claimed []domain.Job
+	for _, job := range m.jobs {
+		if len(claimed) == limit {
+			break
+		}
+		if job.Status() != domain.JobQueued || job.RunAt().After(now) || !slices.Contains(kinds, job.Kind()) {
+			continue
+		}
+		lockedUntil := now.Add(domain.JobLease)
+		job.SetState(domain.JobRunning, job.Attempts()+1, job.MaxAttempts(), job.LastError())
+		job.SetLock(workerId, &lockedUntil)
+		claimed = append(claimed, *job)
+	}
+	return claimed, nil
+}
+
+func (m *memoryJobs) Complete(jobId int64, workerId string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	job := m.jobs[jobId-1]
+	if job.LockedBy() != workerId {
+		return errors.New("lost")
+	}
+	job.SetState(domain.JobSucceeded, job.Attempts(), job.MaxAttempts(), job.LastError())
+	job.SetLock("", nil)
+	return nil
+}
+
+func (m *memoryJobs) Fail(jobId int64, workerId, lastError string, retryAt time.Time, final bool) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	job := m.jobs[jobId-1]
+	if job.LockedBy() != workerId {
+		return errors.New("lost")
+	}
+	status := domain.JobQueued
+	if final {
+		status = domain.JobFailed
+	}
+	job.SetState(status, job.Attempts(), job.MaxAttempts(), lastError)
+	job.SetLock("", nil)
+	m.retryAt[jobId] = retryAt
+	return nil
+}
+
+func (m *memoryJobs) SaveSchedules(schedules []domain.JobSchedule, now time.Time) error {
+	for _, schedule := range schedules {
+		schedule.SetNextRunAt(schedule.Next(now))
+		m.schedules = append(m.schedules, schedule)
+	}
+	return nil
+}
+
+func (m *memoryJobs) EnqueueScheduled(now time.Time) (int, error) {
+	enqueued := 0
+	for index, schedule := range m.schedules {
+		if schedule.NextRunAt().After(now) {
+			continue
+		}
+		active := slices.ContainsFunc(m.jobs, func(job *domain.Job) bool {
+			return job.Schedule() == schedule.Name() &&
+				(job.Status() == domain.JobQueued || job.Status() == domain.JobRunning)
+		})
+		if !active {
+			job := domain.NewJob(0, schedule.Kind(), schedule.Payload(), schedule.NextRunAt())
+			job.SetSchedule(schedule.Name())
+			_, _ = m.Enqueue(*job)
+			enqueued++
+		}
+		m.schedules[index].SetNextRunAt(schedule.Next(now))
+	}
+	return enqueued, nil
+}
+
+func (m *memoryJobs) FindSchedules() ([]domain.JobSchedule, error) { return m.schedules, nil }
+func (m *memoryJobs) FindById(jobId int64) (*domain.Job, error)    { return m.jobs[jobId-1], nil }
+func (m *memoryJobs) Retry(int64) error                            { return nil }
+func (m *memoryJobs) DeleteFinished(time.Time, time.Time) error    { return nil }
+func (m *memoryJobs) FindAll(domain.JobFilter, domain.Pagination) ([]domain.Job, int, error) {
+	return nil, 0, nil
+}
+func (m *memoryJobs) FindByIdOfUser(jobId int64, _ int) (*domain.Job, error) {
+	return m.jobs[jobId-1], nil
+}
+
+func (m *memoryJobs) enqueue(kind string, runAt time.Time) {
+	_, _ = m.Enqueue(*domain.NewJob(0, kind, nil, runAt))
+}
+
+func TestRunner_RunDue(t *testing.T) {
+	t.Run("should run the due jobs of the kinds it handles", func(t *testing.T) {
+		repository := newMemoryJobs()
+		now := time.Now()
+		repository.enqueue("reminders.send", now.Add(-time.Minute))
+		repository.enqueue("reminders.send", now.Add(time.Hour))
+		repository.enqueue("imports.run", now.Add(-time.Minute))
+		runner := NewRunner(repository, DefaultConcurrency)
+		var handled []int64
+		runner.Register("reminders.send", HandlerFunc(func(_ context.Context, job domain.Job) error {
+			handled = append(handled, job.Id())
+			return nil
+		}))
+
+		run, err := runner.RunDue(context.Background(), now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, run)
+		assert.Equal(t, []int64{1}, handled)
+		assert.Equal(t, domain.JobSucceeded, repository.jobs[0].Status())
+		assert.Equal(t, domain.JobQueued, repository.jobs[1].Status())
+		assert.Equal(t, domain.JobQueued, repository.jobs[2].Status())
+	})
+
+	t.Run("should retry the failed job after a growing wait until it has no attempts left", func(t *testing.T) {
+		repository := newMemoryJobs()
+		repository.enqueue("outbox.purge", time.Now())
+		runner := NewRunner(repository, DefaultConcurrency)
+		runner.Register("outbox.purge", HandlerFunc(func(context.Context, domain.Job) error {
+			return errors.New("database unavailable")
+		}))
+
+		var waits []time.Duration
+		for attempt := 1; attempt <= domain.DefaultJobMaxAttempts; attempt++ {
+			started := time.Now()
+			run, _ := runner.RunDue(context.Background(), started.Add(24*time.Hour))
+
+			assert.Equal(t, 1, run)
+			waits = append(waits, repository.retryAt[1].Sub(started).Round(time.Second))
+		}
+
+		assert.Equal(t, []time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second, 80 * time.Second,
+			160 * time.Second}, waits)
+		assert.Equal(t, domain.JobFailed, repository.jobs[0].Status())
+		assert.Equal(t, "database unavailable", repository.jobs[0].LastError())
+		run, _ := runner.RunDue(context.Background(), time.Now().Add(24*time.Hour))
+		assert.Equal(t, 0, run)
+	})
+
+	t.Run("should count the panic of the handler as a failed attempt", func(t *testing.T) {
+		repository := newMemoryJobs()
+		repository.enqueue("jobs.purge", time.Now())
+		runner := NewRunner(repository, DefaultConcurrency)
+		runner.Register("jobs.purge", HandlerFunc(func(context.Context, domain.Job) error {
+			panic("nil map")
+		}))
+
+		_, err := runner.RunDue(context.Background(), time.Now())
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.JobQueued, repository.jobs[0].Status())
+		assert.Equal(t, "the handler panicked: nil map", repository.jobs[0].LastError())
+	})
+
+	t.Run("should run each job once across the runners of the instances", func(t *testing.T) {
+		repository := newMemoryJobs()
+		for range 40 {
+			repository.enqueue("reminders.send", time.Now())
+		}
+		var runs [40]atomic.Int32
+		handler := HandlerFunc(func(_ context.Context, job domain.Job) error {
+			runs[job.Id()-1].Add(1)
+			time.Sleep(time.Millisecond)
+			return nil
+		})
+		var instances sync.WaitGroup
+		for range 3 {
+			runner := NewRunner(repository, DefaultConcurrency)
+			runner.Register("reminders.send", handler)
+			instances.Add(1)
+			go func() {
+				defer instances.Done()
+				for {
+					if run, _ := runner.RunDue(context.Background(), time.Now()); run == 0 {
+						return
+					}
+				}
+			}()
+		}
+		instances.Wait()
+
+		for index := range runs {
+			assert.Equal(t, int32(1), runs[index].Load())
+		}
+	})
+}
+
+func TestRunner_Schedule(t *testing.T) {
+	t.Run("should queue a single job of the schedule while it is queued", func(t *testing.T) {
+		repository := newMemoryJobs()
+		runner := NewRunner(repository, DefaultConcurrency)
+		assert.NoError(t, runner.Schedule("reminders", "* * * * *", "reminders.send"))
+		start := time.Date(2024, 1, 31, 10, 0, 30, 0, time.UTC)
+		_ = repository.SaveSchedules(runner.schedules, start)
+
+		for minutes := 1; minutes <= 3; minutes++ {
+			_, _ = repository.EnqueueScheduled(start.Add(time.Duration(minutes) * time.Minute))
+		}
+
+		assert.Len(t, repository.jobs, 1)
+		assert.Equal(t, "reminders", repository.jobs[0].Schedule())
+		assert.Equal(t, time.Date(2024, 1, 31, 10, 1, 0, 0, time.UTC), repository.jobs[0].RunAt())
+		assert.Equal(t, time.Date(2024, 1, 31, 10, 4, 0, 0, time.UTC), repository.schedules[0].NextRunAt())
+	})
+
+	t.Run("should reject a schedule that can't be read", func(t *testing.T) {
+		runner := NewRunner(newMemoryJobs(), DefaultConcurrency)
+
+		assert.Error(t, runner.Schedule("reminders", "every minute", "reminders.send"))
+		assert.Error(t, runner.Schedule("never", "0 0 30 2 *", "reminders.send"))
+		assert.Empty(t, runner.schedules)
+	})
+}
@@ -0,0 +1,80 @@
+package markdown
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/labstack/gommon/log"
+	"io"
+	"regexp"
+	"strings"
+	"todo/src/core/domain"
+	"todo/src/core/markdown/msgs"
+	"todo/src/core/projecterrors/todoerrors"
+	"unicode/utf8"
+)
+
+var (
+	itemPattern      = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+\[([ xX])\](?:\s+(.*))?$`)
+	headingPattern   = regexp.MustCompile(`^\s{0,3}#{1,6}\s+(.*?)\s*#*\s*$`)
+	escapedCharacter = regexp.MustCompile("\\\\([!-/:-@\\[-`{-~])")
+)
+
+// Decode reads the checklist items of a Markdown document as the tasks of a collection named after its first heading,
+// the name being empty when there is none. The nested items are read as tasks of their own, and the other lines are
+// ignored. Every invalid item is reported, named by its line in the document.
+func Decode(reader io.Reader) (*domain.Collection, *todoerrors.Validation) {
+	scanner := bufio.NewScanner(reader)
+	name := ""
+	var tasks []domain.Task
+	invalidFields := todoerrors.InvalidFields{}
+	for number := 1; scanner.Scan(); number++ {
+		line := scanner.Text()
+		if match := itemPattern.FindStringSubmatch(line); match != nil {
+			row := fmt.Sprintf(msgs.Line, number)
+			tasks = append(tasks, *newTask(match[2], match[1] != " ", row, &invalidFields))
+		} else if match = headingPattern.FindStringSubmatch(line); match != nil && name == "" {
+			name = unescapeText(match[1])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, newChecklistError(err.Error())
+	}
+
+	if len(tasks) == 0 {
+		return nil, newChecklistError(msgs.EmptyChecklist)
+	}
+	if len(tasks) > domain.MaxTransferSize {
+		return nil, newChecklistError(fmt.Sprintf(msgs.TooManyItems, domain.MaxTransferSize))
+	}
+	if invalidFields.HasInvalidFields() {
+		log.Error(msgs.InvalidChecklist)
+		return nil, todoerrors.NewValidationError(msgs.InvalidChecklist, invalidFields)
+	}
+
+	collection := domain.NewCollection(0, name)
+	collection.SetTasks(tasks)
+
+	return collection, nil
+}
+
+func newTask(text string, finished bool, row string, invalidFields *todoerrors.InvalidFields) *domain.Task {
+	description := unescapeText(strings.TrimSpace(text))
+	if description == "" {
+		invalidFields.AppendField(row, msgs.MissingDescription)
+	} else if utf8.RuneCountInString(description) > domain.MaxTaskDescriptionLength {
+		invalidFields.AppendField(row, fmt.Sprintf(msgs.InvalidDescription, domain.MaxTaskDescriptionLength))
+	}
+
+	return domain.NewTask(0, description, finished, domain.NewCollection(0, ""))
+}
+
+func unescapeText(text string) string {
+	return escapedCharacter.ReplaceAllString(text, "$1")
+}
+
+func newChecklistError(description string) *todoerrors.Validation {
+	log.Error(description)
+	invalidFields := todoerrors.InvalidFields{}
+	invalidFields.AppendField(msgs.Checklist, description)
+	return todoerrors.NewValidationError(msgs.InvalidChecklist, invalidFields)
+}
@@ -0,0 +1,39 @@
+package markdown
+
+import (
+	"io"
+	"strings"
+	"todo/src/core/domain"
+)
+
+const (
+	openItem     = "- [ ] "
+	finishedItem = "- [x] "
+	headingMark  = "# "
+)
+
+// The characters that Markdown would read as formatting are escaped, so that the descriptions are read back as is
+var textEscaper = strings.NewReplacer(`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "<", `\<`,
+	">", `\>`, "#", `\#`, "|", `\|`, "~", `\~`)
+
+// Encode writes the collection as a Markdown document titled with its name, with a checklist item for each of its
+// tasks. The tasks have no subtasks, so the checklist is never nested.
+func Encode(writer io.Writer, collection domain.Collection) error {
+	var builder strings.Builder
+	builder.WriteString(headingMark + escapeText(collection.Name()) + "\n\n")
+	for _, task := range collection.Tasks() {
+		if task.Finished() {
+			builder.WriteString(finishedItem)
+		} else {
+			builder.WriteString(openItem)
+		}
+		builder.WriteString(escapeText(task.Description()) + "\n")
+	}
+	_, err := io.WriteString(writer, builder.String())
+
+	return err
+}
+
+func escapeText(text string) string {
+	return textEscaper.Replace(strings.Join(strings.Fields(text), " "))
+}
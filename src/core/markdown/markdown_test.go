@@ -0,0 +1,74 @@
+package markdown
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"todo/src/core/domain"
+)
+
+func TestEncode(t *testing.T) {
+	t.Run("should write a checklist item for each task under the name of the collection", func(t *testing.T) {
+		collection := domain.NewCollection(3, "Home")
+		collection.SetTasks([]domain.Task{
+			*domain.NewTask(1, "Buy *fresh* milk", false, collection),
+			*domain.NewTask(2, "Call the bank", true, collection),
+		})
+		var output bytes.Buffer
+
+		_ = Encode(&output, *collection)
+
+		assert.Equal(t, "# Home\n\n- [ ] Buy \\*fresh\\* milk\n- [x] Call the bank\n", output.String())
+	})
+}
+
+func TestDecode(t *testing.T) {
+	t.Run("should read the items, nested or not, and the first heading", func(t *testing.T) {
+		document := "Some introduction\n\n## Sprint 12 ##\n\n- [ ] Review the PR\n  - [X] Run the tests\n" +
+			"* [x] Deploy\n1. [ ] Write the \\[release\\] notes\n- Not a task\n# Other heading\n"
+
+		collection, err := Decode(strings.NewReader(document))
+
+		assert.Nil(t, err)
+		assert.Equal(t, "Sprint 12", collection.Name())
+		assert.Len(t, collection.Tasks(), 4)
+		assert.False(t, collection.Tasks()[0].Finished())
+		assert.True(t, collection.Tasks()[1].Finished())
+		assert.Equal(t, "Run the tests", collection.Tasks()[1].Description())
+		assert.Equal(t, "Write the [release] notes", collection.Tasks()[3].Description())
+	})
+
+	t.Run("should read back the document written by the encoder", func(t *testing.T) {
+		collection := domain.NewCollection(3, "Work_2024")
+		collection.SetTasks([]domain.Task{*domain.NewTask(1, `Fix C:\temp <paths> #12`, true, collection)})
+		var output bytes.Buffer
+		_ = Encode(&output, *collection)
+
+		decodedCollection, err := Decode(&output)
+
+		assert.Nil(t, err)
+		assert.Equal(t, collection.Name(), decodedCollection.Name())
+		assert.Equal(t, collection.Tasks()[0].Description(), decodedCollection.Tasks()[0].Description())
+		assert.True(t, decodedCollection.Tasks()[0].Finished())
+	})
+
+	t.Run("should report every invalid item by its line", func(t *testing.T) {
+		document := "- [ ] Valid\n- [ ]\n- [x] " + strings.Repeat("a", 51) + "\n"
+
+		collection, err := Decode(strings.NewReader(document))
+
+		assert.Nil(t, collection)
+		assert.Len(t, err.InvalidFields().Fields(), 2)
+		assert.Equal(t, "Line 2", err.InvalidFields().Fields()[0].Name())
+		assert.Equal(t, "The description must have at most 50 characters.", err.InvalidFields().Fields()[1].Description())
+	})
+
+	t.Run("should return an error when the document has no checklist", func(t *testing.T) {
+		_, err := Decode(strings.NewReader("# Notes\n\nNothing to do.\n"))
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "The document must have at least one checklist item, such as - [ ] or - [x].",
+			err.InvalidFields().Fields()[0].Description())
+	})
+}
@@ -0,0 +1,6 @@
+package msgs
+
+const (
+	Checklist = "Checklist"
+	Line      = "Line %d"
+)
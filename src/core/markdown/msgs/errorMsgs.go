@@ -0,0 +1,9 @@
+package msgs
+
+const (
+	InvalidChecklist   = "The checklist provided is invalid."
+	EmptyChecklist     = "The document must have at least one checklist item, such as - [ ] or - [x]."
+	TooManyItems       = "The checklist must have at most %d items."
+	MissingDescription = "The item must have a description."
+	InvalidDescription = "The description must have at most %d characters."
+)
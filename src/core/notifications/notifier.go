@@ -0,0 +1,67 @@
+package notifications
+
+import (
+	"context"
+	"github.com/labstack/gommon/log"
+	"time"
+	"todo/src/core/domain"
+	"todo/src/core/interfaces/mailer"
+	"todo/src/core/interfaces/repository"
+)
+
+const (
+	// ReminderJobKind is the kind of the job sending the due reminders, scheduled every minute
+	ReminderJobKind = "reminders.send"
+	batchSize       = 50
+)
+
+// Notifier sends the reminders by email once they are due. The reminders of finished tasks, and the ones the
+// preferences of the account turn off, are skipped rather than kept for later.
+type Notifier struct {
+	repository repository.INotification
+	mailer     mailer.IMailer
+}
+
+func NewNotifier(repository repository.INotification, mailer mailer.IMailer) *Notifier {
+	return &Notifier{repository, mailer}
+}
+
+// Handle sends the due reminders as a job, batch after batch until none is left or the job runs out of time
+func (n *Notifier) Handle(ctx context.Context, _ domain.Job) error {
+	for ctx.Err() == nil {
+		delivered, err := n.SendDueReminders(time.Now())
+		if err != nil {
+			return err
+		}
+		if delivered < batchSize {
+			return nil
+		}
+	}
+
+	return ctx.Err()
+}
+
+// SendDueReminders sends the first reminders due at now, returning the number of them sent or skipped
+func (n *Notifier) SendDueReminders(now time.Time) (int, error) {
+	delivered, err := n.repository.Deliver(now, batchSize, func(reminder domain.DueReminder) error {
+		if !reminder.Wanted() {
+			return domain.ErrReminderSkipped
+		}
+
+		mail, err := RenderReminder(reminder)
+		if err == nil {
+			err = n.mailer.Send(*mail)
+		}
+		if err != nil {
+			log.Warnf("The reminder %d failed after %d attempts: %v", reminder.Reminder().Id(),
+				reminder.Reminder().Attempts()+1, err)
+		}
+		return err
+	})
+	if err != nil {
+		log.Error(err)
+		return 0, err
+	}
+
+	return delivered, nil
+}
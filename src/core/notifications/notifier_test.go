@@ -0,0 +1,140 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+	"todo/src/core/domain"
+)
+
+// memoryNotifications hands its due reminders and keeps the outcome of each one
+type memoryNotifications struct {
+	due      []domain.DueReminder
+	outcomes map[int]error
+}
+
+func (r *memoryNotifications) CreateReminder(domain.Reminder, int) (int, error)  { return 0, nil }
+func (r *memoryNotifications) DeleteReminder(int, int, int) error                { return nil }
+func (r *memoryNotifications) FindReminders(int, int) ([]domain.Reminder, error) { return nil, nil }
+func (r *memoryNotifications) UpdatePreferences(domain.NotificationPreferences, int) error {
+	return nil
+}
+func (r *memoryNotifications) FindPreferences(int) (*domain.NotificationPreferences, error) {
+	return nil, nil
+}
+
+func (r *memoryNotifications) Deliver(_ time.Time, limit int, deliver func(domain.DueReminder) error) (int, error) {
+	r.outcomes = map[int]error{}
+	delivered := 0
+	for _, reminder := range r.due[:min(limit, len(r.due))] {
+		err := deliver(reminder)
+		r.outcomes[reminder.Reminder().Id()] = err
+		if err == nil || errors.Is(err, domain.ErrReminderSkipped) {
+			delivered++
+		}
+	}
+	return delivered, nil
+}
+
+type recordingMailer struct {
+	sent []domain.Mail
+	err  error
+}
+
+func (m *recordingMailer) Send(mail domain.Mail) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.sent = append(m.sent, mail)
+	return nil
+}
+
+func newDueReminder(id int, description string, finished bool, preferences domain.NotificationPreferences,
+	minutesBeforeDue int) domain.DueReminder {
+	dueDate := time.Date(2030, 1, 2, 9, 30, 0, 0, time.UTC)
+	task := domain.NewTask(id, description, finished, nil)
+	task.SetDueDate(&dueDate)
+	return *domain.NewDueReminder(*domain.NewReminder(id, id, nil, &minutesBeforeDue), *task,
+		*domain.NewAccount(1, "Ann", "ann@example.com", "", ""), preferences)
+}
+
+func TestNotifier_SendDueReminders(t *testing.T) {
+	t.Run("should email the owner of the task with the text and HTML bodies", func(t *testing.T) {
+		repository := &memoryNotifications{due: []domain.DueReminder{
+			newDueReminder(1, "Pay <rent>", false, *domain.DefaultNotificationPreferences(), 60),
+		}}
+		mailer := &recordingMailer{}
+
+		delivered, err := NewNotifier(repository, mailer).SendDueReminders(time.Now())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, delivered)
+		assert.NoError(t, repository.outcomes[1])
+		assert.Len(t, mailer.sent, 1)
+		assert.Equal(t, "ann@example.com", mailer.sent[0].To())
+		assert.Equal(t, "Reminder: Pay <rent>", mailer.sent[0].Subject())
+		assert.Contains(t, mailer.sent[0].Text(), "Hi Ann,")
+		assert.Contains(t, mailer.sent[0].Text(), `"Pay <rent>"`)
+		assert.Contains(t, mailer.sent[0].Text(), "It is due on Wednesday, 2 January 2030 at 09:30 UTC.")
+		assert.Contains(t, mailer.sent[0].Html(), "<strong>Pay &lt;rent&gt;</strong>")
+	})
+
+	t.Run("should skip the reminders of finished tasks and the ones turned off", func(t *testing.T) {
+		noDueDateReminders := *domain.NewNotificationPreferences(true, true, false)
+		noEmail := *domain.NewNotificationPreferences(false, true, true)
+		repository := &memoryNotifications{due: []domain.DueReminder{
+			newDueReminder(1, "Done", true, *domain.DefaultNotificationPreferences(), 0),
+			newDueReminder(2, "Turned off", false, noDueDateReminders, 0),
+			newDueReminder(3, "No email", false, noEmail, 0),
+		}}
+		mailer := &recordingMailer{}
+
+		delivered, _ := NewNotifier(repository, mailer).SendDueReminders(time.Now())
+
+		assert.Equal(t, 3, delivered)
+		assert.Empty(t, mailer.sent)
+		for id := 1; id <= 3; id++ {
+			assert.ErrorIs(t, repository.outcomes[id], domain.ErrReminderSkipped)
+		}
+	})
+
+	t.Run("should hand the error of the mailer back so that the reminder is retried", func(t *testing.T) {
+		repository := &memoryNotifications{due: []domain.DueReminder{
+			newDueReminder(1, "Pay rent", false, *domain.DefaultNotificationPreferences(), 15),
+		}}
+		mailer := &recordingMailer{err: errors.New("connection refused")}
+
+		delivered, err := NewNotifier(repository, mailer).SendDueReminders(time.Now())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, delivered)
+		assert.EqualError(t, repository.outcomes[1], "connection refused")
+	})
+}
+
+func TestNotifier_Handle(t *testing.T) {
+	t.Run("should send the due reminders as a job", func(t *testing.T) {
+		repository := &memoryNotifications{due: []domain.DueReminder{
+			newDueReminder(1, "Pay rent", false, *domain.DefaultNotificationPreferences(), 15),
+		}}
+		mailer := &recordingMailer{}
+
+		err := NewNotifier(repository, mailer).Handle(context.Background(), *domain.NewJob(1, ReminderJobKind, nil,
+			time.Now()))
+
+		assert.NoError(t, err)
+		assert.Len(t, mailer.sent, 1)
+	})
+
+	t.Run("should fail the job once it runs out of time", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := NewNotifier(&memoryNotifications{}, &recordingMailer{}).Handle(ctx, *domain.NewJob(1,
+			ReminderJobKind, nil, time.Now()))
+
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
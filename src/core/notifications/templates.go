@@ -0,0 +1,52 @@
+package notifications
+
+import (
+	"bytes"
+	"embed"
+	htmltemplate "html/template"
+	texttemplate "text/template"
+	"todo/src/core/domain"
+)
+
+const (
+	reminderSubject = "Reminder: "
+	dueDateLayout   = "Monday, 2 January 2006 at 15:04 MST"
+)
+
+//go:embed templates
+var templateFiles embed.FS
+
+var (
+	reminderText = texttemplate.Must(texttemplate.ParseFS(templateFiles, "templates/reminder.txt"))
+	// reminderHtml escapes the description and name, which are written by the users
+	reminderHtml = htmltemplate.Must(htmltemplate.ParseFS(templateFiles, "templates/reminder.html"))
+)
+
+type reminderData struct {
+	Subject     string
+	Name        string
+	Description string
+	DueDate     string
+}
+
+// RenderReminder writes the email of the reminder to the owner of its task, the due date being shown in UTC
+func RenderReminder(reminder domain.DueReminder) (*domain.Mail, error) {
+	data := reminderData{
+		Subject:     reminderSubject + reminder.Task().Description(),
+		Name:        reminder.Account().Name(),
+		Description: reminder.Task().Description(),
+	}
+	if dueDate := reminder.Task().DueDate(); dueDate != nil {
+		data.DueDate = dueDate.UTC().Format(dueDateLayout)
+	}
+
+	var text, html bytes.Buffer
+	if err := reminderText.Execute(&text, data); err != nil {
+		return nil, err
+	}
+	if err := reminderHtml.Execute(&html, data); err != nil {
+		return nil, err
+	}
+
+	return domain.NewMail(reminder.Account().Email(), data.Subject, text.String(), html.String()), nil
+}
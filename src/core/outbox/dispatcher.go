@@ -0,0 +1,93 @@
+package outbox
+
+import (
+	"context"
+	"github.com/labstack/gommon/log"
+	"time"
+	"todo/src/core/domain"
+	interfaces "todo/src/core/interfaces/outbox"
+	"todo/src/core/interfaces/repository"
+)
+
+const (
+	// PurgeJobKind is the kind of the job deleting the processed events once they are old enough, scheduled hourly
+	PurgeJobKind = "outbox.purge"
+	batchSize    = 100
+	pollInterval = time.Second
+)
+
+// Dispatcher relays the events of the outbox to its handlers at least once. The events of an aggregate are handed in
+// the order they have been written: once one of them waits for a retry, the later ones wait behind it.
+type Dispatcher struct {
+	repository repository.IOutbox
+	handlers   []interfaces.IHandler
+}
+
+func NewDispatcher(repository repository.IOutbox) *Dispatcher {
+	return &Dispatcher{repository: repository}
+}
+
+// Register adds a handler, before the dispatcher runs
+func (d *Dispatcher) Register(handler interfaces.IHandler) {
+	d.handlers = append(d.handlers, handler)
+}
+
+// Run relays the pending events until the context is done
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			relayed, err := d.RelayPending()
+			if err != nil || relayed < batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayPending hands the first pending events to the handlers, queueing the jobs they return, and returns the number
+// of events processed
+func (d *Dispatcher) RelayPending() (int, error) {
+	now := time.Now()
+	blocked := map[string]bool{}
+
+	relayed, err := d.repository.Relay(batchSize, func(event domain.OutboxEvent) ([]domain.Job, error) {
+		aggregate := event.Aggregate()
+		if blocked[aggregate] || event.AvailableAt().After(now) {
+			blocked[aggregate] = true
+			return nil, domain.ErrOutboxEventDeferred
+		}
+
+		var jobList []domain.Job
+		for _, handler := range d.handlers {
+			handlerJobs, err := handler.Handle(event.Event())
+			if err != nil {
+				log.Warnf("The outbox event %d failed after %d attempts: %v", event.Event().Id(),
+					event.Attempts()+1, err)
+				blocked[aggregate] = true
+				return nil, err
+			}
+			jobList = append(jobList, handlerJobs...)
+		}
+		return jobList, nil
+	})
+	if err != nil {
+		log.Error(err)
+		return 0, err
+	}
+
+	return relayed, nil
+}
+
+// Purge deletes the processed events once they are old enough, as a job
+func (d *Dispatcher) Purge(context.Context, domain.Job) error {
+	return d.repository.DeleteProcessed(time.Now().Add(-domain.OutboxRetention))
+}
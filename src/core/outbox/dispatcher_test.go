@@ -0,0 +1,138 @@
+package outbox
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+	"todo/src/core/domain"
+)
+
+type pendingEvent struct {
+	event     domain.OutboxEvent
+	processed bool
+	failures  int
+}
+
+// memoryOutbox keeps the outcome of each event the way the Postgres outbox does
+type memoryOutbox struct {
+	events []*pendingEvent
+	jobs   []domain.Job
+}
+
+func (o *memoryOutbox) add(id int64, entity string, entityId, attempts int, availableAt time.Time) {
+	event := domain.NewChangeEvent(1, entity, domain.ChangeActionUpdated, entityId, 1)
+	event.SetId(id)
+	o.events = append(o.events, &pendingEvent{event: *domain.NewOutboxEvent(*event, attempts, availableAt)})
+}
+
+func (o *memoryOutbox) Relay(limit int, relay func(event domain.OutboxEvent) ([]domain.Job, error)) (int, error) {
+	relayed := 0
+	for _, pending := range o.events {
+		if pending.processed || limit == 0 {
+			continue
+		}
+		limit--
+		jobList, err := relay(pending.event)
+		switch {
+		case err == nil:
+			pending.processed = true
+			o.jobs = append(o.jobs, jobList...)
+			relayed++
+		case !errors.Is(err, domain.ErrOutboxEventDeferred):
+			pending.failures++
+		}
+	}
+
+	return relayed, nil
+}
+
+func (o *memoryOutbox) DeleteProcessed(time.Time) error {
+	return nil
+}
+
+type recordingHandler struct {
+	handled []int64
+	failing map[int64]bool
+}
+
+// Handle returns a job named after the event
+func (h *recordingHandler) Handle(event domain.ChangeEvent) ([]domain.Job, error) {
+	if h.failing[event.Id()] {
+		return nil, errors.New("unavailable")
+	}
+	h.handled = append(h.handled, event.Id())
+	return []domain.Job{*domain.NewJob(0, fmt.Sprintf("event.%d", event.Id()), nil, time.Time{})}, nil
+}
+
+func jobKinds(jobList []domain.Job) []string {
+	kinds := make([]string, len(jobList))
+	for i, job := range jobList {
+		kinds[i] = job.Kind()
+	}
+	return kinds
+}
+
+func TestDispatcher_RelayPending(t *testing.T) {
+	t.Run("should hand the events to every handler in the order they have been written", func(t *testing.T) {
+		repository := &memoryOutbox{}
+		repository.add(1, domain.ChangeEntityTask, 5, 0, time.Time{})
+		repository.add(2, domain.ChangeEntityCollection, 5, 0, time.Time{})
+		repository.add(3, domain.ChangeEntityTask, 5, 0, time.Time{})
+		dispatcher := NewDispatcher(repository)
+		firstHandler, secondHandler := &recordingHandler{}, &recordingHandler{}
+		dispatcher.Register(firstHandler)
+		dispatcher.Register(secondHandler)
+
+		relayed, err := dispatcher.RelayPending()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, relayed)
+		assert.Equal(t, []int64{1, 2, 3}, firstHandler.handled)
+		assert.Equal(t, []int64{1, 2, 3}, secondHandler.handled)
+		assert.Equal(t, []string{"event.1", "event.1", "event.2", "event.2", "event.3", "event.3"},
+			jobKinds(repository.jobs))
+	})
+
+	t.Run("should hold back the later events of an aggregate whose event has failed", func(t *testing.T) {
+		repository := &memoryOutbox{}
+		repository.add(1, domain.ChangeEntityTask, 5, 0, time.Time{})
+		repository.add(2, domain.ChangeEntityTask, 6, 0, time.Time{})
+		repository.add(3, domain.ChangeEntityTask, 5, 0, time.Time{})
+		dispatcher := NewDispatcher(repository)
+		handler := &recordingHandler{failing: map[int64]bool{1: true}}
+		dispatcher.Register(handler)
+
+		relayed, _ := dispatcher.RelayPending()
+
+		assert.Equal(t, 1, relayed)
+		assert.Equal(t, []int64{2}, handler.handled)
+		assert.Equal(t, 1, repository.events[0].failures)
+		assert.Zero(t, repository.events[2].failures)
+		assert.Equal(t, []string{"event.2"}, jobKinds(repository.jobs))
+
+		handler.failing = nil
+		relayed, _ = dispatcher.RelayPending()
+
+		assert.Equal(t, 2, relayed)
+		assert.Equal(t, []int64{2, 1, 3}, handler.handled)
+	})
+
+	t.Run("should defer the events waiting for their retry along with the later ones", func(t *testing.T) {
+		repository := &memoryOutbox{}
+		repository.add(1, domain.ChangeEntityTask, 5, 2, time.Now().Add(time.Minute))
+		repository.add(2, domain.ChangeEntityTask, 5, 0, time.Time{})
+		repository.add(3, domain.ChangeEntityTask, 6, 0, time.Time{})
+		dispatcher := NewDispatcher(repository)
+		handler := &recordingHandler{}
+		dispatcher.Register(handler)
+
+		relayed, _ := dispatcher.RelayPending()
+
+		assert.Equal(t, 1, relayed)
+		assert.Equal(t, []int64{3}, handler.handled)
+		assert.Zero(t, repository.events[0].failures)
+		assert.Zero(t, repository.events[1].failures)
+	})
+}
@@ -0,0 +1,164 @@
+package pdf
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// The pages are A4, measured in points from the bottom left corner
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+const (
+	catalogObject  = 1
+	pagesObject    = 2
+	infoObject     = 3
+	firstFont      = 4
+	pdfHeader      = "%PDF-1.4\n%\xE2\xE3\xCF\xD3\n"
+	producer       = "todo-rest-api"
+	objectsPerPage = 2
+)
+
+// Document builds a PDF file with the standard fonts, with no compression so that it stays simple to write
+type Document struct {
+	title string
+	pages []*Page
+}
+
+// Page holds the content stream of a page, written with the PDF drawing operators
+type Page struct {
+	content bytes.Buffer
+}
+
+func NewDocument(title string) *Document {
+	return &Document{title: title}
+}
+
+func (d *Document) AddPage() *Page {
+	page := &Page{}
+	d.pages = append(d.pages, page)
+	return page
+}
+
+func (d *Document) Pages() []*Page {
+	return d.pages
+}
+
+// Text writes the text with its baseline starting at the point
+func (p *Page) Text(x, y float64, font Font, size float64, text string) {
+	fmt.Fprintf(&p.content, "BT /F%d %s Tf %s %s Td %s Tj ET\n", font+1, formatNumber(size), formatNumber(x),
+		formatNumber(y), formatString(text))
+}
+
+func (p *Page) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(&p.content, "%s w %s %s m %s %s l S\n", formatNumber(width), formatNumber(x1), formatNumber(y1),
+		formatNumber(x2), formatNumber(y2))
+}
+
+// Rectangle strokes the outline of the rectangle whose bottom left corner is at the point
+func (p *Page) Rectangle(x, y, width, height, lineWidth float64) {
+	fmt.Fprintf(&p.content, "%s w %s %s %s %s re S\n", formatNumber(lineWidth), formatNumber(x), formatNumber(y),
+		formatNumber(width), formatNumber(height))
+}
+
+// SetGray sets the color of the next texts and lines, from black at zero to white at one
+func (p *Page) SetGray(level float64) {
+	fmt.Fprintf(&p.content, "%s g %s G\n", formatNumber(level), formatNumber(level))
+}
+
+// Write writes the document with the cross-reference table that PDF readers use to find its objects
+func (d *Document) Write(writer io.Writer) error {
+	output := &countingWriter{writer: bufio.NewWriter(writer)}
+	var offsets []int64
+	writeObject := func(body string) {
+		offsets = append(offsets, output.count)
+		fmt.Fprintf(output, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	output.WriteString(pdfHeader)
+	kids := make([]byte, 0)
+	for index := range d.pages {
+		kids = fmt.Appendf(kids, "%d 0 R ", pageObject(index))
+	}
+	writeObject(fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesObject))
+	writeObject(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", bytes.TrimSpace(kids), len(d.pages)))
+	writeObject(fmt.Sprintf("<< /Title %s /Producer %s >>", formatString(d.title), formatString(producer)))
+	for font := Regular; font <= Bold; font++ {
+		writeObject(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>",
+			fontNames[font]))
+	}
+	for index, page := range d.pages {
+		writeObject(fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %s %s] "+
+			"/Resources << /Font << /F1 %d 0 R /F2 %d 0 R >> >> /Contents %d 0 R >>", pagesObject,
+			formatNumber(PageWidth), formatNumber(PageHeight), firstFont, firstFont+1, pageObject(index)+1))
+		writeObject(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.content.Len(), page.content.String()))
+	}
+
+	crossReference := output.count
+	fmt.Fprintf(output, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(output, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(output, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(offsets)+1, catalogObject, infoObject, crossReference)
+
+	if output.err != nil {
+		return output.err
+	}
+	return output.writer.Flush()
+}
+
+func pageObject(index int) int {
+	return firstFont + 2 + index*objectsPerPage
+}
+
+// formatNumber rounds the value to hundredths of a point, far below what a printer can show
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(math.Round(value*100)/100, 'f', -1, 64)
+}
+
+// formatString writes the text as a PDF literal string, escaping the delimiters and the bytes outside ASCII
+func formatString(text string) string {
+	var builder bytes.Buffer
+	builder.WriteByte('(')
+	for _, character := range encodeText(text) {
+		switch {
+		case character == '(' || character == ')' || character == '\\':
+			builder.WriteByte('\\')
+			builder.WriteByte(character)
+		case character > '~':
+			fmt.Fprintf(&builder, "\\%03o", character)
+		default:
+			builder.WriteByte(character)
+		}
+	}
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// countingWriter keeps the offsets of the objects and the first error, so that the writes need no checks
+type countingWriter struct {
+	writer *bufio.Writer
+	count  int64
+	err    error
+}
+
+func (w *countingWriter) Write(data []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	written, err := w.writer.Write(data)
+	w.count += int64(written)
+	w.err = err
+	return written, err
+}
+
+func (w *countingWriter) WriteString(text string) {
+	_, _ = w.Write([]byte(text))
+}
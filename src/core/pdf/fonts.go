@@ -0,0 +1,76 @@
+package pdf
+
+type Font int
+
+// The fonts are two of the standard fonts every PDF reader has, so that nothing has to be embedded
+const (
+	Regular Font = iota
+	Bold
+)
+
+var fontNames = map[Font]string{
+	Regular: "Helvetica",
+	Bold:    "Helvetica-Bold",
+}
+
+const defaultGlyphWidth = 556
+
+// The widths of the printable ASCII characters, from space to tilde, in thousandths of the font size, as in the
+// metrics published by Adobe for the standard fonts
+var glyphWidths = map[Font][]int{
+	Regular: {
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	},
+	Bold: {
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	},
+}
+
+// The characters of WinAnsiEncoding outside Latin-1, which is kept as is
+var winAnsiCharacters = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87, 'ˆ': 0x88, '‰': 0x89, 'Š': 0x8A,
+	'‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+	'˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B, 'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// encodeText converts the text to WinAnsiEncoding, the characters it does not have being replaced by a question mark
+func encodeText(text string) []byte {
+	encoded := make([]byte, 0, len(text))
+	for _, character := range text {
+		switch {
+		case character >= ' ' && character <= '~', character >= 0xA0 && character <= 0xFF:
+			encoded = append(encoded, byte(character))
+		case winAnsiCharacters[character] != 0:
+			encoded = append(encoded, winAnsiCharacters[character])
+		case character < ' ':
+			encoded = append(encoded, ' ')
+		default:
+			encoded = append(encoded, '?')
+		}
+	}
+	return encoded
+}
+
+// TextWidth measures the text written with the font and size, in points
+func TextWidth(text string, font Font, size float64) float64 {
+	widths := glyphWidths[font]
+	total := 0
+	for _, character := range encodeText(text) {
+		if character >= ' ' && character <= '~' {
+			total += widths[character-' ']
+		} else {
+			total += defaultGlyphWidth
+		}
+	}
+	return float64(total) * size / 1000
+}
@@ -0,0 +1,13 @@
+package msgs
+
+const (
+	Account      = "Account: %s"
+	Generated    = "Generated on %s"
+	Summary      = "%d tasks: %d finished, %d open"
+	GroupSummary = "%d of %d finished"
+	Continued    = "%s (continued)"
+	NoCollection = "No collection"
+	NoTasks      = "There are no tasks to report."
+	Due          = "Due %s"
+	PageNumber   = "Page %d of %d"
+)
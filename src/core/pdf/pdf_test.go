@@ -0,0 +1,94 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"todo/src/core/domain"
+)
+
+func TestDocument(t *testing.T) {
+	t.Run("should write a cross-reference table with the offset of every object", func(t *testing.T) {
+		document := NewDocument("Weekly (draft)")
+		document.AddPage().Text(50, 700, Bold, 12, "Olá")
+		document.AddPage()
+		var output bytes.Buffer
+
+		_ = document.Write(&output)
+
+		file := output.String()
+		assert.True(t, strings.HasPrefix(file, "%PDF-1.4\n"))
+		assert.True(t, strings.HasSuffix(file, "%%EOF\n"))
+		assert.Contains(t, file, "/Title (Weekly \\(draft\\))")
+		assert.Contains(t, file, "BT /F2 12 Tf 50 700 Td (Ol\\341) Tj ET")
+		assert.Contains(t, file, "/Count 2")
+		offsets := regexp.MustCompile(`(\d{10}) 00000 n`).FindAllStringSubmatch(file, -1)
+		assert.Len(t, offsets, 9)
+		for index, offset := range offsets {
+			position, _ := strconv.Atoi(offset[1])
+			assert.True(t, strings.HasPrefix(file[position:], fmt.Sprintf("%d 0 obj", index+1)))
+		}
+		startXref := regexp.MustCompile(`startxref\n(\d+)`).FindStringSubmatch(file)
+		position, _ := strconv.Atoi(startXref[1])
+		assert.True(t, strings.HasPrefix(file[position:], "xref"))
+	})
+
+	t.Run("should measure the text with the widths of the font", func(t *testing.T) {
+		assert.Equal(t, 5.56*2+2.78, TextWidth("ab.", Regular, 10))
+		assert.Equal(t, 6.11, TextWidth("b", Bold, 10))
+	})
+}
+
+func TestReport(t *testing.T) {
+	dueDate := time.Date(2024, 1, 31, 18, 0, 0, 0, time.UTC)
+	generatedAt := time.Date(2024, 1, 29, 9, 0, 0, 0, time.UTC)
+
+	t.Run("should group the tasks by collection with the summary counts", func(t *testing.T) {
+		sport := domain.NewCollection(3, "Sport")
+		run := domain.NewTask(4, "Run", true, sport)
+		swim := domain.NewTask(7, "Swim", false, sport)
+		swim.SetDueDate(&dueDate)
+		report := NewReport("Weekly tasks", "example@example.com", generatedAt)
+		report.AddTasks([]domain.Task{*run, *domain.NewTask(8, "Call the bank", false, domain.NewCollection(0, "")),
+			*swim})
+		var output bytes.Buffer
+
+		_ = report.Write(&output)
+
+		file := output.String()
+		assert.Contains(t, file, "(Account: example@example.com)")
+		assert.Contains(t, file, "(Generated on 2024-01-29 09:00 UTC)")
+		assert.Contains(t, file, "(3 tasks: 1 finished, 2 open)")
+		assert.Contains(t, file, "(Sport) Tj")
+		assert.Contains(t, file, "(1 of 2 finished)")
+		assert.Contains(t, file, "(No collection) Tj")
+		assert.Contains(t, file, "(Due 2024-01-31)")
+		assert.Contains(t, file, "(Page 1 of 1)")
+		assert.Less(t, strings.Index(file, "(Swim)"), strings.Index(file, "(No collection)"))
+		assert.Regexp(t, `\(Run\) Tj ET\n0\.75 w 85 [\d.]+ m 105\.17 [\d.]+ l S`, file)
+	})
+
+	t.Run("should continue the collection on a new page", func(t *testing.T) {
+		collection := domain.NewCollection(3, "Backlog")
+		var tasks []domain.Task
+		for id := 1; id <= 60; id++ {
+			tasks = append(tasks, *domain.NewTask(id, fmt.Sprintf("Task %d", id), false, collection))
+		}
+		collection.SetTasks(tasks)
+		report := NewReport("Backlog", "example@example.com", generatedAt)
+		report.AddCollection(*collection)
+		var output bytes.Buffer
+
+		_ = report.Write(&output)
+
+		file := output.String()
+		assert.Contains(t, file, "/Count 2")
+		assert.Contains(t, file, "(Backlog \\(continued\\))")
+		assert.Contains(t, file, "(Page 2 of 2)")
+	})
+}
@@ -0,0 +1,194 @@
+package pdf
+
+import (
+	"fmt"
+	"io"
+	"time"
+	"todo/src/core/domain"
+	"todo/src/core/pdf/msgs"
+)
+
+// MaxReportTasks keeps the reports printable, the larger task lists having to be narrowed by filters
+const MaxReportTasks = 2000
+
+const (
+	margin         = 50.0
+	titleSize      = 18.0
+	textSize       = 11.0
+	detailSize     = 9.0
+	groupSize      = 13.0
+	footerSize     = 8.0
+	itemHeight     = 18.0
+	groupHeight    = 30.0
+	checkboxSize   = 9.0
+	itemIndent     = 18.0
+	finishedGray   = 0.45
+	detailGray     = 0.35
+	dateLayout     = time.DateOnly
+	dateTimeLayout = "2006-01-02 15:04 MST"
+)
+
+// Report lays out tasks grouped by collection, with the finished ones struck through, under a header with the
+// account, the date and the counts of the tasks
+type Report struct {
+	title   string
+	account string
+	date    time.Time
+	groups  []reportGroup
+}
+
+type reportGroup struct {
+	name  string
+	tasks []domain.Task
+}
+
+func NewReport(title, account string, date time.Time) *Report {
+	return &Report{title: title, account: account, date: date}
+}
+
+// AddCollection adds the tasks of the collection as a group
+func (r *Report) AddCollection(collection domain.Collection) {
+	r.groups = append(r.groups, reportGroup{name: collection.Name(), tasks: collection.Tasks()})
+}
+
+// AddTasks groups the tasks by their collections, in the order they first appear
+func (r *Report) AddTasks(tasks []domain.Task) {
+	indexes := map[int]int{}
+	for _, task := range tasks {
+		collectionId := task.Collection().Id()
+		index, ok := indexes[collectionId]
+		if !ok {
+			name := task.Collection().Name()
+			if collectionId == 0 {
+				name = msgs.NoCollection
+			}
+			index = len(r.groups)
+			indexes[collectionId] = index
+			r.groups = append(r.groups, reportGroup{name: name})
+		}
+		r.groups[index].tasks = append(r.groups[index].tasks, task)
+	}
+}
+
+func (r *Report) Write(writer io.Writer) error {
+	document := NewDocument(r.title)
+	layout := &reportLayout{document: document}
+	layout.newPage()
+	r.writeHeader(layout)
+
+	for _, group := range r.groups {
+		layout.reserve(groupHeight + itemHeight)
+		r.writeGroupHeader(layout, group, group.name)
+		for _, task := range group.tasks {
+			if layout.reserve(itemHeight) {
+				r.writeGroupHeader(layout, group, fmt.Sprintf(msgs.Continued, group.name))
+			}
+			writeItem(layout, task)
+		}
+	}
+
+	pages := document.Pages()
+	for index, page := range pages {
+		footer := fmt.Sprintf(msgs.PageNumber, index+1, len(pages))
+		page.SetGray(detailGray)
+		page.Text((PageWidth-TextWidth(footer, Regular, footerSize))/2, margin/2, Regular, footerSize, footer)
+	}
+
+	return document.Write(writer)
+}
+
+func (r *Report) writeHeader(layout *reportLayout) {
+	page := layout.page
+	layout.y -= titleSize
+	page.Text(margin, layout.y, Bold, titleSize, r.title)
+
+	layout.y -= textSize + 8
+	page.SetGray(detailGray)
+	page.Text(margin, layout.y, Regular, detailSize, fmt.Sprintf(msgs.Account, r.account))
+	generated := fmt.Sprintf(msgs.Generated, r.date.Format(dateTimeLayout))
+	page.Text(PageWidth-margin-TextWidth(generated, Regular, detailSize), layout.y, Regular, detailSize, generated)
+	layout.y -= 8
+	page.Line(margin, layout.y, PageWidth-margin, layout.y, 1)
+
+	total, finished := 0, 0
+	for _, group := range r.groups {
+		total += len(group.tasks)
+		finished += countFinished(group.tasks)
+	}
+	layout.y -= textSize + 10
+	page.SetGray(0)
+	page.Text(margin, layout.y, Regular, textSize, fmt.Sprintf(msgs.Summary, total, finished, total-finished))
+	if total == 0 {
+		layout.y -= itemHeight * 2
+		page.Text(margin, layout.y, Regular, textSize, msgs.NoTasks)
+	}
+	layout.y -= 10
+}
+
+func (r *Report) writeGroupHeader(layout *reportLayout, group reportGroup, name string) {
+	page := layout.page
+	layout.y -= groupHeight - 6
+	page.SetGray(0)
+	page.Text(margin, layout.y, Bold, groupSize, name)
+	summary := fmt.Sprintf(msgs.GroupSummary, countFinished(group.tasks), len(group.tasks))
+	page.SetGray(detailGray)
+	page.Text(PageWidth-margin-TextWidth(summary, Regular, detailSize), layout.y, Regular, detailSize, summary)
+	layout.y -= 6
+	page.Line(margin, layout.y, PageWidth-margin, layout.y, 0.5)
+}
+
+// writeItem draws a checkbox, checked and with the description struck through when the task is finished
+func writeItem(layout *reportLayout, task domain.Task) {
+	page := layout.page
+	layout.y -= itemHeight
+	x := margin + itemIndent
+	textX := x + checkboxSize + 8
+	page.SetGray(0)
+	page.Rectangle(x, layout.y-1, checkboxSize, checkboxSize, 0.75)
+	if task.Finished() {
+		page.Line(x+2, layout.y+3, x+4, layout.y+1, 1)
+		page.Line(x+4, layout.y+1, x+8, layout.y+7, 1)
+		page.SetGray(finishedGray)
+	}
+	page.Text(textX, layout.y, Regular, textSize, task.Description())
+	if task.Finished() {
+		strikeY := layout.y + textSize*0.3
+		page.Line(textX, strikeY, textX+TextWidth(task.Description(), Regular, textSize), strikeY, 0.75)
+	}
+	if task.DueDate() != nil {
+		due := fmt.Sprintf(msgs.Due, task.DueDate().Format(dateLayout))
+		page.SetGray(detailGray)
+		page.Text(PageWidth-margin-TextWidth(due, Regular, detailSize), layout.y, Regular, detailSize, due)
+	}
+}
+
+func countFinished(tasks []domain.Task) int {
+	finished := 0
+	for _, task := range tasks {
+		if task.Finished() {
+			finished++
+		}
+	}
+	return finished
+}
+
+// reportLayout follows the page being written and the height left on it, from the top margin down
+type reportLayout struct {
+	document *Document
+	page     *Page
+	y        float64
+}
+
+func (l *reportLayout) newPage() {
+	l.page = l.document.AddPage()
+	l.y = PageHeight - margin
+}
+
+// reserve starts a new page when the current one has not the height left, telling whether it did
+func (l *reportLayout) reserve(height float64) bool {
+	if l.y-height >= margin {
+		return false
+	}
+	l.newPage()
+	return true
+}
@@ -0,0 +1,9 @@
+package repositoryerrors
+
+type PreconditionFailed struct {
+	*repositoryError
+}
+
+func NewPreconditionFailedError(message string, err error) *PreconditionFailed {
+	return &PreconditionFailed{newRepositoryError(message, err)}
+}
@@ -0,0 +1,15 @@
+package todoerrors
+
+import "todo/src/core/projecterrors/todoerrors/msgs"
+
+type FailedDependency struct {
+	message string
+}
+
+func NewFailedDependencyError() *FailedDependency {
+	return &FailedDependency{msgs.FailedDependencyError}
+}
+
+func (err FailedDependency) Error() string {
+	return err.message
+}
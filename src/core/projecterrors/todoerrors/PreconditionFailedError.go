@@ -0,0 +1,15 @@
+package todoerrors
+
+import "todo/src/core/projecterrors/todoerrors/msgs"
+
+type PreconditionFailed struct {
+	message string
+}
+
+func NewPreconditionFailedError() *PreconditionFailed {
+	return &PreconditionFailed{msgs.PreconditionFailedError}
+}
+
+func (err PreconditionFailed) Error() string {
+	return err.message
+}
@@ -20,6 +20,8 @@ func ConvertRepositoryErrorToServiceError(err error, reporter interface{}) error
 		return handleNotFoundError()
 	case *repositoryerrors.Unauthorized:
 		return handleUnauthorizedError()
+	case *repositoryerrors.PreconditionFailed:
+		return handlePreconditionFailedError()
 	default:
 		return handleUnknownError(err, reporterType)
 	}
@@ -49,6 +51,10 @@ func handleUnauthorizedError() error {
 	return NewUnauthorizedError()
 }
 
+func handlePreconditionFailedError() error {
+	return NewPreconditionFailedError()
+}
+
 func handleServiceUnavailableError(err *repositoryerrors.ServiceUnavailable, reporter string) error {
 	log.Error(reporter, " - ", err.PredecessorError())
 	return NewUnexpectedInternalError(msgs.UnexpectedInternalError)
@@ -7,4 +7,6 @@ const (
 	FieldNotFound           = "Field not found."
 	EmptyFieldName          = "Name is empty! No field was added."
 	DefaultInvalidField     = "Invalid field! Check if you filled out the data correctly."
+	PreconditionFailedError = "The resource has been changed since it was last read. Fetch it again and retry."
+	FailedDependencyError   = "The operation was not applied because another operation it depends on has failed."
 )
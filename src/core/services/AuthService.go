@@ -5,6 +5,7 @@ import (
 	"github.com/labstack/gommon/log"
 	"golang.org/x/crypto/bcrypt"
 	"todo/src/core/domain"
+	eventbus "todo/src/core/interfaces/events"
 	"todo/src/core/interfaces/repository"
 	"todo/src/core/projecterrors/todoerrors"
 	errmsgs "todo/src/core/projecterrors/todoerrors/msgs"
@@ -13,10 +14,11 @@ import (
 
 type Auth struct {
 	repository repository.IAuth
+	emitter    eventbus.IEmitter
 }
 
-func NewAuthService(repository repository.IAuth) *Auth {
-	return &Auth{repository}
+func NewAuthService(repository repository.IAuth, emitter eventbus.IEmitter) *Auth {
+	return &Auth{repository, emitter}
 }
 
 func (s Auth) SignUp(account domain.Account) (*domain.Account, error) {
@@ -52,6 +54,7 @@ func (s Auth) SignUp(account domain.Account) (*domain.Account, error) {
 		token,
 	)
 
+	s.emitter.Emit(*domain.NewAccountSignedUp(account))
 	return &account, nil
 }
 
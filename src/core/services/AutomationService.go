@@ -0,0 +1,67 @@
+package services
+
+import (
+	"github.com/labstack/gommon/log"
+	"todo/src/core/domain"
+	"todo/src/core/interfaces/repository"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+// Automation manages the automations of the accounts, which are run by the automation engine
+type Automation struct {
+	repository repository.IAutomation
+}
+
+func NewAutomationService(repository repository.IAutomation) *Automation {
+	return &Automation{repository}
+}
+
+func (s Automation) Create(automation domain.Automation, userId int) (int, error) {
+	id, err := s.repository.Create(automation, userId)
+	if err != nil {
+		log.Error(err)
+		return -1, todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.Create)
+	}
+
+	return id, nil
+}
+
+func (s Automation) Update(automation domain.Automation, userId int) error {
+	err := s.repository.Update(automation, userId)
+	if err != nil {
+		log.Error(err)
+		return todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.Update)
+	}
+
+	return nil
+}
+
+func (s Automation) Delete(automationId, userId int) error {
+	err := s.repository.Delete(automationId, userId)
+	if err != nil {
+		log.Error(err)
+		return todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.Delete)
+	}
+
+	return nil
+}
+
+func (s Automation) FindById(automationId, userId int) (*domain.Automation, error) {
+	automation, err := s.repository.FindById(automationId, userId)
+	if err != nil {
+		log.Error(err)
+		return nil, todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.FindById)
+	}
+
+	return automation, nil
+}
+
+func (s Automation) FindAll(userId int) ([]domain.Automation, error) {
+	automationList, err := s.repository.FindAll(userId)
+	if err != nil {
+		log.Error(err)
+		return nil, todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.FindAll)
+	}
+
+	return automationList, nil
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"github.com/labstack/gommon/log"
+	"todo/src/core/domain"
+	"todo/src/core/interfaces/repository"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+// CalDav keeps the names and UIDs given by the CalDAV clients to their tasks, which are themselves created, updated
+// and deleted by the task service
+type CalDav struct {
+	repository repository.ICalDav
+}
+
+func NewCalDavService(repository repository.ICalDav) *CalDav {
+	return &CalDav{repository}
+}
+
+func (s CalDav) FindObjects(collectionId, userId int) ([]domain.CalendarObject, error) {
+	objects, err := s.repository.FindObjects(collectionId, userId)
+	if err != nil {
+		log.Error(err)
+		return nil, todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.FindObjects)
+	}
+
+	return objects, nil
+}
+
+func (s CalDav) FindObject(name string, collectionId, userId int) (*domain.CalendarObject, error) {
+	object, err := s.repository.FindObject(name, collectionId, userId)
+	if err != nil {
+		log.Error(err)
+		return nil, todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.FindObject)
+	}
+
+	return object, nil
+}
+
+func (s CalDav) SaveObject(object domain.CalendarObject, userId int) error {
+	err := s.repository.SaveObject(object, userId)
+	if err != nil {
+		log.Error(err)
+		return todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.SaveObject)
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"github.com/labstack/gommon/log"
+	"todo/src/core/domain"
+	"todo/src/core/interfaces/repository"
+	"todo/src/core/projecterrors/todoerrors"
+	"todo/src/core/services/msgs"
+)
+
+const calendarFeedTokenSize = 32
+
+type Calendar struct {
+	repository repository.ICalendar
+}
+
+func NewCalendarService(repository repository.ICalendar) *Calendar {
+	return &Calendar{repository}
+}
+
+// CreateFeedToken replaces the feed token of the account, so that the previous feed URL stops working
+func (s Calendar) CreateFeedToken(userId int) (string, error) {
+	token, err := newRandomToken(calendarFeedTokenSize)
+	if err != nil {
+		log.Error(err)
+		return "", todoerrors.NewUnexpectedInternalError(msgs.TokenGenerationError)
+	}
+
+	err = s.repository.SaveFeedToken(token, userId)
+	if err != nil {
+		log.Error(err)
+		return "", todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.SaveFeedToken)
+	}
+
+	return token, nil
+}
+
+func (s Calendar) FindFeedToken(userId int) (string, error) {
+	token, err := s.repository.FindFeedToken(userId)
+	if err != nil {
+		log.Error(err)
+		return "", todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.FindFeedToken)
+	}
+
+	return token, nil
+}
+
+func (s Calendar) DeleteFeedToken(userId int) error {
+	err := s.repository.DeleteFeedToken(userId)
+	if err != nil {
+		log.Error(err)
+		return todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.DeleteFeedToken)
+	}
+
+	return nil
+}
+
+// FindFeed resolves the token to its account, and to one of its collections when the collection ID is not zero
+func (s Calendar) FindFeed(token string, collectionId int) (*domain.CalendarFeed, error) {
+	feed, err := s.repository.FindFeed(token, collectionId)
+	if err != nil {
+		log.Error(err)
+		return nil, todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.FindFeed)
+	}
+
+	return feed, nil
+}
+
+// ExportTasks passes the tasks of the feed to write one at a time, ordered by ID
+func (s Calendar) ExportTasks(feed domain.CalendarFeed, write func(task domain.Task) error) error {
+	err := s.repository.ExportTasks(feed, write)
+	if err != nil {
+		log.Error(err)
+		return todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.ExportTasks)
+	}
+
+	return nil
+}
+
+// Import creates all the tasks in the collection, zero meaning no collection, or none of them
+func (s Calendar) Import(tasks []domain.Task, collectionId, userId int) ([]int, error) {
+	ids, err := s.repository.Import(tasks, collectionId, userId)
+	if err != nil {
+		log.Error(err)
+		return nil, todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.Import)
+	}
+
+	return ids, nil
+}
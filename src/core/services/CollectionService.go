@@ -2,17 +2,22 @@ package services
 
 import (
 	"github.com/labstack/gommon/log"
+	"time"
 	"todo/src/core/domain"
+	eventbus "todo/src/core/interfaces/events"
 	"todo/src/core/interfaces/repository"
 	"todo/src/core/projecterrors/todoerrors"
 )
 
 type Collection struct {
-	repository repository.ICollection
+	repository     repository.ICollection
+	undoRepository repository.IUndo
+	emitter        eventbus.IEmitter
 }
 
-func NewCollectionService(repository repository.ICollection) *Collection {
-	return &Collection{repository}
+func NewCollectionService(repository repository.ICollection, undoRepository repository.IUndo,
+	emitter eventbus.IEmitter) *Collection {
+	return &Collection{repository, undoRepository, emitter}
 }
 
 func (s Collection) Create(collection domain.Collection, userId int) (int, error) {
@@ -22,35 +27,135 @@ func (s Collection) Create(collection domain.Collection, userId int) (int, error
 		return -1, todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.Create)
 	}
 
+	stored := domain.NewCollection(id, collection.Name())
+	stored.SetVersion(1)
+	s.emitter.Emit(*domain.NewCollectionCreated(userId, *stored))
 	return id, nil
 }
 
+// Update tells the version the collection has been left with, the one following the version read before the change
 func (s Collection) Update(collection domain.Collection, userId int) error {
-	err := s.repository.Update(collection, userId)
+	previousCollection, err := s.repository.FindById(collection.Id(), userId)
+	if err != nil {
+		log.Error(err)
+		return todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.FindById)
+	}
+
+	err = s.repository.Update(collection, userId)
 	if err != nil {
 		log.Error(err)
 		return todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.Update)
 	}
 
+	stored := domain.NewCollection(collection.Id(), collection.Name())
+	stored.SetVersion(previousCollection.Version() + 1)
+	s.emitter.Emit(*domain.NewCollectionUpdated(userId, *stored))
 	return nil
 }
 
-func (s Collection) Delete(collectionId, userId int) error {
-	err := s.repository.Delete(collectionId, userId)
+// Delete returns a token that restores the collection as it has been read, the deletion being applied to any version
+// when none is given
+func (s Collection) Delete(collectionId, userId, version int) (string, error) {
+	previousCollection, err := s.repository.FindById(collectionId, userId)
 	if err != nil {
 		log.Error(err)
-		return todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.Delete)
+		return "", todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.FindById)
 	}
 
+	err = s.repository.Delete(collectionId, userId, version)
+	if err != nil {
+		log.Error(err)
+		return "", todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.Delete)
+	}
+
+	s.emitter.Emit(*domain.NewCollectionDeleted(userId, *previousCollection))
+	return recordUndo(s.undoRepository, domain.UndoCollectionDelete, nil, previousCollection, userId), nil
+}
+
+// Archive emits no event when the collection is already archived
+func (s Collection) Archive(collectionId, userId int) error {
+	collection, err := s.repository.FindById(collectionId, userId)
+	if err != nil {
+		log.Error(err)
+		return todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.FindById)
+	}
+	if collection.ArchivedAt() != nil {
+		return nil
+	}
+
+	err = s.repository.Archive(collectionId, userId)
+	if err != nil {
+		log.Error(err)
+		return todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.Archive)
+	}
+
+	archivedAt := time.Now()
+	collection.SetArchivedAt(&archivedAt)
+	collection.SetVersion(collection.Version() + 1)
+	s.emitter.Emit(*domain.NewCollectionUpdated(userId, *collection))
 	return nil
 }
 
-func (s Collection) FindAll(userId int) ([]domain.Collection, error) {
-	collectionList, err := s.repository.FindAll(userId)
+func (s Collection) FindById(collectionId, userId int, expansion domain.Expansion) (*domain.Collection, error) {
+	collection, err := s.repository.FindById(collectionId, userId)
 	if err != nil {
 		log.Error(err)
-		return nil, todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.FindAll)
+		return nil, todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.FindById)
+	}
+
+	if expansion.Includes(domain.ExpandTasks) {
+		taskList, err := s.repository.FindTasks(collectionId, userId)
+		if err != nil {
+			log.Error(err)
+			return nil, todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.FindTasks)
+		}
+		collection.SetTasks(taskList)
+	}
+	if expansion.Includes(domain.ExpandTags) {
+		tags, err := s.repository.FindTags(collectionId, userId)
+		if err != nil {
+			log.Error(err)
+			return nil, todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.FindTags)
+		}
+		collection.SetTags(tags)
+	}
+
+	return collection, nil
+}
+
+// FindByIds reads several collections at once, leaving out the IDs the user has no collection with
+func (s Collection) FindByIds(collectionIds []int, userId int) ([]domain.Collection, error) {
+	collectionList, err := s.repository.FindByIds(collectionIds, userId)
+	if err != nil {
+		log.Error(err)
+		return nil, todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.FindByIds)
 	}
 
 	return collectionList, nil
 }
+
+// FindTasksOfCollections reads the tasks of several collections at once, grouped by the ID of their collection
+func (s Collection) FindTasksOfCollections(collectionIds []int, userId int) (map[int][]domain.Task, error) {
+	taskList, err := s.repository.FindTasksOfCollections(collectionIds, userId)
+	if err != nil {
+		log.Error(err)
+		return nil, todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.FindTasksOfCollections)
+	}
+
+	tasksByCollection := map[int][]domain.Task{}
+	for _, task := range taskList {
+		tasksByCollection[task.Collection().Id()] = append(tasksByCollection[task.Collection().Id()], task)
+	}
+	return tasksByCollection, nil
+}
+
+func (s Collection) FindAll(userId int, filter domain.CollectionFilter,
+	pagination domain.Pagination) ([]domain.Collection, int, error) {
+	collectionList, total, err := s.repository.FindAll(userId, filter, pagination)
+	if err != nil {
+		log.Error(err)
+		return nil, 0, todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.FindAll)
+	}
+
+	return collectionList, total, nil
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"todo/src/core/domain"
+)
+
+type MockCollectionRepository struct {
+	mock.Mock
+}
+
+func (m *MockCollectionRepository) Create(collection domain.Collection, userId int) (int, error) {
+	args := m.Called(collection, userId)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockCollectionRepository) Update(collection domain.Collection, userId int) error {
+	args := m.Called(collection, userId)
+	return args.Error(0)
+}
+
+func (m *MockCollectionRepository) Delete(collectionId, userId, version int) error {
+	args := m.Called(collectionId, userId, version)
+	return args.Error(0)
+}
+
+func (m *MockCollectionRepository) Archive(collectionId, userId int) error {
+	args := m.Called(collectionId, userId)
+	return args.Error(0)
+}
+
+func (m *MockCollectionRepository) FindById(collectionId, userId int) (*domain.Collection, error) {
+	args := m.Called(collectionId, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.Collection), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCollectionRepository) FindByIds(collectionIds []int, userId int) ([]domain.Collection, error) {
+	args := m.Called(collectionIds, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.Collection), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCollectionRepository) FindTasks(collectionId, userId int) ([]domain.Task, error) {
+	args := m.Called(collectionId, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.Task), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCollectionRepository) FindTasksOfCollections(collectionIds []int, userId int) ([]domain.Task, error) {
+	args := m.Called(collectionIds, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.Task), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCollectionRepository) FindTags(collectionId, userId int) ([]string, error) {
+	args := m.Called(collectionId, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).([]string), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCollectionRepository) FindAll(userId int, filter domain.CollectionFilter,
+	pagination domain.Pagination) ([]domain.Collection, int, error) {
+	args := m.Called(userId, filter, pagination)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.Collection), args.Int(1), args.Error(2)
+	}
+	return nil, 0, args.Error(2)
+}
+
+func TestCollection_Update(t *testing.T) {
+	t.Run("should emit the version following the one read when the change is made without a version",
+		func(t *testing.T) {
+			repository := new(MockCollectionRepository)
+			collection := domain.NewCollection(3, "Work")
+			stored := domain.NewCollection(3, "Home")
+			stored.SetVersion(4)
+			repository.On("FindById", 3, 1).Return(stored, nil)
+			repository.On("Update", *collection, 1).Return(nil)
+			emitter := &recordingEmitter{}
+
+			err := NewCollectionService(repository, new(MockUndoRepository), emitter).Update(*collection, 1)
+
+			assert.NoError(t, err)
+			repository.AssertExpectations(t)
+			updated := emitter.events[0].(domain.CollectionUpdated).Collection()
+			assert.Equal(t, 5, updated.Version())
+			assert.Equal(t, "Work", updated.Name())
+		})
+}
+
+func TestCollection_Delete(t *testing.T) {
+	t.Run("should delete the collection without a version whatever its version, keeping the one read to restore",
+		func(t *testing.T) {
+			repository := new(MockCollectionRepository)
+			undoRepository := new(MockUndoRepository)
+			collection := domain.NewCollection(3, "Home")
+			collection.SetVersion(4)
+			repository.On("FindById", 3, 1).Return(collection, nil)
+			repository.On("Delete", 3, 1, 0).Return(nil)
+			undoRepository.On("Create", mock.Anything, 1).Return(nil)
+
+			token, err := NewCollectionService(repository, undoRepository, &recordingEmitter{}).Delete(3, 1, 0)
+
+			assert.NoError(t, err)
+			assert.NotEmpty(t, token)
+			repository.AssertExpectations(t)
+			operation := undoRepository.Calls[0].Arguments.Get(0).(domain.UndoOperation)
+			assert.Equal(t, "Home", operation.Collection().Name())
+		})
+}
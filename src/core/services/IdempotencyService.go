@@ -0,0 +1,69 @@
+package services
+
+import (
+	"github.com/labstack/gommon/log"
+	"time"
+	"todo/src/core/domain"
+	domainmsgs "todo/src/core/domain/msgs"
+	"todo/src/core/interfaces/repository"
+	"todo/src/core/projecterrors/todoerrors"
+	"todo/src/core/services/msgs"
+)
+
+type Idempotency struct {
+	repository repository.IIdempotency
+}
+
+func NewIdempotencyService(repository repository.IIdempotency) *Idempotency {
+	return &Idempotency{repository}
+}
+
+// Reserve claims the key for the request. It returns nil when the request must be processed, or the stored request
+// whose response must be replayed.
+func (s Idempotency) Reserve(request domain.IdempotentRequest, userId int) (*domain.IdempotentRequest, error) {
+	reserved, err := s.repository.Reserve(request, userId, time.Now().Add(-domain.IdempotencyKeyTTL))
+	if err != nil {
+		log.Error(err)
+		return nil, todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.Reserve)
+	}
+	if reserved {
+		return nil, nil
+	}
+
+	storedRequest, err := s.repository.FindByKey(request.Key(), userId)
+	if err != nil {
+		log.Error(err)
+		return nil, todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.FindByKey)
+	}
+	if storedRequest.Fingerprint() != request.Fingerprint() {
+		invalidFields := todoerrors.InvalidFields{}
+		invalidFields.AppendField(domainmsgs.IdempotencyKey, msgs.ReusedIdempotencyKey)
+		return nil, todoerrors.NewValidationError(domainmsgs.InvalidIdempotencyDetails, invalidFields)
+	}
+	if !storedRequest.Completed() {
+		return nil, todoerrors.NewConflictError(domainmsgs.IdempotencyKey)
+	}
+
+	return storedRequest, nil
+}
+
+func (s Idempotency) Complete(request domain.IdempotentRequest, userId int) error {
+	err := s.repository.Complete(request, userId)
+	if err != nil {
+		log.Error(err)
+		return todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.Complete)
+	}
+
+	return nil
+}
+
+// Release frees the key of a request that has failed, so that it can be retried
+func (s Idempotency) Release(request domain.IdempotentRequest, userId int) error {
+	err := s.repository.Release(request, userId)
+	if err != nil {
+		log.Error(err)
+		return todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.Release)
+	}
+
+	return nil
+}
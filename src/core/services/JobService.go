@@ -0,0 +1,58 @@
+package services
+
+import (
+	"github.com/labstack/gommon/log"
+	"todo/src/core/domain"
+	"todo/src/core/interfaces/repository"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+// Job inspects the background jobs for the administrators, the jobs being run by the runners of the instances
+type Job struct {
+	repository repository.IJob
+}
+
+func NewJobService(repository repository.IJob) *Job {
+	return &Job{repository}
+}
+
+func (s Job) FindAll(filter domain.JobFilter, pagination domain.Pagination) ([]domain.Job, int, error) {
+	jobList, total, err := s.repository.FindAll(filter, pagination)
+	if err != nil {
+		log.Error(err)
+		return nil, 0, todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.FindAll)
+	}
+
+	return jobList, total, nil
+}
+
+func (s Job) FindById(jobId int64) (*domain.Job, error) {
+	job, err := s.repository.FindById(jobId)
+	if err != nil {
+		log.Error(err)
+		return nil, todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.FindById)
+	}
+
+	return job, nil
+}
+
+func (s Job) FindSchedules() ([]domain.JobSchedule, error) {
+	scheduleList, err := s.repository.FindSchedules()
+	if err != nil {
+		log.Error(err)
+		return nil, todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.FindSchedules)
+	}
+
+	return scheduleList, nil
+}
+
+// Retry queues a failed job again with all its attempts, reporting a conflict when its schedule has queued another
+// job since
+func (s Job) Retry(jobId int64) error {
+	if err := s.repository.Retry(jobId); err != nil {
+		log.Error(err)
+		return todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.Retry)
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"github.com/labstack/gommon/log"
+	"todo/src/core/domain"
+	"todo/src/core/interfaces/repository"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+// Notification manages the reminders of the tasks and the notification preferences of the accounts, the reminders
+// being sent by the notifier
+type Notification struct {
+	repository repository.INotification
+}
+
+func NewNotificationService(repository repository.INotification) *Notification {
+	return &Notification{repository}
+}
+
+func (s Notification) CreateReminder(reminder domain.Reminder, userId int) (int, error) {
+	id, err := s.repository.CreateReminder(reminder, userId)
+	if err != nil {
+		log.Error(err)
+		return -1, todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.CreateReminder)
+	}
+
+	return id, nil
+}
+
+func (s Notification) DeleteReminder(reminderId, taskId, userId int) error {
+	err := s.repository.DeleteReminder(reminderId, taskId, userId)
+	if err != nil {
+		log.Error(err)
+		return todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.DeleteReminder)
+	}
+
+	return nil
+}
+
+func (s Notification) FindReminders(taskId, userId int) ([]domain.Reminder, error) {
+	reminderList, err := s.repository.FindReminders(taskId, userId)
+	if err != nil {
+		log.Error(err)
+		return nil, todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.FindReminders)
+	}
+
+	return reminderList, nil
+}
+
+func (s Notification) FindPreferences(userId int) (*domain.NotificationPreferences, error) {
+	preferences, err := s.repository.FindPreferences(userId)
+	if err != nil {
+		log.Error(err)
+		return nil, todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.FindPreferences)
+	}
+
+	return preferences, nil
+}
+
+func (s Notification) UpdatePreferences(preferences domain.NotificationPreferences, userId int) error {
+	err := s.repository.UpdatePreferences(preferences, userId)
+	if err != nil {
+		log.Error(err)
+		return todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.UpdatePreferences)
+	}
+
+	return nil
+}
@@ -2,17 +2,21 @@ package services
 
 import (
 	"github.com/labstack/gommon/log"
+	"slices"
 	"todo/src/core/domain"
+	eventbus "todo/src/core/interfaces/events"
 	"todo/src/core/interfaces/repository"
 	"todo/src/core/projecterrors/todoerrors"
 )
 
 type Task struct {
-	repository repository.ITask
+	repository     repository.ITask
+	undoRepository repository.IUndo
+	emitter        eventbus.IEmitter
 }
 
-func NewTaskService(repository repository.ITask) *Task {
-	return &Task{repository}
+func NewTaskService(repository repository.ITask, undoRepository repository.IUndo, emitter eventbus.IEmitter) *Task {
+	return &Task{repository, undoRepository, emitter}
 }
 
 func (s Task) Create(task domain.Task, userId int) (int, error) {
@@ -22,45 +26,242 @@ func (s Task) Create(task domain.Task, userId int) (int, error) {
 		return -1, todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.Create)
 	}
 
+	s.emit(userId, domain.ChangeActionCreated, storedTask(id, task, 1), nil)
 	return id, nil
 }
 
-func (s Task) Update(task domain.Task, userId int) error {
-	err := s.repository.Update(task, userId)
+// Update returns a token that reverses the change. The stored task is read first so that it can be restored. Without
+// a version, the change is applied to any version, and the undo expects the one following the version read, so that
+// it is refused when another change has come in between.
+func (s Task) Update(task domain.Task, userId int) (string, error) {
+	previousTask, err := s.repository.FindById(task.Id(), userId)
 	if err != nil {
 		log.Error(err)
-		return todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.Update)
+		return "", todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.FindById)
 	}
 
-	return nil
+	err = s.repository.Update(task, userId)
+	if err != nil {
+		log.Error(err)
+		return "", todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.Update)
+	}
+
+	version := previousTask.Version() + 1
+	s.emit(userId, domain.ChangeActionUpdated, storedTask(task.Id(), task, version), previousTask)
+	change := domain.NewTaskChange(task.Id(), previousTask, version)
+	return recordUndo(s.undoRepository, domain.UndoTaskUpdate, []domain.TaskChange{*change}, nil, userId), nil
 }
 
-func (s Task) Delete(taskId, userId int) error {
-	err := s.repository.Delete(taskId, userId)
+// Delete returns a token that restores the task as it has been read, the deletion being applied to any version when
+// none is given
+func (s Task) Delete(taskId, userId, version int) (string, error) {
+	previousTask, err := s.repository.FindById(taskId, userId)
+	if err != nil {
+		log.Error(err)
+		return "", todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.FindById)
+	}
+
+	err = s.repository.Delete(taskId, userId, version)
 	if err != nil {
 		log.Error(err)
-		return todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.Delete)
+		return "", todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.Delete)
 	}
 
-	return nil
+	s.emit(userId, domain.ChangeActionDeleted, nil, previousTask)
+	change := domain.NewTaskChange(taskId, previousTask, 0)
+	return recordUndo(s.undoRepository, domain.UndoTaskDelete, []domain.TaskChange{*change}, nil, userId), nil
 }
 
-func (s Task) FindAll(userId int) ([]domain.Task, error) {
-	taskList, err := s.repository.FindAll(userId)
+func (s Task) FindById(taskId, userId int) (*domain.Task, error) {
+	task, err := s.repository.FindById(taskId, userId)
 	if err != nil {
 		log.Error(err)
-		return nil, todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.FindAll)
+		return nil, todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.FindById)
 	}
 
-	return taskList, nil
+	return task, nil
 }
 
-func (s Task) FindByCollectionId(collectionId, userId int) ([]domain.Task, error) {
-	taskList, err := s.repository.FindByCollectionId(collectionId, userId)
+// Batch returns a token that reverses the applied operations along with the results. The tasks are read before the
+// batch so that they can be restored, the operations without a version being applied to any version as Update does.
+func (s Task) Batch(batch domain.TaskBatch, userId int) (*domain.TaskBatchResult, error) {
+	previousTasks := map[int]*domain.Task{}
+	var taskIds []int
+	for _, operation := range batch.Operations() {
+		if operation.Kind() == domain.TaskOperationCreate {
+			continue
+		}
+		if _, ok := previousTasks[operation.TaskId()]; ok {
+			continue
+		}
+
+		previousTask, err := s.repository.FindById(operation.TaskId(), userId)
+		if err != nil {
+			log.Error(err)
+			continue
+		}
+		previousTasks[operation.TaskId()] = previousTask
+		taskIds = append(taskIds, operation.TaskId())
+	}
+
+	batchResult, err := s.repository.Batch(batch, userId)
 	if err != nil {
 		log.Error(err)
-		return nil, todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.FindByCollectionId)
+		return nil, todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.Batch)
+	}
+
+	results := make([]domain.TaskOperationResult, len(batchResult.Results()))
+	for index, result := range batchResult.Results() {
+		var operationErr error
+		if result.Err() != nil {
+			operationErr = todoerrors.ConvertRepositoryErrorToServiceError(result.Err(), s.repository.Batch)
+		} else if !batchResult.Committed() {
+			operationErr = todoerrors.NewFailedDependencyError()
+		}
+		results[index] = *domain.NewTaskOperationResult(result.TaskId(), operationErr)
+	}
+
+	newBatchResult := domain.NewTaskBatchResult(results, batchResult.Committed())
+	if batchResult.Committed() {
+		taskChanges := s.batchChanges(batch, *batchResult, taskIds, previousTasks)
+		s.emitBatch(batch, *batchResult, taskChanges, previousTasks, userId)
+		newBatchResult.SetUndoToken(recordUndo(s.undoRepository, domain.UndoTaskBatch, taskChanges, nil, userId))
+	}
+
+	return newBatchResult, nil
+}
+
+// batchChanges tells, for each task affected by the batch, the version it has been left with, counted from the version
+// read before the batch
+func (s Task) batchChanges(batch domain.TaskBatch, batchResult domain.TaskBatchResult, taskIds []int,
+	previousTasks map[int]*domain.Task) []domain.TaskChange {
+	var taskChanges []domain.TaskChange
+	for index, result := range batchResult.Results() {
+		if result.Err() == nil && batch.Operations()[index].Kind() == domain.TaskOperationCreate {
+			taskChanges = append(taskChanges, *domain.NewTaskChange(result.TaskId(), nil, 1))
+		}
+	}
+
+	for _, taskId := range taskIds {
+		previousTask := previousTasks[taskId]
+		version := previousTask.Version()
+		applied := false
+		for index, result := range batchResult.Results() {
+			operation := batch.Operations()[index]
+			if operation.Kind() == domain.TaskOperationCreate || operation.TaskId() != taskId || result.Err() != nil {
+				continue
+			}
+			applied = true
+			if operation.Kind() == domain.TaskOperationDelete {
+				version = 0
+			} else {
+				version++
+			}
+		}
+		if applied {
+			taskChanges = append(taskChanges, *domain.NewTaskChange(taskId, previousTask, version))
+		}
+	}
+
+	return taskChanges
+}
+
+// emitBatch tells about each task affected by the batch once, with the state it has been left with
+func (s Task) emitBatch(batch domain.TaskBatch, batchResult domain.TaskBatchResult, taskChanges []domain.TaskChange,
+	previousTasks map[int]*domain.Task, userId int) {
+	for _, change := range taskChanges {
+		var task *domain.Task
+		for index, result := range batchResult.Results() {
+			operation := batch.Operations()[index]
+			if result.Err() == nil && result.TaskId() == change.TaskId() && operation.Task() != nil {
+				task = storedTask(change.TaskId(), *operation.Task(), change.Version())
+			}
+		}
+
+		action := domain.ChangeActionUpdated
+		if previousTasks[change.TaskId()] == nil {
+			action = domain.ChangeActionCreated
+		} else if change.Version() == 0 {
+			action = domain.ChangeActionDeleted
+			task = nil
+		}
+		s.emit(userId, action, task, previousTasks[change.TaskId()])
+	}
+}
+
+// emit tells the subscribers of the domain events about the change made to the task, whose state is nil when it has
+// been deleted
+func (s Task) emit(userId int, action string, task, previousTask *domain.Task) {
+	switch {
+	case action == domain.ChangeActionCreated && task != nil:
+		s.emitter.Emit(*domain.NewTaskCreated(userId, *task))
+	case action == domain.ChangeActionUpdated && task != nil && previousTask != nil:
+		s.emitter.Emit(*domain.NewTaskUpdated(userId, *task, *previousTask))
+		if task.Finished() && !previousTask.Finished() {
+			s.emitter.Emit(*domain.NewTaskFinished(userId, *task))
+		}
+	case action == domain.ChangeActionDeleted && previousTask != nil:
+		s.emitter.Emit(*domain.NewTaskDeleted(userId, *previousTask))
+	}
+}
+
+// storedTask is the task as it has been stored with its ID and version
+func storedTask(taskId int, task domain.Task, version int) *domain.Task {
+	stored := domain.NewTask(taskId, task.Description(), task.Finished(), task.Collection())
+	stored.SetCreatedAt(task.CreatedAt())
+	stored.SetDueDate(task.DueDate())
+	stored.SetTags(task.Tags())
+	stored.SetVersion(version)
+	return stored
+}
+
+func (s Task) FindAll(userId int, filter domain.TaskFilter, pagination domain.Pagination) ([]domain.Task, int, error) {
+	taskList, total, err := s.repository.FindAll(userId, filter, pagination)
+	if err != nil {
+		log.Error(err)
+		return nil, 0, todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.FindAll)
+	}
+
+	return taskList, total, nil
+}
+
+func (s Task) FindByCollectionId(collectionId, userId int, filter domain.TaskFilter,
+	pagination domain.Pagination) ([]domain.Task, int, error) {
+	taskList, total, err := s.repository.FindByCollectionId(collectionId, userId, filter, pagination)
+	if err != nil {
+		log.Error(err)
+		return nil, 0, todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.FindByCollectionId)
+	}
+
+	return taskList, total, nil
+}
+
+func (s Task) FindPage(userId int, filter domain.TaskFilter,
+	pagination domain.KeysetPagination) (*domain.TaskPage, error) {
+	taskList, err := s.repository.FindPage(userId, filter, pagination)
+	if err != nil {
+		log.Error(err)
+		return nil, todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.FindPage)
+	}
+
+	hasMore := len(taskList) > pagination.Limit()
+	if hasMore {
+		taskList = taskList[:pagination.Limit()]
+	}
+	if pagination.IsBackward() {
+		slices.Reverse(taskList)
+	}
+	if len(taskList) == 0 {
+		return domain.NewTaskPage(taskList, nil, nil), nil
+	}
+
+	var next, previous *domain.Cursor
+	if hasMore || pagination.IsBackward() {
+		next = pagination.CursorFor(taskList[len(taskList)-1], false)
+	}
+	if (hasMore && pagination.IsBackward()) || (pagination.Cursor() != nil && !pagination.IsBackward()) {
+		previous = pagination.CursorFor(taskList[0], true)
 	}
 
-	return taskList, nil
+	return domain.NewTaskPage(taskList, next, previous), nil
 }
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+	"todo/src/core/domain"
+	"todo/src/core/projecterrors/repositoryerrors"
+)
+
+type MockTaskRepository struct {
+	mock.Mock
+}
+
+func (m *MockTaskRepository) Create(task domain.Task, userId int) (int, error) {
+	args := m.Called(task, userId)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTaskRepository) Update(task domain.Task, userId int) error {
+	args := m.Called(task, userId)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepository) Delete(taskId, userId, version int) error {
+	args := m.Called(taskId, userId, version)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepository) FindById(taskId, userId int) (*domain.Task, error) {
+	args := m.Called(taskId, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.Task), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockTaskRepository) Batch(batch domain.TaskBatch, userId int) (*domain.TaskBatchResult, error) {
+	args := m.Called(batch, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.TaskBatchResult), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockTaskRepository) FindAll(userId int, filter domain.TaskFilter,
+	pagination domain.Pagination) ([]domain.Task, int, error) {
+	args := m.Called(userId, filter, pagination)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.Task), args.Int(1), args.Error(2)
+	}
+	return nil, 0, args.Error(2)
+}
+
+func (m *MockTaskRepository) FindByCollectionId(collectionId, userId int, filter domain.TaskFilter,
+	pagination domain.Pagination) ([]domain.Task, int, error) {
+	args := m.Called(collectionId, userId, filter, pagination)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.Task), args.Int(1), args.Error(2)
+	}
+	return nil, 0, args.Error(2)
+}
+
+func (m *MockTaskRepository) FindPage(userId int, filter domain.TaskFilter,
+	pagination domain.KeysetPagination) ([]domain.Task, error) {
+	args := m.Called(userId, filter, pagination)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.Task), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+type MockUndoRepository struct {
+	mock.Mock
+}
+
+func (m *MockUndoRepository) Create(operation domain.UndoOperation, userId int) error {
+	args := m.Called(operation, userId)
+	return args.Error(0)
+}
+
+func (m *MockUndoRepository) Undo(token string, userId int, now time.Time) error {
+	args := m.Called(token, userId, now)
+	return args.Error(0)
+}
+
+// recordingEmitter keeps the events emitted by the services
+type recordingEmitter struct {
+	events []domain.DomainEvent
+}
+
+func (e *recordingEmitter) Emit(event domain.DomainEvent) {
+	e.events = append(e.events, event)
+}
+
+func newStoredTask(taskId int, description string, version int) *domain.Task {
+	task := domain.NewTask(taskId, description, false, nil)
+	task.SetVersion(version)
+	return task
+}
+
+// recordedChanges returns the task changes of the undo operation stored by the test
+func recordedChanges(undoRepository *MockUndoRepository) []domain.TaskChange {
+	operation := undoRepository.Calls[0].Arguments.Get(0).(domain.UndoOperation)
+	return operation.TaskChanges()
+}
+
+func TestTask_Update(t *testing.T) {
+	t.Run("should apply the change without a version to any version, expecting the next one in the undo",
+		func(t *testing.T) {
+			repository := new(MockTaskRepository)
+			undoRepository := new(MockUndoRepository)
+			task := domain.NewTask(5, "Read", false, nil)
+			repository.On("FindById", 5, 1).Return(newStoredTask(5, "Write", 3), nil)
+			repository.On("Update", *task, 1).Return(nil)
+			undoRepository.On("Create", mock.Anything, 1).Return(nil)
+			emitter := &recordingEmitter{}
+
+			token, err := NewTaskService(repository, undoRepository, emitter).Update(*task, 1)
+
+			assert.NoError(t, err)
+			assert.NotEmpty(t, token)
+			repository.AssertExpectations(t)
+			changes := recordedChanges(undoRepository)
+			assert.Len(t, changes, 1)
+			assert.Equal(t, 4, changes[0].Version())
+			assert.Equal(t, "Write", changes[0].Previous().Description())
+			assert.Equal(t, 4, emitter.events[0].(domain.TaskUpdated).Task().Version())
+		})
+
+	t.Run("should fail when the version given is no longer the stored one", func(t *testing.T) {
+		repository := new(MockTaskRepository)
+		undoRepository := new(MockUndoRepository)
+		task := newStoredTask(5, "Read", 2)
+		repository.On("FindById", 5, 1).Return(newStoredTask(5, "Write", 3), nil)
+		repository.On("Update", *task, 1).Return(repositoryerrors.NewPreconditionFailedError("", nil))
+
+		_, err := NewTaskService(repository, undoRepository, &recordingEmitter{}).Update(*task, 1)
+
+		assert.Error(t, err)
+		undoRepository.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTask_Delete(t *testing.T) {
+	t.Run("should delete the task without a version whatever its version", func(t *testing.T) {
+		repository := new(MockTaskRepository)
+		undoRepository := new(MockUndoRepository)
+		repository.On("FindById", 5, 1).Return(newStoredTask(5, "Write", 3), nil)
+		repository.On("Delete", 5, 1, 0).Return(nil)
+		undoRepository.On("Create", mock.Anything, 1).Return(nil)
+
+		_, err := NewTaskService(repository, undoRepository, &recordingEmitter{}).Delete(5, 1, 0)
+
+		assert.NoError(t, err)
+		repository.AssertExpectations(t)
+		changes := recordedChanges(undoRepository)
+		assert.Equal(t, 0, changes[0].Version())
+		assert.Equal(t, 3, changes[0].Previous().Version())
+	})
+}
+
+func TestTask_Batch(t *testing.T) {
+	t.Run("should pass the operations unchanged and count the versions left from the ones read", func(t *testing.T) {
+		repository := new(MockTaskRepository)
+		undoRepository := new(MockUndoRepository)
+		batch := domain.NewTaskBatch([]domain.TaskOperation{
+			*domain.NewTaskOperation(domain.TaskOperationUpdate, 5, 0, domain.NewTask(5, "Read", false, nil)),
+			*domain.NewTaskOperation(domain.TaskOperationUpdate, 5, 0, domain.NewTask(5, "Read", true, nil)),
+			*domain.NewTaskOperation(domain.TaskOperationDelete, 6, 0, nil),
+			*domain.NewTaskOperation(domain.TaskOperationCreate, 0, 0, domain.NewTask(0, "Sing", false, nil)),
+		}, true)
+		repository.On("FindById", 5, 1).Return(newStoredTask(5, "Write", 3), nil)
+		repository.On("FindById", 6, 1).Return(newStoredTask(6, "Draw", 7), nil)
+		repository.On("Batch", *batch, 1).Return(domain.NewTaskBatchResult([]domain.TaskOperationResult{
+			*domain.NewTaskOperationResult(5, nil),
+			*domain.NewTaskOperationResult(5, nil),
+			*domain.NewTaskOperationResult(6, nil),
+			*domain.NewTaskOperationResult(8, nil),
+		}, true), nil)
+		undoRepository.On("Create", mock.Anything, 1).Return(nil)
+
+		batchResult, err := NewTaskService(repository, undoRepository, &recordingEmitter{}).Batch(*batch, 1)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, batchResult.UndoToken())
+		repository.AssertExpectations(t)
+		versions := map[int]int{}
+		for _, change := range recordedChanges(undoRepository) {
+			versions[change.TaskId()] = change.Version()
+		}
+		assert.Equal(t, map[int]int{5: 5, 6: 0, 8: 1}, versions)
+	})
+
+	t.Run("should leave out of the undo the operations that have failed", func(t *testing.T) {
+		repository := new(MockTaskRepository)
+		undoRepository := new(MockUndoRepository)
+		batch := domain.NewTaskBatch([]domain.TaskOperation{
+			*domain.NewTaskOperation(domain.TaskOperationUpdate, 5, 0, domain.NewTask(5, "Read", false, nil)),
+			*domain.NewTaskOperation(domain.TaskOperationDelete, 6, 2, nil),
+		}, false)
+		repository.On("FindById", 5, 1).Return(newStoredTask(5, "Write", 3), nil)
+		repository.On("FindById", 6, 1).Return(newStoredTask(6, "Draw", 7), nil)
+		repository.On("Batch", *batch, 1).Return(domain.NewTaskBatchResult([]domain.TaskOperationResult{
+			*domain.NewTaskOperationResult(5, nil),
+			*domain.NewTaskOperationResult(6, repositoryerrors.NewPreconditionFailedError("", nil)),
+		}, true), nil)
+		undoRepository.On("Create", mock.Anything, 1).Return(nil)
+
+		batchResult, err := NewTaskService(repository, undoRepository, &recordingEmitter{}).Batch(*batch, 1)
+
+		assert.NoError(t, err)
+		assert.Error(t, batchResult.Results()[1].Err())
+		changes := recordedChanges(undoRepository)
+		assert.Len(t, changes, 1)
+		assert.Equal(t, 5, changes[0].TaskId())
+		assert.Equal(t, 4, changes[0].Version())
+	})
+}
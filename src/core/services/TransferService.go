@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/labstack/gommon/log"
+	"time"
+	"todo/src/core/domain"
+	"todo/src/core/interfaces/repository"
+	"todo/src/core/projecterrors/todoerrors"
+	errmsgs "todo/src/core/projecterrors/todoerrors/msgs"
+)
+
+const (
+	// ImportJobKind is the kind of the jobs importing a transfer in the background
+	ImportJobKind = "transfer.import"
+	// importJobAttempts is a single attempt, as an import committed by an instance that stops before marking its job as
+	// succeeded would be imported twice if the job were run again
+	importJobAttempts = 1
+)
+
+type Transfer struct {
+	repository    repository.ITransfer
+	jobRepository repository.IJob
+}
+
+func NewTransferService(repository repository.ITransfer, jobRepository repository.IJob) *Transfer {
+	return &Transfer{repository, jobRepository}
+}
+
+// Export passes the collections and then the tasks of the account to write one at a time, ordered by ID, as they were
+// at the same instant
+func (s Transfer) Export(userId int, writeCollection func(collection domain.Collection) error,
+	writeTask func(task domain.Task) error) error {
+	err := s.repository.Export(userId, writeCollection, writeTask)
+	if err != nil {
+		log.Error(err)
+		return todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.Export)
+	}
+
+	return nil
+}
+
+// ExportCollections passes the collections of the account to write one at a time, ordered by ID
+func (s Transfer) ExportCollections(userId int, write func(collection domain.Collection) error) error {
+	err := s.repository.ExportCollections(userId, write)
+	if err != nil {
+		log.Error(err)
+		return todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.ExportCollections)
+	}
+
+	return nil
+}
+
+// ExportTasks passes the tasks of the account to write one at a time, ordered by ID
+func (s Transfer) ExportTasks(userId int, write func(task domain.Task) error) error {
+	err := s.repository.ExportTasks(userId, write)
+	if err != nil {
+		log.Error(err)
+		return todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.ExportTasks)
+	}
+
+	return nil
+}
+
+// Import creates all the collections and tasks of the transfer, or none of them
+func (s Transfer) Import(transfer domain.Transfer, userId int) (*domain.TransferResult, error) {
+	result, err := s.repository.Import(context.Background(), transfer, userId)
+	if err != nil {
+		log.Error(err)
+		return nil, todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.Import)
+	}
+
+	return result, nil
+}
+
+// EnqueueImport queues a job importing the transfer for the account, returning the ID of the job, whose status tells
+// when the import has been made
+func (s Transfer) EnqueueImport(transfer domain.Transfer, userId int) (int64, error) {
+	payload, err := json.Marshal(newImportPayload(transfer))
+	if err != nil {
+		log.Error(err)
+		return -1, todoerrors.NewUnexpectedInternalError(errmsgs.UnexpectedInternalError)
+	}
+	job := domain.NewJob(0, ImportJobKind, payload, time.Now())
+	job.SetState(domain.JobQueued, 0, importJobAttempts, "")
+	job.SetUserId(userId)
+
+	jobId, err := s.jobRepository.Enqueue(*job)
+	if err != nil {
+		log.Error(err)
+		return -1, todoerrors.ConvertRepositoryErrorToServiceError(err, s.jobRepository.Enqueue)
+	}
+
+	return jobId, nil
+}
+
+// FindImport finds a job importing a transfer for the account
+func (s Transfer) FindImport(jobId int64, userId int) (*domain.Job, error) {
+	job, err := s.jobRepository.FindByIdOfUser(jobId, userId)
+	if err != nil {
+		log.Error(err)
+		return nil, todoerrors.ConvertRepositoryErrorToServiceError(err, s.jobRepository.FindByIdOfUser)
+	}
+	if job.Kind() != ImportJobKind {
+		return nil, todoerrors.NewNotFoundError()
+	}
+
+	return job, nil
+}
+
+// RunImport imports the transfer queued by EnqueueImport, as a job, rolling it back when the lease of the job expires
+func (s Transfer) RunImport(ctx context.Context, job domain.Job) error {
+	var payload importPayload
+	if err := json.Unmarshal(job.Payload(), &payload); err != nil {
+		return err
+	}
+
+	_, err := s.repository.Import(ctx, payload.convertToDomain(), job.UserId())
+	return err
+}
+
+// ImportTasks creates all the tasks or none of them, in the collections of the account with the IDs of their
+// collections, or else with their names, the collections being created when the account has none
+func (s Transfer) ImportTasks(tasks []domain.Task, userId int) ([]int, error) {
+	ids, err := s.repository.ImportTasks(tasks, userId)
+	if err != nil {
+		log.Error(err)
+		return nil, todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.ImportTasks)
+	}
+
+	return ids, nil
+}
+
+// importPayload is the transfer kept in the job importing it, with the IDs of the file
+type importPayload struct {
+	Collections []importCollection `json:"collections"`
+	Tasks       []importTask       `json:"tasks"`
+}
+
+type importCollection struct {
+	Id        int        `json:"id"`
+	Name      string     `json:"name"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+type importTask struct {
+	Id           int        `json:"id"`
+	Description  string     `json:"description"`
+	Finished     bool       `json:"finished"`
+	CollectionId int        `json:"collection_id,omitempty"`
+	CreatedAt    *time.Time `json:"created_at,omitempty"`
+	DueDate      *time.Time `json:"due_date,omitempty"`
+	Tags         []string   `json:"tags,omitempty"`
+}
+
+func newImportPayload(transfer domain.Transfer) importPayload {
+	payload := importPayload{}
+	for _, collection := range transfer.Collections() {
+		payload.Collections = append(payload.Collections, importCollection{
+			Id:        collection.Id(),
+			Name:      collection.Name(),
+			CreatedAt: optionalTime(collection.CreatedAt()),
+		})
+	}
+	for _, task := range transfer.Tasks() {
+		payload.Tasks = append(payload.Tasks, importTask{
+			Id:           task.Id(),
+			Description:  task.Description(),
+			Finished:     task.Finished(),
+			CollectionId: task.Collection().Id(),
+			CreatedAt:    optionalTime(task.CreatedAt()),
+			DueDate:      task.DueDate(),
+			Tags:         task.Tags(),
+		})
+	}
+
+	return payload
+}
+
+func (p importPayload) convertToDomain() domain.Transfer {
+	var collections []domain.Collection
+	for _, row := range p.Collections {
+		collection := domain.NewCollection(row.Id, row.Name)
+		if row.CreatedAt != nil {
+			collection.SetCreatedAt(*row.CreatedAt)
+		}
+		collections = append(collections, *collection)
+	}
+	var tasks []domain.Task
+	for _, row := range p.Tasks {
+		task := domain.NewTask(row.Id, row.Description, row.Finished, domain.NewCollection(row.CollectionId, ""))
+		if row.CreatedAt != nil {
+			task.SetCreatedAt(*row.CreatedAt)
+		}
+		task.SetDueDate(row.DueDate)
+		task.SetTags(row.Tags)
+		tasks = append(tasks, *task)
+	}
+
+	return *domain.NewTransfer(collections, tasks)
+}
+
+// optionalTime is nil for the zero time
+func optionalTime(value time.Time) *time.Time {
+	if value.IsZero() {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+	"todo/src/core/domain"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+type MockTransferRepository struct {
+	mock.Mock
+}
+
+func (m *MockTransferRepository) Export(userId int, writeCollection func(collection domain.Collection) error,
+	writeTask func(task domain.Task) error) error {
+	args := m.Called(userId, writeCollection, writeTask)
+	return args.Error(0)
+}
+
+func (m *MockTransferRepository) ExportCollections(userId int, write func(collection domain.Collection) error) error {
+	args := m.Called(userId, write)
+	return args.Error(0)
+}
+
+func (m *MockTransferRepository) ExportTasks(userId int, write func(task domain.Task) error) error {
+	args := m.Called(userId, write)
+	return args.Error(0)
+}
+
+func (m *MockTransferRepository) Import(ctx context.Context, transfer domain.Transfer,
+	userId int) (*domain.TransferResult, error) {
+	args := m.Called(ctx, transfer, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.TransferResult), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockTransferRepository) ImportTasks(tasks []domain.Task, userId int) ([]int, error) {
+	args := m.Called(tasks, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).([]int), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// memoryJobs keeps the queued jobs, only the methods used by the transfers doing anything
+type memoryJobs struct {
+	jobs []domain.Job
+}
+
+func (m *memoryJobs) Enqueue(job domain.Job) (int64, error) {
+	m.jobs = append(m.jobs, *domain.NewJob(int64(len(m.jobs)+1), job.Kind(), job.Payload(), job.RunAt()))
+	stored := &m.jobs[len(m.jobs)-1]
+	stored.SetState(job.Status(), job.Attempts(), job.MaxAttempts(), job.LastError())
+	stored.SetUserId(job.UserId())
+	return stored.Id(), nil
+}
+
+func (m *memoryJobs) FindByIdOfUser(jobId int64, userId int) (*domain.Job, error) {
+	for _, job := range m.jobs {
+		if job.Id() == jobId && job.UserId() == userId {
+			return &job, nil
+		}
+	}
+	return nil, todoerrors.NewNotFoundError()
+}
+
+func (m *memoryJobs) Claim(string, []string, int, time.Time) ([]domain.Job, error) { return nil, nil }
+func (m *memoryJobs) Complete(int64, string) error                                 { return nil }
+func (m *memoryJobs) Fail(int64, string, string, time.Time, bool) error            { return nil }
+func (m *memoryJobs) SaveSchedules([]domain.JobSchedule, time.Time) error          { return nil }
+func (m *memoryJobs) EnqueueScheduled(time.Time) (int, error)                      { return 0, nil }
+func (m *memoryJobs) FindSchedules() ([]domain.JobSchedule, error)                 { return nil, nil }
+func (m *memoryJobs) FindById(int64) (*domain.Job, error)                          { return nil, nil }
+func (m *memoryJobs) Retry(int64) error                                            { return nil }
+func (m *memoryJobs) DeleteFinished(time.Time, time.Time) error                    { return nil }
+func (m *memoryJobs) FindAll(domain.JobFilter, domain.Pagination) ([]domain.Job, int, error) {
+	return nil, 0, nil
+}
+
+func TestTransfer_EnqueueImport(t *testing.T) {
+	t.Run("should queue the transfer for a single attempt and import it as it was within the lease of the job",
+		func(t *testing.T) {
+			repository := new(MockTransferRepository)
+			jobs := &memoryJobs{}
+			service := NewTransferService(repository, jobs)
+			createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+			collection := domain.NewCollection(7, "Study")
+			collection.SetCreatedAt(createdAt)
+			task := domain.NewTask(1, "Study Math", true, domain.NewCollection(7, ""))
+			task.SetDueDate(&createdAt)
+			task.SetTags([]string{"school"})
+			transfer := domain.NewTransfer([]domain.Collection{*collection}, []domain.Task{*task})
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			repository.On("Import", ctx, *transfer, 3).Return(domain.NewTransferResult(nil, nil), nil)
+
+			jobId, err := service.EnqueueImport(*transfer, 3)
+			assert.NoError(t, err)
+			job, err := service.FindImport(jobId, 3)
+			assert.NoError(t, err)
+			err = service.RunImport(ctx, *job)
+
+			assert.NoError(t, err)
+			assert.Equal(t, ImportJobKind, job.Kind())
+			assert.Equal(t, 1, job.MaxAttempts())
+			repository.AssertExpectations(t)
+		})
+
+	t.Run("should not find the import of another user", func(t *testing.T) {
+		jobs := &memoryJobs{}
+		service := NewTransferService(new(MockTransferRepository), jobs)
+		jobId, _ := service.EnqueueImport(*domain.NewTransfer(nil, nil), 3)
+
+		_, err := service.FindImport(jobId, 4)
+
+		assert.Error(t, err)
+	})
+}
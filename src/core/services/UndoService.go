@@ -0,0 +1,53 @@
+package services
+
+import (
+	"github.com/labstack/gommon/log"
+	"time"
+	"todo/src/core/domain"
+	"todo/src/core/interfaces/repository"
+	"todo/src/core/projecterrors/todoerrors"
+)
+
+const undoTokenSize = 16
+
+type Undo struct {
+	repository repository.IUndo
+}
+
+func NewUndoService(repository repository.IUndo) *Undo {
+	return &Undo{repository}
+}
+
+// Undo reverses the operation of the token, as long as none of the affected data has changed since
+func (s Undo) Undo(token string, userId int) error {
+	err := s.repository.Undo(token, userId, time.Now())
+	if err != nil {
+		log.Error(err)
+		return todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.Undo)
+	}
+
+	return nil
+}
+
+// recordUndo stores the operation and returns the token that reverses it. As the operation has already been made,
+// a failure is only logged and no token is returned.
+func recordUndo(repository repository.IUndo, kind string, taskChanges []domain.TaskChange,
+	collection *domain.Collection, userId int) string {
+	if len(taskChanges) == 0 && collection == nil {
+		return ""
+	}
+
+	token, err := newRandomToken(undoTokenSize)
+	if err != nil {
+		log.Error(err)
+		return ""
+	}
+
+	operation := domain.NewUndoOperation(token, kind, taskChanges, collection, time.Now().Add(domain.UndoTokenTTL))
+	if err = repository.Create(*operation, userId); err != nil {
+		log.Error(err)
+		return ""
+	}
+
+	return token
+}
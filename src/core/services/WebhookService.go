@@ -0,0 +1,270 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/labstack/gommon/log"
+	"time"
+	"todo/src/core/domain"
+	"todo/src/core/interfaces/repository"
+	interfaces "todo/src/core/interfaces/webhook"
+	"todo/src/core/projecterrors/repositoryerrors"
+	"todo/src/core/projecterrors/todoerrors"
+	"todo/src/core/services/msgs"
+)
+
+const (
+	// WebhookDeliveryJobKind is the kind of the jobs posting an event to a webhook, queued by the outbox relay
+	WebhookDeliveryJobKind = "webhook.deliver"
+	webhookSecretSize      = 32
+)
+
+// Webhook manages the webhooks of the accounts and, as a handler of the outbox, queues the posts of the events to them
+type Webhook struct {
+	repository repository.IWebhook
+	sender     interfaces.ISender
+}
+
+func NewWebhookService(repository repository.IWebhook, sender interfaces.ISender) *Webhook {
+	return &Webhook{repository, sender}
+}
+
+// Create returns the webhook with its secret, which is not read again
+func (s Webhook) Create(webhook domain.Webhook, userId int) (*domain.Webhook, error) {
+	secret, err := newRandomToken(webhookSecretSize)
+	if err != nil {
+		log.Error(err)
+		return nil, todoerrors.NewUnexpectedInternalError(msgs.TokenGenerationError)
+	}
+	webhook.SetSecret(secret)
+
+	id, err := s.repository.Create(webhook, userId)
+	if err != nil {
+		log.Error(err)
+		return nil, todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.Create)
+	}
+
+	createdWebhook, err := s.FindById(id, userId)
+	if err != nil {
+		return nil, err
+	}
+	createdWebhook.SetSecret(secret)
+	return createdWebhook, nil
+}
+
+// Update clears the failures of the webhook when it is enabled again
+func (s Webhook) Update(webhook domain.Webhook, userId int) error {
+	err := s.repository.Update(webhook, userId)
+	if err != nil {
+		log.Error(err)
+		return todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.Update)
+	}
+
+	return nil
+}
+
+func (s Webhook) Delete(webhookId, userId int) error {
+	err := s.repository.Delete(webhookId, userId)
+	if err != nil {
+		log.Error(err)
+		return todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.Delete)
+	}
+
+	return nil
+}
+
+func (s Webhook) FindById(webhookId, userId int) (*domain.Webhook, error) {
+	webhook, err := s.repository.FindById(webhookId, userId)
+	if err != nil {
+		log.Error(err)
+		return nil, todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.FindById)
+	}
+
+	return webhook, nil
+}
+
+func (s Webhook) FindAll(userId int) ([]domain.Webhook, error) {
+	webhookList, err := s.repository.FindAll(userId)
+	if err != nil {
+		log.Error(err)
+		return nil, todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.FindAll)
+	}
+
+	return webhookList, nil
+}
+
+// FindDeliveries reads the attempts made to post the events to the webhook, which must belong to the account
+func (s Webhook) FindDeliveries(webhookId, userId int,
+	pagination domain.Pagination) ([]domain.WebhookDelivery, int, error) {
+	if _, err := s.FindById(webhookId, userId); err != nil {
+		return nil, 0, err
+	}
+
+	deliveryList, total, err := s.repository.FindDeliveries(webhookId, userId, pagination)
+	if err != nil {
+		log.Error(err)
+		return nil, 0, todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.FindDeliveries)
+	}
+
+	return deliveryList, total, nil
+}
+
+// Handle queues a delivery job for each active webhook of the account of the event subscribed to one of its types,
+// the jobs being written along with the acknowledgement of the event, so that the posts are made outside of the relay
+func (s Webhook) Handle(event domain.ChangeEvent) ([]domain.Job, error) {
+	var deliveries []domain.Job
+	for _, eventType := range domain.ChangeEventTypes(event) {
+		webhookList, err := s.repository.FindSubscribed(event.UserId(), eventType)
+		if err != nil {
+			log.Error(err)
+			return nil, todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.FindSubscribed)
+		}
+		if len(webhookList) == 0 {
+			continue
+		}
+
+		body, err := json.Marshal(newWebhookPayload(event, eventType))
+		if err != nil {
+			log.Error(err)
+			continue
+		}
+		for _, webhook := range webhookList {
+			payload, err := json.Marshal(webhookDeliveryPayload{webhook.Id(), event.Id(), eventType, string(body)})
+			if err != nil {
+				log.Error(err)
+				continue
+			}
+			job := domain.NewJob(0, WebhookDeliveryJobKind, payload, time.Now())
+			job.SetState(domain.JobQueued, 0, domain.MaxWebhookAttempts, "")
+			job.SetUserId(event.UserId())
+			deliveries = append(deliveries, *job)
+		}
+	}
+
+	return deliveries, nil
+}
+
+// Deliver posts the event queued by Handle to the webhook, as a job, keeping each attempt in the delivery log. The job
+// fails while the attempt has failed, so that the runner attempts it again after a wait, and the delivery is counted
+// as failed once its last attempt has failed. Nothing is posted when the webhook has been deleted or disabled since,
+// or when it has already received the event.
+func (s Webhook) Deliver(_ context.Context, job domain.Job) error {
+	var payload webhookDeliveryPayload
+	if err := json.Unmarshal(job.Payload(), &payload); err != nil {
+		return err
+	}
+
+	webhook, err := s.repository.FindById(payload.WebhookId, job.UserId())
+	var notFound *repositoryerrors.NotFound
+	if errors.As(err, &notFound) {
+		return nil
+	}
+	if err != nil {
+		log.Error(err)
+		return todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.FindById)
+	}
+	if !webhook.Active() {
+		return nil
+	}
+
+	previousDeliveries, err := s.repository.FindEventDeliveries(payload.EventId)
+	if err != nil {
+		log.Error(err)
+		return todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.FindEventDeliveries)
+	}
+	for _, delivery := range previousDeliveries {
+		if delivery.WebhookId() == webhook.Id() && delivery.EventType() == payload.EventType &&
+			delivery.Succeeded() {
+			return nil
+		}
+	}
+
+	delivery := s.sender.Send(*webhook, *domain.NewWebhookDelivery(webhook.Id(), payload.EventId,
+		payload.EventType, job.Attempts(), payload.Body))
+	if err = s.repository.CreateDelivery(*delivery); err != nil {
+		log.Error(err)
+		return todoerrors.ConvertRepositoryErrorToServiceError(err, s.repository.CreateDelivery)
+	}
+	if !delivery.Succeeded() && job.HasAttemptsLeft() {
+		return fmt.Errorf(msgs.WebhookDeliveryFailed, delivery.Attempt())
+	}
+
+	disabled, err := s.repository.RecordOutcome(webhook.Id(), delivery.Succeeded(), domain.WebhookFailureLimit)
+	if err != nil {
+		log.Error(err)
+	} else if disabled {
+		log.Warnf("The webhook %d has been disabled after %d failed deliveries", webhook.Id(),
+			domain.WebhookFailureLimit)
+	}
+	if !delivery.Succeeded() {
+		return fmt.Errorf(msgs.WebhookDeliveryFailed, delivery.Attempt())
+	}
+	return nil
+}
+
+// webhookDeliveryPayload is the payload of the delivery jobs, holding the body posted so that every attempt posts the
+// event as it was when the change was made
+type webhookDeliveryPayload struct {
+	WebhookId int    `json:"webhook_id"`
+	EventId   int64  `json:"event_id"`
+	EventType string `json:"event_type"`
+	Body      string `json:"body"`
+}
+
+type webhookPayload struct {
+	Id         int64       `json:"id"`
+	Type       string      `json:"type"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       webhookData `json:"data"`
+}
+
+type webhookData struct {
+	Id         int                `json:"id"`
+	Version    int                `json:"version,omitempty"`
+	Task       *webhookTask       `json:"task,omitempty"`
+	Collection *webhookCollection `json:"collection,omitempty"`
+}
+
+type webhookTask struct {
+	Description  string     `json:"description"`
+	Finished     bool       `json:"finished"`
+	DueDate      *time.Time `json:"due_date,omitempty"`
+	Tags         []string   `json:"tags"`
+	CollectionId int        `json:"collection_id,omitempty"`
+}
+
+type webhookCollection struct {
+	Name string `json:"name"`
+}
+
+// newWebhookPayload has the state of the entity after the change, which the deletions leave out
+func newWebhookPayload(event domain.ChangeEvent, eventType string) webhookPayload {
+	data := webhookData{Id: event.EntityId(), Version: event.Version()}
+	if task := event.Task(); task != nil {
+		tags := task.Tags()
+		if tags == nil {
+			tags = []string{}
+		}
+		data.Task = &webhookTask{
+			Description: task.Description(),
+			Finished:    task.Finished(),
+			DueDate:     task.DueDate(),
+			Tags:        tags,
+		}
+		if task.Collection() != nil {
+			data.Task.CollectionId = task.Collection().Id()
+		}
+	}
+	if collection := event.Collection(); collection != nil {
+		data.Collection = &webhookCollection{Name: collection.Name()}
+	}
+
+	return webhookPayload{
+		Id:         event.Id(),
+		Type:       eventType,
+		OccurredAt: event.OccurredAt(),
+		Data:       data,
+	}
+}
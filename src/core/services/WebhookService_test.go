@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+	"todo/src/core/domain"
+	"todo/src/core/projecterrors/repositoryerrors"
+)
+
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) Create(webhook domain.Webhook, userId int) (int, error) {
+	args := m.Called(webhook, userId)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockWebhookRepository) Update(webhook domain.Webhook, userId int) error {
+	args := m.Called(webhook, userId)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) Delete(webhookId, userId int) error {
+	args := m.Called(webhookId, userId)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) FindById(webhookId, userId int) (*domain.Webhook, error) {
+	args := m.Called(webhookId, userId)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.Webhook), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockWebhookRepository) FindAll(userId int) ([]domain.Webhook, error) {
+	args := m.Called(userId)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.Webhook), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockWebhookRepository) FindSubscribed(userId int, eventType string) ([]domain.Webhook, error) {
+	args := m.Called(userId, eventType)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.Webhook), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockWebhookRepository) CreateDelivery(delivery domain.WebhookDelivery) error {
+	args := m.Called(delivery)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) FindEventDeliveries(eventId int64) ([]domain.WebhookDelivery, error) {
+	args := m.Called(eventId)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.WebhookDelivery), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockWebhookRepository) FindDeliveries(webhookId, userId int,
+	pagination domain.Pagination) ([]domain.WebhookDelivery, int, error) {
+	args := m.Called(webhookId, userId, pagination)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.WebhookDelivery), args.Int(1), args.Error(2)
+	}
+	return nil, 0, args.Error(2)
+}
+
+func (m *MockWebhookRepository) RecordOutcome(webhookId int, succeeded bool, failureLimit int) (bool, error) {
+	args := m.Called(webhookId, succeeded, failureLimit)
+	return args.Bool(0), args.Error(1)
+}
+
+// statusSender answers each webhook with its status, recording the attempts made
+type statusSender struct {
+	statuses map[int]int
+	attempts []domain.WebhookDelivery
+}
+
+func (s *statusSender) Send(webhook domain.Webhook, delivery domain.WebhookDelivery) *domain.WebhookDelivery {
+	delivery.SetResult(s.statuses[webhook.Id()], "", time.Millisecond)
+	s.attempts = append(s.attempts, delivery)
+	return &delivery
+}
+
+func newCreatedTaskEvent() domain.ChangeEvent {
+	event := domain.NewChangeEvent(1, domain.ChangeEntityTask, domain.ChangeActionCreated, 7, 1)
+	event.SetId(12)
+	return *event
+}
+
+// newDeliveryJob is the job queued for the delivery to the webhook, claimed for its attempt
+func newDeliveryJob(webhookId, attempt int) domain.Job {
+	payload, _ := json.Marshal(webhookDeliveryPayload{webhookId, 12, "task.created", "{}"})
+	job := domain.NewJob(3, WebhookDeliveryJobKind, payload, time.Now())
+	job.SetState(domain.JobRunning, attempt, domain.MaxWebhookAttempts, "")
+	job.SetUserId(1)
+	return *job
+}
+
+func TestWebhook_Handle(t *testing.T) {
+	t.Run("should queue a delivery for each subscribed webhook without posting the event", func(t *testing.T) {
+		repository := new(MockWebhookRepository)
+		sender := &statusSender{}
+		repository.On("FindSubscribed", 1, "task.created").Return([]domain.Webhook{
+			*domain.NewWebhook(1, "https://example.com/hook", nil, true),
+			*domain.NewWebhook(2, "https://example.org/hook", nil, true),
+		}, nil)
+
+		jobList, err := NewWebhookService(repository, sender).Handle(newCreatedTaskEvent())
+
+		assert.NoError(t, err)
+		assert.Empty(t, sender.attempts)
+		assert.Len(t, jobList, 2)
+		var payload webhookDeliveryPayload
+		assert.NoError(t, json.Unmarshal(jobList[1].Payload(), &payload))
+		assert.Equal(t, 2, payload.WebhookId)
+		assert.Equal(t, int64(12), payload.EventId)
+		assert.Equal(t, "task.created", payload.EventType)
+		assert.Contains(t, payload.Body, `"type":"task.created"`)
+		assert.Equal(t, WebhookDeliveryJobKind, jobList[1].Kind())
+		assert.Equal(t, domain.MaxWebhookAttempts, jobList[1].MaxAttempts())
+		assert.Equal(t, 1, jobList[1].UserId())
+		repository.AssertNotCalled(t, "CreateDelivery", mock.Anything)
+	})
+
+	t.Run("should fail when the subscribed webhooks cannot be read", func(t *testing.T) {
+		repository := new(MockWebhookRepository)
+		repository.On("FindSubscribed", 1, "task.created").
+			Return(nil, repositoryerrors.NewUnknownError(errors.New("unavailable")))
+
+		_, err := NewWebhookService(repository, &statusSender{}).Handle(newCreatedTaskEvent())
+
+		assert.Error(t, err)
+	})
+}
+
+func TestWebhook_Deliver(t *testing.T) {
+	t.Run("should post the event and keep the attempt in the delivery log", func(t *testing.T) {
+		repository := new(MockWebhookRepository)
+		sender := &statusSender{statuses: map[int]int{1: 204}}
+		repository.On("FindById", 1, 1).Return(domain.NewWebhook(1, "https://example.com/hook", nil, true), nil)
+		repository.On("FindEventDeliveries", int64(12)).Return([]domain.WebhookDelivery{}, nil)
+		repository.On("CreateDelivery", mock.Anything).Return(nil)
+		repository.On("RecordOutcome", 1, true, domain.WebhookFailureLimit).Return(false, nil)
+
+		err := NewWebhookService(repository, sender).Deliver(context.Background(), newDeliveryJob(1, 1))
+
+		assert.NoError(t, err)
+		assert.Len(t, sender.attempts, 1)
+		assert.Equal(t, 1, sender.attempts[0].Attempt())
+		repository.AssertExpectations(t)
+	})
+
+	t.Run("should fail while the attempt has failed with attempts left, without counting the failure",
+		func(t *testing.T) {
+			repository := new(MockWebhookRepository)
+			sender := &statusSender{statuses: map[int]int{2: 503}}
+			repository.On("FindById", 2, 1).Return(domain.NewWebhook(2, "https://example.org/hook", nil, true), nil)
+			repository.On("FindEventDeliveries", int64(12)).Return([]domain.WebhookDelivery{}, nil)
+			repository.On("CreateDelivery", mock.Anything).Return(nil)
+
+			err := NewWebhookService(repository, sender).Deliver(context.Background(), newDeliveryJob(2, 2))
+
+			assert.Error(t, err)
+			assert.Equal(t, 2, sender.attempts[0].Attempt())
+			repository.AssertNotCalled(t, "RecordOutcome", mock.Anything, mock.Anything, mock.Anything)
+		})
+
+	t.Run("should count the delivery as failed once its last attempt has failed", func(t *testing.T) {
+		repository := new(MockWebhookRepository)
+		sender := &statusSender{statuses: map[int]int{2: 503}}
+		repository.On("FindById", 2, 1).Return(domain.NewWebhook(2, "https://example.org/hook", nil, true), nil)
+		repository.On("FindEventDeliveries", int64(12)).Return([]domain.WebhookDelivery{}, nil)
+		repository.On("CreateDelivery", mock.Anything).Return(nil)
+		repository.On("RecordOutcome", 2, false, domain.WebhookFailureLimit).Return(true, nil)
+
+		err := NewWebhookService(repository, sender).
+			Deliver(context.Background(), newDeliveryJob(2, domain.MaxWebhookAttempts))
+
+		assert.Error(t, err)
+		assert.Equal(t, domain.MaxWebhookAttempts, sender.attempts[0].Attempt())
+		repository.AssertExpectations(t)
+	})
+
+	t.Run("should not post again an event the webhook has received", func(t *testing.T) {
+		repository := new(MockWebhookRepository)
+		sender := &statusSender{statuses: map[int]int{1: 204}}
+		repository.On("FindById", 1, 1).Return(domain.NewWebhook(1, "https://example.com/hook", nil, true), nil)
+		delivered := domain.NewWebhookDelivery(1, 12, "task.created", 1, "{}")
+		delivered.SetResult(204, "", time.Millisecond)
+		repository.On("FindEventDeliveries", int64(12)).Return([]domain.WebhookDelivery{*delivered}, nil)
+
+		err := NewWebhookService(repository, sender).Deliver(context.Background(), newDeliveryJob(1, 2))
+
+		assert.NoError(t, err)
+		assert.Empty(t, sender.attempts)
+	})
+
+	t.Run("should not post the event to a webhook deleted or disabled since", func(t *testing.T) {
+		repository := new(MockWebhookRepository)
+		sender := &statusSender{statuses: map[int]int{1: 204}}
+		repository.On("FindById", 1, 1).Return(nil, repositoryerrors.NewNotFoundError("", nil))
+		repository.On("FindById", 2, 1).Return(domain.NewWebhook(2, "https://example.org/hook", nil, false), nil)
+		service := NewWebhookService(repository, sender)
+
+		assert.NoError(t, service.Deliver(context.Background(), newDeliveryJob(1, 1)))
+		assert.NoError(t, service.Deliver(context.Background(), newDeliveryJob(2, 1)))
+		assert.Empty(t, sender.attempts)
+	})
+}
@@ -3,4 +3,6 @@ package msgs
 const (
 	EmptyNameEmailOrPassword = "The user name, email and password must not be empty."
 	EmptyEmailOrPassword     = "The email and password must not be empty."
+	TokenGenerationError     = "The token could not be generated."
+	WebhookDeliveryFailed    = "The attempt %d to post the event to the webhook has failed."
 )
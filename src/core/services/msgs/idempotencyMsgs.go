@@ -0,0 +1,5 @@
+package msgs
+
+const (
+	ReusedIdempotencyKey = "The idempotency key provided has already been used with a different request."
+)
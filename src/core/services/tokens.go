@@ -0,0 +1,16 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// newRandomToken returns size random bytes encoded as hexadecimal, to be used as an unguessable secret
+func newRandomToken(size int) (string, error) {
+	tokenBytes := make([]byte, size)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(tokenBytes), nil
+}
@@ -0,0 +1,133 @@
+package todotxt
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/labstack/gommon/log"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+	"todo/src/core/domain"
+	"todo/src/core/projecterrors/todoerrors"
+	"todo/src/core/todotxt/msgs"
+	"unicode/utf8"
+)
+
+var priorityPattern = regexp.MustCompile(`^\([A-Z]\)$`)
+
+// Decode reads the lines of a todo.txt file as tasks without ID, the blank lines being skipped. Every invalid line is
+// reported, named by its number in the file.
+func Decode(reader io.Reader) ([]domain.Task, *todoerrors.Validation) {
+	scanner := bufio.NewScanner(reader)
+	var tasks []domain.Task
+	invalidFields := todoerrors.InvalidFields{}
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		tasks = append(tasks, *parseLine(line, fmt.Sprintf(msgs.Line, number), &invalidFields))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, newFileError(err.Error())
+	}
+
+	if len(tasks) > domain.MaxTransferSize {
+		return nil, newFileError(fmt.Sprintf(msgs.TooManyLines, domain.MaxTransferSize))
+	}
+	if invalidFields.HasInvalidFields() {
+		log.Error(msgs.InvalidFile)
+		return nil, todoerrors.NewValidationError(msgs.InvalidFile, invalidFields)
+	}
+
+	return tasks, nil
+}
+
+// parseLine reads a todo.txt line as a task. The first project names its collection, with the underscores read as
+// spaces, the contexts become its tags and the due key its due date. The priority and the completion date are not
+// stored, and the other projects and key:value extras are kept in the description.
+func parseLine(line, row string, invalidFields *todoerrors.InvalidFields) *domain.Task {
+	words := strings.Fields(line)
+	finished := false
+	if len(words) > 0 && words[0] == completionMarker {
+		finished = true
+		words = skipPriority(words[1:])
+		// A completed task has its completion date first, followed by its creation date when it has one
+		if len(words) > 0 && isDate(words[0]) {
+			words = words[1:]
+		}
+	} else {
+		words = skipPriority(words)
+	}
+
+	var createdAt time.Time
+	if len(words) > 0 && isDate(words[0]) {
+		createdAt, _ = time.Parse(dateLayout, words[0])
+		words = words[1:]
+	}
+
+	var description []string
+	project := ""
+	var contexts []string
+	var dueDate *time.Time
+	for _, word := range words {
+		switch {
+		case project == "" && len(word) > 1 && strings.HasPrefix(word, projectPrefix):
+			project = strings.ReplaceAll(word[1:], spaceReplacement, " ")
+		case len(word) > 1 && strings.HasPrefix(word, contextPrefix):
+			contexts = append(contexts, word[1:])
+		case strings.HasPrefix(word, dueKey):
+			due, err := time.Parse(dateLayout, strings.TrimPrefix(word, dueKey))
+			if err != nil {
+				invalidFields.AppendField(row, msgs.InvalidDue)
+				continue
+			}
+			dueDate = &due
+		default:
+			description = append(description, word)
+		}
+	}
+
+	task := domain.NewTask(0, strings.Join(description, " "), finished, domain.NewCollection(0, project))
+	validateTask(*task, row, invalidFields)
+	tags, validationErr := domain.NewValidatedTags(contexts)
+	if validationErr != nil {
+		invalidFields.AppendField(row, msgs.InvalidContext+validationErr.InvalidFields().Fields()[0].Description())
+	}
+	task.SetCreatedAt(createdAt)
+	task.SetDueDate(dueDate)
+	task.SetTags(tags)
+
+	return task
+}
+
+func validateTask(task domain.Task, row string, invalidFields *todoerrors.InvalidFields) {
+	if task.Description() == "" {
+		invalidFields.AppendField(row, msgs.MissingDescription)
+	} else if utf8.RuneCountInString(task.Description()) > domain.MaxTaskDescriptionLength {
+		invalidFields.AppendField(row, fmt.Sprintf(msgs.InvalidDescription, domain.MaxTaskDescriptionLength))
+	}
+	if utf8.RuneCountInString(task.Collection().Name()) > domain.MaxCollectionNameLength {
+		invalidFields.AppendField(row, fmt.Sprintf(msgs.InvalidProject, domain.MaxCollectionNameLength))
+	}
+}
+
+func skipPriority(words []string) []string {
+	if len(words) > 0 && priorityPattern.MatchString(words[0]) {
+		return words[1:]
+	}
+	return words
+}
+
+func isDate(word string) bool {
+	_, err := time.Parse(dateLayout, word)
+	return err == nil
+}
+
+func newFileError(description string) *todoerrors.Validation {
+	log.Error(description)
+	invalidFields := todoerrors.InvalidFields{}
+	invalidFields.AppendField(msgs.File, description)
+	return todoerrors.NewValidationError(msgs.InvalidFile, invalidFields)
+}
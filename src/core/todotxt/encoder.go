@@ -0,0 +1,54 @@
+package todotxt
+
+import (
+	"io"
+	"strings"
+	"todo/src/core/domain"
+)
+
+const (
+	dateLayout       = "2006-01-02"
+	completionMarker = "x"
+	projectPrefix    = "+"
+	contextPrefix    = "@"
+	dueKey           = "due:"
+	spaceReplacement = "_"
+)
+
+// Encoder writes tasks as the lines of a todo.txt file, one at a time so that it can be streamed
+type Encoder struct {
+	writer io.Writer
+}
+
+func NewEncoder(writer io.Writer) *Encoder {
+	return &Encoder{writer}
+}
+
+func (e Encoder) Encode(task domain.Task) error {
+	_, err := io.WriteString(e.writer, formatLine(task)+"\n")
+	return err
+}
+
+// formatLine writes the task as a todo.txt line. The collection becomes the project and the tags the contexts, with
+// the spaces of the collection name replaced by underscores. Finished tasks have no dates, since the first date of a
+// completed task is its completion date, which is not stored.
+func formatLine(task domain.Task) string {
+	var parts []string
+	if task.Finished() {
+		parts = append(parts, completionMarker)
+	} else if !task.CreatedAt().IsZero() {
+		parts = append(parts, task.CreatedAt().UTC().Format(dateLayout))
+	}
+	parts = append(parts, strings.Join(strings.Fields(task.Description()), " "))
+	if task.Collection() != nil && task.Collection().Name() != "" {
+		parts = append(parts, projectPrefix+strings.Join(strings.Fields(task.Collection().Name()), spaceReplacement))
+	}
+	for _, tag := range task.Tags() {
+		parts = append(parts, contextPrefix+tag)
+	}
+	if task.DueDate() != nil {
+		parts = append(parts, dueKey+task.DueDate().UTC().Format(dateLayout))
+	}
+
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,6 @@
+package msgs
+
+const (
+	File = "File"
+	Line = "Line %d"
+)
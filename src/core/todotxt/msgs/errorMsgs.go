@@ -0,0 +1,11 @@
+package msgs
+
+const (
+	InvalidFile        = "The todo.txt file provided is invalid."
+	TooManyLines       = "The file must have at most %d tasks."
+	MissingDescription = "The task must have a description besides its projects, contexts and dates."
+	InvalidDescription = "The description must have at most %d characters."
+	InvalidProject     = "The project must have at most %d characters."
+	InvalidDue         = "The due date provided is invalid. It must be in the format YYYY-MM-DD."
+	InvalidContext     = "The contexts provided are invalid. "
+)
@@ -0,0 +1,83 @@
+package todotxt
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+	"todo/src/core/domain"
+)
+
+func TestEncoder(t *testing.T) {
+	t.Run("should write the collection as project, the tags as contexts and the due date", func(t *testing.T) {
+		dueDate := time.Date(2024, 1, 31, 18, 0, 0, 0, time.UTC)
+		task := domain.NewTask(1, "Buy milk", false, domain.NewCollection(3, "Home Office"))
+		task.SetCreatedAt(time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC))
+		task.SetDueDate(&dueDate)
+		task.SetTags([]string{"shopping", "urgent"})
+		finishedTask := domain.NewTask(2, "Call the bank", true, domain.NewCollection(0, ""))
+		finishedTask.SetCreatedAt(time.Date(2024, 1, 3, 9, 0, 0, 0, time.UTC))
+		var output bytes.Buffer
+		encoder := NewEncoder(&output)
+
+		_ = encoder.Encode(*task)
+		_ = encoder.Encode(*finishedTask)
+
+		assert.Equal(t, "2024-01-02 Buy milk +Home_Office @shopping @urgent due:2024-01-31\n"+
+			"x Call the bank\n", output.String())
+	})
+}
+
+func TestDecode(t *testing.T) {
+	t.Run("should read the markers, dates, projects, contexts and extras of each line", func(t *testing.T) {
+		file := "(A) 2024-01-02 Buy milk +Home_Office @Shopping due:2024-01-31 +Errands rec:1w\n\n" +
+			"x 2024-01-05 2024-01-03 Call the bank @phone\n" +
+			"x (B) 2024-01-06 Pay rent\n"
+
+		tasks, err := Decode(strings.NewReader(file))
+
+		assert.Nil(t, err)
+		assert.Len(t, tasks, 3)
+		assert.Equal(t, "Buy milk +Errands rec:1w", tasks[0].Description())
+		assert.False(t, tasks[0].Finished())
+		assert.Equal(t, "Home Office", tasks[0].Collection().Name())
+		assert.Equal(t, []string{"shopping"}, tasks[0].Tags())
+		assert.Equal(t, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), tasks[0].CreatedAt())
+		assert.Equal(t, time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), *tasks[0].DueDate())
+		assert.True(t, tasks[1].Finished())
+		assert.Equal(t, "Call the bank", tasks[1].Description())
+		assert.Equal(t, time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC), tasks[1].CreatedAt())
+		assert.Equal(t, "Pay rent", tasks[2].Description())
+		assert.True(t, tasks[2].CreatedAt().IsZero())
+	})
+
+	t.Run("should read back the lines written by the encoder", func(t *testing.T) {
+		task := domain.NewTask(1, "Write the report", false, domain.NewCollection(3, "Work"))
+		task.SetCreatedAt(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+		task.SetTags([]string{"office"})
+		var output bytes.Buffer
+		_ = NewEncoder(&output).Encode(*task)
+
+		tasks, err := Decode(&output)
+
+		assert.Nil(t, err)
+		assert.Equal(t, task.Description(), tasks[0].Description())
+		assert.Equal(t, task.Collection().Name(), tasks[0].Collection().Name())
+		assert.Equal(t, task.CreatedAt(), tasks[0].CreatedAt())
+		assert.Equal(t, task.Tags(), tasks[0].Tags())
+	})
+
+	t.Run("should report every invalid line by its number", func(t *testing.T) {
+		file := "Valid task\n\n+Project @context\nPay due:tomorrow\n"
+
+		tasks, err := Decode(strings.NewReader(file))
+
+		assert.Nil(t, tasks)
+		assert.Len(t, err.InvalidFields().Fields(), 2)
+		assert.Equal(t, "Line 3", err.InvalidFields().Fields()[0].Name())
+		assert.Equal(t, "Line 4", err.InvalidFields().Fields()[1].Name())
+		assert.Equal(t, "The due date provided is invalid. It must be in the format YYYY-MM-DD.",
+			err.InvalidFields().Fields()[1].Description())
+	})
+}
@@ -0,0 +1,131 @@
+package mailer
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"time"
+	"todo/src/core/domain"
+)
+
+const sendTimeout = 30 * time.Second
+
+// Mailer sends the emails through the SMTP server of the environment, using STARTTLS when the server offers it and
+// authenticating only when a username is set, as local sinks such as MailHog take the emails without it
+type Mailer struct {
+	host     string
+	address  string
+	from     string
+	username string
+	password string
+}
+
+func NewSmtpMailer() *Mailer {
+	host := os.Getenv("SMTP_HOST")
+	return &Mailer{
+		host:     host,
+		address:  net.JoinHostPort(host, os.Getenv("SMTP_PORT")),
+		from:     os.Getenv("SMTP_FROM"),
+		username: os.Getenv("SMTP_USERNAME"),
+		password: os.Getenv("SMTP_PASSWORD"),
+	}
+}
+
+func (m Mailer) Send(message domain.Mail) error {
+	sender, err := mail.ParseAddress(m.from)
+	if err != nil {
+		return fmt.Errorf("the sender address %q is invalid: %w", m.from, err)
+	}
+	body, err := m.compose(*sender, message)
+	if err != nil {
+		return err
+	}
+
+	connection, err := net.DialTimeout("tcp", m.address, sendTimeout)
+	if err != nil {
+		return err
+	}
+	if err = connection.SetDeadline(time.Now().Add(sendTimeout)); err != nil {
+		_ = connection.Close()
+		return err
+	}
+	client, err := smtp.NewClient(connection, m.host)
+	if err != nil {
+		_ = connection.Close()
+		return err
+	}
+	defer client.Close()
+
+	if supported, _ := client.Extension("STARTTLS"); supported {
+		if err = client.StartTLS(&tls.Config{ServerName: m.host}); err != nil {
+			return err
+		}
+	}
+	if m.username != "" {
+		if err = client.Auth(smtp.PlainAuth("", m.username, m.password, m.host)); err != nil {
+			return err
+		}
+	}
+	if err = client.Mail(sender.Address); err != nil {
+		return err
+	}
+	if err = client.Rcpt(message.To()); err != nil {
+		return err
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = writer.Write(body); err != nil {
+		return err
+	}
+	if err = writer.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// compose writes the message with the plain text and HTML bodies as alternatives, the preferred one last
+func (m Mailer) compose(sender mail.Address, message domain.Mail) ([]byte, error) {
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+
+	header := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nDate: %s\r\nMIME-Version: 1.0\r\n"+
+		"Content-Type: multipart/alternative; boundary=%q\r\n\r\n", sender.String(), message.To(),
+		mime.QEncoding.Encode("utf-8", message.Subject()), time.Now().Format(time.RFC1123Z), parts.Boundary())
+	body.WriteString(header)
+
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", message.Text()},
+		{"text/html; charset=utf-8", message.Html()},
+	} {
+		writer, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		encoder := quotedprintable.NewWriter(writer)
+		if _, err = encoder.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err = encoder.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	return body.Bytes(), nil
+}
@@ -0,0 +1,111 @@
+package mailer
+
+import (
+	"bufio"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"mime/quotedprintable"
+	"net"
+	"strings"
+	"testing"
+	"todo/src/core/domain"
+)
+
+// smtpSink accepts the emails the way a local sink such as MailHog does, rejecting the recipients in rejected
+type smtpSink struct {
+	listener net.Listener
+	messages chan string
+	rejected string
+}
+
+func newSmtpSink(t *testing.T, rejected string) *smtpSink {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink := &smtpSink{listener: listener, messages: make(chan string, 1), rejected: rejected}
+	go sink.serve()
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	t.Setenv("SMTP_HOST", host)
+	t.Setenv("SMTP_PORT", port)
+	t.Setenv("SMTP_FROM", "Todo <todo@localhost>")
+	t.Setenv("SMTP_USERNAME", "")
+	return sink
+}
+
+func (s *smtpSink) serve() {
+	connection, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer connection.Close()
+	reader := bufio.NewReader(connection)
+	reply := func(line string) {
+		_, _ = connection.Write([]byte(line + "\r\n"))
+	}
+
+	reply("220 localhost ESMTP")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(command, "EHLO"):
+			reply("250-localhost")
+			reply("250 8BITMIME")
+		case strings.HasPrefix(command, "RCPT") && s.rejected != "" && strings.Contains(command, s.rejected):
+			reply("550 No such user")
+		case command == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var message strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil || dataLine == ".\r\n" {
+					break
+				}
+				message.WriteString(dataLine)
+			}
+			s.messages <- message.String()
+			reply("250 Ok")
+		case command == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 Ok")
+		}
+	}
+}
+
+func TestMailer_Send(t *testing.T) {
+	t.Run("should send the plain text and HTML bodies as alternatives", func(t *testing.T) {
+		sink := newSmtpSink(t, "")
+		mail := domain.NewMail("ann@example.com", "Reminder: Café", "Hi Ann,", "<p>Hi Ann,</p>")
+
+		err := NewSmtpMailer().Send(*mail)
+
+		assert.NoError(t, err)
+		message := <-sink.messages
+		assert.Contains(t, message, "From: \"Todo\" <todo@localhost>\r\n")
+		assert.Contains(t, message, "To: ann@example.com\r\n")
+		assert.Contains(t, message, "Subject: =?utf-8?q?Reminder:_Caf=C3=A9?=\r\n")
+		assert.Contains(t, message, "Content-Type: multipart/alternative")
+		assert.Less(t, strings.Index(message, "text/plain"), strings.Index(message, "text/html"))
+		decoded, _ := io.ReadAll(quotedprintable.NewReader(strings.NewReader(message)))
+		assert.Contains(t, string(decoded), "<p>Hi Ann,</p>")
+	})
+
+	t.Run("should fail when the server rejects the recipient", func(t *testing.T) {
+		newSmtpSink(t, "NOBODY@EXAMPLE.COM")
+		mail := domain.NewMail("nobody@example.com", "Reminder", "Hi", "<p>Hi</p>")
+
+		err := NewSmtpMailer().Send(*mail)
+
+		assert.ErrorContains(t, err, "No such user")
+	})
+}
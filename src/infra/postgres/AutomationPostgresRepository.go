@@ -0,0 +1,164 @@
+package postgres
+
+import (
+	"errors"
+	"github.com/labstack/gommon/log"
+	"strings"
+	"todo/src/core/domain"
+	"todo/src/core/projecterrors/repositoryerrors"
+	"todo/src/infra/postgres/dto"
+	"todo/src/infra/postgres/msgs"
+	"todo/src/infra/postgres/query"
+)
+
+type Automation struct {
+	iConnectionManager
+}
+
+func NewAutomationPostgresRepository(connectionManager iConnectionManager) *Automation {
+	return &Automation{
+		connectionManager,
+	}
+}
+
+func (r Automation) Create(automation domain.Automation, userId int) (int, error) {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return -1, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	args, err := dto.Automation().Insert(automation, userId)
+	if err != nil {
+		log.Error(err)
+		return -1, repositoryerrors.NewUnknownError(err)
+	}
+	var id int
+	err = connection.QueryRow(query.Automation().Insert(), args...).Scan(&id)
+	if err != nil {
+		log.Error(err)
+		return -1, r.handlePostgresError(err)
+	}
+
+	return id, nil
+}
+
+func (r Automation) Update(automation domain.Automation, userId int) error {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	args, err := dto.Automation().Update(automation, userId)
+	if err != nil {
+		log.Error(err)
+		return repositoryerrors.NewUnknownError(err)
+	}
+	result, err := connection.Exec(query.Automation().Update(), args...)
+	if err != nil {
+		log.Error(err)
+		return r.handlePostgresError(err)
+	}
+
+	return r.checkAffectedRows(result.RowsAffected())
+}
+
+func (r Automation) Delete(automationId, userId int) error {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	result, err := connection.Exec(query.Automation().Delete(), automationId, userId)
+	if err != nil {
+		log.Error(err)
+		return r.handlePostgresError(err)
+	}
+
+	return r.checkAffectedRows(result.RowsAffected())
+}
+
+func (r Automation) FindById(automationId, userId int) (*domain.Automation, error) {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return nil, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	destination := dto.Automation().Select().ById()
+	err = connection.Get(&destination, query.Automation().Select().ById(), automationId, userId)
+	if err != nil {
+		log.Error(err)
+		return nil, r.handlePostgresError(err)
+	}
+	automation, err := destination.ConvertToDomain()
+	if err != nil {
+		log.Error(err)
+		return nil, repositoryerrors.NewUnknownError(err)
+	}
+
+	return automation, nil
+}
+
+func (r Automation) FindAll(userId int) ([]domain.Automation, error) {
+	return r.findAutomations(query.Automation().Select().All(), userId)
+}
+
+// FindTriggered reads the active automations of the account with the trigger, in the order they have been created
+func (r Automation) FindTriggered(userId int, trigger string) ([]domain.Automation, error) {
+	return r.findAutomations(query.Automation().Select().Triggered(), userId, trigger)
+}
+
+func (r Automation) findAutomations(sql string, args ...interface{}) ([]domain.Automation, error) {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return nil, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	destination := dto.Automation().Select().All()
+	err = connection.Select(&destination, sql, args...)
+	if err != nil {
+		log.Error(err)
+		return nil, r.handlePostgresError(err)
+	}
+	var automationList []domain.Automation
+	for _, row := range destination {
+		automation, err := row.ConvertToDomain()
+		if err != nil {
+			log.Error(err)
+			return nil, repositoryerrors.NewUnknownError(err)
+		}
+		automationList = append(automationList, *automation)
+	}
+
+	return automationList, nil
+}
+
+func (r Automation) checkAffectedRows(affectedRows int64, resultErr error) error {
+	if resultErr != nil {
+		log.Error(resultErr)
+		return repositoryerrors.NewUnknownError(resultErr)
+	} else if affectedRows == 0 {
+		return repositoryerrors.NewNotFoundError(msgs.AutomationNotFound, errors.New(msgs.AutomationNotFound))
+	}
+
+	return nil
+}
+
+func (r Automation) handlePostgresError(err error) error {
+	errMessage := err.Error()
+
+	if strings.Contains(errMessage, "sql: no rows in result set") {
+		return repositoryerrors.NewNotFoundError(msgs.AutomationNotFound, err)
+	}
+
+	return repositoryerrors.NewUnknownError(err)
+}
@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"errors"
+	"github.com/labstack/gommon/log"
+	"strings"
+	"todo/src/core/domain"
+	"todo/src/core/projecterrors/repositoryerrors"
+	"todo/src/infra/postgres/dto"
+	"todo/src/infra/postgres/msgs"
+	"todo/src/infra/postgres/query"
+)
+
+type CalDav struct {
+	iConnectionManager
+}
+
+func NewCalDavPostgresRepository(connectionManager iConnectionManager) *CalDav {
+	return &CalDav{
+		connectionManager,
+	}
+}
+
+func (r CalDav) FindObjects(collectionId, userId int) ([]domain.CalendarObject, error) {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return nil, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	destination := dto.CalDav().Select().Objects()
+	err = connection.Select(&destination, query.CalDav().Select().Objects(), collectionId, userId)
+	if err != nil {
+		log.Error(err)
+		return nil, r.handlePostgresError(err)
+	}
+
+	objects := make([]domain.CalendarObject, len(destination))
+	for index, object := range destination {
+		objects[index] = *object.ConvertToDomain()
+	}
+
+	return objects, nil
+}
+
+func (r CalDav) FindObject(name string, collectionId, userId int) (*domain.CalendarObject, error) {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return nil, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	destination := dto.CalDav().Select().ByName()
+	err = connection.Get(&destination, query.CalDav().Select().ByName(), collectionId, userId, name)
+	if err != nil {
+		log.Error(err)
+		return nil, r.handlePostgresError(err)
+	}
+
+	return destination.ConvertToDomain(), nil
+}
+
+func (r CalDav) SaveObject(object domain.CalendarObject, userId int) error {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	result, err := connection.Exec(query.CalDav().SaveObject(), dto.CalDav().SaveObject(object, userId)...)
+	if err != nil {
+		log.Error(err)
+		return r.handlePostgresError(err)
+	}
+	if affectedRows, resultErr := result.RowsAffected(); affectedRows == 0 {
+		return repositoryerrors.NewNotFoundError(msgs.TaskNotFound, errors.New(msgs.TaskNotFoundNewError))
+	} else if resultErr != nil {
+		log.Error(resultErr)
+		return repositoryerrors.NewUnknownError(resultErr)
+	}
+
+	return nil
+}
+
+func (r CalDav) handlePostgresError(err error) error {
+	errMessage := err.Error()
+
+	if strings.Contains(errMessage, "sql: no rows in result set") {
+		return repositoryerrors.NewNotFoundError(msgs.CalendarObjectNotFound, err)
+	}
+
+	return repositoryerrors.NewUnknownError(err)
+}
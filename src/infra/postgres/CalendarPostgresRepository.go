@@ -0,0 +1,254 @@
+package postgres
+
+import (
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/gommon/log"
+	"strings"
+	"todo/src/core/domain"
+	"todo/src/core/projecterrors/repositoryerrors"
+	"todo/src/infra/postgres/dto"
+	"todo/src/infra/postgres/msgs"
+	"todo/src/infra/postgres/query"
+)
+
+type Calendar struct {
+	iConnectionManager
+}
+
+func NewCalendarPostgresRepository(connectionManager iConnectionManager) *Calendar {
+	return &Calendar{
+		connectionManager,
+	}
+}
+
+func (r Calendar) SaveFeedToken(token string, userId int) error {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	_, err = connection.Exec(query.Calendar().SaveFeedToken(), userId, token)
+	if err != nil {
+		log.Error(err)
+		return r.handlePostgresError(err)
+	}
+
+	return nil
+}
+
+func (r Calendar) FindFeedToken(userId int) (string, error) {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return "", repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	var token string
+	err = connection.Get(&token, query.Calendar().Select().FeedToken(), userId)
+	if err != nil {
+		log.Error(err)
+		return "", r.handlePostgresError(err)
+	}
+
+	return token, nil
+}
+
+func (r Calendar) DeleteFeedToken(userId int) error {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	result, err := connection.Exec(query.Calendar().DeleteFeedToken(), userId)
+	if err != nil {
+		log.Error(err)
+		return r.handlePostgresError(err)
+	}
+	if affectedRows, resultErr := result.RowsAffected(); affectedRows == 0 {
+		return repositoryerrors.NewNotFoundError(msgs.CalendarFeedNotFound, errors.New(msgs.CalendarFeedNotFound))
+	} else if resultErr != nil {
+		log.Error(resultErr)
+		return repositoryerrors.NewUnknownError(resultErr)
+	}
+
+	return nil
+}
+
+func (r Calendar) FindFeed(token string, collectionId int) (*domain.CalendarFeed, error) {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return nil, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	var userId int
+	err = connection.Get(&userId, query.Calendar().Select().FeedOwner(), token)
+	if err != nil {
+		log.Error(err)
+		return nil, r.handlePostgresError(err)
+	}
+	if collectionId == 0 {
+		return domain.NewCalendarFeed(token, userId, nil), nil
+	}
+
+	destination := dto.Collection().Select().ById()
+	err = connection.Get(&destination, query.Collection().Select().ById(), collectionId, userId)
+	if err != nil {
+		log.Error(err)
+		return nil, repositoryerrors.NewNotFoundError(msgs.CollectionNotFound, err)
+	}
+
+	return domain.NewCalendarFeed(token, userId, destination.ConvertToDomain()), nil
+}
+
+// ExportTasks reads the tasks with a cursor, so that they are never all held in memory
+func (r Calendar) ExportTasks(feed domain.CalendarFeed, write func(task domain.Task) error) error {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	collectionId := 0
+	if feed.Collection() != nil {
+		collectionId = feed.Collection().Id()
+	}
+	rows, err := connection.Queryx(query.Calendar().Select().Tasks(), feed.UserId(), collectionId)
+	if err != nil {
+		log.Error(err)
+		return r.handlePostgresError(err)
+	}
+	defer r.closeRows(rows)
+
+	for rows.Next() {
+		destination := dto.Task().Select().ById()
+		if err = rows.StructScan(&destination); err != nil {
+			log.Error(err)
+			return r.handlePostgresError(err)
+		}
+		if err = write(*destination.ConvertToDomain()); err != nil {
+			log.Error(err)
+			return repositoryerrors.NewUnknownError(err)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		log.Error(err)
+		return r.handlePostgresError(err)
+	}
+
+	return nil
+}
+
+// Import creates the tasks in a single transaction, after checking that the collection belongs to the account. Without
+// a collection, the tasks go to the import collection of the account.
+func (r Calendar) Import(tasks []domain.Task, collectionId, userId int) ([]int, error) {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return nil, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	transaction, err := connection.Beginx()
+	if err != nil {
+		log.Error(err)
+		return nil, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	ids, err := r.insert(transaction, tasks, collectionId, userId)
+	if err != nil {
+		if rollbackErr := transaction.Rollback(); rollbackErr != nil {
+			log.Error(rollbackErr)
+		}
+		return nil, err
+	}
+	if err = transaction.Commit(); err != nil {
+		log.Error(err)
+		return nil, r.handlePostgresError(err)
+	}
+
+	return ids, nil
+}
+
+func (r Calendar) insert(transaction *sqlx.Tx, tasks []domain.Task, collectionId, userId int) ([]int, error) {
+	if collectionId != 0 {
+		var exists bool
+		err := transaction.Get(&exists, query.Collection().Exists(), collectionId, userId)
+		if err != nil {
+			log.Error(err)
+			return nil, r.handlePostgresError(err)
+		}
+		if !exists {
+			return nil, repositoryerrors.NewDependencyError(msgs.CollectionNotFound,
+				errors.New(msgs.CollectionNotFoundNewError), msgs.Collection)
+		}
+	} else if len(tasks) > 0 {
+		var err error
+		if collectionId, err = r.findOrCreateImportCollection(transaction, userId); err != nil {
+			return nil, err
+		}
+	}
+	collection := &collectionId
+
+	ids := []int{}
+	for _, task := range tasks {
+		var id int
+		err := transaction.QueryRowx(query.Transfer().InsertTask(),
+			dto.Transfer().InsertTask(task, collection, userId)...).Scan(&id)
+		if err != nil {
+			log.Error(err)
+			return nil, r.handlePostgresError(err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func (r Calendar) findOrCreateImportCollection(transaction *sqlx.Tx, userId int) (int, error) {
+	var ids []int
+	err := transaction.Select(&ids, query.Transfer().Select().CollectionIdByName(), userId,
+		domain.ImportCollectionName)
+	if err != nil {
+		log.Error(err)
+		return 0, r.handlePostgresError(err)
+	}
+	if len(ids) > 0 {
+		return ids[0], nil
+	}
+
+	var id int
+	err = transaction.QueryRowx(query.Transfer().InsertCollection(),
+		dto.Transfer().InsertCollection(*domain.NewCollection(0, domain.ImportCollectionName), userId)...).Scan(&id)
+	if err != nil {
+		log.Error(err)
+		return 0, r.handlePostgresError(err)
+	}
+
+	return id, nil
+}
+
+func (r Calendar) closeRows(rows *sqlx.Rows) {
+	if err := rows.Close(); err != nil {
+		log.Error(err)
+	}
+}
+
+func (r Calendar) handlePostgresError(err error) error {
+	errMessage := err.Error()
+
+	if strings.Contains(errMessage, "sql: no rows in result set") {
+		return repositoryerrors.NewNotFoundError(msgs.CalendarFeedNotFound, err)
+	} else if strings.Contains(errMessage, "task_collection_fk") {
+		return repositoryerrors.NewDependencyError(msgs.CollectionNotFound, err, msgs.Collection)
+	}
+
+	return repositoryerrors.NewUnknownError(err)
+}
@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"github.com/labstack/gommon/log"
+	"github.com/lib/pq"
+	"todo/src/core/domain"
+	"todo/src/core/projecterrors/repositoryerrors"
+	"todo/src/infra/postgres/dto"
+	"todo/src/infra/postgres/msgs"
+	"todo/src/infra/postgres/query"
+)
+
+// ChangeFeed reads the change events from the outbox, where every instance of the API writes them
+type ChangeFeed struct {
+	iConnectionManager
+}
+
+func NewChangeFeedPostgresRepository(connectionManager iConnectionManager) *ChangeFeed {
+	return &ChangeFeed{
+		connectionManager,
+	}
+}
+
+func (r ChangeFeed) LastEventId() (int64, error) {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return 0, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	var lastEventId int64
+	if err = connection.Get(&lastEventId, query.ChangeFeed().LastEventId()); err != nil {
+		log.Error(err)
+		return 0, repositoryerrors.NewUnknownError(err)
+	}
+
+	return lastEventId, nil
+}
+
+func (r ChangeFeed) FindEvents(afterId int64, ids []int64, limit int) ([]domain.ChangeEvent, error) {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return nil, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	destination := dto.Outbox().Select().Events()
+	err = connection.Select(&destination, query.ChangeFeed().Select().Events(), afterId, pq.Array(ids), limit)
+	if err != nil {
+		log.Error(err)
+		return nil, repositoryerrors.NewUnknownError(err)
+	}
+
+	var eventList []domain.ChangeEvent
+	for _, row := range destination {
+		event, err := row.ConvertToDomain()
+		if err != nil {
+			log.Error(err)
+			continue
+		}
+		eventList = append(eventList, event.Event())
+	}
+
+	return eventList, nil
+}
+
+// FindMissedEvents reads the events once the last event received has been found, so that none of the events read
+// have been purged
+func (r ChangeFeed) FindMissedEvents(userId int, lastEventId int64, limit int) ([]domain.ChangeEvent, bool, error) {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return nil, false, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	var found bool
+	if err = connection.Get(&found, query.ChangeFeed().EventExists(), lastEventId, userId); err != nil {
+		log.Error(err)
+		return nil, false, repositoryerrors.NewUnknownError(err)
+	}
+	if !found {
+		return nil, false, nil
+	}
+
+	destination := dto.Outbox().Select().Events()
+	err = connection.Select(&destination, query.ChangeFeed().Select().UserEvents(), userId, lastEventId, limit)
+	if err != nil {
+		log.Error(err)
+		return nil, false, repositoryerrors.NewUnknownError(err)
+	}
+
+	var eventList []domain.ChangeEvent
+	for _, row := range destination {
+		event, err := row.ConvertToDomain()
+		if err != nil {
+			log.Error(err)
+			continue
+		}
+		eventList = append(eventList, event.Event())
+	}
+
+	return eventList, true, nil
+}
@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"errors"
+	"github.com/jmoiron/sqlx"
 	"github.com/labstack/gommon/log"
 	"strings"
 	"todo/src/core/domain"
@@ -54,7 +55,7 @@ func (r Collection) Update(collection domain.Collection, userId int) error {
 	}
 	affectedRows, resultErr := result.RowsAffected()
 	if affectedRows == 0 {
-		return repositoryerrors.NewNotFoundError(msgs.CollectionNotFound, errors.New(msgs.CollectionNotFoundNewError))
+		return r.handleNotAffected(connection, collection.Id(), userId, collection.Version())
 	} else if resultErr != nil {
 		log.Error(resultErr)
 		return repositoryerrors.NewUnknownError(resultErr)
@@ -63,7 +64,7 @@ func (r Collection) Update(collection domain.Collection, userId int) error {
 	return nil
 }
 
-func (r Collection) Delete(collectionId, userId int) error {
+func (r Collection) Delete(collectionId, userId, version int) error {
 	connection, err := r.getConnection()
 	if err != nil {
 		log.Error(err)
@@ -71,13 +72,13 @@ func (r Collection) Delete(collectionId, userId int) error {
 	}
 	defer r.closeConnection(connection)
 
-	result, err := connection.Exec(query.Collection().Delete(), collectionId, userId)
+	result, err := connection.Exec(query.Collection().Delete(), collectionId, userId, version)
 	if err != nil {
 		log.Error(err)
 		return r.handlePostgresError(err)
 	}
 	if affectedRows, resultErr := result.RowsAffected(); affectedRows == 0 {
-		return repositoryerrors.NewNotFoundError(msgs.CollectionNotFound, errors.New(msgs.CollectionNotFoundNewError))
+		return r.handleNotAffected(connection, collectionId, userId, version)
 	} else if resultErr != nil {
 		log.Error(resultErr)
 		return repositoryerrors.NewUnknownError(resultErr)
@@ -86,7 +87,91 @@ func (r Collection) Delete(collectionId, userId int) error {
 	return nil
 }
 
-func (r Collection) FindAll(userId int) ([]domain.Collection, error) {
+// Archive leaves the collection as it is when it is already archived
+func (r Collection) Archive(collectionId, userId int) error {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	result, err := connection.Exec(query.Collection().Archive(), collectionId, userId)
+	if err != nil {
+		log.Error(err)
+		return r.handlePostgresError(err)
+	}
+	if affectedRows, resultErr := result.RowsAffected(); resultErr != nil {
+		log.Error(resultErr)
+		return repositoryerrors.NewUnknownError(resultErr)
+	} else if affectedRows > 0 {
+		return nil
+	}
+
+	var exists bool
+	if err = connection.Get(&exists, query.Collection().Exists(), collectionId, userId); err != nil {
+		log.Error(err)
+		return r.handlePostgresError(err)
+	}
+	if !exists {
+		return repositoryerrors.NewNotFoundError(msgs.CollectionNotFound, errors.New(msgs.CollectionNotFoundNewError))
+	}
+
+	return nil
+}
+
+func (r Collection) FindAll(userId int, filter domain.CollectionFilter,
+	pagination domain.Pagination) ([]domain.Collection, int, error) {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return nil, 0, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	var total int
+	countQuery, countArgs := query.Collection().Select().Count(userId, filter)
+	err = connection.Get(&total, countQuery, countArgs...)
+	if err != nil {
+		log.Error(err)
+		return nil, 0, r.handlePostgresError(err)
+	}
+
+	destination := dto.Collection().Select().All()
+	selectQuery, selectArgs := query.Collection().Select().All(userId, filter, pagination)
+	err = connection.Select(&destination, selectQuery, selectArgs...)
+	if err != nil {
+		log.Error(err)
+		return nil, 0, r.handlePostgresError(err)
+	}
+	var collectionList []domain.Collection
+	for _, collection := range destination {
+		collectionList = append(collectionList, *collection.ConvertToDomain())
+	}
+
+	return collectionList, total, nil
+}
+
+func (r Collection) FindById(collectionId, userId int) (*domain.Collection, error) {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return nil, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	destination := dto.Collection().Select().ById()
+	err = connection.Get(&destination, query.Collection().Select().ById(), collectionId, userId)
+	if err != nil {
+		log.Error(err)
+		return nil, r.handlePostgresError(err)
+	}
+
+	return destination.ConvertToDomain(), nil
+}
+
+// FindByIds reads the collections of the user among the IDs, the others being left out
+func (r Collection) FindByIds(collectionIds []int, userId int) ([]domain.Collection, error) {
 	connection, err := r.getConnection()
 	if err != nil {
 		log.Error(err)
@@ -95,12 +180,13 @@ func (r Collection) FindAll(userId int) ([]domain.Collection, error) {
 	defer r.closeConnection(connection)
 
 	destination := dto.Collection().Select().All()
-	err = connection.Select(&destination, query.Collection().Select().All(), userId)
+	err = connection.Select(&destination, query.Collection().Select().ByIds(),
+		dto.Collection().Ids(collectionIds, userId)...)
 	if err != nil {
 		log.Error(err)
 		return nil, r.handlePostgresError(err)
 	}
-	var collectionList []domain.Collection
+	collectionList := []domain.Collection{}
 	for _, collection := range destination {
 		collectionList = append(collectionList, *collection.ConvertToDomain())
 	}
@@ -108,6 +194,89 @@ func (r Collection) FindAll(userId int) ([]domain.Collection, error) {
 	return collectionList, nil
 }
 
+func (r Collection) FindTasks(collectionId, userId int) ([]domain.Task, error) {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return nil, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	destination := dto.Task().Select().All()
+	err = connection.Select(&destination, query.Collection().Select().Tasks(), collectionId, userId)
+	if err != nil {
+		log.Error(err)
+		return nil, r.handlePostgresError(err)
+	}
+	taskList := []domain.Task{}
+	for _, task := range destination {
+		taskList = append(taskList, *task.ConvertToDomain())
+	}
+
+	return taskList, nil
+}
+
+// FindTasksOfCollections reads the tasks of several collections in a single query
+func (r Collection) FindTasksOfCollections(collectionIds []int, userId int) ([]domain.Task, error) {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return nil, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	destination := dto.Task().Select().All()
+	err = connection.Select(&destination, query.Collection().Select().TasksOfCollections(),
+		dto.Collection().Ids(collectionIds, userId)...)
+	if err != nil {
+		log.Error(err)
+		return nil, r.handlePostgresError(err)
+	}
+	taskList := []domain.Task{}
+	for _, task := range destination {
+		taskList = append(taskList, *task.ConvertToDomain())
+	}
+
+	return taskList, nil
+}
+
+func (r Collection) FindTags(collectionId, userId int) ([]string, error) {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return nil, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	tags := []string{}
+	err = connection.Select(&tags, query.Collection().Select().Tags(), collectionId, userId)
+	if err != nil {
+		log.Error(err)
+		return nil, r.handlePostgresError(err)
+	}
+
+	return tags, nil
+}
+
+// handleNotAffected tells apart a missing collection from one whose version no longer matches the expected one
+func (r Collection) handleNotAffected(connection *sqlx.DB, collectionId, userId, version int) error {
+	if version == 0 {
+		return repositoryerrors.NewNotFoundError(msgs.CollectionNotFound, errors.New(msgs.CollectionNotFoundNewError))
+	}
+
+	var exists bool
+	if err := connection.Get(&exists, query.Collection().Exists(), collectionId, userId); err != nil {
+		log.Error(err)
+		return r.handlePostgresError(err)
+	}
+	if !exists {
+		return repositoryerrors.NewNotFoundError(msgs.CollectionNotFound, errors.New(msgs.CollectionNotFoundNewError))
+	}
+
+	return repositoryerrors.NewPreconditionFailedError(msgs.CollectionVersionMismatch,
+		errors.New(msgs.CollectionVersionMismatchNewError))
+}
+
 func (r Collection) handlePostgresError(err error) error {
 	errMessage := err.Error()
 
@@ -0,0 +1,111 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/labstack/gommon/log"
+	"strings"
+	"time"
+	"todo/src/core/domain"
+	"todo/src/core/projecterrors/repositoryerrors"
+	"todo/src/infra/postgres/dto"
+	"todo/src/infra/postgres/msgs"
+	"todo/src/infra/postgres/query"
+)
+
+type Idempotency struct {
+	iConnectionManager
+}
+
+func NewIdempotencyPostgresRepository(connectionManager iConnectionManager) *Idempotency {
+	return &Idempotency{
+		connectionManager,
+	}
+}
+
+func (r Idempotency) Reserve(request domain.IdempotentRequest, userId int, expiredBefore time.Time) (bool, error) {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return false, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	_, err = connection.Exec(query.Idempotency().DeleteExpired(), userId, expiredBefore)
+	if err != nil {
+		log.Error(err)
+		return false, r.handlePostgresError(err)
+	}
+
+	var reserved bool
+	args := append(dto.Idempotency().Reserve(request, userId), expiredBefore)
+	err = connection.QueryRow(query.Idempotency().Reserve(), args...).Scan(&reserved)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	} else if err != nil {
+		log.Error(err)
+		return false, r.handlePostgresError(err)
+	}
+
+	return reserved, nil
+}
+
+func (r Idempotency) Complete(request domain.IdempotentRequest, userId int) error {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	_, err = connection.Exec(query.Idempotency().Complete(), dto.Idempotency().Complete(request, userId)...)
+	if err != nil {
+		log.Error(err)
+		return r.handlePostgresError(err)
+	}
+
+	return nil
+}
+
+func (r Idempotency) Release(request domain.IdempotentRequest, userId int) error {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	_, err = connection.Exec(query.Idempotency().Release(), userId, request.Key())
+	if err != nil {
+		log.Error(err)
+		return r.handlePostgresError(err)
+	}
+
+	return nil
+}
+
+func (r Idempotency) FindByKey(key string, userId int) (*domain.IdempotentRequest, error) {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return nil, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	destination := dto.Idempotency().Select().ByKey()
+	err = connection.Get(&destination, query.Idempotency().Select().ByKey(), key, userId)
+	if err != nil {
+		log.Error(err)
+		return nil, r.handlePostgresError(err)
+	}
+
+	return destination.ConvertToDomain(), nil
+}
+
+func (r Idempotency) handlePostgresError(err error) error {
+	if strings.Contains(err.Error(), "sql: no rows in result set") {
+		return repositoryerrors.NewNotFoundError(msgs.IdempotencyKeyNotFound, err)
+	}
+
+	return repositoryerrors.NewUnknownError(err)
+}
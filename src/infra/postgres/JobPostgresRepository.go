@@ -0,0 +1,337 @@
+package postgres
+
+import (
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/gommon/log"
+	"strings"
+	"time"
+	"todo/src/core/domain"
+	"todo/src/core/projecterrors/repositoryerrors"
+	"todo/src/infra/postgres/dto"
+	"todo/src/infra/postgres/msgs"
+	"todo/src/infra/postgres/query"
+)
+
+type Job struct {
+	iConnectionManager
+}
+
+func NewJobPostgresRepository(connectionManager iConnectionManager) *Job {
+	return &Job{
+		connectionManager,
+	}
+}
+
+func (r Job) Enqueue(job domain.Job) (int64, error) {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return -1, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	var id int64
+	if err = connection.QueryRow(query.Job().Insert(), dto.Job().Insert(job)...).Scan(&id); err != nil {
+		log.Error(err)
+		return -1, repositoryerrors.NewUnknownError(err)
+	}
+
+	return id, nil
+}
+
+// Claim fails the expired jobs that have no attempts left before claiming, so that a job stopping the instances
+// running it is not taken over forever
+func (r Job) Claim(workerId string, kinds []string, limit int, now time.Time) ([]domain.Job, error) {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return nil, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	if _, err = connection.Exec(query.Job().FailExpired(), now); err != nil {
+		log.Error(err)
+		return nil, repositoryerrors.NewUnknownError(err)
+	}
+
+	destination := dto.Job().Select().Jobs()
+	err = connection.Select(&destination, query.Job().Claim(), dto.Job().Claim(workerId, kinds, limit, now)...)
+	if err != nil {
+		log.Error(err)
+		return nil, repositoryerrors.NewUnknownError(err)
+	}
+	var jobList []domain.Job
+	for _, job := range destination {
+		jobList = append(jobList, *job.ConvertToDomain())
+	}
+
+	return jobList, nil
+}
+
+func (r Job) Complete(jobId int64, workerId string) error {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	result, err := connection.Exec(query.Job().Complete(), jobId, workerId)
+	if err != nil {
+		log.Error(err)
+		return repositoryerrors.NewUnknownError(err)
+	}
+
+	return r.checkAffectedRows(result.RowsAffected())
+}
+
+func (r Job) Fail(jobId int64, workerId, lastError string, retryAt time.Time, final bool) error {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	result, err := connection.Exec(query.Job().Fail(), jobId, workerId, lastError, retryAt, final)
+	if err != nil {
+		log.Error(err)
+		return repositoryerrors.NewUnknownError(err)
+	}
+
+	return r.checkAffectedRows(result.RowsAffected())
+}
+
+func (r Job) SaveSchedules(schedules []domain.JobSchedule, now time.Time) error {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	for _, schedule := range schedules {
+		if _, err = connection.Exec(query.Job().UpsertSchedule(), dto.Job().UpsertSchedule(schedule, now)...); err != nil {
+			log.Error(err)
+			return repositoryerrors.NewUnknownError(err)
+		}
+	}
+
+	return nil
+}
+
+// EnqueueScheduled keeps the schedules read locked until they have been moved to their next run, so that the other
+// instances skip them rather than queueing their jobs again
+func (r Job) EnqueueScheduled(now time.Time) (int, error) {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return 0, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	transaction, err := connection.Beginx()
+	if err != nil {
+		log.Error(err)
+		return 0, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	enqueued, err := r.enqueueScheduled(transaction, now)
+	if err != nil {
+		if rollbackErr := transaction.Rollback(); rollbackErr != nil {
+			log.Error(rollbackErr)
+		}
+		return 0, err
+	}
+	if err = transaction.Commit(); err != nil {
+		log.Error(err)
+		return 0, repositoryerrors.NewUnknownError(err)
+	}
+
+	return enqueued, nil
+}
+
+func (r Job) enqueueScheduled(transaction *sqlx.Tx, now time.Time) (int, error) {
+	destination := dto.Job().Select().Schedules()
+	if err := transaction.Select(&destination, query.Job().Select().DueSchedules(), now); err != nil {
+		log.Error(err)
+		return 0, repositoryerrors.NewUnknownError(err)
+	}
+
+	enqueued := 0
+	for _, row := range destination {
+		schedule, err := row.ConvertToDomain()
+		if err != nil {
+			log.Warnf("The job schedule %s could not be read: %v", row.Name, err)
+			continue
+		}
+
+		result, err := transaction.Exec(query.Job().InsertScheduled(), dto.Job().InsertScheduled(*schedule)...)
+		if err == nil {
+			var inserted int64
+			if inserted, err = result.RowsAffected(); err == nil {
+				enqueued += int(inserted)
+				_, err = transaction.Exec(query.Job().MoveSchedule(), schedule.Name(), schedule.Next(now))
+			}
+		}
+		if err != nil {
+			log.Error(err)
+			return 0, repositoryerrors.NewUnknownError(err)
+		}
+	}
+
+	return enqueued, nil
+}
+
+func (r Job) FindSchedules() ([]domain.JobSchedule, error) {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return nil, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	destination := dto.Job().Select().Schedules()
+	if err = connection.Select(&destination, query.Job().Select().Schedules()); err != nil {
+		log.Error(err)
+		return nil, repositoryerrors.NewUnknownError(err)
+	}
+	var scheduleList []domain.JobSchedule
+	for _, row := range destination {
+		schedule, err := row.ConvertToDomain()
+		if err != nil {
+			log.Error(err)
+			return nil, repositoryerrors.NewUnknownError(err)
+		}
+		scheduleList = append(scheduleList, *schedule)
+	}
+
+	return scheduleList, nil
+}
+
+func (r Job) FindById(jobId int64) (*domain.Job, error) {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return nil, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	destination := dto.Job().Select().Job()
+	if err = connection.Get(&destination, query.Job().Select().ById(), jobId); err != nil {
+		log.Error(err)
+		return nil, r.handlePostgresError(err)
+	}
+
+	return destination.ConvertToDomain(), nil
+}
+
+func (r Job) FindByIdOfUser(jobId int64, userId int) (*domain.Job, error) {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return nil, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	destination := dto.Job().Select().Job()
+	if err = connection.Get(&destination, query.Job().Select().ByIdOfUser(), jobId, userId); err != nil {
+		log.Error(err)
+		return nil, r.handlePostgresError(err)
+	}
+
+	return destination.ConvertToDomain(), nil
+}
+
+func (r Job) FindAll(filter domain.JobFilter, pagination domain.Pagination) ([]domain.Job, int, error) {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return nil, 0, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	var total int
+	if err = connection.Get(&total, query.Job().Select().Count(), filter.Status()); err != nil {
+		log.Error(err)
+		return nil, 0, r.handlePostgresError(err)
+	}
+
+	destination := dto.Job().Select().Jobs()
+	err = connection.Select(&destination, query.Job().Select().All(pagination), dto.Job().All(filter, pagination)...)
+	if err != nil {
+		log.Error(err)
+		return nil, 0, r.handlePostgresError(err)
+	}
+	var jobList []domain.Job
+	for _, job := range destination {
+		jobList = append(jobList, *job.ConvertToDomain())
+	}
+
+	return jobList, total, nil
+}
+
+func (r Job) Retry(jobId int64) error {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	result, err := connection.Exec(query.Job().Retry(), jobId)
+	if err != nil {
+		log.Error(err)
+		return r.handlePostgresError(err)
+	}
+	if affectedRows, err := result.RowsAffected(); err != nil {
+		log.Error(err)
+		return repositoryerrors.NewUnknownError(err)
+	} else if affectedRows == 0 {
+		return repositoryerrors.NewNotFoundError(msgs.FailedJobNotFound, errors.New(msgs.FailedJobNotFound))
+	}
+
+	return nil
+}
+
+func (r Job) DeleteFinished(succeededBefore, failedBefore time.Time) error {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	if _, err = connection.Exec(query.Job().DeleteFinished(), succeededBefore, failedBefore); err != nil {
+		log.Error(err)
+		return repositoryerrors.NewUnknownError(err)
+	}
+
+	return nil
+}
+
+// checkAffectedRows reports the jobs the worker no longer holds, as their lease has expired and another instance has
+// taken them over, as not found
+func (r Job) checkAffectedRows(affectedRows int64, resultErr error) error {
+	if resultErr != nil {
+		log.Error(resultErr)
+		return repositoryerrors.NewUnknownError(resultErr)
+	} else if affectedRows == 0 {
+		return repositoryerrors.NewNotFoundError(msgs.JobNotFound, errors.New(msgs.JobLeaseLost))
+	}
+
+	return nil
+}
+
+// handlePostgresError reports the failed jobs queued again while their schedule has another job queued as a conflict
+func (r Job) handlePostgresError(err error) error {
+	errMessage := err.Error()
+
+	if strings.Contains(errMessage, "sql: no rows in result set") {
+		return repositoryerrors.NewNotFoundError(msgs.JobNotFound, err)
+	} else if strings.Contains(errMessage, "job_schedule_active_idx") {
+		return repositoryerrors.NewDuplicatedError(msgs.JobScheduleActive, err, msgs.Schedule)
+	}
+
+	return repositoryerrors.NewUnknownError(err)
+}
@@ -0,0 +1,222 @@
+package postgres
+
+import (
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/gommon/log"
+	"strings"
+	"time"
+	"todo/src/core/domain"
+	"todo/src/core/projecterrors/repositoryerrors"
+	"todo/src/infra/postgres/dto"
+	"todo/src/infra/postgres/msgs"
+	"todo/src/infra/postgres/query"
+)
+
+type Notification struct {
+	iConnectionManager
+}
+
+func NewNotificationPostgresRepository(connectionManager iConnectionManager) *Notification {
+	return &Notification{
+		connectionManager,
+	}
+}
+
+// CreateReminder reports the task as not found when it does not belong to the account
+func (r Notification) CreateReminder(reminder domain.Reminder, userId int) (int, error) {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return -1, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	var id int
+	err = connection.QueryRow(query.Notification().InsertReminder(),
+		dto.Notification().InsertReminder(reminder, userId)...).Scan(&id)
+	if err != nil {
+		log.Error(err)
+		return -1, r.handlePostgresError(err, msgs.TaskNotFound)
+	}
+
+	return id, nil
+}
+
+func (r Notification) DeleteReminder(reminderId, taskId, userId int) error {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	result, err := connection.Exec(query.Notification().DeleteReminder(), reminderId, taskId, userId)
+	if err != nil {
+		log.Error(err)
+		return r.handlePostgresError(err, msgs.ReminderNotFound)
+	}
+
+	return r.checkAffectedRows(result.RowsAffected())
+}
+
+func (r Notification) FindReminders(taskId, userId int) ([]domain.Reminder, error) {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return nil, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	destination := dto.Notification().Select().Reminders()
+	err = connection.Select(&destination, query.Notification().Select().Reminders(), taskId, userId)
+	if err != nil {
+		log.Error(err)
+		return nil, r.handlePostgresError(err, msgs.ReminderNotFound)
+	}
+	var reminderList []domain.Reminder
+	for _, reminder := range destination {
+		reminderList = append(reminderList, *reminder.ConvertToDomain())
+	}
+
+	return reminderList, nil
+}
+
+func (r Notification) FindPreferences(userId int) (*domain.NotificationPreferences, error) {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return nil, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	destination := dto.Notification().Select().Preferences()
+	err = connection.Get(&destination, query.Notification().Select().Preferences(), userId)
+	if err != nil {
+		log.Error(err)
+		return nil, r.handlePostgresError(err, msgs.AccountNotFound)
+	}
+
+	return destination.ConvertToDomain(), nil
+}
+
+func (r Notification) UpdatePreferences(preferences domain.NotificationPreferences, userId int) error {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	_, err = connection.Exec(query.Notification().UpsertPreferences(),
+		dto.Notification().UpsertPreferences(preferences, userId)...)
+	if err != nil {
+		log.Error(err)
+		return r.handlePostgresError(err, msgs.AccountNotFound)
+	}
+
+	return nil
+}
+
+// Deliver claims the reminders with a lease in a short transaction, so that the other instances skip them while
+// they are sent without any transaction or lock held, and then writes the outcome of each one on its own
+func (r Notification) Deliver(now time.Time, limit int,
+	deliver func(reminder domain.DueReminder) error) (int, error) {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return 0, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	transaction, err := connection.Beginx()
+	if err != nil {
+		log.Error(err)
+		return 0, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	claimed, err := r.claim(transaction, now, limit)
+	if err != nil {
+		if rollbackErr := transaction.Rollback(); rollbackErr != nil {
+			log.Error(rollbackErr)
+		}
+		return 0, err
+	}
+	if err = transaction.Commit(); err != nil {
+		log.Error(err)
+		return 0, repositoryerrors.NewUnknownError(err)
+	}
+
+	delivered := 0
+	for _, reminder := range claimed {
+		reminderId := reminder.Reminder().Id()
+		err := deliver(reminder)
+
+		switch {
+		case err == nil:
+			_, err = connection.Exec(query.Notification().MarkReminder(), reminderId, domain.ReminderSent)
+			delivered++
+		case errors.Is(err, domain.ErrReminderSkipped):
+			_, err = connection.Exec(query.Notification().MarkReminder(), reminderId, domain.ReminderSkipped)
+			delivered++
+		default:
+			_, err = connection.Exec(query.Notification().RecordReminderFailure(), reminderId, err.Error(),
+				reminder.Reminder().Attempts()+1 >= domain.MaxReminderAttempts)
+		}
+		if err != nil {
+			log.Error(err)
+			return 0, repositoryerrors.NewUnknownError(err)
+		}
+	}
+
+	return delivered, nil
+}
+
+func (r Notification) claim(transaction *sqlx.Tx, now time.Time, limit int) ([]domain.DueReminder, error) {
+	destination := dto.Notification().Select().Due()
+	if err := transaction.Select(&destination, query.Notification().Select().Due(), now, limit); err != nil {
+		log.Error(err)
+		return nil, repositoryerrors.NewUnknownError(err)
+	}
+	if len(destination) == 0 {
+		return nil, nil
+	}
+
+	reminders := make([]domain.DueReminder, 0, len(destination))
+	reminderIds := make([]int, 0, len(destination))
+	for _, row := range destination {
+		reminders = append(reminders, *row.ConvertToDomain())
+		reminderIds = append(reminderIds, row.Id)
+	}
+	if _, err := transaction.Exec(query.Notification().Lease(),
+		dto.Notification().Lease(reminderIds, now)...); err != nil {
+		log.Error(err)
+		return nil, repositoryerrors.NewUnknownError(err)
+	}
+
+	return reminders, nil
+}
+
+func (r Notification) checkAffectedRows(affectedRows int64, resultErr error) error {
+	if resultErr != nil {
+		log.Error(resultErr)
+		return repositoryerrors.NewUnknownError(resultErr)
+	} else if affectedRows == 0 {
+		return repositoryerrors.NewNotFoundError(msgs.ReminderNotFound, errors.New(msgs.ReminderNotFound))
+	}
+
+	return nil
+}
+
+// handlePostgresError reports the missing rows as the entity the statement needs not being found, and the accounts
+// that do not exist when writing their preferences
+func (r Notification) handlePostgresError(err error, notFoundMessage string) error {
+	errMessage := err.Error()
+
+	if strings.Contains(errMessage, "sql: no rows in result set") {
+		return repositoryerrors.NewNotFoundError(notFoundMessage, err)
+	} else if strings.Contains(errMessage, "notification_preference_user_fk") {
+		return repositoryerrors.NewNotFoundError(msgs.AccountNotFound, err)
+	}
+
+	return repositoryerrors.NewUnknownError(err)
+}
@@ -0,0 +1,130 @@
+package postgres
+
+import (
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/gommon/log"
+	"time"
+	"todo/src/core/domain"
+	"todo/src/core/projecterrors/repositoryerrors"
+	"todo/src/infra/postgres/dto"
+	"todo/src/infra/postgres/msgs"
+	"todo/src/infra/postgres/query"
+)
+
+// outboxLockKey is the key of the advisory lock held while relaying, so that a single instance relays at a time and
+// the events are handed in order. It spells "outbox" in ASCII.
+const outboxLockKey = 0x6f7574626f78
+
+type Outbox struct {
+	iConnectionManager
+}
+
+func NewOutboxPostgresRepository(connectionManager iConnectionManager) *Outbox {
+	return &Outbox{
+		connectionManager,
+	}
+}
+
+// Relay reads nothing when another instance is relaying, and keeps the events read locked until their outcome has
+// been written. The jobs of an event are inserted in the transaction marking it as processed, so that they are
+// queued once whatever happens to the process.
+func (r Outbox) Relay(limit int, relay func(event domain.OutboxEvent) ([]domain.Job, error)) (int, error) {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return 0, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	transaction, err := connection.Beginx()
+	if err != nil {
+		log.Error(err)
+		return 0, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	relayed, err := r.relay(transaction, limit, relay)
+	if err != nil {
+		if rollbackErr := transaction.Rollback(); rollbackErr != nil {
+			log.Error(rollbackErr)
+		}
+		return 0, err
+	}
+	if err = transaction.Commit(); err != nil {
+		log.Error(err)
+		return 0, repositoryerrors.NewUnknownError(err)
+	}
+
+	return relayed, nil
+}
+
+func (r Outbox) relay(transaction *sqlx.Tx, limit int,
+	relay func(event domain.OutboxEvent) ([]domain.Job, error)) (int, error) {
+	var locked bool
+	if err := transaction.Get(&locked, query.Outbox().Lock(), outboxLockKey); err != nil {
+		log.Error(err)
+		return 0, repositoryerrors.NewUnknownError(err)
+	}
+	if !locked {
+		return 0, nil
+	}
+
+	destination := dto.Outbox().Select().Pending()
+	if err := transaction.Select(&destination, query.Outbox().Select().Pending(), limit); err != nil {
+		log.Error(err)
+		return 0, repositoryerrors.NewUnknownError(err)
+	}
+
+	relayed := 0
+	for _, row := range destination {
+		var jobList []domain.Job
+		event, err := row.ConvertToDomain()
+		if err == nil {
+			jobList, err = relay(*event)
+		}
+
+		switch {
+		case err == nil:
+			if err = r.enqueue(transaction, jobList); err == nil {
+				_, err = transaction.Exec(query.Outbox().MarkProcessed(), row.Id)
+			}
+			relayed++
+		case errors.Is(err, domain.ErrOutboxEventDeferred):
+			continue
+		default:
+			attempts := row.Attempts + 1
+			_, err = transaction.Exec(query.Outbox().RecordFailure(), row.Id, err.Error(),
+				time.Now().Add(domain.OutboxRetryWait(attempts)), attempts >= domain.MaxOutboxAttempts)
+		}
+		if err != nil {
+			log.Error(err)
+			return 0, repositoryerrors.NewUnknownError(err)
+		}
+	}
+
+	return relayed, nil
+}
+
+func (r Outbox) enqueue(transaction *sqlx.Tx, jobList []domain.Job) error {
+	for _, job := range jobList {
+		if _, err := transaction.Exec(query.Job().Insert(), dto.Job().Insert(job)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r Outbox) DeleteProcessed(before time.Time) error {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	if _, err = connection.Exec(query.Outbox().DeleteProcessed(), before); err != nil {
+		log.Error(err)
+		return repositoryerrors.NewUnknownError(err)
+	}
+
+	return nil
+}
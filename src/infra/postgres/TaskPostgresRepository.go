@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"errors"
+	"github.com/jmoiron/sqlx"
 	"github.com/labstack/gommon/log"
 	"strings"
 	"todo/src/core/domain"
@@ -29,17 +30,21 @@ func (r Task) Create(task domain.Task, userId int) (int, error) {
 	}
 	defer r.closeConnection(connection)
 
-	var id int
-	err = connection.QueryRow(query.Task().Insert(), dto.Task().Insert(task, userId)...).Scan(&id)
+	return r.create(connection, task, userId)
+}
+
+func (r Task) Update(task domain.Task, userId int) error {
+	connection, err := r.getConnection()
 	if err != nil {
 		log.Error(err)
-		return -1, r.handlePostgresError(err)
+		return repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
 	}
+	defer r.closeConnection(connection)
 
-	return id, nil
+	return r.update(connection, task, userId)
 }
 
-func (r Task) Update(task domain.Task, userId int) error {
+func (r Task) Delete(taskId, userId, version int) error {
 	connection, err := r.getConnection()
 	if err != nil {
 		log.Error(err)
@@ -47,14 +52,81 @@ func (r Task) Update(task domain.Task, userId int) error {
 	}
 	defer r.closeConnection(connection)
 
-	result, err := connection.Exec(query.Task().Update(), dto.Task().Update(task, userId)...)
+	return r.delete(connection, taskId, userId, version)
+}
+
+func (r Task) Batch(batch domain.TaskBatch, userId int) (*domain.TaskBatchResult, error) {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return nil, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	results := make([]domain.TaskOperationResult, len(batch.Operations()))
+	if !batch.Atomic() {
+		for index, operation := range batch.Operations() {
+			results[index] = *r.apply(connection, operation, userId)
+		}
+		return domain.NewTaskBatchResult(results, true), nil
+	}
+
+	transaction, err := connection.Beginx()
+	if err != nil {
+		log.Error(err)
+		return nil, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	for index, operation := range batch.Operations() {
+		results[index] = *r.apply(transaction, operation, userId)
+		if results[index].Err() != nil {
+			if rollbackErr := transaction.Rollback(); rollbackErr != nil {
+				log.Error(rollbackErr)
+			}
+			return domain.NewTaskBatchResult(results, false), nil
+		}
+	}
+	if err = transaction.Commit(); err != nil {
+		log.Error(err)
+		return nil, r.handlePostgresError(err)
+	}
+
+	return domain.NewTaskBatchResult(results, true), nil
+}
+
+func (r Task) apply(executor sqlx.Ext, operation domain.TaskOperation, userId int) *domain.TaskOperationResult {
+	switch operation.Kind() {
+	case domain.TaskOperationCreate:
+		id, err := r.create(executor, *operation.Task(), userId)
+		return domain.NewTaskOperationResult(id, err)
+	case domain.TaskOperationUpdate:
+		err := r.update(executor, *operation.Task(), userId)
+		return domain.NewTaskOperationResult(operation.TaskId(), err)
+	default:
+		err := r.delete(executor, operation.TaskId(), userId, operation.Version())
+		return domain.NewTaskOperationResult(operation.TaskId(), err)
+	}
+}
+
+func (r Task) create(executor sqlx.Ext, task domain.Task, userId int) (int, error) {
+	var id int
+	err := executor.QueryRowx(query.Task().Insert(), dto.Task().Insert(task, userId)...).Scan(&id)
+	if err != nil {
+		log.Error(err)
+		return -1, r.handlePostgresError(err)
+	}
+
+	return id, nil
+}
+
+func (r Task) update(executor sqlx.Ext, task domain.Task, userId int) error {
+	result, err := executor.Exec(query.Task().Update(), dto.Task().Update(task, userId)...)
 	if err != nil {
 		log.Error(err)
 		return r.handlePostgresError(err)
 	}
 	affectedRows, resultErr := result.RowsAffected()
 	if affectedRows == 0 {
-		return repositoryerrors.NewNotFoundError(msgs.TaskNotFound, errors.New(msgs.TaskNotFoundNewError))
+		return r.handleNotAffected(executor, task.Id(), userId, task.Version())
 	} else if resultErr != nil {
 		log.Error(resultErr)
 		return repositoryerrors.NewUnknownError(resultErr)
@@ -63,21 +135,14 @@ func (r Task) Update(task domain.Task, userId int) error {
 	return nil
 }
 
-func (r Task) Delete(taskId, userId int) error {
-	connection, err := r.getConnection()
-	if err != nil {
-		log.Error(err)
-		return repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
-	}
-	defer r.closeConnection(connection)
-
-	result, err := connection.Exec(query.Task().Delete(), taskId, userId)
+func (r Task) delete(executor sqlx.Ext, taskId, userId, version int) error {
+	result, err := executor.Exec(query.Task().Delete(), taskId, userId, version)
 	if err != nil {
 		log.Error(err)
 		return r.handlePostgresError(err)
 	}
 	if affectedRows, resultErr := result.RowsAffected(); affectedRows == 0 {
-		return repositoryerrors.NewNotFoundError(msgs.TaskNotFound, errors.New(msgs.TaskNotFoundNewError))
+		return r.handleNotAffected(executor, taskId, userId, version)
 	} else if resultErr != nil {
 		log.Error(resultErr)
 		return repositoryerrors.NewUnknownError(resultErr)
@@ -86,29 +151,59 @@ func (r Task) Delete(taskId, userId int) error {
 	return nil
 }
 
-func (r Task) FindAll(userId int) ([]domain.Task, error) {
+// handleNotAffected tells apart a missing task from one whose version no longer matches the expected one
+func (r Task) handleNotAffected(executor sqlx.Ext, taskId, userId, version int) error {
+	if version == 0 {
+		return repositoryerrors.NewNotFoundError(msgs.TaskNotFound, errors.New(msgs.TaskNotFoundNewError))
+	}
+
+	var exists bool
+	if err := sqlx.Get(executor, &exists, query.Task().Exists(), taskId, userId); err != nil {
+		log.Error(err)
+		return r.handlePostgresError(err)
+	}
+	if !exists {
+		return repositoryerrors.NewNotFoundError(msgs.TaskNotFound, errors.New(msgs.TaskNotFoundNewError))
+	}
+
+	return repositoryerrors.NewPreconditionFailedError(msgs.TaskVersionMismatch,
+		errors.New(msgs.TaskVersionMismatchNewError))
+}
+
+func (r Task) FindAll(userId int, filter domain.TaskFilter, pagination domain.Pagination) ([]domain.Task, int,
+	error) {
 	connection, err := r.getConnection()
 	if err != nil {
 		log.Error(err)
-		return nil, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+		return nil, 0, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
 	}
 	defer r.closeConnection(connection)
 
+	var total int
+	countQuery, countArgs := query.Task().Select().Count(userId, filter)
+	err = connection.Get(&total, countQuery, countArgs...)
+	if err != nil {
+		log.Error(err)
+		return nil, 0, r.handlePostgresError(err)
+	}
+
 	destination := dto.Task().Select().All()
-	err = connection.Select(&destination, query.Task().Select().All(), userId)
+	selectQuery, selectArgs := query.Task().Select().All(userId, filter, pagination)
+	err = connection.Select(&destination, selectQuery, selectArgs...)
 	if err != nil {
 		log.Error(err)
-		return nil, r.handlePostgresError(err)
+		return nil, 0, r.handlePostgresError(err)
 	}
 	var taskList []domain.Task
 	for _, task := range destination {
 		taskList = append(taskList, *task.ConvertToDomain())
 	}
 
-	return taskList, nil
+	return taskList, total, nil
 }
 
-func (r Task) FindById(taskId, userId int) (*domain.Task, error) {
+func (r Task) FindPage(userId int, filter domain.TaskFilter,
+	pagination domain.KeysetPagination) ([]domain.Task, error) {
 	connection, err := r.getConnection()
 	if err != nil {
 		log.Error(err)
@@ -116,17 +211,22 @@ func (r Task) FindById(taskId, userId int) (*domain.Task, error) {
 	}
 	defer r.closeConnection(connection)
 
-	destination := dto.Task().Select().ById()
-	err = connection.Get(&destination, query.Task().Select().ById(), taskId, userId)
+	destination := dto.Task().Select().All()
+	selectQuery, selectArgs := query.Task().Select().Page(userId, filter, pagination)
+	err = connection.Select(&destination, selectQuery, selectArgs...)
 	if err != nil {
 		log.Error(err)
 		return nil, r.handlePostgresError(err)
 	}
+	var taskList []domain.Task
+	for _, task := range destination {
+		taskList = append(taskList, *task.ConvertToDomain())
+	}
 
-	return destination.ConvertToDomain(), nil
+	return taskList, nil
 }
 
-func (r Task) FindByCollectionId(collectionId, userId int) ([]domain.Task, error) {
+func (r Task) FindById(taskId, userId int) (*domain.Task, error) {
 	connection, err := r.getConnection()
 	if err != nil {
 		log.Error(err)
@@ -134,18 +234,21 @@ func (r Task) FindByCollectionId(collectionId, userId int) ([]domain.Task, error
 	}
 	defer r.closeConnection(connection)
 
-	destination := dto.Task().Select().ByCollection()
-	err = connection.Select(&destination, query.Task().Select().ByCollection(), collectionId, userId)
+	destination := dto.Task().Select().ById()
+	err = connection.Get(&destination, query.Task().Select().ById(), taskId, userId)
 	if err != nil {
 		log.Error(err)
 		return nil, r.handlePostgresError(err)
 	}
-	var taskList []domain.Task
-	for _, task := range destination {
-		taskList = append(taskList, *task.ConvertToDomain())
-	}
 
-	return taskList, nil
+	return destination.ConvertToDomain(), nil
+}
+
+func (r Task) FindByCollectionId(collectionId, userId int, filter domain.TaskFilter,
+	pagination domain.Pagination) ([]domain.Task, int, error) {
+	filter.SetCollectionId(collectionId)
+
+	return r.FindAll(userId, filter, pagination)
 }
 
 func (r Task) handlePostgresError(err error) error {
@@ -0,0 +1,337 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/gommon/log"
+	"strings"
+	"todo/src/core/domain"
+	"todo/src/core/projecterrors/repositoryerrors"
+	"todo/src/infra/postgres/dto"
+	"todo/src/infra/postgres/msgs"
+	"todo/src/infra/postgres/query"
+)
+
+type Transfer struct {
+	iConnectionManager
+}
+
+func NewTransferPostgresRepository(connectionManager iConnectionManager) *Transfer {
+	return &Transfer{
+		connectionManager,
+	}
+}
+
+// Export reads the collections and the tasks in a single read only transaction, so that both are read from the same
+// snapshot and every task refers to a collection of the export
+func (r Transfer) Export(userId int, writeCollection func(collection domain.Collection) error,
+	writeTask func(task domain.Task) error) error {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	transaction, err := connection.BeginTxx(context.Background(),
+		&sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		log.Error(err)
+		return repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	err = r.exportCollections(transaction, userId, writeCollection)
+	if err == nil {
+		err = r.exportTasks(transaction, userId, writeTask)
+	}
+	if err != nil {
+		if rollbackErr := transaction.Rollback(); rollbackErr != nil {
+			log.Error(rollbackErr)
+		}
+		return err
+	}
+	if err = transaction.Commit(); err != nil {
+		log.Error(err)
+		return r.handlePostgresError(err)
+	}
+
+	return nil
+}
+
+// ExportCollections reads the collections with a cursor, so that they are never all held in memory
+func (r Transfer) ExportCollections(userId int, write func(collection domain.Collection) error) error {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	return r.exportCollections(connection, userId, write)
+}
+
+func (r Transfer) exportCollections(queryer sqlx.Queryer, userId int,
+	write func(collection domain.Collection) error) error {
+	rows, err := queryer.Queryx(query.Transfer().Select().Collections(), userId)
+	if err != nil {
+		log.Error(err)
+		return r.handlePostgresError(err)
+	}
+	defer r.closeRows(rows)
+
+	for rows.Next() {
+		destination := dto.Transfer().Select().Collection()
+		if err = rows.StructScan(&destination); err != nil {
+			log.Error(err)
+			return r.handlePostgresError(err)
+		}
+		if err = write(*destination.ConvertToDomain()); err != nil {
+			log.Error(err)
+			return repositoryerrors.NewUnknownError(err)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		log.Error(err)
+		return r.handlePostgresError(err)
+	}
+
+	return nil
+}
+
+// ExportTasks reads the tasks with a cursor, so that they are never all held in memory
+func (r Transfer) ExportTasks(userId int, write func(task domain.Task) error) error {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	return r.exportTasks(connection, userId, write)
+}
+
+func (r Transfer) exportTasks(queryer sqlx.Queryer, userId int, write func(task domain.Task) error) error {
+	rows, err := queryer.Queryx(query.Transfer().Select().Tasks(), userId)
+	if err != nil {
+		log.Error(err)
+		return r.handlePostgresError(err)
+	}
+	defer r.closeRows(rows)
+
+	for rows.Next() {
+		destination := dto.Transfer().Select().Task()
+		if err = rows.StructScan(&destination); err != nil {
+			log.Error(err)
+			return r.handlePostgresError(err)
+		}
+		if err = write(*destination.ConvertToDomain()); err != nil {
+			log.Error(err)
+			return repositoryerrors.NewUnknownError(err)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		log.Error(err)
+		return r.handlePostgresError(err)
+	}
+
+	return nil
+}
+
+// Import creates the collections first, so that the tasks are created with the new IDs of their collections. It
+// runs in a single transaction, so nothing is created when any row fails. The tasks without a collection of the
+// transfer go to the import collection of the account.
+func (r Transfer) Import(ctx context.Context, transfer domain.Transfer, userId int) (*domain.TransferResult, error) {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return nil, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	transaction, err := connection.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error(err)
+		return nil, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	result, err := r.insert(transaction, transfer, userId)
+	if err != nil {
+		if rollbackErr := transaction.Rollback(); rollbackErr != nil {
+			log.Error(rollbackErr)
+		}
+		return nil, err
+	}
+	if err = transaction.Commit(); err != nil {
+		log.Error(err)
+		return nil, r.handlePostgresError(err)
+	}
+
+	return result, nil
+}
+
+func (r Transfer) insert(transaction *sqlx.Tx, transfer domain.Transfer, userId int) (*domain.TransferResult, error) {
+	collectionIds := map[int]int{}
+	for _, collection := range transfer.Collections() {
+		var id int
+		err := transaction.QueryRowx(query.Transfer().InsertCollection(),
+			dto.Transfer().InsertCollection(collection, userId)...).Scan(&id)
+		if err != nil {
+			log.Error(err)
+			return nil, r.handlePostgresError(err)
+		}
+		collectionIds[collection.Id()] = id
+	}
+
+	taskIds := map[int]int{}
+	importCollectionId := 0
+	for _, task := range transfer.Tasks() {
+		collectionId, ok := collectionIds[task.Collection().Id()]
+		if !ok {
+			if importCollectionId == 0 {
+				var err error
+				importCollectionId, err = r.findOrCreateCollection(transaction, domain.ImportCollectionName, userId)
+				if err != nil {
+					return nil, err
+				}
+			}
+			collectionId = importCollectionId
+		}
+		var id int
+		err := transaction.QueryRowx(query.Transfer().InsertTask(),
+			dto.Transfer().InsertTask(task, &collectionId, userId)...).Scan(&id)
+		if err != nil {
+			log.Error(err)
+			return nil, r.handlePostgresError(err)
+		}
+		taskIds[task.Id()] = id
+	}
+
+	return domain.NewTransferResult(collectionIds, taskIds), nil
+}
+
+// ImportTasks matches the collections of the tasks by ID, or else by name ignoring the case, in a single transaction.
+// The tasks without a collection go to the import collection of the account.
+func (r Transfer) ImportTasks(tasks []domain.Task, userId int) ([]int, error) {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return nil, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	transaction, err := connection.Beginx()
+	if err != nil {
+		log.Error(err)
+		return nil, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	ids, err := r.insertTasks(transaction, tasks, userId)
+	if err != nil {
+		if rollbackErr := transaction.Rollback(); rollbackErr != nil {
+			log.Error(rollbackErr)
+		}
+		return nil, err
+	}
+	if err = transaction.Commit(); err != nil {
+		log.Error(err)
+		return nil, r.handlePostgresError(err)
+	}
+
+	return ids, nil
+}
+
+func (r Transfer) insertTasks(transaction *sqlx.Tx, tasks []domain.Task, userId int) ([]int, error) {
+	collectionIds := map[string]int{}
+	existingIds := map[int]bool{}
+	ids := []int{}
+	for _, task := range tasks {
+		var collectionId *int
+		if id := task.Collection().Id(); id != 0 {
+			if !existingIds[id] {
+				if err := r.checkCollection(transaction, id, userId); err != nil {
+					return nil, err
+				}
+				existingIds[id] = true
+			}
+			collectionId = &id
+		} else {
+			name := task.Collection().Name()
+			if name == "" {
+				name = domain.ImportCollectionName
+			}
+			key := strings.ToLower(name)
+			if _, ok := collectionIds[key]; !ok {
+				id, err := r.findOrCreateCollection(transaction, name, userId)
+				if err != nil {
+					return nil, err
+				}
+				collectionIds[key] = id
+			}
+			id := collectionIds[key]
+			collectionId = &id
+		}
+
+		var id int
+		err := transaction.QueryRowx(query.Transfer().InsertTask(),
+			dto.Transfer().InsertTask(task, collectionId, userId)...).Scan(&id)
+		if err != nil {
+			log.Error(err)
+			return nil, r.handlePostgresError(err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func (r Transfer) checkCollection(transaction *sqlx.Tx, collectionId, userId int) error {
+	var exists bool
+	err := transaction.Get(&exists, query.Collection().Exists(), collectionId, userId)
+	if err != nil {
+		log.Error(err)
+		return r.handlePostgresError(err)
+	}
+	if !exists {
+		return repositoryerrors.NewDependencyError(msgs.CollectionNotFound,
+			errors.New(msgs.CollectionNotFoundNewError), msgs.Collection)
+	}
+
+	return nil
+}
+
+func (r Transfer) findOrCreateCollection(transaction *sqlx.Tx, name string, userId int) (int, error) {
+	var ids []int
+	err := transaction.Select(&ids, query.Transfer().Select().CollectionIdByName(), userId, name)
+	if err != nil {
+		log.Error(err)
+		return 0, r.handlePostgresError(err)
+	}
+	if len(ids) > 0 {
+		return ids[0], nil
+	}
+
+	var id int
+	err = transaction.QueryRowx(query.Transfer().InsertCollection(),
+		dto.Transfer().InsertCollection(*domain.NewCollection(0, name), userId)...).Scan(&id)
+	if err != nil {
+		log.Error(err)
+		return 0, r.handlePostgresError(err)
+	}
+
+	return id, nil
+}
+
+func (r Transfer) closeRows(rows *sqlx.Rows) {
+	if err := rows.Close(); err != nil {
+		log.Error(err)
+	}
+}
+
+func (r Transfer) handlePostgresError(err error) error {
+	errMessage := err.Error()
+
+	if strings.Contains(errMessage, "task_collection_fk") {
+		return repositoryerrors.NewDependencyError(msgs.CollectionNotFound, err, msgs.Collection)
+	}
+
+	return repositoryerrors.NewUnknownError(err)
+}
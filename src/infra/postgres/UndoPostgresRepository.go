@@ -0,0 +1,157 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/gommon/log"
+	"strings"
+	"time"
+	"todo/src/core/domain"
+	"todo/src/core/projecterrors/repositoryerrors"
+	"todo/src/infra/postgres/dto"
+	"todo/src/infra/postgres/msgs"
+	"todo/src/infra/postgres/query"
+)
+
+type Undo struct {
+	iConnectionManager
+}
+
+func NewUndoPostgresRepository(connectionManager iConnectionManager) *Undo {
+	return &Undo{
+		connectionManager,
+	}
+}
+
+func (r Undo) Create(operation domain.UndoOperation, userId int) error {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	_, err = connection.Exec(query.Undo().DeleteExpired(), userId, time.Now())
+	if err != nil {
+		log.Error(err)
+		return r.handlePostgresError(err)
+	}
+
+	args, err := dto.Undo().Insert(operation, userId)
+	if err != nil {
+		log.Error(err)
+		return repositoryerrors.NewUnknownError(err)
+	}
+	_, err = connection.Exec(query.Undo().Insert(), args...)
+	if err != nil {
+		log.Error(err)
+		return r.handlePostgresError(err)
+	}
+
+	return nil
+}
+
+// Undo reverses the operation in a single transaction, consuming the token. Nothing is reversed when any of the
+// affected data has changed since the operation.
+func (r Undo) Undo(token string, userId int, now time.Time) error {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	transaction, err := connection.Beginx()
+	if err != nil {
+		log.Error(err)
+		return repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	if err = r.undo(transaction, token, userId, now); err != nil {
+		if rollbackErr := transaction.Rollback(); rollbackErr != nil {
+			log.Error(rollbackErr)
+		}
+		return err
+	}
+	if err = transaction.Commit(); err != nil {
+		log.Error(err)
+		return r.handlePostgresError(err)
+	}
+
+	return nil
+}
+
+func (r Undo) undo(transaction *sqlx.Tx, token string, userId int, now time.Time) error {
+	destination := dto.Undo().Select().ByToken()
+	err := transaction.Get(&destination, query.Undo().Select().ByToken(), token, userId, now)
+	if errors.Is(err, sql.ErrNoRows) {
+		return repositoryerrors.NewNotFoundError(msgs.UndoTokenNotFound, err)
+	} else if err != nil {
+		log.Error(err)
+		return r.handlePostgresError(err)
+	}
+	operation, err := destination.ConvertToDomain()
+	if err != nil {
+		log.Error(msgs.UndoChangesParseError, err)
+		return repositoryerrors.NewUnknownError(err)
+	}
+
+	if collection := operation.Collection(); collection != nil {
+		result, err := transaction.Exec(query.Undo().RestoreCollection(),
+			dto.Undo().RestoreCollection(*collection, userId)...)
+		if err = r.checkReverted(result, err, msgs.Collection); err != nil {
+			return err
+		}
+	}
+	for _, change := range operation.TaskChanges() {
+		var result sql.Result
+		if change.Previous() == nil {
+			result, err = transaction.Exec(query.Undo().DeleteTask(), change.TaskId(), userId, change.Version())
+		} else if change.Version() == 0 {
+			result, err = transaction.Exec(query.Undo().RestoreTask(),
+				dto.Undo().RestoreTask(*change.Previous(), userId)...)
+		} else {
+			result, err = transaction.Exec(query.Undo().RevertTask(),
+				dto.Undo().RevertTask(*change.Previous(), userId, change.Version())...)
+		}
+		if err = r.checkReverted(result, err, msgs.Task); err != nil {
+			return err
+		}
+	}
+
+	_, err = transaction.Exec(query.Undo().Delete(), userId, token)
+	if err != nil {
+		log.Error(err)
+		return r.handlePostgresError(err)
+	}
+
+	return nil
+}
+
+// checkReverted reports a conflict when the statement has not affected the expected row
+func (r Undo) checkReverted(result sql.Result, err error, entity string) error {
+	if err != nil {
+		log.Error(err)
+		return r.handlePostgresError(err)
+	}
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		log.Error(err)
+		return repositoryerrors.NewUnknownError(err)
+	}
+	if affectedRows == 0 {
+		return repositoryerrors.NewDuplicatedError(msgs.UndoConflict, errors.New(msgs.UndoConflictNewError), entity)
+	}
+
+	return nil
+}
+
+func (r Undo) handlePostgresError(err error) error {
+	errMessage := err.Error()
+
+	if strings.Contains(errMessage, "task_collection_fk") {
+		return repositoryerrors.NewDuplicatedError(msgs.UndoConflict, err, msgs.Collection)
+	}
+
+	return repositoryerrors.NewUnknownError(err)
+}
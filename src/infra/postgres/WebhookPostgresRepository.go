@@ -0,0 +1,232 @@
+package postgres
+
+import (
+	"errors"
+	"github.com/labstack/gommon/log"
+	"strings"
+	"todo/src/core/domain"
+	"todo/src/core/projecterrors/repositoryerrors"
+	"todo/src/infra/postgres/dto"
+	"todo/src/infra/postgres/msgs"
+	"todo/src/infra/postgres/query"
+)
+
+type Webhook struct {
+	iConnectionManager
+}
+
+func NewWebhookPostgresRepository(connectionManager iConnectionManager) *Webhook {
+	return &Webhook{
+		connectionManager,
+	}
+}
+
+func (r Webhook) Create(webhook domain.Webhook, userId int) (int, error) {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return -1, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	var id int
+	err = connection.QueryRow(query.Webhook().Insert(), dto.Webhook().Insert(webhook, userId)...).Scan(&id)
+	if err != nil {
+		log.Error(err)
+		return -1, r.handlePostgresError(err)
+	}
+
+	return id, nil
+}
+
+func (r Webhook) Update(webhook domain.Webhook, userId int) error {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	result, err := connection.Exec(query.Webhook().Update(), dto.Webhook().Update(webhook, userId)...)
+	if err != nil {
+		log.Error(err)
+		return r.handlePostgresError(err)
+	}
+
+	return r.checkAffectedRows(result.RowsAffected())
+}
+
+func (r Webhook) Delete(webhookId, userId int) error {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	result, err := connection.Exec(query.Webhook().Delete(), webhookId, userId)
+	if err != nil {
+		log.Error(err)
+		return r.handlePostgresError(err)
+	}
+
+	return r.checkAffectedRows(result.RowsAffected())
+}
+
+func (r Webhook) FindById(webhookId, userId int) (*domain.Webhook, error) {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return nil, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	destination := dto.Webhook().Select().ById()
+	err = connection.Get(&destination, query.Webhook().Select().ById(), webhookId, userId)
+	if err != nil {
+		log.Error(err)
+		return nil, r.handlePostgresError(err)
+	}
+
+	return destination.ConvertToDomain(), nil
+}
+
+func (r Webhook) FindAll(userId int) ([]domain.Webhook, error) {
+	return r.findWebhooks(query.Webhook().Select().All(), userId)
+}
+
+// FindSubscribed reads the active webhooks of the account with the event type, along with their secret
+func (r Webhook) FindSubscribed(userId int, eventType string) ([]domain.Webhook, error) {
+	return r.findWebhooks(query.Webhook().Select().Subscribed(), userId, eventType)
+}
+
+func (r Webhook) findWebhooks(sql string, args ...interface{}) ([]domain.Webhook, error) {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return nil, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	destination := dto.Webhook().Select().All()
+	err = connection.Select(&destination, sql, args...)
+	if err != nil {
+		log.Error(err)
+		return nil, r.handlePostgresError(err)
+	}
+	var webhookList []domain.Webhook
+	for _, webhook := range destination {
+		webhookList = append(webhookList, *webhook.ConvertToDomain())
+	}
+
+	return webhookList, nil
+}
+
+func (r Webhook) CreateDelivery(delivery domain.WebhookDelivery) error {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	_, err = connection.Exec(query.Webhook().InsertDelivery(), dto.Webhook().InsertDelivery(delivery)...)
+	if err != nil {
+		log.Error(err)
+		return r.handlePostgresError(err)
+	}
+
+	return nil
+}
+
+func (r Webhook) FindDeliveries(webhookId, userId int,
+	pagination domain.Pagination) ([]domain.WebhookDelivery, int, error) {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return nil, 0, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	var total int
+	err = connection.Get(&total, query.Webhook().Select().DeliveryCount(), webhookId, userId)
+	if err != nil {
+		log.Error(err)
+		return nil, 0, r.handlePostgresError(err)
+	}
+
+	destination := dto.Webhook().Select().Deliveries()
+	err = connection.Select(&destination, query.Webhook().Select().Deliveries(pagination),
+		dto.Webhook().Deliveries(webhookId, userId, pagination)...)
+	if err != nil {
+		log.Error(err)
+		return nil, 0, r.handlePostgresError(err)
+	}
+	var deliveryList []domain.WebhookDelivery
+	for _, delivery := range destination {
+		deliveryList = append(deliveryList, *delivery.ConvertToDomain())
+	}
+
+	return deliveryList, total, nil
+}
+
+func (r Webhook) FindEventDeliveries(eventId int64) ([]domain.WebhookDelivery, error) {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return nil, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	destination := dto.Webhook().Select().Deliveries()
+	if err = connection.Select(&destination, query.Webhook().Select().EventDeliveries(), eventId); err != nil {
+		log.Error(err)
+		return nil, r.handlePostgresError(err)
+	}
+	var deliveryList []domain.WebhookDelivery
+	for _, delivery := range destination {
+		deliveryList = append(deliveryList, *delivery.ConvertToDomain())
+	}
+
+	return deliveryList, nil
+}
+
+// RecordOutcome returns whether the webhook is disabled once the outcome of the delivery has been counted
+func (r Webhook) RecordOutcome(webhookId int, succeeded bool, failureLimit int) (bool, error) {
+	connection, err := r.getConnection()
+	if err != nil {
+		log.Error(err)
+		return false, repositoryerrors.NewServiceUnavailableError(msgs.ConnectionError, err)
+	}
+	defer r.closeConnection(connection)
+
+	var disabled bool
+	err = connection.QueryRow(query.Webhook().RecordOutcome(), webhookId, succeeded, failureLimit).Scan(&disabled)
+	if err != nil {
+		log.Error(err)
+		return false, r.handlePostgresError(err)
+	}
+
+	return disabled, nil
+}
+
+func (r Webhook) checkAffectedRows(affectedRows int64, resultErr error) error {
+	if resultErr != nil {
+		log.Error(resultErr)
+		return repositoryerrors.NewUnknownError(resultErr)
+	} else if affectedRows == 0 {
+		return repositoryerrors.NewNotFoundError(msgs.WebhookNotFound, errors.New(msgs.WebhookNotFound))
+	}
+
+	return nil
+}
+
+func (r Webhook) handlePostgresError(err error) error {
+	errMessage := err.Error()
+
+	if strings.Contains(errMessage, "sql: no rows in result set") {
+		return repositoryerrors.NewNotFoundError(msgs.WebhookNotFound, err)
+	}
+
+	return repositoryerrors.NewUnknownError(err)
+}
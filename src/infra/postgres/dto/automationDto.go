@@ -0,0 +1,113 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+	"todo/src/core/domain"
+)
+
+type automationDto struct {
+	Id        int       `db:"automation_id"`
+	Name      string    `db:"automation_name"`
+	Trigger   string    `db:"automation_trigger"`
+	Condition string    `db:"automation_condition"`
+	Actions   []byte    `db:"automation_actions"`
+	Active    bool      `db:"automation_active"`
+	CreatedAt time.Time `db:"automation_created_at"`
+}
+
+// automationActionDto is an action as it is kept in the actions column, leaving out the parameters its kind does
+// not read
+type automationActionDto struct {
+	Kind         string `json:"type"`
+	CollectionId int    `json:"collection_id,omitempty"`
+	Tag          string `json:"tag,omitempty"`
+	Finished     bool   `json:"finished,omitempty"`
+	Text         string `json:"text,omitempty"`
+}
+
+func (d automationDto) ConvertToDomain() (*domain.Automation, error) {
+	var actionDtos []automationActionDto
+	if err := json.Unmarshal(d.Actions, &actionDtos); err != nil {
+		return nil, err
+	}
+	var actions []domain.AutomationAction
+	for _, action := range actionDtos {
+		actions = append(actions, *domain.NewAutomationAction(action.Kind, action.CollectionId, action.Tag,
+			action.Finished, action.Text))
+	}
+
+	automation := domain.NewAutomation(d.Id, d.Name, d.Trigger, d.Condition, actions, d.Active)
+	automation.SetCreatedAt(d.CreatedAt)
+
+	return automation, nil
+}
+
+type automationDtoManager struct{}
+
+func Automation() *automationDtoManager {
+	return &automationDtoManager{}
+}
+
+func (automationDtoManager) Insert(automation domain.Automation, userId int) ([]interface{}, error) {
+	actions, err := automationActions(automation)
+	if err != nil {
+		return nil, err
+	}
+
+	return []interface{}{
+		automation.Name(),
+		automation.Trigger(),
+		automation.Condition(),
+		actions,
+		automation.Active(),
+		userId,
+	}, nil
+}
+
+func (automationDtoManager) Update(automation domain.Automation, userId int) ([]interface{}, error) {
+	actions, err := automationActions(automation)
+	if err != nil {
+		return nil, err
+	}
+
+	return []interface{}{
+		automation.Name(),
+		automation.Trigger(),
+		automation.Condition(),
+		actions,
+		automation.Active(),
+		automation.Id(),
+		userId,
+	}, nil
+}
+
+func automationActions(automation domain.Automation) (string, error) {
+	actionDtos := []automationActionDto{}
+	for _, action := range automation.Actions() {
+		actionDtos = append(actionDtos, automationActionDto{
+			Kind:         action.Kind(),
+			CollectionId: action.CollectionId(),
+			Tag:          action.Tag(),
+			Finished:     action.Finished(),
+			Text:         action.Text(),
+		})
+	}
+
+	actions, err := json.Marshal(actionDtos)
+	return string(actions), err
+}
+
+type automationDtoSelectManager struct{}
+
+func (automationDtoManager) Select() *automationDtoSelectManager {
+	return &automationDtoSelectManager{}
+}
+
+func (automationDtoSelectManager) All() []automationDto {
+	return []automationDto{}
+}
+
+func (automationDtoSelectManager) ById() automationDto {
+	return automationDto{}
+}
@@ -0,0 +1,42 @@
+package dto
+
+import "todo/src/core/domain"
+
+type calendarObjectDto struct {
+	taskDto
+	Name string `db:"object_name"`
+	Uid  string `db:"object_uid"`
+}
+
+func (d calendarObjectDto) ConvertToDomain() *domain.CalendarObject {
+	return domain.NewCalendarObject(d.Name, d.Uid, d.taskDto.ConvertToDomain())
+}
+
+type calDavDtoManager struct{}
+
+func CalDav() *calDavDtoManager {
+	return &calDavDtoManager{}
+}
+
+func (calDavDtoManager) SaveObject(object domain.CalendarObject, userId int) []interface{} {
+	return []interface{}{
+		object.Task().Id(),
+		object.Name(),
+		object.Uid(),
+		userId,
+	}
+}
+
+type calDavDtoSelectManager struct{}
+
+func (calDavDtoManager) Select() *calDavDtoSelectManager {
+	return &calDavDtoSelectManager{}
+}
+
+func (calDavDtoSelectManager) Objects() []calendarObjectDto {
+	return []calendarObjectDto{}
+}
+
+func (calDavDtoSelectManager) ByName() calendarObjectDto {
+	return calendarObjectDto{}
+}
@@ -1,14 +1,26 @@
 package dto
 
-import "todo/src/core/domain"
+import (
+	"github.com/lib/pq"
+	"time"
+	"todo/src/core/domain"
+)
 
 type collectionDto struct {
-	Id   int    `db:"collection_id"`
-	Name string `db:"collection_name"`
+	Id         int        `db:"collection_id"`
+	Name       string     `db:"collection_name"`
+	CreatedAt  time.Time  `db:"collection_created_at"`
+	Version    int        `db:"collection_version"`
+	ArchivedAt *time.Time `db:"collection_archived_at"`
 }
 
 func (d collectionDto) ConvertToDomain() *domain.Collection {
-	return domain.NewCollection(d.Id, d.Name)
+	collection := domain.NewCollection(d.Id, d.Name)
+	collection.SetCreatedAt(d.CreatedAt)
+	collection.SetVersion(d.Version)
+	collection.SetArchivedAt(d.ArchivedAt)
+
+	return collection
 }
 
 type collectionDtoManager struct{}
@@ -29,6 +41,14 @@ func (collectionDtoManager) Update(collection domain.Collection, userId int) []i
 		collection.Name(),
 		collection.Id(),
 		userId,
+		collection.Version(),
+	}
+}
+
+func (collectionDtoManager) Ids(collectionIds []int, userId int) []interface{} {
+	return []interface{}{
+		pq.Array(collectionIds),
+		userId,
 	}
 }
 
@@ -0,0 +1,67 @@
+package dto
+
+import (
+	"encoding/json"
+	"github.com/labstack/gommon/log"
+	"todo/src/core/domain"
+)
+
+type idempotencyDto struct {
+	Key         string `db:"idempotency_key"`
+	Fingerprint string `db:"idempotency_fingerprint"`
+	Status      int    `db:"idempotency_status"`
+	ContentType string `db:"idempotency_content_type"`
+	Headers     []byte `db:"idempotency_headers"`
+	Body        []byte `db:"idempotency_body"`
+	Completed   bool   `db:"idempotency_completed"`
+}
+
+func (d idempotencyDto) ConvertToDomain() *domain.IdempotentRequest {
+	request := domain.NewIdempotentRequest(d.Key, d.Fingerprint)
+	if d.Completed {
+		var headers map[string]string
+		if err := json.Unmarshal(d.Headers, &headers); err != nil {
+			log.Error(err)
+		}
+		request.SetResponse(d.Status, d.ContentType, headers, d.Body)
+	}
+
+	return request
+}
+
+type idempotencyDtoManager struct{}
+
+func Idempotency() *idempotencyDtoManager {
+	return &idempotencyDtoManager{}
+}
+
+func (idempotencyDtoManager) Reserve(request domain.IdempotentRequest, userId int) []interface{} {
+	return []interface{}{
+		userId,
+		request.Key(),
+		request.Fingerprint(),
+	}
+}
+
+// Complete encodes the headers of the response, which can't fail for a map of strings
+func (idempotencyDtoManager) Complete(request domain.IdempotentRequest, userId int) []interface{} {
+	headers, _ := json.Marshal(request.Headers())
+	return []interface{}{
+		request.Status(),
+		request.ContentType(),
+		string(headers),
+		request.Body(),
+		userId,
+		request.Key(),
+	}
+}
+
+type idempotencyDtoSelectManager struct{}
+
+func (idempotencyDtoManager) Select() *idempotencyDtoSelectManager {
+	return &idempotencyDtoSelectManager{}
+}
+
+func (idempotencyDtoSelectManager) ByKey() idempotencyDto {
+	return idempotencyDto{}
+}
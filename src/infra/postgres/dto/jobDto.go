@@ -0,0 +1,126 @@
+package dto
+
+import (
+	"github.com/lib/pq"
+	"time"
+	"todo/src/core/domain"
+)
+
+type jobDto struct {
+	Id          int64      `db:"job_id"`
+	Kind        string     `db:"job_kind"`
+	Payload     []byte     `db:"job_payload"`
+	Status      string     `db:"job_status"`
+	Attempts    int        `db:"job_attempts"`
+	MaxAttempts int        `db:"job_max_attempts"`
+	LastError   string     `db:"job_last_error"`
+	RunAt       time.Time  `db:"job_run_at"`
+	Schedule    string     `db:"job_schedule"`
+	UserId      int        `db:"job_user_id"`
+	LockedBy    string     `db:"job_locked_by"`
+	LockedUntil *time.Time `db:"job_locked_until"`
+	StartedAt   *time.Time `db:"job_started_at"`
+	FinishedAt  *time.Time `db:"job_finished_at"`
+	CreatedAt   time.Time  `db:"job_created_at"`
+}
+
+func (d jobDto) ConvertToDomain() *domain.Job {
+	job := domain.NewJob(d.Id, d.Kind, d.Payload, d.RunAt)
+	job.SetState(d.Status, d.Attempts, d.MaxAttempts, d.LastError)
+	job.SetSchedule(d.Schedule)
+	job.SetUserId(d.UserId)
+	job.SetLock(d.LockedBy, d.LockedUntil)
+	job.SetTimes(d.StartedAt, d.FinishedAt, d.CreatedAt)
+
+	return job
+}
+
+type jobScheduleDto struct {
+	Name      string    `db:"schedule_name"`
+	Kind      string    `db:"schedule_kind"`
+	Spec      string    `db:"schedule_spec"`
+	Payload   []byte    `db:"schedule_payload"`
+	NextRunAt time.Time `db:"schedule_next_run_at"`
+}
+
+// ConvertToDomain fails when the expression saved can no longer be read
+func (d jobScheduleDto) ConvertToDomain() (*domain.JobSchedule, error) {
+	schedule, err := domain.NewJobSchedule(d.Name, d.Kind, d.Spec, d.Payload)
+	if err != nil {
+		return nil, err
+	}
+	schedule.SetNextRunAt(d.NextRunAt)
+
+	return schedule, nil
+}
+
+type jobDtoManager struct{}
+
+func Job() *jobDtoManager {
+	return &jobDtoManager{}
+}
+
+func (jobDtoManager) Insert(job domain.Job) []interface{} {
+	return []interface{}{
+		job.Kind(),
+		string(job.Payload()),
+		job.MaxAttempts(),
+		job.RunAt(),
+		job.UserId(),
+	}
+}
+
+func (jobDtoManager) Claim(workerId string, kinds []string, limit int, now time.Time) []interface{} {
+	return []interface{}{
+		workerId,
+		pq.Array(kinds),
+		limit,
+		now,
+		now.Add(domain.JobLease),
+	}
+}
+
+func (jobDtoManager) UpsertSchedule(schedule domain.JobSchedule, now time.Time) []interface{} {
+	return []interface{}{
+		schedule.Name(),
+		schedule.Kind(),
+		schedule.Spec(),
+		string(schedule.Payload()),
+		schedule.Next(now),
+	}
+}
+
+func (jobDtoManager) InsertScheduled(schedule domain.JobSchedule) []interface{} {
+	return []interface{}{
+		schedule.Kind(),
+		string(schedule.Payload()),
+		schedule.NextRunAt(),
+		schedule.Name(),
+	}
+}
+
+func (jobDtoManager) All(filter domain.JobFilter, pagination domain.Pagination) []interface{} {
+	return []interface{}{
+		filter.Status(),
+		pagination.Limit(),
+		pagination.Offset(),
+	}
+}
+
+type jobDtoSelectManager struct{}
+
+func (jobDtoManager) Select() *jobDtoSelectManager {
+	return &jobDtoSelectManager{}
+}
+
+func (jobDtoSelectManager) Job() jobDto {
+	return jobDto{}
+}
+
+func (jobDtoSelectManager) Jobs() []jobDto {
+	return []jobDto{}
+}
+
+func (jobDtoSelectManager) Schedules() []jobScheduleDto {
+	return []jobScheduleDto{}
+}
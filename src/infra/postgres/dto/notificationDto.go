@@ -0,0 +1,110 @@
+package dto
+
+import (
+	"github.com/lib/pq"
+	"time"
+	"todo/src/core/domain"
+)
+
+type reminderDto struct {
+	Id               int        `db:"reminder_id"`
+	TaskId           int        `db:"reminder_task_id"`
+	RemindAt         *time.Time `db:"reminder_remind_at"`
+	MinutesBeforeDue *int       `db:"reminder_minutes_before_due"`
+	Status           string     `db:"reminder_status"`
+	Attempts         int        `db:"reminder_attempts"`
+	LastError        string     `db:"reminder_last_error"`
+	ProcessedAt      *time.Time `db:"reminder_processed_at"`
+	CreatedAt        time.Time  `db:"reminder_created_at"`
+}
+
+func (d reminderDto) ConvertToDomain() *domain.Reminder {
+	reminder := domain.NewReminder(d.Id, d.TaskId, d.RemindAt, d.MinutesBeforeDue)
+	reminder.SetDelivery(d.Status, d.Attempts, d.LastError)
+	if d.ProcessedAt != nil {
+		reminder.SetProcessedAt(*d.ProcessedAt)
+	}
+	reminder.SetCreatedAt(d.CreatedAt)
+
+	return reminder
+}
+
+type preferencesDto struct {
+	Email              bool `db:"preference_email"`
+	ScheduledReminders bool `db:"preference_scheduled_reminders"`
+	DueDateReminders   bool `db:"preference_due_date_reminders"`
+}
+
+func (d preferencesDto) ConvertToDomain() *domain.NotificationPreferences {
+	return domain.NewNotificationPreferences(d.Email, d.ScheduledReminders, d.DueDateReminders)
+}
+
+type dueReminderDto struct {
+	reminderDto
+	preferencesDto
+	TaskDescription string     `db:"task_description"`
+	TaskFinished    bool       `db:"task_finished"`
+	TaskDueDate     *time.Time `db:"task_due_date"`
+	AccountId       int        `db:"account_id"`
+	AccountName     string     `db:"account_name"`
+	AccountEmail    string     `db:"account_email"`
+}
+
+func (d dueReminderDto) ConvertToDomain() *domain.DueReminder {
+	task := domain.NewTask(d.TaskId, d.TaskDescription, d.TaskFinished, nil)
+	task.SetDueDate(d.TaskDueDate)
+	account := domain.NewAccount(d.AccountId, d.AccountName, d.AccountEmail, "", "")
+
+	return domain.NewDueReminder(*d.reminderDto.ConvertToDomain(), *task, *account,
+		*d.preferencesDto.ConvertToDomain())
+}
+
+type notificationDtoManager struct{}
+
+func Notification() *notificationDtoManager {
+	return &notificationDtoManager{}
+}
+
+func (notificationDtoManager) InsertReminder(reminder domain.Reminder, userId int) []interface{} {
+	return []interface{}{
+		reminder.RemindAt(),
+		reminder.MinutesBeforeDue(),
+		reminder.TaskId(),
+		userId,
+	}
+}
+
+func (notificationDtoManager) UpsertPreferences(preferences domain.NotificationPreferences,
+	userId int) []interface{} {
+	return []interface{}{
+		preferences.Email(),
+		preferences.ScheduledReminders(),
+		preferences.DueDateReminders(),
+		userId,
+	}
+}
+
+func (notificationDtoManager) Lease(reminderIds []int, now time.Time) []interface{} {
+	return []interface{}{
+		pq.Array(reminderIds),
+		now.Add(domain.ReminderLease),
+	}
+}
+
+type notificationDtoSelectManager struct{}
+
+func (notificationDtoManager) Select() *notificationDtoSelectManager {
+	return &notificationDtoSelectManager{}
+}
+
+func (notificationDtoSelectManager) Reminders() []reminderDto {
+	return []reminderDto{}
+}
+
+func (notificationDtoSelectManager) Preferences() preferencesDto {
+	return preferencesDto{}
+}
+
+func (notificationDtoSelectManager) Due() []dueReminderDto {
+	return []dueReminderDto{}
+}
@@ -0,0 +1,104 @@
+package dto
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+	"todo/src/core/domain"
+)
+
+type outboxDto struct {
+	Id            int64     `db:"outbox_id"`
+	AggregateType string    `db:"outbox_aggregate_type"`
+	AggregateId   int       `db:"outbox_aggregate_id"`
+	EventType     string    `db:"outbox_event_type"`
+	Payload       []byte    `db:"outbox_payload"`
+	CreatedAt     time.Time `db:"outbox_created_at"`
+	Attempts      int       `db:"outbox_attempts"`
+	AvailableAt   time.Time `db:"outbox_available_at"`
+	UserId        int       `db:"outbox_user_id"`
+}
+
+// outboxPayloadDto is the state of the task or collection after the change, as written by to_jsonb
+type outboxPayloadDto struct {
+	Task         *outboxTaskDto       `json:"task"`
+	Collection   *outboxCollectionDto `json:"collection"`
+	FinishesTask bool                 `json:"finishes_task"`
+}
+
+type outboxTaskDto struct {
+	Id           int        `json:"id"`
+	Description  string     `json:"description"`
+	Finished     bool       `json:"finished"`
+	CreatedAt    time.Time  `json:"created_at"`
+	Version      int        `json:"version"`
+	DueDate      *time.Time `json:"due_date"`
+	Tags         []string   `json:"tags"`
+	CollectionId *int       `json:"collection_id"`
+}
+
+type outboxCollectionDto struct {
+	Id        int       `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	Version   int       `json:"version"`
+}
+
+func (d outboxDto) ConvertToDomain() (*domain.OutboxEvent, error) {
+	var payload outboxPayloadDto
+	if err := json.Unmarshal(d.Payload, &payload); err != nil {
+		return nil, err
+	}
+
+	var task *domain.Task
+	var collection *domain.Collection
+	version := 0
+	if payload.Task != nil {
+		var taskCollection *domain.Collection
+		if payload.Task.CollectionId != nil {
+			taskCollection = domain.NewCollection(*payload.Task.CollectionId, "")
+		}
+		task = domain.NewTask(payload.Task.Id, payload.Task.Description, payload.Task.Finished, taskCollection)
+		task.SetCreatedAt(payload.Task.CreatedAt)
+		task.SetVersion(payload.Task.Version)
+		task.SetDueDate(payload.Task.DueDate)
+		task.SetTags(payload.Task.Tags)
+		version = task.Version()
+	}
+	if payload.Collection != nil {
+		collection = domain.NewCollection(payload.Collection.Id, payload.Collection.Name)
+		collection.SetCreatedAt(payload.Collection.CreatedAt)
+		collection.SetVersion(payload.Collection.Version)
+		version = collection.Version()
+	}
+
+	action := strings.TrimPrefix(d.EventType, d.AggregateType+".")
+	event := domain.NewChangeEvent(d.UserId, d.AggregateType, action, d.AggregateId, version)
+	event.SetId(d.Id)
+	event.SetOccurredAt(d.CreatedAt)
+	event.SetTask(task, nil)
+	event.SetFinishesTask(payload.FinishesTask)
+	event.SetCollection(collection)
+
+	return domain.NewOutboxEvent(*event, d.Attempts, d.AvailableAt), nil
+}
+
+type outboxDtoManager struct{}
+
+func Outbox() *outboxDtoManager {
+	return &outboxDtoManager{}
+}
+
+type outboxDtoSelectManager struct{}
+
+func (outboxDtoManager) Select() *outboxDtoSelectManager {
+	return &outboxDtoSelectManager{}
+}
+
+func (outboxDtoSelectManager) Pending() []outboxDto {
+	return []outboxDto{}
+}
+
+func (outboxDtoSelectManager) Events() []outboxDto {
+	return []outboxDto{}
+}
@@ -1,19 +1,32 @@
 package dto
 
-import "todo/src/core/domain"
+import (
+	"github.com/lib/pq"
+	"time"
+	"todo/src/core/domain"
+)
 
 type taskDto struct {
-	Id             int    `db:"task_id"`
-	Description    string `db:"task_description"`
-	Finished       bool   `db:"task_finished"`
-	CollectionId   int    `db:"collection_id"`
-	CollectionName string `db:"collection_name"`
+	Id             int            `db:"task_id"`
+	Description    string         `db:"task_description"`
+	Finished       bool           `db:"task_finished"`
+	CreatedAt      time.Time      `db:"task_created_at"`
+	Version        int            `db:"task_version"`
+	DueDate        *time.Time     `db:"task_due_date"`
+	Tags           pq.StringArray `db:"task_tags"`
+	CollectionId   int            `db:"collection_id"`
+	CollectionName string         `db:"collection_name"`
 }
 
 func (d taskDto) ConvertToDomain() *domain.Task {
 	collection := domain.NewCollection(d.CollectionId, d.CollectionName)
+	task := domain.NewTask(d.Id, d.Description, d.Finished, collection)
+	task.SetCreatedAt(d.CreatedAt)
+	task.SetVersion(d.Version)
+	task.SetDueDate(d.DueDate)
+	task.SetTags(d.Tags)
 
-	return domain.NewTask(d.Id, d.Description, d.Finished, collection)
+	return task
 }
 
 type taskDtoManager struct{}
@@ -23,34 +36,43 @@ func Task() *taskDtoManager {
 }
 
 func (taskDtoManager) Insert(task domain.Task, userId int) []interface{} {
-	var collection *int
-	collectionId := task.Collection().Id()
-	if collectionId != 0 {
-		collection = &collectionId
-	}
-
 	return []interface{}{
 		task.Description(),
 		task.Finished(),
-		collection,
+		task.DueDate(),
+		pq.Array(tags(task)),
+		collectionId(task),
 		userId,
 	}
 }
 
 func (taskDtoManager) Update(task domain.Task, userId int) []interface{} {
-	var collection *int
-	collectionId := task.Collection().Id()
-	if collectionId != 0 {
-		collection = &collectionId
-	}
-
 	return []interface{}{
 		task.Description(),
 		task.Finished(),
-		collection,
+		task.DueDate(),
+		pq.Array(tags(task)),
+		collectionId(task),
 		task.Id(),
 		userId,
+		task.Version(),
+	}
+}
+
+// collectionId is nil for a task without collection, so that no foreign key is set
+func collectionId(task domain.Task) *int {
+	id := task.Collection().Id()
+	if id == 0 {
+		return nil
 	}
+	return &id
+}
+
+func tags(task domain.Task) []string {
+	if task.Tags() == nil {
+		return []string{}
+	}
+	return task.Tags()
 }
 
 type taskDtoSelectManager struct{}
@@ -66,7 +88,3 @@ func (taskDtoSelectManager) All() []taskDto {
 func (taskDtoSelectManager) ById() taskDto {
 	return taskDto{}
 }
-
-func (taskDtoSelectManager) ByCollection() []taskDto {
-	return []taskDto{}
-}
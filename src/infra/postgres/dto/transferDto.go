@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"github.com/lib/pq"
+	"time"
+	"todo/src/core/domain"
+)
+
+type transferDtoManager struct{}
+
+func Transfer() *transferDtoManager {
+	return &transferDtoManager{}
+}
+
+func (transferDtoManager) InsertCollection(collection domain.Collection, userId int) []interface{} {
+	return []interface{}{
+		collection.Name(),
+		optionalTime(collection.CreatedAt()),
+		userId,
+	}
+}
+
+// InsertTask takes the ID the collection of the task has been created with
+func (transferDtoManager) InsertTask(task domain.Task, collectionId *int, userId int) []interface{} {
+	return []interface{}{
+		task.Description(),
+		task.Finished(),
+		optionalTime(task.CreatedAt()),
+		task.DueDate(),
+		pq.Array(tags(task)),
+		collectionId,
+		userId,
+	}
+}
+
+type transferDtoSelectManager struct{}
+
+func (transferDtoManager) Select() *transferDtoSelectManager {
+	return &transferDtoSelectManager{}
+}
+
+func (transferDtoSelectManager) Collection() collectionDto {
+	return collectionDto{}
+}
+
+func (transferDtoSelectManager) Task() taskDto {
+	return taskDto{}
+}
+
+// optionalTime is nil for the zero time, so that the database default is used
+func optionalTime(value time.Time) *time.Time {
+	if value.IsZero() {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,168 @@
+package dto
+
+import (
+	"encoding/json"
+	"github.com/lib/pq"
+	"time"
+	"todo/src/core/domain"
+)
+
+type undoDto struct {
+	Token     string    `db:"undo_token"`
+	Kind      string    `db:"undo_kind"`
+	Changes   []byte    `db:"undo_changes"`
+	ExpiresAt time.Time `db:"undo_expires_at"`
+}
+
+// undoChangesDto is the JSON kept with the operation, holding the data needed to reverse it
+type undoChangesDto struct {
+	Tasks      []taskChangeDto    `json:"tasks,omitempty"`
+	Collection *undoCollectionDto `json:"collection,omitempty"`
+}
+
+type taskChangeDto struct {
+	TaskId   int          `json:"taskId"`
+	Version  int          `json:"version"`
+	Previous *undoTaskDto `json:"previous,omitempty"`
+}
+
+type undoTaskDto struct {
+	Description  string     `json:"description"`
+	Finished     bool       `json:"finished"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	Version      int        `json:"version"`
+	DueDate      *time.Time `json:"dueDate,omitempty"`
+	Tags         []string   `json:"tags"`
+	CollectionId int        `json:"collectionId"`
+}
+
+type undoCollectionDto struct {
+	Id        int       `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
+	Version   int       `json:"version"`
+}
+
+func (d undoDto) ConvertToDomain() (*domain.UndoOperation, error) {
+	var changes undoChangesDto
+	if err := json.Unmarshal(d.Changes, &changes); err != nil {
+		return nil, err
+	}
+
+	var taskChanges []domain.TaskChange
+	for _, change := range changes.Tasks {
+		var previousTask *domain.Task
+		if change.Previous != nil {
+			collection := domain.NewCollection(change.Previous.CollectionId, "")
+			previousTask = domain.NewTask(change.TaskId, change.Previous.Description, change.Previous.Finished,
+				collection)
+			previousTask.SetCreatedAt(change.Previous.CreatedAt)
+			previousTask.SetVersion(change.Previous.Version)
+			previousTask.SetDueDate(change.Previous.DueDate)
+			previousTask.SetTags(change.Previous.Tags)
+		}
+		taskChanges = append(taskChanges, *domain.NewTaskChange(change.TaskId, previousTask, change.Version))
+	}
+
+	var collection *domain.Collection
+	if changes.Collection != nil {
+		collection = domain.NewCollection(changes.Collection.Id, changes.Collection.Name)
+		collection.SetCreatedAt(changes.Collection.CreatedAt)
+		collection.SetVersion(changes.Collection.Version)
+	}
+
+	return domain.NewUndoOperation(d.Token, d.Kind, taskChanges, collection, d.ExpiresAt), nil
+}
+
+type undoDtoManager struct{}
+
+func Undo() *undoDtoManager {
+	return &undoDtoManager{}
+}
+
+func (undoDtoManager) Insert(operation domain.UndoOperation, userId int) ([]interface{}, error) {
+	changes := undoChangesDto{}
+	for _, change := range operation.TaskChanges() {
+		changeDto := taskChangeDto{TaskId: change.TaskId(), Version: change.Version()}
+		if previousTask := change.Previous(); previousTask != nil {
+			changeDto.Previous = &undoTaskDto{
+				Description:  previousTask.Description(),
+				Finished:     previousTask.Finished(),
+				CreatedAt:    previousTask.CreatedAt(),
+				Version:      previousTask.Version(),
+				DueDate:      previousTask.DueDate(),
+				Tags:         tags(*previousTask),
+				CollectionId: previousTask.Collection().Id(),
+			}
+		}
+		changes.Tasks = append(changes.Tasks, changeDto)
+	}
+	if collection := operation.Collection(); collection != nil {
+		changes.Collection = &undoCollectionDto{
+			Id:        collection.Id(),
+			Name:      collection.Name(),
+			CreatedAt: collection.CreatedAt(),
+			Version:   collection.Version(),
+		}
+	}
+
+	encodedChanges, err := json.Marshal(changes)
+	if err != nil {
+		return nil, err
+	}
+
+	return []interface{}{
+		userId,
+		operation.Token(),
+		operation.Kind(),
+		encodedChanges,
+		operation.ExpiresAt(),
+	}, nil
+}
+
+func (undoDtoManager) RestoreTask(task domain.Task, userId int) []interface{} {
+	return []interface{}{
+		task.Id(),
+		task.Description(),
+		task.Finished(),
+		task.CreatedAt(),
+		task.Version(),
+		task.DueDate(),
+		pq.Array(tags(task)),
+		collectionId(task),
+		userId,
+	}
+}
+
+func (undoDtoManager) RevertTask(task domain.Task, userId, version int) []interface{} {
+	return []interface{}{
+		task.Description(),
+		task.Finished(),
+		task.DueDate(),
+		pq.Array(tags(task)),
+		collectionId(task),
+		task.Id(),
+		userId,
+		version,
+	}
+}
+
+func (undoDtoManager) RestoreCollection(collection domain.Collection, userId int) []interface{} {
+	return []interface{}{
+		collection.Id(),
+		collection.Name(),
+		collection.CreatedAt(),
+		collection.Version(),
+		userId,
+	}
+}
+
+type undoDtoSelectManager struct{}
+
+func (undoDtoManager) Select() *undoDtoSelectManager {
+	return &undoDtoSelectManager{}
+}
+
+func (undoDtoSelectManager) ByToken() undoDto {
+	return undoDto{}
+}
@@ -0,0 +1,115 @@
+package dto
+
+import (
+	"github.com/lib/pq"
+	"time"
+	"todo/src/core/domain"
+)
+
+type webhookDto struct {
+	Id         int            `db:"webhook_id"`
+	Url        string         `db:"webhook_url"`
+	Secret     string         `db:"webhook_secret"`
+	EventTypes pq.StringArray `db:"webhook_event_types"`
+	Active     bool           `db:"webhook_active"`
+	Failures   int            `db:"webhook_failures"`
+	CreatedAt  time.Time      `db:"webhook_created_at"`
+}
+
+func (d webhookDto) ConvertToDomain() *domain.Webhook {
+	webhook := domain.NewWebhook(d.Id, d.Url, d.EventTypes, d.Active)
+	webhook.SetSecret(d.Secret)
+	webhook.SetFailures(d.Failures)
+	webhook.SetCreatedAt(d.CreatedAt)
+
+	return webhook
+}
+
+type webhookDeliveryDto struct {
+	Id             int64     `db:"delivery_id"`
+	WebhookId      int       `db:"delivery_webhook_id"`
+	EventId        int64     `db:"delivery_event_id"`
+	EventType      string    `db:"delivery_event_type"`
+	Attempt        int       `db:"delivery_attempt"`
+	RequestBody    string    `db:"delivery_request_body"`
+	ResponseStatus int       `db:"delivery_response_status"`
+	Error          string    `db:"delivery_error"`
+	DurationMs     int64     `db:"delivery_duration_ms"`
+	CreatedAt      time.Time `db:"delivery_created_at"`
+}
+
+func (d webhookDeliveryDto) ConvertToDomain() *domain.WebhookDelivery {
+	delivery := domain.NewWebhookDelivery(d.WebhookId, d.EventId, d.EventType, d.Attempt, d.RequestBody)
+	delivery.SetId(d.Id)
+	delivery.SetResult(d.ResponseStatus, d.Error, time.Duration(d.DurationMs)*time.Millisecond)
+	delivery.SetCreatedAt(d.CreatedAt)
+
+	return delivery
+}
+
+type webhookDtoManager struct{}
+
+func Webhook() *webhookDtoManager {
+	return &webhookDtoManager{}
+}
+
+func (webhookDtoManager) Insert(webhook domain.Webhook, userId int) []interface{} {
+	return []interface{}{
+		webhook.Url(),
+		webhook.Secret(),
+		pq.Array(webhook.EventTypes()),
+		webhook.Active(),
+		userId,
+	}
+}
+
+func (webhookDtoManager) Update(webhook domain.Webhook, userId int) []interface{} {
+	return []interface{}{
+		webhook.Url(),
+		pq.Array(webhook.EventTypes()),
+		webhook.Active(),
+		webhook.Id(),
+		userId,
+	}
+}
+
+func (webhookDtoManager) InsertDelivery(delivery domain.WebhookDelivery) []interface{} {
+	return []interface{}{
+		delivery.WebhookId(),
+		delivery.EventId(),
+		delivery.EventType(),
+		delivery.Attempt(),
+		delivery.RequestBody(),
+		delivery.ResponseStatus(),
+		delivery.ErrorMessage(),
+		delivery.Duration().Milliseconds(),
+		delivery.CreatedAt(),
+	}
+}
+
+func (webhookDtoManager) Deliveries(webhookId, userId int, pagination domain.Pagination) []interface{} {
+	return []interface{}{
+		webhookId,
+		userId,
+		pagination.Limit(),
+		pagination.Offset(),
+	}
+}
+
+type webhookDtoSelectManager struct{}
+
+func (webhookDtoManager) Select() *webhookDtoSelectManager {
+	return &webhookDtoSelectManager{}
+}
+
+func (webhookDtoSelectManager) All() []webhookDto {
+	return []webhookDto{}
+}
+
+func (webhookDtoSelectManager) ById() webhookDto {
+	return webhookDto{}
+}
+
+func (webhookDtoSelectManager) Deliveries() []webhookDeliveryDto {
+	return []webhookDeliveryDto{}
+}
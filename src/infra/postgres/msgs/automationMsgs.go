@@ -0,0 +1,5 @@
+package msgs
+
+const (
+	AutomationNotFound = "The reported automation was not found."
+)
@@ -0,0 +1,5 @@
+package msgs
+
+const (
+	CalendarObjectNotFound = "The reported calendar object was not found."
+)
@@ -0,0 +1,5 @@
+package msgs
+
+const (
+	CalendarFeedNotFound = "The reported calendar feed was not found."
+)
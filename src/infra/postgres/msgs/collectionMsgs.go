@@ -1,6 +1,8 @@
 package msgs
 
 const (
-	CollectionNotFound         = "The reported collection was not found."
-	CollectionNotFoundNewError = "the reported collection was not found"
+	CollectionNotFound                = "The reported collection was not found."
+	CollectionNotFoundNewError        = "the reported collection was not found"
+	CollectionVersionMismatch         = "The reported collection has been changed since it was last read."
+	CollectionVersionMismatchNewError = "the reported collection version does not match"
 )
@@ -2,4 +2,6 @@ package msgs
 
 const (
 	Collection = "collection"
+	Task       = "task"
+	Schedule   = "schedule"
 )
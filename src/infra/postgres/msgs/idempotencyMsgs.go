@@ -0,0 +1,5 @@
+package msgs
+
+const (
+	IdempotencyKeyNotFound = "The reported idempotency key was not found."
+)
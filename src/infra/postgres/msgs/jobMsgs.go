@@ -0,0 +1,8 @@
+package msgs
+
+const (
+	JobNotFound       = "The reported job was not found."
+	FailedJobNotFound = "The reported job was not found or has not failed."
+	JobScheduleActive = "The schedule of the job has another job queued or running."
+	JobLeaseLost      = "the job is no longer held by the worker"
+)
@@ -0,0 +1,6 @@
+package msgs
+
+const (
+	ReminderNotFound = "The reported reminder was not found."
+	AccountNotFound  = "The reported account was not found."
+)
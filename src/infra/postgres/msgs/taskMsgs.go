@@ -1,6 +1,8 @@
 package msgs
 
 const (
-	TaskNotFound         = "The reported task was not found."
-	TaskNotFoundNewError = "the reported task was not found"
+	TaskNotFound                = "The reported task was not found."
+	TaskNotFoundNewError        = "the reported task was not found"
+	TaskVersionMismatch         = "The reported task has been changed since it was last read."
+	TaskVersionMismatchNewError = "the reported task version does not match"
 )
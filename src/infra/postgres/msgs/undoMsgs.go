@@ -0,0 +1,8 @@
+package msgs
+
+const (
+	UndoTokenNotFound     = "The reported undo token was not found or has expired."
+	UndoConflict          = "The data changed by the operation has been changed since, so it can't be undone."
+	UndoConflictNewError  = "the data changed by the operation no longer has the expected version"
+	UndoChangesParseError = "the changes kept with the operation could not be read"
+)
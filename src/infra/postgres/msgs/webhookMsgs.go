@@ -0,0 +1,5 @@
+package msgs
+
+const (
+	WebhookNotFound = "The reported webhook was not found."
+)
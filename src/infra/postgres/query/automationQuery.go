@@ -0,0 +1,66 @@
+package query
+
+type automationSqlManager struct{}
+
+func Automation() *automationSqlManager {
+	return &automationSqlManager{}
+}
+
+func (automationSqlManager) Insert() string {
+	return `INSERT INTO automation (name, trigger, condition, actions, active, user_id) VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id;`
+}
+
+func (automationSqlManager) Update() string {
+	return `UPDATE automation SET name = $1, trigger = $2, condition = $3, actions = $4, active = $5
+			WHERE id = $6 AND user_id = $7;`
+}
+
+func (automationSqlManager) Delete() string {
+	return "DELETE FROM automation WHERE id = $1 AND user_id = $2;"
+}
+
+type automationSelectSqlManager struct{}
+
+func (automationSqlManager) Select() *automationSelectSqlManager {
+	return &automationSelectSqlManager{}
+}
+
+func (automationSelectSqlManager) ById() string {
+	return `SELECT id   		AS automation_id,
+				   name			AS automation_name,
+				   trigger		AS automation_trigger,
+				   condition	AS automation_condition,
+				   actions		AS automation_actions,
+				   active		AS automation_active,
+				   created_at	AS automation_created_at
+			FROM automation
+			WHERE id = $1 AND user_id = $2;`
+}
+
+func (automationSelectSqlManager) All() string {
+	return `SELECT id   		AS automation_id,
+				   name			AS automation_name,
+				   trigger		AS automation_trigger,
+				   condition	AS automation_condition,
+				   actions		AS automation_actions,
+				   active		AS automation_active,
+				   created_at	AS automation_created_at
+			FROM automation
+			WHERE user_id = $1
+			ORDER BY id;`
+}
+
+// Triggered reads the active automations of the account with the trigger, in the order they have been created
+func (automationSelectSqlManager) Triggered() string {
+	return `SELECT id   		AS automation_id,
+				   name			AS automation_name,
+				   trigger		AS automation_trigger,
+				   condition	AS automation_condition,
+				   actions		AS automation_actions,
+				   active		AS automation_active,
+				   created_at	AS automation_created_at
+			FROM automation
+			WHERE user_id = $1 AND trigger = $2 AND active
+			ORDER BY id;`
+}
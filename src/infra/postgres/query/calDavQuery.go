@@ -0,0 +1,47 @@
+package query
+
+type calDavSqlManager struct{}
+
+func CalDav() *calDavSqlManager {
+	return &calDavSqlManager{}
+}
+
+// SaveObject only names the tasks of the account
+func (calDavSqlManager) SaveObject() string {
+	return `INSERT INTO caldav_object (task_id, name, uid)
+			SELECT id, $2, $3 FROM task WHERE id = $1 AND user_id = $4
+			ON CONFLICT (task_id) DO UPDATE SET name = EXCLUDED.name, uid = EXCLUDED.uid;`
+}
+
+type calDavSelectSqlManager struct{}
+
+func (calDavSqlManager) Select() *calDavSelectSqlManager {
+	return &calDavSelectSqlManager{}
+}
+
+func (calDavSelectSqlManager) Objects() string {
+	return calendarObjectSelect + " ORDER BY t.id;"
+}
+
+func (calDavSelectSqlManager) ByName() string {
+	return calendarObjectSelect + " AND " + calendarObjectName + " = $3 LIMIT 1;"
+}
+
+// calendarObjectName names the tasks that were not created by a CalDAV client after their ID
+const calendarObjectName = "COALESCE(o.name, 'task-' || t.id || '.ics')"
+
+const calendarObjectSelect = `SELECT t.id						AS task_id,
+									 t.description				AS task_description,
+									 t.finished					AS task_finished,
+									 t.created_at				AS task_created_at,
+									 t.version					AS task_version,
+									 t.due_date					AS task_due_date,
+									 t.tags						AS task_tags,
+									 c.id						AS collection_id,
+									 c.name						AS collection_name,
+									 ` + calendarObjectName + ` AS object_name,
+									 COALESCE(o.uid, '')		AS object_uid
+							  FROM task t
+							  INNER JOIN collection c ON t.collection_id = c.id
+							  LEFT JOIN caldav_object o ON o.task_id = t.id
+							  WHERE t.collection_id = $1 AND t.user_id = $2`
@@ -0,0 +1,47 @@
+package query
+
+type calendarSqlManager struct{}
+
+func Calendar() *calendarSqlManager {
+	return &calendarSqlManager{}
+}
+
+func (calendarSqlManager) SaveFeedToken() string {
+	return `INSERT INTO calendar_feed (user_id, token) VALUES ($1, $2)
+			ON CONFLICT (user_id) DO UPDATE SET token = EXCLUDED.token, created_at = CURRENT_TIMESTAMP;`
+}
+
+func (calendarSqlManager) DeleteFeedToken() string {
+	return "DELETE FROM calendar_feed WHERE user_id = $1;"
+}
+
+type calendarSelectSqlManager struct{}
+
+func (calendarSqlManager) Select() *calendarSelectSqlManager {
+	return &calendarSelectSqlManager{}
+}
+
+func (calendarSelectSqlManager) FeedToken() string {
+	return "SELECT token FROM calendar_feed WHERE user_id = $1;"
+}
+
+func (calendarSelectSqlManager) FeedOwner() string {
+	return "SELECT user_id FROM calendar_feed WHERE token = $1;"
+}
+
+// Tasks reads the tasks of the account, or only those of the collection when its ID is not zero
+func (calendarSelectSqlManager) Tasks() string {
+	return `SELECT t.id						AS task_id,
+				   t.description			AS task_description,
+				   t.finished				AS task_finished,
+				   t.created_at				AS task_created_at,
+				   t.version				AS task_version,
+				   t.due_date				AS task_due_date,
+				   t.tags					AS task_tags,
+				   COALESCE(c.id, 0)		AS collection_id,
+				   COALESCE(c.name, '')		AS collection_name
+			FROM task t
+			LEFT JOIN collection c ON t.collection_id = c.id
+			WHERE t.user_id = $1 AND ($2::int = 0 OR t.collection_id = $2)
+			ORDER BY t.id;`
+}
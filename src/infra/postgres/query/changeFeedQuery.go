@@ -0,0 +1,55 @@
+package query
+
+type changeFeedSqlManager struct{}
+
+func ChangeFeed() *changeFeedSqlManager {
+	return &changeFeedSqlManager{}
+}
+
+func (changeFeedSqlManager) LastEventId() string {
+	return "SELECT COALESCE(MAX(id), 0) FROM outbox_event;"
+}
+
+func (changeFeedSqlManager) EventExists() string {
+	return "SELECT EXISTS (SELECT 1 FROM outbox_event WHERE id = $1 AND user_id = $2);"
+}
+
+type changeFeedSelectSqlManager struct{}
+
+func (changeFeedSqlManager) Select() *changeFeedSelectSqlManager {
+	return &changeFeedSelectSqlManager{}
+}
+
+// Events reads the events written after the ID, along with the ones whose ID has been skipped while their
+// transaction was still running
+func (changeFeedSelectSqlManager) Events() string {
+	return `SELECT id			AS outbox_id,
+				   aggregate_type	AS outbox_aggregate_type,
+				   aggregate_id		AS outbox_aggregate_id,
+				   event_type		AS outbox_event_type,
+				   payload			AS outbox_payload,
+				   created_at		AS outbox_created_at,
+				   attempts			AS outbox_attempts,
+				   available_at		AS outbox_available_at,
+				   user_id			AS outbox_user_id
+			FROM outbox_event
+			WHERE id > $1 OR id = ANY($2)
+			ORDER BY id
+			LIMIT $3;`
+}
+
+func (changeFeedSelectSqlManager) UserEvents() string {
+	return `SELECT id			AS outbox_id,
+				   aggregate_type	AS outbox_aggregate_type,
+				   aggregate_id		AS outbox_aggregate_id,
+				   event_type		AS outbox_event_type,
+				   payload			AS outbox_payload,
+				   created_at		AS outbox_created_at,
+				   attempts			AS outbox_attempts,
+				   available_at		AS outbox_available_at,
+				   user_id			AS outbox_user_id
+			FROM outbox_event
+			WHERE user_id = $1 AND id > $2
+			ORDER BY id
+			LIMIT $3;`
+}
@@ -1,21 +1,70 @@
 package query
 
+import (
+	"fmt"
+	"todo/src/core/domain"
+)
+
+var collectionSortColumns = map[string]string{
+	"id":         "id",
+	"name":       "name",
+	"created_at": "created_at",
+}
+
 type collectionSqlManager struct{}
 
 func Collection() *collectionSqlManager {
 	return &collectionSqlManager{}
 }
 
+// Insert writes the collection along with its event in the outbox, returning its ID
 func (collectionSqlManager) Insert() string {
-	return "INSERT INTO  collection (id, name, user_id)  VALUES (DEFAULT, $1, $2) RETURNING id;"
+	return `WITH created AS (
+				INSERT INTO collection (id, name, user_id) VALUES (DEFAULT, $1, $2) RETURNING *
+			)
+			` + insertOutboxEvent + `
+			SELECT 'collection', id, 'collection.created', jsonb_build_object('collection', to_jsonb(created)),
+				user_id
+			FROM created
+			RETURNING aggregate_id;`
 }
 
+// Update writes the event of the change in the outbox
 func (collectionSqlManager) Update() string {
-	return "UPDATE collection SET name = $1 WHERE id = $2 AND user_id = $3;"
+	return `WITH updated AS (
+				UPDATE collection SET name = $1, version = version + 1
+				WHERE id = $2 AND user_id = $3 AND ($4::int = 0 OR version = $4) RETURNING *
+			)
+			` + insertOutboxEvent + `
+			SELECT 'collection', id, 'collection.updated', jsonb_build_object('collection', to_jsonb(updated)),
+				user_id
+			FROM updated;`
 }
 
+// Archive writes the event of the change in the outbox, leaving the archived collection as it is
+func (collectionSqlManager) Archive() string {
+	return `WITH archived AS (
+				UPDATE collection SET archived_at = CURRENT_TIMESTAMP, version = version + 1
+				WHERE id = $1 AND user_id = $2 AND archived_at IS NULL RETURNING *
+			)
+			` + insertOutboxEvent + `
+			SELECT 'collection', id, 'collection.updated', jsonb_build_object('collection', to_jsonb(archived)),
+				user_id
+			FROM archived;`
+}
+
+// Delete writes the event of the deletion in the outbox
 func (collectionSqlManager) Delete() string {
-	return "DELETE FROM collection WHERE id = $1 AND user_id = $2;"
+	return `WITH deleted AS (
+				DELETE FROM collection WHERE id = $1 AND user_id = $2 AND ($3::int = 0 OR version = $3)
+				RETURNING id, user_id
+			)
+			` + insertOutboxEvent + `
+			SELECT 'collection', id, 'collection.deleted', '{}'::jsonb, user_id FROM deleted;`
+}
+
+func (collectionSqlManager) Exists() string {
+	return "SELECT EXISTS (SELECT 1 FROM collection WHERE id = $1 AND user_id = $2);"
 }
 
 type collectionSelectSqlManager struct{}
@@ -24,8 +73,108 @@ func (collectionSqlManager) Select() *collectionSelectSqlManager {
 	return &collectionSelectSqlManager{}
 }
 
-func (collectionSelectSqlManager) All() string {
-	return `SELECT id   AS collection_id,
-				   name AS collection_name
-			FROM collection WHERE user_id = $1;`
+func (collectionSelectSqlManager) All(userId int, filter domain.CollectionFilter,
+	pagination domain.Pagination) (string, []interface{}) {
+	conditions := collectionConditions(userId, filter)
+	sortColumn, ok := collectionSortColumns[pagination.SortField()]
+	if !ok {
+		sortColumn = collectionSortColumns["id"]
+	}
+	direction := sortDirection(pagination.IsDescending())
+
+	sql := fmt.Sprintf(`SELECT id   		AS collection_id,
+				   name 		AS collection_name,
+				   created_at	AS collection_created_at,
+				   version		AS collection_version,
+				   archived_at	AS collection_archived_at
+			FROM collection
+			WHERE %s
+			ORDER BY %s %s, id %s
+			LIMIT %s OFFSET %s;`,
+		conditions.where(), sortColumn, direction, direction,
+		conditions.parameter(pagination.Limit()), conditions.parameter(pagination.Offset()))
+
+	return sql, conditions.arguments()
+}
+
+func (collectionSelectSqlManager) ById() string {
+	return `SELECT id   		AS collection_id,
+				   name 		AS collection_name,
+				   created_at	AS collection_created_at,
+				   version		AS collection_version,
+				   archived_at	AS collection_archived_at
+			FROM collection
+			WHERE id = $1 AND user_id = $2;`
+}
+
+func (collectionSelectSqlManager) ByIds() string {
+	return `SELECT id   		AS collection_id,
+				   name 		AS collection_name,
+				   created_at	AS collection_created_at,
+				   version		AS collection_version,
+				   archived_at	AS collection_archived_at
+			FROM collection
+			WHERE id = ANY($1) AND user_id = $2
+			ORDER BY id;`
+}
+
+func (collectionSelectSqlManager) Tasks() string {
+	return `SELECT t.id				AS task_id,
+				   t.description	AS task_description,
+				   t.finished		AS task_finished,
+				   t.created_at		AS task_created_at,
+				   t.version		AS task_version,
+				   t.due_date		AS task_due_date,
+				   t.tags			AS task_tags,
+				   c.id				AS collection_id,
+				   c.name			AS collection_name
+			FROM task t
+			INNER JOIN collection c ON t.collection_id = c.id
+			WHERE c.id = $1 AND t.user_id = $2
+			ORDER BY t.id;`
+}
+
+func (collectionSelectSqlManager) TasksOfCollections() string {
+	return `SELECT t.id				AS task_id,
+				   t.description	AS task_description,
+				   t.finished		AS task_finished,
+				   t.created_at		AS task_created_at,
+				   t.version		AS task_version,
+				   t.due_date		AS task_due_date,
+				   t.tags			AS task_tags,
+				   c.id				AS collection_id,
+				   c.name			AS collection_name
+			FROM task t
+			INNER JOIN collection c ON t.collection_id = c.id
+			WHERE c.id = ANY($1) AND t.user_id = $2
+			ORDER BY t.id;`
+}
+
+func (collectionSelectSqlManager) Tags() string {
+	return `SELECT DISTINCT unnest(tags) AS tag
+			FROM task
+			WHERE collection_id = $1 AND user_id = $2
+			ORDER BY tag;`
+}
+
+func (collectionSelectSqlManager) Count(userId int, filter domain.CollectionFilter) (string, []interface{}) {
+	conditions := collectionConditions(userId, filter)
+
+	return fmt.Sprintf("SELECT COUNT(*) FROM collection WHERE %s;", conditions.where()), conditions.arguments()
+}
+
+func collectionConditions(userId int, filter domain.CollectionFilter) *conditionsBuilder {
+	conditions := newConditionsBuilder()
+	conditions.add("user_id = ?", userId)
+	if filter.Text() != "" {
+		conditions.add("name ILIKE '%' || ? || '%'", escapeLikePattern(filter.Text()))
+	}
+	if filter.CreatedFrom() != nil {
+		conditions.add("created_at >= ?", *filter.CreatedFrom())
+	}
+	if filter.CreatedTo() != nil {
+		conditions.add("created_at <= ?", *filter.CreatedTo())
+	}
+
+	return conditions
 }
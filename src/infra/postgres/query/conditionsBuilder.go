@@ -0,0 +1,48 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+type conditionsBuilder struct {
+	clauses []string
+	args    []interface{}
+}
+
+func newConditionsBuilder() *conditionsBuilder {
+	return &conditionsBuilder{}
+}
+
+// add appends a clause where each "?" is replaced by the positional parameter of the matching argument
+func (b *conditionsBuilder) add(clause string, args ...interface{}) {
+	for _, arg := range args {
+		b.args = append(b.args, arg)
+		clause = strings.Replace(clause, "?", fmt.Sprintf("$%d", len(b.args)), 1)
+	}
+	b.clauses = append(b.clauses, clause)
+}
+
+func (b *conditionsBuilder) parameter(arg interface{}) string {
+	b.args = append(b.args, arg)
+	return fmt.Sprintf("$%d", len(b.args))
+}
+
+func (b *conditionsBuilder) where() string {
+	return strings.Join(b.clauses, " AND ")
+}
+
+func (b *conditionsBuilder) arguments() []interface{} {
+	return b.args
+}
+
+func escapeLikePattern(text string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(text)
+}
+
+func sortDirection(descending bool) string {
+	if descending {
+		return "DESC"
+	}
+	return "ASC"
+}
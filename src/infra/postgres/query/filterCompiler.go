@@ -0,0 +1,71 @@
+package query
+
+import (
+	"fmt"
+	"todo/src/core/filterexpr"
+)
+
+var taskFilterColumns = map[filterexpr.Field]string{
+	filterexpr.FieldFinished: "t.finished",
+	filterexpr.FieldDue:      "t.due_date",
+	filterexpr.FieldCreated:  "t.created_at",
+}
+
+// sqlOperators are the SQL operators written for the ones of the expression, the only operators ever written in the SQL
+var sqlOperators = map[filterexpr.Operator]string{
+	filterexpr.OperatorEqual:          "=",
+	filterexpr.OperatorNotEqual:       "<>",
+	filterexpr.OperatorLess:           "<",
+	filterexpr.OperatorLessOrEqual:    "<=",
+	filterexpr.OperatorGreater:        ">",
+	filterexpr.OperatorGreaterOrEqual: ">=",
+}
+
+// compileTaskExpression translates a parsed filter expression into a SQL condition over the task (t) and
+// collection (c) tables. Every value is sent as a parameter, only columns and operators are written in the SQL.
+func compileTaskExpression(expression filterexpr.Expression, conditions *conditionsBuilder) string {
+	switch node := expression.(type) {
+	case filterexpr.And:
+		return fmt.Sprintf("(%s AND %s)", compileTaskExpression(node.Left, conditions),
+			compileTaskExpression(node.Right, conditions))
+	case filterexpr.Or:
+		return fmt.Sprintf("(%s OR %s)", compileTaskExpression(node.Left, conditions),
+			compileTaskExpression(node.Right, conditions))
+	case filterexpr.Not:
+		return fmt.Sprintf("(NOT %s)", compileTaskExpression(node.Operand, conditions))
+	case filterexpr.Comparison:
+		return compileTaskComparison(node, conditions)
+	default:
+		return "TRUE"
+	}
+}
+
+func compileTaskComparison(comparison filterexpr.Comparison, conditions *conditionsBuilder) string {
+	operator, found := sqlOperators[comparison.Operator]
+	if !found {
+		return "FALSE"
+	}
+	negation := ""
+	if comparison.Operator == filterexpr.OperatorNotEqual {
+		negation = "NOT "
+	}
+
+	switch comparison.Field {
+	case filterexpr.FieldText:
+		value := escapeLikePattern(fmt.Sprint(comparison.Value))
+		return fmt.Sprintf("t.description %sILIKE '%%' || %s || '%%'", negation, conditions.parameter(value))
+	case filterexpr.FieldTag:
+		return fmt.Sprintf("%s(%s = ANY(t.tags))", negation, conditions.parameter(comparison.Value))
+	case filterexpr.FieldCollection:
+		if id, isId := comparison.Value.(int); isId {
+			return fmt.Sprintf("c.id %s %s", operator, conditions.parameter(id))
+		}
+		return fmt.Sprintf("LOWER(c.name) %s LOWER(%s)", operator, conditions.parameter(comparison.Value))
+	default:
+		column := taskFilterColumns[comparison.Field]
+		if comparison.Value == nil {
+			return fmt.Sprintf("%s IS %sNULL", column, negation)
+		}
+		return fmt.Sprintf("%s %s %s", column, operator, conditions.parameter(comparison.Value))
+	}
+}
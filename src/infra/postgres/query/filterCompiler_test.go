@@ -0,0 +1,57 @@
+package query
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+	"todo/src/core/filterexpr"
+)
+
+func TestCompileTaskExpression(t *testing.T) {
+	t.Run("should compile the expression keeping every value as a parameter", func(t *testing.T) {
+		now := time.Date(2024, 1, 10, 15, 30, 0, 0, time.UTC)
+		expression, _ := filterexpr.Parse(`(tag:work or tag:urgent) and not finished and due < +7d and "50%"`, now)
+		conditions := newConditionsBuilder()
+		conditions.add("t.user_id = ?", 1)
+
+		sql := compileTaskExpression(expression, conditions)
+
+		dueLimit := now.Add(7 * 24 * time.Hour)
+		expectedSql := "((((($2 = ANY(t.tags)) OR ($3 = ANY(t.tags))) AND (NOT t.finished = $4)) AND t.due_date < $5) " +
+			"AND t.description ILIKE '%' || $6 || '%')"
+
+		assert.Equal(t, expectedSql, sql)
+		assert.Equal(t, []interface{}{1, "work", "urgent", true, &dueLimit, `50\%`}, conditions.arguments())
+	})
+
+	t.Run("should compile empty dates and negations", func(t *testing.T) {
+		expression, _ := filterexpr.Parse("due!=none and tag!=home and collection:Work", time.Now())
+		conditions := newConditionsBuilder()
+
+		sql := compileTaskExpression(expression, conditions)
+
+		assert.Equal(t, "((t.due_date IS NOT NULL AND NOT ($1 = ANY(t.tags))) AND LOWER(c.name) = LOWER($2))", sql)
+		assert.Equal(t, []interface{}{"home", "Work"}, conditions.arguments())
+	})
+
+	t.Run("should never write an operator the language does not have", func(t *testing.T) {
+		_, err := filterexpr.Parse("due==today", time.Now())
+		conditions := newConditionsBuilder()
+
+		sql := compileTaskExpression(filterexpr.Comparison{Field: filterexpr.FieldDue, Operator: "==",
+			Value: time.Now()}, conditions)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, "FALSE", sql)
+		assert.Empty(t, conditions.arguments())
+	})
+
+	t.Run("should compile the inequality of the collection IDs", func(t *testing.T) {
+		expression, _ := filterexpr.Parse("collection!=3", time.Now())
+		conditions := newConditionsBuilder()
+
+		sql := compileTaskExpression(expression, conditions)
+
+		assert.Equal(t, "c.id <> $1", sql)
+	})
+}
@@ -0,0 +1,50 @@
+package query
+
+type idempotencySqlManager struct{}
+
+func Idempotency() *idempotencySqlManager {
+	return &idempotencySqlManager{}
+}
+
+// Reserve only takes over an existing key when it has expired, returning no rows otherwise
+func (idempotencySqlManager) Reserve() string {
+	return `INSERT INTO idempotency_key (user_id, idempotency_key, fingerprint)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (user_id, idempotency_key) DO UPDATE
+				SET fingerprint = EXCLUDED.fingerprint, status = NULL, content_type = NULL, headers = NULL,
+					body = NULL,
+					completed = FALSE, created_at = CURRENT_TIMESTAMP
+				WHERE idempotency_key.created_at < $4
+			RETURNING TRUE;`
+}
+
+func (idempotencySqlManager) DeleteExpired() string {
+	return "DELETE FROM idempotency_key WHERE user_id = $1 AND created_at < $2;"
+}
+
+func (idempotencySqlManager) Complete() string {
+	return `UPDATE idempotency_key SET status = $1, content_type = $2, headers = $3, body = $4, completed = TRUE
+			WHERE user_id = $5 AND idempotency_key = $6;`
+}
+
+func (idempotencySqlManager) Release() string {
+	return "DELETE FROM idempotency_key WHERE user_id = $1 AND idempotency_key = $2 AND completed = FALSE;"
+}
+
+type idempotencySelectSqlManager struct{}
+
+func (idempotencySqlManager) Select() *idempotencySelectSqlManager {
+	return &idempotencySelectSqlManager{}
+}
+
+func (idempotencySelectSqlManager) ByKey() string {
+	return `SELECT idempotency_key					AS idempotency_key,
+				   fingerprint						AS idempotency_fingerprint,
+				   COALESCE(status, 0)				AS idempotency_status,
+				   COALESCE(content_type, '')		AS idempotency_content_type,
+				   COALESCE(headers, '{}')			AS idempotency_headers,
+				   COALESCE(body, '')				AS idempotency_body,
+				   completed						AS idempotency_completed
+			FROM idempotency_key
+			WHERE idempotency_key = $1 AND user_id = $2;`
+}
@@ -0,0 +1,174 @@
+package query
+
+import (
+	"fmt"
+	"todo/src/core/domain"
+)
+
+var jobSortColumns = map[string]string{
+	"id":         "id",
+	"run_at":     "run_at",
+	"created_at": "created_at",
+}
+
+const jobColumns = `id				AS job_id,
+				   kind			AS job_kind,
+				   payload		AS job_payload,
+				   status		AS job_status,
+				   attempts		AS job_attempts,
+				   max_attempts	AS job_max_attempts,
+				   last_error	AS job_last_error,
+				   run_at		AS job_run_at,
+				   COALESCE(schedule, '')	AS job_schedule,
+				   COALESCE(user_id, 0)	AS job_user_id,
+				   COALESCE(locked_by, '')	AS job_locked_by,
+				   locked_until	AS job_locked_until,
+				   started_at	AS job_started_at,
+				   finished_at	AS job_finished_at,
+				   created_at	AS job_created_at`
+
+type jobSqlManager struct{}
+
+func Job() *jobSqlManager {
+	return &jobSqlManager{}
+}
+
+func (jobSqlManager) Insert() string {
+	return `INSERT INTO job (kind, payload, max_attempts, run_at, user_id)
+			VALUES ($1, $2, $3, $4, NULLIF($5, 0))
+			RETURNING id;`
+}
+
+// FailExpired gives up on the running jobs whose lease has expired after their last attempt, as the instances
+// running them have stopped while they were running
+func (jobSqlManager) FailExpired() string {
+	return `UPDATE job SET status = 'failed', last_error = 'the lease of the job expired while it was running',
+				locked_by = NULL, locked_until = NULL, finished_at = $1
+			WHERE status = 'running' AND locked_until <= $1 AND attempts >= max_attempts;`
+}
+
+// Claim locks the first jobs due skipping the ones locked by the other instances, so that each job is claimed by a
+// single instance, and takes over the running ones whose lease has expired
+func (jobSqlManager) Claim() string {
+	return fmt.Sprintf(`WITH claimable AS (
+				SELECT id AS claimed_id
+				FROM job
+				WHERE kind = ANY($2)
+				  AND ((status = 'queued' AND run_at <= $4) OR (status = 'running' AND locked_until <= $4))
+				ORDER BY run_at, id
+				LIMIT $3
+				FOR UPDATE SKIP LOCKED
+			)
+			UPDATE job SET status = 'running', attempts = attempts + 1, locked_by = $1, locked_until = $5,
+				started_at = $4
+			FROM claimable
+			WHERE job.id = claimable.claimed_id
+			RETURNING %s;`, jobColumns)
+}
+
+func (jobSqlManager) Complete() string {
+	return `UPDATE job SET status = 'succeeded', locked_by = NULL, locked_until = NULL, finished_at = CURRENT_TIMESTAMP
+			WHERE id = $1 AND locked_by = $2 AND status = 'running';`
+}
+
+// Fail queues the job again at its retry time, or marks it as failed when it has no attempts left
+func (jobSqlManager) Fail() string {
+	return `UPDATE job SET last_error = $3, locked_by = NULL, locked_until = NULL,
+				status = CASE WHEN $5 THEN 'failed' ELSE 'queued' END,
+				run_at = CASE WHEN $5 THEN run_at ELSE $4 END,
+				finished_at = CASE WHEN $5 THEN CURRENT_TIMESTAMP END
+			WHERE id = $1 AND locked_by = $2 AND status = 'running';`
+}
+
+func (jobSqlManager) Retry() string {
+	return `UPDATE job SET status = 'queued', attempts = 0, last_error = '', run_at = CURRENT_TIMESTAMP,
+				started_at = NULL, finished_at = NULL
+			WHERE id = $1 AND status = 'failed';`
+}
+
+func (jobSqlManager) DeleteFinished() string {
+	return `DELETE FROM job
+			WHERE (status = 'succeeded' AND finished_at < $1) OR (status = 'failed' AND finished_at < $2);`
+}
+
+// UpsertSchedule keeps the next run of the schedule saved before unless its expression has changed
+func (jobSqlManager) UpsertSchedule() string {
+	return `INSERT INTO job_schedule (name, kind, spec, payload, next_run_at)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (name) DO UPDATE SET kind = EXCLUDED.kind, spec = EXCLUDED.spec, payload = EXCLUDED.payload,
+				next_run_at = CASE WHEN job_schedule.spec = EXCLUDED.spec THEN job_schedule.next_run_at
+					ELSE EXCLUDED.next_run_at END;`
+}
+
+// InsertScheduled queues nothing when the schedule has a job queued or running already
+func (jobSqlManager) InsertScheduled() string {
+	return `INSERT INTO job (kind, payload, run_at, schedule)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (schedule) WHERE status IN ('queued', 'running') DO NOTHING;`
+}
+
+func (jobSqlManager) MoveSchedule() string {
+	return "UPDATE job_schedule SET next_run_at = $2 WHERE name = $1;"
+}
+
+type jobSelectSqlManager struct{}
+
+func (jobSqlManager) Select() *jobSelectSqlManager {
+	return &jobSelectSqlManager{}
+}
+
+func (jobSelectSqlManager) ById() string {
+	return fmt.Sprintf(`SELECT %s
+			FROM job
+			WHERE id = $1;`, jobColumns)
+}
+
+func (jobSelectSqlManager) ByIdOfUser() string {
+	return fmt.Sprintf(`SELECT %s
+			FROM job
+			WHERE id = $1 AND user_id = $2;`, jobColumns)
+}
+
+func (jobSelectSqlManager) Count() string {
+	return `SELECT COUNT(*)
+			FROM job
+			WHERE $1 = '' OR status = $1;`
+}
+
+func (jobSelectSqlManager) All(pagination domain.Pagination) string {
+	sortColumn, ok := jobSortColumns[pagination.SortField()]
+	if !ok {
+		sortColumn = jobSortColumns["id"]
+	}
+	direction := sortDirection(pagination.IsDescending())
+
+	return fmt.Sprintf(`SELECT %s
+			FROM job
+			WHERE $1 = '' OR status = $1
+			ORDER BY %s %s, id %s
+			LIMIT $2 OFFSET $3;`, jobColumns, sortColumn, direction, direction)
+}
+
+func (jobSelectSqlManager) Schedules() string {
+	return `SELECT name			AS schedule_name,
+				   kind			AS schedule_kind,
+				   spec			AS schedule_spec,
+				   payload		AS schedule_payload,
+				   next_run_at	AS schedule_next_run_at
+			FROM job_schedule
+			ORDER BY name;`
+}
+
+// DueSchedules locks the schedules due skipping the ones locked by the other instances, so that each run of a
+// schedule is queued by a single instance
+func (jobSelectSqlManager) DueSchedules() string {
+	return `SELECT name			AS schedule_name,
+				   kind			AS schedule_kind,
+				   spec			AS schedule_spec,
+				   payload		AS schedule_payload,
+				   next_run_at	AS schedule_next_run_at
+			FROM job_schedule
+			WHERE next_run_at <= $1
+			ORDER BY name
+			FOR UPDATE SKIP LOCKED;`
+}
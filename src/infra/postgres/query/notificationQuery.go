@@ -0,0 +1,107 @@
+package query
+
+type notificationSqlManager struct{}
+
+func Notification() *notificationSqlManager {
+	return &notificationSqlManager{}
+}
+
+// InsertReminder writes the reminder only when the task belongs to the account, returning no rows otherwise
+func (notificationSqlManager) InsertReminder() string {
+	return `INSERT INTO reminder (remind_at, minutes_before_due, task_id, user_id)
+			SELECT $1, $2, id, user_id FROM task WHERE id = $3 AND user_id = $4
+			RETURNING id;`
+}
+
+func (notificationSqlManager) DeleteReminder() string {
+	return "DELETE FROM reminder WHERE id = $1 AND task_id = $2 AND user_id = $3;"
+}
+
+func (notificationSqlManager) UpsertPreferences() string {
+	return `INSERT INTO notification_preference (email, scheduled_reminders, due_date_reminders, user_id)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (user_id) DO UPDATE SET email = EXCLUDED.email,
+				scheduled_reminders = EXCLUDED.scheduled_reminders, due_date_reminders = EXCLUDED.due_date_reminders;`
+}
+
+func (notificationSqlManager) MarkReminder() string {
+	return "UPDATE reminder SET status = $2, processed_at = CURRENT_TIMESTAMP, locked_until = NULL WHERE id = $1;"
+}
+
+// RecordReminderFailure gives up on the reminder when it has no attempts left
+func (notificationSqlManager) RecordReminderFailure() string {
+	return `UPDATE reminder SET attempts = attempts + 1, last_error = $2, locked_until = NULL,
+				status = CASE WHEN $3 THEN 'failed' ELSE status END,
+				processed_at = CASE WHEN $3 THEN CURRENT_TIMESTAMP END
+			WHERE id = $1;`
+}
+
+// Lease keeps the reminders claimed from the other instances until the lease expires or their outcome is written
+func (notificationSqlManager) Lease() string {
+	return "UPDATE reminder SET locked_until = $2 WHERE id = ANY($1);"
+}
+
+type notificationSelectSqlManager struct{}
+
+func (notificationSqlManager) Select() *notificationSelectSqlManager {
+	return &notificationSelectSqlManager{}
+}
+
+func (notificationSelectSqlManager) Reminders() string {
+	return `SELECT r.id					AS reminder_id,
+				   r.task_id			AS reminder_task_id,
+				   r.remind_at			AS reminder_remind_at,
+				   r.minutes_before_due	AS reminder_minutes_before_due,
+				   r.status				AS reminder_status,
+				   r.attempts			AS reminder_attempts,
+				   r.last_error			AS reminder_last_error,
+				   r.processed_at		AS reminder_processed_at,
+				   r.created_at			AS reminder_created_at
+			FROM reminder r
+			WHERE r.task_id = $1 AND r.user_id = $2
+			ORDER BY r.id;`
+}
+
+// Preferences reads the defaults of the accounts that have never changed their preferences
+func (notificationSelectSqlManager) Preferences() string {
+	return `SELECT COALESCE(p.email, TRUE)				AS preference_email,
+				   COALESCE(p.scheduled_reminders, TRUE)	AS preference_scheduled_reminders,
+				   COALESCE(p.due_date_reminders, TRUE)	AS preference_due_date_reminders
+			FROM user_account a
+			LEFT JOIN notification_preference p ON p.user_id = a.id
+			WHERE a.id = $1;`
+}
+
+// Due reads the pending reminders whose time has come, the ones before the due date following its changes, and
+// locks them skipping the ones locked or leased by other instances, so that each reminder is claimed by a single
+// instance
+func (notificationSelectSqlManager) Due() string {
+	return `SELECT r.id					AS reminder_id,
+				   r.task_id			AS reminder_task_id,
+				   r.remind_at			AS reminder_remind_at,
+				   r.minutes_before_due	AS reminder_minutes_before_due,
+				   r.status				AS reminder_status,
+				   r.attempts			AS reminder_attempts,
+				   r.last_error			AS reminder_last_error,
+				   r.processed_at		AS reminder_processed_at,
+				   r.created_at			AS reminder_created_at,
+				   t.description		AS task_description,
+				   t.finished			AS task_finished,
+				   t.due_date			AS task_due_date,
+				   a.id					AS account_id,
+				   a.name				AS account_name,
+				   a.email				AS account_email,
+				   COALESCE(p.email, TRUE)				AS preference_email,
+				   COALESCE(p.scheduled_reminders, TRUE)	AS preference_scheduled_reminders,
+				   COALESCE(p.due_date_reminders, TRUE)	AS preference_due_date_reminders
+			FROM reminder r
+			JOIN task t ON r.task_id = t.id
+			JOIN user_account a ON r.user_id = a.id
+			LEFT JOIN notification_preference p ON p.user_id = r.user_id
+			WHERE r.status = 'pending'
+			  AND (r.locked_until IS NULL OR r.locked_until <= $1)
+			  AND COALESCE(r.remind_at, t.due_date - make_interval(mins => r.minutes_before_due)) <= $1
+			ORDER BY r.id
+			LIMIT $2
+			FOR UPDATE OF r SKIP LOCKED;`
+}
@@ -0,0 +1,62 @@
+package query
+
+// insertOutboxEvent is the main statement of the ones changing the tasks and collections, writing their event from
+// the changed rows of the preceding CTE, so that both are committed together
+const insertOutboxEvent = `INSERT INTO outbox_event (aggregate_type, aggregate_id, event_type, payload, user_id)`
+
+type outboxSqlManager struct{}
+
+func Outbox() *outboxSqlManager {
+	return &outboxSqlManager{}
+}
+
+// Lock takes the lock of the relay until the end of the transaction, returning false when another instance holds it
+func (outboxSqlManager) Lock() string {
+	return "SELECT pg_try_advisory_xact_lock($1);"
+}
+
+func (outboxSqlManager) MarkProcessed() string {
+	return "UPDATE outbox_event SET processed_at = CURRENT_TIMESTAMP WHERE id = $1;"
+}
+
+// RecordFailure sets the event aside for good when it has no attempts left
+func (outboxSqlManager) RecordFailure() string {
+	return `UPDATE outbox_event SET attempts = attempts + 1, last_error = $2, available_at = $3,
+				failed_at = CASE WHEN $4 THEN CURRENT_TIMESTAMP END
+			WHERE id = $1;`
+}
+
+func (outboxSqlManager) DeleteProcessed() string {
+	return "DELETE FROM outbox_event WHERE processed_at < $1;"
+}
+
+type outboxSelectSqlManager struct{}
+
+func (outboxSqlManager) Select() *outboxSelectSqlManager {
+	return &outboxSelectSqlManager{}
+}
+
+// Pending reads the events neither processed nor failed in the order they have been written, leaving out the ones
+// waiting for a retry along with the later events of their aggregate, which are held back behind them
+func (outboxSelectSqlManager) Pending() string {
+	return `SELECT e.id				AS outbox_id,
+				   e.aggregate_type	AS outbox_aggregate_type,
+				   e.aggregate_id	AS outbox_aggregate_id,
+				   e.event_type		AS outbox_event_type,
+				   e.payload		AS outbox_payload,
+				   e.created_at		AS outbox_created_at,
+				   e.attempts		AS outbox_attempts,
+				   e.available_at	AS outbox_available_at,
+				   e.user_id		AS outbox_user_id
+			FROM outbox_event e
+			WHERE e.processed_at IS NULL AND e.failed_at IS NULL
+			  AND NOT EXISTS (
+				SELECT 1
+				FROM outbox_event waiting
+				WHERE waiting.aggregate_type = e.aggregate_type AND waiting.aggregate_id = e.aggregate_id
+				  AND waiting.id <= e.id AND waiting.processed_at IS NULL AND waiting.failed_at IS NULL
+				  AND waiting.available_at > CURRENT_TIMESTAMP
+			  )
+			ORDER BY e.id
+			LIMIT $1;`
+}
@@ -1,21 +1,63 @@
 package query
 
+import (
+	"fmt"
+	"todo/src/core/domain"
+)
+
+var taskSortColumns = map[string]string{
+	"id":              "t.id",
+	"description":     "t.description",
+	"finished":        "t.finished",
+	"collection_id":   "c.id",
+	"collection_name": "c.name",
+	"created_at":      "t.created_at",
+	"due_date":        "t.due_date",
+}
+
 type taskSqlManager struct{}
 
 func Task() *taskSqlManager {
 	return &taskSqlManager{}
 }
 
+// Insert writes the task along with its event in the outbox, returning its ID
 func (taskSqlManager) Insert() string {
-	return "INSERT INTO task (id, description, finished, collection_id, user_id) VALUES (DEFAULT, $1, $2, $3, $4) RETURNING id;"
+	return `WITH created AS (
+				INSERT INTO task (id, description, finished, due_date, tags, collection_id, user_id)
+				VALUES (DEFAULT, $1, $2, $3, $4, $5, $6) RETURNING *
+			)
+			` + insertOutboxEvent + `
+			SELECT 'task', id, 'task.created', jsonb_build_object('task', to_jsonb(created)), user_id FROM created
+			RETURNING aggregate_id;`
 }
 
+// Update writes the event of the change in the outbox, telling whether it has finished the task
 func (taskSqlManager) Update() string {
-	return "UPDATE task SET description = $1, finished = $2, collection_id = $3 WHERE id = $4 AND user_id = $5;"
+	return `WITH updated AS (
+				UPDATE task t SET description = $1, finished = $2, due_date = $3, tags = $4, collection_id = $5,
+					version = t.version + 1
+				FROM task previous
+				WHERE previous.id = t.id AND t.id = $6 AND t.user_id = $7 AND ($8::int = 0 OR t.version = $8)
+				RETURNING t.*, previous.finished AS previously_finished
+			)
+			` + insertOutboxEvent + `
+			SELECT 'task', id, 'task.updated', jsonb_build_object('task', to_jsonb(updated) - 'previously_finished',
+				'finishes_task', finished AND NOT previously_finished), user_id
+			FROM updated;`
 }
 
+// Delete writes the event of the deletion in the outbox
 func (taskSqlManager) Delete() string {
-	return "DELETE FROM task WHERE id = $1 AND user_id = $2;"
+	return `WITH deleted AS (
+				DELETE FROM task WHERE id = $1 AND user_id = $2 AND ($3::int = 0 OR version = $3) RETURNING id, user_id
+			)
+			` + insertOutboxEvent + `
+			SELECT 'task', id, 'task.deleted', '{}'::jsonb, user_id FROM deleted;`
+}
+
+func (taskSqlManager) Exists() string {
+	return "SELECT EXISTS (SELECT 1 FROM task WHERE id = $1 AND user_id = $2);"
 }
 
 type taskSelectSqlManager struct{}
@@ -24,35 +66,124 @@ func (taskSqlManager) Select() *taskSelectSqlManager {
 	return &taskSelectSqlManager{}
 }
 
-func (taskSelectSqlManager) All() string {
-	return `SELECT t.id				AS task_id,
+func (taskSelectSqlManager) All(userId int, filter domain.TaskFilter,
+	pagination domain.Pagination) (string, []interface{}) {
+	conditions := taskConditions(userId, filter)
+	sortColumn, ok := taskSortColumns[pagination.SortField()]
+	if !ok {
+		sortColumn = taskSortColumns["id"]
+	}
+	direction := sortDirection(pagination.IsDescending())
+
+	sql := fmt.Sprintf(`SELECT t.id				AS task_id,
 				   t.description	AS task_description,
 				   t.finished		AS task_finished,
+				   t.created_at		AS task_created_at,
+				   t.version		AS task_version,
+				   t.due_date		AS task_due_date,
+				   t.tags			AS task_tags,
 				   c.id				AS collection_id,
 				   c.name			AS collection_name
 			FROM task t
 			INNER JOIN collection c ON t.collection_id= c.id
-			WHERE t.user_id = $1;`
+			WHERE %s
+			ORDER BY %s %s, t.id %s
+			LIMIT %s OFFSET %s;`,
+		conditions.where(), sortColumn, direction, direction,
+		conditions.parameter(pagination.Limit()), conditions.parameter(pagination.Offset()))
+
+	return sql, conditions.arguments()
 }
 
-func (taskSelectSqlManager) ById() string {
-	return `SELECT t.id				AS task_id,
+func (taskSelectSqlManager) Count(userId int, filter domain.TaskFilter) (string, []interface{}) {
+	conditions := taskConditions(userId, filter)
+
+	sql := fmt.Sprintf(`SELECT COUNT(*)
+			FROM task t
+			INNER JOIN collection c ON t.collection_id= c.id
+			WHERE %s;`, conditions.where())
+
+	return sql, conditions.arguments()
+}
+
+// Page reads one more task than the limit so that the caller knows whether another page exists. When the pagination
+// is backward the tasks are returned in the inverse of the requested order.
+func (taskSelectSqlManager) Page(userId int, filter domain.TaskFilter,
+	pagination domain.KeysetPagination) (string, []interface{}) {
+	conditions := taskConditions(userId, filter)
+	descending := pagination.IsDescending() != pagination.IsBackward()
+	if cursor := pagination.Cursor(); cursor != nil {
+		comparison := ">"
+		if descending {
+			comparison = "<"
+		}
+		if pagination.SortField() == "created_at" {
+			conditions.add(fmt.Sprintf("(t.created_at, t.id) %s (?, ?)", comparison), cursor.CreatedAt(), cursor.Id())
+		} else {
+			conditions.add(fmt.Sprintf("t.id %s ?", comparison), cursor.Id())
+		}
+	}
+	sortColumn := "t.id"
+	if pagination.SortField() == "created_at" {
+		sortColumn = "t.created_at"
+	}
+	direction := sortDirection(descending)
+
+	sql := fmt.Sprintf(`SELECT t.id				AS task_id,
 				   t.description	AS task_description,
 				   t.finished		AS task_finished,
+				   t.created_at		AS task_created_at,
+				   t.version		AS task_version,
+				   t.due_date		AS task_due_date,
+				   t.tags			AS task_tags,
 				   c.id				AS collection_id,
 				   c.name			AS collection_name
 			FROM task t
 			INNER JOIN collection c ON t.collection_id= c.id
-			WHERE t.id = $1 AND t.user_id = $2;`
+			WHERE %s
+			ORDER BY %s %s, t.id %s
+			LIMIT %s;`,
+		conditions.where(), sortColumn, direction, direction, conditions.parameter(pagination.Limit()+1))
+
+	return sql, conditions.arguments()
 }
 
-func (taskSelectSqlManager) ByCollection() string {
+func (taskSelectSqlManager) ById() string {
 	return `SELECT t.id				AS task_id,
 				   t.description	AS task_description,
 				   t.finished		AS task_finished,
+				   t.created_at		AS task_created_at,
+				   t.version		AS task_version,
+				   t.due_date		AS task_due_date,
+				   t.tags			AS task_tags,
 				   c.id				AS collection_id,
 				   c.name			AS collection_name
 			FROM task t
 			INNER JOIN collection c ON t.collection_id= c.id
-			WHERE c.id = $1 AND t.user_id = $2;`
+			WHERE t.id = $1 AND t.user_id = $2;`
+}
+
+func taskConditions(userId int, filter domain.TaskFilter) *conditionsBuilder {
+	conditions := newConditionsBuilder()
+	conditions.add("t.user_id = ?", userId)
+	if filter.Finished() != nil {
+		conditions.add("t.finished = ?", *filter.Finished())
+	}
+	if filter.CollectionId() != 0 {
+		conditions.add("c.id = ?", filter.CollectionId())
+	}
+	if filter.Text() != "" {
+		conditions.add("t.description ILIKE '%' || ? || '%'", escapeLikePattern(filter.Text()))
+	}
+	if filter.CreatedFrom() != nil {
+		conditions.add("t.created_at >= ?", *filter.CreatedFrom())
+	}
+	if filter.CreatedTo() != nil {
+		conditions.add("t.created_at <= ?", *filter.CreatedTo())
+	}
+	if filter.Expression() != nil {
+		conditions.add(compileTaskExpression(filter.Expression(), conditions))
+	}
+
+	return conditions
 }
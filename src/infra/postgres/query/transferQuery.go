@@ -0,0 +1,68 @@
+package query
+
+type transferSqlManager struct{}
+
+func Transfer() *transferSqlManager {
+	return &transferSqlManager{}
+}
+
+// InsertCollection writes the imported collection along with its event in the outbox, returning its ID
+func (transferSqlManager) InsertCollection() string {
+	return `WITH created AS (
+				INSERT INTO collection (id, name, created_at, user_id)
+				VALUES (DEFAULT, $1, COALESCE($2::timestamptz, CURRENT_TIMESTAMP), $3) RETURNING *
+			)
+			` + insertOutboxEvent + `
+			SELECT 'collection', id, 'collection.created', jsonb_build_object('collection', to_jsonb(created)),
+				user_id
+			FROM created
+			RETURNING aggregate_id;`
+}
+
+// InsertTask writes the imported task along with its event in the outbox, returning its ID
+func (transferSqlManager) InsertTask() string {
+	return `WITH created AS (
+				INSERT INTO task (id, description, finished, created_at, due_date, tags, collection_id, user_id)
+				VALUES (DEFAULT, $1, $2, COALESCE($3::timestamptz, CURRENT_TIMESTAMP), $4, $5, $6, $7) RETURNING *
+			)
+			` + insertOutboxEvent + `
+			SELECT 'task', id, 'task.created', jsonb_build_object('task', to_jsonb(created)), user_id FROM created
+			RETURNING aggregate_id;`
+}
+
+type transferSelectSqlManager struct{}
+
+func (transferSqlManager) Select() *transferSelectSqlManager {
+	return &transferSelectSqlManager{}
+}
+
+func (transferSelectSqlManager) Collections() string {
+	return `SELECT id			AS collection_id,
+				   name			AS collection_name,
+				   created_at	AS collection_created_at,
+				   version		AS collection_version
+			FROM collection
+			WHERE user_id = $1
+			ORDER BY id;`
+}
+
+func (transferSelectSqlManager) CollectionIdByName() string {
+	return "SELECT id FROM collection WHERE user_id = $1 AND LOWER(name) = LOWER($2) ORDER BY id LIMIT 1;"
+}
+
+// Tasks also reads the tasks without collection, whose collection ID is zero
+func (transferSelectSqlManager) Tasks() string {
+	return `SELECT t.id						AS task_id,
+				   t.description			AS task_description,
+				   t.finished				AS task_finished,
+				   t.created_at				AS task_created_at,
+				   t.version				AS task_version,
+				   t.due_date				AS task_due_date,
+				   t.tags					AS task_tags,
+				   COALESCE(c.id, 0)		AS collection_id,
+				   COALESCE(c.name, '')		AS collection_name
+			FROM task t
+			LEFT JOIN collection c ON t.collection_id = c.id
+			WHERE t.user_id = $1
+			ORDER BY t.id;`
+}
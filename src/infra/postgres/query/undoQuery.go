@@ -0,0 +1,89 @@
+package query
+
+type undoSqlManager struct{}
+
+func Undo() *undoSqlManager {
+	return &undoSqlManager{}
+}
+
+func (undoSqlManager) Insert() string {
+	return "INSERT INTO undo_operation (user_id, token, kind, changes, expires_at) VALUES ($1, $2, $3, $4, $5);"
+}
+
+func (undoSqlManager) Delete() string {
+	return "DELETE FROM undo_operation WHERE user_id = $1 AND token = $2;"
+}
+
+func (undoSqlManager) DeleteExpired() string {
+	return "DELETE FROM undo_operation WHERE user_id = $1 AND expires_at <= $2;"
+}
+
+// RestoreTask inserts a deleted task back with its previous ID, inserting nothing when the ID is in use, and writes
+// its creation in the outbox
+func (undoSqlManager) RestoreTask() string {
+	return `WITH restored AS (
+				INSERT INTO task (id, description, finished, created_at, version, due_date, tags, collection_id,
+					user_id)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+				ON CONFLICT (id) DO NOTHING
+				RETURNING *
+			)
+			` + insertOutboxEvent + `
+			SELECT 'task', id, 'task.created', jsonb_build_object('task', to_jsonb(restored)), user_id FROM restored;`
+}
+
+// RevertTask gives the task its previous data back, as long as it still has the version left by the operation, and
+// writes the event of the change in the outbox
+func (undoSqlManager) RevertTask() string {
+	return `WITH reverted AS (
+				UPDATE task t SET description = $1, finished = $2, due_date = $3, tags = $4, collection_id = $5,
+					version = t.version + 1
+				FROM task previous
+				WHERE previous.id = t.id AND t.id = $6 AND t.user_id = $7 AND t.version = $8
+				RETURNING t.*, previous.finished AS previously_finished
+			)
+			` + insertOutboxEvent + `
+			SELECT 'task', id, 'task.updated', jsonb_build_object('task', to_jsonb(reverted) - 'previously_finished',
+				'finishes_task', finished AND NOT previously_finished), user_id
+			FROM reverted;`
+}
+
+// DeleteTask writes the event of the deletion in the outbox
+func (undoSqlManager) DeleteTask() string {
+	return `WITH deleted AS (
+				DELETE FROM task WHERE id = $1 AND user_id = $2 AND version = $3 RETURNING id, user_id
+			)
+			` + insertOutboxEvent + `
+			SELECT 'task', id, 'task.deleted', '{}'::jsonb, user_id FROM deleted;`
+}
+
+// RestoreCollection writes the creation of the collection in the outbox
+func (undoSqlManager) RestoreCollection() string {
+	return `WITH restored AS (
+				INSERT INTO collection (id, name, created_at, version, user_id)
+				VALUES ($1, $2, $3, $4, $5)
+				ON CONFLICT (id) DO NOTHING
+				RETURNING *
+			)
+			` + insertOutboxEvent + `
+			SELECT 'collection', id, 'collection.created', jsonb_build_object('collection', to_jsonb(restored)),
+				user_id
+			FROM restored;`
+}
+
+type undoSelectSqlManager struct{}
+
+func (undoSqlManager) Select() *undoSelectSqlManager {
+	return &undoSelectSqlManager{}
+}
+
+// ByToken locks the operation, so that it can only be undone once
+func (undoSelectSqlManager) ByToken() string {
+	return `SELECT token		AS undo_token,
+				   kind			AS undo_kind,
+				   changes		AS undo_changes,
+				   expires_at	AS undo_expires_at
+			FROM undo_operation
+			WHERE token = $1 AND user_id = $2 AND expires_at > $3
+			FOR UPDATE;`
+}
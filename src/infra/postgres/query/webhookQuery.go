@@ -0,0 +1,139 @@
+package query
+
+import (
+	"fmt"
+	"todo/src/core/domain"
+)
+
+var webhookDeliverySortColumns = map[string]string{
+	"id":         "d.id",
+	"created_at": "d.created_at",
+}
+
+type webhookSqlManager struct{}
+
+func Webhook() *webhookSqlManager {
+	return &webhookSqlManager{}
+}
+
+func (webhookSqlManager) Insert() string {
+	return `INSERT INTO webhook (url, secret, event_types, active, user_id) VALUES ($1, $2, $3, $4, $5)
+			RETURNING id;`
+}
+
+// Update clears the failures when the webhook is enabled again
+func (webhookSqlManager) Update() string {
+	return `UPDATE webhook SET url = $1, event_types = $2,
+				failures = CASE WHEN $3 AND NOT active THEN 0 ELSE failures END, active = $3
+			WHERE id = $4 AND user_id = $5;`
+}
+
+func (webhookSqlManager) Delete() string {
+	return "DELETE FROM webhook WHERE id = $1 AND user_id = $2;"
+}
+
+func (webhookSqlManager) InsertDelivery() string {
+	return `INSERT INTO webhook_delivery (webhook_id, event_id, event_type, attempt, request_body, response_status,
+				error, duration_ms, created_at)
+			VALUES ($1, $2, $3, $4, $5, NULLIF($6, 0), $7, $8, $9);`
+}
+
+// RecordOutcome counts the deliveries that have failed in a row, disabling the webhook once they reach the limit,
+// and returns whether the webhook is disabled
+func (webhookSqlManager) RecordOutcome() string {
+	return `UPDATE webhook SET failures = CASE WHEN $2 THEN 0 ELSE failures + 1 END,
+				active = active AND ($2 OR failures + 1 < $3)
+			WHERE id = $1
+			RETURNING NOT active;`
+}
+
+type webhookSelectSqlManager struct{}
+
+func (webhookSqlManager) Select() *webhookSelectSqlManager {
+	return &webhookSelectSqlManager{}
+}
+
+func (webhookSelectSqlManager) ById() string {
+	return `SELECT id   		AS webhook_id,
+				   url 			AS webhook_url,
+				   event_types	AS webhook_event_types,
+				   active		AS webhook_active,
+				   failures		AS webhook_failures,
+				   created_at	AS webhook_created_at
+			FROM webhook
+			WHERE id = $1 AND user_id = $2;`
+}
+
+func (webhookSelectSqlManager) All() string {
+	return `SELECT id   		AS webhook_id,
+				   url 			AS webhook_url,
+				   event_types	AS webhook_event_types,
+				   active		AS webhook_active,
+				   failures		AS webhook_failures,
+				   created_at	AS webhook_created_at
+			FROM webhook
+			WHERE user_id = $1
+			ORDER BY id;`
+}
+
+// Subscribed reads the active webhooks of the account with the event type, along with their secret
+func (webhookSelectSqlManager) Subscribed() string {
+	return `SELECT id   		AS webhook_id,
+				   url 			AS webhook_url,
+				   secret		AS webhook_secret,
+				   event_types	AS webhook_event_types,
+				   active		AS webhook_active,
+				   failures		AS webhook_failures,
+				   created_at	AS webhook_created_at
+			FROM webhook
+			WHERE user_id = $1 AND active AND $2 = ANY(event_types)
+			ORDER BY id;`
+}
+
+func (webhookSelectSqlManager) DeliveryCount() string {
+	return `SELECT COUNT(*)
+			FROM webhook_delivery d
+			JOIN webhook w ON d.webhook_id = w.id
+			WHERE d.webhook_id = $1 AND w.user_id = $2;`
+}
+
+func (webhookSelectSqlManager) Deliveries(pagination domain.Pagination) string {
+	sortColumn, ok := webhookDeliverySortColumns[pagination.SortField()]
+	if !ok {
+		sortColumn = webhookDeliverySortColumns["id"]
+	}
+	direction := sortDirection(pagination.IsDescending())
+
+	return fmt.Sprintf(`SELECT d.id						AS delivery_id,
+				   d.webhook_id					AS delivery_webhook_id,
+				   d.event_id					AS delivery_event_id,
+				   d.event_type					AS delivery_event_type,
+				   d.attempt					AS delivery_attempt,
+				   d.request_body				AS delivery_request_body,
+				   COALESCE(d.response_status, 0)	AS delivery_response_status,
+				   d.error						AS delivery_error,
+				   d.duration_ms				AS delivery_duration_ms,
+				   d.created_at					AS delivery_created_at
+			FROM webhook_delivery d
+			JOIN webhook w ON d.webhook_id = w.id
+			WHERE d.webhook_id = $1 AND w.user_id = $2
+			ORDER BY %s %s, d.id %s
+			LIMIT $3 OFFSET $4;`, sortColumn, direction, direction)
+}
+
+// EventDeliveries reads the attempts made to post the event to the webhooks, whatever their account
+func (webhookSelectSqlManager) EventDeliveries() string {
+	return `SELECT id						AS delivery_id,
+				   webhook_id				AS delivery_webhook_id,
+				   event_id					AS delivery_event_id,
+				   event_type				AS delivery_event_type,
+				   attempt					AS delivery_attempt,
+				   request_body				AS delivery_request_body,
+				   COALESCE(response_status, 0)	AS delivery_response_status,
+				   error					AS delivery_error,
+				   duration_ms				AS delivery_duration_ms,
+				   created_at				AS delivery_created_at
+			FROM webhook_delivery
+			WHERE event_id = $1
+			ORDER BY id;`
+}
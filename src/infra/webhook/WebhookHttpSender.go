@@ -0,0 +1,108 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/labstack/gommon/log"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+	"todo/src/core/domain"
+)
+
+const (
+	HeaderEvent       = "X-Todo-Event"
+	HeaderEventId     = "X-Todo-Event-Id"
+	HeaderTimestamp   = "X-Todo-Timestamp"
+	HeaderSignature   = "X-Todo-Signature"
+	signaturePrefix   = "sha256="
+	userAgent         = "todo-webhooks/1.0"
+	requestTimeout    = 10 * time.Second
+	maxResponseToRead = 64 * 1024
+	blockedAddress    = "the address %s is not public"
+)
+
+// Sender posts the payloads over HTTP, without following redirects so that a webhook can't send them elsewhere. It
+// only connects to public addresses, checked once the host is resolved, so that a name validated as public can't be
+// rebound to a private address.
+type Sender struct {
+	client *http.Client
+}
+
+func NewWebhookHttpSender() *Sender {
+	return newSender(domain.PublicWebhookIp)
+}
+
+// newSender only connects to the addresses allowed
+func newSender(allowed func(ip net.IP) bool) *Sender {
+	dialer := &net.Dialer{
+		Timeout: requestTimeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !allowed(ip) {
+				return fmt.Errorf(blockedAddress, host)
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would be connected to instead of the webhook, whose address would then go unchecked
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &Sender{&http.Client{
+		Timeout:   requestTimeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}}
+}
+
+func (s Sender) Send(webhook domain.Webhook, delivery domain.WebhookDelivery) *domain.WebhookDelivery {
+	request, err := http.NewRequest(http.MethodPost, webhook.Url(), strings.NewReader(delivery.RequestBody()))
+	if err != nil {
+		log.Error(err)
+		delivery.SetResult(0, err.Error(), 0)
+		return &delivery
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", userAgent)
+	request.Header.Set(HeaderEvent, delivery.EventType())
+	request.Header.Set(HeaderEventId, strconv.FormatInt(delivery.EventId(), 10))
+	request.Header.Set(HeaderTimestamp, timestamp)
+	request.Header.Set(HeaderSignature, Signature(webhook.Secret(), timestamp, []byte(delivery.RequestBody())))
+
+	start := time.Now()
+	response, err := s.client.Do(request)
+	if err != nil {
+		log.Error(err)
+		delivery.SetResult(0, err.Error(), time.Since(start))
+		return &delivery
+	}
+	defer response.Body.Close()
+	// The body is read so that the connection can be reused, but is not kept
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, maxResponseToRead))
+
+	delivery.SetResult(response.StatusCode, "", time.Since(start))
+	return &delivery
+}
+
+// Signature is the HMAC-SHA256 of the timestamp and the body joined by a dot, keyed with the secret of the webhook,
+// which lets the receiver check that the payload is authentic and recent
+func Signature(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = fmt.Fprintf(mac, "%s.", timestamp)
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
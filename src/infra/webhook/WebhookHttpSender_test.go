@@ -0,0 +1,97 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"todo/src/core/domain"
+)
+
+// allowAll lets the tests post to their local servers
+func allowAll(net.IP) bool {
+	return true
+}
+
+func TestSender_Send(t *testing.T) {
+	t.Run("should post the body signed with the secret of the webhook", func(t *testing.T) {
+		var receivedRequest *http.Request
+		var receivedBody []byte
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			receivedRequest = request
+			receivedBody, _ = io.ReadAll(request.Body)
+			writer.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+		webhook := domain.NewWebhook(1, server.URL, []string{"task.created"}, true)
+		webhook.SetSecret("secret")
+		body := `{"id":7,"type":"task.created"}`
+
+		delivery := newSender(allowAll).Send(*webhook, *domain.NewWebhookDelivery(1, 7, "task.created", 1, body))
+
+		assert.True(t, delivery.Succeeded())
+		assert.Equal(t, http.StatusNoContent, delivery.ResponseStatus())
+		assert.Equal(t, body, string(receivedBody))
+		assert.Equal(t, "task.created", receivedRequest.Header.Get(HeaderEvent))
+		assert.Equal(t, "7", receivedRequest.Header.Get(HeaderEventId))
+		expectedSignature := Signature("secret", receivedRequest.Header.Get(HeaderTimestamp), receivedBody)
+		assert.True(t, hmac.Equal([]byte(expectedSignature), []byte(receivedRequest.Header.Get(HeaderSignature))))
+		assert.NotEqual(t, Signature("other", receivedRequest.Header.Get(HeaderTimestamp), receivedBody),
+			receivedRequest.Header.Get(HeaderSignature))
+	})
+
+	t.Run("should fail when the webhook does not answer with a 2xx status", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			http.Redirect(writer, request, "http://example.com", http.StatusFound)
+		}))
+		defer server.Close()
+		webhook := domain.NewWebhook(1, server.URL, []string{"task.created"}, true)
+
+		delivery := newSender(allowAll).Send(*webhook, *domain.NewWebhookDelivery(1, 7, "task.created", 2, "{}"))
+
+		assert.False(t, delivery.Succeeded())
+		assert.Equal(t, http.StatusFound, delivery.ResponseStatus())
+		assert.Equal(t, 2, delivery.Attempt())
+	})
+
+	t.Run("should keep the error when no response is received", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+		webhook := domain.NewWebhook(1, server.URL, []string{"task.created"}, true)
+
+		delivery := newSender(allowAll).Send(*webhook, *domain.NewWebhookDelivery(1, 7, "task.created", 1, "{}"))
+
+		assert.False(t, delivery.Succeeded())
+		assert.Zero(t, delivery.ResponseStatus())
+		assert.NotEmpty(t, delivery.ErrorMessage())
+	})
+
+	t.Run("should not connect to the addresses that are not public", func(t *testing.T) {
+		called := false
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			called = true
+		}))
+		defer server.Close()
+		webhook := domain.NewWebhook(1, server.URL, []string{"task.created"}, true)
+
+		delivery := NewWebhookHttpSender().Send(*webhook, *domain.NewWebhookDelivery(1, 7, "task.created", 1, "{}"))
+
+		assert.False(t, delivery.Succeeded())
+		assert.False(t, called)
+		assert.Contains(t, delivery.ErrorMessage(), "the address 127.0.0.1 is not public")
+	})
+
+	t.Run("should not connect to the denied networks", func(t *testing.T) {
+		for _, address := range []string{"100.64.0.1", "198.18.0.1", "0.1.2.3", "64:ff9b::a9fe:a9fe"} {
+			webhook := domain.NewWebhook(1, "http://"+net.JoinHostPort(address, "80")+"/hook", nil, true)
+
+			delivery := NewWebhookHttpSender().Send(*webhook, *domain.NewWebhookDelivery(1, 7, "task.created", 1, "{}"))
+
+			assert.False(t, delivery.Succeeded(), address)
+			assert.Contains(t, delivery.ErrorMessage(), "the address "+address+" is not public", address)
+		}
+	})
+}